@@ -0,0 +1,41 @@
+package domain
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// AuditAction defines the kind of change recorded in the audit log
+type AuditAction string
+
+const (
+	AuditActionCreate AuditAction = "create"
+	AuditActionUpdate AuditAction = "update"
+	AuditActionDelete AuditAction = "delete"
+
+	// Actions recorded explicitly for operations that are not a single model change
+	AuditActionExport AuditAction = "export" // Personal data export of a user
+	AuditActionErase  AuditAction = "erase"  // Personal data erasure of a user
+	AuditActionPurge  AuditAction = "purge"  // Messages and files deleted by a retention policy
+)
+
+// AuditLog represents an append-only record of a change made to a model
+// It does not embed BaseModel because entries are never updated
+type AuditLog struct {
+	ID          string          `json:"id" gorm:"type:uuid;primaryKey;default:gen_random_uuid()"`
+	CreatedAt   time.Time       `json:"created_at" gorm:"autoCreateTime;index"`
+	ActorID     string          `json:"actor_id" gorm:"index"` // Empty for system changes outside a request
+	Action      AuditAction     `json:"action" gorm:"index"`
+	EntityType  string          `json:"entity_type" gorm:"index"` // Table name of the changed model
+	EntityID    string          `json:"entity_id" gorm:"index"`
+	Before      json.RawMessage `json:"before,omitempty" gorm:"type:jsonb"`
+	After       json.RawMessage `json:"after,omitempty" gorm:"type:jsonb"`
+	IP          string          `json:"ip"`
+	RequestID   string          `json:"request_id" gorm:"index"`
+	WorkspaceID string          `json:"workspace_id,omitempty" gorm:"index"` // Workspace of the request, empty for platform-level changes
+}
+
+// TableName specifies the table name for the AuditLog model
+func (AuditLog) TableName() string {
+	return "audit_logs"
+}
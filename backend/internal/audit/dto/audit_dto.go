@@ -0,0 +1,52 @@
+package dto
+
+import (
+	"encoding/json"
+
+	"thothix-backend/internal/shared/dto"
+)
+
+// === AUDIT DTOs ===
+
+// AuditLogDto represents an audit log entry in API responses
+type AuditLogDto struct {
+	ID          string          `json:"id"`
+	ActorID     string          `json:"actor_id"`
+	Action      string          `json:"action"`
+	EntityType  string          `json:"entity_type"`
+	EntityID    string          `json:"entity_id"`
+	Before      json.RawMessage `json:"before,omitempty"`
+	After       json.RawMessage `json:"after,omitempty"`
+	IP          string          `json:"ip"`
+	RequestID   string          `json:"request_id"`
+	WorkspaceID string          `json:"workspace_id,omitempty"`
+	CreatedAt   string          `json:"created_at"`
+}
+
+// AuditLogListDto represents paginated audit log data
+type AuditLogListDto = dto.PaginatedListResponse[AuditLogDto]
+
+// NewAuditLogListDto creates an AuditLogListDto with proper pagination metadata
+func NewAuditLogListDto(entries []AuditLogDto, total int64, page, perPage int) *AuditLogListDto {
+	return dto.NewPaginatedListResponse(entries, total, page, perPage)
+}
+
+// AuditLogFilterRequest represents the query filters for listing audit log entries
+// From and To are RFC3339 timestamps bounding created_at
+type AuditLogFilterRequest struct {
+	dto.PaginationRequest
+	ActorID    string `form:"actor_id"`
+	Action     string `form:"action"`
+	EntityType string `form:"entity_type"`
+	EntityID   string `form:"entity_id"`
+	RequestID  string `form:"request_id"`
+	From       string `form:"from"`
+	To         string `form:"to"`
+}
+
+// GetAuditLogsResponse wraps a paginated list of AuditLogDto
+type GetAuditLogsResponse = dto.ListResponse[AuditLogDto]
+
+func NewGetAuditLogsResponse(producer func() dto.Validation[*AuditLogListDto]) *GetAuditLogsResponse {
+	return dto.NewListResponse(producer)
+}
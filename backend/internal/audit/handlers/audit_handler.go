@@ -0,0 +1,80 @@
+package handlers
+
+import (
+	"github.com/gin-gonic/gin"
+
+	auditDto "thothix-backend/internal/audit/dto"
+	"thothix-backend/internal/audit/service"
+	"thothix-backend/internal/shared/constants"
+	"thothix-backend/internal/shared/dto"
+	"thothix-backend/internal/shared/handlers"
+)
+
+type AuditHandler struct {
+	auditService service.AuditServiceInterface
+}
+
+func NewAuditHandler(auditService service.AuditServiceInterface) *AuditHandler {
+	return &AuditHandler{
+		auditService: auditService,
+	}
+}
+
+// GetAuditLogs godoc
+// @Summary Query the audit log
+// @Description Get a filtered, paginated list of audit log entries (platform admin only)
+// @Tags audit
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param page query int false "Page number (default: 1)"
+// @Param per_page query int false "Items per page (default: 20, max: 100)"
+// @Param actor_id query string false "Filter by actor user ID"
+// @Param action query string false "Filter by action (create, update, delete, export, erase, purge)"
+// @Param entity_type query string false "Filter by entity type (table name)"
+// @Param entity_id query string false "Filter by entity ID"
+// @Param request_id query string false "Filter by request ID"
+// @Param from query string false "Only entries created at or after this RFC3339 timestamp"
+// @Param to query string false "Only entries created at or before this RFC3339 timestamp"
+// @Success 200 {object} auditDto.AuditLogListDto
+// @Failure 400 {object} dto.ErrorViewModel
+// @Failure 403 {object} dto.ErrorViewModel
+// @Failure 500 {object} dto.ErrorViewModel
+// @Router /audit-logs [get]
+func (h *AuditHandler) GetAuditLogs(c *gin.Context) {
+	wrapper := handlers.WrapContext(c)
+
+	var request auditDto.AuditLogFilterRequest
+	if err := c.ShouldBindQuery(&request); err != nil {
+		wrapper.BadRequestErrorResponse("Invalid query parameters")
+		return
+	}
+
+	// Set defaults
+	if request.Page == 0 {
+		request.Page = constants.DefaultPage
+	}
+	if request.PerPage == 0 {
+		request.PerPage = 20
+	}
+
+	response := h.auditService.GetAuditLogs(&request)
+
+	response.Match(
+		// Exception case
+		func(err error) interface{} {
+			wrapper.SystemErrorResponse(err, "Failed to retrieve audit logs")
+			return nil
+		},
+		// Success case
+		func(result *auditDto.AuditLogListDto) interface{} {
+			wrapper.SuccessResponse(result)
+			return nil
+		},
+		// Validation failure case
+		func(errors []dto.Error) interface{} {
+			wrapper.ValidationErrorResponse(errors, "Audit log query validation failed")
+			return nil
+		},
+	)
+}
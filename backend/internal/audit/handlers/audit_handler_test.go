@@ -0,0 +1,84 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	auditDto "thothix-backend/internal/audit/dto"
+	"thothix-backend/internal/shared/dto"
+)
+
+// MockAuditService is a mock implementation of the AuditService
+type MockAuditService struct {
+	mock.Mock
+}
+
+func (m *MockAuditService) GetAuditLogs(req *auditDto.AuditLogFilterRequest) *auditDto.GetAuditLogsResponse {
+	args := m.Called(req)
+	return args.Get(0).(*auditDto.GetAuditLogsResponse)
+}
+
+type AuditHandlerTestSuite struct {
+	suite.Suite
+	mockService *MockAuditService
+	router      *gin.Engine
+}
+
+func (suite *AuditHandlerTestSuite) SetupSuite() {
+	gin.SetMode(gin.TestMode)
+}
+
+func (suite *AuditHandlerTestSuite) SetupTest() {
+	suite.mockService = new(MockAuditService)
+	handler := NewAuditHandler(suite.mockService)
+
+	suite.router = gin.New()
+	suite.router.GET("/audit-logs", handler.GetAuditLogs)
+}
+
+func (suite *AuditHandlerTestSuite) TestGetAuditLogs_BindsFiltersAndDefaults() {
+	// Arrange
+	mockResponse := auditDto.NewGetAuditLogsResponse(func() dto.Validation[*auditDto.AuditLogListDto] {
+		return dto.Success(auditDto.NewAuditLogListDto([]auditDto.AuditLogDto{{ID: "entry-1"}}, 1, 1, 20))
+	})
+
+	suite.mockService.On("GetAuditLogs", mock.MatchedBy(func(req *auditDto.AuditLogFilterRequest) bool {
+		return req.Page == 1 && req.PerPage == 20 &&
+			req.ActorID == "user-1" && req.Action == "update" && req.EntityType == "channels"
+	})).Return(mockResponse)
+
+	// Act
+	req, _ := http.NewRequest("GET", "/audit-logs?actor_id=user-1&action=update&entity_type=channels", nil)
+	w := httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(suite.T(), http.StatusOK, w.Code)
+	suite.mockService.AssertExpectations(suite.T())
+}
+
+func (suite *AuditHandlerTestSuite) TestGetAuditLogs_ValidationError() {
+	// Arrange
+	mockResponse := auditDto.NewGetAuditLogsResponse(func() dto.Validation[*auditDto.AuditLogListDto] {
+		return dto.Invalid[*auditDto.AuditLogListDto](dto.NewError("VALIDATION_ERROR", "From must be an RFC3339 timestamp", nil))
+	})
+
+	suite.mockService.On("GetAuditLogs", mock.AnythingOfType("*dto.AuditLogFilterRequest")).Return(mockResponse)
+
+	// Act
+	req, _ := http.NewRequest("GET", "/audit-logs?from=yesterday", nil)
+	w := httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(suite.T(), http.StatusBadRequest, w.Code)
+}
+
+func TestAuditHandlerTestSuite(t *testing.T) {
+	suite.Run(t, new(AuditHandlerTestSuite))
+}
@@ -0,0 +1,18 @@
+package handlers
+
+import (
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"thothix-backend/internal/audit/service"
+	"thothix-backend/internal/middleware"
+)
+
+// RegisterAuditRoutes registers the audit log routes, restricted to platform administrators
+func RegisterAuditRoutes(router *gin.RouterGroup, db *gorm.DB) {
+	auditService := service.NewAuditService(db)
+	auditHandler := NewAuditHandler(auditService)
+
+	audit := router.Group("/audit-logs")
+	audit.Use(middleware.RequirePlatformAdmin(db))
+	audit.GET("", auditHandler.GetAuditLogs)
+}
@@ -0,0 +1,401 @@
+// Package hooks records the changes made through GORM models in the audit log. Only statements that go through
+// the create, update and delete callbacks are captured: SQL run with Exec or Raw, and models skipped with
+// WithoutAudit, are not audited, so such changes must write their own entry with Record.
+package hooks
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	auditDomain "thothix-backend/internal/audit/domain"
+	"thothix-backend/internal/encryption"
+	sharedMiddleware "thothix-backend/internal/shared/middleware"
+)
+
+const (
+	// beforeStateKey stores the rows loaded before an update or delete on the statement instance
+	beforeStateKey = "audit:before_state"
+	// auditPageSize bounds how many rows are loaded or written per query when auditing a bulk statement
+	auditPageSize = 500
+	// encryptedPlaceholder replaces fields encrypted at rest, whose plain text must not reach the audit log
+	encryptedPlaceholder = "[encrypted]"
+	// redactedPlaceholder replaces fields tagged `audit:"redact"`, personal data that must be erasable while
+	// the append-only audit log cannot be rewritten
+	redactedPlaceholder = "[redacted]"
+)
+
+// skipAuditKey marks a context whose statements are left out of the audit log, see WithoutAudit
+type skipAuditKey struct{}
+
+// auditRecord is the serialized state of one row touched by a statement
+type auditRecord struct {
+	ID   string
+	Data json.RawMessage
+}
+
+// Register installs the GORM callbacks that stamp created_by/updated_by and write the audit log
+// The actor, workspace, client IP and request ID are read from the statement context set by SetUserContext,
+// so callers must use db.WithContext(c.Request.Context()) for changes to be attributed
+func Register(db *gorm.DB) error {
+	callbacks := db.Callback()
+
+	if err := callbacks.Create().Before("gorm:create").Register("audit:stamp_create", stampCreate); err != nil {
+		return err
+	}
+	if err := callbacks.Create().After("gorm:create").Register("audit:after_create", afterCreate); err != nil {
+		return err
+	}
+	if err := callbacks.Update().Before("gorm:update").Register("audit:stamp_update", stampUpdate); err != nil {
+		return err
+	}
+	if err := callbacks.Update().Before("gorm:update").Register("audit:before_update", captureBeforeState); err != nil {
+		return err
+	}
+	if err := callbacks.Update().After("gorm:update").Register("audit:after_update", afterUpdate); err != nil {
+		return err
+	}
+	if err := callbacks.Delete().Before("gorm:delete").Register("audit:before_delete", captureBeforeState); err != nil {
+		return err
+	}
+	return callbacks.Delete().After("gorm:delete").Register("audit:after_delete", afterDelete)
+}
+
+// Record writes an explicit audit entry for an operation that is not captured by the callbacks,
+// such as a data export. The actor and request metadata are read from the db context like the callbacks do
+func Record(db *gorm.DB, action auditDomain.AuditAction, entityType, entityID string, details interface{}) error {
+	var after json.RawMessage
+	if details != nil {
+		data, err := json.Marshal(details)
+		if err != nil {
+			return fmt.Errorf("audit: failed to encode details: %w", err)
+		}
+		after = data
+	}
+
+	ctx := db.Statement.Context
+	actorID, _ := sharedMiddleware.UserIDFromContext(ctx)
+	entry := auditDomain.AuditLog{
+		ActorID:    actorID,
+		Action:     action,
+		EntityType: entityType,
+		EntityID:   entityID,
+		After:      after,
+		IP:         sharedMiddleware.ClientIPFromContext(ctx),
+		RequestID:  sharedMiddleware.RequestIDFromContext(ctx),
+	}
+	entry.WorkspaceID, _ = sharedMiddleware.WorkspaceIDFromContext(ctx)
+	return db.Create(&entry).Error
+}
+
+// WithoutAudit returns a session whose changes are not captured by the callbacks. It is meant for changes
+// that must not copy their before-state into the append-only audit log, such as the anonymization of a user;
+// callers record a redacted entry through Record instead
+func WithoutAudit(db *gorm.DB) *gorm.DB {
+	return db.WithContext(context.WithValue(db.Statement.Context, skipAuditKey{}, true))
+}
+
+// stampCreate sets created_by and updated_by on new rows
+func stampCreate(db *gorm.DB) {
+	if !isAudited(db) {
+		return
+	}
+	actorID, ok := sharedMiddleware.UserIDFromContext(db.Statement.Context)
+	if !ok {
+		return
+	}
+	if db.Statement.Schema.LookUpField("created_by") != nil {
+		db.Statement.SetColumn("created_by", actorID, true)
+	}
+	if db.Statement.Schema.LookUpField("updated_by") != nil {
+		db.Statement.SetColumn("updated_by", actorID, true)
+	}
+}
+
+// stampUpdate sets updated_by on modified rows
+func stampUpdate(db *gorm.DB) {
+	if !isAudited(db) {
+		return
+	}
+	actorID, ok := sharedMiddleware.UserIDFromContext(db.Statement.Context)
+	if !ok {
+		return
+	}
+	if db.Statement.Schema.LookUpField("updated_by") != nil {
+		db.Statement.SetColumn("updated_by", actorID, true)
+	}
+}
+
+// captureBeforeState loads the rows targeted by an update or delete before they change
+func captureBeforeState(db *gorm.DB) {
+	if !isAudited(db) {
+		return
+	}
+
+	records, err := loadAffectedRecords(db, nil)
+	if err != nil {
+		_ = db.AddError(fmt.Errorf("audit: failed to load state before change: %w", err))
+		return
+	}
+	db.InstanceSet(beforeStateKey, records)
+}
+
+// afterCreate records every created row
+func afterCreate(db *gorm.DB) {
+	if !isAudited(db) || db.Error != nil {
+		return
+	}
+
+	var entries []auditDomain.AuditLog
+	forEachElement(db.Statement.ReflectValue, func(elem reflect.Value) {
+		data, err := marshalRecord(db, elem)
+		if err != nil {
+			return
+		}
+		entries = append(entries, newEntry(db, auditDomain.AuditActionCreate, primaryKeyOf(db, elem), nil, data))
+	})
+	writeEntries(db, entries)
+}
+
+// afterUpdate records the before and after state of every updated row
+func afterUpdate(db *gorm.DB) {
+	if !isAudited(db) || db.Error != nil || db.RowsAffected == 0 {
+		return
+	}
+
+	before := beforeState(db)
+	if len(before) == 0 {
+		return
+	}
+
+	ids := make([]string, 0, len(before))
+	for _, record := range before {
+		ids = append(ids, record.ID)
+	}
+
+	// The IDs are looked up a page at a time to keep each query within the bind parameter limit
+	afterByID := make(map[string]json.RawMessage, len(before))
+	for start := 0; start < len(ids); start += auditPageSize {
+		after, err := loadAffectedRecords(db, ids[start:min(start+auditPageSize, len(ids))])
+		if err != nil {
+			_ = db.AddError(fmt.Errorf("audit: failed to load state after update: %w", err))
+			return
+		}
+		for _, record := range after {
+			afterByID[record.ID] = record.Data
+		}
+	}
+
+	entries := make([]auditDomain.AuditLog, 0, len(before))
+	for _, record := range before {
+		entries = append(entries, newEntry(db, auditDomain.AuditActionUpdate, record.ID, record.Data, afterByID[record.ID]))
+	}
+	writeEntries(db, entries)
+}
+
+// afterDelete records the last known state of every deleted row
+func afterDelete(db *gorm.DB) {
+	if !isAudited(db) || db.Error != nil || db.RowsAffected == 0 {
+		return
+	}
+
+	before := beforeState(db)
+	entries := make([]auditDomain.AuditLog, 0, len(before))
+	for _, record := range before {
+		entries = append(entries, newEntry(db, auditDomain.AuditActionDelete, record.ID, record.Data, nil))
+	}
+	writeEntries(db, entries)
+}
+
+// isAudited reports whether the statement targets a model that should be audited
+func isAudited(db *gorm.DB) bool {
+	stmt := db.Statement
+	if stmt.Schema == nil || stmt.DryRun {
+		return false
+	}
+	if skip, _ := stmt.Context.Value(skipAuditKey{}).(bool); skip {
+		return false
+	}
+	// Never audit the audit log itself
+	return stmt.Schema.Table != (auditDomain.AuditLog{}).TableName()
+}
+
+// loadAffectedRecords reads the rows matched by the statement conditions, or by the given IDs
+func loadAffectedRecords(db *gorm.DB, ids []string) ([]auditRecord, error) {
+	stmt := db.Statement
+	pk := stmt.Schema.PrioritizedPrimaryField
+	if pk == nil {
+		return nil, nil
+	}
+
+	query := db.Session(&gorm.Session{NewDB: true}).Table(stmt.Table)
+	switch {
+	case ids != nil:
+		query = query.Where(clause.IN{Column: clause.Column{Name: pk.DBName}, Values: toInterfaces(ids)})
+	default:
+		hasCondition := false
+		if stmt.ReflectValue.Kind() == reflect.Struct {
+			if id := primaryKeyOf(db, stmt.ReflectValue); id != "" {
+				query = query.Where(clause.Eq{Column: clause.Column{Name: pk.DBName}, Value: id})
+				hasCondition = true
+			}
+		}
+		if where, ok := stmt.Clauses["WHERE"].Expression.(clause.Where); ok && len(where.Exprs) > 0 {
+			query = query.Clauses(where)
+			hasCondition = true
+		}
+		// Statements without conditions are rejected by GORM as global updates
+		if !hasCondition {
+			return nil, nil
+		}
+	}
+
+	// Every row is audited, read a page at a time in primary key order
+	query = query.Session(&gorm.Session{})
+	var records []auditRecord
+	lastID := ""
+	for {
+		page := query.Order(clause.OrderByColumn{Column: clause.Column{Name: pk.DBName}}).Limit(auditPageSize)
+		if lastID != "" {
+			page = page.Where(clause.Gt{Column: clause.Column{Name: pk.DBName}, Value: lastID})
+		}
+		rows := reflect.New(reflect.SliceOf(stmt.Schema.ModelType))
+		if err := page.Find(rows.Interface()).Error; err != nil {
+			return nil, err
+		}
+
+		forEachElement(rows.Elem(), func(elem reflect.Value) {
+			lastID = primaryKeyOf(db, elem)
+			data, err := marshalRecord(db, elem)
+			if err != nil {
+				return
+			}
+			records = append(records, auditRecord{ID: lastID, Data: data})
+		})
+		if rows.Elem().Len() < auditPageSize {
+			return records, nil
+		}
+	}
+}
+
+// beforeState returns the records captured before the statement ran
+func beforeState(db *gorm.DB) []auditRecord {
+	value, ok := db.InstanceGet(beforeStateKey)
+	if !ok {
+		return nil
+	}
+	records, _ := value.([]auditRecord)
+	return records
+}
+
+// newEntry builds an audit log entry with the actor and request metadata from the statement context
+func newEntry(db *gorm.DB, action auditDomain.AuditAction, entityID string, before, after json.RawMessage) auditDomain.AuditLog {
+	ctx := db.Statement.Context
+	actorID, _ := sharedMiddleware.UserIDFromContext(ctx)
+	workspaceID, _ := sharedMiddleware.WorkspaceIDFromContext(ctx)
+	return auditDomain.AuditLog{
+		ActorID:     actorID,
+		Action:      action,
+		EntityType:  db.Statement.Schema.Table,
+		EntityID:    entityID,
+		Before:      before,
+		After:       after,
+		IP:          sharedMiddleware.ClientIPFromContext(ctx),
+		RequestID:   sharedMiddleware.RequestIDFromContext(ctx),
+		WorkspaceID: workspaceID,
+	}
+}
+
+// writeEntries stores audit entries on the same connection so they share the statement transaction
+func writeEntries(db *gorm.DB, entries []auditDomain.AuditLog) {
+	if len(entries) == 0 {
+		return
+	}
+	if err := db.Session(&gorm.Session{NewDB: true}).CreateInBatches(&entries, auditPageSize).Error; err != nil {
+		_ = db.AddError(fmt.Errorf("audit: failed to write audit log: %w", err))
+	}
+}
+
+// marshalRecord serializes a row for the audit log with its encrypted and redacted fields replaced by a placeholder
+func marshalRecord(db *gorm.DB, elem reflect.Value) (json.RawMessage, error) {
+	data, err := json.Marshal(elem.Interface())
+	if err != nil {
+		return nil, err
+	}
+
+	placeholders := make(map[string]string)
+	for _, field := range db.Statement.Schema.Fields {
+		placeholder := ""
+		switch {
+		case field.Tag.Get("audit") == "redact":
+			placeholder = redactedPlaceholder
+		case field.TagSettings["SERIALIZER"] == encryption.SerializerName:
+			placeholder = encryptedPlaceholder
+		default:
+			continue
+		}
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "" {
+			name = field.Name
+		}
+		if name != "-" {
+			placeholders[name] = placeholder
+		}
+	}
+	if len(placeholders) == 0 {
+		return data, nil
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+	for name, placeholder := range placeholders {
+		if value, ok := fields[name]; ok && string(value) != "null" {
+			fields[name] = json.RawMessage(`"` + placeholder + `"`)
+		}
+	}
+	return json.Marshal(fields)
+}
+
+// primaryKeyOf returns the primary key of a model value as a string, or "" when unset
+func primaryKeyOf(db *gorm.DB, value reflect.Value) string {
+	pk := db.Statement.Schema.PrioritizedPrimaryField
+	if pk == nil {
+		return ""
+	}
+	id, isZero := pk.ValueOf(db.Statement.Context, reflect.Indirect(value))
+	if isZero {
+		return ""
+	}
+	return fmt.Sprint(id)
+}
+
+// forEachElement calls fn for a struct value or for every struct in a slice
+func forEachElement(value reflect.Value, fn func(reflect.Value)) {
+	value = reflect.Indirect(value)
+	switch value.Kind() {
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < value.Len(); i++ {
+			if elem := reflect.Indirect(value.Index(i)); elem.Kind() == reflect.Struct {
+				fn(elem)
+			}
+		}
+	case reflect.Struct:
+		fn(value)
+	}
+}
+
+// toInterfaces converts a string slice for use in clause.IN
+func toInterfaces(values []string) []interface{} {
+	result := make([]interface{}, len(values))
+	for i, v := range values {
+		result[i] = v
+	}
+	return result
+}
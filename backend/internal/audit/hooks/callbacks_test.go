@@ -0,0 +1,113 @@
+package hooks
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/gorm"
+	auditDomain "thothix-backend/internal/audit/domain"
+	sharedMiddleware "thothix-backend/internal/shared/middleware"
+	sharedTesting "thothix-backend/internal/shared/testing"
+	usersDomain "thothix-backend/internal/users/domain"
+)
+
+type AuditCallbacksTestSuite struct {
+	suite.Suite
+	container *sharedTesting.PostgresTestContainer
+}
+
+func (suite *AuditCallbacksTestSuite) SetupSuite() {
+	suite.container = sharedTesting.GetSharedTestContainer(
+		suite.T(),
+		"audit/hooks",
+		[]interface{}{&usersDomain.User{}, &auditDomain.AuditLog{}},
+	)
+	assert.NoError(suite.T(), Register(suite.container.DB))
+}
+
+func (suite *AuditCallbacksTestSuite) TestCreateUpdateDelete_WritesAuditTrail() {
+	suite.container.WithTransaction(func(tx *gorm.DB) {
+		// Arrange
+		ctx := sharedMiddleware.WithWorkspaceID(sharedMiddleware.WithUserID(context.Background(), "actor-1"), "workspace-1")
+		db := tx.WithContext(ctx)
+		user := &usersDomain.User{Email: "audit@example.com", Name: "Audit User"}
+		user.ID = uuid.New().String()
+
+		// Act
+		assert.NoError(suite.T(), db.Create(user).Error)
+		assert.NoError(suite.T(), db.Model(user).Update("system_role", "manager").Error)
+		assert.NoError(suite.T(), db.Delete(&usersDomain.User{}, "id = ?", user.ID).Error)
+
+		// Assert
+		assert.Equal(suite.T(), "actor-1", user.CreatedBy)
+
+		var entries []auditDomain.AuditLog
+		assert.NoError(suite.T(), tx.Where("entity_id = ?", user.ID).Order("created_at, action").Find(&entries).Error)
+		assert.Len(suite.T(), entries, 3)
+
+		actions := map[auditDomain.AuditAction]auditDomain.AuditLog{}
+		for _, entry := range entries {
+			assert.Equal(suite.T(), "actor-1", entry.ActorID)
+			assert.Equal(suite.T(), "users", entry.EntityType)
+			assert.Equal(suite.T(), "workspace-1", entry.WorkspaceID)
+			// Personal data never reaches the append-only log
+			assert.NotContains(suite.T(), string(entry.Before)+string(entry.After), "Audit User")
+			assert.NotContains(suite.T(), string(entry.Before)+string(entry.After), "audit@example.com")
+			actions[entry.Action] = entry
+		}
+		assert.Contains(suite.T(), string(actions[auditDomain.AuditActionCreate].After), `"name":"[redacted]"`)
+		assert.Contains(suite.T(), string(actions[auditDomain.AuditActionUpdate].Before), `"system_role":"user"`)
+		assert.Contains(suite.T(), string(actions[auditDomain.AuditActionUpdate].After), `"system_role":"manager"`)
+		assert.Nil(suite.T(), actions[auditDomain.AuditActionDelete].After)
+	})
+}
+
+func (suite *AuditCallbacksTestSuite) TestBulkUpdate_AuditsEveryRow() {
+	suite.container.WithTransaction(func(tx *gorm.DB) {
+		// Arrange
+		db := tx.WithContext(sharedMiddleware.WithUserID(context.Background(), "actor-1"))
+		users := make([]usersDomain.User, auditPageSize+2)
+		for i := range users {
+			users[i] = usersDomain.User{Email: fmt.Sprintf("bulk-%d@example.com", i), Name: "Bulk User"}
+			users[i].ID = uuid.New().String()
+		}
+		assert.NoError(suite.T(), db.CreateInBatches(&users, 100).Error)
+
+		// Act
+		assert.NoError(suite.T(), db.Model(&usersDomain.User{}).Where("email LIKE ?", "bulk-%").Update("system_role", "manager").Error)
+
+		// Assert
+		var updates []auditDomain.AuditLog
+		assert.NoError(suite.T(), tx.Where("action = ?", auditDomain.AuditActionUpdate).Find(&updates).Error)
+		assert.Len(suite.T(), updates, len(users))
+		for _, entry := range updates {
+			assert.Contains(suite.T(), string(entry.After), `"system_role":"manager"`)
+		}
+	})
+}
+
+func (suite *AuditCallbacksTestSuite) TestWithoutAudit_SkipsAuditTrail() {
+	suite.container.WithTransaction(func(tx *gorm.DB) {
+		// Arrange
+		db := tx.WithContext(sharedMiddleware.WithUserID(context.Background(), "actor-1"))
+		user := &usersDomain.User{Email: "unaudited@example.com", Name: "Unaudited User"}
+		user.ID = uuid.New().String()
+		assert.NoError(suite.T(), db.Create(user).Error)
+
+		// Act
+		assert.NoError(suite.T(), WithoutAudit(db).Model(user).Update("name", "Renamed").Error)
+
+		// Assert
+		var updates int64
+		tx.Model(&auditDomain.AuditLog{}).Where("entity_id = ? AND action = ?", user.ID, auditDomain.AuditActionUpdate).Count(&updates)
+		assert.Equal(suite.T(), int64(0), updates)
+	})
+}
+
+func TestAuditCallbacksTestSuite(t *testing.T) {
+	suite.Run(t, new(AuditCallbacksTestSuite))
+}
@@ -0,0 +1,52 @@
+package mappers
+
+import (
+	"time"
+
+	"thothix-backend/internal/audit/domain"
+	auditDto "thothix-backend/internal/audit/dto"
+)
+
+// AuditMapper handles conversion between AuditLog models and DTOs
+type AuditMapper struct{}
+
+// NewAuditMapper creates a new AuditMapper instance
+func NewAuditMapper() *AuditMapper {
+	return &AuditMapper{}
+}
+
+// ModelToDto converts an AuditLog model to AuditLogDto
+func (m *AuditMapper) ModelToDto(entry *domain.AuditLog) *auditDto.AuditLogDto {
+	if entry == nil {
+		return nil
+	}
+
+	return &auditDto.AuditLogDto{
+		ID:          entry.ID,
+		ActorID:     entry.ActorID,
+		Action:      string(entry.Action),
+		EntityType:  entry.EntityType,
+		EntityID:    entry.EntityID,
+		Before:      entry.Before,
+		After:       entry.After,
+		IP:          entry.IP,
+		RequestID:   entry.RequestID,
+		WorkspaceID: entry.WorkspaceID,
+		CreatedAt:   entry.CreatedAt.Format(time.RFC3339),
+	}
+}
+
+// ModelsToDtos converts a slice of AuditLog models to AuditLogDto DTOs
+func (m *AuditMapper) ModelsToDtos(entries []domain.AuditLog) []auditDto.AuditLogDto {
+	if entries == nil {
+		return nil
+	}
+
+	dtos := make([]auditDto.AuditLogDto, len(entries))
+	for i := range entries {
+		if dto := m.ModelToDto(&entries[i]); dto != nil {
+			dtos[i] = *dto
+		}
+	}
+	return dtos
+}
@@ -0,0 +1,125 @@
+package service
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+
+	"thothix-backend/internal/audit/domain"
+	auditDto "thothix-backend/internal/audit/dto"
+	"thothix-backend/internal/audit/mappers"
+	"thothix-backend/internal/shared/constants"
+	"thothix-backend/internal/shared/dto"
+	sharedModels "thothix-backend/internal/shared/models"
+)
+
+type AuditService struct {
+	db     *gorm.DB
+	mapper *mappers.AuditMapper
+}
+
+func NewAuditService(db *gorm.DB) *AuditService {
+	return &AuditService{
+		db:     db,
+		mapper: mappers.NewAuditMapper(),
+	}
+}
+
+// GetAuditLogs retrieves a filtered, paginated list of audit log entries, newest first
+func (s *AuditService) GetAuditLogs(req *auditDto.AuditLogFilterRequest) *auditDto.GetAuditLogsResponse {
+	return auditDto.NewGetAuditLogsResponse(func() dto.Validation[*auditDto.AuditLogListDto] {
+		var validationErrors []dto.Error
+
+		// Validation
+		if req == nil {
+			return dto.Failure[*auditDto.AuditLogListDto](dto.NewError(constants.ValidationError, "Filter request cannot be nil", nil))
+		}
+
+		if req.Page < 1 {
+			validationErrors = append(validationErrors, dto.NewError(constants.ValidationError, "Page must be greater than 0", nil))
+		}
+
+		if req.PerPage < 1 || req.PerPage > constants.MaxPerPage {
+			validationErrors = append(validationErrors, dto.NewError(constants.ValidationError, "Per page must be between 1 and 100", nil))
+		}
+
+		if req.Action != "" && !isValidAction(req.Action) {
+			validationErrors = append(validationErrors, dto.NewError(constants.ValidationError, "Action must be one of create, update, delete, export, erase, purge", map[string]string{"action": req.Action}))
+		}
+
+		from, fromErr := parseTime(req.From)
+		if fromErr != nil {
+			validationErrors = append(validationErrors, dto.NewError(constants.ValidationError, "From must be an RFC3339 timestamp", map[string]string{"from": req.From}))
+		}
+
+		to, toErr := parseTime(req.To)
+		if toErr != nil {
+			validationErrors = append(validationErrors, dto.NewError(constants.ValidationError, "To must be an RFC3339 timestamp", map[string]string{"to": req.To}))
+		}
+
+		if len(validationErrors) > 0 {
+			return dto.Failure[*auditDto.AuditLogListDto](validationErrors...)
+		}
+
+		// Business logic
+		// Within a workspace only its own entries are listed; platform-level entries need a request without one
+		query := s.db.Model(&domain.AuditLog{}).Scopes(sharedModels.InWorkspace("audit_logs"))
+		if req.ActorID != "" {
+			query = query.Where("actor_id = ?", req.ActorID)
+		}
+		if req.Action != "" {
+			query = query.Where("action = ?", req.Action)
+		}
+		if req.EntityType != "" {
+			query = query.Where("entity_type = ?", req.EntityType)
+		}
+		if req.EntityID != "" {
+			query = query.Where("entity_id = ?", req.EntityID)
+		}
+		if req.RequestID != "" {
+			query = query.Where("request_id = ?", req.RequestID)
+		}
+		if from != nil {
+			query = query.Where("created_at >= ?", *from)
+		}
+		if to != nil {
+			query = query.Where("created_at <= ?", *to)
+		}
+
+		var total int64
+		if err := query.Count(&total).Error; err != nil {
+			panic(err)
+		}
+
+		var entries []domain.AuditLog
+		offset := (req.Page - 1) * req.PerPage
+		if err := query.Order("created_at DESC").Offset(offset).Limit(req.PerPage).Find(&entries).Error; err != nil {
+			panic(err)
+		}
+
+		response := auditDto.NewAuditLogListDto(s.mapper.ModelsToDtos(entries), total, req.Page, req.PerPage)
+		return dto.Success(response)
+	})
+}
+
+// isValidAction checks that an action filter matches a recorded action
+func isValidAction(action string) bool {
+	switch domain.AuditAction(action) {
+	case domain.AuditActionCreate, domain.AuditActionUpdate, domain.AuditActionDelete,
+		domain.AuditActionExport, domain.AuditActionErase, domain.AuditActionPurge:
+		return true
+	}
+	return false
+}
+
+// parseTime parses an optional RFC3339 timestamp
+func parseTime(value string) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
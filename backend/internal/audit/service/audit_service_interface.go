@@ -0,0 +1,10 @@
+package service
+
+import (
+	auditDto "thothix-backend/internal/audit/dto"
+)
+
+// AuditServiceInterface defines the contract for querying the audit log using Response pattern
+type AuditServiceInterface interface {
+	GetAuditLogs(req *auditDto.AuditLogFilterRequest) *auditDto.GetAuditLogsResponse
+}
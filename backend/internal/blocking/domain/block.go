@@ -0,0 +1,33 @@
+package domain
+
+import (
+	commonModels "thothix-backend/internal/common/models"
+)
+
+// MaxBlocksPerUser is the number of users one user can block or mute
+const MaxBlocksPerUser = 1000
+
+// Type is how far a block goes
+type Type string
+
+const (
+	TypeBlock Type = "block" // No direct messages either way, no notifications, and messages collapsed in shared channels
+	TypeMute  Type = "mute"  // No notifications and messages collapsed in shared channels; direct messages still go through
+)
+
+// Block is a user blocking or muting another user, in every workspace the two users share
+type Block struct {
+	commonModels.BaseModel
+	BlockerID string `json:"blocker_id" gorm:"not null;uniqueIndex:idx_user_blocks_pair"`
+	BlockedID string `json:"blocked_id" gorm:"not null;uniqueIndex:idx_user_blocks_pair;index"`
+	Type      Type   `json:"type" gorm:"not null;default:'block'"`
+}
+
+func (Block) TableName() string {
+	return "user_blocks"
+}
+
+// StopsDirectMessages reports whether the block keeps the two users from sending each other direct messages
+func (b *Block) StopsDirectMessages() bool {
+	return b.Type == TypeBlock
+}
@@ -0,0 +1,40 @@
+package dto
+
+import (
+	"thothix-backend/internal/shared/dto"
+)
+
+// BlockDto represents a user blocked or muted by the current user in API responses
+type BlockDto struct {
+	ID        string `json:"id"`
+	UserID    string `json:"user_id"`
+	Type      string `json:"type"`
+	CreatedAt string `json:"created_at"`
+}
+
+// SetBlockRequest blocks or mutes a user, or switches an existing block between the two
+type SetBlockRequest struct {
+	Type string `json:"type" binding:"required,oneof=block mute"`
+}
+
+// BlockResponse wraps a single BlockDto response
+type BlockResponse struct {
+	*dto.Response[*BlockDto]
+}
+
+func NewBlockResponse(producer func() dto.Validation[*BlockDto]) *BlockResponse {
+	return &BlockResponse{
+		Response: dto.NewResponse(producer),
+	}
+}
+
+// BlockListResponse wraps the blocks of the current user
+type BlockListResponse struct {
+	*dto.Response[[]BlockDto]
+}
+
+func NewBlockListResponse(producer func() dto.Validation[[]BlockDto]) *BlockListResponse {
+	return &BlockListResponse{
+		Response: dto.NewResponse(producer),
+	}
+}
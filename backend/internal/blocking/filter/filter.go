@@ -0,0 +1,97 @@
+// Package filter applies the blocks and mutes between users to direct messages, notifications and the
+// messages of shared channels. It is kept apart from the blocking service so message delivery can use it
+// without depending on the HTTP slice.
+package filter
+
+import (
+	"gorm.io/gorm"
+
+	"thothix-backend/internal/blocking/domain"
+	chatDomain "thothix-backend/internal/chat/domain"
+	messageDomain "thothix-backend/internal/message/domain"
+)
+
+// DirectMessagesBlocked reports whether either user blocked the other, which stops direct messages both ways
+func DirectMessagesBlocked(db *gorm.DB, userID, otherID string) (bool, error) {
+	var count int64
+	err := db.Model(&domain.Block{}).
+		Where("type = ?", domain.TypeBlock).
+		Where("(blocker_id = ? AND blocked_id = ?) OR (blocker_id = ? AND blocked_id = ?)", userID, otherID, otherID, userID).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// ConversationBlocked reports whether channelID is a direct conversation, a channel whose only members are
+// userID and one other, and either of them blocked the other
+func ConversationBlocked(db *gorm.DB, userID, channelID string) (bool, error) {
+	var memberIDs []string
+	if err := db.Model(&chatDomain.ChannelMember{}).Where("channel_id = ?", channelID).
+		Distinct().Limit(3).Pluck("user_id", &memberIDs).Error; err != nil {
+		return false, err
+	}
+	if len(memberIDs) != 2 || (memberIDs[0] != userID && memberIDs[1] != userID) {
+		return false, nil
+	}
+
+	otherID := memberIDs[0]
+	if otherID == userID {
+		otherID = memberIDs[1]
+	}
+	return DirectMessagesBlocked(db, userID, otherID)
+}
+
+// Recipients returns the users of recipientIDs to notify of activity by senderID, leaving out those who
+// blocked or muted the sender and those the sender blocked
+func Recipients(db *gorm.DB, senderID string, recipientIDs []string) ([]string, error) {
+	if len(recipientIDs) == 0 {
+		return recipientIDs, nil
+	}
+
+	var blocks []domain.Block
+	if err := db.Where("blocker_id IN ? AND blocked_id = ?", recipientIDs, senderID).
+		Or("blocker_id = ? AND blocked_id IN ? AND type = ?", senderID, recipientIDs, domain.TypeBlock).
+		Find(&blocks).Error; err != nil {
+		return nil, err
+	}
+	excluded := make(map[string]bool, len(blocks))
+	for _, block := range blocks {
+		if block.BlockerID == senderID {
+			excluded[block.BlockedID] = true
+		} else {
+			excluded[block.BlockerID] = true
+		}
+	}
+
+	recipients := make([]string, 0, len(recipientIDs))
+	for _, id := range recipientIDs {
+		if !excluded[id] {
+			recipients = append(recipients, id)
+		}
+	}
+	return recipients, nil
+}
+
+// Collapse marks the messages whose sender viewerID blocked or muted, so clients fold them away
+func Collapse(db *gorm.DB, viewerID string, messages []messageDomain.Message) error {
+	if len(messages) == 0 {
+		return nil
+	}
+	senderIDs := make([]string, 0, len(messages))
+	for _, message := range messages {
+		senderIDs = append(senderIDs, message.SenderID)
+	}
+
+	var blocked []string
+	if err := db.Model(&domain.Block{}).Where("blocker_id = ? AND blocked_id IN ?", viewerID, senderIDs).
+		Pluck("blocked_id", &blocked).Error; err != nil {
+		return err
+	}
+	collapsed := make(map[string]bool, len(blocked))
+	for _, id := range blocked {
+		collapsed[id] = true
+	}
+	for i := range messages {
+		messages[i].Collapsed = collapsed[messages[i].SenderID]
+	}
+	return nil
+}
@@ -0,0 +1,159 @@
+package handlers
+
+import (
+	"github.com/gin-gonic/gin"
+
+	blockingDto "thothix-backend/internal/blocking/dto"
+	"thothix-backend/internal/blocking/service"
+	"thothix-backend/internal/shared/constants"
+	"thothix-backend/internal/shared/dto"
+	"thothix-backend/internal/shared/handlers"
+)
+
+type BlockHandler struct {
+	blockService service.BlockServiceInterface
+}
+
+func NewBlockHandler(blockService service.BlockServiceInterface) *BlockHandler {
+	return &BlockHandler{
+		blockService: blockService,
+	}
+}
+
+// ListBlocks godoc
+// @Summary List blocked and muted users
+// @Description List the users the authenticated user blocked or muted, most recent first
+// @Tags blocks
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} blockingDto.BlockDto
+// @Failure 401 {object} dto.ErrorViewModel
+// @Failure 500 {object} dto.ErrorViewModel
+// @Router /blocks [get]
+func (h *BlockHandler) ListBlocks(c *gin.Context) {
+	wrapper := handlers.WrapContext(c)
+
+	response := h.scopedService(c).ListBlocks()
+
+	response.Match(
+		// Exception case
+		func(err error) interface{} {
+			wrapper.SystemErrorResponse(err, "Failed to retrieve blocks")
+			return nil
+		},
+		// Success case
+		func(result []blockingDto.BlockDto) interface{} {
+			wrapper.SuccessResponse(result)
+			return nil
+		},
+		// Validation failure case
+		func(errors []dto.Error) interface{} {
+			respondWithFailure(wrapper, errors, "Blocks", "", "Block list validation failed")
+			return nil
+		},
+	)
+}
+
+// SetBlock godoc
+// @Summary Block or mute a user
+// @Description Block or mute a member of the workspace, or switch an existing block between the two. Neither lets the user's activity notify you, and their messages are collapsed in shared channels; a block also stops direct messages in both directions. The user is not told
+// @Tags blocks
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param userId path string true "User ID"
+// @Param block body blockingDto.SetBlockRequest true "Block type"
+// @Success 200 {object} blockingDto.BlockDto
+// @Failure 400 {object} dto.ErrorViewModel
+// @Failure 404 {object} dto.ErrorViewModel
+// @Failure 500 {object} dto.ErrorViewModel
+// @Router /blocks/{userId} [put]
+func (h *BlockHandler) SetBlock(c *gin.Context) {
+	wrapper := handlers.WrapContext(c)
+	userID := c.Param("userId")
+
+	var request blockingDto.SetBlockRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		wrapper.BadRequestErrorResponse("Invalid request payload")
+		return
+	}
+
+	response := h.scopedService(c).SetBlock(userID, &request)
+
+	response.Match(
+		// Exception case
+		func(err error) interface{} {
+			wrapper.SystemErrorResponse(err, "Failed to block user: %s", userID)
+			return nil
+		},
+		// Success case
+		func(result *blockingDto.BlockDto) interface{} {
+			wrapper.SuccessResponse(result)
+			return nil
+		},
+		// Validation failure case
+		func(errors []dto.Error) interface{} {
+			respondWithFailure(wrapper, errors, "User", userID, "Block validation failed")
+			return nil
+		},
+	)
+}
+
+// RemoveBlock godoc
+// @Summary Unblock a user
+// @Description Remove the block or mute the authenticated user set on a user
+// @Tags blocks
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param userId path string true "User ID"
+// @Success 200 {object} dto.ErrorViewModel
+// @Failure 404 {object} dto.ErrorViewModel
+// @Failure 500 {object} dto.ErrorViewModel
+// @Router /blocks/{userId} [delete]
+func (h *BlockHandler) RemoveBlock(c *gin.Context) {
+	wrapper := handlers.WrapContext(c)
+	userID := c.Param("userId")
+
+	response := h.scopedService(c).RemoveBlock(userID)
+
+	response.Match(
+		// Exception case
+		func(err error) interface{} {
+			wrapper.SystemErrorResponse(err, "Failed to unblock user: %s", userID)
+			return nil
+		},
+		// Success case
+		func(result *blockingDto.BlockDto) interface{} {
+			wrapper.NoContentResponse()
+			return nil
+		},
+		// Validation failure case
+		func(errors []dto.Error) interface{} {
+			respondWithFailure(wrapper, errors, "Block", userID, "Unblock validation failed")
+			return nil
+		},
+	)
+}
+
+// respondWithFailure maps service errors to not found, unauthorized or validation responses
+func respondWithFailure(wrapper *handlers.ContextWrapper, errors []dto.Error, resource, identifier, logMessage string) {
+	switch {
+	case len(errors) > 0 && errors[0].Code == constants.NotFoundError:
+		wrapper.NotFoundErrorResponse(resource, identifier)
+	case len(errors) > 0 && errors[0].Code == constants.UnauthorizedError:
+		wrapper.UnauthorizedErrorResponse(errors[0].Message)
+	default:
+		wrapper.ValidationErrorResponse(errors, "%s", logMessage)
+	}
+}
+
+// scopedService binds the service to the request context when supported,
+// so blocks are resolved for the current user and workspace
+func (h *BlockHandler) scopedService(c *gin.Context) service.BlockServiceInterface {
+	if aware, ok := h.blockService.(service.ContextAwareBlockService); ok {
+		return aware.WithContext(c.Request.Context())
+	}
+	return h.blockService
+}
@@ -0,0 +1,126 @@
+package handlers
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	blockingDto "thothix-backend/internal/blocking/dto"
+	"thothix-backend/internal/shared/dto"
+)
+
+// MockBlockService is a mock implementation of the BlockService
+type MockBlockService struct {
+	mock.Mock
+}
+
+func (m *MockBlockService) ListBlocks() *blockingDto.BlockListResponse {
+	args := m.Called()
+	return args.Get(0).(*blockingDto.BlockListResponse)
+}
+
+func (m *MockBlockService) SetBlock(userID string, req *blockingDto.SetBlockRequest) *blockingDto.BlockResponse {
+	args := m.Called(userID, req)
+	return args.Get(0).(*blockingDto.BlockResponse)
+}
+
+func (m *MockBlockService) RemoveBlock(userID string) *blockingDto.BlockResponse {
+	args := m.Called(userID)
+	return args.Get(0).(*blockingDto.BlockResponse)
+}
+
+type BlockHandlerTestSuite struct {
+	suite.Suite
+	mockService *MockBlockService
+	router      *gin.Engine
+}
+
+func (suite *BlockHandlerTestSuite) SetupSuite() {
+	gin.SetMode(gin.TestMode)
+}
+
+func (suite *BlockHandlerTestSuite) SetupTest() {
+	suite.mockService = new(MockBlockService)
+	handler := NewBlockHandler(suite.mockService)
+
+	suite.router = gin.New()
+	suite.router.GET("/blocks", handler.ListBlocks)
+	suite.router.PUT("/blocks/:userId", handler.SetBlock)
+	suite.router.DELETE("/blocks/:userId", handler.RemoveBlock)
+}
+
+func (suite *BlockHandlerTestSuite) TestSetBlock_Success() {
+	// Arrange
+	mockResponse := blockingDto.NewBlockResponse(func() dto.Validation[*blockingDto.BlockDto] {
+		return dto.Success(&blockingDto.BlockDto{ID: "block-1", UserID: "user-2", Type: "mute"})
+	})
+
+	suite.mockService.On("SetBlock", "user-2", &blockingDto.SetBlockRequest{Type: "mute"}).Return(mockResponse)
+
+	// Act
+	req, _ := http.NewRequest("PUT", "/blocks/user-2", bytes.NewBufferString(`{"type":"mute"}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(suite.T(), http.StatusOK, w.Code)
+	assert.Contains(suite.T(), w.Body.String(), "block-1")
+}
+
+func (suite *BlockHandlerTestSuite) TestSetBlock_InvalidType() {
+	// Act
+	req, _ := http.NewRequest("PUT", "/blocks/user-2", bytes.NewBufferString(`{"type":"hide"}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(suite.T(), http.StatusBadRequest, w.Code)
+	suite.mockService.AssertNotCalled(suite.T(), "SetBlock", mock.Anything, mock.Anything)
+}
+
+func (suite *BlockHandlerTestSuite) TestSetBlock_UserNotFound() {
+	// Arrange
+	mockResponse := blockingDto.NewBlockResponse(func() dto.Validation[*blockingDto.BlockDto] {
+		return dto.Invalid[*blockingDto.BlockDto](dto.NewError("NOT_FOUND", "User not found", nil))
+	})
+
+	suite.mockService.On("SetBlock", "missing", &blockingDto.SetBlockRequest{Type: "block"}).Return(mockResponse)
+
+	// Act
+	req, _ := http.NewRequest("PUT", "/blocks/missing", bytes.NewBufferString(`{"type":"block"}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(suite.T(), http.StatusNotFound, w.Code)
+}
+
+func (suite *BlockHandlerTestSuite) TestRemoveBlock_Success() {
+	// Arrange
+	mockResponse := blockingDto.NewBlockResponse(func() dto.Validation[*blockingDto.BlockDto] {
+		return dto.Success(&blockingDto.BlockDto{ID: "block-1", UserID: "user-2", Type: "block"})
+	})
+
+	suite.mockService.On("RemoveBlock", "user-2").Return(mockResponse)
+
+	// Act
+	req, _ := http.NewRequest("DELETE", "/blocks/user-2", nil)
+	w := httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(suite.T(), http.StatusOK, w.Code)
+	suite.mockService.AssertExpectations(suite.T())
+}
+
+func TestBlockHandlerTestSuite(t *testing.T) {
+	suite.Run(t, new(BlockHandlerTestSuite))
+}
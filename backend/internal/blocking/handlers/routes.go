@@ -0,0 +1,18 @@
+package handlers
+
+import (
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"thothix-backend/internal/blocking/service"
+)
+
+// RegisterBlockRoutes registers the routes blocking and muting members of the current workspace
+func RegisterBlockRoutes(router *gin.RouterGroup, db *gorm.DB) {
+	blockService := service.NewBlockService(db)
+	blockHandler := NewBlockHandler(blockService)
+
+	blocks := router.Group("/blocks")
+	blocks.GET("", blockHandler.ListBlocks)
+	blocks.PUT("/:userId", blockHandler.SetBlock)
+	blocks.DELETE("/:userId", blockHandler.RemoveBlock)
+}
@@ -0,0 +1,39 @@
+package mappers
+
+import (
+	"time"
+
+	"thothix-backend/internal/blocking/domain"
+	blockingDto "thothix-backend/internal/blocking/dto"
+)
+
+// BlockMapper handles conversion between blocks and their DTOs
+type BlockMapper struct{}
+
+// NewBlockMapper creates a new BlockMapper instance
+func NewBlockMapper() *BlockMapper {
+	return &BlockMapper{}
+}
+
+// BlockToDto converts a Block to BlockDto, seen from the blocker
+func (m *BlockMapper) BlockToDto(block *domain.Block) *blockingDto.BlockDto {
+	if block == nil {
+		return nil
+	}
+
+	return &blockingDto.BlockDto{
+		ID:        block.ID,
+		UserID:    block.BlockedID,
+		Type:      string(block.Type),
+		CreatedAt: block.CreatedAt.UTC().Format(time.RFC3339),
+	}
+}
+
+// BlocksToDtos converts a list of Blocks to BlockDtos
+func (m *BlockMapper) BlocksToDtos(blocks []domain.Block) []blockingDto.BlockDto {
+	result := make([]blockingDto.BlockDto, 0, len(blocks))
+	for i := range blocks {
+		result = append(result, *m.BlockToDto(&blocks[i]))
+	}
+	return result
+}
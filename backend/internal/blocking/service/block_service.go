@@ -0,0 +1,133 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"gorm.io/gorm"
+
+	"thothix-backend/internal/blocking/domain"
+	blockingDto "thothix-backend/internal/blocking/dto"
+	"thothix-backend/internal/blocking/mappers"
+	"thothix-backend/internal/shared/constants"
+	"thothix-backend/internal/shared/dto"
+	sharedMiddleware "thothix-backend/internal/shared/middleware"
+	sharedModels "thothix-backend/internal/shared/models"
+	usersDomain "thothix-backend/internal/users/domain"
+)
+
+type BlockService struct {
+	db     *gorm.DB
+	mapper *mappers.BlockMapper
+}
+
+func NewBlockService(db *gorm.DB) *BlockService {
+	return &BlockService{
+		db:     db,
+		mapper: mappers.NewBlockMapper(),
+	}
+}
+
+// WithContext returns a copy of the service whose database session carries ctx
+func (s *BlockService) WithContext(ctx context.Context) *BlockService {
+	return &BlockService{
+		db:     s.db.WithContext(ctx),
+		mapper: s.mapper,
+	}
+}
+
+// ListBlocks lists the users the current user blocked or muted, most recent first
+func (s *BlockService) ListBlocks() *blockingDto.BlockListResponse {
+	return blockingDto.NewBlockListResponse(func() dto.Validation[[]blockingDto.BlockDto] {
+		userID, ok := sharedMiddleware.UserIDFromContext(s.db.Statement.Context)
+		if !ok {
+			return dto.Failure[[]blockingDto.BlockDto](dto.NewError(constants.UnauthorizedError, "User not authenticated", nil))
+		}
+
+		var blocks []domain.Block
+		if err := s.db.Where("blocker_id = ?", userID).Order("created_at DESC").Find(&blocks).Error; err != nil {
+			panic(err)
+		}
+		return dto.Success(s.mapper.BlocksToDtos(blocks))
+	})
+}
+
+// SetBlock blocks or mutes a member of the current workspace, or changes how an existing block applies
+// The blocked user is not told; blocks apply in every workspace the two users share
+func (s *BlockService) SetBlock(userID string, req *blockingDto.SetBlockRequest) *blockingDto.BlockResponse {
+	return blockingDto.NewBlockResponse(func() dto.Validation[*blockingDto.BlockDto] {
+		if req == nil || (req.Type != string(domain.TypeBlock) && req.Type != string(domain.TypeMute)) {
+			return dto.Failure[*blockingDto.BlockDto](dto.NewError(constants.ValidationError, "Type must be block or mute", nil))
+		}
+
+		blockerID, ok := sharedMiddleware.UserIDFromContext(s.db.Statement.Context)
+		if !ok {
+			return dto.Failure[*blockingDto.BlockDto](dto.NewError(constants.UnauthorizedError, "User not authenticated", nil))
+		}
+		if userID == blockerID {
+			return dto.Invalid[*blockingDto.BlockDto](dto.NewError(constants.ValidationError, "You cannot block yourself", nil))
+		}
+
+		var user usersDomain.User
+		if err := s.db.Scopes(sharedModels.WorkspaceMembers).Where("id = ?", userID).First(&user).Error; err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return dto.Invalid[*blockingDto.BlockDto](dto.NewError(constants.NotFoundError, "User not found", nil))
+			}
+			panic(err)
+		}
+
+		var blocks []domain.Block
+		if err := s.db.Where("blocker_id = ? AND blocked_id = ?", blockerID, user.ID).Limit(1).Find(&blocks).Error; err != nil {
+			panic(err)
+		}
+		if len(blocks) > 0 {
+			block := &blocks[0]
+			if block.Type != domain.Type(req.Type) {
+				block.Type = domain.Type(req.Type)
+				if err := s.db.Model(block).Update("type", block.Type).Error; err != nil {
+					panic(err)
+				}
+			}
+			return dto.Success(s.mapper.BlockToDto(block))
+		}
+
+		var count int64
+		if err := s.db.Model(&domain.Block{}).Where("blocker_id = ?", blockerID).Count(&count).Error; err != nil {
+			panic(err)
+		}
+		if count >= domain.MaxBlocksPerUser {
+			return dto.Invalid[*blockingDto.BlockDto](dto.NewError(constants.ValidationError,
+				fmt.Sprintf("You cannot block or mute more than %d users", domain.MaxBlocksPerUser), nil))
+		}
+
+		block := domain.Block{BlockerID: blockerID, BlockedID: user.ID, Type: domain.Type(req.Type)}
+		if err := s.db.Create(&block).Error; err != nil {
+			panic(err)
+		}
+		return dto.Success(s.mapper.BlockToDto(&block))
+	})
+}
+
+// RemoveBlock unblocks or unmutes a user
+// It does not require the user to be a member of the current workspace, so blocks of users who left can be removed
+func (s *BlockService) RemoveBlock(userID string) *blockingDto.BlockResponse {
+	return blockingDto.NewBlockResponse(func() dto.Validation[*blockingDto.BlockDto] {
+		blockerID, ok := sharedMiddleware.UserIDFromContext(s.db.Statement.Context)
+		if !ok {
+			return dto.Failure[*blockingDto.BlockDto](dto.NewError(constants.UnauthorizedError, "User not authenticated", nil))
+		}
+
+		var block domain.Block
+		if err := s.db.Where("blocker_id = ? AND blocked_id = ?", blockerID, userID).First(&block).Error; err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return dto.Invalid[*blockingDto.BlockDto](dto.NewError(constants.NotFoundError, "Block not found", nil))
+			}
+			panic(err)
+		}
+
+		if err := s.db.Delete(&block).Error; err != nil {
+			panic(err)
+		}
+		return dto.Success(s.mapper.BlockToDto(&block))
+	})
+}
@@ -0,0 +1,19 @@
+package service
+
+import (
+	"context"
+
+	blockingDto "thothix-backend/internal/blocking/dto"
+)
+
+// BlockServiceInterface defines the contract for blocking and muting users using Response pattern
+type BlockServiceInterface interface {
+	ListBlocks() *blockingDto.BlockListResponse
+	SetBlock(userID string, req *blockingDto.SetBlockRequest) *blockingDto.BlockResponse
+	RemoveBlock(userID string) *blockingDto.BlockResponse
+}
+
+// ContextAwareBlockService is implemented by services that can bind their database session to a request context
+type ContextAwareBlockService interface {
+	WithContext(ctx context.Context) *BlockService
+}
@@ -0,0 +1,168 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/gorm"
+	"thothix-backend/internal/blocking/domain"
+	blockingDto "thothix-backend/internal/blocking/dto"
+	"thothix-backend/internal/blocking/filter"
+	messageDomain "thothix-backend/internal/message/domain"
+	sharedMiddleware "thothix-backend/internal/shared/middleware"
+	sharedModels "thothix-backend/internal/shared/models"
+	sharedTesting "thothix-backend/internal/shared/testing"
+	usersDomain "thothix-backend/internal/users/domain"
+	workspaceDomain "thothix-backend/internal/workspace/domain"
+)
+
+type BlockServiceTestSuite struct {
+	suite.Suite
+	container *sharedTesting.PostgresTestContainer
+}
+
+func (suite *BlockServiceTestSuite) SetupSuite() {
+	suite.container = sharedTesting.GetSharedTestContainer(
+		suite.T(),
+		"blocking/service",
+		[]interface{}{
+			&usersDomain.User{}, &sharedModels.UserRole{}, &workspaceDomain.Workspace{}, &domain.Block{},
+		},
+	)
+}
+
+func (suite *BlockServiceTestSuite) createWorkspace(db *gorm.DB, slug string) *workspaceDomain.Workspace {
+	workspace := &workspaceDomain.Workspace{Name: slug, Slug: slug}
+	assert.NoError(suite.T(), db.Create(workspace).Error)
+	return workspace
+}
+
+// createMember stores a user who is a member of workspace
+func (suite *BlockServiceTestSuite) createMember(db *gorm.DB, name string, workspace *workspaceDomain.Workspace) *usersDomain.User {
+	user := &usersDomain.User{Email: name + "@example.com", Name: name, SystemRole: sharedModels.RoleUser}
+	user.ID = uuid.New().String()
+	assert.NoError(suite.T(), db.Create(user).Error)
+
+	resourceType := sharedModels.ResourceTypeWorkspace
+	assert.NoError(suite.T(), db.Create(&sharedModels.UserRole{
+		UserID: user.ID, Role: sharedModels.RoleUser, ResourceType: &resourceType, ResourceID: &workspace.ID,
+	}).Error)
+	return user
+}
+
+// serviceFor creates a service acting as the given user in workspace
+func (suite *BlockServiceTestSuite) serviceFor(db *gorm.DB, userID string, workspace *workspaceDomain.Workspace) *BlockService {
+	ctx := sharedMiddleware.WithWorkspaceID(sharedMiddleware.WithUserID(context.Background(), userID), workspace.ID)
+	return NewBlockService(db).WithContext(ctx)
+}
+
+func (suite *BlockServiceTestSuite) TestSetBlock_MembersOnly() {
+	suite.container.WithTransaction(func(db *gorm.DB) {
+		// Arrange
+		workspace := suite.createWorkspace(db, "block-members")
+		user := suite.createMember(db, "block-members-user", workspace)
+		other := suite.createMember(db, "block-members-other", workspace)
+		stranger := suite.createMember(db, "block-members-stranger", suite.createWorkspace(db, "block-members-elsewhere"))
+		service := suite.serviceFor(db, user.ID, workspace)
+		request := &blockingDto.SetBlockRequest{Type: "block"}
+
+		// Act
+		blocked := service.SetBlock(other.ID, request)
+		self := service.SetBlock(user.ID, request)
+		outside := service.SetBlock(stranger.ID, request)
+
+		// Assert
+		block := sharedTesting.AssertSuccessWithValue(suite.T(), blocked.Response)
+		assert.Equal(suite.T(), other.ID, block.UserID)
+		assert.Equal(suite.T(), "block", block.Type)
+		sharedTesting.AssertValidationErrorWithCode(suite.T(), self.Response, "VALIDATION_ERROR")
+		sharedTesting.AssertValidationErrorWithCode(suite.T(), outside.Response, "NOT_FOUND")
+	})
+}
+
+func (suite *BlockServiceTestSuite) TestSetBlock_SwitchesTypeAndUnblocks() {
+	suite.container.WithTransaction(func(db *gorm.DB) {
+		// Arrange
+		workspace := suite.createWorkspace(db, "block-switch")
+		user := suite.createMember(db, "block-switch-user", workspace)
+		other := suite.createMember(db, "block-switch-other", workspace)
+		service := suite.serviceFor(db, user.ID, workspace)
+
+		// Act
+		first := sharedTesting.AssertSuccessWithValue(suite.T(), service.SetBlock(other.ID, &blockingDto.SetBlockRequest{Type: "block"}).Response)
+		switched := sharedTesting.AssertSuccessWithValue(suite.T(), service.SetBlock(other.ID, &blockingDto.SetBlockRequest{Type: "mute"}).Response)
+		listed := sharedTesting.AssertSuccessWithValue(suite.T(), service.ListBlocks().Response)
+		removed := service.RemoveBlock(other.ID)
+		again := service.RemoveBlock(other.ID)
+
+		// Assert
+		assert.Equal(suite.T(), first.ID, switched.ID)
+		assert.Equal(suite.T(), "mute", switched.Type)
+		assert.Len(suite.T(), listed, 1)
+		sharedTesting.AssertSuccessWithValue(suite.T(), removed.Response)
+		sharedTesting.AssertValidationErrorWithCode(suite.T(), again.Response, "NOT_FOUND")
+		assert.Empty(suite.T(), sharedTesting.AssertSuccessWithValue(suite.T(), service.ListBlocks().Response))
+	})
+}
+
+func (suite *BlockServiceTestSuite) TestFilter_DirectMessagesBlockedBothWays() {
+	suite.container.WithTransaction(func(db *gorm.DB) {
+		// Arrange
+		workspace := suite.createWorkspace(db, "filter-direct")
+		user := suite.createMember(db, "filter-direct-user", workspace)
+		blocked := suite.createMember(db, "filter-direct-blocked", workspace)
+		muted := suite.createMember(db, "filter-direct-muted", workspace)
+		service := suite.serviceFor(db, user.ID, workspace)
+		sharedTesting.AssertSuccessWithValue(suite.T(), service.SetBlock(blocked.ID, &blockingDto.SetBlockRequest{Type: "block"}).Response)
+		sharedTesting.AssertSuccessWithValue(suite.T(), service.SetBlock(muted.ID, &blockingDto.SetBlockRequest{Type: "mute"}).Response)
+
+		// Act & Assert
+		for _, pair := range [][2]string{{user.ID, blocked.ID}, {blocked.ID, user.ID}} {
+			stopped, err := filter.DirectMessagesBlocked(db, pair[0], pair[1])
+			assert.NoError(suite.T(), err)
+			assert.True(suite.T(), stopped)
+		}
+		stopped, err := filter.DirectMessagesBlocked(db, muted.ID, user.ID)
+		assert.NoError(suite.T(), err)
+		assert.False(suite.T(), stopped, "muted users can still send direct messages")
+	})
+}
+
+func (suite *BlockServiceTestSuite) TestFilter_RecipientsAndCollapse() {
+	suite.container.WithTransaction(func(db *gorm.DB) {
+		// Arrange
+		workspace := suite.createWorkspace(db, "filter-fanout")
+		user := suite.createMember(db, "filter-fanout-user", workspace)
+		blocked := suite.createMember(db, "filter-fanout-blocked", workspace)
+		muted := suite.createMember(db, "filter-fanout-muted", workspace)
+		bystander := suite.createMember(db, "filter-fanout-bystander", workspace)
+		service := suite.serviceFor(db, user.ID, workspace)
+		sharedTesting.AssertSuccessWithValue(suite.T(), service.SetBlock(blocked.ID, &blockingDto.SetBlockRequest{Type: "block"}).Response)
+		sharedTesting.AssertSuccessWithValue(suite.T(), service.SetBlock(muted.ID, &blockingDto.SetBlockRequest{Type: "mute"}).Response)
+		messages := []messageDomain.Message{{SenderID: blocked.ID}, {SenderID: muted.ID}, {SenderID: bystander.ID}}
+
+		// Act
+		fromBlocked, err := filter.Recipients(db, blocked.ID, []string{user.ID, bystander.ID})
+		assert.NoError(suite.T(), err)
+		fromMuted, err := filter.Recipients(db, muted.ID, []string{user.ID, bystander.ID})
+		assert.NoError(suite.T(), err)
+		fromUser, err := filter.Recipients(db, user.ID, []string{blocked.ID, muted.ID, bystander.ID})
+		assert.NoError(suite.T(), err)
+		assert.NoError(suite.T(), filter.Collapse(db, user.ID, messages))
+
+		// Assert
+		assert.Equal(suite.T(), []string{bystander.ID}, fromBlocked)
+		assert.Equal(suite.T(), []string{bystander.ID}, fromMuted)
+		assert.Equal(suite.T(), []string{muted.ID, bystander.ID}, fromUser, "a mute only silences the muted user")
+		assert.True(suite.T(), messages[0].Collapsed)
+		assert.True(suite.T(), messages[1].Collapsed)
+		assert.False(suite.T(), messages[2].Collapsed)
+	})
+}
+
+func TestBlockServiceTestSuite(t *testing.T) {
+	suite.Run(t, new(BlockServiceTestSuite))
+}
@@ -0,0 +1,34 @@
+package domain
+
+import (
+	"time"
+
+	commonModels "thothix-backend/internal/common/models"
+	messageDomain "thothix-backend/internal/message/domain"
+)
+
+// MaxPinsPerChannel is the number of messages a channel can have pinned at once
+const MaxPinsPerChannel = 50
+
+// Pin marks a message as pinned in its channel, visible to everyone with access to the channel
+type Pin struct {
+	commonModels.BaseModel
+	ChannelID string                 `json:"channel_id" gorm:"type:uuid;not null;index"`
+	MessageID string                 `json:"message_id" gorm:"type:uuid;not null;uniqueIndex"`
+	PinnedBy  string                 `json:"pinned_by" gorm:"not null"`
+	Message   *messageDomain.Message `json:"message,omitempty" gorm:"constraint:OnDelete:CASCADE"`
+}
+
+// SavedItem is a message a user saved for later, private to that user
+type SavedItem struct {
+	commonModels.BaseModel
+	UserID    string                 `json:"user_id" gorm:"not null;uniqueIndex:idx_saved_items_user_message"`
+	MessageID string                 `json:"message_id" gorm:"type:uuid;not null;uniqueIndex:idx_saved_items_user_message"`
+	DoneAt    *time.Time             `json:"done_at,omitempty"` // Set when the user marks the item done
+	Message   *messageDomain.Message `json:"message,omitempty" gorm:"constraint:OnDelete:CASCADE"`
+}
+
+// IsDone reports whether the user marked the item done
+func (s *SavedItem) IsDone() bool {
+	return s.DoneAt != nil
+}
@@ -0,0 +1,96 @@
+package dto
+
+import (
+	messageDto "thothix-backend/internal/message/dto"
+	"thothix-backend/internal/shared/dto"
+)
+
+// === Pin DTOs ===
+
+// PinDto represents a pinned message in API responses
+type PinDto struct {
+	ID        string                 `json:"id"`
+	ChannelID string                 `json:"channel_id"`
+	PinnedBy  string                 `json:"pinned_by"`
+	PinnedAt  string                 `json:"pinned_at"`
+	Message   *messageDto.MessageDto `json:"message"`
+}
+
+// PinMessageRequest represents a request to pin a message of the channel
+type PinMessageRequest struct {
+	MessageID string `json:"message_id" binding:"required"`
+}
+
+// PinResponse wraps a single PinDto response
+type PinResponse struct {
+	*dto.Response[*PinDto]
+}
+
+func NewPinResponse(producer func() dto.Validation[*PinDto]) *PinResponse {
+	return &PinResponse{
+		Response: dto.NewResponse(producer),
+	}
+}
+
+// PinListResponse wraps the pins of a channel
+type PinListResponse struct {
+	*dto.Response[[]PinDto]
+}
+
+func NewPinListResponse(producer func() dto.Validation[[]PinDto]) *PinListResponse {
+	return &PinListResponse{
+		Response: dto.NewResponse(producer),
+	}
+}
+
+// === Saved item DTOs ===
+
+// SavedItemDto represents a message saved for later in API responses
+type SavedItemDto struct {
+	ID      string                 `json:"id"`
+	SavedAt string                 `json:"saved_at"`
+	Done    bool                   `json:"done"`
+	DoneAt  string                 `json:"done_at,omitempty"`
+	Message *messageDto.MessageDto `json:"message"`
+}
+
+// SaveMessageRequest represents a request to save a message for later
+type SaveMessageRequest struct {
+	MessageID string `json:"message_id" binding:"required"`
+}
+
+// UpdateSavedItemRequest marks a saved item done or not done
+type UpdateSavedItemRequest struct {
+	Done *bool `json:"done" binding:"required"`
+}
+
+// SavedItemListRequest filters and paginates the saved items of the current user
+type SavedItemListRequest struct {
+	dto.PaginationRequest
+	Done *bool `form:"done"` // Only done or only open items, all when omitted
+}
+
+// SavedItemListDto is a page of saved items
+type SavedItemListDto = dto.PaginatedListResponse[SavedItemDto]
+
+// SavedItemResponse wraps a single SavedItemDto response
+type SavedItemResponse struct {
+	*dto.Response[*SavedItemDto]
+}
+
+func NewSavedItemResponse(producer func() dto.Validation[*SavedItemDto]) *SavedItemResponse {
+	return &SavedItemResponse{
+		Response: dto.NewResponse(producer),
+	}
+}
+
+// SavedItemListResponse wraps a page of saved items
+type SavedItemListResponse struct {
+	*dto.Response[*SavedItemListDto]
+}
+
+func NewSavedItemListResponse(producer func() dto.Validation[*SavedItemListDto]) *SavedItemListResponse {
+	return &SavedItemListResponse{
+		Response: dto.NewResponse(producer),
+	}
+}
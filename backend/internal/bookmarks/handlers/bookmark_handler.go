@@ -0,0 +1,347 @@
+package handlers
+
+import (
+	"github.com/gin-gonic/gin"
+
+	bookmarkDto "thothix-backend/internal/bookmarks/dto"
+	"thothix-backend/internal/bookmarks/service"
+	"thothix-backend/internal/shared/constants"
+	"thothix-backend/internal/shared/dto"
+	"thothix-backend/internal/shared/handlers"
+)
+
+type BookmarkHandler struct {
+	bookmarkService service.BookmarkServiceInterface
+}
+
+func NewBookmarkHandler(bookmarkService service.BookmarkServiceInterface) *BookmarkHandler {
+	return &BookmarkHandler{
+		bookmarkService: bookmarkService,
+	}
+}
+
+// ListPins godoc
+// @Summary List pinned messages
+// @Description List the pinned messages of a channel, most recently pinned first
+// @Tags pins
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Channel ID"
+// @Success 200 {array} bookmarkDto.PinDto
+// @Failure 403 {object} dto.ErrorViewModel
+// @Failure 404 {object} dto.ErrorViewModel
+// @Failure 500 {object} dto.ErrorViewModel
+// @Router /channels/{id}/pins [get]
+func (h *BookmarkHandler) ListPins(c *gin.Context) {
+	wrapper := handlers.WrapContext(c)
+	channelID := c.Param("id")
+
+	response := h.scopedService(c).ListPins(channelID)
+
+	response.Match(
+		// Exception case
+		func(err error) interface{} {
+			wrapper.SystemErrorResponse(err, "Failed to retrieve pins of channel: %s", channelID)
+			return nil
+		},
+		// Success case
+		func(result []bookmarkDto.PinDto) interface{} {
+			wrapper.SuccessResponse(result)
+			return nil
+		},
+		// Validation failure case
+		func(errors []dto.Error) interface{} {
+			respondWithFailure(wrapper, errors, "Channel", channelID, "Pin list validation failed")
+			return nil
+		},
+	)
+}
+
+// PinMessage godoc
+// @Summary Pin a message
+// @Description Pin a message of the channel. Channel managers can pin any message and authors their own, up to 50 per channel
+// @Tags pins
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Channel ID"
+// @Param pin body bookmarkDto.PinMessageRequest true "Message to pin"
+// @Success 201 {object} bookmarkDto.PinDto
+// @Failure 400 {object} dto.ErrorViewModel
+// @Failure 403 {object} dto.ErrorViewModel
+// @Failure 404 {object} dto.ErrorViewModel
+// @Failure 409 {object} dto.ErrorViewModel
+// @Failure 500 {object} dto.ErrorViewModel
+// @Router /channels/{id}/pins [post]
+func (h *BookmarkHandler) PinMessage(c *gin.Context) {
+	wrapper := handlers.WrapContext(c)
+	channelID := c.Param("id")
+
+	var request bookmarkDto.PinMessageRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		wrapper.BadRequestErrorResponse("Invalid request payload")
+		return
+	}
+
+	response := h.scopedService(c).PinMessage(channelID, &request)
+
+	response.Match(
+		// Exception case
+		func(err error) interface{} {
+			wrapper.SystemErrorResponse(err, "Failed to pin message %s in channel: %s", request.MessageID, channelID)
+			return nil
+		},
+		// Success case
+		func(result *bookmarkDto.PinDto) interface{} {
+			wrapper.CreatedResponse(result)
+			return nil
+		},
+		// Validation failure case
+		func(errors []dto.Error) interface{} {
+			respondWithFailure(wrapper, errors, "Message", request.MessageID, "Pin validation failed")
+			return nil
+		},
+	)
+}
+
+// UnpinMessage godoc
+// @Summary Unpin a message
+// @Description Remove a pin from the channel. Channel managers can unpin any message and authors their own
+// @Tags pins
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Channel ID"
+// @Param messageId path string true "Message ID"
+// @Success 200 {object} dto.ErrorViewModel
+// @Failure 403 {object} dto.ErrorViewModel
+// @Failure 404 {object} dto.ErrorViewModel
+// @Failure 500 {object} dto.ErrorViewModel
+// @Router /channels/{id}/pins/{messageId} [delete]
+func (h *BookmarkHandler) UnpinMessage(c *gin.Context) {
+	wrapper := handlers.WrapContext(c)
+	channelID, messageID := c.Param("id"), c.Param("messageId")
+
+	response := h.scopedService(c).UnpinMessage(channelID, messageID)
+
+	response.Match(
+		// Exception case
+		func(err error) interface{} {
+			wrapper.SystemErrorResponse(err, "Failed to unpin message %s in channel: %s", messageID, channelID)
+			return nil
+		},
+		// Success case
+		func(result *bookmarkDto.PinDto) interface{} {
+			wrapper.NoContentResponse()
+			return nil
+		},
+		// Validation failure case
+		func(errors []dto.Error) interface{} {
+			respondWithFailure(wrapper, errors, "Pin", messageID, "Unpin validation failed")
+			return nil
+		},
+	)
+}
+
+// ListSavedItems godoc
+// @Summary List saved items
+// @Description List the messages the authenticated user saved for later, most recent first. Messages they can no longer read are left out
+// @Tags saved
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param page query int false "Page number (default: 1)"
+// @Param per_page query int false "Items per page (default: 20, max: 100)"
+// @Param done query bool false "Only done or only open items"
+// @Success 200 {object} bookmarkDto.SavedItemListDto
+// @Failure 400 {object} dto.ErrorViewModel
+// @Failure 500 {object} dto.ErrorViewModel
+// @Router /saved [get]
+func (h *BookmarkHandler) ListSavedItems(c *gin.Context) {
+	wrapper := handlers.WrapContext(c)
+
+	var request bookmarkDto.SavedItemListRequest
+	if err := c.ShouldBindQuery(&request); err != nil {
+		wrapper.BadRequestErrorResponse("Invalid query parameters")
+		return
+	}
+
+	// Set defaults
+	if request.Page == 0 {
+		request.Page = 1
+	}
+	if request.PerPage == 0 {
+		request.PerPage = 20
+	}
+
+	response := h.scopedService(c).ListSavedItems(&request)
+
+	response.Match(
+		// Exception case
+		func(err error) interface{} {
+			wrapper.SystemErrorResponse(err, "Failed to retrieve saved items")
+			return nil
+		},
+		// Success case
+		func(result *bookmarkDto.SavedItemListDto) interface{} {
+			wrapper.SuccessResponse(result)
+			return nil
+		},
+		// Validation failure case
+		func(errors []dto.Error) interface{} {
+			wrapper.ValidationErrorResponse(errors, "Saved item list validation failed")
+			return nil
+		},
+	)
+}
+
+// SaveMessage godoc
+// @Summary Save a message for later
+// @Description Save a message the authenticated user can read. Saving it again returns the existing item
+// @Tags saved
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param item body bookmarkDto.SaveMessageRequest true "Message to save"
+// @Success 201 {object} bookmarkDto.SavedItemDto
+// @Failure 400 {object} dto.ErrorViewModel
+// @Failure 404 {object} dto.ErrorViewModel
+// @Failure 500 {object} dto.ErrorViewModel
+// @Router /saved [post]
+func (h *BookmarkHandler) SaveMessage(c *gin.Context) {
+	wrapper := handlers.WrapContext(c)
+
+	var request bookmarkDto.SaveMessageRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		wrapper.BadRequestErrorResponse("Invalid request payload")
+		return
+	}
+
+	response := h.scopedService(c).SaveMessage(&request)
+
+	response.Match(
+		// Exception case
+		func(err error) interface{} {
+			wrapper.SystemErrorResponse(err, "Failed to save message: %s", request.MessageID)
+			return nil
+		},
+		// Success case
+		func(result *bookmarkDto.SavedItemDto) interface{} {
+			wrapper.CreatedResponse(result)
+			return nil
+		},
+		// Validation failure case
+		func(errors []dto.Error) interface{} {
+			respondWithFailure(wrapper, errors, "Message", request.MessageID, "Save message validation failed")
+			return nil
+		},
+	)
+}
+
+// UpdateSavedItem godoc
+// @Summary Mark a saved item done
+// @Description Mark a saved item of the authenticated user done or not done
+// @Tags saved
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Saved item ID"
+// @Param item body bookmarkDto.UpdateSavedItemRequest true "Done state"
+// @Success 200 {object} bookmarkDto.SavedItemDto
+// @Failure 400 {object} dto.ErrorViewModel
+// @Failure 404 {object} dto.ErrorViewModel
+// @Failure 500 {object} dto.ErrorViewModel
+// @Router /saved/{id} [patch]
+func (h *BookmarkHandler) UpdateSavedItem(c *gin.Context) {
+	wrapper := handlers.WrapContext(c)
+	itemID := c.Param("id")
+
+	var request bookmarkDto.UpdateSavedItemRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		wrapper.BadRequestErrorResponse("Invalid request payload")
+		return
+	}
+
+	response := h.scopedService(c).UpdateSavedItem(itemID, &request)
+
+	response.Match(
+		// Exception case
+		func(err error) interface{} {
+			wrapper.SystemErrorResponse(err, "Failed to update saved item: %s", itemID)
+			return nil
+		},
+		// Success case
+		func(result *bookmarkDto.SavedItemDto) interface{} {
+			wrapper.SuccessResponse(result)
+			return nil
+		},
+		// Validation failure case
+		func(errors []dto.Error) interface{} {
+			respondWithFailure(wrapper, errors, "Saved item", itemID, "Saved item update validation failed")
+			return nil
+		},
+	)
+}
+
+// DeleteSavedItem godoc
+// @Summary Remove a saved item
+// @Description Remove a saved item of the authenticated user
+// @Tags saved
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Saved item ID"
+// @Success 200 {object} dto.ErrorViewModel
+// @Failure 404 {object} dto.ErrorViewModel
+// @Failure 500 {object} dto.ErrorViewModel
+// @Router /saved/{id} [delete]
+func (h *BookmarkHandler) DeleteSavedItem(c *gin.Context) {
+	wrapper := handlers.WrapContext(c)
+	itemID := c.Param("id")
+
+	response := h.scopedService(c).DeleteSavedItem(itemID)
+
+	response.Match(
+		// Exception case
+		func(err error) interface{} {
+			wrapper.SystemErrorResponse(err, "Failed to remove saved item: %s", itemID)
+			return nil
+		},
+		// Success case
+		func(result *bookmarkDto.SavedItemDto) interface{} {
+			wrapper.NoContentResponse()
+			return nil
+		},
+		// Validation failure case
+		func(errors []dto.Error) interface{} {
+			respondWithFailure(wrapper, errors, "Saved item", itemID, "Saved item removal validation failed")
+			return nil
+		},
+	)
+}
+
+// respondWithFailure maps service errors to not found, forbidden, conflict or validation responses
+func respondWithFailure(wrapper *handlers.ContextWrapper, errors []dto.Error, resource, identifier, logMessage string) {
+	switch {
+	case len(errors) > 0 && errors[0].Code == constants.NotFoundError:
+		wrapper.NotFoundErrorResponse(resource, identifier)
+	case len(errors) > 0 && errors[0].Code == constants.ForbiddenError:
+		wrapper.ForbiddenErrorResponse(errors[0].Message)
+	case len(errors) > 0 && errors[0].Code == constants.ConflictError:
+		wrapper.ConflictErrorResponse(errors[0].Message)
+	case len(errors) > 0 && errors[0].Code == constants.UnauthorizedError:
+		wrapper.UnauthorizedErrorResponse(errors[0].Message)
+	default:
+		wrapper.ValidationErrorResponse(errors, "%s", logMessage)
+	}
+}
+
+// scopedService binds the service to the request context when supported,
+// so pins and saved items are resolved for the current user and workspace
+func (h *BookmarkHandler) scopedService(c *gin.Context) service.BookmarkServiceInterface {
+	if aware, ok := h.bookmarkService.(service.ContextAwareBookmarkService); ok {
+		return aware.WithContext(c.Request.Context())
+	}
+	return h.bookmarkService
+}
@@ -0,0 +1,150 @@
+package handlers
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	bookmarkDto "thothix-backend/internal/bookmarks/dto"
+	"thothix-backend/internal/shared/dto"
+)
+
+// MockBookmarkService is a mock implementation of the BookmarkService
+type MockBookmarkService struct {
+	mock.Mock
+}
+
+func (m *MockBookmarkService) ListPins(channelID string) *bookmarkDto.PinListResponse {
+	args := m.Called(channelID)
+	return args.Get(0).(*bookmarkDto.PinListResponse)
+}
+
+func (m *MockBookmarkService) PinMessage(channelID string, req *bookmarkDto.PinMessageRequest) *bookmarkDto.PinResponse {
+	args := m.Called(channelID, req)
+	return args.Get(0).(*bookmarkDto.PinResponse)
+}
+
+func (m *MockBookmarkService) UnpinMessage(channelID, messageID string) *bookmarkDto.PinResponse {
+	args := m.Called(channelID, messageID)
+	return args.Get(0).(*bookmarkDto.PinResponse)
+}
+
+func (m *MockBookmarkService) ListSavedItems(req *bookmarkDto.SavedItemListRequest) *bookmarkDto.SavedItemListResponse {
+	args := m.Called(req)
+	return args.Get(0).(*bookmarkDto.SavedItemListResponse)
+}
+
+func (m *MockBookmarkService) SaveMessage(req *bookmarkDto.SaveMessageRequest) *bookmarkDto.SavedItemResponse {
+	args := m.Called(req)
+	return args.Get(0).(*bookmarkDto.SavedItemResponse)
+}
+
+func (m *MockBookmarkService) UpdateSavedItem(itemID string, req *bookmarkDto.UpdateSavedItemRequest) *bookmarkDto.SavedItemResponse {
+	args := m.Called(itemID, req)
+	return args.Get(0).(*bookmarkDto.SavedItemResponse)
+}
+
+func (m *MockBookmarkService) DeleteSavedItem(itemID string) *bookmarkDto.SavedItemResponse {
+	args := m.Called(itemID)
+	return args.Get(0).(*bookmarkDto.SavedItemResponse)
+}
+
+type BookmarkHandlerTestSuite struct {
+	suite.Suite
+	mockService *MockBookmarkService
+	router      *gin.Engine
+}
+
+func (suite *BookmarkHandlerTestSuite) SetupSuite() {
+	gin.SetMode(gin.TestMode)
+}
+
+func (suite *BookmarkHandlerTestSuite) SetupTest() {
+	suite.mockService = new(MockBookmarkService)
+	handler := NewBookmarkHandler(suite.mockService)
+
+	suite.router = gin.New()
+	suite.router.GET("/channels/:id/pins", handler.ListPins)
+	suite.router.POST("/channels/:id/pins", handler.PinMessage)
+	suite.router.GET("/saved", handler.ListSavedItems)
+	suite.router.PATCH("/saved/:id", handler.UpdateSavedItem)
+}
+
+func (suite *BookmarkHandlerTestSuite) TestListPins_Forbidden() {
+	// Arrange
+	mockResponse := bookmarkDto.NewPinListResponse(func() dto.Validation[[]bookmarkDto.PinDto] {
+		return dto.Failure[[]bookmarkDto.PinDto](dto.NewError("FORBIDDEN", "Access denied to this channel", nil))
+	})
+
+	suite.mockService.On("ListPins", "channel-1").Return(mockResponse)
+
+	// Act
+	req, _ := http.NewRequest("GET", "/channels/channel-1/pins", nil)
+	w := httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(suite.T(), http.StatusForbidden, w.Code)
+}
+
+func (suite *BookmarkHandlerTestSuite) TestPinMessage_LimitReached() {
+	// Arrange
+	mockResponse := bookmarkDto.NewPinResponse(func() dto.Validation[*bookmarkDto.PinDto] {
+		return dto.Invalid[*bookmarkDto.PinDto](dto.NewError("CONFLICT", "A channel can have at most 50 pinned messages", nil))
+	})
+
+	suite.mockService.On("PinMessage", "channel-1", &bookmarkDto.PinMessageRequest{MessageID: "message-1"}).Return(mockResponse)
+
+	// Act
+	req, _ := http.NewRequest("POST", "/channels/channel-1/pins", bytes.NewBufferString(`{"message_id":"message-1"}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(suite.T(), http.StatusConflict, w.Code)
+	assert.Contains(suite.T(), w.Body.String(), "at most 50")
+}
+
+func (suite *BookmarkHandlerTestSuite) TestListSavedItems_DefaultsAndDoneFilter() {
+	// Arrange
+	done := false
+	mockResponse := bookmarkDto.NewSavedItemListResponse(func() dto.Validation[*bookmarkDto.SavedItemListDto] {
+		return dto.Success(dto.NewPaginatedListResponse([]bookmarkDto.SavedItemDto{{ID: "item-1"}}, 1, 1, 20))
+	})
+
+	suite.mockService.On("ListSavedItems", &bookmarkDto.SavedItemListRequest{
+		PaginationRequest: dto.PaginationRequest{Page: 1, PerPage: 20},
+		Done:              &done,
+	}).Return(mockResponse)
+
+	// Act
+	req, _ := http.NewRequest("GET", "/saved?done=false", nil)
+	w := httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(suite.T(), http.StatusOK, w.Code)
+	assert.Contains(suite.T(), w.Body.String(), "item-1")
+}
+
+func (suite *BookmarkHandlerTestSuite) TestUpdateSavedItem_MissingDone() {
+	// Act
+	req, _ := http.NewRequest("PATCH", "/saved/item-1", bytes.NewBufferString(`{}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(suite.T(), http.StatusBadRequest, w.Code)
+	suite.mockService.AssertNotCalled(suite.T(), "UpdateSavedItem", mock.Anything, mock.Anything)
+}
+
+func TestBookmarkHandlerTestSuite(t *testing.T) {
+	suite.Run(t, new(BookmarkHandlerTestSuite))
+}
@@ -0,0 +1,24 @@
+package handlers
+
+import (
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"thothix-backend/internal/bookmarks/service"
+)
+
+// RegisterBookmarkRoutes registers the pinned message and saved item routes of the current workspace
+func RegisterBookmarkRoutes(router *gin.RouterGroup, db *gorm.DB) {
+	bookmarkService := service.NewBookmarkService(db)
+	bookmarkHandler := NewBookmarkHandler(bookmarkService)
+
+	pins := router.Group("/channels/:id/pins")
+	pins.GET("", bookmarkHandler.ListPins)
+	pins.POST("", bookmarkHandler.PinMessage)
+	pins.DELETE("/:messageId", bookmarkHandler.UnpinMessage)
+
+	saved := router.Group("/saved")
+	saved.GET("", bookmarkHandler.ListSavedItems)
+	saved.POST("", bookmarkHandler.SaveMessage)
+	saved.PATCH("/:id", bookmarkHandler.UpdateSavedItem)
+	saved.DELETE("/:id", bookmarkHandler.DeleteSavedItem)
+}
@@ -0,0 +1,84 @@
+package mappers
+
+import (
+	"time"
+
+	"thothix-backend/internal/bookmarks/domain"
+	bookmarkDto "thothix-backend/internal/bookmarks/dto"
+	messageDomain "thothix-backend/internal/message/domain"
+	messageDto "thothix-backend/internal/message/dto"
+)
+
+// BookmarkMapper handles conversion between pins, saved items and their DTOs
+type BookmarkMapper struct{}
+
+// NewBookmarkMapper creates a new BookmarkMapper instance
+func NewBookmarkMapper() *BookmarkMapper {
+	return &BookmarkMapper{}
+}
+
+// PinToDto converts a Pin, with its message loaded, to PinDto
+func (m *BookmarkMapper) PinToDto(pin *domain.Pin) *bookmarkDto.PinDto {
+	if pin == nil {
+		return nil
+	}
+
+	return &bookmarkDto.PinDto{
+		ID:        pin.ID,
+		ChannelID: pin.ChannelID,
+		PinnedBy:  pin.PinnedBy,
+		PinnedAt:  pin.CreatedAt.Format(time.RFC3339),
+		Message:   m.messageToDto(pin.Message),
+	}
+}
+
+// PinsToDtos converts a list of Pins to PinDtos
+func (m *BookmarkMapper) PinsToDtos(pins []domain.Pin) []bookmarkDto.PinDto {
+	result := make([]bookmarkDto.PinDto, 0, len(pins))
+	for i := range pins {
+		result = append(result, *m.PinToDto(&pins[i]))
+	}
+	return result
+}
+
+// SavedItemToDto converts a SavedItem, with its message loaded, to SavedItemDto
+func (m *BookmarkMapper) SavedItemToDto(item *domain.SavedItem) *bookmarkDto.SavedItemDto {
+	if item == nil {
+		return nil
+	}
+
+	result := &bookmarkDto.SavedItemDto{
+		ID:      item.ID,
+		SavedAt: item.CreatedAt.Format(time.RFC3339),
+		Done:    item.IsDone(),
+		Message: m.messageToDto(item.Message),
+	}
+	if item.DoneAt != nil {
+		result.DoneAt = item.DoneAt.Format(time.RFC3339)
+	}
+	return result
+}
+
+// SavedItemsToDtos converts a list of SavedItems to SavedItemDtos
+func (m *BookmarkMapper) SavedItemsToDtos(items []domain.SavedItem) []bookmarkDto.SavedItemDto {
+	result := make([]bookmarkDto.SavedItemDto, 0, len(items))
+	for i := range items {
+		result = append(result, *m.SavedItemToDto(&items[i]))
+	}
+	return result
+}
+
+func (m *BookmarkMapper) messageToDto(message *messageDomain.Message) *messageDto.MessageDto {
+	if message == nil {
+		return nil
+	}
+	return &messageDto.MessageDto{
+		ID:         message.ID,
+		SenderID:   message.SenderID,
+		ChannelID:  message.ChannelID,
+		ReceiverID: message.ReceiverID,
+		Content:    message.Content,
+		CreatedAt:  message.CreatedAt,
+		UpdatedAt:  message.UpdatedAt,
+	}
+}
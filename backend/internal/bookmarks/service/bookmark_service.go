@@ -0,0 +1,373 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+
+	"thothix-backend/internal/bookmarks/domain"
+	bookmarkDto "thothix-backend/internal/bookmarks/dto"
+	"thothix-backend/internal/bookmarks/mappers"
+	messageDomain "thothix-backend/internal/message/domain"
+	"thothix-backend/internal/shared/constants"
+	"thothix-backend/internal/shared/dto"
+	sharedMiddleware "thothix-backend/internal/shared/middleware"
+	sharedModels "thothix-backend/internal/shared/models"
+)
+
+type BookmarkService struct {
+	db     *gorm.DB
+	mapper *mappers.BookmarkMapper
+}
+
+func NewBookmarkService(db *gorm.DB) *BookmarkService {
+	return &BookmarkService{
+		db:     db,
+		mapper: mappers.NewBookmarkMapper(),
+	}
+}
+
+// WithContext returns a copy of the service whose database session carries ctx
+func (s *BookmarkService) WithContext(ctx context.Context) *BookmarkService {
+	return &BookmarkService{
+		db:     s.db.WithContext(ctx),
+		mapper: s.mapper,
+	}
+}
+
+// ListPins lists the pinned messages of a channel, most recently pinned first
+func (s *BookmarkService) ListPins(channelID string) *bookmarkDto.PinListResponse {
+	return bookmarkDto.NewPinListResponse(func() dto.Validation[[]bookmarkDto.PinDto] {
+		userID, ok := sharedMiddleware.UserIDFromContext(s.db.Statement.Context)
+		if !ok {
+			return dto.Failure[[]bookmarkDto.PinDto](dto.NewError(constants.UnauthorizedError, "User not authenticated", nil))
+		}
+		if failure := s.checkChannelAccess(userID, channelID); failure != nil {
+			return dto.Failure[[]bookmarkDto.PinDto](*failure)
+		}
+
+		var pins []domain.Pin
+		if err := s.db.Preload("Message").Where("channel_id = ?", channelID).Order("created_at DESC").Find(&pins).Error; err != nil {
+			panic(err)
+		}
+		return dto.Success(s.mapper.PinsToDtos(pins))
+	})
+}
+
+// PinMessage pins a message of the channel
+// Channel managers can pin any message and authors their own, up to MaxPinsPerChannel per channel
+func (s *BookmarkService) PinMessage(channelID string, req *bookmarkDto.PinMessageRequest) *bookmarkDto.PinResponse {
+	return bookmarkDto.NewPinResponse(func() dto.Validation[*bookmarkDto.PinDto] {
+		if req == nil || req.MessageID == "" {
+			return dto.Failure[*bookmarkDto.PinDto](dto.NewError(constants.ValidationError, "Message ID is required", nil))
+		}
+
+		userID, ok := sharedMiddleware.UserIDFromContext(s.db.Statement.Context)
+		if !ok {
+			return dto.Failure[*bookmarkDto.PinDto](dto.NewError(constants.UnauthorizedError, "User not authenticated", nil))
+		}
+		message, failure := s.channelMessage(userID, channelID, req.MessageID)
+		if failure != nil {
+			return dto.Failure[*bookmarkDto.PinDto](*failure)
+		}
+		if !s.canManagePins(userID, channelID, message) {
+			return dto.Failure[*bookmarkDto.PinDto](dto.NewError(constants.ForbiddenError, "Only channel managers and the message author can pin it", nil))
+		}
+
+		pin := domain.Pin{ChannelID: channelID, MessageID: message.ID, PinnedBy: userID}
+		var conflict *dto.Error
+		err := s.db.Transaction(func(tx *gorm.DB) error {
+			// Lock the channel so concurrent pins cannot exceed the limit
+			if err := tx.Exec("SELECT id FROM channels WHERE id = ? FOR UPDATE", channelID).Error; err != nil {
+				return err
+			}
+
+			var pinned int64
+			if err := tx.Model(&domain.Pin{}).Where("channel_id = ?", channelID).Count(&pinned).Error; err != nil {
+				return err
+			}
+			var existing int64
+			if err := tx.Model(&domain.Pin{}).Where("message_id = ?", message.ID).Count(&existing).Error; err != nil {
+				return err
+			}
+			switch {
+			case existing > 0:
+				err := dto.NewError(constants.ConflictError, "Message is already pinned", nil)
+				conflict = &err
+				return nil
+			case pinned >= domain.MaxPinsPerChannel:
+				err := dto.NewError(constants.ConflictError, fmt.Sprintf("A channel can have at most %d pinned messages", domain.MaxPinsPerChannel), nil)
+				conflict = &err
+				return nil
+			}
+			return tx.Create(&pin).Error
+		})
+		if err != nil {
+			panic(err)
+		}
+		if conflict != nil {
+			return dto.Invalid[*bookmarkDto.PinDto](*conflict)
+		}
+
+		pin.Message = message
+		return dto.Success(s.mapper.PinToDto(&pin))
+	})
+}
+
+// UnpinMessage removes a pin from the channel, with the same rules as PinMessage
+func (s *BookmarkService) UnpinMessage(channelID, messageID string) *bookmarkDto.PinResponse {
+	return bookmarkDto.NewPinResponse(func() dto.Validation[*bookmarkDto.PinDto] {
+		userID, ok := sharedMiddleware.UserIDFromContext(s.db.Statement.Context)
+		if !ok {
+			return dto.Failure[*bookmarkDto.PinDto](dto.NewError(constants.UnauthorizedError, "User not authenticated", nil))
+		}
+		message, failure := s.channelMessage(userID, channelID, messageID)
+		if failure != nil {
+			return dto.Failure[*bookmarkDto.PinDto](*failure)
+		}
+
+		var pin domain.Pin
+		if err := s.db.Where("channel_id = ? AND message_id = ?", channelID, message.ID).First(&pin).Error; err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return dto.Invalid[*bookmarkDto.PinDto](dto.NewError(constants.NotFoundError, "Message is not pinned", nil))
+			}
+			panic(err)
+		}
+		if !s.canManagePins(userID, channelID, message) {
+			return dto.Failure[*bookmarkDto.PinDto](dto.NewError(constants.ForbiddenError, "Only channel managers and the message author can unpin it", nil))
+		}
+
+		if err := s.db.Delete(&pin).Error; err != nil {
+			panic(err)
+		}
+		pin.Message = message
+		return dto.Success(s.mapper.PinToDto(&pin))
+	})
+}
+
+// ListSavedItems lists the current user's saved items, most recently saved first
+// Items whose message the user can no longer read, such as after leaving a private channel, are left out
+func (s *BookmarkService) ListSavedItems(req *bookmarkDto.SavedItemListRequest) *bookmarkDto.SavedItemListResponse {
+	return bookmarkDto.NewSavedItemListResponse(func() dto.Validation[*bookmarkDto.SavedItemListDto] {
+		if req == nil || req.Page < 1 || req.PerPage < 1 || req.PerPage > 100 {
+			return dto.Failure[*bookmarkDto.SavedItemListDto](dto.NewError(constants.ValidationError, "Page must be positive and per page between 1 and 100", nil))
+		}
+
+		userID, ok := sharedMiddleware.UserIDFromContext(s.db.Statement.Context)
+		if !ok {
+			return dto.Failure[*bookmarkDto.SavedItemListDto](dto.NewError(constants.UnauthorizedError, "User not authenticated", nil))
+		}
+
+		query := s.db.Model(&domain.SavedItem{}).
+			Where("user_id = ?", userID).
+			Where("message_id IN (?)", s.readableMessages(userID))
+		if req.Done != nil {
+			if *req.Done {
+				query = query.Where("done_at IS NOT NULL")
+			} else {
+				query = query.Where("done_at IS NULL")
+			}
+		}
+
+		var total int64
+		if err := query.Count(&total).Error; err != nil {
+			panic(err)
+		}
+		var items []domain.SavedItem
+		if err := query.Preload("Message").Order("created_at DESC").
+			Offset((req.Page - 1) * req.PerPage).Limit(req.PerPage).Find(&items).Error; err != nil {
+			panic(err)
+		}
+
+		return dto.Success(dto.NewPaginatedListResponse(s.mapper.SavedItemsToDtos(items), total, req.Page, req.PerPage))
+	})
+}
+
+// SaveMessage saves a message the current user can read; saving it again returns the existing item
+func (s *BookmarkService) SaveMessage(req *bookmarkDto.SaveMessageRequest) *bookmarkDto.SavedItemResponse {
+	return bookmarkDto.NewSavedItemResponse(func() dto.Validation[*bookmarkDto.SavedItemDto] {
+		if req == nil || req.MessageID == "" {
+			return dto.Failure[*bookmarkDto.SavedItemDto](dto.NewError(constants.ValidationError, "Message ID is required", nil))
+		}
+
+		userID, ok := sharedMiddleware.UserIDFromContext(s.db.Statement.Context)
+		if !ok {
+			return dto.Failure[*bookmarkDto.SavedItemDto](dto.NewError(constants.UnauthorizedError, "User not authenticated", nil))
+		}
+		message, failure := s.readableMessage(userID, req.MessageID)
+		if failure != nil {
+			return dto.Failure[*bookmarkDto.SavedItemDto](*failure)
+		}
+
+		item := domain.SavedItem{UserID: userID, MessageID: message.ID}
+		if err := s.db.Where(&item).FirstOrCreate(&item).Error; err != nil {
+			panic(err)
+		}
+		item.Message = message
+		return dto.Success(s.mapper.SavedItemToDto(&item))
+	})
+}
+
+// UpdateSavedItem marks a saved item of the current user done or not done
+func (s *BookmarkService) UpdateSavedItem(itemID string, req *bookmarkDto.UpdateSavedItemRequest) *bookmarkDto.SavedItemResponse {
+	return bookmarkDto.NewSavedItemResponse(func() dto.Validation[*bookmarkDto.SavedItemDto] {
+		if req == nil || req.Done == nil {
+			return dto.Failure[*bookmarkDto.SavedItemDto](dto.NewError(constants.ValidationError, "Done is required", nil))
+		}
+
+		userID, ok := sharedMiddleware.UserIDFromContext(s.db.Statement.Context)
+		if !ok {
+			return dto.Failure[*bookmarkDto.SavedItemDto](dto.NewError(constants.UnauthorizedError, "User not authenticated", nil))
+		}
+		item, failure := s.savedItem(userID, itemID)
+		if failure != nil {
+			return dto.Failure[*bookmarkDto.SavedItemDto](*failure)
+		}
+		message, failure := s.readableMessage(userID, item.MessageID)
+		if failure != nil {
+			return dto.Failure[*bookmarkDto.SavedItemDto](dto.NewError(constants.NotFoundError, "Saved item not found", nil))
+		}
+
+		switch {
+		case *req.Done && item.DoneAt == nil:
+			now := time.Now()
+			item.DoneAt = &now
+		case !*req.Done:
+			item.DoneAt = nil
+		}
+		if err := s.db.Model(item).Update("done_at", item.DoneAt).Error; err != nil {
+			panic(err)
+		}
+		item.Message = message
+		return dto.Success(s.mapper.SavedItemToDto(item))
+	})
+}
+
+// DeleteSavedItem removes a saved item of the current user
+// Unlike the other operations it works without access to the message, so users can clean up stale items
+func (s *BookmarkService) DeleteSavedItem(itemID string) *bookmarkDto.SavedItemResponse {
+	return bookmarkDto.NewSavedItemResponse(func() dto.Validation[*bookmarkDto.SavedItemDto] {
+		userID, ok := sharedMiddleware.UserIDFromContext(s.db.Statement.Context)
+		if !ok {
+			return dto.Failure[*bookmarkDto.SavedItemDto](dto.NewError(constants.UnauthorizedError, "User not authenticated", nil))
+		}
+		item, failure := s.savedItem(userID, itemID)
+		if failure != nil {
+			return dto.Failure[*bookmarkDto.SavedItemDto](*failure)
+		}
+
+		if err := s.db.Delete(item).Error; err != nil {
+			panic(err)
+		}
+		return dto.Success(s.mapper.SavedItemToDto(item))
+	})
+}
+
+// checkChannelAccess reports channels outside the current workspace as not found and channels the user
+// cannot read, such as a private channel they were removed from, as forbidden
+func (s *BookmarkService) checkChannelAccess(userID, channelID string) *dto.Error {
+	var count int64
+	if err := s.db.Table("channels").Scopes(sharedModels.InWorkspace("channels")).Where("id::text = ?", channelID).Count(&count).Error; err != nil {
+		panic(err)
+	}
+	if count == 0 {
+		err := dto.NewError(constants.NotFoundError, "Channel not found", nil)
+		return &err
+	}
+	if !s.canReadChannel(userID, channelID) {
+		err := dto.NewError(constants.ForbiddenError, "Access denied to this channel", nil)
+		return &err
+	}
+	return nil
+}
+
+// channelMessage loads a message of a channel the user can read
+func (s *BookmarkService) channelMessage(userID, channelID, messageID string) (*messageDomain.Message, *dto.Error) {
+	if failure := s.checkChannelAccess(userID, channelID); failure != nil {
+		return nil, failure
+	}
+
+	var message messageDomain.Message
+	if err := s.db.Where("id::text = ? AND channel_id = ?", messageID, channelID).First(&message).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			err := dto.NewError(constants.NotFoundError, "Message not found", nil)
+			return nil, &err
+		}
+		panic(err)
+	}
+	return &message, nil
+}
+
+// readableMessage loads a message the user can read: one in a channel they have access to, or a direct
+// message they sent or received. Other messages are reported as not found so their existence is not revealed
+func (s *BookmarkService) readableMessage(userID, messageID string) (*messageDomain.Message, *dto.Error) {
+	notFound := dto.NewError(constants.NotFoundError, "Message not found", nil)
+
+	var message messageDomain.Message
+	if err := s.db.Where("id::text = ?", messageID).First(&message).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, &notFound
+		}
+		panic(err)
+	}
+
+	if message.ChannelID != nil {
+		if !s.canReadChannel(userID, *message.ChannelID) {
+			return nil, &notFound
+		}
+	} else if message.SenderID != userID && (message.ReceiverID == nil || *message.ReceiverID != userID) {
+		return nil, &notFound
+	}
+	return &message, nil
+}
+
+// readableMessages is a subquery of the IDs of the messages saved by the user that they can still read
+// Channel access is checked once per channel the user saved messages from
+func (s *BookmarkService) readableMessages(userID string) *gorm.DB {
+	var channelIDs []string
+	if err := s.db.Model(&messageDomain.Message{}).Distinct("channel_id").
+		Where("channel_id IS NOT NULL AND id IN (?)", s.db.Model(&domain.SavedItem{}).Select("message_id").Where("user_id = ?", userID)).
+		Pluck("channel_id", &channelIDs).Error; err != nil {
+		panic(err)
+	}
+
+	readable := make([]string, 0, len(channelIDs))
+	for _, channelID := range channelIDs {
+		if s.canReadChannel(userID, channelID) {
+			readable = append(readable, channelID)
+		}
+	}
+
+	return s.db.Model(&messageDomain.Message{}).Select("id").Where(
+		"channel_id IN ? OR (channel_id IS NULL AND (sender_id = ? OR receiver_id = ?))", readable, userID, userID,
+	)
+}
+
+// savedItem loads a saved item of the user
+func (s *BookmarkService) savedItem(userID, itemID string) (*domain.SavedItem, *dto.Error) {
+	var item domain.SavedItem
+	if err := s.db.Where("id::text = ? AND user_id = ?", itemID, userID).First(&item).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			err := dto.NewError(constants.NotFoundError, "Saved item not found", nil)
+			return nil, &err
+		}
+		panic(err)
+	}
+	return &item, nil
+}
+
+func (s *BookmarkService) canReadChannel(userID, channelID string) bool {
+	resourceType := "channel"
+	return sharedModels.HasUserPermission(s.db, userID, sharedModels.PermissionChannelRead, &resourceType, &channelID)
+}
+
+// canManagePins allows the message author and users who can manage the channel
+func (s *BookmarkService) canManagePins(userID, channelID string, message *messageDomain.Message) bool {
+	if message.SenderID == userID {
+		return true
+	}
+	resourceType := "channel"
+	return sharedModels.HasUserPermission(s.db, userID, sharedModels.PermissionChannelManage, &resourceType, &channelID)
+}
@@ -0,0 +1,23 @@
+package service
+
+import (
+	"context"
+
+	bookmarkDto "thothix-backend/internal/bookmarks/dto"
+)
+
+// BookmarkServiceInterface defines the contract for pinned messages and saved items using Response pattern
+type BookmarkServiceInterface interface {
+	ListPins(channelID string) *bookmarkDto.PinListResponse
+	PinMessage(channelID string, req *bookmarkDto.PinMessageRequest) *bookmarkDto.PinResponse
+	UnpinMessage(channelID, messageID string) *bookmarkDto.PinResponse
+	ListSavedItems(req *bookmarkDto.SavedItemListRequest) *bookmarkDto.SavedItemListResponse
+	SaveMessage(req *bookmarkDto.SaveMessageRequest) *bookmarkDto.SavedItemResponse
+	UpdateSavedItem(itemID string, req *bookmarkDto.UpdateSavedItemRequest) *bookmarkDto.SavedItemResponse
+	DeleteSavedItem(itemID string) *bookmarkDto.SavedItemResponse
+}
+
+// ContextAwareBookmarkService is implemented by services that can bind their database session to a request context
+type ContextAwareBookmarkService interface {
+	WithContext(ctx context.Context) *BookmarkService
+}
@@ -0,0 +1,168 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/gorm"
+	"thothix-backend/internal/bookmarks/domain"
+	bookmarkDto "thothix-backend/internal/bookmarks/dto"
+	chatDomain "thothix-backend/internal/chat/domain"
+	messageDomain "thothix-backend/internal/message/domain"
+	"thothix-backend/internal/shared/dto"
+	sharedMiddleware "thothix-backend/internal/shared/middleware"
+	sharedModels "thothix-backend/internal/shared/models"
+	sharedTesting "thothix-backend/internal/shared/testing"
+	usersDomain "thothix-backend/internal/users/domain"
+	workspaceDomain "thothix-backend/internal/workspace/domain"
+)
+
+type BookmarkServiceTestSuite struct {
+	suite.Suite
+	container *sharedTesting.PostgresTestContainer
+}
+
+func (suite *BookmarkServiceTestSuite) SetupSuite() {
+	suite.container = sharedTesting.GetSharedTestContainer(
+		suite.T(),
+		"bookmarks/service",
+		[]interface{}{
+			&usersDomain.User{}, &sharedModels.UserRole{}, &workspaceDomain.Workspace{},
+			&chatDomain.Channel{}, &chatDomain.ChannelMember{}, &messageDomain.Message{},
+			&domain.Pin{}, &domain.SavedItem{},
+		},
+	)
+}
+
+// fixture is a workspace with a private channel, a member of it and a message the member posted there
+type fixture struct {
+	workspace *workspaceDomain.Workspace
+	channel   *chatDomain.Channel
+	member    *usersDomain.User
+	message   *messageDomain.Message
+}
+
+func (suite *BookmarkServiceTestSuite) createFixture(db *gorm.DB, slug string) *fixture {
+	workspace := &workspaceDomain.Workspace{Name: slug, Slug: slug}
+	assert.NoError(suite.T(), db.Create(workspace).Error)
+
+	channel := &chatDomain.Channel{WorkspaceID: workspace.ID, Name: "private", ProjectID: uuid.New().String()}
+	assert.NoError(suite.T(), db.Create(channel).Error)
+
+	member := suite.createMember(db, slug+"-member", workspace, sharedModels.RoleUser)
+	assert.NoError(suite.T(), db.Create(&chatDomain.ChannelMember{ChannelID: channel.ID, UserID: member.ID}).Error)
+
+	message := &messageDomain.Message{SenderID: member.ID, ChannelID: &channel.ID, Content: "release notes"}
+	assert.NoError(suite.T(), db.Create(message).Error)
+
+	return &fixture{workspace: workspace, channel: channel, member: member, message: message}
+}
+
+// createMember stores a user with the given role in workspace
+func (suite *BookmarkServiceTestSuite) createMember(db *gorm.DB, name string, workspace *workspaceDomain.Workspace, role sharedModels.RoleType) *usersDomain.User {
+	user := &usersDomain.User{Email: name + "@example.com", Name: name, SystemRole: sharedModels.RoleUser}
+	user.ID = uuid.New().String()
+	assert.NoError(suite.T(), db.Create(user).Error)
+
+	resourceType := sharedModels.ResourceTypeWorkspace
+	assert.NoError(suite.T(), db.Create(&sharedModels.UserRole{
+		UserID: user.ID, Role: role, ResourceType: &resourceType, ResourceID: &workspace.ID,
+	}).Error)
+	return user
+}
+
+// serviceFor creates a service acting as the given user in workspace
+func (suite *BookmarkServiceTestSuite) serviceFor(db *gorm.DB, userID string, workspace *workspaceDomain.Workspace) *BookmarkService {
+	ctx := sharedMiddleware.WithWorkspaceID(sharedMiddleware.WithUserID(context.Background(), userID), workspace.ID)
+	return NewBookmarkService(db).WithContext(ctx)
+}
+
+func (suite *BookmarkServiceTestSuite) TestPinMessage_AuthorAndManagerOnly() {
+	suite.container.WithTransaction(func(db *gorm.DB) {
+		// Arrange
+		f := suite.createFixture(db, "pins-rules")
+		other := suite.createMember(db, "pins-rules-other", f.workspace, sharedModels.RoleUser)
+		assert.NoError(suite.T(), db.Create(&chatDomain.ChannelMember{ChannelID: f.channel.ID, UserID: other.ID}).Error)
+		manager := suite.createMember(db, "pins-rules-manager", f.workspace, sharedModels.RoleManager)
+		request := &bookmarkDto.PinMessageRequest{MessageID: f.message.ID}
+
+		// Act
+		byOther := suite.serviceFor(db, other.ID, f.workspace).PinMessage(f.channel.ID, request)
+		byManager := suite.serviceFor(db, manager.ID, f.workspace).PinMessage(f.channel.ID, request)
+		again := suite.serviceFor(db, f.member.ID, f.workspace).PinMessage(f.channel.ID, request)
+
+		// Assert
+		sharedTesting.AssertValidationErrorWithCode(suite.T(), byOther.Response, "FORBIDDEN")
+		pin := sharedTesting.AssertSuccessWithValue(suite.T(), byManager.Response)
+		assert.Equal(suite.T(), manager.ID, pin.PinnedBy)
+		assert.Equal(suite.T(), "release notes", pin.Message.Content)
+		sharedTesting.AssertValidationErrorWithCode(suite.T(), again.Response, "CONFLICT")
+	})
+}
+
+func (suite *BookmarkServiceTestSuite) TestPinMessage_LimitPerChannel() {
+	suite.container.WithTransaction(func(db *gorm.DB) {
+		// Arrange
+		f := suite.createFixture(db, "pins-limit")
+		for i := 0; i < domain.MaxPinsPerChannel; i++ {
+			message := &messageDomain.Message{SenderID: f.member.ID, ChannelID: &f.channel.ID, Content: "pinned"}
+			assert.NoError(suite.T(), db.Create(message).Error)
+			assert.NoError(suite.T(), db.Create(&domain.Pin{ChannelID: f.channel.ID, MessageID: message.ID, PinnedBy: f.member.ID}).Error)
+		}
+
+		// Act
+		response := suite.serviceFor(db, f.member.ID, f.workspace).PinMessage(f.channel.ID, &bookmarkDto.PinMessageRequest{MessageID: f.message.ID})
+
+		// Assert
+		sharedTesting.AssertValidationErrorWithCode(suite.T(), response.Response, "CONFLICT")
+	})
+}
+
+func (suite *BookmarkServiceTestSuite) TestRemovedMember_LosesPinsAndSaves() {
+	suite.container.WithTransaction(func(db *gorm.DB) {
+		// Arrange
+		f := suite.createFixture(db, "saves-removed")
+		service := suite.serviceFor(db, f.member.ID, f.workspace)
+		sharedTesting.AssertSuccessWithValue(suite.T(), service.PinMessage(f.channel.ID, &bookmarkDto.PinMessageRequest{MessageID: f.message.ID}).Response)
+		item := sharedTesting.AssertSuccessWithValue(suite.T(), service.SaveMessage(&bookmarkDto.SaveMessageRequest{MessageID: f.message.ID}).Response)
+		page := dto.PaginationRequest{Page: 1, PerPage: 20}
+		sharedTesting.AssertPaginatedCount(suite.T(), service.ListSavedItems(&bookmarkDto.SavedItemListRequest{PaginationRequest: page}).Response, 1)
+
+		// Act
+		assert.NoError(suite.T(), db.Where("channel_id = ? AND user_id = ?", f.channel.ID, f.member.ID).Delete(&chatDomain.ChannelMember{}).Error)
+
+		// Assert
+		sharedTesting.AssertValidationErrorWithCode(suite.T(), service.ListPins(f.channel.ID).Response, "FORBIDDEN")
+		sharedTesting.AssertPaginatedCount(suite.T(), service.ListSavedItems(&bookmarkDto.SavedItemListRequest{PaginationRequest: page}).Response, 0)
+		done := true
+		sharedTesting.AssertValidationErrorWithCode(suite.T(), service.UpdateSavedItem(item.ID, &bookmarkDto.UpdateSavedItemRequest{Done: &done}).Response, "NOT_FOUND")
+		sharedTesting.AssertSuccessWithValue(suite.T(), service.DeleteSavedItem(item.ID).Response)
+	})
+}
+
+func (suite *BookmarkServiceTestSuite) TestSavedItems_DoneFilter() {
+	suite.container.WithTransaction(func(db *gorm.DB) {
+		// Arrange
+		f := suite.createFixture(db, "saves-done")
+		service := suite.serviceFor(db, f.member.ID, f.workspace)
+		item := sharedTesting.AssertSuccessWithValue(suite.T(), service.SaveMessage(&bookmarkDto.SaveMessageRequest{MessageID: f.message.ID}).Response)
+		done := true
+
+		// Act
+		updated := sharedTesting.AssertSuccessWithValue(suite.T(), service.UpdateSavedItem(item.ID, &bookmarkDto.UpdateSavedItemRequest{Done: &done}).Response)
+
+		// Assert
+		assert.True(suite.T(), updated.Done)
+		page := dto.PaginationRequest{Page: 1, PerPage: 20}
+		open := false
+		sharedTesting.AssertPaginatedCount(suite.T(), service.ListSavedItems(&bookmarkDto.SavedItemListRequest{PaginationRequest: page, Done: &open}).Response, 0)
+		sharedTesting.AssertPaginatedCount(suite.T(), service.ListSavedItems(&bookmarkDto.SavedItemListRequest{PaginationRequest: page, Done: &done}).Response, 1)
+	})
+}
+
+func TestBookmarkServiceTestSuite(t *testing.T) {
+	suite.Run(t, new(BookmarkServiceTestSuite))
+}
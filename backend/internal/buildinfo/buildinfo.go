@@ -0,0 +1,10 @@
+// Package buildinfo holds version metadata injected at build time, for example:
+//
+//	go build -ldflags "-X thothix-backend/internal/buildinfo.Version=1.4.0 -X thothix-backend/internal/buildinfo.Commit=$(git rev-parse --short HEAD)"
+package buildinfo
+
+var (
+	Version   = "dev"     // Release version
+	Commit    = "unknown" // Git commit the binary was built from
+	BuildTime = "unknown" // Build timestamp in RFC 3339
+)
@@ -7,9 +7,13 @@ import (
 // Channel represents a chat channel in the chat domain
 type Channel struct {
 	commonModels.BaseModel
-	Name      string `json:"name"`
-	ProjectID string `json:"project_id"`
-	IsPrivate bool   `json:"is_private" gorm:"-"` // Computed field, not stored in DB
+	WorkspaceID string `json:"workspace_id" gorm:"index"`
+	Name        string `json:"name"`
+	ProjectID   string `json:"project_id"`
+	Topic       string `json:"topic"`
+	IsPrivate   bool   `json:"is_private" gorm:"-"` // Computed field, not stored in DB
+	// LinkPreviewsDisabled stops links posted in the channel from being unfurled; set by channel managers
+	LinkPreviewsDisabled bool `json:"link_previews_disabled" gorm:"not null;default:false"`
 }
 
 // LoadIsPrivate calculates and sets the IsPrivate field based on channel members
@@ -7,6 +7,7 @@ type ChannelDto struct {
 	ID        string    `json:"id"`
 	Name      string    `json:"name"`
 	ProjectID string    `json:"project_id"`
+	Topic     string    `json:"topic"`
 	IsPrivate bool      `json:"is_private"`
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
@@ -21,5 +22,11 @@ type ChannelCreateRequest struct {
 
 // ChannelUpdateRequest represents a request to update a channel
 type ChannelUpdateRequest struct {
-	Name *string `json:"name,omitempty"`
+	Name  *string `json:"name,omitempty"`
+	Topic *string `json:"topic,omitempty"`
+}
+
+// ChannelLinkPreviewsRequest turns the link previews of a channel on or off
+type ChannelLinkPreviewsRequest struct {
+	Enabled *bool `json:"enabled" binding:"required"`
 }
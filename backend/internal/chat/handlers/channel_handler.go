@@ -1,12 +1,15 @@
 package handlers
 
 import (
-	"log"
+	"log/slog"
 	"net/http"
 
 	chatDomain "thothix-backend/internal/chat/domain"
 	chatDto "thothix-backend/internal/chat/dto"
+	"thothix-backend/internal/metrics"
 	projectDomain "thothix-backend/internal/project/domain"
+	"thothix-backend/internal/shared/logging"
+	sharedMiddleware "thothix-backend/internal/shared/middleware"
 	sharedModels "thothix-backend/internal/shared/models"
 
 	"github.com/gin-gonic/gin"
@@ -32,6 +35,8 @@ func NewChannelHandler(db *gorm.DB) *ChannelHandler {
 // @Failure 500 {object} map[string]interface{}
 // @Router /api/v1/channels [get]
 func (h *ChannelHandler) GetChats(c *gin.Context) {
+	db := h.db.WithContext(c.Request.Context())
+
 	userID, exists := c.Get("clerk_user_id")
 	if !exists {
 		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
@@ -39,18 +44,20 @@ func (h *ChannelHandler) GetChats(c *gin.Context) {
 	}
 
 	// Get user's system role
-	userRole, err := sharedModels.GetUserRole(h.db, userID.(string))
+	userRole, err := sharedModels.GetUserRole(db, userID.(string))
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get user role"})
 		return
 	}
 
+	workspaceID, _ := sharedMiddleware.WorkspaceIDFromContext(c.Request.Context())
+
 	var channels []chatDomain.Channel
 
 	// Admins and managers can see all channels
 	switch userRole {
 	case sharedModels.RoleAdmin, sharedModels.RoleManager:
-		if err := h.db.Preload("Project").Find(&channels).Error; err != nil {
+		if err := db.Preload("Project").Scopes(sharedModels.InWorkspace("channels")).Find(&channels).Error; err != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get channels"})
 			return
 		}
@@ -59,9 +66,9 @@ func (h *ChannelHandler) GetChats(c *gin.Context) {
 		query := `
 			SELECT c.* FROM channels c
 			LEFT JOIN channel_members cm ON c.id = cm.channel_id
-			WHERE cm.channel_id IS NULL
+			WHERE cm.channel_id IS NULL AND (? = '' OR c.workspace_id = ?)
 		`
-		if err := h.db.Preload("Project").Raw(query).Scan(&channels).Error; err != nil {
+		if err := db.Preload("Project").Raw(query, workspaceID, workspaceID).Scan(&channels).Error; err != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get channels"})
 			return
 		}
@@ -71,9 +78,9 @@ func (h *ChannelHandler) GetChats(c *gin.Context) {
 			SELECT DISTINCT c.* FROM channels c
 			LEFT JOIN channel_members cm1 ON c.id = cm1.channel_id AND cm1.user_id = ?
 			LEFT JOIN channel_members cm2 ON c.id = cm2.channel_id
-			WHERE cm2.channel_id IS NULL OR cm1.user_id IS NOT NULL
+			WHERE (cm2.channel_id IS NULL OR cm1.user_id IS NOT NULL) AND (? = '' OR c.workspace_id = ?)
 		`
-		if err := h.db.Preload("Project").Raw(query, userID).Scan(&channels).Error; err != nil {
+		if err := db.Preload("Project").Raw(query, userID, workspaceID, workspaceID).Scan(&channels).Error; err != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get channels"})
 			return
 		}
@@ -81,8 +88,8 @@ func (h *ChannelHandler) GetChats(c *gin.Context) {
 
 	// Load IsPrivate field for each channel
 	for i := range channels {
-		if err := channels[i].LoadIsPrivate(h.db); err != nil {
-			log.Printf("Error loading IsPrivate for channel %s: %v", channels[i].ID, err)
+		if err := channels[i].LoadIsPrivate(db); err != nil {
+			logging.FromContext(c.Request.Context()).Warn("Error loading IsPrivate for channel", slog.String("channel_id", channels[i].ID), slog.Any("error", err))
 		}
 	}
 
@@ -102,6 +109,8 @@ func (h *ChannelHandler) GetChats(c *gin.Context) {
 // @Failure 403 {object} map[string]interface{}
 // @Router /api/v1/channels [post]
 func (h *ChannelHandler) CreateChat(c *gin.Context) {
+	db := h.db.WithContext(c.Request.Context())
+
 	userID, exists := c.Get("clerk_user_id")
 	if !exists {
 		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
@@ -115,39 +124,41 @@ func (h *ChannelHandler) CreateChat(c *gin.Context) {
 	}
 
 	// Check if user has permission to create channels
-	if !sharedModels.HasUserPermission(h.db, userID.(string), sharedModels.PermissionChannelCreate, nil, nil) {
+	if !sharedModels.HasUserPermission(db, userID.(string), sharedModels.PermissionChannelCreate, nil, nil) {
 		c.JSON(http.StatusForbidden, gin.H{"error": "Insufficient permissions to create channels"})
 		return
 	}
 
 	// Verify project exists and user has access
 	var project projectDomain.Project
-	if err := h.db.Where("id = ?", req.ProjectID).First(&project).Error; err != nil {
+	if err := db.Scopes(sharedModels.InWorkspace("projects")).Where("id = ?", req.ProjectID).First(&project).Error; err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Project not found"})
 		return
 	}
 
 	resourceType := "project"
-	if !sharedModels.HasUserPermission(h.db, userID.(string), sharedModels.PermissionProjectRead, &resourceType, &req.ProjectID) {
+	if !sharedModels.HasUserPermission(db, userID.(string), sharedModels.PermissionProjectRead, &resourceType, &req.ProjectID) {
 		c.JSON(http.StatusForbidden, gin.H{"error": "Access denied to project"})
 		return
 	}
 
 	// Create channel
 	channel := chatDomain.Channel{
-		Name:      req.Name,
-		ProjectID: req.ProjectID,
+		WorkspaceID: project.WorkspaceID,
+		Name:        req.Name,
+		ProjectID:   req.ProjectID,
 	}
 
-	if err := h.db.Create(&channel).Error; err != nil {
+	if err := db.Create(&channel).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create channel"})
 		return
 	}
+	metrics.ChannelsCreated.Inc()
 
 	// Load project relation and IsPrivate field for response
-	h.db.Preload("Project").First(&channel, channel.ID)
-	if err := channel.LoadIsPrivate(h.db); err != nil {
-		log.Printf("Error loading IsPrivate for channel %s: %v", channel.ID, err)
+	db.Preload("Project").First(&channel, channel.ID)
+	if err := channel.LoadIsPrivate(db); err != nil {
+		logging.FromContext(c.Request.Context()).Warn("Error loading IsPrivate for channel", slog.String("channel_id", channel.ID), slog.Any("error", err))
 	}
 
 	c.JSON(http.StatusCreated, channel)
@@ -166,6 +177,8 @@ func (h *ChannelHandler) CreateChat(c *gin.Context) {
 // @Failure 403 {object} map[string]interface{}
 // @Router /api/v1/channels/{id} [get]
 func (h *ChannelHandler) GetChat(c *gin.Context) {
+	db := h.db.WithContext(c.Request.Context())
+
 	userID, exists := c.Get("clerk_user_id")
 	if !exists {
 		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
@@ -176,13 +189,13 @@ func (h *ChannelHandler) GetChat(c *gin.Context) {
 
 	// Check if user has access to this channel
 	resourceType := "channel"
-	if !sharedModels.HasUserPermission(h.db, userID.(string), sharedModels.PermissionChannelRead, &resourceType, &channelID) {
+	if !sharedModels.HasUserPermission(db, userID.(string), sharedModels.PermissionChannelRead, &resourceType, &channelID) {
 		c.JSON(http.StatusForbidden, gin.H{"error": "Access denied to channel"})
 		return
 	}
 
 	var channel chatDomain.Channel
-	if err := h.db.Preload("Project").Where("id = ?", channelID).First(&channel).Error; err != nil {
+	if err := db.Preload("Project").Scopes(sharedModels.InWorkspace("channels")).Where("id = ?", channelID).First(&channel).Error; err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Channel not found"})
 		return
 	}
@@ -203,6 +216,8 @@ func (h *ChannelHandler) GetChat(c *gin.Context) {
 // @Failure 403 {object} map[string]interface{}
 // @Router /api/v1/channels/{id}/join [post]
 func (h *ChannelHandler) JoinChannel(c *gin.Context) {
+	db := h.db.WithContext(c.Request.Context())
+
 	userID, exists := c.Get("clerk_user_id")
 	if !exists {
 		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
@@ -213,13 +228,13 @@ func (h *ChannelHandler) JoinChannel(c *gin.Context) {
 
 	// Get channel info
 	var channel chatDomain.Channel
-	if err := h.db.Where("id = ?", channelID).First(&channel).Error; err != nil {
+	if err := db.Scopes(sharedModels.InWorkspace("channels")).Where("id = ?", channelID).First(&channel).Error; err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Channel not found"})
 		return
 	}
 
 	// Get user role
-	userRole, err := sharedModels.GetUserRole(h.db, userID.(string))
+	userRole, err := sharedModels.GetUserRole(db, userID.(string))
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get user role"})
 		return
@@ -238,7 +253,7 @@ func (h *ChannelHandler) JoinChannel(c *gin.Context) {
 	case userRole == sharedModels.RoleUser:
 		// Regular users can join any public channel if they have project access
 		resourceType := "project"
-		if !sharedModels.HasUserPermission(h.db, userID.(string), sharedModels.PermissionProjectRead, &resourceType, &channel.ProjectID) {
+		if !sharedModels.HasUserPermission(db, userID.(string), sharedModels.PermissionProjectRead, &resourceType, &channel.ProjectID) {
 			c.JSON(http.StatusForbidden, gin.H{"error": "Access denied to project"})
 			return
 		}
@@ -246,7 +261,7 @@ func (h *ChannelHandler) JoinChannel(c *gin.Context) {
 
 	// Check if already a member
 	var existingMember chatDomain.ChannelMember
-	if err := h.db.Where("channel_id = ? AND user_id = ?", channelID, userID).First(&existingMember).Error; err == nil {
+	if err := db.Where("channel_id = ? AND user_id = ?", channelID, userID).First(&existingMember).Error; err == nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Already a member of this channel"})
 		return
 	}
@@ -257,10 +272,61 @@ func (h *ChannelHandler) JoinChannel(c *gin.Context) {
 		UserID:    userID.(string),
 	}
 
-	if err := h.db.Create(&member).Error; err != nil {
+	if err := db.Create(&member).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to join channel"})
 		return
 	}
 
 	c.JSON(http.StatusCreated, member)
 }
+
+// UpdateLinkPreviews godoc
+// @Summary Turn link previews on or off
+// @Description Allow or stop previews of the links posted in a channel; previews already attached are hidden while off (channel managers only)
+// @Tags channels
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Channel ID"
+// @Param settings body chatDto.ChannelLinkPreviewsRequest true "Link preview setting"
+// @Success 200 {object} chatDomain.Channel
+// @Failure 400 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /api/v1/channels/{id}/link-previews [put]
+func (h *ChannelHandler) UpdateLinkPreviews(c *gin.Context) {
+	db := h.db.WithContext(c.Request.Context())
+
+	userID, exists := c.Get("clerk_user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	channelID := c.Param("id")
+
+	var req chatDto.ChannelLinkPreviewsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	resourceType := "channel"
+	if !sharedModels.HasUserPermission(db, userID.(string), sharedModels.PermissionChannelManage, &resourceType, &channelID) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Only channel managers can change link previews"})
+		return
+	}
+
+	var channel chatDomain.Channel
+	if err := db.Scopes(sharedModels.InWorkspace("channels")).Where("id = ?", channelID).First(&channel).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Channel not found"})
+		return
+	}
+
+	if err := db.Model(&channel).Update("link_previews_disabled", !*req.Enabled).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update channel"})
+		return
+	}
+
+	c.JSON(http.StatusOK, channel)
+}
@@ -5,8 +5,11 @@ import (
 )
 
 // BaseModel contains common fields for all models
+// CreatedBy and UpdatedBy are stamped by the audit GORM callbacks from the request context
 type BaseModel struct {
 	ID        string    `json:"id" gorm:"type:uuid;primaryKey;default:gen_random_uuid()"`
 	CreatedAt time.Time `json:"created_at" gorm:"autoCreateTime"`
 	UpdatedAt time.Time `json:"updated_at" gorm:"autoUpdateTime"`
+	CreatedBy string    `json:"created_by,omitempty" gorm:"type:text;default:''"`
+	UpdatedBy string    `json:"updated_by,omitempty" gorm:"type:text;default:''"`
 }
@@ -1,10 +1,16 @@
 package config
 
 import (
-	"log"
+	"fmt"
+	"log/slog"
 	"os"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/joho/godotenv"
+
+	"thothix-backend/internal/vault"
 )
 
 type Config struct {
@@ -16,40 +22,223 @@ type Config struct {
 	DBName             string
 	ClerkSecretKey     string // Chiave segreta di Clerk
 	ClerkWebhookSecret string // Webhook signing secret di Clerk
+	EncryptionKey      string // Application encryption key, also keys the search index of encrypted messages
 	Environment        string
+	LogLevel           string // debug, info, warn or error
+	UseVault           bool   // Secrets are loaded from Vault, which readiness then depends on
+
+	// Dynamic database credentials, requested from Vault's database secrets engine instead of DBUser/DBPassword
+	VaultDBMount     string // Mount path of the database secrets engine
+	VaultDBRole      string // Role to request credentials for, empty to use the static login
+	VaultDBOwnerRole string // Role assumed by every connection so schema objects keep one owner, empty to skip
+
+	// HTTP server
+	HTTPReadHeaderTimeout time.Duration // Time to read request headers, guards against slow clients
+	HTTPReadTimeout       time.Duration // Time to read the whole request
+	HTTPWriteTimeout      time.Duration // Time to write the response
+	HTTPIdleTimeout       time.Duration // Keep-alive time between requests
+	HTTPMaxHeaderBytes    int           // Maximum size of request headers
+	ShutdownTimeout       time.Duration // Time allowed for draining requests and stopping dependencies on SIGTERM
+
+	// Rate limiting, limits are "<requests>/<period>" such as "60/m", or "off"
+	RateLimitStore    string // "memory" for a single instance, "postgres" to share limits across replicas
+	RateLimitMessages string // Sending messages, per user
+	RateLimitAuth     string // Auth endpoints, per user
+	RateLimitWebhooks string // Incoming webhooks, per IP
+
+	// Presence
+	PresenceStore string // "memory" for a single instance, "postgres" to share presence across replicas
+
+	// Scheduled messages and reminders
+	SchedulerInterval time.Duration // Time between passes of the scheduler sending due messages and reminders
+
+	// Retention
+	RetentionInterval time.Duration // Time between passes of the purge deleting content past its retention policy
+
+	// Background jobs
+	JobQueues       map[string]int // Jobs each queue runs at once across all replicas; queues left out run one
+	JobPollInterval time.Duration  // Time an idle queue waits before looking for due jobs again
+
+	// Encryption at rest of message content and file metadata
+	EncryptionProvider    string         // "local" wraps data keys with EncryptionKey, "transit" with Vault's transit engine
+	EncryptionKeyVersion  int            // Version of EncryptionKey; bump it when replacing the key
+	EncryptionRetiredKeys map[int]string // Previous keys by version, kept for decryption and search until re-encryption completes
+	VaultTransitMount     string         // Mount path of the transit engine
+	VaultTransitKey       string         // Transit key wrapping data keys
+	ReencryptInterval     time.Duration  // Time between passes of the re-encryption job
+
+	// CORS
+	CORSAllowedOrigins   []string      // Exact origins, wildcard subdomains such as "https://*.thothix.io", or "*"
+	CORSAllowCredentials bool          // Allow cookies and Authorization on cross-origin requests from allowed origins
+	CORSExposedHeaders   []string      // Response headers readable by browser clients
+	CORSMaxAge           time.Duration // How long browsers may cache a preflight response
+
+	// Reverse proxies
+	TrustedProxies []string // IPs or CIDRs of proxies whose X-Forwarded-For gives the client IP; empty trusts none
+
+	// Metrics, served on the MetricsAddr admin listener and, when MetricsToken is set, also on the API port
+	MetricsAddr  string // Separate admin listener, loopback-only by default; empty when METRICS_ADDR is "off"
+	MetricsToken string // Bearer token required on the API port, empty to disable
+
+	// Tracing
+	TracingEndpoint string // OTLP/HTTP collector URL such as "http://otel-collector:4318", empty to disable export
+
+	settings []Setting // Resolved settings with their source, for Dump
+	problems []error   // Values that failed to parse, reported by Validate
+}
+
+// defaultCORSExposedHeaders lets clients read the request ID and their rate limit
+const defaultCORSExposedHeaders = "X-Request-ID,X-RateLimit-Limit,X-RateLimit-Remaining,X-RateLimit-Reset,Retry-After"
+
+// defaultMetricsAddr keeps the admin listener on loopback; bind another interface explicitly to scrape remotely
+const defaultMetricsAddr = "127.0.0.1:9090"
+
+// defaultCORSOrigins is the origin list of each environment when CORS_ALLOWED_ORIGINS is not set
+func defaultCORSOrigins(environment string) string {
+	if environment == "development" {
+		return "http://localhost:30001"
+	}
+	return "https://thothix.io,https://thothix.com"
+}
+
+// retiredKeys parses "<version>:<key>" pairs separated by commas
+func retiredKeys(r *resolver, key string) map[int]string {
+	keys := make(map[int]string)
+	for _, item := range r.list(key, "") {
+		version, secret, ok := strings.Cut(item, ":")
+		number, err := strconv.Atoi(version)
+		if !ok || err != nil || number <= 0 || secret == "" {
+			r.problems = append(r.problems, fmt.Errorf("%s: expected <version>:<key> pairs", key))
+			continue
+		}
+		keys[number] = secret
+	}
+	return keys
 }
 
-func Load() *Config {
+// optionalAddr returns the listen address, or "" when the listener is turned off
+// An empty value falls back to the default like every setting, so "off" is how a listener is disabled
+func optionalAddr(value string) string {
+	if strings.EqualFold(strings.TrimSpace(value), "off") {
+		return ""
+	}
+	return value
+}
+
+// queueConcurrency parses "<queue>:<concurrency>" pairs separated by commas
+func queueConcurrency(r *resolver, key, fallback string) map[string]int {
+	queues := make(map[string]int)
+	for _, item := range r.list(key, fallback) {
+		queue, limit, ok := strings.Cut(item, ":")
+		number, err := strconv.Atoi(limit)
+		if !ok || err != nil || number <= 0 || queue == "" {
+			r.problems = append(r.problems, fmt.Errorf("%s: expected <queue>:<concurrency> pairs", key))
+			continue
+		}
+		queues[queue] = number
+	}
+	return queues
+}
+
+// loadVaultSecrets reads the Vault layer; replaced in tests
+var loadVaultSecrets = vault.LoadSecrets
+
+// Load resolves the configuration from defaults, then CONFIG_FILE, then the environment, then Vault
+// when USE_VAULT is true, each layer overriding the previous one. A .env file is loaded into the
+// environment first. The configuration is returned even when validation fails, so it can be dumped
+func Load() (*Config, error) {
 	// Carica file .env se esiste
 	if err := godotenv.Load(); err != nil {
-		log.Println("No .env file found, using environment variables")
-	}
-	config := &Config{
-		Port:               getEnv("PORT", "30000"),
-		DBHost:             getEnv("DB_HOST", "localhost"),
-		DBPort:             getEnv("DB_PORT", "5432"),
-		DBUser:             getEnv("DB_USER", "postgres"),
-		DBPassword:         getEnv("DB_PASSWORD", "@Admin123"),
-		DBName:             getEnv("DB_NAME", "thothix-db"),
-		ClerkSecretKey:     getEnv("CLERK_SECRET_KEY", "development_key"),
-		ClerkWebhookSecret: getEnv("CLERK_WEBHOOK_SECRET", ""),
-		Environment:        getEnv("ENVIRONMENT", "development"),
+		slog.Info("No .env file found, using environment variables")
 	}
 
-	if config.ClerkSecretKey == "" || config.ClerkSecretKey == "development_key" {
-		log.Println("WARNING: CLERK_SECRET_KEY not set or using development key")
+	var layers []layer
+	if path := os.Getenv("CONFIG_FILE"); path != "" {
+		file, err := fileLayer(path)
+		if err != nil {
+			return nil, err
+		}
+		layers = append(layers, file)
 	}
+	layers = append(layers, envLayer())
+
+	return load(layers)
+}
 
-	if config.ClerkWebhookSecret == "" {
-		log.Println("WARNING: CLERK_WEBHOOK_SECRET not set - webhook verification will fail")
+// load adds the Vault layer when the lower layers enable it, then builds and validates the configuration
+// Vault's own address and credentials always come from the environment
+func load(layers []layer) (*Config, error) {
+	if useVault, _ := strconv.ParseBool((&resolver{layers: layers}).get("USE_VAULT", "false")); useVault {
+		secrets, err := loadVaultSecrets()
+		if err != nil {
+			return nil, fmt.Errorf("failed to load configuration from Vault: %w", err)
+		}
+		layers = append(layers, layer{source: SourceVault, values: secrets})
 	}
 
-	return config
+	config := build(&resolver{layers: layers})
+	return config, config.Validate()
+}
+
+// UsesDynamicDBCredentials reports whether database credentials are requested from Vault
+func (c *Config) UsesDynamicDBCredentials() bool {
+	return c.UseVault && c.VaultDBRole != ""
 }
 
-func getEnv(key, defaultValue string) string {
-	if value := os.Getenv(key); value != "" {
-		return value
+// build reads every setting through r
+func build(r *resolver) *Config {
+	config := &Config{
+		Port:               r.get("PORT", "30000"),
+		DBHost:             r.get("DB_HOST", "localhost"),
+		DBPort:             r.get("DB_PORT", "5432"),
+		DBUser:             r.get("DB_USER", "postgres"),
+		DBPassword:         r.get("DB_PASSWORD", defaultDBPassword),
+		DBName:             r.get("DB_NAME", "thothix-db"),
+		ClerkSecretKey:     r.get("CLERK_SECRET_KEY", defaultClerkSecretKey),
+		ClerkWebhookSecret: r.get("CLERK_WEBHOOK_SECRET", ""),
+		EncryptionKey:      r.get("ENCRYPTION_KEY", ""),
+		Environment:        r.get("ENVIRONMENT", "development"),
+		LogLevel:           r.get("LOG_LEVEL", "info"),
+		UseVault:           r.bool("USE_VAULT", false),
+
+		VaultDBMount:     r.get("VAULT_DB_MOUNT", "database"),
+		VaultDBRole:      r.get("VAULT_DB_ROLE", ""),
+		VaultDBOwnerRole: r.get("VAULT_DB_OWNER_ROLE", ""),
+
+		HTTPReadHeaderTimeout: r.duration("HTTP_READ_HEADER_TIMEOUT", 5*time.Second),
+		HTTPReadTimeout:       r.duration("HTTP_READ_TIMEOUT", 15*time.Second),
+		HTTPWriteTimeout:      r.duration("HTTP_WRITE_TIMEOUT", 30*time.Second),
+		HTTPIdleTimeout:       r.duration("HTTP_IDLE_TIMEOUT", 120*time.Second),
+		HTTPMaxHeaderBytes:    r.int("HTTP_MAX_HEADER_BYTES", 1<<20),
+		ShutdownTimeout:       r.duration("SHUTDOWN_TIMEOUT", 30*time.Second),
+
+		RateLimitStore:    r.get("RATE_LIMIT_STORE", "memory"),
+		RateLimitMessages: r.get("RATE_LIMIT_MESSAGES", "60/m"),
+		RateLimitAuth:     r.get("RATE_LIMIT_AUTH", "30/m"),
+		RateLimitWebhooks: r.get("RATE_LIMIT_WEBHOOKS", "300/m"),
+		PresenceStore:     r.get("PRESENCE_STORE", "memory"),
+		SchedulerInterval: r.duration("SCHEDULER_INTERVAL", 15*time.Second),
+		RetentionInterval: r.duration("RETENTION_INTERVAL", time.Hour),
+		MetricsAddr:       optionalAddr(r.get("METRICS_ADDR", defaultMetricsAddr)),
+		MetricsToken:      r.get("METRICS_TOKEN", ""),
+		TracingEndpoint:   r.get("OTEL_EXPORTER_OTLP_ENDPOINT", ""),
 	}
-	return defaultValue
+	config.JobQueues = queueConcurrency(r, "JOB_QUEUES", "default:4,gdpr:1,unfurl:4")
+	config.JobPollInterval = r.duration("JOB_POLL_INTERVAL", time.Second)
+	config.EncryptionProvider = r.get("ENCRYPTION_PROVIDER", "local")
+	config.EncryptionKeyVersion = r.int("ENCRYPTION_KEY_VERSION", 1)
+	config.EncryptionRetiredKeys = retiredKeys(r, "ENCRYPTION_RETIRED_KEYS")
+	config.VaultTransitMount = r.get("VAULT_TRANSIT_MOUNT", "transit")
+	config.VaultTransitKey = r.get("VAULT_TRANSIT_KEY", "thothix-messages")
+	config.ReencryptInterval = r.duration("ENCRYPTION_REENCRYPT_INTERVAL", time.Hour)
+
+	config.CORSAllowedOrigins = r.list("CORS_ALLOWED_ORIGINS", defaultCORSOrigins(config.Environment))
+	config.CORSAllowCredentials = r.bool("CORS_ALLOW_CREDENTIALS", true)
+	config.CORSExposedHeaders = r.list("CORS_EXPOSED_HEADERS", defaultCORSExposedHeaders)
+	config.CORSMaxAge = r.duration("CORS_MAX_AGE", 12*time.Hour)
+	config.TrustedProxies = r.list("TRUSTED_PROXIES", "")
+
+	config.settings = r.settings
+	config.problems = r.problems
+	return config
 }
@@ -0,0 +1,243 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// productionSecrets are valid secrets so tests can focus on a single rule
+var productionSecrets = map[string]string{
+	"ENVIRONMENT":          "production",
+	"DB_PASSWORD":          "s3cret-db-password",
+	"CLERK_SECRET_KEY":     "sk_live_abc",
+	"CLERK_WEBHOOK_SECRET": "whsec_abc",
+	"ENCRYPTION_KEY":       "0123456789abcdef0123456789abcdef",
+}
+
+func stubVault(t *testing.T, secrets map[string]string, err error) {
+	previous := loadVaultSecrets
+	loadVaultSecrets = func() (map[string]string, error) { return secrets, err }
+	t.Cleanup(func() { loadVaultSecrets = previous })
+}
+
+func settingSource(cfg *Config, key string) string {
+	for _, setting := range cfg.Dump() {
+		if setting.Key == key {
+			return setting.Source
+		}
+	}
+	return ""
+}
+
+func TestLoad_LayerPrecedence(t *testing.T) {
+	stubVault(t, map[string]string{"DB_PASSWORD": "from-vault"}, nil)
+	file := layer{source: SourceFile, values: map[string]string{"DB_HOST": "file-host", "DB_NAME": "file-db", "DB_PASSWORD": "file-pw"}}
+	env := layer{source: SourceEnv, values: map[string]string{"DB_NAME": "env-db", "DB_PASSWORD": "env-pw", "USE_VAULT": "true"}}
+
+	cfg, err := load([]layer{file, env})
+
+	require.NoError(t, err)
+	assert.Equal(t, "5432", cfg.DBPort)
+	assert.Equal(t, "file-host", cfg.DBHost)
+	assert.Equal(t, "env-db", cfg.DBName)
+	assert.Equal(t, "from-vault", cfg.DBPassword)
+	assert.Equal(t, SourceDefault, settingSource(cfg, "DB_PORT"))
+	assert.Equal(t, SourceFile, settingSource(cfg, "DB_HOST"))
+	assert.Equal(t, SourceEnv, settingSource(cfg, "DB_NAME"))
+	assert.Equal(t, SourceVault, settingSource(cfg, "DB_PASSWORD"))
+}
+
+func TestLoad_VaultFailureIsFatal(t *testing.T) {
+	stubVault(t, nil, errors.New("permission denied"))
+
+	_, err := load([]layer{{source: SourceEnv, values: map[string]string{"USE_VAULT": "true"}}})
+
+	assert.ErrorContains(t, err, "permission denied")
+}
+
+func TestFileLayer(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("DB_HOST: db.internal\nHTTP_MAX_HEADER_BYTES: 4096\nMETRICS_TOKEN:\n"), 0o600))
+
+	file, err := fileLayer(path)
+	require.NoError(t, err)
+	cfg, err := load([]layer{file})
+
+	require.NoError(t, err)
+	assert.Equal(t, "db.internal", cfg.DBHost)
+	assert.Equal(t, 4096, cfg.HTTPMaxHeaderBytes)
+	assert.Equal(t, "", cfg.MetricsToken)
+}
+
+func TestMetricsAddr(t *testing.T) {
+	defaults, _ := load(nil)
+	disabled, _ := load([]layer{{source: SourceEnv, values: map[string]string{"METRICS_ADDR": "off"}}})
+	public, _ := load([]layer{{source: SourceEnv, values: map[string]string{"METRICS_ADDR": ":9090"}}})
+
+	assert.Equal(t, "127.0.0.1:9090", defaults.MetricsAddr)
+	assert.Equal(t, "", disabled.MetricsAddr)
+	assert.Equal(t, ":9090", public.MetricsAddr)
+}
+
+func TestValidate_ProductionRejectsDefaults(t *testing.T) {
+	values := map[string]string{"ENVIRONMENT": "production", "CLERK_SECRET_KEY": "your_clerk_secret_key_here"}
+
+	cfg, err := load([]layer{{source: SourceEnv, values: values}})
+
+	require.Error(t, err)
+	assert.NotNil(t, cfg)
+	assert.ErrorContains(t, err, "DB_PASSWORD uses a default or example value")
+	assert.ErrorContains(t, err, "CLERK_SECRET_KEY uses a default or example value")
+	assert.ErrorContains(t, err, "CLERK_WEBHOOK_SECRET is not set")
+	assert.ErrorContains(t, err, "ENCRYPTION_KEY is not set")
+}
+
+func TestValidate_ProductionRejectsInvalidValues(t *testing.T) {
+	values := map[string]string{"HTTP_READ_TIMEOUT": "soon"}
+	for key, value := range productionSecrets {
+		values[key] = value
+	}
+
+	cfg, err := load([]layer{{source: SourceEnv, values: values}})
+
+	assert.ErrorContains(t, err, `HTTP_READ_TIMEOUT: invalid duration "soon"`)
+	assert.Equal(t, 15*time.Second, cfg.HTTPReadTimeout)
+}
+
+func TestValidate_ProductionAcceptsRealSecrets(t *testing.T) {
+	_, err := load([]layer{{source: SourceEnv, values: productionSecrets}})
+
+	assert.NoError(t, err)
+}
+
+func TestValidate_DynamicDBCredentialsSkipPassword(t *testing.T) {
+	stubVault(t, map[string]string{}, nil)
+	values := map[string]string{"USE_VAULT": "true", "VAULT_DB_ROLE": "thothix-app"}
+	for key, value := range productionSecrets {
+		values[key] = value
+	}
+	delete(values, "DB_PASSWORD")
+
+	cfg, err := load([]layer{{source: SourceEnv, values: values}})
+
+	assert.NoError(t, err)
+	assert.True(t, cfg.UsesDynamicDBCredentials())
+	assert.Equal(t, "database", cfg.VaultDBMount)
+}
+
+func TestValidate_DBRoleRequiresVault(t *testing.T) {
+	values := map[string]string{"VAULT_DB_ROLE": "thothix-app"}
+	for key, value := range productionSecrets {
+		values[key] = value
+	}
+
+	_, err := load([]layer{{source: SourceEnv, values: values}})
+
+	assert.ErrorContains(t, err, "VAULT_DB_ROLE requires USE_VAULT")
+}
+
+func TestLoad_CORSOriginsPerEnvironment(t *testing.T) {
+	development, err := load(nil)
+	require.NoError(t, err)
+	production, err := load([]layer{{source: SourceEnv, values: productionSecrets}})
+	require.NoError(t, err)
+	override, err := load([]layer{{source: SourceEnv, values: map[string]string{"CORS_ALLOWED_ORIGINS": " https://*.thothix.dev, ,https://thothix.io "}}})
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"http://localhost:30001"}, development.CORSAllowedOrigins)
+	assert.Equal(t, []string{"https://thothix.io", "https://thothix.com"}, production.CORSAllowedOrigins)
+	assert.Equal(t, []string{"https://*.thothix.dev", "https://thothix.io"}, override.CORSAllowedOrigins)
+	assert.Contains(t, production.CORSExposedHeaders, "X-RateLimit-Remaining")
+}
+
+func TestValidate_ProductionRejectsInvalidCORSOrigins(t *testing.T) {
+	values := map[string]string{"CORS_ALLOWED_ORIGINS": "*,thothix.io,https://app.*.thothix.io,https://thothix.io/app"}
+	for key, value := range productionSecrets {
+		values[key] = value
+	}
+
+	_, err := load([]layer{{source: SourceEnv, values: values}})
+
+	assert.ErrorContains(t, err, `"*" cannot be combined with CORS_ALLOW_CREDENTIALS`)
+	assert.ErrorContains(t, err, `invalid origin "thothix.io"`)
+	assert.ErrorContains(t, err, `invalid origin "https://app.*.thothix.io"`)
+	assert.ErrorContains(t, err, `invalid origin "https://thothix.io/app"`)
+}
+
+func TestValidate_ProductionRejectsInvalidTrustedProxies(t *testing.T) {
+	values := map[string]string{"TRUSTED_PROXIES": "10.0.0.0/8,192.168.1.7,proxy.internal"}
+	for key, value := range productionSecrets {
+		values[key] = value
+	}
+
+	_, err := load([]layer{{source: SourceEnv, values: values}})
+
+	assert.ErrorContains(t, err, `TRUSTED_PROXIES: invalid address or range "proxy.internal"`)
+	assert.NotContains(t, err.Error(), `"10.0.0.0/8"`)
+	assert.NotContains(t, err.Error(), `"192.168.1.7"`)
+}
+
+func TestLoad_EncryptionRetiredKeys(t *testing.T) {
+	values := map[string]string{"ENCRYPTION_KEY_VERSION": "3", "ENCRYPTION_RETIRED_KEYS": "1:first-key, 2:second:key"}
+	for key, value := range productionSecrets {
+		values[key] = value
+	}
+
+	cfg, err := load([]layer{{source: SourceEnv, values: values}})
+
+	require.NoError(t, err)
+	assert.Equal(t, 3, cfg.EncryptionKeyVersion)
+	assert.Equal(t, map[int]string{1: "first-key", 2: "second:key"}, cfg.EncryptionRetiredKeys)
+	for _, setting := range cfg.Dump() {
+		if setting.Key == "ENCRYPTION_RETIRED_KEYS" {
+			assert.Equal(t, redacted, setting.Value)
+		}
+	}
+}
+
+func TestValidate_ProductionRejectsInvalidEncryption(t *testing.T) {
+	values := map[string]string{"ENCRYPTION_RETIRED_KEYS": "1:old,two:bad", "ENCRYPTION_PROVIDER": "transit"}
+	for key, value := range productionSecrets {
+		values[key] = value
+	}
+
+	_, err := load([]layer{{source: SourceEnv, values: values}})
+
+	assert.ErrorContains(t, err, "ENCRYPTION_RETIRED_KEYS: expected <version>:<key> pairs")
+	assert.ErrorContains(t, err, "ENCRYPTION_PROVIDER=transit requires USE_VAULT")
+}
+
+func TestValidate_DevelopmentOnlyWarns(t *testing.T) {
+	cfg, err := load(nil)
+
+	assert.NoError(t, err)
+	assert.Equal(t, defaultDBPassword, cfg.DBPassword)
+}
+
+func TestDump_RedactsSecrets(t *testing.T) {
+	cfg, err := load([]layer{{source: SourceEnv, values: productionSecrets}})
+	require.NoError(t, err)
+
+	for _, setting := range cfg.Dump() {
+		if secretKeys[setting.Key] && setting.Value != "" {
+			assert.Equal(t, redacted, setting.Value, setting.Key)
+		}
+		assert.NotContains(t, setting.Value, "s3cret")
+	}
+	assert.Equal(t, "production", settingValue(cfg, "ENVIRONMENT"))
+}
+
+func settingValue(cfg *Config, key string) string {
+	for _, setting := range cfg.Dump() {
+		if setting.Key == key {
+			return setting.Value
+		}
+	}
+	return ""
+}
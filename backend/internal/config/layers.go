@@ -0,0 +1,121 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Configuration layers, from lowest to highest priority
+const (
+	SourceDefault = "default"
+	SourceFile    = "file"
+	SourceEnv     = "env"
+	SourceVault   = "vault"
+)
+
+// layer is a set of settings keyed by environment variable name
+type layer struct {
+	source string
+	values map[string]string
+}
+
+// resolver looks settings up through the layers, the last layer defining a key wins
+// It remembers where each setting came from and which values failed to parse
+type resolver struct {
+	layers   []layer
+	settings []Setting
+	problems []error
+}
+
+// get returns the value of key from the highest layer defining it, or defaultValue
+func (r *resolver) get(key, defaultValue string) string {
+	value, source := defaultValue, SourceDefault
+	for _, l := range r.layers {
+		if v, ok := l.values[key]; ok && v != "" {
+			value, source = v, l.source
+		}
+	}
+	r.settings = append(r.settings, Setting{Key: key, Value: value, Source: source})
+	return value
+}
+
+// duration parses a Go duration such as "15s"; invalid values are reported and replaced by defaultValue
+func (r *resolver) duration(key string, defaultValue time.Duration) time.Duration {
+	value := r.get(key, defaultValue.String())
+	parsed, err := time.ParseDuration(value)
+	if err != nil || parsed <= 0 {
+		r.problems = append(r.problems, fmt.Errorf("%s: invalid duration %q", key, value))
+		return defaultValue
+	}
+	return parsed
+}
+
+// int parses a positive integer; invalid values are reported and replaced by defaultValue
+func (r *resolver) int(key string, defaultValue int) int {
+	value := r.get(key, strconv.Itoa(defaultValue))
+	parsed, err := strconv.Atoi(value)
+	if err != nil || parsed <= 0 {
+		r.problems = append(r.problems, fmt.Errorf("%s: invalid number %q", key, value))
+		return defaultValue
+	}
+	return parsed
+}
+
+// bool parses "true" or "false"; invalid values are reported and replaced by defaultValue
+func (r *resolver) bool(key string, defaultValue bool) bool {
+	value := r.get(key, strconv.FormatBool(defaultValue))
+	parsed, err := strconv.ParseBool(value)
+	if err != nil {
+		r.problems = append(r.problems, fmt.Errorf("%s: invalid boolean %q", key, value))
+		return defaultValue
+	}
+	return parsed
+}
+
+// list splits a comma-separated value, dropping blank entries
+func (r *resolver) list(key, defaultValue string) []string {
+	var items []string
+	for _, item := range strings.Split(r.get(key, defaultValue), ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// fileLayer reads a YAML file of settings keyed by environment variable name, such as "DB_HOST: db"
+func fileLayer(path string) (layer, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return layer{}, fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	var raw map[string]interface{}
+	if err := yaml.Unmarshal(content, &raw); err != nil {
+		return layer{}, fmt.Errorf("failed to parse config file %s: %w", path, err)
+	}
+
+	values := make(map[string]string, len(raw))
+	for key, value := range raw {
+		if value != nil {
+			values[key] = fmt.Sprint(value)
+		}
+	}
+	return layer{source: SourceFile, values: values}, nil
+}
+
+// envLayer reads the process environment, which includes any .env file loaded beforehand
+func envLayer() layer {
+	values := make(map[string]string)
+	for _, entry := range os.Environ() {
+		if key, value, ok := strings.Cut(entry, "="); ok {
+			values[key] = value
+		}
+	}
+	return layer{source: SourceEnv, values: values}
+}
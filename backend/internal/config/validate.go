@@ -0,0 +1,150 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/netip"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+const (
+	// Development defaults, rejected in production
+	defaultDBPassword     = "@Admin123"
+	defaultClerkSecretKey = "development_key"
+
+	redacted = "[redacted]"
+)
+
+// insecureValues are defaults and the placeholders shipped in .env.example
+var insecureValues = map[string]bool{
+	defaultDBPassword:                  true,
+	defaultClerkSecretKey:              true,
+	"change_me_in_production":          true,
+	"your_clerk_secret_key_here":       true,
+	"your_clerk_webhook_secret_here":   true,
+	"A7bC9dF3gH6jK4mN1qU8wY2eR5tA7bC9": true,
+}
+
+// secretKeys are never printed by Dump
+var secretKeys = map[string]bool{
+	"DB_PASSWORD":             true,
+	"CLERK_SECRET_KEY":        true,
+	"CLERK_WEBHOOK_SECRET":    true,
+	"ENCRYPTION_KEY":          true,
+	"METRICS_TOKEN":           true,
+	"ENCRYPTION_RETIRED_KEYS": true,
+}
+
+// Setting is a resolved setting and the layer it came from
+type Setting struct {
+	Key    string `json:"key"`
+	Value  string `json:"value"`
+	Source string `json:"source"`
+}
+
+// IsProduction reports whether the strict production rules apply
+func (c *Config) IsProduction() bool {
+	return c.Environment == "production"
+}
+
+// Validate reports invalid values and missing or default secrets
+// In production every problem is an error; elsewhere they are logged as warnings and nil is returned
+func (c *Config) Validate() error {
+	problems := append([]error(nil), c.problems...)
+
+	requireSecret := func(key, value string) {
+		switch {
+		case value == "":
+			problems = append(problems, fmt.Errorf("%s is not set", key))
+		case insecureValues[value]:
+			problems = append(problems, fmt.Errorf("%s uses a default or example value", key))
+		}
+	}
+	// With dynamic credentials Vault issues the login and DB_PASSWORD is not used
+	if !c.UsesDynamicDBCredentials() {
+		if c.VaultDBRole != "" {
+			problems = append(problems, fmt.Errorf("VAULT_DB_ROLE requires USE_VAULT"))
+		}
+		requireSecret("DB_PASSWORD", c.DBPassword)
+	}
+	problems = append(problems, c.validateCORS()...)
+	problems = append(problems, c.validateTrustedProxies()...)
+
+	switch c.EncryptionProvider {
+	case "local":
+		if _, retired := c.EncryptionRetiredKeys[c.EncryptionKeyVersion]; retired {
+			problems = append(problems, fmt.Errorf("ENCRYPTION_RETIRED_KEYS: version %d is the current ENCRYPTION_KEY_VERSION", c.EncryptionKeyVersion))
+		}
+	case "transit":
+		if !c.UseVault {
+			problems = append(problems, errors.New("ENCRYPTION_PROVIDER=transit requires USE_VAULT"))
+		}
+	default:
+		problems = append(problems, fmt.Errorf("ENCRYPTION_PROVIDER: unknown provider %q", c.EncryptionProvider))
+	}
+
+	requireSecret("CLERK_SECRET_KEY", c.ClerkSecretKey)
+	requireSecret("CLERK_WEBHOOK_SECRET", c.ClerkWebhookSecret)
+	requireSecret("ENCRYPTION_KEY", c.EncryptionKey)
+
+	if len(problems) == 0 {
+		return nil
+	}
+	if c.IsProduction() {
+		return fmt.Errorf("invalid production configuration: %w", errors.Join(problems...))
+	}
+	for _, problem := range problems {
+		slog.Warn("Invalid configuration", slog.Any("problem", problem))
+	}
+	return nil
+}
+
+// validateCORS checks the origin patterns, which must be "*" or scheme://host[:port] where the host
+// may start with "*." to allow any subdomain
+func (c *Config) validateCORS() []error {
+	var problems []error
+	for _, origin := range c.CORSAllowedOrigins {
+		if origin == "*" {
+			if c.CORSAllowCredentials {
+				problems = append(problems, errors.New("CORS_ALLOWED_ORIGINS: \"*\" cannot be combined with CORS_ALLOW_CREDENTIALS"))
+			}
+			continue
+		}
+		parsed, err := url.Parse(strings.Replace(origin, "://*.", "://wildcard.", 1))
+		if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" ||
+			parsed.Path != "" || parsed.RawQuery != "" || parsed.User != nil || strings.Contains(parsed.Host, "*") {
+			problems = append(problems, fmt.Errorf("CORS_ALLOWED_ORIGINS: invalid origin %q", origin))
+		}
+	}
+	return problems
+}
+
+// validateTrustedProxies checks that every trusted proxy is an IP address or a CIDR range
+func (c *Config) validateTrustedProxies() []error {
+	var problems []error
+	for _, proxy := range c.TrustedProxies {
+		if _, err := netip.ParsePrefix(proxy); err == nil {
+			continue
+		}
+		if _, err := netip.ParseAddr(proxy); err != nil {
+			problems = append(problems, fmt.Errorf("TRUSTED_PROXIES: invalid address or range %q", proxy))
+		}
+	}
+	return problems
+}
+
+// Dump lists the effective settings sorted by key, with secrets redacted
+func (c *Config) Dump() []Setting {
+	settings := make([]Setting, 0, len(c.settings))
+	for _, setting := range c.settings {
+		if secretKeys[setting.Key] && setting.Value != "" {
+			setting.Value = redacted
+		}
+		settings = append(settings, setting)
+	}
+	sort.Slice(settings, func(i, j int) bool { return settings[i].Key < settings[j].Key })
+	return settings
+}
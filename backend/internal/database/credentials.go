@@ -0,0 +1,145 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/hashicorp/vault/api"
+	_ "github.com/jackc/pgx/v5/stdlib" // Registers the "pgx" database/sql driver
+
+	"thothix-backend/internal/config"
+	"thothix-backend/internal/shared/logging"
+	"thothix-backend/internal/vault"
+)
+
+const (
+	// Backoff between failed credential rotations
+	minRotationBackoff = time.Second
+	maxRotationBackoff = 30 * time.Second
+)
+
+// dsn builds the connection string for the given login
+// ownerRole, when set, is assumed at connection start so objects created by migrations keep a
+// stable owner across the short-lived users Vault generates
+func dsn(cfg *config.Config, username, password, ownerRole string) string {
+	dsn := fmt.Sprintf(
+		"host=%s user=%s password=%s dbname=%s port=%s sslmode=disable TimeZone=UTC",
+		cfg.DBHost, username, password, cfg.DBName, cfg.DBPort,
+	)
+	if ownerRole != "" {
+		dsn += " role=" + ownerRole
+	}
+	return dsn
+}
+
+// dynamicPool opens a pool on credentials from Vault's database secrets engine and keeps them
+// valid in the background: the lease is renewed, and before it expires new credentials are
+// requested and the pool is swapped to them
+func dynamicPool(cfg *config.Config) (*SwappablePool, error) {
+	client, err := vault.Shared()
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize vault client: %w", err)
+	}
+
+	rotator := &credentialRotator{
+		vault: client,
+		mount: cfg.VaultDBMount,
+		role:  cfg.VaultDBRole,
+		open: func(ctx context.Context, credentials *vault.DatabaseCredentials) (*sql.DB, error) {
+			return openPool(ctx, dsn(cfg, credentials.Username, credentials.Password, cfg.VaultDBOwnerRole))
+		},
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	db, lease, err := rotator.connect(ctx)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+
+	rotator.pool = NewSwappablePool(db)
+	rotator.pool.stop = cancel
+	go rotator.run(ctx, lease)
+	return rotator.pool, nil
+}
+
+// openPool opens and pings a pool
+func openPool(ctx context.Context, dsn string) (*sql.DB, error) {
+	db, err := sql.Open("pgx", dsn)
+	if err != nil {
+		return nil, err
+	}
+	if err := db.PingContext(ctx); err != nil {
+		_ = db.Close()
+		return nil, err
+	}
+	return db, nil
+}
+
+// credentialRotator keeps a SwappablePool on valid Vault-issued credentials
+type credentialRotator struct {
+	vault *vault.Client
+	mount string
+	role  string
+	open  func(ctx context.Context, credentials *vault.DatabaseCredentials) (*sql.DB, error)
+	pool  *SwappablePool
+}
+
+// connect requests credentials and opens a pool on them
+func (r *credentialRotator) connect(ctx context.Context) (*sql.DB, *api.Secret, error) {
+	credentials, err := r.vault.DatabaseCredentials(ctx, r.mount, r.role)
+	if err != nil {
+		return nil, nil, err
+	}
+	db, err := r.open(ctx, credentials)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to connect with vault credentials: %w", err)
+	}
+	return db, credentials.Lease, nil
+}
+
+// run renews the current lease and rotates the credentials when it runs out, until ctx is cancelled
+func (r *credentialRotator) run(ctx context.Context, lease *api.Secret) {
+	logger := logging.FromContext(ctx).With(slog.String("vault_role", r.role))
+
+	for {
+		if err := r.vault.WatchLease(ctx, lease); err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			logger.Error("Database lease watcher failed", slog.Any("error", err))
+		}
+
+		next, ok := r.rotate(ctx, logger)
+		if !ok {
+			return
+		}
+		lease = next
+	}
+}
+
+// rotate swaps the pool to new credentials, retrying with backoff; it returns false when ctx was cancelled
+func (r *credentialRotator) rotate(ctx context.Context, logger *slog.Logger) (*api.Secret, bool) {
+	backoff := minRotationBackoff
+	for {
+		db, lease, err := r.connect(ctx)
+		if err == nil {
+			if err := r.pool.Swap(db); err != nil {
+				return nil, false
+			}
+			logger.Info("Database credentials rotated", slog.Int("lease_seconds", lease.LeaseDuration))
+			return lease, true
+		}
+		logger.Error("Database credential rotation failed", slog.Any("error", err), slog.Duration("retry_in", backoff))
+
+		select {
+		case <-ctx.Done():
+			return nil, false
+		case <-time.After(backoff):
+		}
+		backoff = min(backoff*2, maxRotationBackoff)
+	}
+}
@@ -2,42 +2,185 @@ package database
 
 import (
 	"fmt"
-	"log"
+	"log/slog"
+	"time"
 
+	auditDomain "thothix-backend/internal/audit/domain"
+	auditHooks "thothix-backend/internal/audit/hooks"
+	blockingDomain "thothix-backend/internal/blocking/domain"
+	bookmarkDomain "thothix-backend/internal/bookmarks/domain"
+	chatDomain "thothix-backend/internal/chat/domain"
 	"thothix-backend/internal/config"
+	gdprDomain "thothix-backend/internal/gdpr/domain"
+	jobsDomain "thothix-backend/internal/jobs/domain"
+	messageDomain "thothix-backend/internal/message/domain"
+	"thothix-backend/internal/message/search"
+	"thothix-backend/internal/metrics"
+	moderationDomain "thothix-backend/internal/moderation/domain"
+	presenceDomain "thothix-backend/internal/presence/domain"
+	projectDomain "thothix-backend/internal/project/domain"
+	"thothix-backend/internal/ratelimit"
+	retentionDomain "thothix-backend/internal/retention/domain"
+	schedulingDomain "thothix-backend/internal/scheduling/domain"
+	"thothix-backend/internal/shared/logging"
+	sharedModels "thothix-backend/internal/shared/models"
+	"thothix-backend/internal/tracing"
+	usersDomain "thothix-backend/internal/users/domain"
+	workspaceDomain "thothix-backend/internal/workspace/domain"
 
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 	"gorm.io/gorm/logger"
 )
 
+// slowQueryThreshold is the duration above which a query is logged as slow
+const slowQueryThreshold = 200 * time.Millisecond
+
+// Initialize connects to Postgres with the static DB_USER/DB_PASSWORD login, or with short-lived
+// credentials from Vault when VAULT_DB_ROLE is set. Close the returned database with Close
 func Initialize(cfg *config.Config) (*gorm.DB, error) {
-	dsn := fmt.Sprintf(
-		"host=%s user=%s password=%s dbname=%s port=%s sslmode=disable TimeZone=UTC",
-		cfg.DBHost, cfg.DBUser, cfg.DBPassword, cfg.DBName, cfg.DBPort,
-	)
+	dialector := postgres.Open(dsn(cfg, cfg.DBUser, cfg.DBPassword, ""))
+	var pool *SwappablePool
+	if cfg.UsesDynamicDBCredentials() {
+		var err error
+		if pool, err = dynamicPool(cfg); err != nil {
+			return nil, err
+		}
+		dialector = postgres.New(postgres.Config{Conn: pool})
+	}
 
+	// Queries go through the request logger; they are logged at debug level in development
 	var logLevel logger.LogLevel
 	if cfg.Environment == "development" {
 		logLevel = logger.Info
 	} else {
-		logLevel = logger.Error
+		logLevel = logger.Warn
 	}
 
-	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{
-		Logger: logger.Default.LogMode(logLevel),
+	db, err := gorm.Open(dialector, &gorm.Config{
+		Logger: logging.NewGormLogger(logLevel, slowQueryThreshold),
 	})
 	if err != nil {
+		if pool != nil {
+			_ = pool.Close()
+		}
 		return nil, fmt.Errorf("failed to connect to database: %w", err)
 	}
 
-	log.Println("Database connected successfully")
+	// Stamp created_by/updated_by and write the audit log on every change
+	if err := auditHooks.Register(db); err != nil {
+		return nil, fmt.Errorf("failed to register audit callbacks: %w", err)
+	}
+
+	// Blind index of message words, so encrypted messages stay searchable
+	if err := search.RegisterGORM(db); err != nil {
+		return nil, fmt.Errorf("failed to register search callbacks: %w", err)
+	}
+
+	// Spans for every statement run within a traced request
+	if err := tracing.RegisterGORM(db); err != nil {
+		return nil, fmt.Errorf("failed to register tracing callbacks: %w", err)
+	}
+
+	// Query durations and connection pool stats for /metrics
+	if err := metrics.RegisterGORM(db); err != nil {
+		return nil, fmt.Errorf("failed to register metrics callbacks: %w", err)
+	}
+
+	slog.Info("Database connected successfully")
 	return db, nil
 }
 
+// Models returns every domain model managed by the auto-migration
+func Models() []interface{} {
+	return []interface{}{
+		&usersDomain.User{},
+		&sharedModels.UserRole{},
+		&projectDomain.Project{},
+		&projectDomain.ProjectMember{},
+		&chatDomain.Channel{},
+		&chatDomain.ChannelMember{},
+		&messageDomain.Message{},
+		&messageDomain.File{},
+		&messageDomain.Reminder{},
+		&messageDomain.MessageSearchToken{},
+		&messageDomain.LinkPreview{},
+		&messageDomain.MessageLinkPreview{},
+		&messageDomain.CustomCommand{},
+		&bookmarkDomain.Pin{},
+		&bookmarkDomain.SavedItem{},
+		&schedulingDomain.ScheduledMessage{},
+		&retentionDomain.Policy{},
+		&retentionDomain.LegalHold{},
+		&moderationDomain.Report{},
+		&moderationDomain.Moderator{},
+		&moderationDomain.Suspension{},
+		&moderationDomain.Action{},
+		&moderationDomain.BlockedWordList{},
+		&blockingDomain.Block{},
+		&jobsDomain.Job{},
+		&auditDomain.AuditLog{},
+		&gdprDomain.DataRequest{},
+		&workspaceDomain.Workspace{},
+		&workspaceDomain.Invitation{},
+		&ratelimit.Bucket{},
+		&presenceDomain.Connection{},
+		&presenceDomain.TypingIndicator{},
+	}
+}
+
+// auditLogAppendOnlySQL rejects UPDATE and DELETE on the audit log at the database level
+const auditLogAppendOnlySQL = `
+CREATE OR REPLACE FUNCTION audit_logs_append_only() RETURNS trigger AS $$
+BEGIN
+	RAISE EXCEPTION 'audit_logs is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS audit_logs_append_only ON audit_logs;
+CREATE TRIGGER audit_logs_append_only
+	BEFORE UPDATE OR DELETE ON audit_logs
+	FOR EACH ROW EXECUTE FUNCTION audit_logs_append_only();
+`
+
+// defaultWorkspaceSQL moves data created before workspaces existed into a default workspace
+// Every user joins it with their system role, so existing permissions are unchanged.
+// Custom command names become unique per workspace instead of globally
+const defaultWorkspaceSQL = `
+DROP INDEX IF EXISTS idx_custom_commands_name;
+
+INSERT INTO workspaces (name, slug, created_at, updated_at)
+SELECT 'Default', 'default', now(), now()
+WHERE NOT EXISTS (SELECT 1 FROM workspaces WHERE slug = 'default')
+	AND (EXISTS (SELECT 1 FROM users) OR EXISTS (SELECT 1 FROM projects) OR EXISTS (SELECT 1 FROM channels));
+
+UPDATE projects SET workspace_id = w.id::text FROM workspaces w
+WHERE w.slug = 'default' AND (projects.workspace_id IS NULL OR projects.workspace_id = '');
+
+UPDATE channels SET workspace_id = w.id::text FROM workspaces w
+WHERE w.slug = 'default' AND (channels.workspace_id IS NULL OR channels.workspace_id = '');
+
+UPDATE custom_commands SET workspace_id = w.id::text FROM workspaces w
+WHERE w.slug = 'default' AND (custom_commands.workspace_id IS NULL OR custom_commands.workspace_id = '');
+
+INSERT INTO user_roles (user_id, role, resource_type, resource_id, created_at, updated_at)
+SELECT u.id::text, u.system_role, 'workspace', w.id::text, now(), now()
+FROM users u CROSS JOIN workspaces w
+WHERE w.slug = 'default' AND u.purged_at IS NULL
+	AND NOT EXISTS (SELECT 1 FROM user_roles r WHERE r.user_id = u.id::text AND r.resource_type = 'workspace');
+`
+
 func Migrate(db *gorm.DB) error {
-	log.Println("Running database migrations...")
-	log.Println("🔄 Database migration disabled temporarily to resolve import cycles")
-	log.Println("✅ Database migration completed successfully")
+	slog.Info("Running database migrations")
+	if err := db.AutoMigrate(Models()...); err != nil {
+		return fmt.Errorf("failed to migrate database: %w", err)
+	}
+	if err := db.Exec(auditLogAppendOnlySQL).Error; err != nil {
+		return fmt.Errorf("failed to protect audit log: %w", err)
+	}
+	if err := db.Exec(defaultWorkspaceSQL).Error; err != nil {
+		return fmt.Errorf("failed to backfill default workspace: %w", err)
+	}
+	slog.Info("Database migration completed successfully")
 	return nil
 }
@@ -0,0 +1,53 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync/atomic"
+
+	"gorm.io/gorm"
+)
+
+// PingCheck verifies the database accepts connections
+func PingCheck(db *gorm.DB) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		sqlDB, err := db.DB()
+		if err != nil {
+			return err
+		}
+		return sqlDB.PingContext(ctx)
+	}
+}
+
+// MigrationsCheck verifies the table of every model exists
+// Once all tables are found the result is cached, since migrations are never rolled back at runtime
+func MigrationsCheck(db *gorm.DB) func(ctx context.Context) error {
+	var applied atomic.Bool
+	return func(ctx context.Context) error {
+		if applied.Load() {
+			return nil
+		}
+
+		migrator := db.WithContext(ctx).Migrator()
+		var missing []string
+		for _, model := range Models() {
+			if !migrator.HasTable(model) {
+				stmt := &gorm.Statement{DB: db}
+				if err := stmt.Parse(model); err != nil {
+					return err
+				}
+				missing = append(missing, stmt.Schema.Table)
+			}
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if len(missing) > 0 {
+			return fmt.Errorf("missing tables: %s", strings.Join(missing, ", "))
+		}
+
+		applied.Store(true)
+		return nil
+	}
+}
@@ -0,0 +1,86 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"sync/atomic"
+
+	"gorm.io/gorm"
+)
+
+// SwappablePool is a GORM connection pool whose underlying *sql.DB can be replaced at runtime
+// Statements started before a swap finish on the old pool; new statements use the new one
+type SwappablePool struct {
+	current atomic.Pointer[sql.DB]
+	closing atomic.Bool
+	stop    func() // Stops credential rotation
+}
+
+var (
+	_ gorm.ConnPool       = (*SwappablePool)(nil)
+	_ gorm.TxBeginner     = (*SwappablePool)(nil)
+	_ gorm.GetDBConnector = (*SwappablePool)(nil)
+)
+
+// NewSwappablePool wraps db
+func NewSwappablePool(db *sql.DB) *SwappablePool {
+	p := &SwappablePool{stop: func() {}}
+	p.current.Store(db)
+	return p
+}
+
+// Swap installs db and closes the previous pool once its in-flight statements have finished
+func (p *SwappablePool) Swap(db *sql.DB) error {
+	if p.closing.Load() {
+		_ = db.Close()
+		return errors.New("database pool is closed")
+	}
+	previous := p.current.Swap(db)
+	go previous.Close() // Close waits for the queries already running on the old pool
+	return nil
+}
+
+// Close stops credential rotation and closes the current pool
+func (p *SwappablePool) Close() error {
+	p.closing.Store(true)
+	p.stop()
+	return p.current.Load().Close()
+}
+
+func (p *SwappablePool) PrepareContext(ctx context.Context, query string) (*sql.Stmt, error) {
+	return p.current.Load().PrepareContext(ctx, query)
+}
+
+func (p *SwappablePool) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	return p.current.Load().ExecContext(ctx, query, args...)
+}
+
+func (p *SwappablePool) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	return p.current.Load().QueryContext(ctx, query, args...)
+}
+
+func (p *SwappablePool) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	return p.current.Load().QueryRowContext(ctx, query, args...)
+}
+
+func (p *SwappablePool) BeginTx(ctx context.Context, opts *sql.TxOptions) (*sql.Tx, error) {
+	return p.current.Load().BeginTx(ctx, opts)
+}
+
+// GetDBConn returns the current pool, so gorm.DB.DB() follows swaps
+func (p *SwappablePool) GetDBConn() (*sql.DB, error) {
+	return p.current.Load(), nil
+}
+
+// Close closes the database: it stops credential rotation when the pool is swappable
+func Close(db *gorm.DB) error {
+	if pool, ok := db.ConnPool.(*SwappablePool); ok {
+		return pool.Close()
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		return err
+	}
+	return sqlDB.Close()
+}
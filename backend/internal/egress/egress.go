@@ -0,0 +1,127 @@
+// Package egress builds HTTP clients for requests to URLs chosen by users or tenant admins, such as link
+// previews and custom command endpoints, so those requests cannot reach internal services or cloud metadata
+package egress
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"strings"
+	"syscall"
+	"time"
+)
+
+var (
+	// ErrBlockedAddress is returned when a URL, or a redirect it leads to, resolves to a non-public address
+	ErrBlockedAddress = errors.New("egress: address is not public")
+
+	// ErrUnsupportedURL is returned for URLs other than plain http and https ones
+	ErrUnsupportedURL = errors.New("egress: unsupported URL")
+)
+
+// blockedPrefixes are the special-purpose ranges not covered by the netip predicates used in IsPublic
+var blockedPrefixes = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),       // "This" network
+	netip.MustParsePrefix("100.64.0.0/10"),   // Carrier-grade NAT
+	netip.MustParsePrefix("192.0.0.0/24"),    // IETF protocol assignments
+	netip.MustParsePrefix("192.0.2.0/24"),    // Documentation
+	netip.MustParsePrefix("198.18.0.0/15"),   // Benchmarking
+	netip.MustParsePrefix("198.51.100.0/24"), // Documentation
+	netip.MustParsePrefix("203.0.113.0/24"),  // Documentation
+	netip.MustParsePrefix("240.0.0.0/4"),     // Reserved, including broadcast
+	netip.MustParsePrefix("64:ff9b::/96"),    // NAT64, which can reach IPv4 private ranges
+	netip.MustParsePrefix("64:ff9b:1::/48"),  // Local-use NAT64
+	netip.MustParsePrefix("2001:db8::/32"),   // Documentation
+	netip.MustParsePrefix("2002::/16"),       // 6to4, which embeds any IPv4 address
+}
+
+// IsPublic reports whether addr is a globally routable unicast address
+func IsPublic(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	if !addr.IsGlobalUnicast() || addr.IsPrivate() || addr.IsLoopback() || addr.IsLinkLocalUnicast() {
+		return false
+	}
+	for _, prefix := range blockedPrefixes {
+		if prefix.Contains(addr) {
+			return false
+		}
+	}
+	return true
+}
+
+// CheckURL accepts absolute http and https URLs without credentials
+func CheckURL(u *url.URL) error {
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Hostname() == "" || u.User != nil {
+		return fmt.Errorf("%w: %s", ErrUnsupportedURL, u.Redacted())
+	}
+	return nil
+}
+
+// ValidateURL checks a URL before it is stored: it must pass CheckURL and must not name a non-public
+// address or localhost. Host names are only resolved when the URL is requested, by the client's dialer
+func ValidateURL(rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrUnsupportedURL, err)
+	}
+	if err := CheckURL(u); err != nil {
+		return err
+	}
+
+	host := strings.TrimSuffix(strings.ToLower(u.Hostname()), ".")
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return fmt.Errorf("%w: %s", ErrBlockedAddress, host)
+	}
+	if addr, err := netip.ParseAddr(host); err == nil && !IsPublic(addr) {
+		return fmt.Errorf("%w: %s", ErrBlockedAddress, host)
+	}
+	return nil
+}
+
+// ClientOptions configure NewClient
+type ClientOptions struct {
+	Timeout      time.Duration             // Total time of a request and its redirects
+	MaxRedirects int                       // Redirects followed; zero refuses redirects
+	Permit       func(netip.AddrPort) bool // Addresses that may be dialed; public addresses when nil
+}
+
+// NewClient returns a client that only connects to permitted addresses. The check runs after name
+// resolution, on the address actually dialed, for every connection including those of redirects,
+// which also defeats DNS rebinding
+func NewClient(options ClientOptions) *http.Client {
+	permit := options.Permit
+	if permit == nil {
+		permit = func(addr netip.AddrPort) bool { return IsPublic(addr.Addr()) }
+	}
+
+	dialer := &net.Dialer{
+		Timeout: options.Timeout,
+		Control: func(network, address string, _ syscall.RawConn) error {
+			addr, err := netip.ParseAddrPort(address)
+			if err != nil || !permit(addr) {
+				return fmt.Errorf("%w: %s", ErrBlockedAddress, address)
+			}
+			return nil
+		},
+	}
+	return &http.Client{
+		Timeout: options.Timeout,
+		Transport: &http.Transport{
+			Proxy:                 nil, // A proxy would dial internal hosts on our behalf
+			DialContext:           dialer.DialContext,
+			TLSHandshakeTimeout:   options.Timeout,
+			ResponseHeaderTimeout: options.Timeout,
+			MaxIdleConns:          10,
+			IdleConnTimeout:       30 * time.Second,
+		},
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if len(via) > options.MaxRedirects {
+				return fmt.Errorf("egress: stopped after %d redirects", options.MaxRedirects)
+			}
+			return CheckURL(req.URL)
+		},
+	}
+}
@@ -0,0 +1,104 @@
+package encryption
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+const (
+	// minTokenLength skips single characters, which would match nearly every value
+	minTokenLength = 2
+
+	// maxTokens bounds the index size of one value
+	maxTokens = 256
+
+	// tokenSize is the truncated HMAC length; collisions only cost extra candidates
+	tokenSize = 16
+)
+
+// BlindIndex hashes words with a secret key, so encrypted text can be searched by whole word
+// without the index revealing the words. Equal words give equal tokens, which reveals word
+// frequencies to someone with database access but not the words themselves.
+// New tokens use the current key; retired keys are kept for searching until re-encryption has
+// re-indexed every message under the current one
+type BlindIndex struct {
+	key     []byte
+	retired [][]byte
+}
+
+// NewBlindIndex derives the index key from secret, and the keys still searched from retired secrets
+func NewBlindIndex(secret string, retired ...string) (*BlindIndex, error) {
+	key, err := deriveKey(secret, "thothix search index")
+	if err != nil {
+		return nil, err
+	}
+	index := &BlindIndex{key: key}
+	for _, old := range retired {
+		oldKey, err := deriveKey(old, "thothix search index")
+		if err != nil {
+			return nil, err
+		}
+		index.retired = append(index.retired, oldKey)
+	}
+	return index, nil
+}
+
+// Tokens returns the distinct tokens of the words in text, case-insensitively
+func (i *BlindIndex) Tokens(text string) []string {
+	words := distinctWords(text)
+	tokens := make([]string, 0, len(words))
+	for _, word := range words {
+		tokens = append(tokens, token(i.key, word))
+	}
+	return tokens
+}
+
+// QueryTokens returns the tokens of the words in query under the current and every retired key,
+// with the number of distinct words. A message is indexed under a single key, so it contains every
+// word when it matches as many tokens as there are words
+func (i *BlindIndex) QueryTokens(query string) ([]string, int) {
+	words := distinctWords(query)
+	tokens := make([]string, 0, len(words)*(1+len(i.retired)))
+	for _, key := range append([][]byte{i.key}, i.retired...) {
+		for _, word := range words {
+			tokens = append(tokens, token(key, word))
+		}
+	}
+	return tokens, len(words)
+}
+
+// Key returns the token of a whole value, to look up an encrypted value by exact match
+func (i *BlindIndex) Key(value string) string {
+	return token(i.key, value)
+}
+
+// distinctWords splits text into lowercase words, skipping short and repeated ones
+func distinctWords(text string) []string {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	seen := make(map[string]bool, len(words))
+	distinct := make([]string, 0, len(words))
+	for _, word := range words {
+		if utf8.RuneCountInString(word) < minTokenLength || seen[word] {
+			continue
+		}
+		seen[word] = true
+		distinct = append(distinct, word)
+		if len(distinct) == maxTokens {
+			break
+		}
+	}
+	return distinct
+}
+
+func token(key []byte, word string) string {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(word))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil)[:tokenSize])
+}
@@ -0,0 +1,250 @@
+package encryption
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// envelopePrefix marks an encrypted value: "enc:<key id>:<wrapped data key>:<nonce and ciphertext>",
+	// the last two base64 encoded. Values that do not have this exact shape are legacy plain text,
+	// even when they start with the prefix
+	envelopePrefix = "enc:"
+
+	// minSealedSize is the size of a sealed empty value: the AES-GCM nonce and tag
+	minSealedSize = 12 + 16
+
+	// dataKeySize is the AES-256 key size of data keys
+	dataKeySize = 32
+
+	// defaultDataKeyTTL is how long one data key encrypts new values before a fresh one is wrapped
+	defaultDataKeyTTL = 5 * time.Minute
+
+	// maxCachedDataKeys bounds the cache of unwrapped data keys used for decryption
+	maxCachedDataKeys = 1024
+)
+
+// ErrUnknownKey is returned when a value was encrypted under a key that is no longer configured
+var ErrUnknownKey = errors.New("encryption key not available")
+
+// KeyWrapper encrypts data keys under a key-encryption key that it manages and versions
+type KeyWrapper interface {
+	// KeyID identifies the current key-encryption key, such as "local-v2"; it contains no ':', '%' or '_'
+	KeyID(ctx context.Context) (string, error)
+	// Wrap encrypts a data key under the current key and returns that key's ID
+	Wrap(ctx context.Context, dataKey []byte) (keyID string, wrapped []byte, err error)
+	// Unwrap decrypts a data key wrapped under keyID
+	Unwrap(ctx context.Context, keyID string, wrapped []byte) ([]byte, error)
+}
+
+// Keyring encrypts values with envelope encryption: values are sealed with AES-GCM data keys, and the
+// data keys are stored alongside them wrapped by a KeyWrapper. A data key is reused for a few minutes,
+// so the wrapper, which may be remote, is not called for every value
+type Keyring struct {
+	wrapper    KeyWrapper
+	index      *BlindIndex
+	dataKeyTTL time.Duration
+	now        func() time.Time
+
+	mu        sync.Mutex
+	current   *dataKey
+	unwrapped map[string][]byte // Plain data keys by their wrapped form
+}
+
+// dataKey is the data key currently used for encryption
+type dataKey struct {
+	keyID   string
+	plain   []byte
+	wrapped string
+	created time.Time
+}
+
+// NewKeyring creates a keyring wrapping data keys with wrapper; index hashes words for search
+func NewKeyring(wrapper KeyWrapper, index *BlindIndex) *Keyring {
+	return &Keyring{
+		wrapper:    wrapper,
+		index:      index,
+		dataKeyTTL: defaultDataKeyTTL,
+		now:        time.Now,
+		unwrapped:  make(map[string][]byte),
+	}
+}
+
+// Index returns the blind index used to search encrypted text
+func (k *Keyring) Index() *BlindIndex {
+	return k.index
+}
+
+// CurrentKeyID returns the ID of the key new values are encrypted under
+func (k *Keyring) CurrentKeyID(ctx context.Context) (string, error) {
+	return k.wrapper.KeyID(ctx)
+}
+
+// Encrypt seals plaintext into an envelope
+func (k *Keyring) Encrypt(ctx context.Context, plaintext string) (string, error) {
+	key, err := k.dataKey(ctx)
+	if err != nil {
+		return "", err
+	}
+	sealed, err := seal(key.plain, []byte(plaintext))
+	if err != nil {
+		return "", err
+	}
+	return envelopePrefix + key.keyID + ":" + key.wrapped + ":" + base64.RawURLEncoding.EncodeToString(sealed), nil
+}
+
+// Decrypt opens an envelope; legacy plain text is returned unchanged
+func (k *Keyring) Decrypt(ctx context.Context, value string) (string, error) {
+	env, ok := parseEnvelope(value)
+	if !ok {
+		return value, nil
+	}
+
+	plainKey, err := k.unwrap(ctx, env.keyID, env.wrapped)
+	if err != nil {
+		return "", err
+	}
+	plaintext, err := open(plainKey, env.sealed)
+	if err != nil {
+		return "", err
+	}
+	return string(plaintext), nil
+}
+
+// IsEncrypted reports whether value is an envelope
+func IsEncrypted(value string) bool {
+	_, ok := parseEnvelope(value)
+	return ok
+}
+
+// envelope is a parsed encrypted value
+type envelope struct {
+	keyID   string
+	wrapped string // Base64 form, which also keys the cache of unwrapped data keys
+	sealed  []byte
+}
+
+// parseEnvelope splits an encrypted value, reporting false for anything that is not a well-formed envelope
+func parseEnvelope(value string) (envelope, bool) {
+	rest, ok := strings.CutPrefix(value, envelopePrefix)
+	if !ok {
+		return envelope{}, false
+	}
+	parts := strings.Split(rest, ":")
+	if len(parts) != 3 || parts[0] == "" || strings.ContainsAny(parts[0], " %_") {
+		return envelope{}, false
+	}
+	wrapped, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil || len(wrapped) == 0 {
+		return envelope{}, false
+	}
+	sealed, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil || len(sealed) < minSealedSize {
+		return envelope{}, false
+	}
+	return envelope{keyID: parts[0], wrapped: parts[1], sealed: sealed}, true
+}
+
+// EnvelopePrefix is the prefix of values encrypted under keyID, for finding values still under older keys
+func EnvelopePrefix(keyID string) string {
+	return envelopePrefix + keyID + ":"
+}
+
+// dataKey returns the current data key, wrapping a new one when it is missing or too old
+func (k *Keyring) dataKey(ctx context.Context) (*dataKey, error) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	if k.current != nil && k.now().Sub(k.current.created) < k.dataKeyTTL {
+		return k.current, nil
+	}
+
+	plain := make([]byte, dataKeySize)
+	if _, err := rand.Read(plain); err != nil {
+		return nil, fmt.Errorf("failed to generate data key: %w", err)
+	}
+	keyID, wrapped, err := k.wrapper.Wrap(ctx, plain)
+	if err != nil {
+		return nil, fmt.Errorf("failed to wrap data key: %w", err)
+	}
+	k.current = &dataKey{
+		keyID:   keyID,
+		plain:   plain,
+		wrapped: base64.RawURLEncoding.EncodeToString(wrapped),
+		created: k.now(),
+	}
+	return k.current, nil
+}
+
+// unwrap returns the plain data key for a wrapped one, from the cache when possible
+func (k *Keyring) unwrap(ctx context.Context, keyID, wrapped string) ([]byte, error) {
+	cacheKey := keyID + ":" + wrapped
+	k.mu.Lock()
+	plain, ok := k.unwrapped[cacheKey]
+	k.mu.Unlock()
+	if ok {
+		return plain, nil
+	}
+
+	raw, err := base64.RawURLEncoding.DecodeString(wrapped)
+	if err != nil {
+		return nil, fmt.Errorf("malformed wrapped data key: %w", err)
+	}
+	plain, err = k.wrapper.Unwrap(ctx, keyID, raw)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unwrap data key: %w", err)
+	}
+
+	k.mu.Lock()
+	if len(k.unwrapped) >= maxCachedDataKeys {
+		k.unwrapped = make(map[string][]byte)
+	}
+	k.unwrapped[cacheKey] = plain
+	k.mu.Unlock()
+	return plain, nil
+}
+
+// seal encrypts plaintext with AES-GCM, prefixing the random nonce
+func seal(key, plaintext []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return gcm.Seal(nonce, nonce, plaintext, nil), nil
+}
+
+// open decrypts the output of seal
+func open(key, sealed []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(sealed) < gcm.NonceSize() {
+		return nil, errors.New("encrypted value too short")
+	}
+	plaintext, err := gcm.Open(nil, sealed[:gcm.NonceSize()], sealed[gcm.NonceSize():], nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt value: %w", err)
+	}
+	return plaintext, nil
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
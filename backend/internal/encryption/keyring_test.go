@@ -0,0 +1,130 @@
+package encryption
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestKeyring(t *testing.T, version int, secret string, retired map[int]string) *Keyring {
+	wrapper, err := NewLocalWrapper(version, secret, retired)
+	require.NoError(t, err)
+	index, err := NewBlindIndex("index-secret")
+	require.NoError(t, err)
+	return NewKeyring(wrapper, index)
+}
+
+func TestKeyring_RoundTrip(t *testing.T) {
+	keyring := newTestKeyring(t, 1, "app-secret", nil)
+
+	encrypted, err := keyring.Encrypt(context.Background(), "hello world")
+	require.NoError(t, err)
+	decrypted, err := keyring.Decrypt(context.Background(), encrypted)
+
+	require.NoError(t, err)
+	assert.Equal(t, "hello world", decrypted)
+	assert.True(t, strings.HasPrefix(encrypted, EnvelopePrefix("local-v1")))
+	assert.NotContains(t, encrypted, "hello")
+}
+
+func TestKeyring_LegacyPlainTextPassesThrough(t *testing.T) {
+	keyring := newTestKeyring(t, 1, "app-secret", nil)
+
+	decrypted, err := keyring.Decrypt(context.Background(), "stored before encryption")
+
+	require.NoError(t, err)
+	assert.Equal(t, "stored before encryption", decrypted)
+}
+
+func TestKeyring_ReusesDataKeyUntilTTL(t *testing.T) {
+	keyring := newTestKeyring(t, 1, "app-secret", nil)
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	keyring.now = func() time.Time { return now }
+
+	first, _ := keyring.Encrypt(context.Background(), "a")
+	second, _ := keyring.Encrypt(context.Background(), "b")
+	now = now.Add(defaultDataKeyTTL)
+	third, _ := keyring.Encrypt(context.Background(), "c")
+
+	wrappedKey := func(value string) string { return strings.Split(value, ":")[2] }
+	assert.Equal(t, wrappedKey(first), wrappedKey(second))
+	assert.NotEqual(t, wrappedKey(first), wrappedKey(third))
+}
+
+func TestKeyring_RotationKeepsRetiredKeysReadable(t *testing.T) {
+	old := newTestKeyring(t, 1, "old-secret", nil)
+	encrypted, err := old.Encrypt(context.Background(), "before rotation")
+	require.NoError(t, err)
+
+	rotated := newTestKeyring(t, 2, "new-secret", map[int]string{1: "old-secret"})
+	decrypted, err := rotated.Decrypt(context.Background(), encrypted)
+	require.NoError(t, err)
+	keyID, _ := rotated.CurrentKeyID(context.Background())
+
+	assert.Equal(t, "before rotation", decrypted)
+	assert.Equal(t, "local-v2", keyID)
+}
+
+func TestKeyring_RemovedKeyIsUnknown(t *testing.T) {
+	old := newTestKeyring(t, 1, "old-secret", nil)
+	encrypted, _ := old.Encrypt(context.Background(), "before rotation")
+
+	_, err := newTestKeyring(t, 2, "new-secret", nil).Decrypt(context.Background(), encrypted)
+
+	assert.ErrorIs(t, err, ErrUnknownKey)
+}
+
+func TestKeyring_TamperedValueFails(t *testing.T) {
+	keyring := newTestKeyring(t, 1, "app-secret", nil)
+	encrypted, _ := keyring.Encrypt(context.Background(), "hello world")
+	tampered := encrypted[:len(encrypted)-2] + "AA"
+
+	_, err := keyring.Decrypt(context.Background(), tampered)
+
+	assert.Error(t, err)
+}
+
+func TestKeyring_PlainTextWithPrefixIsLegacy(t *testing.T) {
+	keyring := newTestKeyring(t, 1, "app-secret", nil)
+
+	for _, value := range []string{"enc:", "enc: see the attached file", "enc:local-v1:not base64!:x", "enc:a:b:c"} {
+		decrypted, err := keyring.Decrypt(context.Background(), value)
+
+		assert.NoError(t, err, value)
+		assert.Equal(t, value, decrypted)
+		assert.False(t, IsEncrypted(value), value)
+	}
+}
+
+func TestBlindIndex_Tokens(t *testing.T) {
+	index, err := NewBlindIndex("index-secret")
+	require.NoError(t, err)
+	other, err := NewBlindIndex("other-secret")
+	require.NoError(t, err)
+
+	tokens := index.Tokens("Deploy the API, deploy it NOW! a")
+
+	assert.Len(t, tokens, 5) // deploy, the, api, it, now
+	assert.Equal(t, index.Tokens("deploy"), index.Tokens("DEPLOY"))
+	assert.Subset(t, tokens, index.Tokens("api now"))
+	assert.NotEqual(t, index.Tokens("deploy"), other.Tokens("deploy"))
+	assert.Empty(t, index.Tokens("a ! ?"))
+}
+
+func TestBlindIndex_QueryTokensIncludeRetiredKeys(t *testing.T) {
+	old, err := NewBlindIndex("old-secret")
+	require.NoError(t, err)
+	rotated, err := NewBlindIndex("new-secret", "old-secret")
+	require.NoError(t, err)
+
+	tokens, words := rotated.QueryTokens("deploy API")
+
+	assert.Equal(t, 2, words)
+	assert.Subset(t, tokens, old.Tokens("deploy api"))
+	assert.Subset(t, tokens, rotated.Tokens("deploy api"))
+	assert.NotEqual(t, old.Tokens("deploy"), rotated.Tokens("deploy"))
+}
@@ -0,0 +1,80 @@
+package encryption
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"golang.org/x/crypto/hkdf"
+)
+
+// localKeyPrefix prefixes the IDs of keys derived from application secrets
+const localKeyPrefix = "local-v"
+
+// LocalWrapper wraps data keys with keys derived from the application's ENCRYPTION_KEY
+// Retired secrets stay available for decryption until re-encryption has moved every value off them
+type LocalWrapper struct {
+	version int
+	keys    map[int][]byte
+}
+
+// NewLocalWrapper wraps under secret as key version; retired maps older versions to their secrets
+func NewLocalWrapper(version int, secret string, retired map[int]string) (*LocalWrapper, error) {
+	w := &LocalWrapper{version: version, keys: make(map[int][]byte, len(retired)+1)}
+	for retiredVersion, retiredSecret := range retired {
+		if retiredVersion == version {
+			return nil, fmt.Errorf("retired key version %d is the current version", version)
+		}
+		key, err := deriveKey(retiredSecret, "thothix data key wrapping")
+		if err != nil {
+			return nil, err
+		}
+		w.keys[retiredVersion] = key
+	}
+	key, err := deriveKey(secret, "thothix data key wrapping")
+	if err != nil {
+		return nil, err
+	}
+	w.keys[version] = key
+	return w, nil
+}
+
+func (w *LocalWrapper) KeyID(context.Context) (string, error) {
+	return localKeyPrefix + strconv.Itoa(w.version), nil
+}
+
+func (w *LocalWrapper) Wrap(ctx context.Context, dataKey []byte) (string, []byte, error) {
+	wrapped, err := seal(w.keys[w.version], dataKey)
+	if err != nil {
+		return "", nil, err
+	}
+	keyID, _ := w.KeyID(ctx)
+	return keyID, wrapped, nil
+}
+
+func (w *LocalWrapper) Unwrap(_ context.Context, keyID string, wrapped []byte) ([]byte, error) {
+	version, err := strconv.Atoi(strings.TrimPrefix(keyID, localKeyPrefix))
+	if err != nil || !strings.HasPrefix(keyID, localKeyPrefix) {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownKey, keyID)
+	}
+	key, ok := w.keys[version]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownKey, keyID)
+	}
+	return open(key, wrapped)
+}
+
+// deriveKey derives a 256-bit key for purpose from secret
+func deriveKey(secret, purpose string) ([]byte, error) {
+	if secret == "" {
+		return nil, fmt.Errorf("empty secret for %s", purpose)
+	}
+	key := make([]byte, dataKeySize)
+	if _, err := io.ReadFull(hkdf.New(sha256.New, []byte(secret), nil, []byte(purpose)), key); err != nil {
+		return nil, err
+	}
+	return key, nil
+}
@@ -0,0 +1,165 @@
+package encryption
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"gorm.io/gorm"
+
+	"thothix-backend/internal/shared/logging"
+)
+
+// defaultBatchSize is how many values the re-encryption job loads at a time
+const defaultBatchSize = 500
+
+// Column is an encrypted column kept on the current key by the re-encryption job
+// Its table must have a text-convertible "id" primary key
+type Column struct {
+	Table  string
+	Column string
+	// OnRewrite runs in the transaction of each rewritten value, for example to rebuild a search index
+	OnRewrite func(tx *gorm.DB, id, plaintext string) error
+}
+
+// ReencryptResult counts the values handled by one pass
+type ReencryptResult struct {
+	Rewritten int // Values moved to the current key, including legacy plain text
+	Failed    int // Values that could not be decrypted, such as those under a key no longer configured
+}
+
+// Reencryptor moves values encrypted under older keys, and legacy plain text, to the current key,
+// so retired keys can be removed once a pass reports no failures
+type Reencryptor struct {
+	db        *gorm.DB
+	keyring   *Keyring
+	columns   []Column
+	batchSize int
+}
+
+// NewReencryptor creates a job re-encrypting columns with keyring
+func NewReencryptor(db *gorm.DB, keyring *Keyring, columns ...Column) *Reencryptor {
+	return &Reencryptor{db: db, keyring: keyring, columns: columns, batchSize: defaultBatchSize}
+}
+
+// Run makes a pass every interval until ctx is cancelled
+// Replicas may run it concurrently: a value is only rewritten if it did not change since it was read
+func (r *Reencryptor) Run(ctx context.Context, interval time.Duration) {
+	logger := logging.FromContext(ctx)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		result, err := r.RunOnce(ctx)
+		switch {
+		case err != nil && ctx.Err() == nil:
+			logger.Error("Re-encryption pass failed", slog.Any("error", err))
+		case result.Rewritten > 0 || result.Failed > 0:
+			logger.Info("Re-encryption pass completed", slog.Int("rewritten", result.Rewritten), slog.Int("failed", result.Failed))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunOnce makes one pass over every column
+func (r *Reencryptor) RunOnce(ctx context.Context) (ReencryptResult, error) {
+	var total ReencryptResult
+	keyID, err := r.keyring.CurrentKeyID(ctx)
+	if err != nil {
+		return total, err
+	}
+
+	for _, column := range r.columns {
+		result, err := r.reencryptColumn(ctx, column, EnvelopePrefix(keyID))
+		total.Rewritten += result.Rewritten
+		total.Failed += result.Failed
+		if err != nil {
+			return total, fmt.Errorf("%s.%s: %w", column.Table, column.Column, err)
+		}
+	}
+	return total, nil
+}
+
+// storedValue is a raw column value, read without the serializer
+type storedValue struct {
+	ID    string
+	Value string
+}
+
+// reencryptColumn rewrites every value of column not under currentPrefix, walking the table by id
+func (r *Reencryptor) reencryptColumn(ctx context.Context, column Column, currentPrefix string) (ReencryptResult, error) {
+	var result ReencryptResult
+	db := r.db.WithContext(ctx)
+	lastID := ""
+
+	for {
+		var batch []storedValue
+		err := db.Table(column.Table).
+			Select(fmt.Sprintf("id::text AS id, %s AS value", column.Column)).
+			Where(fmt.Sprintf("id::text > ? AND %[1]s <> '' AND %[1]s NOT LIKE ?", column.Column), lastID, currentPrefix+"%").
+			Order("id::text").
+			Limit(r.batchSize).
+			Scan(&batch).Error
+		if err != nil {
+			return result, err
+		}
+
+		for _, stored := range batch {
+			rewritten, err := r.rewrite(db, column, stored)
+			switch {
+			case err != nil && ctx.Err() != nil:
+				return result, ctx.Err()
+			case err != nil:
+				result.Failed++
+				logging.FromContext(ctx).Warn("Value could not be re-encrypted",
+					slog.String("table", column.Table), slog.String("id", stored.ID), slog.Any("error", err))
+			case rewritten:
+				result.Rewritten++
+			}
+		}
+
+		if len(batch) < r.batchSize {
+			return result, nil
+		}
+		lastID = batch[len(batch)-1].ID
+	}
+}
+
+// rewrite re-encrypts one value unless it changed since it was read
+func (r *Reencryptor) rewrite(db *gorm.DB, column Column, stored storedValue) (bool, error) {
+	plaintext, err := r.keyring.Decrypt(db.Statement.Context, stored.Value)
+	if err != nil {
+		return false, err
+	}
+	ciphertext, err := r.keyring.Encrypt(db.Statement.Context, plaintext)
+	if err != nil {
+		return false, err
+	}
+
+	rewritten := false
+	err = db.Transaction(func(tx *gorm.DB) error {
+		// A raw update keeps updated_at and the audit log untouched: the content did not change
+		update := tx.Exec(
+			fmt.Sprintf("UPDATE %[1]s SET %[2]s = ? WHERE id::text = ? AND %[2]s = ?", column.Table, column.Column),
+			ciphertext, stored.ID, stored.Value,
+		)
+		if update.Error != nil {
+			return update.Error
+		}
+		if update.RowsAffected == 0 {
+			return nil
+		}
+		rewritten = true
+		if column.OnRewrite != nil {
+			return column.OnRewrite(tx, stored.ID, plaintext)
+		}
+		return nil
+	})
+	return rewritten, err
+}
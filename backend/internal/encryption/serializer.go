@@ -0,0 +1,132 @@
+package encryption
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"sync/atomic"
+
+	"gorm.io/gorm/schema"
+)
+
+// SerializerName is the GORM serializer encrypting a column at rest: `gorm:"serializer:encrypted"`
+// String fields are stored as their encrypted value and byte slices as the encrypted value of their base64
+// encoding, kept as is without a key; other fields are encoded as JSON first
+const SerializerName = "encrypted"
+
+// bytesType is the type of binary fields, stored as bytes rather than as text
+var bytesType = reflect.TypeOf([]byte(nil))
+
+// defaultKeyring is used by the serializer; without one, values are stored as plain text
+var defaultKeyring atomic.Pointer[Keyring]
+
+func init() {
+	schema.RegisterSerializer(SerializerName, Serializer{})
+}
+
+// SetDefault installs the keyring used by the GORM serializer
+func SetDefault(keyring *Keyring) {
+	defaultKeyring.Store(keyring)
+}
+
+// Default returns the keyring used by the GORM serializer, or nil when encryption is disabled
+func Default() *Keyring {
+	return defaultKeyring.Load()
+}
+
+// Serializer encrypts fields when they are written and decrypts them when they are read,
+// so models and handlers only ever see plain values
+type Serializer struct{}
+
+func (Serializer) Scan(ctx context.Context, field *schema.Field, dst reflect.Value, dbValue interface{}) error {
+	var value string
+	switch v := dbValue.(type) {
+	case nil:
+	case string:
+		value = v
+	case []byte:
+		value = string(v)
+	default:
+		return fmt.Errorf("encrypted field %s: unsupported database value %T", field.Name, dbValue)
+	}
+
+	encrypted := IsEncrypted(value)
+	if encrypted {
+		keyring := Default()
+		if keyring == nil {
+			return errors.New("encrypted value read without an encryption key configured")
+		}
+		plaintext, err := keyring.Decrypt(ctx, value)
+		if err != nil {
+			return fmt.Errorf("encrypted field %s: %w", field.Name, err)
+		}
+		value = plaintext
+	}
+
+	target := field.ReflectValueOf(ctx, dst)
+	if field.FieldType.Kind() == reflect.String {
+		target.SetString(value)
+		return nil
+	}
+	if field.FieldType == bytesType {
+		data := []byte(value)
+		if encrypted {
+			decoded, err := base64.StdEncoding.DecodeString(value)
+			if err != nil {
+				return fmt.Errorf("encrypted field %s: %w", field.Name, err)
+			}
+			data = decoded
+		}
+		if value == "" {
+			data = nil
+		}
+		target.SetBytes(data)
+		return nil
+	}
+	decoded := reflect.New(field.FieldType)
+	if value != "" {
+		if err := json.Unmarshal([]byte(value), decoded.Interface()); err != nil {
+			return fmt.Errorf("encrypted field %s: %w", field.Name, err)
+		}
+	}
+	target.Set(decoded.Elem())
+	return nil
+}
+
+func (Serializer) Value(ctx context.Context, field *schema.Field, _ reflect.Value, fieldValue interface{}) (interface{}, error) {
+	if data, ok := fieldValue.([]byte); ok {
+		keyring := Default()
+		if keyring == nil || len(data) == 0 {
+			return data, nil
+		}
+		encrypted, err := keyring.Encrypt(ctx, base64.StdEncoding.EncodeToString(data))
+		return []byte(encrypted), err
+	}
+
+	value, ok := fieldValue.(string)
+	if !ok {
+		encoded, err := encodeJSON(fieldValue)
+		if err != nil {
+			return nil, fmt.Errorf("encrypted field %s: %w", field.Name, err)
+		}
+		value = encoded
+	}
+	keyring := Default()
+	if keyring == nil || value == "" {
+		return value, nil
+	}
+	return keyring.Encrypt(ctx, value)
+}
+
+// encodeJSON encodes a non-string field, storing nil pointers, slices and maps as an empty value
+func encodeJSON(fieldValue interface{}) (string, error) {
+	if value := reflect.ValueOf(fieldValue); !value.IsValid() ||
+		((value.Kind() == reflect.Pointer || value.Kind() == reflect.Slice || value.Kind() == reflect.Map) && value.IsNil()) {
+		return "", nil
+	}
+	encoded, err := json.Marshal(fieldValue)
+	return string(encoded), err
+}
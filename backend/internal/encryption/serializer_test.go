@@ -0,0 +1,83 @@
+package encryption
+
+import (
+	"context"
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm/schema"
+)
+
+type serializedDocument struct {
+	Title string   `json:"title"`
+	Tags  []string `json:"tags"`
+}
+
+type serializedModel struct {
+	ID       string
+	Body     string              `gorm:"serializer:encrypted"`
+	Document *serializedDocument `gorm:"serializer:encrypted"`
+	Archive  []byte              `gorm:"serializer:encrypted"`
+}
+
+func TestSerializer_EncryptsJSONFields(t *testing.T) {
+	SetDefault(newTestKeyring(t, 1, "app-secret", nil))
+	defer SetDefault(nil)
+	modelSchema, err := schema.Parse(&serializedModel{}, &sync.Map{}, schema.NamingStrategy{})
+	require.NoError(t, err)
+	field := modelSchema.LookUpField("Document")
+	ctx := context.Background()
+	document := &serializedDocument{Title: "Launch plan", Tags: []string{"ops"}}
+
+	stored, err := Serializer{}.Value(ctx, field, reflect.Value{}, document)
+	require.NoError(t, err)
+	var loaded serializedModel
+	require.NoError(t, Serializer{}.Scan(ctx, field, reflect.ValueOf(&loaded), stored))
+
+	assert.True(t, IsEncrypted(stored.(string)))
+	assert.NotContains(t, stored, "Launch")
+	assert.Equal(t, document, loaded.Document)
+}
+
+func TestSerializer_NilAndLegacyJSONValues(t *testing.T) {
+	modelSchema, err := schema.Parse(&serializedModel{}, &sync.Map{}, schema.NamingStrategy{})
+	require.NoError(t, err)
+	field := modelSchema.LookUpField("Document")
+	ctx := context.Background()
+
+	stored, err := Serializer{}.Value(ctx, field, reflect.Value{}, (*serializedDocument)(nil))
+	require.NoError(t, err)
+	var empty serializedModel
+	require.NoError(t, Serializer{}.Scan(ctx, field, reflect.ValueOf(&empty), stored))
+	var legacy serializedModel
+	require.NoError(t, Serializer{}.Scan(ctx, field, reflect.ValueOf(&legacy), `{"title":"plain"}`))
+
+	assert.Equal(t, "", stored)
+	assert.Nil(t, empty.Document)
+	assert.Equal(t, "plain", legacy.Document.Title)
+}
+
+func TestSerializer_BinaryFields(t *testing.T) {
+	SetDefault(newTestKeyring(t, 1, "app-secret", nil))
+	defer SetDefault(nil)
+	modelSchema, err := schema.Parse(&serializedModel{}, &sync.Map{}, schema.NamingStrategy{})
+	require.NoError(t, err)
+	field := modelSchema.LookUpField("Archive")
+	ctx := context.Background()
+	archive := []byte("PK\x03\x04 zipped messages")
+
+	stored, err := Serializer{}.Value(ctx, field, reflect.Value{}, archive)
+	require.NoError(t, err)
+	var loaded serializedModel
+	require.NoError(t, Serializer{}.Scan(ctx, field, reflect.ValueOf(&loaded), stored))
+	var legacy serializedModel
+	require.NoError(t, Serializer{}.Scan(ctx, field, reflect.ValueOf(&legacy), archive))
+
+	assert.True(t, IsEncrypted(string(stored.([]byte))))
+	assert.NotContains(t, string(stored.([]byte)), "zipped")
+	assert.Equal(t, archive, loaded.Archive)
+	assert.Equal(t, archive, legacy.Archive, "archives stored before encryption are read as they are")
+}
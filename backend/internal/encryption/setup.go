@@ -0,0 +1,58 @@
+package encryption
+
+import (
+	"fmt"
+	"sort"
+
+	"thothix-backend/internal/config"
+	"thothix-backend/internal/vault"
+)
+
+const (
+	// ProviderLocal wraps data keys with ENCRYPTION_KEY
+	ProviderLocal = "local"
+	// ProviderTransit wraps data keys with Vault's transit engine
+	ProviderTransit = "transit"
+)
+
+// New builds the keyring selected by the configuration, or returns nil when ENCRYPTION_KEY is not
+// set, in which case values are stored as plain text. The search index is keyed by ENCRYPTION_KEY;
+// the retired keys are still searched, since messages are only re-indexed as they are re-encrypted
+func New(cfg *config.Config) (*Keyring, error) {
+	if cfg.EncryptionKey == "" {
+		return nil, nil
+	}
+
+	versions := make([]int, 0, len(cfg.EncryptionRetiredKeys))
+	for version := range cfg.EncryptionRetiredKeys {
+		versions = append(versions, version)
+	}
+	sort.Sort(sort.Reverse(sort.IntSlice(versions)))
+	retired := make([]string, 0, len(versions))
+	for _, version := range versions {
+		retired = append(retired, cfg.EncryptionRetiredKeys[version])
+	}
+
+	index, err := NewBlindIndex(cfg.EncryptionKey, retired...)
+	if err != nil {
+		return nil, err
+	}
+
+	var wrapper KeyWrapper
+	switch cfg.EncryptionProvider {
+	case "", ProviderLocal:
+		wrapper, err = NewLocalWrapper(cfg.EncryptionKeyVersion, cfg.EncryptionKey, cfg.EncryptionRetiredKeys)
+		if err != nil {
+			return nil, err
+		}
+	case ProviderTransit:
+		client, err := vault.Shared()
+		if err != nil {
+			return nil, fmt.Errorf("failed to initialize vault client: %w", err)
+		}
+		wrapper = NewTransitWrapper(client.API(), cfg.VaultTransitMount, cfg.VaultTransitKey)
+	default:
+		return nil, fmt.Errorf("unknown encryption provider %q", cfg.EncryptionProvider)
+	}
+	return NewKeyring(wrapper, index), nil
+}
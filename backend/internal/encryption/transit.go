@@ -0,0 +1,99 @@
+package encryption
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/vault/api"
+)
+
+const (
+	// transitKeyPrefix prefixes the IDs of Vault transit key versions
+	transitKeyPrefix = "transit-v"
+
+	// transitVersionTTL is how long the latest transit key version is cached
+	transitVersionTTL = time.Minute
+)
+
+// TransitWrapper wraps data keys with a key held by Vault's transit engine, which never leaves Vault
+// Rotating the transit key creates a new version; raising its min_decryption_version retires old ones
+type TransitWrapper struct {
+	client *api.Client
+	mount  string
+	key    string
+	now    func() time.Time
+
+	mu      sync.Mutex
+	version int
+	checked time.Time
+}
+
+// NewTransitWrapper wraps with the transit key named key at mount
+func NewTransitWrapper(client *api.Client, mount, key string) *TransitWrapper {
+	return &TransitWrapper{client: client, mount: mount, key: key, now: time.Now}
+}
+
+func (w *TransitWrapper) KeyID(ctx context.Context) (string, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.version == 0 || w.now().Sub(w.checked) >= transitVersionTTL {
+		secret, err := w.client.Logical().ReadWithContext(ctx, fmt.Sprintf("%s/keys/%s", w.mount, w.key))
+		if err != nil {
+			return "", fmt.Errorf("failed to read transit key: %w", err)
+		}
+		if secret == nil || secret.Data == nil {
+			return "", fmt.Errorf("transit key %s not found", w.key)
+		}
+		number, _ := secret.Data["latest_version"].(json.Number)
+		version, err := number.Int64()
+		if err != nil {
+			return "", fmt.Errorf("invalid transit key version: %w", err)
+		}
+		w.version, w.checked = int(version), w.now()
+	}
+	return transitKeyPrefix + strconv.Itoa(w.version), nil
+}
+
+func (w *TransitWrapper) Wrap(ctx context.Context, dataKey []byte) (string, []byte, error) {
+	secret, err := w.client.Logical().WriteWithContext(ctx, fmt.Sprintf("%s/encrypt/%s", w.mount, w.key), map[string]interface{}{
+		"plaintext": base64.StdEncoding.EncodeToString(dataKey),
+	})
+	if err != nil {
+		return "", nil, err
+	}
+	if secret == nil {
+		return "", nil, fmt.Errorf("no ciphertext returned by transit")
+	}
+	ciphertext, _ := secret.Data["ciphertext"].(string)
+
+	// Ciphertexts look like "vault:v3:..." and carry the key version used
+	parts := strings.SplitN(ciphertext, ":", 3)
+	if len(parts) != 3 || !strings.HasPrefix(parts[1], "v") {
+		return "", nil, fmt.Errorf("unexpected transit ciphertext")
+	}
+	return transitKeyPrefix + strings.TrimPrefix(parts[1], "v"), []byte(ciphertext), nil
+}
+
+func (w *TransitWrapper) Unwrap(ctx context.Context, keyID string, wrapped []byte) ([]byte, error) {
+	if !strings.HasPrefix(keyID, transitKeyPrefix) {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownKey, keyID)
+	}
+	secret, err := w.client.Logical().WriteWithContext(ctx, fmt.Sprintf("%s/decrypt/%s", w.mount, w.key), map[string]interface{}{
+		"ciphertext": string(wrapped),
+	})
+	if err != nil {
+		return nil, err
+	}
+	if secret == nil {
+		return nil, fmt.Errorf("no plaintext returned by transit")
+	}
+	plaintext, _ := secret.Data["plaintext"].(string)
+	return base64.StdEncoding.DecodeString(plaintext)
+}
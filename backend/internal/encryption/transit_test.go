@@ -0,0 +1,69 @@
+package encryption
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/vault/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeTransitServer imitates the transit engine at version 3 of key "messages", "encrypting" by prefixing
+func fakeTransitServer(t *testing.T) *api.Client {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]string
+		_ = json.NewDecoder(r.Body).Decode(&body)
+
+		var data map[string]interface{}
+		switch r.URL.Path {
+		case "/v1/transit/keys/messages":
+			data = map[string]interface{}{"latest_version": 3}
+		case "/v1/transit/encrypt/messages":
+			data = map[string]interface{}{"ciphertext": "vault:v3:" + body["plaintext"]}
+		case "/v1/transit/decrypt/messages":
+			data = map[string]interface{}{"plaintext": strings.SplitN(body["ciphertext"], ":", 3)[2]}
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"data": data})
+	}))
+	t.Cleanup(server.Close)
+
+	config := api.DefaultConfig()
+	config.Address = server.URL
+	client, err := api.NewClient(config)
+	require.NoError(t, err)
+	return client
+}
+
+func TestTransitWrapper_WrapsWithLatestVersion(t *testing.T) {
+	wrapper := NewTransitWrapper(fakeTransitServer(t), "transit", "messages")
+	index, err := NewBlindIndex("index-secret")
+	require.NoError(t, err)
+	keyring := NewKeyring(wrapper, index)
+
+	keyID, err := keyring.CurrentKeyID(context.Background())
+	require.NoError(t, err)
+	encrypted, err := keyring.Encrypt(context.Background(), "hello world")
+	require.NoError(t, err)
+	decrypted, err := NewKeyring(wrapper, index).Decrypt(context.Background(), encrypted)
+
+	require.NoError(t, err)
+	assert.Equal(t, "transit-v3", keyID)
+	assert.True(t, strings.HasPrefix(encrypted, EnvelopePrefix("transit-v3")))
+	assert.Equal(t, "hello world", decrypted)
+}
+
+func TestTransitWrapper_RejectsLocalKeys(t *testing.T) {
+	wrapper := NewTransitWrapper(fakeTransitServer(t), "transit", "messages")
+
+	_, err := wrapper.Unwrap(context.Background(), "local-v1", []byte("wrapped"))
+
+	assert.ErrorIs(t, err, ErrUnknownKey)
+}
@@ -0,0 +1,49 @@
+package domain
+
+import (
+	"time"
+
+	commonModels "thothix-backend/internal/common/models"
+	_ "thothix-backend/internal/encryption" // Registers the "encrypted" serializer used by the models
+)
+
+// DataRequestType defines the kind of data subject request
+type DataRequestType string
+
+const (
+	DataRequestExport  DataRequestType = "export"  // Archive of all personal data tied to a user
+	DataRequestErasure DataRequestType = "erasure" // Anonymization of a user and their message authorship
+)
+
+// DataRequestStatus tracks the progress of a data subject request job
+type DataRequestStatus string
+
+const (
+	DataRequestPending   DataRequestStatus = "pending"
+	DataRequestRunning   DataRequestStatus = "running"
+	DataRequestCompleted DataRequestStatus = "completed"
+	DataRequestFailed    DataRequestStatus = "failed"
+)
+
+// DataRequest represents a GDPR data subject request processed as a background job
+type DataRequest struct {
+	commonModels.BaseModel
+	Type        DataRequestType   `json:"type" gorm:"index"`
+	Status      DataRequestStatus `json:"status" gorm:"index;default:'pending'"`
+	SubjectID   string            `json:"subject_id" gorm:"index"` // User the request is about
+	Error       string            `json:"error,omitempty"`
+	StartedAt   *time.Time        `json:"started_at,omitempty"`
+	CompletedAt *time.Time        `json:"completed_at,omitempty"`
+	ArchiveName string            `json:"archive_name,omitempty"`
+	Archive     []byte            `json:"-" gorm:"type:bytea;serializer:encrypted"` // Zip archive produced by export requests, encrypted at rest
+}
+
+// TableName specifies the table name for the DataRequest model
+func (DataRequest) TableName() string {
+	return "data_requests"
+}
+
+// IsFinished reports whether the job has stopped, successfully or not
+func (r *DataRequest) IsFinished() bool {
+	return r.Status == DataRequestCompleted || r.Status == DataRequestFailed
+}
@@ -0,0 +1,49 @@
+package dto
+
+import (
+	"thothix-backend/internal/shared/dto"
+)
+
+// === GDPR DTOs ===
+
+// DataRequestDto represents a data subject request job in API responses
+type DataRequestDto struct {
+	ID          string `json:"id"`
+	Type        string `json:"type"`
+	Status      string `json:"status"`
+	SubjectID   string `json:"subject_id"`
+	Error       string `json:"error,omitempty"`
+	ArchiveName string `json:"archive_name,omitempty"`
+	RequestedBy string `json:"requested_by,omitempty"`
+	StartedAt   string `json:"started_at,omitempty"`
+	CompletedAt string `json:"completed_at,omitempty"`
+	CreatedAt   string `json:"created_at"`
+}
+
+// DataArchiveDto represents a completed export archive ready for download
+type DataArchiveDto struct {
+	Name    string
+	Content []byte
+}
+
+// DataRequestResponse wraps a single DataRequestDto response
+type DataRequestResponse struct {
+	*dto.Response[*DataRequestDto]
+}
+
+func NewDataRequestResponse(producer func() dto.Validation[*DataRequestDto]) *DataRequestResponse {
+	return &DataRequestResponse{
+		Response: dto.NewResponse(producer),
+	}
+}
+
+// DataArchiveResponse wraps an export archive download
+type DataArchiveResponse struct {
+	*dto.Response[*DataArchiveDto]
+}
+
+func NewDataArchiveResponse(producer func() dto.Validation[*DataArchiveDto]) *DataArchiveResponse {
+	return &DataArchiveResponse{
+		Response: dto.NewResponse(producer),
+	}
+}
@@ -0,0 +1,186 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	gdprDto "thothix-backend/internal/gdpr/dto"
+	"thothix-backend/internal/gdpr/service"
+	"thothix-backend/internal/shared/constants"
+	"thothix-backend/internal/shared/dto"
+	"thothix-backend/internal/shared/handlers"
+)
+
+type GDPRHandler struct {
+	gdprService service.GDPRServiceInterface
+}
+
+func NewGDPRHandler(gdprService service.GDPRServiceInterface) *GDPRHandler {
+	return &GDPRHandler{
+		gdprService: gdprService,
+	}
+}
+
+// RequestExport godoc
+// @Summary Export a user's personal data
+// @Description Start a job building a zip archive of a user's profile, messages, files, memberships and roles (platform admin only)
+// @Tags gdpr
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "User ID"
+// @Success 202 {object} gdprDto.DataRequestDto
+// @Failure 400 {object} dto.ErrorViewModel
+// @Failure 404 {object} dto.ErrorViewModel
+// @Failure 409 {object} dto.ErrorViewModel
+// @Failure 500 {object} dto.ErrorViewModel
+// @Router /gdpr/users/{id}/export [post]
+func (h *GDPRHandler) RequestExport(c *gin.Context) {
+	userID := c.Param("id")
+	h.respondWithRequest(c, h.scopedService(c).RequestExport(userID), "start export", userID)
+}
+
+// RequestErasure godoc
+// @Summary Erase a user's personal data
+// @Description Start a job anonymizing a user and reassigning their messages to a tombstone identity (platform admin only)
+// @Tags gdpr
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "User ID"
+// @Success 202 {object} gdprDto.DataRequestDto
+// @Failure 400 {object} dto.ErrorViewModel
+// @Failure 404 {object} dto.ErrorViewModel
+// @Failure 409 {object} dto.ErrorViewModel
+// @Failure 500 {object} dto.ErrorViewModel
+// @Router /gdpr/users/{id}/erasure [post]
+func (h *GDPRHandler) RequestErasure(c *gin.Context) {
+	userID := c.Param("id")
+	h.respondWithRequest(c, h.scopedService(c).RequestErasure(userID), "start erasure", userID)
+}
+
+// GetDataRequest godoc
+// @Summary Get a data request
+// @Description Get the status of an export or erasure job (platform admin only)
+// @Tags gdpr
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Data request ID"
+// @Success 200 {object} gdprDto.DataRequestDto
+// @Failure 404 {object} dto.ErrorViewModel
+// @Failure 500 {object} dto.ErrorViewModel
+// @Router /gdpr/requests/{id} [get]
+func (h *GDPRHandler) GetDataRequest(c *gin.Context) {
+	wrapper := handlers.WrapContext(c)
+	requestID := c.Param("id")
+
+	response := h.scopedService(c).GetDataRequest(requestID)
+
+	response.Match(
+		// Exception case
+		func(err error) interface{} {
+			wrapper.SystemErrorResponse(err, "Failed to retrieve data request with ID: %s", requestID)
+			return nil
+		},
+		// Success case
+		func(result *gdprDto.DataRequestDto) interface{} {
+			wrapper.SuccessResponse(result)
+			return nil
+		},
+		// Validation failure case
+		func(errors []dto.Error) interface{} {
+			if len(errors) > 0 && errors[0].Code == constants.NotFoundError {
+				wrapper.NotFoundErrorResponse("Data request", requestID)
+			} else {
+				wrapper.ValidationErrorResponse(errors, "Data request retrieval validation failed for ID: %s", requestID)
+			}
+			return nil
+		},
+	)
+}
+
+// DownloadArchive godoc
+// @Summary Download an export archive
+// @Description Download the zip archive produced by a completed export job (platform admin only)
+// @Tags gdpr
+// @Produce application/zip
+// @Security BearerAuth
+// @Param id path string true "Data request ID"
+// @Success 200 {file} file
+// @Failure 404 {object} dto.ErrorViewModel
+// @Failure 409 {object} dto.ErrorViewModel
+// @Failure 500 {object} dto.ErrorViewModel
+// @Router /gdpr/requests/{id}/archive [get]
+func (h *GDPRHandler) DownloadArchive(c *gin.Context) {
+	wrapper := handlers.WrapContext(c)
+	requestID := c.Param("id")
+
+	response := h.scopedService(c).GetArchive(requestID)
+
+	response.Match(
+		// Exception case
+		func(err error) interface{} {
+			wrapper.SystemErrorResponse(err, "Failed to retrieve export archive for request ID: %s", requestID)
+			return nil
+		},
+		// Success case
+		func(result *gdprDto.DataArchiveDto) interface{} {
+			c.Header("Content-Disposition", `attachment; filename="`+result.Name+`"`)
+			c.Data(http.StatusOK, "application/zip", result.Content)
+			return nil
+		},
+		// Validation failure case
+		func(errors []dto.Error) interface{} {
+			switch {
+			case len(errors) > 0 && errors[0].Code == constants.NotFoundError:
+				wrapper.NotFoundErrorResponse("Export request", requestID)
+			case len(errors) > 0 && errors[0].Code == constants.ConflictError:
+				wrapper.ConflictErrorResponse(errors[0].Message)
+			default:
+				wrapper.ValidationErrorResponse(errors, "Export archive validation failed for request ID: %s", requestID)
+			}
+			return nil
+		},
+	)
+}
+
+// respondWithRequest maps the result of starting a data request job to an HTTP response
+func (h *GDPRHandler) respondWithRequest(c *gin.Context, response *gdprDto.DataRequestResponse, action, userID string) {
+	wrapper := handlers.WrapContext(c)
+
+	response.Match(
+		// Exception case
+		func(err error) interface{} {
+			wrapper.SystemErrorResponse(err, "Failed to %s for user ID: %s", action, userID)
+			return nil
+		},
+		// Success case
+		func(result *gdprDto.DataRequestDto) interface{} {
+			wrapper.AcceptedResponse(result)
+			return nil
+		},
+		// Validation failure case
+		func(errors []dto.Error) interface{} {
+			switch {
+			case len(errors) > 0 && errors[0].Code == constants.UserNotFoundError:
+				wrapper.NotFoundErrorResponse("User", userID)
+			case len(errors) > 0 && errors[0].Code == constants.ConflictError:
+				wrapper.ConflictErrorResponse(errors[0].Message)
+			default:
+				wrapper.ValidationErrorResponse(errors, "Failed to %s for user ID: %s", action, userID)
+			}
+			return nil
+		},
+	)
+}
+
+// scopedService binds the service to the request context when supported,
+// so jobs and their changes are attributed to the requesting admin in the audit log
+func (h *GDPRHandler) scopedService(c *gin.Context) service.GDPRServiceInterface {
+	if aware, ok := h.gdprService.(service.ContextAwareGDPRService); ok {
+		return aware.WithContext(c.Request.Context())
+	}
+	return h.gdprService
+}
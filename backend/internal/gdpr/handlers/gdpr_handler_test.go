@@ -0,0 +1,135 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	gdprDto "thothix-backend/internal/gdpr/dto"
+	"thothix-backend/internal/shared/dto"
+)
+
+// MockGDPRService is a mock implementation of the GDPRService
+type MockGDPRService struct {
+	mock.Mock
+}
+
+func (m *MockGDPRService) RequestExport(subjectID string) *gdprDto.DataRequestResponse {
+	args := m.Called(subjectID)
+	return args.Get(0).(*gdprDto.DataRequestResponse)
+}
+
+func (m *MockGDPRService) RequestErasure(subjectID string) *gdprDto.DataRequestResponse {
+	args := m.Called(subjectID)
+	return args.Get(0).(*gdprDto.DataRequestResponse)
+}
+
+func (m *MockGDPRService) GetDataRequest(requestID string) *gdprDto.DataRequestResponse {
+	args := m.Called(requestID)
+	return args.Get(0).(*gdprDto.DataRequestResponse)
+}
+
+func (m *MockGDPRService) GetArchive(requestID string) *gdprDto.DataArchiveResponse {
+	args := m.Called(requestID)
+	return args.Get(0).(*gdprDto.DataArchiveResponse)
+}
+
+type GDPRHandlerTestSuite struct {
+	suite.Suite
+	mockService *MockGDPRService
+	router      *gin.Engine
+}
+
+func (suite *GDPRHandlerTestSuite) SetupSuite() {
+	gin.SetMode(gin.TestMode)
+}
+
+func (suite *GDPRHandlerTestSuite) SetupTest() {
+	suite.mockService = new(MockGDPRService)
+	handler := NewGDPRHandler(suite.mockService)
+
+	suite.router = gin.New()
+	suite.router.POST("/gdpr/users/:id/export", handler.RequestExport)
+	suite.router.POST("/gdpr/users/:id/erasure", handler.RequestErasure)
+	suite.router.GET("/gdpr/requests/:id/archive", handler.DownloadArchive)
+}
+
+func (suite *GDPRHandlerTestSuite) TestRequestExport_Accepted() {
+	// Arrange
+	mockResponse := gdprDto.NewDataRequestResponse(func() dto.Validation[*gdprDto.DataRequestDto] {
+		return dto.Success(&gdprDto.DataRequestDto{ID: "request-1", Type: "export", Status: "pending", SubjectID: "user-1"})
+	})
+
+	suite.mockService.On("RequestExport", "user-1").Return(mockResponse)
+
+	// Act
+	req, _ := http.NewRequest("POST", "/gdpr/users/user-1/export", nil)
+	w := httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(suite.T(), http.StatusAccepted, w.Code)
+	assert.Contains(suite.T(), w.Body.String(), "request-1")
+}
+
+func (suite *GDPRHandlerTestSuite) TestRequestErasure_AlreadyInProgress() {
+	// Arrange
+	mockResponse := gdprDto.NewDataRequestResponse(func() dto.Validation[*gdprDto.DataRequestDto] {
+		return dto.Invalid[*gdprDto.DataRequestDto](dto.NewError("CONFLICT", "An erasure request is already in progress for this user", nil))
+	})
+
+	suite.mockService.On("RequestErasure", "user-1").Return(mockResponse)
+
+	// Act
+	req, _ := http.NewRequest("POST", "/gdpr/users/user-1/erasure", nil)
+	w := httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(suite.T(), http.StatusConflict, w.Code)
+}
+
+func (suite *GDPRHandlerTestSuite) TestDownloadArchive_Success() {
+	// Arrange
+	mockResponse := gdprDto.NewDataArchiveResponse(func() dto.Validation[*gdprDto.DataArchiveDto] {
+		return dto.Success(&gdprDto.DataArchiveDto{Name: "export.zip", Content: []byte("zip-content")})
+	})
+
+	suite.mockService.On("GetArchive", "request-1").Return(mockResponse)
+
+	// Act
+	req, _ := http.NewRequest("GET", "/gdpr/requests/request-1/archive", nil)
+	w := httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(suite.T(), http.StatusOK, w.Code)
+	assert.Equal(suite.T(), "application/zip", w.Header().Get("Content-Type"))
+	assert.Contains(suite.T(), w.Header().Get("Content-Disposition"), "export.zip")
+	assert.Equal(suite.T(), "zip-content", w.Body.String())
+}
+
+func (suite *GDPRHandlerTestSuite) TestDownloadArchive_NotReady() {
+	// Arrange
+	mockResponse := gdprDto.NewDataArchiveResponse(func() dto.Validation[*gdprDto.DataArchiveDto] {
+		return dto.Invalid[*gdprDto.DataArchiveDto](dto.NewError("CONFLICT", "Export archive is not ready", nil))
+	})
+
+	suite.mockService.On("GetArchive", "request-1").Return(mockResponse)
+
+	// Act
+	req, _ := http.NewRequest("GET", "/gdpr/requests/request-1/archive", nil)
+	w := httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(suite.T(), http.StatusConflict, w.Code)
+}
+
+func TestGDPRHandlerTestSuite(t *testing.T) {
+	suite.Run(t, new(GDPRHandlerTestSuite))
+}
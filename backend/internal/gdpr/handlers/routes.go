@@ -0,0 +1,21 @@
+package handlers
+
+import (
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"thothix-backend/internal/gdpr/service"
+	"thothix-backend/internal/middleware"
+)
+
+// RegisterGDPRRoutes registers the data subject request routes, restricted to platform administrators
+func RegisterGDPRRoutes(router *gin.RouterGroup, db *gorm.DB) {
+	gdprService := service.NewGDPRService(db)
+	gdprHandler := NewGDPRHandler(gdprService)
+
+	gdpr := router.Group("/gdpr")
+	gdpr.Use(middleware.RequirePlatformAdmin(db))
+	gdpr.POST("/users/:id/export", gdprHandler.RequestExport)
+	gdpr.POST("/users/:id/erasure", gdprHandler.RequestErasure)
+	gdpr.GET("/requests/:id", gdprHandler.GetDataRequest)
+	gdpr.GET("/requests/:id/archive", gdprHandler.DownloadArchive)
+}
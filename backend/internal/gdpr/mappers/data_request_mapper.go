@@ -0,0 +1,44 @@
+package mappers
+
+import (
+	"time"
+
+	"thothix-backend/internal/gdpr/domain"
+	gdprDto "thothix-backend/internal/gdpr/dto"
+)
+
+// DataRequestMapper handles conversion between DataRequest models and DTOs
+type DataRequestMapper struct{}
+
+// NewDataRequestMapper creates a new DataRequestMapper instance
+func NewDataRequestMapper() *DataRequestMapper {
+	return &DataRequestMapper{}
+}
+
+// ModelToDto converts a DataRequest model to DataRequestDto
+func (m *DataRequestMapper) ModelToDto(request *domain.DataRequest) *gdprDto.DataRequestDto {
+	if request == nil {
+		return nil
+	}
+
+	return &gdprDto.DataRequestDto{
+		ID:          request.ID,
+		Type:        string(request.Type),
+		Status:      string(request.Status),
+		SubjectID:   request.SubjectID,
+		Error:       request.Error,
+		ArchiveName: request.ArchiveName,
+		RequestedBy: request.CreatedBy,
+		StartedAt:   formatOptionalTime(request.StartedAt),
+		CompletedAt: formatOptionalTime(request.CompletedAt),
+		CreatedAt:   request.CreatedAt.Format(time.RFC3339),
+	}
+}
+
+// formatOptionalTime formats a nullable timestamp, returning "" when unset
+func formatOptionalTime(value *time.Time) string {
+	if value == nil {
+		return ""
+	}
+	return value.Format(time.RFC3339)
+}
@@ -0,0 +1,452 @@
+package service
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"gorm.io/gorm"
+
+	auditDomain "thothix-backend/internal/audit/domain"
+	auditHooks "thothix-backend/internal/audit/hooks"
+	blockingDomain "thothix-backend/internal/blocking/domain"
+	bookmarkDomain "thothix-backend/internal/bookmarks/domain"
+	chatDomain "thothix-backend/internal/chat/domain"
+	"thothix-backend/internal/gdpr/domain"
+	gdprDto "thothix-backend/internal/gdpr/dto"
+	"thothix-backend/internal/gdpr/mappers"
+	"thothix-backend/internal/jobs/queue"
+	messageDomain "thothix-backend/internal/message/domain"
+	moderationDomain "thothix-backend/internal/moderation/domain"
+	projectDomain "thothix-backend/internal/project/domain"
+	schedulingDomain "thothix-backend/internal/scheduling/domain"
+	"thothix-backend/internal/shared/constants"
+	"thothix-backend/internal/shared/dto"
+	"thothix-backend/internal/shared/logging"
+	sharedMiddleware "thothix-backend/internal/shared/middleware"
+	sharedModels "thothix-backend/internal/shared/models"
+	usersDomain "thothix-backend/internal/users/domain"
+	usersService "thothix-backend/internal/users/service"
+)
+
+// processRequestPayload identifies the data request a job runs
+type processRequestPayload struct {
+	RequestID string `json:"request_id"`
+}
+
+// processRequestKind runs data requests on their own queue, so a large export does not hold up other jobs
+// A request whose worker died is run again from the start; export and erasure are both idempotent
+var processRequestKind *queue.Kind[processRequestPayload]
+
+func init() {
+	processRequestKind = queue.Register(queue.Default, "gdpr.process_request", queue.Options{
+		Queue:       "gdpr",
+		MaxAttempts: 3,
+		Timeout:     time.Hour,
+	}, processRequestJob)
+}
+
+type GDPRService struct {
+	db      *gorm.DB
+	mapper  *mappers.DataRequestMapper
+	enqueue func(db *gorm.DB, request *domain.DataRequest) error // Schedules the job of a request; tests replace it to run synchronously
+}
+
+func NewGDPRService(db *gorm.DB) *GDPRService {
+	return &GDPRService{
+		db:      db,
+		mapper:  mappers.NewDataRequestMapper(),
+		enqueue: enqueueRequest,
+	}
+}
+
+// WithContext returns a copy of the service whose database session carries ctx
+// Jobs are attributed to the actor of ctx, who becomes the acting user while they run
+func (s *GDPRService) WithContext(ctx context.Context) *GDPRService {
+	return &GDPRService{
+		db:      s.db.WithContext(ctx),
+		mapper:  s.mapper,
+		enqueue: s.enqueue,
+	}
+}
+
+// enqueueRequest stores the job of a data request; a request is never queued twice
+func enqueueRequest(db *gorm.DB, request *domain.DataRequest) error {
+	_, err := processRequestKind.Enqueue(db, processRequestPayload{RequestID: request.ID}, queue.Unique(request.ID))
+	return err
+}
+
+// processRequestJob runs a pending data request, or resumes one whose previous attempt was abandoned
+func processRequestJob(ctx context.Context, db *gorm.DB, payload processRequestPayload) error {
+	var request domain.DataRequest
+	if err := db.Where("id = ?", payload.RequestID).First(&request).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return queue.Permanent(err)
+		}
+		return err
+	}
+	if request.Status != domain.DataRequestPending && request.Status != domain.DataRequestRunning {
+		return nil
+	}
+	return NewGDPRService(db).process(&request)
+}
+
+// RequestExport starts a job building an archive of all personal data tied to a user
+func (s *GDPRService) RequestExport(subjectID string) *gdprDto.DataRequestResponse {
+	return gdprDto.NewDataRequestResponse(func() dto.Validation[*gdprDto.DataRequestDto] {
+		return s.startRequest(domain.DataRequestExport, subjectID)
+	})
+}
+
+// RequestErasure starts a job anonymizing a user and reassigning their messages to the tombstone identity
+func (s *GDPRService) RequestErasure(subjectID string) *gdprDto.DataRequestResponse {
+	return gdprDto.NewDataRequestResponse(func() dto.Validation[*gdprDto.DataRequestDto] {
+		return s.startRequest(domain.DataRequestErasure, subjectID)
+	})
+}
+
+// GetDataRequest retrieves the status of a data subject request
+func (s *GDPRService) GetDataRequest(requestID string) *gdprDto.DataRequestResponse {
+	return gdprDto.NewDataRequestResponse(func() dto.Validation[*gdprDto.DataRequestDto] {
+		if requestID == "" {
+			return dto.Failure[*gdprDto.DataRequestDto](dto.NewError(constants.ValidationError, "Request ID cannot be empty", nil))
+		}
+
+		var request domain.DataRequest
+		if err := s.db.Omit("archive").Scopes(subjectsInWorkspace).Where("id = ?", requestID).First(&request).Error; err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return dto.Invalid[*gdprDto.DataRequestDto](dto.NewError(constants.NotFoundError, "Data request not found", nil))
+			}
+			panic(err)
+		}
+
+		return dto.Success(s.mapper.ModelToDto(&request))
+	})
+}
+
+// GetArchive returns the archive produced by a completed export request
+func (s *GDPRService) GetArchive(requestID string) *gdprDto.DataArchiveResponse {
+	return gdprDto.NewDataArchiveResponse(func() dto.Validation[*gdprDto.DataArchiveDto] {
+		if requestID == "" {
+			return dto.Failure[*gdprDto.DataArchiveDto](dto.NewError(constants.ValidationError, "Request ID cannot be empty", nil))
+		}
+
+		var request domain.DataRequest
+		if err := s.db.Scopes(subjectsInWorkspace).Where("id = ? AND type = ?", requestID, domain.DataRequestExport).First(&request).Error; err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return dto.Invalid[*gdprDto.DataArchiveDto](dto.NewError(constants.NotFoundError, "Export request not found", nil))
+			}
+			panic(err)
+		}
+
+		if request.Status != domain.DataRequestCompleted {
+			return dto.Invalid[*gdprDto.DataArchiveDto](dto.NewError(constants.ConflictError, "Export archive is not ready", map[string]string{"status": string(request.Status)}))
+		}
+
+		return dto.Success(&gdprDto.DataArchiveDto{Name: request.ArchiveName, Content: request.Archive})
+	})
+}
+
+// startRequest validates the subject, stores a pending request and schedules its job
+func (s *GDPRService) startRequest(requestType domain.DataRequestType, subjectID string) dto.Validation[*gdprDto.DataRequestDto] {
+	var validationErrors []dto.Error
+
+	// Validation
+	if subjectID == "" {
+		validationErrors = append(validationErrors, dto.NewError(constants.ValidationError, "User ID cannot be empty", nil))
+	}
+
+	if subjectID == usersDomain.TombstoneUserID {
+		validationErrors = append(validationErrors, dto.NewError(constants.ValidationError, "The tombstone identity cannot be the subject of a data request", nil))
+	}
+
+	if len(validationErrors) > 0 {
+		return dto.Failure[*gdprDto.DataRequestDto](validationErrors...)
+	}
+
+	// Within a workspace only its members can be the subject of a request
+	var subject usersDomain.User
+	if err := s.db.Scopes(sharedModels.WorkspaceMembers).Where("users.id = ?", subjectID).First(&subject).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return dto.Invalid[*gdprDto.DataRequestDto](dto.NewError(constants.UserNotFoundError, "User not found", nil))
+		}
+		panic(err)
+	}
+
+	// Only one job of each type may be in progress for a user
+	var inProgress int64
+	if err := s.db.Model(&domain.DataRequest{}).
+		Where("subject_id = ? AND type = ? AND status IN ?", subjectID, requestType, []domain.DataRequestStatus{domain.DataRequestPending, domain.DataRequestRunning}).
+		Count(&inProgress).Error; err != nil {
+		panic(err)
+	}
+	if inProgress > 0 {
+		return dto.Invalid[*gdprDto.DataRequestDto](dto.NewError(constants.ConflictError, fmt.Sprintf("An %s request is already in progress for this user", requestType), nil))
+	}
+
+	request := domain.DataRequest{
+		Type:      requestType,
+		Status:    domain.DataRequestPending,
+		SubjectID: subjectID,
+	}
+	// The job commits with the request, so a pending request always has a job to run it
+	if err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&request).Error; err != nil {
+			return err
+		}
+		return s.enqueue(tx, &request)
+	}); err != nil {
+		panic(err)
+	}
+
+	return dto.Success(s.mapper.ModelToDto(&request))
+}
+
+// subjectsInWorkspace limits data requests to subjects who are members of the workspace carried by the db context
+// Requests without a workspace are left unfiltered
+func subjectsInWorkspace(db *gorm.DB) *gorm.DB {
+	if _, ok := sharedMiddleware.WorkspaceIDFromContext(db.Statement.Context); !ok {
+		return db
+	}
+	members := db.Session(&gorm.Session{NewDB: true}).Model(&usersDomain.User{}).Select("users.id::text").Scopes(sharedModels.WorkspaceMembers)
+	return db.Where("subject_id IN (?)", members)
+}
+
+// process runs a data request and records its outcome on the request
+// A failed export or erasure fails the request; the returned error only reports that the outcome could not be recorded
+func (s *GDPRService) process(request *domain.DataRequest) error {
+	ctx := context.Background()
+	if s.db.Statement.Context != nil {
+		ctx = s.db.Statement.Context
+	}
+	ctx = logging.With(ctx, slog.String("data_request_id", request.ID), slog.String("data_request_type", string(request.Type)))
+	logger := logging.FromContext(ctx)
+	db := s.db.WithContext(ctx)
+
+	startedAt := time.Now()
+	if err := db.Model(request).Updates(map[string]interface{}{"status": domain.DataRequestRunning, "started_at": startedAt}).Error; err != nil {
+		return fmt.Errorf("start data request: %w", err)
+	}
+
+	err := func() (err error) {
+		// Jobs run outside the Response pattern, so panics are converted here
+		defer func() {
+			if r := recover(); r != nil {
+				err = fmt.Errorf("panic: %v", r)
+			}
+		}()
+
+		switch request.Type {
+		case domain.DataRequestExport:
+			return s.export(db, request)
+		case domain.DataRequestErasure:
+			return s.erase(db, request)
+		default:
+			return fmt.Errorf("unknown data request type %q", request.Type)
+		}
+	}()
+
+	completedAt := time.Now()
+	updates := map[string]interface{}{"status": domain.DataRequestCompleted, "completed_at": completedAt}
+	if err != nil {
+		logger.Error("Data request failed", slog.String("subject_id", request.SubjectID), slog.Any("error", err))
+		updates = map[string]interface{}{"status": domain.DataRequestFailed, "completed_at": completedAt, "error": err.Error()}
+	}
+	if err := db.Model(request).Updates(updates).Error; err != nil {
+		return fmt.Errorf("record outcome of data request: %w", err)
+	}
+	return nil
+}
+
+// export builds a zip archive with one JSON document per category of personal data
+func (s *GDPRService) export(db *gorm.DB, request *domain.DataRequest) error {
+	subjectID := request.SubjectID
+
+	var user usersDomain.User
+	if err := db.Scopes(sharedModels.WorkspaceMembers).Where("users.id = ?", subjectID).First(&user).Error; err != nil {
+		return err
+	}
+
+	var messages []messageDomain.Message
+	if err := db.Where("sender_id = ? OR receiver_id = ?", subjectID, subjectID).Order("created_at").Find(&messages).Error; err != nil {
+		return err
+	}
+
+	var files []messageDomain.File
+	sentMessages := db.Model(&messageDomain.Message{}).Select("id::text").Where("sender_id = ?", subjectID)
+	if err := db.Where("message_id IN (?)", sentMessages).Find(&files).Error; err != nil {
+		return err
+	}
+
+	var channelMemberships []chatDomain.ChannelMember
+	if err := db.Where("user_id = ?", subjectID).Find(&channelMemberships).Error; err != nil {
+		return err
+	}
+
+	var projectMemberships []projectDomain.ProjectMember
+	if err := db.Where("user_id = ?", subjectID).Find(&projectMemberships).Error; err != nil {
+		return err
+	}
+
+	var roles []sharedModels.UserRole
+	if err := db.Where("user_id = ?", subjectID).Find(&roles).Error; err != nil {
+		return err
+	}
+
+	var reminders []messageDomain.Reminder
+	if err := db.Where("user_id = ?", subjectID).Find(&reminders).Error; err != nil {
+		return err
+	}
+
+	var savedItems []bookmarkDomain.SavedItem
+	if err := db.Where("user_id = ?", subjectID).Find(&savedItems).Error; err != nil {
+		return err
+	}
+
+	var scheduledMessages []schedulingDomain.ScheduledMessage
+	if err := db.Where("sender_id = ?", subjectID).Find(&scheduledMessages).Error; err != nil {
+		return err
+	}
+
+	var blocks []blockingDomain.Block
+	if err := db.Where("blocker_id = ?", subjectID).Find(&blocks).Error; err != nil {
+		return err
+	}
+
+	documents := []struct {
+		name    string
+		content interface{}
+	}{
+		{"profile.json", user},
+		{"messages.json", messages},
+		{"files.json", files},
+		{"memberships.json", map[string]interface{}{"channels": channelMemberships, "projects": projectMemberships}},
+		{"roles.json", map[string]interface{}{"system_role": user.SystemRole, "assignments": roles}},
+		{"reminders.json", reminders},
+		{"saved_items.json", savedItems},
+		{"scheduled_messages.json", scheduledMessages},
+		{"blocks.json", blocks},
+	}
+
+	var buf bytes.Buffer
+	archive := zip.NewWriter(&buf)
+	for _, document := range documents {
+		data, err := json.MarshalIndent(document.content, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to encode %s: %w", document.name, err)
+		}
+		writer, err := archive.Create(document.name)
+		if err != nil {
+			return err
+		}
+		if _, err := writer.Write(data); err != nil {
+			return err
+		}
+	}
+	if err := archive.Close(); err != nil {
+		return err
+	}
+
+	request.Archive = buf.Bytes()
+	request.ArchiveName = fmt.Sprintf("thothix-export-%s-%s.zip", subjectID, time.Now().UTC().Format("20060102"))
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		// A struct update, unlike a map, goes through the serializer encrypting the archive
+		if err := tx.Model(request).Updates(&domain.DataRequest{Archive: request.Archive, ArchiveName: request.ArchiveName}).Error; err != nil {
+			return err
+		}
+		return auditHooks.Record(tx, auditDomain.AuditActionExport, usersDomain.User{}.TableName(), subjectID, map[string]interface{}{
+			"data_request_id": request.ID,
+			"archive_name":    request.ArchiveName,
+			"messages":        len(messages),
+			"files":           len(files),
+		})
+	})
+}
+
+// erase reassigns the user's messages to the tombstone identity and purges the user
+func (s *GDPRService) erase(db *gorm.DB, request *domain.DataRequest) error {
+	subjectID := request.SubjectID
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		tombstone := usersDomain.NewTombstoneUser()
+		if err := tx.FirstOrCreate(tombstone, "id = ?", usersDomain.TombstoneUserID).Error; err != nil {
+			return err
+		}
+
+		sent := tx.Model(&messageDomain.Message{}).Where("sender_id = ?", subjectID).Update("sender_id", usersDomain.TombstoneUserID)
+		if sent.Error != nil {
+			return sent.Error
+		}
+		received := tx.Model(&messageDomain.Message{}).Where("receiver_id = ?", subjectID).Update("receiver_id", usersDomain.TombstoneUserID)
+		if received.Error != nil {
+			return received.Error
+		}
+		if err := tx.Where("user_id = ?", subjectID).Delete(&messageDomain.Reminder{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("user_id = ?", subjectID).Delete(&bookmarkDomain.SavedItem{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("sender_id = ?", subjectID).Delete(&schedulingDomain.ScheduledMessage{}).Error; err != nil {
+			return err
+		}
+		// Messages others scheduled to the subject fail at delivery, since the tombstone is no workspace member
+		if err := tx.Model(&schedulingDomain.ScheduledMessage{}).Where("receiver_id = ?", subjectID).Update("receiver_id", usersDomain.TombstoneUserID).Error; err != nil {
+			return err
+		}
+		// Reports and suspensions stay as moderation records, attributed to the tombstone,
+		// without the excerpt of the subject's message or the details the subject wrote
+		if err := tx.Model(&moderationDomain.Report{}).Where("author_id = ?", subjectID).
+			Updates(map[string]interface{}{"author_id": usersDomain.TombstoneUserID, "excerpt": ""}).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&moderationDomain.Report{}).Where("reporter_id = ?", subjectID).
+			Updates(map[string]interface{}{"reporter_id": usersDomain.TombstoneUserID, "details": ""}).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&moderationDomain.Suspension{}).Where("user_id = ?", subjectID).Update("user_id", usersDomain.TombstoneUserID).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("user_id = ?", subjectID).Delete(&moderationDomain.Moderator{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("blocker_id = ? OR blocked_id = ?", subjectID, subjectID).Delete(&blockingDomain.Block{}).Error; err != nil {
+			return err
+		}
+		// Earlier export archives hold a copy of the erased data
+		if err := tx.Where("subject_id = ? AND type = ?", subjectID, domain.DataRequestExport).Delete(&domain.DataRequest{}).Error; err != nil {
+			return err
+		}
+
+		// Anonymize the profile and drop memberships and roles
+		var purgeErr error
+		usersService.NewUserService(tx).PurgeUser(subjectID).Match(
+			func(err error) interface{} {
+				purgeErr = err
+				return nil
+			},
+			func(string) interface{} {
+				return nil
+			},
+			func(errors []dto.Error) interface{} {
+				purgeErr = errors[0]
+				return nil
+			},
+		)
+		if purgeErr != nil {
+			return purgeErr
+		}
+
+		return auditHooks.Record(tx, auditDomain.AuditActionErase, usersDomain.User{}.TableName(), subjectID, map[string]interface{}{
+			"data_request_id":   request.ID,
+			"tombstone_id":      usersDomain.TombstoneUserID,
+			"messages_sent":     sent.RowsAffected,
+			"messages_received": received.RowsAffected,
+		})
+	})
+}
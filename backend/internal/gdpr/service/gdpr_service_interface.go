@@ -0,0 +1,20 @@
+package service
+
+import (
+	"context"
+
+	gdprDto "thothix-backend/internal/gdpr/dto"
+)
+
+// GDPRServiceInterface defines the contract for data subject request operations using Response pattern
+type GDPRServiceInterface interface {
+	RequestExport(subjectID string) *gdprDto.DataRequestResponse
+	RequestErasure(subjectID string) *gdprDto.DataRequestResponse
+	GetDataRequest(requestID string) *gdprDto.DataRequestResponse
+	GetArchive(requestID string) *gdprDto.DataArchiveResponse
+}
+
+// ContextAwareGDPRService is implemented by services that can bind their database session to a request context
+type ContextAwareGDPRService interface {
+	WithContext(ctx context.Context) *GDPRService
+}
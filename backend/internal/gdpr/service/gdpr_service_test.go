@@ -0,0 +1,196 @@
+package service
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/gorm"
+	auditDomain "thothix-backend/internal/audit/domain"
+	auditHooks "thothix-backend/internal/audit/hooks"
+	blockingDomain "thothix-backend/internal/blocking/domain"
+	bookmarkDomain "thothix-backend/internal/bookmarks/domain"
+	chatDomain "thothix-backend/internal/chat/domain"
+	"thothix-backend/internal/encryption"
+	"thothix-backend/internal/gdpr/domain"
+	messageDomain "thothix-backend/internal/message/domain"
+	moderationDomain "thothix-backend/internal/moderation/domain"
+	projectDomain "thothix-backend/internal/project/domain"
+	schedulingDomain "thothix-backend/internal/scheduling/domain"
+	sharedMiddleware "thothix-backend/internal/shared/middleware"
+	sharedModels "thothix-backend/internal/shared/models"
+	sharedTesting "thothix-backend/internal/shared/testing"
+	usersDomain "thothix-backend/internal/users/domain"
+	workspaceDomain "thothix-backend/internal/workspace/domain"
+)
+
+type GDPRServiceTestSuite struct {
+	suite.Suite
+	container *sharedTesting.PostgresTestContainer
+}
+
+func (suite *GDPRServiceTestSuite) SetupSuite() {
+	suite.container = sharedTesting.GetSharedTestContainer(
+		suite.T(),
+		"gdpr/service",
+		[]interface{}{
+			&usersDomain.User{}, &sharedModels.UserRole{},
+			&chatDomain.ChannelMember{}, &projectDomain.ProjectMember{},
+			&messageDomain.Message{}, &messageDomain.File{}, &messageDomain.Reminder{},
+			&bookmarkDomain.SavedItem{},
+			&schedulingDomain.ScheduledMessage{},
+			&moderationDomain.Report{}, &moderationDomain.Suspension{}, &moderationDomain.Moderator{},
+			&blockingDomain.Block{}, &workspaceDomain.Invitation{},
+			&auditDomain.AuditLog{}, &domain.DataRequest{},
+		},
+	)
+	// Requests are attributed to the admin through the audit callbacks
+	assert.NoError(suite.T(), auditHooks.Register(suite.container.DB))
+}
+
+// newSyncService creates a service that runs jobs synchronously as the given admin
+func (suite *GDPRServiceTestSuite) newSyncService(db *gorm.DB, adminID string) *GDPRService {
+	service := NewGDPRService(db)
+	service.enqueue = func(db *gorm.DB, request *domain.DataRequest) error {
+		return NewGDPRService(db).process(request)
+	}
+	return service.WithContext(sharedMiddleware.WithUserID(context.Background(), adminID))
+}
+
+// createSubject stores a user with one sent message for the given test
+func (suite *GDPRServiceTestSuite) createSubject(db *gorm.DB, testName string) *usersDomain.User {
+	user := &usersDomain.User{Email: testName + "@example.com", Name: "User " + testName}
+	user.ID = uuid.New().String()
+	assert.NoError(suite.T(), db.Create(user).Error)
+
+	channelID := uuid.New().String()
+	message := &messageDomain.Message{SenderID: user.ID, ChannelID: &channelID, Content: "hello from " + testName}
+	assert.NoError(suite.T(), db.Create(message).Error)
+	return user
+}
+
+func (suite *GDPRServiceTestSuite) TestRequestExport_BuildsArchive() {
+	suite.container.WithTransaction(func(db *gorm.DB) {
+		// Arrange
+		user := suite.createSubject(db, "TestRequestExport_BuildsArchive")
+		service := suite.newSyncService(db, "admin-1")
+		wrapper, err := encryption.NewLocalWrapper(1, "app-secret", nil)
+		require.NoError(suite.T(), err)
+		index, err := encryption.NewBlindIndex("index-secret")
+		require.NoError(suite.T(), err)
+		encryption.SetDefault(encryption.NewKeyring(wrapper, index))
+		defer encryption.SetDefault(nil)
+
+		// Act
+		response := service.RequestExport(user.ID)
+
+		// Assert
+		request := sharedTesting.AssertSuccessWithValue(suite.T(), response.Response)
+		assert.Equal(suite.T(), "admin-1", request.RequestedBy)
+
+		archive := sharedTesting.AssertSuccessWithValue(suite.T(), service.GetArchive(request.ID).Response)
+		reader, err := zip.NewReader(bytes.NewReader(archive.Content), int64(len(archive.Content)))
+		assert.NoError(suite.T(), err)
+
+		names := make([]string, 0, len(reader.File))
+		for _, file := range reader.File {
+			names = append(names, file.Name)
+		}
+		var stored []byte
+		assert.NoError(suite.T(), db.Table("data_requests").Where("id = ?", request.ID).Select("archive").Row().Scan(&stored))
+		assert.True(suite.T(), encryption.IsEncrypted(string(stored)), "archives are encrypted at rest")
+
+		assert.ElementsMatch(suite.T(), []string{"profile.json", "messages.json", "files.json", "memberships.json", "roles.json", "reminders.json", "saved_items.json", "scheduled_messages.json", "blocks.json"}, names)
+
+		var exports int64
+		db.Model(&auditDomain.AuditLog{}).Where("action = ? AND entity_id = ?", auditDomain.AuditActionExport, user.ID).Count(&exports)
+		assert.Equal(suite.T(), int64(1), exports)
+	})
+}
+
+func (suite *GDPRServiceTestSuite) TestRequestErasure_RewritesAuthorship() {
+	suite.container.WithTransaction(func(db *gorm.DB) {
+		// Arrange
+		user := suite.createSubject(db, "TestRequestErasure_RewritesAuthorship")
+		assert.NoError(suite.T(), db.Create(&blockingDomain.Block{BlockerID: uuid.New().String(), BlockedID: user.ID, Type: blockingDomain.TypeBlock}).Error)
+		report := &moderationDomain.Report{WorkspaceID: uuid.New().String(), MessageID: uuid.New().String(), AuthorID: user.ID, Reason: moderationDomain.ReasonSpam, Excerpt: "hello from " + user.Name}
+		assert.NoError(suite.T(), db.Create(report).Error)
+		service := suite.newSyncService(db, "admin-1")
+		sharedTesting.AssertSuccessWithValue(suite.T(), service.RequestExport(user.ID).Response)
+
+		// Act
+		response := service.RequestErasure(user.ID)
+
+		// Assert
+		request := sharedTesting.AssertSuccessWithValue(suite.T(), response.Response)
+		status := sharedTesting.AssertSuccessWithValue(suite.T(), service.GetDataRequest(request.ID).Response)
+		assert.Equal(suite.T(), string(domain.DataRequestCompleted), status.Status)
+
+		var remaining int64
+		db.Model(&messageDomain.Message{}).Where("sender_id = ?", user.ID).Count(&remaining)
+		assert.Equal(suite.T(), int64(0), remaining)
+
+		var blocks int64
+		db.Model(&blockingDomain.Block{}).Where("blocked_id = ?", user.ID).Count(&blocks)
+		assert.Equal(suite.T(), int64(0), blocks)
+
+		var stored usersDomain.User
+		assert.NoError(suite.T(), db.Where("id = ?", user.ID).First(&stored).Error)
+		assert.True(suite.T(), stored.IsPurged())
+		assert.Empty(suite.T(), stored.Email)
+
+		var storedReport moderationDomain.Report
+		assert.NoError(suite.T(), db.First(&storedReport, "id = ?", report.ID).Error)
+		assert.Equal(suite.T(), usersDomain.TombstoneUserID, storedReport.AuthorID)
+		assert.Empty(suite.T(), storedReport.Excerpt)
+
+		var exports int64
+		db.Model(&domain.DataRequest{}).Where("subject_id = ? AND type = ?", user.ID, domain.DataRequestExport).Count(&exports)
+		assert.Equal(suite.T(), int64(0), exports)
+
+		var entries []auditDomain.AuditLog
+		assert.NoError(suite.T(), db.Where("entity_id = ?", user.ID).Find(&entries).Error)
+		assert.NotEmpty(suite.T(), entries)
+		for _, entry := range entries {
+			assert.NotContains(suite.T(), string(entry.Before)+string(entry.After), user.Email, entry.Action)
+			assert.NotContains(suite.T(), string(entry.Before)+string(entry.After), user.Name, entry.Action)
+		}
+	})
+}
+
+func (suite *GDPRServiceTestSuite) TestRequestExport_UserNotFound() {
+	suite.container.WithTransaction(func(db *gorm.DB) {
+		// Arrange
+		service := suite.newSyncService(db, "admin-1")
+
+		// Act
+		response := service.RequestExport(uuid.New().String())
+
+		// Assert
+		sharedTesting.AssertValidationErrorWithCode(suite.T(), response.Response, "USER_NOT_FOUND")
+	})
+}
+
+func (suite *GDPRServiceTestSuite) TestRequestExport_SubjectOutsideWorkspace() {
+	suite.container.WithTransaction(func(db *gorm.DB) {
+		// Arrange
+		user := suite.createSubject(db, "TestRequestExport_SubjectOutsideWorkspace")
+		ctx := sharedMiddleware.WithWorkspaceID(sharedMiddleware.WithUserID(context.Background(), "admin-1"), uuid.New().String())
+		service := NewGDPRService(db).WithContext(ctx)
+
+		// Act
+		response := service.RequestExport(user.ID)
+
+		// Assert
+		sharedTesting.AssertValidationErrorWithCode(suite.T(), response.Response, "USER_NOT_FOUND")
+	})
+}
+
+func TestGDPRServiceTestSuite(t *testing.T) {
+	suite.Run(t, new(GDPRServiceTestSuite))
+}
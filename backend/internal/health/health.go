@@ -0,0 +1,108 @@
+package health
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Check statuses
+const (
+	StatusUp   = "up"
+	StatusDown = "down"
+)
+
+// defaultTimeout bounds each check so a hung dependency cannot stall the probe
+const defaultTimeout = 2 * time.Second
+
+// CheckFunc reports a dependency as healthy by returning nil
+type CheckFunc func(ctx context.Context) error
+
+// CheckResult is the outcome of a single check
+type CheckResult struct {
+	Status    string  `json:"status"`
+	LatencyMS float64 `json:"latency_ms"`
+	Error     string  `json:"error,omitempty"`
+}
+
+// Report is the outcome of all checks
+type Report struct {
+	Status  string                 `json:"status"`
+	Version string                 `json:"version"`
+	Commit  string                 `json:"commit"`
+	Checks  map[string]CheckResult `json:"checks"`
+}
+
+// Checker runs the readiness checks registered by name
+type Checker struct {
+	mu      sync.RWMutex
+	checks  map[string]CheckFunc
+	timeout time.Duration
+}
+
+// NewChecker creates an empty Checker
+func NewChecker() *Checker {
+	return &Checker{checks: make(map[string]CheckFunc), timeout: defaultTimeout}
+}
+
+// Add registers a check, replacing any check with the same name
+func (c *Checker) Add(name string, check CheckFunc) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.checks[name] = check
+}
+
+// Run executes all checks concurrently; the report is up only when every check is
+func (c *Checker) Run(ctx context.Context) map[string]CheckResult {
+	c.mu.RLock()
+	checks := make(map[string]CheckFunc, len(c.checks))
+	for name, check := range c.checks {
+		checks[name] = check
+	}
+	c.mu.RUnlock()
+
+	var (
+		wg      sync.WaitGroup
+		mu      sync.Mutex
+		results = make(map[string]CheckResult, len(checks))
+	)
+	for name, check := range checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			result := c.run(ctx, check)
+			mu.Lock()
+			results[name] = result
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+	return results
+}
+
+func (c *Checker) run(ctx context.Context, check CheckFunc) CheckResult {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	start := time.Now()
+	err := check(ctx)
+	result := CheckResult{
+		Status:    StatusUp,
+		LatencyMS: float64(time.Since(start).Microseconds()) / 1000,
+	}
+	if err != nil {
+		result.Status = StatusDown
+		result.Error = err.Error()
+	}
+	return result
+}
+
+// Healthy reports whether every result is up
+func Healthy(results map[string]CheckResult) bool {
+	for _, result := range results {
+		if result.Status != StatusUp {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,58 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChecker_ReportsEachCheck(t *testing.T) {
+	checker := NewChecker()
+	checker.Add("database", func(context.Context) error { return nil })
+	checker.Add("vault", func(context.Context) error { return errors.New("vault is sealed") })
+
+	results := checker.Run(context.Background())
+
+	assert.Len(t, results, 2)
+	assert.Equal(t, StatusUp, results["database"].Status)
+	assert.Empty(t, results["database"].Error)
+	assert.Equal(t, StatusDown, results["vault"].Status)
+	assert.Equal(t, "vault is sealed", results["vault"].Error)
+	assert.False(t, Healthy(results))
+}
+
+func TestChecker_TimesOutSlowChecks(t *testing.T) {
+	checker := NewChecker()
+	checker.timeout = 10 * time.Millisecond
+	checker.Add("slow", func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+
+	results := checker.Run(context.Background())
+
+	assert.Equal(t, StatusDown, results["slow"].Status)
+	assert.GreaterOrEqual(t, results["slow"].LatencyMS, 10.0)
+}
+
+func TestChecker_EmptyIsHealthy(t *testing.T) {
+	assert.True(t, Healthy(NewChecker().Run(context.Background())))
+}
+
+func TestHeartbeat(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	heartbeat := NewHeartbeat(time.Minute)
+	heartbeat.nowFunc = func() time.Time { return now }
+
+	assert.EqualError(t, heartbeat.Check(context.Background()), "worker has not started")
+
+	heartbeat.Beat()
+	now = now.Add(30 * time.Second)
+	assert.NoError(t, heartbeat.Check(context.Background()))
+
+	now = now.Add(2 * time.Minute)
+	assert.EqualError(t, heartbeat.Check(context.Background()), "last heartbeat 2m30s ago")
+}
@@ -0,0 +1,38 @@
+package health
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"time"
+)
+
+// Heartbeat lets a background worker prove it is still running
+// The worker calls Beat on every loop iteration and registers Check with the readiness Checker
+type Heartbeat struct {
+	last    atomic.Int64 // Unix nanoseconds of the last beat, 0 before the first
+	maxAge  time.Duration
+	nowFunc func() time.Time
+}
+
+// NewHeartbeat creates a heartbeat considered stale when no beat arrived within maxAge
+func NewHeartbeat(maxAge time.Duration) *Heartbeat {
+	return &Heartbeat{maxAge: maxAge, nowFunc: time.Now}
+}
+
+// Beat records that the worker is alive
+func (h *Heartbeat) Beat() {
+	h.last.Store(h.nowFunc().UnixNano())
+}
+
+// Check fails until the first beat and whenever the last beat is older than maxAge
+func (h *Heartbeat) Check(context.Context) error {
+	last := h.last.Load()
+	if last == 0 {
+		return fmt.Errorf("worker has not started")
+	}
+	if age := h.nowFunc().Sub(time.Unix(0, last)); age > h.maxAge {
+		return fmt.Errorf("last heartbeat %s ago", age.Round(time.Second))
+	}
+	return nil
+}
@@ -0,0 +1,51 @@
+package domain
+
+import (
+	"encoding/json"
+	"time"
+
+	commonModels "thothix-backend/internal/common/models"
+)
+
+// Status is the lifecycle state of a job
+type Status string
+
+const (
+	StatusQueued    Status = "queued"    // Waiting for RunAt, including retries after a failure
+	StatusRunning   Status = "running"   // Claimed by a worker until LockedUntil
+	StatusSucceeded Status = "succeeded" // Completed; pruned after a while
+	StatusDead      Status = "dead"      // Failed every attempt, or permanently; kept until retried by an admin
+)
+
+// IsValid reports whether the status is one of the job lifecycle states
+func (s Status) IsValid() bool {
+	switch s {
+	case StatusQueued, StatusRunning, StatusSucceeded, StatusDead:
+		return true
+	}
+	return false
+}
+
+// Job is a unit of background work stored in Postgres and run by the workers of its queue
+// CreatedBy, stamped from the enqueuing request, becomes the acting user while the job runs
+type Job struct {
+	commonModels.BaseModel
+	Queue       string          `json:"queue" gorm:"not null;index:idx_jobs_claim,priority:1"`
+	Kind        string          `json:"kind" gorm:"not null;index"`
+	Payload     json.RawMessage `json:"payload" gorm:"type:jsonb;not null"`
+	Status      Status          `json:"status" gorm:"not null;default:'queued';index:idx_jobs_claim,priority:2"`
+	RunAt       time.Time       `json:"run_at" gorm:"not null;index:idx_jobs_claim,priority:3"`
+	Attempts    int             `json:"attempts" gorm:"not null;default:0"`
+	MaxAttempts int             `json:"max_attempts" gorm:"not null"`
+	// UniqueKey deduplicates jobs: only one queued or running job may hold a key
+	UniqueKey   *string    `json:"unique_key,omitempty" gorm:"uniqueIndex:idx_jobs_unique_key,where:status = 'queued' OR status = 'running'"`
+	LockedBy    string     `json:"locked_by,omitempty"`    // Worker running the job
+	LockedUntil *time.Time `json:"locked_until,omitempty"` // A running job not finished by then is considered abandoned and claimed again
+	LastError   string     `json:"last_error,omitempty"`
+	FinishedAt  *time.Time `json:"finished_at,omitempty"`
+}
+
+// IsDead reports whether the job stopped retrying and waits for an admin
+func (j *Job) IsDead() bool {
+	return j.Status == StatusDead
+}
@@ -0,0 +1,83 @@
+package dto
+
+import (
+	"encoding/json"
+
+	"thothix-backend/internal/shared/dto"
+)
+
+// === JOB DTOs ===
+
+// JobDto represents a background job in API responses
+type JobDto struct {
+	ID          string          `json:"id"`
+	Queue       string          `json:"queue"`
+	Kind        string          `json:"kind"`
+	Payload     json.RawMessage `json:"payload"`
+	Status      string          `json:"status"`
+	RunAt       string          `json:"run_at"`
+	Attempts    int             `json:"attempts"`
+	MaxAttempts int             `json:"max_attempts"`
+	UniqueKey   *string         `json:"unique_key,omitempty"`
+	LockedBy    string          `json:"locked_by,omitempty"`
+	LastError   string          `json:"last_error,omitempty"`
+	CreatedBy   string          `json:"created_by,omitempty"`
+	CreatedAt   string          `json:"created_at"`
+	FinishedAt  string          `json:"finished_at,omitempty"`
+}
+
+// JobListDto represents paginated job data
+type JobListDto = dto.PaginatedListResponse[JobDto]
+
+// NewJobListDto creates a JobListDto with proper pagination metadata
+func NewJobListDto(jobs []JobDto, total int64, page, perPage int) *JobListDto {
+	return dto.NewPaginatedListResponse(jobs, total, page, perPage)
+}
+
+// JobFilterRequest represents the query filters for listing jobs
+type JobFilterRequest struct {
+	dto.PaginationRequest
+	Queue  string `form:"queue"`
+	Kind   string `form:"kind"`
+	Status string `form:"status"`
+}
+
+// QueueStatsDto counts the jobs of a queue by status
+type QueueStatsDto struct {
+	Queue     string `json:"queue"`
+	Queued    int64  `json:"queued"`
+	Running   int64  `json:"running"`
+	Succeeded int64  `json:"succeeded"`
+	Dead      int64  `json:"dead"`
+	Due       int64  `json:"due"`                  // Queued jobs whose run time has passed
+	OldestDue string `json:"oldest_due,omitempty"` // Run time of the longest waiting due job
+}
+
+// GetJobsResponse wraps a paginated list of JobDto
+type GetJobsResponse = dto.ListResponse[JobDto]
+
+func NewGetJobsResponse(producer func() dto.Validation[*JobListDto]) *GetJobsResponse {
+	return dto.NewListResponse(producer)
+}
+
+// JobResponse wraps a single JobDto
+type JobResponse struct {
+	*dto.Response[*JobDto]
+}
+
+func NewJobResponse(producer func() dto.Validation[*JobDto]) *JobResponse {
+	return &JobResponse{
+		Response: dto.NewResponse(producer),
+	}
+}
+
+// QueueStatsResponse wraps the statistics of every queue
+type QueueStatsResponse struct {
+	*dto.Response[[]QueueStatsDto]
+}
+
+func NewQueueStatsResponse(producer func() dto.Validation[[]QueueStatsDto]) *QueueStatsResponse {
+	return &QueueStatsResponse{
+		Response: dto.NewResponse(producer),
+	}
+}
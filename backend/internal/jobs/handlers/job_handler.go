@@ -0,0 +1,209 @@
+package handlers
+
+import (
+	"github.com/gin-gonic/gin"
+
+	jobDto "thothix-backend/internal/jobs/dto"
+	"thothix-backend/internal/jobs/service"
+	"thothix-backend/internal/shared/constants"
+	"thothix-backend/internal/shared/dto"
+	"thothix-backend/internal/shared/handlers"
+)
+
+type JobHandler struct {
+	jobService service.JobServiceInterface
+}
+
+func NewJobHandler(jobService service.JobServiceInterface) *JobHandler {
+	return &JobHandler{
+		jobService: jobService,
+	}
+}
+
+// GetJobs godoc
+// @Summary List background jobs
+// @Description Get a filtered, paginated list of background jobs, most recently scheduled first (platform admin only)
+// @Tags jobs
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param page query int false "Page number (default: 1)"
+// @Param per_page query int false "Items per page (default: 20, max: 100)"
+// @Param queue query string false "Filter by queue"
+// @Param kind query string false "Filter by job kind"
+// @Param status query string false "Filter by status (queued, running, succeeded, dead)"
+// @Success 200 {object} jobDto.JobListDto
+// @Failure 400 {object} dto.ErrorViewModel
+// @Failure 403 {object} dto.ErrorViewModel
+// @Failure 500 {object} dto.ErrorViewModel
+// @Router /jobs [get]
+func (h *JobHandler) GetJobs(c *gin.Context) {
+	wrapper := handlers.WrapContext(c)
+
+	var request jobDto.JobFilterRequest
+	if err := c.ShouldBindQuery(&request); err != nil {
+		wrapper.BadRequestErrorResponse("Invalid query parameters")
+		return
+	}
+
+	// Set defaults
+	if request.Page == 0 {
+		request.Page = constants.DefaultPage
+	}
+	if request.PerPage == 0 {
+		request.PerPage = 20
+	}
+
+	response := h.scopedService(c).GetJobs(&request)
+
+	response.Match(
+		// Exception case
+		func(err error) interface{} {
+			wrapper.SystemErrorResponse(err, "Failed to retrieve jobs")
+			return nil
+		},
+		// Success case
+		func(result *jobDto.JobListDto) interface{} {
+			wrapper.SuccessResponse(result)
+			return nil
+		},
+		// Validation failure case
+		func(errors []dto.Error) interface{} {
+			wrapper.ValidationErrorResponse(errors, "Job query validation failed")
+			return nil
+		},
+	)
+}
+
+// GetQueueStats godoc
+// @Summary Background queue statistics
+// @Description Count the jobs of every queue by status, with the number of due jobs and how long the oldest has waited (platform admin only)
+// @Tags jobs
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} jobDto.QueueStatsDto
+// @Failure 403 {object} dto.ErrorViewModel
+// @Failure 500 {object} dto.ErrorViewModel
+// @Router /jobs/stats [get]
+func (h *JobHandler) GetQueueStats(c *gin.Context) {
+	wrapper := handlers.WrapContext(c)
+
+	response := h.scopedService(c).GetQueueStats()
+
+	response.Match(
+		// Exception case
+		func(err error) interface{} {
+			wrapper.SystemErrorResponse(err, "Failed to retrieve queue statistics")
+			return nil
+		},
+		// Success case
+		func(result []jobDto.QueueStatsDto) interface{} {
+			wrapper.SuccessResponse(result)
+			return nil
+		},
+		// Validation failure case
+		func(errors []dto.Error) interface{} {
+			wrapper.ValidationErrorResponse(errors, "Queue statistics validation failed")
+			return nil
+		},
+	)
+}
+
+// GetJob godoc
+// @Summary Get a background job
+// @Description Get a job with its payload, attempts and last error (platform admin only)
+// @Tags jobs
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Job ID"
+// @Success 200 {object} jobDto.JobDto
+// @Failure 403 {object} dto.ErrorViewModel
+// @Failure 404 {object} dto.ErrorViewModel
+// @Failure 500 {object} dto.ErrorViewModel
+// @Router /jobs/{id} [get]
+func (h *JobHandler) GetJob(c *gin.Context) {
+	wrapper := handlers.WrapContext(c)
+	jobID := c.Param("id")
+
+	response := h.scopedService(c).GetJob(jobID)
+
+	response.Match(
+		// Exception case
+		func(err error) interface{} {
+			wrapper.SystemErrorResponse(err, "Failed to retrieve job: %s", jobID)
+			return nil
+		},
+		// Success case
+		func(result *jobDto.JobDto) interface{} {
+			wrapper.SuccessResponse(result)
+			return nil
+		},
+		// Validation failure case
+		func(errors []dto.Error) interface{} {
+			respondWithFailure(wrapper, errors, jobID, "Job validation failed")
+			return nil
+		},
+	)
+}
+
+// RetryJob godoc
+// @Summary Retry a dead job
+// @Description Queue a dead-lettered job again with a fresh set of attempts (platform admin only)
+// @Tags jobs
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Job ID"
+// @Success 200 {object} jobDto.JobDto
+// @Failure 403 {object} dto.ErrorViewModel
+// @Failure 404 {object} dto.ErrorViewModel
+// @Failure 409 {object} dto.ErrorViewModel
+// @Failure 500 {object} dto.ErrorViewModel
+// @Router /jobs/{id}/retry [post]
+func (h *JobHandler) RetryJob(c *gin.Context) {
+	wrapper := handlers.WrapContext(c)
+	jobID := c.Param("id")
+
+	response := h.scopedService(c).RetryJob(jobID)
+
+	response.Match(
+		// Exception case
+		func(err error) interface{} {
+			wrapper.SystemErrorResponse(err, "Failed to retry job: %s", jobID)
+			return nil
+		},
+		// Success case
+		func(result *jobDto.JobDto) interface{} {
+			wrapper.SuccessResponse(result)
+			return nil
+		},
+		// Validation failure case
+		func(errors []dto.Error) interface{} {
+			respondWithFailure(wrapper, errors, jobID, "Job retry validation failed")
+			return nil
+		},
+	)
+}
+
+// respondWithFailure maps service validation failures to HTTP responses
+func respondWithFailure(wrapper *handlers.ContextWrapper, errors []dto.Error, jobID, logMessage string) {
+	switch {
+	case len(errors) > 0 && errors[0].Code == constants.NotFoundError:
+		wrapper.NotFoundErrorResponse("Job", jobID)
+	case len(errors) > 0 && errors[0].Code == constants.ConflictError:
+		wrapper.ConflictErrorResponse(errors[0].Message)
+	default:
+		wrapper.ValidationErrorResponse(errors, "%s", logMessage)
+	}
+}
+
+// scopedService binds the service to the request context when supported,
+// so retries are attributed to the admin in the audit log
+func (h *JobHandler) scopedService(c *gin.Context) service.JobServiceInterface {
+	if aware, ok := h.jobService.(service.ContextAwareJobService); ok {
+		return aware.WithContext(c.Request.Context())
+	}
+	return h.jobService
+}
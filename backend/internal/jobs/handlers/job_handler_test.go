@@ -0,0 +1,136 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	jobDto "thothix-backend/internal/jobs/dto"
+	"thothix-backend/internal/shared/dto"
+)
+
+// MockJobService is a mock implementation of the JobService
+type MockJobService struct {
+	mock.Mock
+}
+
+func (m *MockJobService) GetJobs(req *jobDto.JobFilterRequest) *jobDto.GetJobsResponse {
+	args := m.Called(req)
+	return args.Get(0).(*jobDto.GetJobsResponse)
+}
+
+func (m *MockJobService) GetJob(id string) *jobDto.JobResponse {
+	args := m.Called(id)
+	return args.Get(0).(*jobDto.JobResponse)
+}
+
+func (m *MockJobService) GetQueueStats() *jobDto.QueueStatsResponse {
+	args := m.Called()
+	return args.Get(0).(*jobDto.QueueStatsResponse)
+}
+
+func (m *MockJobService) RetryJob(id string) *jobDto.JobResponse {
+	args := m.Called(id)
+	return args.Get(0).(*jobDto.JobResponse)
+}
+
+type JobHandlerTestSuite struct {
+	suite.Suite
+	mockService *MockJobService
+	router      *gin.Engine
+}
+
+func (suite *JobHandlerTestSuite) SetupSuite() {
+	gin.SetMode(gin.TestMode)
+}
+
+func (suite *JobHandlerTestSuite) SetupTest() {
+	suite.mockService = new(MockJobService)
+	handler := NewJobHandler(suite.mockService)
+
+	suite.router = gin.New()
+	suite.router.GET("/jobs", handler.GetJobs)
+	suite.router.GET("/jobs/:id", handler.GetJob)
+	suite.router.POST("/jobs/:id/retry", handler.RetryJob)
+}
+
+func (suite *JobHandlerTestSuite) TestGetJobs_AppliesDefaults() {
+	// Arrange
+	mockResponse := jobDto.NewGetJobsResponse(func() dto.Validation[*jobDto.JobListDto] {
+		return dto.Success(jobDto.NewJobListDto([]jobDto.JobDto{{ID: "job-1", Status: "dead"}}, 1, 1, 20))
+	})
+
+	suite.mockService.On("GetJobs", &jobDto.JobFilterRequest{
+		PaginationRequest: dto.PaginationRequest{Page: 1, PerPage: 20},
+		Status:            "dead",
+	}).Return(mockResponse)
+
+	// Act
+	req, _ := http.NewRequest("GET", "/jobs?status=dead", nil)
+	w := httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(suite.T(), http.StatusOK, w.Code)
+	assert.Contains(suite.T(), w.Body.String(), "job-1")
+}
+
+func (suite *JobHandlerTestSuite) TestGetJob_NotFound() {
+	// Arrange
+	mockResponse := jobDto.NewJobResponse(func() dto.Validation[*jobDto.JobDto] {
+		return dto.Failure[*jobDto.JobDto](dto.NewError("NOT_FOUND", "Job not found", nil))
+	})
+
+	suite.mockService.On("GetJob", "job-1").Return(mockResponse)
+
+	// Act
+	req, _ := http.NewRequest("GET", "/jobs/job-1", nil)
+	w := httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(suite.T(), http.StatusNotFound, w.Code)
+}
+
+func (suite *JobHandlerTestSuite) TestRetryJob_Success() {
+	// Arrange
+	mockResponse := jobDto.NewJobResponse(func() dto.Validation[*jobDto.JobDto] {
+		return dto.Success(&jobDto.JobDto{ID: "job-1", Status: "queued"})
+	})
+
+	suite.mockService.On("RetryJob", "job-1").Return(mockResponse)
+
+	// Act
+	req, _ := http.NewRequest("POST", "/jobs/job-1/retry", nil)
+	w := httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(suite.T(), http.StatusOK, w.Code)
+	assert.Contains(suite.T(), w.Body.String(), `"status":"queued"`)
+}
+
+func (suite *JobHandlerTestSuite) TestRetryJob_NotDead() {
+	// Arrange
+	mockResponse := jobDto.NewJobResponse(func() dto.Validation[*jobDto.JobDto] {
+		return dto.Invalid[*jobDto.JobDto](dto.NewError("CONFLICT", "Only dead jobs can be retried", nil))
+	})
+
+	suite.mockService.On("RetryJob", "job-1").Return(mockResponse)
+
+	// Act
+	req, _ := http.NewRequest("POST", "/jobs/job-1/retry", nil)
+	w := httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(suite.T(), http.StatusConflict, w.Code)
+}
+
+func TestJobHandlerTestSuite(t *testing.T) {
+	suite.Run(t, new(JobHandlerTestSuite))
+}
@@ -0,0 +1,21 @@
+package handlers
+
+import (
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"thothix-backend/internal/jobs/service"
+	"thothix-backend/internal/middleware"
+)
+
+// RegisterJobRoutes registers the routes inspecting and retrying background jobs, restricted to platform administrators
+func RegisterJobRoutes(router *gin.RouterGroup, db *gorm.DB) {
+	jobService := service.NewJobService(db)
+	jobHandler := NewJobHandler(jobService)
+
+	jobs := router.Group("/jobs")
+	jobs.Use(middleware.RequirePlatformAdmin(db))
+	jobs.GET("", jobHandler.GetJobs)
+	jobs.GET("/stats", jobHandler.GetQueueStats)
+	jobs.GET("/:id", jobHandler.GetJob)
+	jobs.POST("/:id/retry", jobHandler.RetryJob)
+}
@@ -0,0 +1,52 @@
+package mappers
+
+import (
+	"time"
+
+	"thothix-backend/internal/jobs/domain"
+	jobDto "thothix-backend/internal/jobs/dto"
+)
+
+// JobMapper handles conversion between jobs and their DTOs
+type JobMapper struct{}
+
+// NewJobMapper creates a new JobMapper instance
+func NewJobMapper() *JobMapper {
+	return &JobMapper{}
+}
+
+// ModelToDto converts a Job to JobDto
+func (m *JobMapper) ModelToDto(job *domain.Job) *jobDto.JobDto {
+	if job == nil {
+		return nil
+	}
+
+	dto := &jobDto.JobDto{
+		ID:          job.ID,
+		Queue:       job.Queue,
+		Kind:        job.Kind,
+		Payload:     job.Payload,
+		Status:      string(job.Status),
+		RunAt:       job.RunAt.Format(time.RFC3339),
+		Attempts:    job.Attempts,
+		MaxAttempts: job.MaxAttempts,
+		UniqueKey:   job.UniqueKey,
+		LockedBy:    job.LockedBy,
+		LastError:   job.LastError,
+		CreatedBy:   job.CreatedBy,
+		CreatedAt:   job.CreatedAt.Format(time.RFC3339),
+	}
+	if job.FinishedAt != nil {
+		dto.FinishedAt = job.FinishedAt.Format(time.RFC3339)
+	}
+	return dto
+}
+
+// ModelsToDtos converts a slice of Job to JobDto
+func (m *JobMapper) ModelsToDtos(jobs []domain.Job) []jobDto.JobDto {
+	dtos := make([]jobDto.JobDto, 0, len(jobs))
+	for i := range jobs {
+		dtos = append(dtos, *m.ModelToDto(&jobs[i]))
+	}
+	return dtos
+}
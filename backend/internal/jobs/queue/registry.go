@@ -0,0 +1,229 @@
+package queue
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"thothix-backend/internal/jobs/domain"
+)
+
+const (
+	// DefaultQueue runs the kinds that do not name a queue
+	DefaultQueue = "default"
+
+	defaultMaxAttempts = 5
+	defaultTimeout     = 10 * time.Minute
+)
+
+// Options configure a job kind
+type Options struct {
+	Queue       string                          // Queue the jobs run on, DefaultQueue when empty
+	MaxAttempts int                             // Attempts before the job is dead-lettered, 5 when zero
+	Timeout     time.Duration                   // Time a run may take before it is cancelled and considered abandoned, 10m when zero
+	Backoff     func(attempt int) time.Duration // Delay before the next attempt after attempt failed, ExponentialBackoff when nil
+}
+
+// Handler runs one job; db carries the job context
+// Returning an error retries the job with backoff, unless it is wrapped with Permanent
+type Handler[T any] func(ctx context.Context, db *gorm.DB, payload T) error
+
+// Kind is a registered job type whose payload is a T, encoded as JSON
+type Kind[T any] struct {
+	name    string
+	options Options
+}
+
+// Name returns the name the kind was registered under
+func (k *Kind[T]) Name() string {
+	return k.name
+}
+
+// definition is the untyped view of a kind used by the worker
+type definition struct {
+	options Options
+	run     func(ctx context.Context, db *gorm.DB, payload json.RawMessage) error
+}
+
+// Registry maps job kinds to their handlers
+type Registry struct {
+	mu    sync.RWMutex
+	kinds map[string]*definition
+}
+
+// Default is the registry kinds register into from their package, and the one main runs
+var Default = NewRegistry()
+
+func NewRegistry() *Registry {
+	return &Registry{kinds: make(map[string]*definition)}
+}
+
+// Register adds a job kind to registry; registering a name twice panics, like duplicate routes do
+func Register[T any](registry *Registry, name string, options Options, handler Handler[T]) *Kind[T] {
+	if options.Queue == "" {
+		options.Queue = DefaultQueue
+	}
+	if options.MaxAttempts <= 0 {
+		options.MaxAttempts = defaultMaxAttempts
+	}
+	if options.Timeout <= 0 {
+		options.Timeout = defaultTimeout
+	}
+	if options.Backoff == nil {
+		options.Backoff = ExponentialBackoff
+	}
+
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+	if _, exists := registry.kinds[name]; exists {
+		panic(fmt.Sprintf("jobs: kind %q registered twice", name))
+	}
+	registry.kinds[name] = &definition{
+		options: options,
+		run: func(ctx context.Context, db *gorm.DB, raw json.RawMessage) error {
+			var payload T
+			if err := json.Unmarshal(raw, &payload); err != nil {
+				return Permanent(fmt.Errorf("decode payload: %w", err))
+			}
+			return handler(ctx, db, payload)
+		},
+	}
+	return &Kind[T]{name: name, options: options}
+}
+
+// lookup returns the definition of a kind, nil when no handler is registered
+func (r *Registry) lookup(name string) *definition {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.kinds[name]
+}
+
+// kindsOf lists the kinds registered on queue
+func (r *Registry) kindsOf(queue string) []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var kinds []string
+	for name, kind := range r.kinds {
+		if kind.options.Queue == queue {
+			kinds = append(kinds, name)
+		}
+	}
+	return kinds
+}
+
+// Queues lists the queues of the registered kinds
+func (r *Registry) Queues() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	seen := make(map[string]bool)
+	var queues []string
+	for _, kind := range r.kinds {
+		if !seen[kind.options.Queue] {
+			seen[kind.options.Queue] = true
+			queues = append(queues, kind.options.Queue)
+		}
+	}
+	sort.Strings(queues)
+	return queues
+}
+
+// EnqueueOption adjusts a job before it is stored
+type EnqueueOption func(*domain.Job)
+
+// RunAt delays the first attempt until at
+func RunAt(at time.Time) EnqueueOption {
+	return func(job *domain.Job) {
+		job.RunAt = at
+	}
+}
+
+// Unique deduplicates the job: while a job of the same kind and key is queued or running,
+// Enqueue returns that job instead of adding another
+func Unique(key string) EnqueueOption {
+	return func(job *domain.Job) {
+		uniqueKey := job.Kind + ":" + key
+		job.UniqueKey = &uniqueKey
+	}
+}
+
+// Enqueue stores a job of kind k with db, so it commits or rolls back with the caller's transaction
+func (k *Kind[T]) Enqueue(db *gorm.DB, payload T, options ...EnqueueOption) (*domain.Job, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("encode %s payload: %w", k.name, err)
+	}
+
+	job := &domain.Job{
+		Queue:       k.options.Queue,
+		Kind:        k.name,
+		Payload:     data,
+		Status:      domain.StatusQueued,
+		RunAt:       time.Now(),
+		MaxAttempts: k.options.MaxAttempts,
+	}
+	for _, option := range options {
+		option(job)
+	}
+
+	if job.UniqueKey == nil {
+		return job, db.Create(job).Error
+	}
+
+	result := db.Clauses(clause.OnConflict{
+		Columns:     []clause.Column{{Name: "unique_key"}},
+		TargetWhere: clause.Where{Exprs: []clause.Expression{clause.Expr{SQL: activeJobs}}},
+		DoNothing:   true,
+	}).Create(job)
+	if result.Error != nil || result.RowsAffected > 0 {
+		return job, result.Error
+	}
+
+	var existing domain.Job
+	if err := db.Where("unique_key = ? AND ("+activeJobs+")", *job.UniqueKey).First(&existing).Error; err != nil {
+		return nil, err
+	}
+	return &existing, nil
+}
+
+// activeJobs matches the predicate of the partial unique index on unique_key
+const activeJobs = "status = 'queued' OR status = 'running'"
+
+// permanentError marks a failure that retrying cannot fix
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string { return e.err.Error() }
+func (e *permanentError) Unwrap() error { return e.err }
+
+// Permanent wraps err so the job is dead-lettered at once instead of retried
+func Permanent(err error) error {
+	return &permanentError{err: err}
+}
+
+// IsPermanent reports whether err was wrapped with Permanent
+func IsPermanent(err error) bool {
+	var permanent *permanentError
+	return errors.As(err, &permanent)
+}
+
+// ExponentialBackoff waits 10s after the first failed attempt and doubles the delay each time, up to an hour
+func ExponentialBackoff(attempt int) time.Duration {
+	const base, ceiling = 10 * time.Second, time.Hour
+	if attempt < 1 {
+		attempt = 1
+	}
+	if attempt > 10 {
+		return ceiling
+	}
+	return min(base<<(attempt-1), ceiling)
+}
@@ -0,0 +1,71 @@
+package queue
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func noop(context.Context, *gorm.DB, struct{}) error { return nil }
+
+func TestRegister_AppliesDefaults(t *testing.T) {
+	registry := NewRegistry()
+
+	kind := Register(registry, "test.noop", Options{}, noop)
+
+	assert.Equal(t, "test.noop", kind.Name())
+	definition := registry.lookup("test.noop")
+	assert.Equal(t, DefaultQueue, definition.options.Queue)
+	assert.Equal(t, defaultMaxAttempts, definition.options.MaxAttempts)
+	assert.Equal(t, defaultTimeout, definition.options.Timeout)
+	assert.NotNil(t, definition.options.Backoff)
+}
+
+func TestRegister_DuplicatePanics(t *testing.T) {
+	registry := NewRegistry()
+	Register(registry, "test.noop", Options{}, noop)
+
+	assert.Panics(t, func() { Register(registry, "test.noop", Options{}, noop) })
+}
+
+func TestRegistry_Queues(t *testing.T) {
+	registry := NewRegistry()
+	Register(registry, "test.mail", Options{Queue: "mail"}, noop)
+	Register(registry, "test.noop", Options{}, noop)
+	Register(registry, "test.digest", Options{Queue: "mail"}, noop)
+
+	assert.Equal(t, []string{"default", "mail"}, registry.Queues())
+	assert.ElementsMatch(t, []string{"test.mail", "test.digest"}, registry.kindsOf("mail"))
+}
+
+func TestRegister_UndecodablePayloadIsPermanent(t *testing.T) {
+	registry := NewRegistry()
+	Register(registry, "test.typed", Options{}, func(context.Context, *gorm.DB, struct{ Count int }) error { return nil })
+
+	err := registry.lookup("test.typed").run(context.Background(), nil, []byte(`{"Count":"many"}`))
+
+	assert.True(t, IsPermanent(err))
+}
+
+func TestIsPermanent_Wrapped(t *testing.T) {
+	cause := errors.New("no such user")
+
+	err := fmt.Errorf("send digest: %w", Permanent(cause))
+
+	assert.True(t, IsPermanent(err))
+	assert.ErrorIs(t, err, cause)
+	assert.False(t, IsPermanent(cause))
+}
+
+func TestExponentialBackoff(t *testing.T) {
+	assert.Equal(t, 10*time.Second, ExponentialBackoff(1))
+	assert.Equal(t, 20*time.Second, ExponentialBackoff(2))
+	assert.Equal(t, 80*time.Second, ExponentialBackoff(4))
+	assert.Equal(t, time.Hour, ExponentialBackoff(10))
+	assert.Equal(t, time.Hour, ExponentialBackoff(50))
+}
@@ -0,0 +1,268 @@
+package queue
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+
+	"thothix-backend/internal/health"
+	"thothix-backend/internal/jobs/domain"
+	"thothix-backend/internal/metrics"
+	"thothix-backend/internal/shared/logging"
+	sharedMiddleware "thothix-backend/internal/shared/middleware"
+)
+
+const (
+	// succeededRetention is how long succeeded jobs are kept for inspection
+	succeededRetention = 7 * 24 * time.Hour
+
+	// pruneInterval is the time between deletions of old succeeded jobs
+	pruneInterval = time.Hour
+)
+
+// Worker runs the jobs of every queue of a registry
+// Each queue runs at most its concurrency of jobs at once across all replicas: claims are serialized per queue
+// with a transaction-level advisory lock and count the jobs already running
+type Worker struct {
+	db           *gorm.DB
+	registry     *Registry
+	concurrency  map[string]int
+	pollInterval time.Duration
+	id           string
+	now          func() time.Time
+}
+
+// NewWorker creates a worker; queues missing from concurrency run one job at a time
+func NewWorker(db *gorm.DB, registry *Registry, concurrency map[string]int, pollInterval time.Duration) *Worker {
+	return &Worker{
+		db:           db,
+		registry:     registry,
+		concurrency:  concurrency,
+		pollInterval: pollInterval,
+		id:           workerID(),
+		now:          time.Now,
+	}
+}
+
+// Run polls every queue until ctx is cancelled, then waits for the running jobs to finish
+// Jobs do not see the cancellation of ctx, only their own timeout, so a shutdown lets them complete;
+// a job cut short by process exit is claimed again once its lock expires
+func (w *Worker) Run(ctx context.Context, heartbeat *health.Heartbeat) {
+	var wg sync.WaitGroup
+	for _, queue := range w.registry.Queues() {
+		limit := w.limit(queue)
+		for i := 0; i < limit; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				w.poll(ctx, queue, limit, heartbeat)
+			}()
+		}
+	}
+
+	// Beats here too, so queues busy with long jobs do not make the replica unready
+	ticker := time.NewTicker(w.pollInterval)
+	defer ticker.Stop()
+	var prunedAt time.Time
+	for {
+		heartbeat.Beat()
+		if w.now().Sub(prunedAt) >= pruneInterval {
+			w.prune(ctx)
+			prunedAt = w.now()
+		}
+
+		select {
+		case <-ctx.Done():
+			wg.Wait()
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunOnce claims and runs one job of queue, reporting whether there was one
+func (w *Worker) RunOnce(ctx context.Context, queue string) (bool, error) {
+	job, err := w.claim(ctx, queue, w.limit(queue))
+	if err != nil || job == nil {
+		return false, err
+	}
+	return true, w.execute(ctx, job)
+}
+
+func (w *Worker) limit(queue string) int {
+	if limit := w.concurrency[queue]; limit > 0 {
+		return limit
+	}
+	return 1
+}
+
+// poll runs jobs of queue back to back while there are some, and waits pollInterval when it is empty
+func (w *Worker) poll(ctx context.Context, queue string, limit int, heartbeat *health.Heartbeat) {
+	logger := logging.FromContext(ctx).With(slog.String("queue", queue))
+	for {
+		heartbeat.Beat()
+		job, err := w.claim(ctx, queue, limit)
+		if err != nil && ctx.Err() == nil {
+			logger.Error("Failed to claim job", slog.Any("error", err))
+		}
+		if job != nil {
+			if err := w.execute(ctx, job); err != nil {
+				logger.Error("Failed to record job outcome", slog.String("job_id", job.ID), slog.Any("error", err))
+			}
+			if ctx.Err() == nil {
+				continue
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(w.pollInterval):
+		}
+	}
+}
+
+// claim locks the next due job of queue and marks it running, unless limit jobs already run
+// Running jobs past their lock are abandoned: they are claimed again, or dead-lettered when out of attempts
+func (w *Worker) claim(ctx context.Context, queue string, limit int) (*domain.Job, error) {
+	var claimed *domain.Job
+	err := w.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("SELECT pg_advisory_xact_lock(hashtext(?))", "jobs:"+queue).Error; err != nil {
+			return err
+		}
+		now := w.now()
+
+		var running int64
+		if err := tx.Raw("SELECT count(*) FROM jobs WHERE queue = ? AND status = ? AND locked_until > ?",
+			queue, domain.StatusRunning, now).Scan(&running).Error; err != nil {
+			return err
+		}
+		if running >= int64(limit) {
+			return nil
+		}
+
+		// Only kinds this binary can run are claimed, so during a rolling deploy new kinds wait for new workers
+		kinds := w.registry.kindsOf(queue)
+		for {
+			var job domain.Job
+			if err := tx.Raw(`SELECT * FROM jobs
+				WHERE queue = ? AND kind IN ? AND ((status = ? AND run_at <= ?) OR (status = ? AND locked_until <= ?))
+				ORDER BY run_at, created_at LIMIT 1 FOR UPDATE SKIP LOCKED`,
+				queue, kinds, domain.StatusQueued, now, domain.StatusRunning, now).Scan(&job).Error; err != nil {
+				return err
+			}
+			if job.ID == "" {
+				return nil
+			}
+			if job.Status == domain.StatusRunning && job.Attempts >= job.MaxAttempts {
+				if err := w.finish(tx, &job, domain.StatusDead, "abandoned by its worker on the last attempt", nil); err != nil {
+					return err
+				}
+				continue
+			}
+
+			kind := w.registry.lookup(job.Kind)
+			lockedUntil := now.Add(kind.options.Timeout)
+			if err := tx.Exec(`UPDATE jobs SET status = ?, attempts = attempts + 1, locked_by = ?, locked_until = ?, updated_at = ?
+				WHERE id = ?`, domain.StatusRunning, w.id, lockedUntil, now, job.ID).Error; err != nil {
+				return err
+			}
+			job.Status = domain.StatusRunning
+			job.Attempts++
+			job.LockedBy = w.id
+			job.LockedUntil = &lockedUntil
+			claimed = &job
+			return nil
+		}
+	})
+	return claimed, err
+}
+
+// execute runs a claimed job and records its outcome: succeeded, queued again with backoff, or dead
+func (w *Worker) execute(ctx context.Context, job *domain.Job) error {
+	kind := w.registry.lookup(job.Kind)
+	jobCtx := logging.With(context.WithoutCancel(ctx),
+		slog.String("job_id", job.ID), slog.String("job_kind", job.Kind), slog.Int("attempt", job.Attempts))
+	if job.CreatedBy != "" {
+		jobCtx = sharedMiddleware.WithUserID(jobCtx, job.CreatedBy)
+	}
+	jobCtx, cancel := context.WithTimeout(jobCtx, kind.options.Timeout)
+	defer cancel()
+	logger := logging.FromContext(jobCtx)
+
+	err := func() (err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				err = fmt.Errorf("panic: %v", r)
+			}
+		}()
+		return kind.run(jobCtx, w.db.WithContext(jobCtx), job.Payload)
+	}()
+
+	db := w.db.WithContext(context.WithoutCancel(ctx))
+	switch {
+	case err == nil:
+		metrics.JobsProcessed.WithLabelValues(job.Kind, metrics.JobSucceeded).Inc()
+		return w.finish(db, job, domain.StatusSucceeded, "", nil)
+
+	case IsPermanent(err) || job.Attempts >= job.MaxAttempts:
+		logger.Error("Job failed, moved to dead letter", slog.Any("error", err))
+		metrics.JobsProcessed.WithLabelValues(job.Kind, metrics.JobDead).Inc()
+		return w.finish(db, job, domain.StatusDead, err.Error(), nil)
+
+	default:
+		retryAt := w.now().Add(kind.options.Backoff(job.Attempts))
+		logger.Warn("Job failed, will retry", slog.Time("retry_at", retryAt), slog.Any("error", err))
+		metrics.JobsProcessed.WithLabelValues(job.Kind, metrics.JobRetried).Inc()
+		return w.finish(db, job, domain.StatusQueued, err.Error(), &retryAt)
+	}
+}
+
+// finish records the outcome of an attempt; runAt schedules the next one when the job is queued again
+// The update is guarded by the lock, so a worker whose job was reclaimed after its lock expired cannot overwrite it
+func (w *Worker) finish(db *gorm.DB, job *domain.Job, status domain.Status, lastError string, runAt *time.Time) error {
+	now := w.now()
+	updates := "status = ?, last_error = ?, locked_by = '', locked_until = NULL, updated_at = ?"
+	args := []interface{}{status, lastError, now}
+	if runAt != nil {
+		updates += ", run_at = ?"
+		args = append(args, *runAt)
+	} else {
+		updates += ", finished_at = ?"
+		args = append(args, now)
+	}
+	args = append(args, job.ID, job.LockedBy, job.Attempts)
+
+	result := db.Exec("UPDATE jobs SET "+updates+" WHERE id = ? AND locked_by = ? AND attempts = ?", args...)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("job was reclaimed by another worker")
+	}
+	return nil
+}
+
+// prune deletes succeeded jobs older than succeededRetention
+func (w *Worker) prune(ctx context.Context) {
+	cutoff := w.now().Add(-succeededRetention)
+	if err := w.db.WithContext(ctx).Exec("DELETE FROM jobs WHERE status = ? AND finished_at < ?", domain.StatusSucceeded, cutoff).Error; err != nil && ctx.Err() == nil {
+		logging.FromContext(ctx).Error("Failed to prune succeeded jobs", slog.Any("error", err))
+	}
+}
+
+// workerID identifies this process in locked_by
+func workerID() string {
+	host, _ := os.Hostname()
+	suffix := make([]byte, 4)
+	_, _ = rand.Read(suffix)
+	return fmt.Sprintf("%s-%d-%s", host, os.Getpid(), hex.EncodeToString(suffix))
+}
@@ -0,0 +1,268 @@
+package queue
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/gorm"
+	auditDomain "thothix-backend/internal/audit/domain"
+	auditHooks "thothix-backend/internal/audit/hooks"
+	"thothix-backend/internal/jobs/domain"
+	sharedMiddleware "thothix-backend/internal/shared/middleware"
+	sharedTesting "thothix-backend/internal/shared/testing"
+)
+
+type greeting struct {
+	Name string `json:"name"`
+}
+
+type WorkerTestSuite struct {
+	suite.Suite
+	container *sharedTesting.PostgresTestContainer
+}
+
+func (suite *WorkerTestSuite) SetupSuite() {
+	suite.container = sharedTesting.GetSharedTestContainer(
+		suite.T(),
+		"jobs/queue",
+		[]interface{}{&auditDomain.AuditLog{}, &domain.Job{}},
+	)
+	// Jobs are attributed to their creator through the audit callbacks
+	assert.NoError(suite.T(), auditHooks.Register(suite.container.DB))
+}
+
+// reload reads the stored state of a job
+func (suite *WorkerTestSuite) reload(db *gorm.DB, id string) domain.Job {
+	var job domain.Job
+	assert.NoError(suite.T(), db.Where("id = ?", id).First(&job).Error)
+	return job
+}
+
+func (suite *WorkerTestSuite) TestRunOnce_DecodesPayloadAndRunsAsCreator() {
+	suite.container.WithTransaction(func(db *gorm.DB) {
+		// Arrange
+		registry := NewRegistry()
+		var received greeting
+		var actor string
+		kind := Register(registry, "test.greet", Options{}, func(ctx context.Context, db *gorm.DB, payload greeting) error {
+			received = payload
+			actor, _ = sharedMiddleware.UserIDFromContext(ctx)
+			return nil
+		})
+		adminDB := db.WithContext(sharedMiddleware.WithUserID(context.Background(), "admin-1"))
+		job, err := kind.Enqueue(adminDB, greeting{Name: "Ada"})
+		assert.NoError(suite.T(), err)
+
+		// Act
+		ran, err := NewWorker(db, registry, nil, time.Second).RunOnce(context.Background(), DefaultQueue)
+
+		// Assert
+		assert.NoError(suite.T(), err)
+		assert.True(suite.T(), ran)
+		assert.Equal(suite.T(), greeting{Name: "Ada"}, received)
+		assert.Equal(suite.T(), "admin-1", actor)
+		stored := suite.reload(db, job.ID)
+		assert.Equal(suite.T(), domain.StatusSucceeded, stored.Status)
+		assert.Equal(suite.T(), 1, stored.Attempts)
+		assert.NotNil(suite.T(), stored.FinishedAt)
+	})
+}
+
+func (suite *WorkerTestSuite) TestRunOnce_RetriesWithBackoffThenDeadLetters() {
+	suite.container.WithTransaction(func(db *gorm.DB) {
+		// Arrange
+		registry := NewRegistry()
+		kind := Register(registry, "test.flaky", Options{
+			MaxAttempts: 2,
+			Backoff:     func(int) time.Duration { return time.Minute },
+		}, func(context.Context, *gorm.DB, greeting) error {
+			return errors.New("smtp unavailable")
+		})
+		job, err := kind.Enqueue(db, greeting{Name: "Ada"})
+		assert.NoError(suite.T(), err)
+		worker := NewWorker(db, registry, nil, time.Second)
+		now := time.Now()
+		worker.now = func() time.Time { return now }
+
+		// Act: the first failure is retried after the backoff
+		ran, err := worker.RunOnce(context.Background(), DefaultQueue)
+		assert.NoError(suite.T(), err)
+		assert.True(suite.T(), ran)
+
+		// Assert
+		stored := suite.reload(db, job.ID)
+		assert.Equal(suite.T(), domain.StatusQueued, stored.Status)
+		assert.Equal(suite.T(), 1, stored.Attempts)
+		assert.Equal(suite.T(), "smtp unavailable", stored.LastError)
+		assert.WithinDuration(suite.T(), now.Add(time.Minute), stored.RunAt, time.Second)
+
+		ran, err = worker.RunOnce(context.Background(), DefaultQueue)
+		assert.NoError(suite.T(), err)
+		assert.False(suite.T(), ran, "the retry is not due before the backoff elapses")
+
+		// Act: the last attempt fails too
+		now = now.Add(2 * time.Minute)
+		ran, err = worker.RunOnce(context.Background(), DefaultQueue)
+		assert.NoError(suite.T(), err)
+		assert.True(suite.T(), ran)
+
+		// Assert
+		stored = suite.reload(db, job.ID)
+		assert.Equal(suite.T(), domain.StatusDead, stored.Status)
+		assert.Equal(suite.T(), 2, stored.Attempts)
+		assert.NotNil(suite.T(), stored.FinishedAt)
+	})
+}
+
+func (suite *WorkerTestSuite) TestRunOnce_PermanentErrorDeadLettersAtOnce() {
+	suite.container.WithTransaction(func(db *gorm.DB) {
+		// Arrange
+		registry := NewRegistry()
+		kind := Register(registry, "test.permanent", Options{}, func(context.Context, *gorm.DB, greeting) error {
+			return Permanent(errors.New("user was erased"))
+		})
+		job, err := kind.Enqueue(db, greeting{Name: "Ada"})
+		assert.NoError(suite.T(), err)
+
+		// Act
+		_, err = NewWorker(db, registry, nil, time.Second).RunOnce(context.Background(), DefaultQueue)
+
+		// Assert
+		assert.NoError(suite.T(), err)
+		stored := suite.reload(db, job.ID)
+		assert.Equal(suite.T(), domain.StatusDead, stored.Status)
+		assert.Equal(suite.T(), 1, stored.Attempts)
+		assert.Equal(suite.T(), "user was erased", stored.LastError)
+	})
+}
+
+func (suite *WorkerTestSuite) TestRunOnce_WaitsForRunAt() {
+	suite.container.WithTransaction(func(db *gorm.DB) {
+		// Arrange
+		registry := NewRegistry()
+		kind := Register(registry, "test.later", Options{}, noopGreeting)
+		_, err := kind.Enqueue(db, greeting{Name: "Ada"}, RunAt(time.Now().Add(time.Hour)))
+		assert.NoError(suite.T(), err)
+
+		// Act
+		ran, err := NewWorker(db, registry, nil, time.Second).RunOnce(context.Background(), DefaultQueue)
+
+		// Assert
+		assert.NoError(suite.T(), err)
+		assert.False(suite.T(), ran)
+	})
+}
+
+func (suite *WorkerTestSuite) TestEnqueue_UniqueDeduplicatesActiveJobs() {
+	suite.container.WithTransaction(func(db *gorm.DB) {
+		// Arrange
+		registry := NewRegistry()
+		kind := Register(registry, "test.digest", Options{}, func(context.Context, *gorm.DB, greeting) error {
+			return Permanent(errors.New("digest template missing"))
+		})
+
+		// Act
+		first, err := kind.Enqueue(db, greeting{Name: "Ada"}, Unique("user-1"))
+		assert.NoError(suite.T(), err)
+		second, err := kind.Enqueue(db, greeting{Name: "Ada"}, Unique("user-1"))
+		assert.NoError(suite.T(), err)
+		other, err := kind.Enqueue(db, greeting{Name: "Grace"}, Unique("user-2"))
+		assert.NoError(suite.T(), err)
+
+		// Assert
+		assert.Equal(suite.T(), first.ID, second.ID)
+		assert.NotEqual(suite.T(), first.ID, other.ID)
+
+		// Once the job is dead its key is free again
+		_, err = NewWorker(db, registry, nil, time.Second).RunOnce(context.Background(), DefaultQueue)
+		assert.NoError(suite.T(), err)
+		third, err := kind.Enqueue(db, greeting{Name: "Ada"}, Unique("user-1"))
+		assert.NoError(suite.T(), err)
+		assert.NotEqual(suite.T(), first.ID, third.ID)
+	})
+}
+
+func (suite *WorkerTestSuite) TestClaim_RespectsQueueConcurrency() {
+	suite.container.WithTransaction(func(db *gorm.DB) {
+		// Arrange
+		registry := NewRegistry()
+		kind := Register(registry, "test.export", Options{Queue: "exports"}, noopGreeting)
+		for _, name := range []string{"Ada", "Grace", "Linus"} {
+			_, err := kind.Enqueue(db, greeting{Name: name})
+			assert.NoError(suite.T(), err)
+		}
+		worker := NewWorker(db, registry, map[string]int{"exports": 2}, time.Second)
+
+		// Act
+		first, err := worker.claim(context.Background(), "exports", 2)
+		assert.NoError(suite.T(), err)
+		second, err := worker.claim(context.Background(), "exports", 2)
+		assert.NoError(suite.T(), err)
+		third, err := worker.claim(context.Background(), "exports", 2)
+		assert.NoError(suite.T(), err)
+
+		// Assert
+		assert.NotNil(suite.T(), first)
+		assert.NotNil(suite.T(), second)
+		assert.Nil(suite.T(), third, "two jobs already run on the queue")
+	})
+}
+
+func (suite *WorkerTestSuite) TestClaim_ReclaimsAbandonedJobs() {
+	suite.container.WithTransaction(func(db *gorm.DB) {
+		// Arrange
+		registry := NewRegistry()
+		kind := Register(registry, "test.abandoned", Options{MaxAttempts: 2, Timeout: time.Minute}, noopGreeting)
+		exhausted, err := kind.Enqueue(db, greeting{Name: "Grace"})
+		assert.NoError(suite.T(), err)
+		retried, err := kind.Enqueue(db, greeting{Name: "Ada"})
+		assert.NoError(suite.T(), err)
+		expired := time.Now().Add(-time.Minute)
+		assert.NoError(suite.T(), db.Exec("UPDATE jobs SET status = ?, attempts = 1, locked_by = 'gone', locked_until = ? WHERE id = ?",
+			domain.StatusRunning, expired, retried.ID).Error)
+		assert.NoError(suite.T(), db.Exec("UPDATE jobs SET status = ?, attempts = 2, locked_by = 'gone', locked_until = ? WHERE id = ?",
+			domain.StatusRunning, expired, exhausted.ID).Error)
+		worker := NewWorker(db, registry, nil, time.Second)
+
+		// Act
+		claimed, err := worker.claim(context.Background(), DefaultQueue, 1)
+
+		// Assert
+		assert.NoError(suite.T(), err)
+		if assert.NotNil(suite.T(), claimed) {
+			assert.Equal(suite.T(), retried.ID, claimed.ID)
+			assert.Equal(suite.T(), 2, claimed.Attempts)
+			assert.Equal(suite.T(), worker.id, claimed.LockedBy)
+		}
+		assert.Equal(suite.T(), domain.StatusDead, suite.reload(db, exhausted.ID).Status)
+	})
+}
+
+func (suite *WorkerTestSuite) TestClaim_SkipsUnknownKinds() {
+	suite.container.WithTransaction(func(db *gorm.DB) {
+		// Arrange
+		newer := NewRegistry()
+		kind := Register(newer, "test.from_newer_release", Options{}, noopGreeting)
+		_, err := kind.Enqueue(db, greeting{Name: "Ada"})
+		assert.NoError(suite.T(), err)
+		older := NewRegistry()
+		Register(older, "test.known", Options{}, noopGreeting)
+
+		// Act
+		claimed, err := NewWorker(db, older, nil, time.Second).claim(context.Background(), DefaultQueue, 1)
+
+		// Assert
+		assert.NoError(suite.T(), err)
+		assert.Nil(suite.T(), claimed)
+	})
+}
+
+func noopGreeting(context.Context, *gorm.DB, greeting) error { return nil }
+
+func TestWorkerTestSuite(t *testing.T) {
+	suite.Run(t, new(WorkerTestSuite))
+}
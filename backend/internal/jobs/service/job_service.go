@@ -0,0 +1,209 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"thothix-backend/internal/jobs/domain"
+	jobDto "thothix-backend/internal/jobs/dto"
+	"thothix-backend/internal/jobs/mappers"
+	"thothix-backend/internal/shared/constants"
+	"thothix-backend/internal/shared/dto"
+)
+
+type JobService struct {
+	db     *gorm.DB
+	mapper *mappers.JobMapper
+	now    func() time.Time
+}
+
+func NewJobService(db *gorm.DB) *JobService {
+	return &JobService{
+		db:     db,
+		mapper: mappers.NewJobMapper(),
+		now:    time.Now,
+	}
+}
+
+// WithContext returns a copy of the service whose database session carries ctx
+func (s *JobService) WithContext(ctx context.Context) *JobService {
+	return &JobService{
+		db:     s.db.WithContext(ctx),
+		mapper: s.mapper,
+		now:    s.now,
+	}
+}
+
+// GetJobs retrieves a filtered, paginated list of jobs, most recently scheduled first
+func (s *JobService) GetJobs(req *jobDto.JobFilterRequest) *jobDto.GetJobsResponse {
+	return jobDto.NewGetJobsResponse(func() dto.Validation[*jobDto.JobListDto] {
+		var validationErrors []dto.Error
+
+		// Validation
+		if req == nil {
+			return dto.Failure[*jobDto.JobListDto](dto.NewError(constants.ValidationError, "Filter request cannot be nil", nil))
+		}
+
+		if req.Page < 1 {
+			validationErrors = append(validationErrors, dto.NewError(constants.ValidationError, "Page must be greater than 0", nil))
+		}
+
+		if req.PerPage < 1 || req.PerPage > constants.MaxPerPage {
+			validationErrors = append(validationErrors, dto.NewError(constants.ValidationError, "Per page must be between 1 and 100", nil))
+		}
+
+		if req.Status != "" && !domain.Status(req.Status).IsValid() {
+			validationErrors = append(validationErrors, dto.NewError(constants.ValidationError, "Status must be one of queued, running, succeeded, dead", map[string]string{"status": req.Status}))
+		}
+
+		if len(validationErrors) > 0 {
+			return dto.Failure[*jobDto.JobListDto](validationErrors...)
+		}
+
+		// Business logic
+		query := s.db.Model(&domain.Job{})
+		if req.Queue != "" {
+			query = query.Where("queue = ?", req.Queue)
+		}
+		if req.Kind != "" {
+			query = query.Where("kind = ?", req.Kind)
+		}
+		if req.Status != "" {
+			query = query.Where("status = ?", req.Status)
+		}
+
+		var total int64
+		if err := query.Count(&total).Error; err != nil {
+			panic(err)
+		}
+
+		var jobs []domain.Job
+		offset := (req.Page - 1) * req.PerPage
+		if err := query.Order("run_at DESC, created_at DESC").Offset(offset).Limit(req.PerPage).Find(&jobs).Error; err != nil {
+			panic(err)
+		}
+
+		response := jobDto.NewJobListDto(s.mapper.ModelsToDtos(jobs), total, req.Page, req.PerPage)
+		return dto.Success(response)
+	})
+}
+
+// GetJob retrieves a job with its payload and last error
+func (s *JobService) GetJob(id string) *jobDto.JobResponse {
+	return jobDto.NewJobResponse(func() dto.Validation[*jobDto.JobDto] {
+		var job domain.Job
+		if err := s.db.Where("id::text = ?", id).First(&job).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return dto.Failure[*jobDto.JobDto](dto.NewError(constants.NotFoundError, "Job not found", map[string]string{"id": id}))
+			}
+			panic(err)
+		}
+		return dto.Success(s.mapper.ModelToDto(&job))
+	})
+}
+
+// GetQueueStats counts the jobs of every queue by status
+func (s *JobService) GetQueueStats() *jobDto.QueueStatsResponse {
+	return jobDto.NewQueueStatsResponse(func() dto.Validation[[]jobDto.QueueStatsDto] {
+		var rows []struct {
+			Queue     string
+			Queued    int64
+			Running   int64
+			Succeeded int64
+			Dead      int64
+			Due       int64
+			OldestDue *time.Time
+		}
+		now := s.now()
+		if err := s.db.Model(&domain.Job{}).
+			Select(`queue,
+				count(*) FILTER (WHERE status = ?) AS queued,
+				count(*) FILTER (WHERE status = ?) AS running,
+				count(*) FILTER (WHERE status = ?) AS succeeded,
+				count(*) FILTER (WHERE status = ?) AS dead,
+				count(*) FILTER (WHERE status = ? AND run_at <= ?) AS due,
+				min(run_at) FILTER (WHERE status = ? AND run_at <= ?) AS oldest_due`,
+				domain.StatusQueued, domain.StatusRunning, domain.StatusSucceeded, domain.StatusDead,
+				domain.StatusQueued, now, domain.StatusQueued, now).
+			Group("queue").
+			Order("queue").
+			Scan(&rows).Error; err != nil {
+			panic(err)
+		}
+
+		stats := make([]jobDto.QueueStatsDto, 0, len(rows))
+		for _, row := range rows {
+			stat := jobDto.QueueStatsDto{
+				Queue:     row.Queue,
+				Queued:    row.Queued,
+				Running:   row.Running,
+				Succeeded: row.Succeeded,
+				Dead:      row.Dead,
+				Due:       row.Due,
+			}
+			if row.OldestDue != nil {
+				stat.OldestDue = row.OldestDue.Format(time.RFC3339)
+			}
+			stats = append(stats, stat)
+		}
+		return dto.Success(stats)
+	})
+}
+
+// RetryJob queues a dead job again with a fresh set of attempts
+// A unique job cannot be retried while another job holds its key
+func (s *JobService) RetryJob(id string) *jobDto.JobResponse {
+	return jobDto.NewJobResponse(func() dto.Validation[*jobDto.JobDto] {
+		var (
+			job      domain.Job
+			response dto.Validation[*jobDto.JobDto]
+		)
+		err := s.db.Transaction(func(tx *gorm.DB) error {
+			if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id::text = ?", id).First(&job).Error; err != nil {
+				if errors.Is(err, gorm.ErrRecordNotFound) {
+					response = dto.Failure[*jobDto.JobDto](dto.NewError(constants.NotFoundError, "Job not found", map[string]string{"id": id}))
+					return nil
+				}
+				return err
+			}
+
+			if !job.IsDead() {
+				response = dto.Failure[*jobDto.JobDto](dto.NewError(constants.ConflictError, "Only dead jobs can be retried", map[string]string{"status": string(job.Status)}))
+				return nil
+			}
+
+			if job.UniqueKey != nil {
+				var active int64
+				if err := tx.Model(&domain.Job{}).
+					Where("unique_key = ? AND status IN ? AND id <> ?", *job.UniqueKey, []domain.Status{domain.StatusQueued, domain.StatusRunning}, job.ID).
+					Count(&active).Error; err != nil {
+					return err
+				}
+				if active > 0 {
+					response = dto.Failure[*jobDto.JobDto](dto.NewError(constants.ConflictError, "Another job with the same unique key is already queued or running", map[string]string{"unique_key": *job.UniqueKey}))
+					return nil
+				}
+			}
+
+			// Saved through the model so the retry is audited like other admin changes
+			job.Status = domain.StatusQueued
+			job.RunAt = s.now()
+			job.Attempts = 0
+			job.LastError = ""
+			job.FinishedAt = nil
+			if err := tx.Model(&job).Select("status", "run_at", "attempts", "last_error", "finished_at").Updates(&job).Error; err != nil {
+				return err
+			}
+			response = dto.Success(s.mapper.ModelToDto(&job))
+			return nil
+		})
+		if err != nil {
+			panic(err)
+		}
+		return response
+	})
+}
@@ -0,0 +1,20 @@
+package service
+
+import (
+	"context"
+
+	jobDto "thothix-backend/internal/jobs/dto"
+)
+
+// JobServiceInterface defines the contract for inspecting and retrying background jobs using Response pattern
+type JobServiceInterface interface {
+	GetJobs(req *jobDto.JobFilterRequest) *jobDto.GetJobsResponse
+	GetJob(id string) *jobDto.JobResponse
+	GetQueueStats() *jobDto.QueueStatsResponse
+	RetryJob(id string) *jobDto.JobResponse
+}
+
+// ContextAwareJobService is implemented by services that can bind their database session to a request context
+type ContextAwareJobService interface {
+	WithContext(ctx context.Context) *JobService
+}
@@ -0,0 +1,187 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/gorm"
+	auditDomain "thothix-backend/internal/audit/domain"
+	auditHooks "thothix-backend/internal/audit/hooks"
+	"thothix-backend/internal/jobs/domain"
+	jobDto "thothix-backend/internal/jobs/dto"
+	"thothix-backend/internal/shared/constants"
+	"thothix-backend/internal/shared/dto"
+	sharedMiddleware "thothix-backend/internal/shared/middleware"
+	sharedTesting "thothix-backend/internal/shared/testing"
+)
+
+type JobServiceTestSuite struct {
+	suite.Suite
+	container *sharedTesting.PostgresTestContainer
+}
+
+func (suite *JobServiceTestSuite) SetupSuite() {
+	suite.container = sharedTesting.GetSharedTestContainer(
+		suite.T(),
+		"jobs/service",
+		[]interface{}{&auditDomain.AuditLog{}, &domain.Job{}},
+	)
+	// Retries are attributed to the admin through the audit callbacks
+	assert.NoError(suite.T(), auditHooks.Register(suite.container.DB))
+}
+
+// createJob stores a job of the digest kind in the given status
+func (suite *JobServiceTestSuite) createJob(db *gorm.DB, queue string, status domain.Status, uniqueKey *string) *domain.Job {
+	now := time.Now()
+	job := &domain.Job{
+		Queue:       queue,
+		Kind:        "mail.digest",
+		Payload:     json.RawMessage(`{"user_id":"user-1"}`),
+		Status:      status,
+		RunAt:       now,
+		Attempts:    3,
+		MaxAttempts: 3,
+		UniqueKey:   uniqueKey,
+		LastError:   "smtp unavailable",
+	}
+	if status == domain.StatusDead || status == domain.StatusSucceeded {
+		job.FinishedAt = &now
+	}
+	assert.NoError(suite.T(), db.Create(job).Error)
+	return job
+}
+
+func (suite *JobServiceTestSuite) TestGetJobs_FiltersByStatus() {
+	suite.container.WithTransaction(func(db *gorm.DB) {
+		// Arrange
+		dead := suite.createJob(db, "mail", domain.StatusDead, nil)
+		suite.createJob(db, "mail", domain.StatusSucceeded, nil)
+		service := NewJobService(db)
+
+		// Act
+		response := service.GetJobs(&jobDto.JobFilterRequest{
+			PaginationRequest: dto.PaginationRequest{Page: 1, PerPage: 20},
+			Queue:             "mail",
+			Status:            string(domain.StatusDead),
+		})
+
+		// Assert
+		result := sharedTesting.AssertSuccessWithValue(suite.T(), response.Response)
+		if assert.Len(suite.T(), result.Items, 1) {
+			assert.Equal(suite.T(), dead.ID, result.Items[0].ID)
+		}
+	})
+}
+
+func (suite *JobServiceTestSuite) TestGetJobs_InvalidStatus() {
+	suite.container.WithTransaction(func(db *gorm.DB) {
+		// Act
+		response := NewJobService(db).GetJobs(&jobDto.JobFilterRequest{
+			PaginationRequest: dto.PaginationRequest{Page: 1, PerPage: 20},
+			Status:            "stuck",
+		})
+
+		// Assert
+		sharedTesting.AssertValidationErrorWithCode(suite.T(), response.Response, constants.ValidationError)
+	})
+}
+
+func (suite *JobServiceTestSuite) TestGetQueueStats() {
+	suite.container.WithTransaction(func(db *gorm.DB) {
+		// Arrange
+		suite.createJob(db, "stats", domain.StatusQueued, nil)
+		suite.createJob(db, "stats", domain.StatusQueued, nil)
+		suite.createJob(db, "stats", domain.StatusDead, nil)
+
+		// Act
+		response := NewJobService(db).GetQueueStats()
+
+		// Assert
+		stats := sharedTesting.AssertSuccessWithValue(suite.T(), response.Response)
+		var queue *jobDto.QueueStatsDto
+		for i := range stats {
+			if stats[i].Queue == "stats" {
+				queue = &stats[i]
+			}
+		}
+		if assert.NotNil(suite.T(), queue) {
+			assert.Equal(suite.T(), int64(2), queue.Queued)
+			assert.Equal(suite.T(), int64(2), queue.Due)
+			assert.Equal(suite.T(), int64(1), queue.Dead)
+			assert.NotEmpty(suite.T(), queue.OldestDue)
+		}
+	})
+}
+
+func (suite *JobServiceTestSuite) TestRetryJob_RequeuesDeadJob() {
+	suite.container.WithTransaction(func(db *gorm.DB) {
+		// Arrange
+		job := suite.createJob(db, "mail", domain.StatusDead, nil)
+		service := NewJobService(db).WithContext(sharedMiddleware.WithUserID(context.Background(), "admin-1"))
+
+		// Act
+		response := service.RetryJob(job.ID)
+
+		// Assert
+		result := sharedTesting.AssertSuccessWithValue(suite.T(), response.Response)
+		assert.Equal(suite.T(), string(domain.StatusQueued), result.Status)
+		assert.Equal(suite.T(), 0, result.Attempts)
+
+		var stored domain.Job
+		assert.NoError(suite.T(), db.Where("id = ?", job.ID).First(&stored).Error)
+		assert.Equal(suite.T(), domain.StatusQueued, stored.Status)
+		assert.Nil(suite.T(), stored.FinishedAt)
+
+		var audited int64
+		assert.NoError(suite.T(), db.Model(&auditDomain.AuditLog{}).
+			Where("entity_type = ? AND entity_id = ? AND action = ? AND actor_id = ?", "jobs", job.ID, auditDomain.AuditActionUpdate, "admin-1").
+			Count(&audited).Error)
+		assert.Equal(suite.T(), int64(1), audited)
+	})
+}
+
+func (suite *JobServiceTestSuite) TestRetryJob_OnlyDeadJobs() {
+	suite.container.WithTransaction(func(db *gorm.DB) {
+		// Arrange
+		job := suite.createJob(db, "mail", domain.StatusSucceeded, nil)
+
+		// Act
+		response := NewJobService(db).RetryJob(job.ID)
+
+		// Assert
+		sharedTesting.AssertValidationErrorWithCode(suite.T(), response.Response, constants.ConflictError)
+	})
+}
+
+func (suite *JobServiceTestSuite) TestRetryJob_UniqueKeyTaken() {
+	suite.container.WithTransaction(func(db *gorm.DB) {
+		// Arrange
+		key := "mail.digest:user-1"
+		dead := suite.createJob(db, "mail", domain.StatusDead, &key)
+		suite.createJob(db, "mail", domain.StatusQueued, &key)
+
+		// Act
+		response := NewJobService(db).RetryJob(dead.ID)
+
+		// Assert
+		sharedTesting.AssertValidationErrorWithCode(suite.T(), response.Response, constants.ConflictError)
+	})
+}
+
+func (suite *JobServiceTestSuite) TestRetryJob_NotFound() {
+	suite.container.WithTransaction(func(db *gorm.DB) {
+		// Act
+		response := NewJobService(db).RetryJob("00000000-0000-0000-0000-000000000000")
+
+		// Assert
+		sharedTesting.AssertValidationErrorWithCode(suite.T(), response.Response, constants.NotFoundError)
+	})
+}
+
+func TestJobServiceTestSuite(t *testing.T) {
+	suite.Run(t, new(JobServiceTestSuite))
+}
@@ -0,0 +1,237 @@
+package commands
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+
+	chatDomain "thothix-backend/internal/chat/domain"
+	messageDomain "thothix-backend/internal/message/domain"
+	"thothix-backend/internal/message/posting"
+	"thothix-backend/internal/message/richtext"
+	"thothix-backend/internal/metrics"
+	"thothix-backend/internal/shared/constants"
+	"thothix-backend/internal/shared/dto"
+	sharedMiddleware "thothix-backend/internal/shared/middleware"
+	sharedModels "thothix-backend/internal/shared/models"
+	usersDomain "thothix-backend/internal/users/domain"
+)
+
+// maxReminderDelay caps how far in the future a reminder can be scheduled
+const maxReminderDelay = 365 * 24 * time.Hour
+
+// RegisterBuiltins registers the built-in commands on the given registry
+func RegisterBuiltins(registry *Registry) {
+	registry.Register(&builtin{name: "invite", description: "Invite a user to this channel: /invite @username", run: runInvite})
+	registry.Register(&builtin{name: "leave", description: "Leave this channel", run: runLeave})
+	registry.Register(&builtin{name: "topic", description: "Show or set the channel topic: /topic [text]", run: runTopic})
+	registry.Register(&builtin{name: "remind", description: "Set a reminder: /remind [in] <duration> <text>", run: runRemind})
+	registry.Register(&builtin{name: "me", description: "Post an action message: /me <text>", run: runMe})
+}
+
+// builtin adapts a plain function to the Command interface
+type builtin struct {
+	name        string
+	description string
+	run         func(ctx *Context) (*Result, error)
+}
+
+func (b *builtin) Name() string        { return b.name }
+func (b *builtin) Description() string { return b.description }
+
+func (b *builtin) Execute(ctx *Context) (*Result, error) {
+	return b.run(ctx)
+}
+
+// runInvite adds the mentioned user as a member of the current channel
+// Private channels only take invites from channel managers, and only of users with access to their project
+func runInvite(ctx *Context) (*Result, error) {
+	handle := strings.TrimPrefix(strings.TrimSpace(ctx.Args), "@")
+	if handle == "" || strings.ContainsAny(handle, " \t") {
+		return nil, usageError(ctx.Command, "/invite @username")
+	}
+
+	var channel chatDomain.Channel
+	if err := ctx.DB.Scopes(sharedModels.InWorkspace("channels")).Where("id = ?", ctx.ChannelID).First(&channel).Error; err != nil {
+		return nil, err
+	}
+	if err := channel.LoadIsPrivate(ctx.DB); err != nil {
+		return nil, err
+	}
+	channelType := "channel"
+	if channel.IsPrivate && !sharedModels.HasUserPermission(ctx.DB, ctx.UserID, sharedModels.PermissionChannelManage, &channelType, &ctx.ChannelID) {
+		return nil, dto.NewError(constants.ForbiddenError, "Only channel managers can invite users to a private channel", nil)
+	}
+
+	var invitee usersDomain.User
+	if err := ctx.DB.Scopes(sharedModels.WorkspaceMembers).Where("username = ? OR id::text = ?", handle, handle).First(&invitee).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, dto.NewError(constants.UserNotFoundError, fmt.Sprintf("User @%s not found", handle), nil)
+		}
+		return nil, err
+	}
+	// Memberships of deactivated users are frozen
+	if !invitee.IsActive() {
+		return nil, dto.NewError(constants.UserDeactivatedError, fmt.Sprintf("@%s is deactivated", handle), nil)
+	}
+	projectType := "project"
+	if channel.IsPrivate && !sharedModels.HasUserPermission(ctx.DB, invitee.ID, sharedModels.PermissionProjectRead, &projectType, &channel.ProjectID) {
+		return nil, dto.NewError(constants.ForbiddenError, fmt.Sprintf("@%s has no access to this channel's project", handle), nil)
+	}
+
+	var count int64
+	if err := ctx.DB.Model(&chatDomain.ChannelMember{}).
+		Where("channel_id = ? AND user_id = ?", ctx.ChannelID, invitee.ID).
+		Count(&count).Error; err != nil {
+		return nil, err
+	}
+	if count > 0 {
+		return nil, dto.NewError(constants.ConflictError, fmt.Sprintf("@%s is already a member of this channel", handle), nil)
+	}
+
+	member := chatDomain.ChannelMember{
+		ChannelID: ctx.ChannelID,
+		UserID:    invitee.ID,
+	}
+	if err := ctx.DB.Create(&member).Error; err != nil {
+		return nil, err
+	}
+
+	return &Result{
+		ResponseType: ResponseEphemeral,
+		Text:         fmt.Sprintf("Invited @%s to the channel", handle),
+	}, nil
+}
+
+// runLeave removes the invoking user's membership from the current channel
+func runLeave(ctx *Context) (*Result, error) {
+	result := ctx.DB.Where("channel_id = ? AND user_id = ?", ctx.ChannelID, ctx.UserID).
+		Delete(&chatDomain.ChannelMember{})
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, dto.NewError(constants.CommandUsageError, "You are not a member of this channel", nil)
+	}
+
+	return &Result{
+		ResponseType: ResponseEphemeral,
+		Text:         "You left the channel",
+	}, nil
+}
+
+// runTopic shows the current topic, or updates it and announces the change in the channel
+func runTopic(ctx *Context) (*Result, error) {
+	var channel chatDomain.Channel
+	if err := ctx.DB.Scopes(sharedModels.InWorkspace("channels")).Where("id = ?", ctx.ChannelID).First(&channel).Error; err != nil {
+		return nil, err
+	}
+
+	topic := strings.TrimSpace(ctx.Args)
+	if topic == "" {
+		text := "This channel has no topic"
+		if channel.Topic != "" {
+			text = "Current topic: " + channel.Topic
+		}
+		return &Result{ResponseType: ResponseEphemeral, Text: text}, nil
+	}
+
+	resourceType := "channel"
+	if !sharedModels.HasUserPermission(ctx.DB, ctx.UserID, sharedModels.PermissionChannelUpdate, &resourceType, &ctx.ChannelID) {
+		return nil, dto.NewError(constants.ForbiddenError, "Insufficient permissions to change the channel topic", nil)
+	}
+
+	var message *messageDomain.Message
+	err := ctx.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&channel).Update("topic", topic).Error; err != nil {
+			return err
+		}
+		var err error
+		message, err = createChannelMessage(tx, ctx, messageDomain.MessageTypeSystem, richtext.Plain("set the channel topic: "+topic))
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &Result{
+		ResponseType: ResponseInChannel,
+		Text:         message.Content,
+		Message:      message,
+	}, nil
+}
+
+// runRemind stores a reminder for the invoking user
+func runRemind(ctx *Context) (*Result, error) {
+	const usage = "/remind [in] <duration> <text>, e.g. /remind in 30m check the build"
+
+	fields := strings.Fields(ctx.Args)
+	if len(fields) > 0 && strings.EqualFold(fields[0], "in") {
+		fields = fields[1:]
+	}
+	if len(fields) < 2 {
+		return nil, usageError(ctx.Command, usage)
+	}
+
+	delay, err := time.ParseDuration(fields[0])
+	if err != nil || delay <= 0 || delay > maxReminderDelay {
+		return nil, usageError(ctx.Command, usage)
+	}
+
+	channelID := ctx.ChannelID
+	reminder := messageDomain.Reminder{
+		UserID:    ctx.UserID,
+		ChannelID: &channelID,
+		Text:      strings.Join(fields[1:], " "),
+		RemindAt:  time.Now().UTC().Add(delay),
+	}
+	if workspaceID, ok := sharedMiddleware.WorkspaceIDFromContext(ctx.Ctx); ok {
+		reminder.WorkspaceID = &workspaceID
+	}
+	if err := ctx.DB.Create(&reminder).Error; err != nil {
+		return nil, err
+	}
+
+	return &Result{
+		ResponseType: ResponseEphemeral,
+		Text:         fmt.Sprintf("I will remind you at %s: %s", reminder.RemindAt.Format(time.RFC3339), reminder.Text),
+	}, nil
+}
+
+// runMe posts an action message in the current channel
+func runMe(ctx *Context) (*Result, error) {
+	action := strings.TrimSpace(ctx.Args)
+	if action == "" {
+		return nil, usageError(ctx.Command, "/me <text>")
+	}
+
+	message, err := createChannelMessage(ctx.DB, ctx, messageDomain.MessageTypeAction, richtext.Plain(action))
+	if err != nil {
+		return nil, err
+	}
+
+	return &Result{
+		ResponseType: ResponseInChannel,
+		Text:         message.Content,
+		Message:      message,
+	}, nil
+}
+
+// createChannelMessage posts a message of the given type in the command's channel
+// It is screened against the blocked-word list of the channel's project, as messages sent directly are
+func createChannelMessage(db *gorm.DB, ctx *Context, messageType messageDomain.MessageType, body *richtext.Body) (*messageDomain.Message, error) {
+	channelID := ctx.ChannelID
+	message := &messageDomain.Message{
+		ChannelID: &channelID,
+		SenderID:  ctx.UserID,
+		Type:      messageType,
+	}
+	message.SetBody(body)
+	if err := posting.Post(db, message); err != nil {
+		return nil, err
+	}
+	metrics.MessagesSent.WithLabelValues(metrics.MessageCommand).Inc()
+	return message, nil
+}
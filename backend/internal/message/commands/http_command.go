@@ -0,0 +1,178 @@
+package commands
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+	"gorm.io/gorm"
+
+	"thothix-backend/internal/egress"
+	messageDomain "thothix-backend/internal/message/domain"
+	"thothix-backend/internal/message/richtext"
+	"thothix-backend/internal/shared/constants"
+	"thothix-backend/internal/shared/dto"
+	sharedModels "thothix-backend/internal/shared/models"
+	"thothix-backend/internal/tracing"
+)
+
+const (
+	// SignatureHeader carries the request signature in the "v1,<base64>" format used by Svix webhooks
+	SignatureHeader = "X-Thothix-Signature"
+	// TimestampHeader carries the unix timestamp included in the signed payload
+	TimestampHeader = "X-Thothix-Timestamp"
+
+	customCommandTimeout      = 5 * time.Second
+	customCommandMaxRedirects = 3
+	maxCustomResponseBytes    = 64 * 1024
+	maxCustomResponseTextLen  = 4000 // In characters
+)
+
+// defaultClient calls custom command endpoints. Commands are registered by workspace admins, so the client
+// refuses internal addresses: a command must not read cloud metadata or internal services into a channel
+var defaultClient = egress.NewClient(egress.ClientOptions{Timeout: customCommandTimeout, MaxRedirects: customCommandMaxRedirects})
+
+// HTTPCommandPayload is the JSON body sent to a custom command endpoint
+type HTTPCommandPayload struct {
+	Command   string `json:"command"`
+	Text      string `json:"text"`
+	UserID    string `json:"user_id"`
+	ChannelID string `json:"channel_id"`
+	Timestamp int64  `json:"timestamp"`
+}
+
+// HTTPCommandResponse is the JSON body expected back from a custom command endpoint
+// In-channel responses may declare the rich text format and add blocks, including buttons
+type HTTPCommandResponse struct {
+	ResponseType ResponseType     `json:"response_type"`
+	Text         string           `json:"text"`
+	Format       int              `json:"format"`
+	Blocks       []richtext.Block `json:"blocks,omitempty"`
+}
+
+// HTTPCommand forwards a slash command to an external endpoint with a signed request
+type HTTPCommand struct {
+	definition messageDomain.CustomCommand
+	client     *http.Client
+}
+
+// NewHTTPCommand creates a Command backed by the given custom command definition
+func NewHTTPCommand(definition messageDomain.CustomCommand, client *http.Client) *HTTPCommand {
+	if client == nil {
+		client = defaultClient
+	}
+	return &HTTPCommand{definition: definition, client: client}
+}
+
+// LookupCustomCommand loads a custom command definition from the database
+func LookupCustomCommand(db *gorm.DB, name string) (Command, bool) {
+	var definition messageDomain.CustomCommand
+	if err := db.Scopes(sharedModels.InWorkspace("custom_commands")).Where("name = ?", name).First(&definition).Error; err != nil {
+		return nil, false
+	}
+	return NewHTTPCommand(definition, nil), true
+}
+
+func (h *HTTPCommand) Name() string        { return h.definition.Name }
+func (h *HTTPCommand) Description() string { return h.definition.Description }
+
+// Execute posts the command to the external endpoint and relays its response
+func (h *HTTPCommand) Execute(ctx *Context) (*Result, error) {
+	timestamp := time.Now().Unix()
+	body, err := json.Marshal(HTTPCommandPayload{
+		Command:   h.definition.Name,
+		Text:      ctx.Args,
+		UserID:    ctx.UserID,
+		ChannelID: ctx.ChannelID,
+		Timestamp: timestamp,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx.Ctx, http.MethodPost, h.definition.URL, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(TimestampHeader, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(SignatureHeader, "v1,"+Sign(h.definition.Secret, timestamp, body))
+
+	// The endpoint joins the caller's trace through the W3C traceparent header
+	spanCtx, span := tracing.Start(ctx.Ctx, "command.webhook", trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(
+		attribute.String("command.name", h.definition.Name),
+		attribute.String("http.request.method", http.MethodPost),
+	))
+	defer span.End()
+	otel.GetTextMapPropagator().Inject(spanCtx, propagation.HeaderCarrier(req.Header))
+
+	resp, err := h.client.Do(req)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "endpoint unreachable")
+		return nil, commandFailed(h.definition.Name, "endpoint unreachable")
+	}
+	defer resp.Body.Close()
+	span.SetAttributes(attribute.Int("http.response.status_code", resp.StatusCode))
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		span.SetStatus(codes.Error, fmt.Sprintf("endpoint returned status %d", resp.StatusCode))
+		return nil, commandFailed(h.definition.Name, fmt.Sprintf("endpoint returned status %d", resp.StatusCode))
+	}
+
+	var payload HTTPCommandResponse
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxCustomResponseBytes)).Decode(&payload); err != nil {
+		return nil, commandFailed(h.definition.Name, "invalid endpoint response")
+	}
+	payload.Text = truncate(payload.Text, maxCustomResponseTextLen)
+
+	if payload.ResponseType != ResponseInChannel {
+		return &Result{ResponseType: ResponseEphemeral, Text: payload.Text}, nil
+	}
+
+	if payload.Text == "" && len(payload.Blocks) == 0 {
+		return nil, commandFailed(h.definition.Name, "empty channel response")
+	}
+	messageBody, err := richtext.Build(richtext.Input{Format: payload.Format, Content: payload.Text, Blocks: payload.Blocks}, richtext.Options{AllowButtons: true})
+	if err != nil {
+		return nil, commandFailed(h.definition.Name, "invalid channel response: "+err.Error())
+	}
+
+	message, err := createChannelMessage(ctx.DB, ctx, messageDomain.MessageTypeCommand, messageBody)
+	if err != nil {
+		return nil, err
+	}
+	return &Result{ResponseType: ResponseInChannel, Text: message.Content, Message: message}, nil
+}
+
+// Sign computes the base64 HMAC-SHA256 of "timestamp.body" with the command secret
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(fmt.Sprintf("%d.%s", timestamp, body)))
+	return base64.StdEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// truncate cuts text to at most limit characters, never inside a multi-byte character
+func truncate(text string, limit int) string {
+	if runes := []rune(text); len(runes) > limit {
+		return string(runes[:limit])
+	}
+	return text
+}
+
+// commandFailed builds a structured error for a failed custom command
+func commandFailed(command, reason string) error {
+	return dto.NewError(constants.CommandFailedError, fmt.Sprintf("Command /%s failed: %s", command, reason), map[string]string{"command": command})
+}
@@ -0,0 +1,205 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"gorm.io/gorm"
+
+	messageDomain "thothix-backend/internal/message/domain"
+	"thothix-backend/internal/shared/constants"
+	"thothix-backend/internal/shared/dto"
+)
+
+// ResponseType defines who can see the result of a command
+type ResponseType string
+
+const (
+	ResponseEphemeral ResponseType = "ephemeral"  // Only the invoking user sees the response
+	ResponseInChannel ResponseType = "in_channel" // The response is stored as a channel message
+)
+
+// Context carries everything a command handler needs to execute
+type Context struct {
+	Ctx       context.Context
+	DB        *gorm.DB
+	UserID    string
+	ChannelID string
+	Command   string // Command name without the leading slash
+	Args      string // Raw text following the command name
+}
+
+// Result represents the outcome of a command execution
+type Result struct {
+	Command      string                 `json:"command"`
+	ResponseType ResponseType           `json:"response_type"`
+	Text         string                 `json:"text"`
+	Message      *messageDomain.Message `json:"message,omitempty"` // Set when the command stored a channel message
+}
+
+// Command is implemented by every slash command, built-in or custom
+type Command interface {
+	Name() string
+	Description() string
+	Execute(ctx *Context) (*Result, error)
+}
+
+// Registry holds the built-in commands and resolves custom commands stored in the database
+type Registry struct {
+	mu       sync.RWMutex
+	commands map[string]Command
+	custom   func(db *gorm.DB, name string) (Command, bool)
+}
+
+// NewRegistry creates an empty Registry that only resolves explicitly registered commands
+func NewRegistry() *Registry {
+	return &Registry{commands: make(map[string]Command)}
+}
+
+// NewDefaultRegistry creates a Registry with all built-in commands and custom command lookup enabled
+func NewDefaultRegistry() *Registry {
+	registry := NewRegistry()
+	RegisterBuiltins(registry)
+	registry.custom = LookupCustomCommand
+	return registry
+}
+
+// Register adds a command to the registry, replacing any command with the same name
+func (r *Registry) Register(cmd Command) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.commands[strings.ToLower(cmd.Name())] = cmd
+}
+
+// IsBuiltin reports whether a name is taken by a registered command
+func (r *Registry) IsBuiltin(name string) bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	_, exists := r.commands[strings.ToLower(name)]
+	return exists
+}
+
+// Commands returns the registered commands sorted by name
+func (r *Registry) Commands() []Command {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	list := make([]Command, 0, len(r.commands))
+	for _, cmd := range r.commands {
+		list = append(list, cmd)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Name() < list[j].Name() })
+	return list
+}
+
+// Lookup resolves a command by name, falling back to custom commands when enabled
+func (r *Registry) Lookup(db *gorm.DB, name string) (Command, bool) {
+	r.mu.RLock()
+	cmd, exists := r.commands[strings.ToLower(name)]
+	r.mu.RUnlock()
+	if exists {
+		return cmd, true
+	}
+
+	if r.custom != nil {
+		return r.custom(db, strings.ToLower(name))
+	}
+	return nil, false
+}
+
+// Execute parses the input and runs the matching command
+// Returns a dto.Error for unknown commands and usage problems so callers can map them to 4xx responses
+func (r *Registry) Execute(ctx *Context, input string) (*Result, error) {
+	name, args, ok := Parse(input)
+	if !ok {
+		return nil, dto.NewError(constants.CommandUsageError, "Invalid command", nil)
+	}
+
+	cmd, exists := r.Lookup(ctx.DB, name)
+	if !exists {
+		return nil, dto.NewError(constants.CommandNotFoundError, fmt.Sprintf("Unknown command /%s", name), map[string]string{"command": name})
+	}
+
+	ctx.Command = cmd.Name()
+	ctx.Args = args
+
+	result, err := cmd.Execute(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if result.Command == "" {
+		result.Command = cmd.Name()
+	}
+	if result.ResponseType == "" {
+		result.ResponseType = ResponseEphemeral
+	}
+	return result, nil
+}
+
+// IsCommand reports whether message content should be routed to the command registry
+// Content starting with "//" is treated as an escaped literal slash and stored as text
+func IsCommand(content string) bool {
+	return strings.HasPrefix(content, "/") && !strings.HasPrefix(content, "//")
+}
+
+// Unescape removes the escaping slash from content that starts with "//"
+func Unescape(content string) string {
+	if strings.HasPrefix(content, "//") {
+		return content[1:]
+	}
+	return content
+}
+
+// Parse splits "/name args..." into the lowercase command name and the trimmed argument text
+func Parse(input string) (name, args string, ok bool) {
+	input = strings.TrimSpace(input)
+	if !strings.HasPrefix(input, "/") {
+		return "", "", false
+	}
+
+	body := input[1:]
+	if idx := strings.IndexAny(body, " \t\n"); idx >= 0 {
+		name, args = body[:idx], strings.TrimSpace(body[idx+1:])
+	} else {
+		name = body
+	}
+
+	if !IsValidName(name) {
+		return "", "", false
+	}
+	return strings.ToLower(name), args, true
+}
+
+// IsValidName checks that a command name only contains letters, digits, '-' and '_'
+func IsValidName(name string) bool {
+	if name == "" || len(name) > 32 {
+		return false
+	}
+	for _, r := range name {
+		isLetter := (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z')
+		isDigit := r >= '0' && r <= '9'
+		if !isLetter && !isDigit && r != '-' && r != '_' {
+			return false
+		}
+	}
+	return true
+}
+
+// AsError extracts the structured error from a command error, if any
+// Errors without structure are unexpected failures (database, encoding) and should be treated as system errors
+func AsError(err error) (dto.Error, bool) {
+	var cmdErr dto.Error
+	if errors.As(err, &cmdErr) {
+		return cmdErr, true
+	}
+	return dto.Error{}, false
+}
+
+// usageError builds a structured usage error for a command
+func usageError(command, usage string) error {
+	return dto.NewError(constants.CommandUsageError, "Usage: "+usage, map[string]string{"command": command})
+}
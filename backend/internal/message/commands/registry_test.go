@@ -0,0 +1,279 @@
+package commands
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	messageDomain "thothix-backend/internal/message/domain"
+	"thothix-backend/internal/message/richtext"
+	"thothix-backend/internal/shared/constants"
+)
+
+// echoCommand is a test command that echoes its arguments
+type echoCommand struct{}
+
+func (echoCommand) Name() string        { return "echo" }
+func (echoCommand) Description() string { return "Echo arguments" }
+func (echoCommand) Execute(ctx *Context) (*Result, error) {
+	return &Result{Text: ctx.Args}, nil
+}
+
+type CommandsTestSuite struct {
+	suite.Suite
+}
+
+func (suite *CommandsTestSuite) TestParse() {
+	// Arrange
+	cases := []struct {
+		input    string
+		wantName string
+		wantArgs string
+		wantOK   bool
+	}{
+		{"/me waves", "me", "waves", true},
+		{"/TOPIC  Release planning ", "topic", "Release planning", true},
+		{"/leave", "leave", "", true},
+		{"hello", "", "", false},
+		{"/", "", "", false},
+		{"/bad!name args", "", "", false},
+	}
+
+	for _, tc := range cases {
+		// Act
+		name, args, ok := Parse(tc.input)
+
+		// Assert
+		assert.Equal(suite.T(), tc.wantOK, ok, tc.input)
+		assert.Equal(suite.T(), tc.wantName, name, tc.input)
+		assert.Equal(suite.T(), tc.wantArgs, args, tc.input)
+	}
+}
+
+func (suite *CommandsTestSuite) TestIsCommandAndUnescape() {
+	assert.True(suite.T(), IsCommand("/me waves"))
+	assert.False(suite.T(), IsCommand("//not a command"))
+	assert.False(suite.T(), IsCommand("plain text"))
+	assert.Equal(suite.T(), "/not a command", Unescape("//not a command"))
+	assert.Equal(suite.T(), "plain text", Unescape("plain text"))
+}
+
+func (suite *CommandsTestSuite) TestExecute_RegisteredCommand() {
+	// Arrange
+	registry := NewRegistry()
+	registry.Register(echoCommand{})
+
+	// Act
+	result, err := registry.Execute(&Context{UserID: "user-1"}, "/echo hello world")
+
+	// Assert
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "echo", result.Command)
+	assert.Equal(suite.T(), ResponseEphemeral, result.ResponseType)
+	assert.Equal(suite.T(), "hello world", result.Text)
+}
+
+func (suite *CommandsTestSuite) TestExecute_UnknownCommand() {
+	// Arrange
+	registry := NewRegistry()
+
+	// Act
+	_, err := registry.Execute(&Context{}, "/missing")
+
+	// Assert
+	cmdErr, ok := AsError(err)
+	assert.True(suite.T(), ok)
+	assert.Equal(suite.T(), constants.CommandNotFoundError, cmdErr.Code)
+}
+
+func (suite *CommandsTestSuite) TestDefaultRegistry_Builtins() {
+	// Arrange
+	registry := NewDefaultRegistry()
+
+	// Assert
+	for _, name := range []string{"invite", "leave", "topic", "remind", "me"} {
+		assert.True(suite.T(), registry.IsBuiltin(name), name)
+	}
+	assert.Len(suite.T(), registry.Commands(), 5)
+}
+
+func (suite *CommandsTestSuite) TestBuiltins_UsageErrors() {
+	// Arrange
+	registry := NewDefaultRegistry()
+
+	for _, input := range []string{"/invite", "/me", "/remind soon do it", "/remind 10m"} {
+		// Act
+		_, err := registry.Execute(&Context{}, input)
+
+		// Assert
+		cmdErr, ok := AsError(err)
+		assert.True(suite.T(), ok, input)
+		assert.Equal(suite.T(), constants.CommandUsageError, cmdErr.Code, input)
+	}
+}
+
+func (suite *CommandsTestSuite) TestHTTPCommand_SignedEphemeralResponse() {
+	// Arrange
+	const secret = "test-secret"
+	var received HTTPCommandPayload
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		timestamp, _ := strconv.ParseInt(r.Header.Get(TimestampHeader), 10, 64)
+		if r.Header.Get(SignatureHeader) != "v1,"+Sign(secret, timestamp, body) {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		_ = json.Unmarshal(body, &received)
+		_ = json.NewEncoder(w).Encode(HTTPCommandResponse{ResponseType: ResponseEphemeral, Text: "deployed " + received.Text})
+	}))
+	defer server.Close()
+
+	cmd := NewHTTPCommand(messageDomain.CustomCommand{Name: "deploy", URL: server.URL, Secret: secret}, server.Client())
+
+	// Act
+	result, err := cmd.Execute(&Context{Ctx: context.Background(), UserID: "user-1", ChannelID: "channel-1", Args: "staging"})
+
+	// Assert
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), ResponseEphemeral, result.ResponseType)
+	assert.Equal(suite.T(), "deployed staging", result.Text)
+	assert.Equal(suite.T(), "deploy", received.Command)
+	assert.Equal(suite.T(), "user-1", received.UserID)
+	assert.Equal(suite.T(), "channel-1", received.ChannelID)
+}
+
+func (suite *CommandsTestSuite) TestHTTPCommand_EndpointError() {
+	// Arrange
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	cmd := NewHTTPCommand(messageDomain.CustomCommand{Name: "deploy", URL: server.URL, Secret: "s"}, server.Client())
+
+	// Act
+	_, err := cmd.Execute(&Context{Ctx: context.Background()})
+
+	// Assert
+	cmdErr, ok := AsError(err)
+	assert.True(suite.T(), ok)
+	assert.Equal(suite.T(), constants.CommandFailedError, cmdErr.Code)
+	assert.True(suite.T(), strings.Contains(cmdErr.Message, "500"))
+}
+
+func (suite *CommandsTestSuite) TestHTTPCommand_RejectsUnsafeBlocks() {
+	// Arrange
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(HTTPCommandResponse{
+			ResponseType: ResponseInChannel,
+			Text:         "Build **failed**",
+			Format:       richtext.FormatRich,
+			Blocks: []richtext.Block{{Type: richtext.BlockActions, Buttons: []richtext.Button{
+				{Label: "Logs", URL: "javascript:alert(document.cookie)"},
+			}}},
+		})
+	}))
+	defer server.Close()
+
+	cmd := NewHTTPCommand(messageDomain.CustomCommand{Name: "deploy", URL: server.URL, Secret: "s"}, server.Client())
+
+	// Act
+	_, err := cmd.Execute(&Context{Ctx: context.Background()})
+
+	// Assert
+	cmdErr, ok := AsError(err)
+	assert.True(suite.T(), ok)
+	assert.Equal(suite.T(), constants.CommandFailedError, cmdErr.Code)
+	assert.Contains(suite.T(), cmdErr.Message, "button URL")
+}
+
+func (suite *CommandsTestSuite) TestHTTPCommand_PropagatesTraceContext() {
+	// Arrange
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	previousProvider, previousPropagator := otel.GetTracerProvider(), otel.GetTextMapPropagator()
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	defer func() {
+		otel.SetTracerProvider(previousProvider)
+		otel.SetTextMapPropagator(previousPropagator)
+	}()
+
+	var traceparent string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		traceparent = r.Header.Get("traceparent")
+		_ = json.NewEncoder(w).Encode(HTTPCommandResponse{ResponseType: ResponseEphemeral, Text: "ok"})
+	}))
+	defer server.Close()
+
+	cmd := NewHTTPCommand(messageDomain.CustomCommand{Name: "deploy", URL: server.URL, Secret: "s"}, server.Client())
+	ctx, parent := provider.Tracer("test").Start(context.Background(), "request")
+
+	// Act
+	_, err := cmd.Execute(&Context{Ctx: ctx})
+	parent.End()
+
+	// Assert
+	assert.NoError(suite.T(), err)
+	assert.Contains(suite.T(), traceparent, parent.SpanContext().TraceID().String())
+
+	spans := recorder.Ended()
+	assert.Len(suite.T(), spans, 2)
+	assert.Equal(suite.T(), "command.webhook", spans[0].Name())
+	assert.Equal(suite.T(), parent.SpanContext().SpanID(), spans[0].Parent().SpanID())
+}
+
+func (suite *CommandsTestSuite) TestHTTPCommand_RefusesInternalAddresses() {
+	// Arrange
+	called := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+		_ = json.NewEncoder(w).Encode(HTTPCommandResponse{ResponseType: ResponseEphemeral, Text: "secret"})
+	}))
+	defer server.Close()
+
+	cmd := NewHTTPCommand(messageDomain.CustomCommand{Name: "deploy", URL: server.URL, Secret: "s"}, nil)
+
+	// Act
+	_, err := cmd.Execute(&Context{Ctx: context.Background()})
+
+	// Assert
+	cmdErr, ok := AsError(err)
+	assert.True(suite.T(), ok)
+	assert.Equal(suite.T(), constants.CommandFailedError, cmdErr.Code)
+	assert.False(suite.T(), called, "the loopback endpoint must not be reached")
+}
+
+func (suite *CommandsTestSuite) TestHTTPCommand_TruncatesOnCharacterBoundary() {
+	// Arrange
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(HTTPCommandResponse{ResponseType: ResponseEphemeral, Text: strings.Repeat("é", maxCustomResponseTextLen+10)})
+	}))
+	defer server.Close()
+
+	cmd := NewHTTPCommand(messageDomain.CustomCommand{Name: "deploy", URL: server.URL, Secret: "s"}, server.Client())
+
+	// Act
+	result, err := cmd.Execute(&Context{Ctx: context.Background()})
+
+	// Assert
+	assert.NoError(suite.T(), err)
+	assert.True(suite.T(), utf8.ValidString(result.Text))
+	assert.Equal(suite.T(), maxCustomResponseTextLen, utf8.RuneCountInString(result.Text))
+}
+
+func TestCommandsTestSuite(t *testing.T) {
+	suite.Run(t, new(CommandsTestSuite))
+}
@@ -0,0 +1,20 @@
+package domain
+
+import (
+	commonModels "thothix-backend/internal/common/models"
+)
+
+// CustomCommand represents a slash command that is forwarded to an external HTTP endpoint
+type CustomCommand struct {
+	commonModels.BaseModel
+	WorkspaceID string `json:"workspace_id" gorm:"uniqueIndex:idx_custom_commands_workspace_name"`
+	Name        string `json:"name" gorm:"uniqueIndex:idx_custom_commands_workspace_name"` // Command name without the leading slash, unique per workspace
+	Description string `json:"description"`
+	URL         string `json:"url"` // Endpoint receiving the signed request
+	Secret      string `json:"-"`   // Signing secret, never exposed after creation
+}
+
+// TableName specifies the table name for the CustomCommand model
+func (CustomCommand) TableName() string {
+	return "custom_commands"
+}
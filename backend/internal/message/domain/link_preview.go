@@ -0,0 +1,35 @@
+package domain
+
+import (
+	"time"
+
+	commonModels "thothix-backend/internal/common/models"
+)
+
+// LinkPreview is the Open Graph or oEmbed metadata of a URL, fetched once and shared by every message linking to it
+// The URL and metadata are encrypted at rest like the messages they come from; URLKey finds a URL without revealing it
+type LinkPreview struct {
+	commonModels.BaseModel
+	URLKey      string    `json:"-" gorm:"not null;uniqueIndex"`
+	URL         string    `json:"url" gorm:"serializer:encrypted"`
+	Title       string    `json:"title,omitempty" gorm:"serializer:encrypted"`
+	Description string    `json:"description,omitempty" gorm:"serializer:encrypted"`
+	ImageURL    string    `json:"image_url,omitempty" gorm:"serializer:encrypted"`
+	SiteName    string    `json:"site_name,omitempty" gorm:"serializer:encrypted"`
+	Failed      bool      `json:"-" gorm:"not null;default:false"` // The last fetch failed; retried sooner than a successful one
+	FetchedAt   time.Time `json:"-" gorm:"not null"`
+}
+
+// HasContent reports whether the preview has anything to show
+func (p *LinkPreview) HasContent() bool {
+	return p.Title != "" || p.Description != "" || p.ImageURL != ""
+}
+
+// MessageLinkPreview attaches a preview to a message, in the order the links appear in its content
+type MessageLinkPreview struct {
+	MessageID string       `json:"-" gorm:"type:uuid;primaryKey"`
+	PreviewID string       `json:"-" gorm:"type:uuid;primaryKey;index"`
+	Position  int          `json:"-" gorm:"not null"`
+	Message   *Message     `json:"-" gorm:"constraint:OnDelete:CASCADE"`
+	Preview   *LinkPreview `json:"-" gorm:"constraint:OnDelete:CASCADE"`
+}
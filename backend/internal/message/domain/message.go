@@ -1,22 +1,107 @@
 package domain
 
 import (
+	"time"
+
 	commonModels "thothix-backend/internal/common/models"
+	_ "thothix-backend/internal/encryption" // Registers the "encrypted" serializer used by the models
+	"thothix-backend/internal/message/richtext"
+	usersDomain "thothix-backend/internal/users/domain"
+)
+
+// MessageType distinguishes regular chat text from messages produced by commands
+type MessageType string
+
+const (
+	MessageTypeText    MessageType = "text"    // Regular user message
+	MessageTypeAction  MessageType = "action"  // Emote produced by /me
+	MessageTypeSystem  MessageType = "system"  // Channel event such as a topic change, or a delivered reminder
+	MessageTypeCommand MessageType = "command" // Channel-visible output of a custom command
 )
 
 // Message represents a chat or direct message in the message domain
 type Message struct {
 	commonModels.BaseModel
-	SenderID   string  `json:"sender_id"`
-	ChannelID  *string `json:"channel_id,omitempty"`
-	ReceiverID *string `json:"receiver_id,omitempty"`
-	Content    string  `json:"content"`
+	SenderID   string             `json:"sender_id"`
+	ChannelID  *string            `json:"channel_id,omitempty"`
+	ReceiverID *string            `json:"receiver_id,omitempty"`
+	Content    string             `json:"content" gorm:"serializer:encrypted"` // Source as sent, literal or Markdown depending on Format; encrypted at rest
+	Type       MessageType        `json:"type" gorm:"default:'text'"`
+	Format     int                `json:"format" gorm:"not null;default:0"`                 // richtext format version of Content
+	Document   *richtext.Document `json:"document,omitempty" gorm:"serializer:encrypted"`   // Parsed content and structured blocks; empty for messages older than rich text
+	PlainText  string             `json:"plain_text,omitempty" gorm:"serializer:encrypted"` // Rendering used by notifications and searched through MessageSearchToken
+	HTML       string             `json:"html,omitempty" gorm:"serializer:encrypted"`       // Sanitized rendering for web and email clients
+	Sender     *Author            `json:"sender,omitempty" gorm:"-"`                        // Loaded separately, see NewAuthor
+	Receiver   *Author            `json:"receiver,omitempty" gorm:"-"`                      // Loaded separately for direct messages
+	Previews   []*LinkPreview     `json:"previews,omitempty" gorm:"-"`                      // Loaded separately, attached once the links are unfurled
+	Collapsed  bool               `json:"collapsed,omitempty" gorm:"-"`                     // Set for readers who blocked or muted the sender
+}
+
+// SetBody sets the content of the message from a validated body and its renderings
+func (m *Message) SetBody(body *richtext.Body) {
+	m.Content = body.Source
+	m.Format = body.Format
+	m.Document = body.Document
+	m.PlainText = body.PlainText
+	m.HTML = body.HTML
+}
+
+// DisplayText returns the message without formatting, falling back to the content of messages older than rich text
+func (m *Message) DisplayText() string {
+	if m.PlainText != "" {
+		return m.PlainText
+	}
+	return m.Content
+}
+
+// Author is the public view of a message sender or receiver
+// Deactivated and purged users are shown with a placeholder name and without personal details
+type Author struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	Username    string `json:"username,omitempty"`
+	AvatarURL   string `json:"avatar_url,omitempty"`
+	Deactivated bool   `json:"deactivated,omitempty"`
+}
+
+// NewAuthor builds the public view of a user shown next to their messages
+func NewAuthor(user *usersDomain.User) *Author {
+	if !user.IsActive() {
+		return &Author{ID: user.ID, Name: user.DisplayName(), Deactivated: true}
+	}
+	return &Author{
+		ID:        user.ID,
+		Name:      user.Name,
+		Username:  user.Username,
+		AvatarURL: user.AvatarURL,
+	}
 }
 
 // File represents a file uploaded in a message or project
 type File struct {
 	commonModels.BaseModel
 	MessageID *string `json:"message_id,omitempty"`
-	ProjectID *string `json:"project_id,omitempty"` // Optional, can be null for files in direct messages
-	URL       string  `json:"url"`
+	ProjectID *string `json:"project_id,omitempty"`            // Optional, can be null for files in direct messages
+	URL       string  `json:"url" gorm:"serializer:encrypted"` // Encrypted at rest
+}
+
+// MessageSearchToken is a blind index entry: the keyed hash of one word of an encrypted message
+// Searching hashes the query words the same way, see encryption.BlindIndex
+type MessageSearchToken struct {
+	MessageID string   `json:"-" gorm:"type:uuid;primaryKey"`
+	Token     string   `json:"-" gorm:"primaryKey;index"`
+	Message   *Message `json:"-" gorm:"constraint:OnDelete:CASCADE"`
+}
+
+// Reminder represents a reminder requested by a user through the /remind command or the reminders API
+// The scheduler delivers it as a system direct message to the user at RemindAt
+type Reminder struct {
+	commonModels.BaseModel
+	UserID      string    `json:"user_id"`
+	WorkspaceID *string   `json:"workspace_id,omitempty"` // Workspace whose permissions apply at delivery
+	ChannelID   *string   `json:"channel_id,omitempty"`   // Channel the reminder was created from
+	MessageID   *string   `json:"message_id,omitempty"`   // Message to be reminded about, quoted if still readable
+	Text        string    `json:"text"`
+	RemindAt    time.Time `json:"remind_at" gorm:"index"`
+	Delivered   bool      `json:"delivered" gorm:"default:false"`
 }
@@ -1,6 +1,10 @@
 package dto
 
-import "time"
+import (
+	"time"
+
+	"thothix-backend/internal/message/richtext"
+)
 
 // MessageDto represents a message in API responses
 type MessageDto struct {
@@ -9,15 +13,19 @@ type MessageDto struct {
 	ChannelID  *string   `json:"channel_id,omitempty"`
 	ReceiverID *string   `json:"receiver_id,omitempty"`
 	Content    string    `json:"content"`
+	Format     int       `json:"format"`
 	CreatedAt  time.Time `json:"created_at"`
 	UpdatedAt  time.Time `json:"updated_at"`
 }
 
 // MessageCreateRequest represents a request to create a new message
+// Format declares how Content is written: 0 for literal text, the default, or 1 for Markdown followed by Blocks
 type MessageCreateRequest struct {
-	ChannelID  *string `json:"channel_id,omitempty"`
-	ReceiverID *string `json:"receiver_id,omitempty"`
-	Content    string  `json:"content" binding:"required"`
+	ChannelID  *string          `json:"channel_id,omitempty"`
+	ReceiverID *string          `json:"receiver_id,omitempty"`
+	Content    string           `json:"content"`
+	Format     int              `json:"format"`
+	Blocks     []richtext.Block `json:"blocks,omitempty"`
 }
 
 // MessageUpdateRequest represents a request to update a message
@@ -27,6 +35,31 @@ type MessageUpdateRequest struct {
 
 // DirectMessageCreateRequest represents a request to create a direct message
 type DirectMessageCreateRequest struct {
-	Content     string `json:"content" binding:"required"`
-	RecipientID string `json:"recipient_id" binding:"required"`
+	Content     string           `json:"content"`
+	Format      int              `json:"format"`
+	Blocks      []richtext.Block `json:"blocks,omitempty"`
+	RecipientID string           `json:"recipient_id" binding:"required"`
+}
+
+// CommandInfoDto describes a slash command available to users
+type CommandInfoDto struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Custom      bool   `json:"custom"`
+}
+
+// CustomCommandCreateRequest represents a request to register a custom slash command
+type CustomCommandCreateRequest struct {
+	Name        string `json:"name" binding:"required"`
+	Description string `json:"description"`
+	URL         string `json:"url" binding:"required,url"`
+}
+
+// CustomCommandCreatedDto is returned once on creation and is the only place the signing secret is exposed
+type CustomCommandCreatedDto struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	URL         string `json:"url"`
+	Secret      string `json:"secret"`
 }
@@ -0,0 +1,173 @@
+package handlers
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"strings"
+
+	"thothix-backend/internal/egress"
+	"thothix-backend/internal/message/commands"
+	messageDomain "thothix-backend/internal/message/domain"
+	messageDto "thothix-backend/internal/message/dto"
+	sharedMiddleware "thothix-backend/internal/shared/middleware"
+	sharedModels "thothix-backend/internal/shared/models"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+type CommandHandler struct {
+	db       *gorm.DB
+	commands *commands.Registry
+}
+
+func NewCommandHandler(db *gorm.DB) *CommandHandler {
+	return &CommandHandler{db: db, commands: commands.NewDefaultRegistry()}
+}
+
+// GetCommands godoc
+// @Summary List slash commands
+// @Description List built-in and custom slash commands available in channels
+// @Tags commands
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} messageDto.CommandInfoDto
+// @Failure 500 {object} map[string]interface{}
+// @Router /api/v1/commands [get]
+func (h *CommandHandler) GetCommands(c *gin.Context) {
+	db := h.db.WithContext(c.Request.Context())
+
+	var custom []messageDomain.CustomCommand
+	if err := db.Scopes(sharedModels.InWorkspace("custom_commands")).Order("name").Find(&custom).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get commands"})
+		return
+	}
+
+	builtins := h.commands.Commands()
+	result := make([]messageDto.CommandInfoDto, 0, len(builtins)+len(custom))
+	for _, cmd := range builtins {
+		result = append(result, messageDto.CommandInfoDto{Name: cmd.Name(), Description: cmd.Description()})
+	}
+	for _, cmd := range custom {
+		result = append(result, messageDto.CommandInfoDto{Name: cmd.Name, Description: cmd.Description, Custom: true})
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// CreateCustomCommand godoc
+// @Summary Register a custom slash command
+// @Description Register a slash command forwarded to an external HTTP endpoint. The endpoint must be a public http or https URL; internal and private addresses are refused, also when a host name resolves to one. The signing secret is only returned in this response.
+// @Tags commands
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param command body messageDto.CustomCommandCreateRequest true "Command data"
+// @Success 201 {object} messageDto.CustomCommandCreatedDto
+// @Failure 400 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Router /api/v1/commands [post]
+func (h *CommandHandler) CreateCustomCommand(c *gin.Context) {
+	db := h.db.WithContext(c.Request.Context())
+
+	var req messageDto.CustomCommandCreateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	name := strings.ToLower(strings.TrimPrefix(req.Name, "/"))
+	if !commands.IsValidName(name) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Command name may only contain letters, digits, '-' and '_'"})
+		return
+	}
+	if err := egress.ValidateURL(req.URL); err != nil {
+		if errors.Is(err, egress.ErrBlockedAddress) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Command URL must point to a public address"})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Command URL must be an absolute http or https URL without credentials"})
+		return
+	}
+
+	// Built-in names cannot be shadowed by custom commands
+	if h.commands.IsBuiltin(name) {
+		c.JSON(http.StatusConflict, gin.H{"error": "Command name is reserved by a built-in command"})
+		return
+	}
+
+	var count int64
+	db.Model(&messageDomain.CustomCommand{}).Scopes(sharedModels.InWorkspace("custom_commands")).Where("name = ?", name).Count(&count)
+	if count > 0 {
+		c.JSON(http.StatusConflict, gin.H{"error": "Command already exists"})
+		return
+	}
+
+	secret, err := generateSecret()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate command secret"})
+		return
+	}
+
+	workspaceID, _ := sharedMiddleware.WorkspaceIDFromContext(c.Request.Context())
+	command := messageDomain.CustomCommand{
+		WorkspaceID: workspaceID,
+		Name:        name,
+		Description: req.Description,
+		URL:         req.URL,
+		Secret:      secret,
+	}
+	if err := db.Create(&command).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create command"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, messageDto.CustomCommandCreatedDto{
+		ID:          command.ID,
+		Name:        command.Name,
+		Description: command.Description,
+		URL:         command.URL,
+		Secret:      command.Secret,
+	})
+}
+
+// DeleteCustomCommand godoc
+// @Summary Delete a custom slash command
+// @Description Remove a custom slash command
+// @Tags commands
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Command ID"
+// @Success 204 "No Content"
+// @Failure 404 {object} map[string]interface{}
+// @Router /api/v1/commands/{id} [delete]
+func (h *CommandHandler) DeleteCustomCommand(c *gin.Context) {
+	db := h.db.WithContext(c.Request.Context())
+
+	commandID := c.Param("id")
+
+	result := db.Scopes(sharedModels.InWorkspace("custom_commands")).Delete(&messageDomain.CustomCommand{}, "id = ?", commandID)
+	if result.Error != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete command"})
+		return
+	}
+	if result.RowsAffected == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Command not found"})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// generateSecret creates a random hex-encoded signing secret for a custom command
+func generateSecret() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
@@ -1,12 +1,24 @@
 package handlers
 
 import (
+	"log/slog"
 	"net/http"
 	"strconv"
+	"strings"
 
+	"thothix-backend/internal/blocking/filter"
 	chatDomain "thothix-backend/internal/chat/domain"
+	"thothix-backend/internal/message/commands"
 	messageDomain "thothix-backend/internal/message/domain"
 	messageDto "thothix-backend/internal/message/dto"
+	"thothix-backend/internal/message/posting"
+	"thothix-backend/internal/message/richtext"
+	"thothix-backend/internal/message/search"
+	"thothix-backend/internal/message/unfurl"
+	"thothix-backend/internal/metrics"
+	"thothix-backend/internal/moderation/screening"
+	"thothix-backend/internal/shared/constants"
+	"thothix-backend/internal/shared/logging"
 	sharedModels "thothix-backend/internal/shared/models"
 	usersDomain "thothix-backend/internal/users/domain"
 
@@ -15,16 +27,17 @@ import (
 )
 
 type MessageHandler struct {
-	db *gorm.DB
+	db       *gorm.DB
+	commands *commands.Registry
 }
 
 func NewMessageHandler(db *gorm.DB) *MessageHandler {
-	return &MessageHandler{db: db}
+	return &MessageHandler{db: db, commands: commands.NewDefaultRegistry()}
 }
 
 // GetMessages godoc
 // @Summary Get messages for a channel
-// @Description Get all messages for a specific channel with pagination
+// @Description Get all messages for a specific channel with pagination. Messages from users the reader blocked or muted are marked collapsed
 // @Tags messages
 // @Accept json
 // @Produce json
@@ -36,6 +49,8 @@ func NewMessageHandler(db *gorm.DB) *MessageHandler {
 // @Failure 500 {object} map[string]interface{}
 // @Router /api/v1/channels/{id}/messages [get]
 func (h *MessageHandler) GetMessages(c *gin.Context) {
+	db := h.db.WithContext(c.Request.Context())
+
 	userID, exists := c.Get("clerk_user_id")
 	if !exists {
 		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
@@ -60,7 +75,7 @@ func (h *MessageHandler) GetMessages(c *gin.Context) {
 
 	// Check if user has access to this channel (already done by middleware, but double-check)
 	resourceType := "channel"
-	if !sharedModels.HasUserPermission(h.db, userID.(string), sharedModels.PermissionChannelRead, &resourceType, &channelID) {
+	if !sharedModels.HasUserPermission(db, userID.(string), sharedModels.PermissionChannelRead, &resourceType, &channelID) {
 		c.JSON(http.StatusForbidden, gin.H{"error": "Access denied to channel"})
 		return
 	}
@@ -71,10 +86,10 @@ func (h *MessageHandler) GetMessages(c *gin.Context) {
 	var total int64
 
 	// Count total messages
-	h.db.Model(&messageDomain.Message{}).Where("channel_id = ?", channelID).Count(&total)
+	db.Model(&messageDomain.Message{}).Where("channel_id = ?", channelID).Count(&total)
 
-	// Get paginated messages with user preloading
-	if err := h.db.Preload("Sender").Where("channel_id = ?", channelID).
+	// Get paginated messages with their authors
+	if err := db.Where("channel_id = ?", channelID).
 		Order("created_at DESC").
 		Offset(offset).
 		Limit(limit).
@@ -82,6 +97,18 @@ func (h *MessageHandler) GetMessages(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get messages"})
 		return
 	}
+	if err := loadAuthors(db, messages); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get messages"})
+		return
+	}
+	if err := unfurl.Attach(db, messages); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get messages"})
+		return
+	}
+	if err := filter.Collapse(db, userID.(string), messages); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get messages"})
+		return
+	}
 
 	response := MessageListResponse{
 		Messages: messages,
@@ -94,20 +121,112 @@ func (h *MessageHandler) GetMessages(c *gin.Context) {
 	c.JSON(http.StatusOK, response)
 }
 
+// SearchMessages godoc
+// @Summary Search messages in a channel
+// @Description Find the messages of a channel containing every word of the query, newest first. Words match whole and case-insensitively, since encrypted messages are searched through a blind index
+// @Tags messages
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Channel ID"
+// @Param q query string true "Words to search for"
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Messages per page" default(50)
+// @Success 200 {object} MessageListResponse
+// @Failure 400 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Router /api/v1/channels/{id}/messages/search [get]
+func (h *MessageHandler) SearchMessages(c *gin.Context) {
+	db := h.db.WithContext(c.Request.Context())
+
+	userID, exists := c.Get("clerk_user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	channelID := c.Param("id")
+	query := strings.TrimSpace(c.Query("q"))
+	if query == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Search query is required"})
+		return
+	}
+
+	page := 1
+	limit := 50
+	if p := c.Query("page"); p != "" {
+		if parsed, err := strconv.Atoi(p); err == nil && parsed > 0 {
+			page = parsed
+		}
+	}
+	if l := c.Query("limit"); l != "" {
+		if parsed, err := strconv.Atoi(l); err == nil && parsed > 0 && parsed <= 100 {
+			limit = parsed
+		}
+	}
+
+	resourceType := "channel"
+	if !sharedModels.HasUserPermission(db, userID.(string), sharedModels.PermissionChannelRead, &resourceType, &channelID) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Access denied to channel"})
+		return
+	}
+
+	matching := db.Model(&messageDomain.Message{}).Where("channel_id = ?", channelID).Scopes(search.Matching(query))
+
+	var total int64
+	if err := matching.Session(&gorm.Session{}).Count(&total).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to search messages"})
+		return
+	}
+
+	var messages []messageDomain.Message
+	if err := matching.Session(&gorm.Session{}).
+		Order("created_at DESC").
+		Offset((page - 1) * limit).
+		Limit(limit).
+		Find(&messages).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to search messages"})
+		return
+	}
+	if err := loadAuthors(db, messages); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to search messages"})
+		return
+	}
+	if err := unfurl.Attach(db, messages); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to search messages"})
+		return
+	}
+	if err := filter.Collapse(db, userID.(string), messages); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to search messages"})
+		return
+	}
+
+	c.JSON(http.StatusOK, MessageListResponse{
+		Messages: messages,
+		Page:     page,
+		Limit:    limit,
+		Total:    total,
+		Pages:    (total + int64(limit) - 1) / int64(limit),
+	})
+}
+
 // SendMessage godoc
 // @Summary Send a message
-// @Description Send a message to a channel
+// @Description Send a message to a channel. Content starting with "/" runs a slash command instead of being stored; use "//" to send a literal slash. With format 1, content is Markdown (bold, italics, strikethrough, code, links, lists, quotes and fenced code) and may be followed by code, quote and attachment blocks; the stored plain text and HTML renderings are sanitized. Links are previewed shortly after, unless the channel turned previews off. Suspended users cannot post, users who blocked each other cannot post in a channel only they share, and messages containing a blocked word of the channel's project are refused or reported to its moderators.
 // @Tags messages
 // @Accept json
 // @Produce json
 // @Security BearerAuth
 // @Param id path string true "Channel ID"
 // @Param message body messageDto.MessageCreateRequest true "Message data"
+// @Success 200 {object} commands.Result
 // @Success 201 {object} models.Message
 // @Failure 400 {object} map[string]interface{}
 // @Failure 403 {object} map[string]interface{}
 // @Router /api/v1/channels/{id}/messages [post]
 func (h *MessageHandler) SendMessage(c *gin.Context) {
+	db := h.db.WithContext(c.Request.Context())
+
 	userID, exists := c.Get("clerk_user_id")
 	if !exists {
 		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
@@ -124,39 +243,67 @@ func (h *MessageHandler) SendMessage(c *gin.Context) {
 
 	// Check if user has permission to send messages in this channel
 	resourceType := "channel"
-	if !sharedModels.HasUserPermission(h.db, userID.(string), sharedModels.PermissionMessageCreate, &resourceType, &channelID) {
+	if !sharedModels.HasUserPermission(db, userID.(string), sharedModels.PermissionMessageCreate, &resourceType, &channelID) {
 		c.JSON(http.StatusForbidden, gin.H{"error": "Cannot send messages to this channel"})
 		return
 	}
 
 	// Verify channel exists
 	var channel chatDomain.Channel
-	if err := h.db.Where("id = ?", channelID).First(&channel).Error; err != nil {
+	if err := db.Scopes(sharedModels.InWorkspace("channels")).Where("id = ?", channelID).First(&channel).Error; err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Channel not found"})
 		return
 	}
 
-	// Create message
+	// Suspended users can neither post nor run commands
+	if !checkNotSuspended(c, db, userID.(string), channel.ProjectID) {
+		return
+	}
+	if !checkNotBlocked(c, db, userID.(string), channelID) {
+		return
+	}
+
+	// Route slash commands to the command registry instead of storing the text
+	if commands.IsCommand(req.Content) {
+		h.executeCommand(c, userID.(string), channelID, req.Content)
+		return
+	}
+
+	body, err := richtext.Build(richtext.Input{Format: req.Format, Content: commands.Unescape(req.Content), Blocks: req.Blocks}, richtext.Options{})
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid message: " + err.Error()})
+		return
+	}
+
+	// Create message; blocked words of the project refuse it, or send it and report it to the moderators
 	message := messageDomain.Message{
-		Content:   req.Content,
 		ChannelID: &channelID,
 		SenderID:  userID.(string),
 	}
+	message.SetBody(body)
 
-	if err := h.db.Create(&message).Error; err != nil {
+	if err := posting.Post(db, &message); err != nil {
+		if rejection, ok := commands.AsError(err); ok {
+			c.JSON(http.StatusBadRequest, gin.H{"error": rejection.Message, "code": rejection.Code})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to send message"})
 		return
 	}
+	metrics.MessagesSent.WithLabelValues(metrics.MessageChannel).Inc()
+	enqueueUnfurl(c, db, &message)
 
-	// Load sender relation for response
-	h.db.Preload("Sender").First(&message, message.ID)
+	// Load sender for response
+	messages := []messageDomain.Message{message}
+	_ = loadAuthors(db, messages)
+	message = messages[0]
 
 	c.JSON(http.StatusCreated, message)
 }
 
 // CreateDirectMessage godoc
 // @Summary Create/Send direct message
-// @Description Create a direct message conversation or send a message to existing DM
+// @Description Create a direct message conversation or send a message to existing DM. Content follows the declared format, as for channel messages. Users who blocked each other cannot exchange direct messages
 // @Tags messages
 // @Accept json
 // @Produce json
@@ -167,6 +314,8 @@ func (h *MessageHandler) SendMessage(c *gin.Context) {
 // @Failure 403 {object} map[string]interface{}
 // @Router /api/v1/messages/direct [post]
 func (h *MessageHandler) CreateDirectMessage(c *gin.Context) {
+	db := h.db.WithContext(c.Request.Context())
+
 	userID, exists := c.Get("clerk_user_id")
 	if !exists {
 		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
@@ -180,40 +329,176 @@ func (h *MessageHandler) CreateDirectMessage(c *gin.Context) {
 	}
 
 	// Check if user has permission to create direct messages
-	if !sharedModels.HasUserPermission(h.db, userID.(string), sharedModels.PermissionDMCreate, nil, nil) {
+	if !sharedModels.HasUserPermission(db, userID.(string), sharedModels.PermissionDMCreate, nil, nil) {
 		c.JSON(http.StatusForbidden, gin.H{"error": "Cannot create direct messages"})
 		return
 	}
 
-	// Verify recipient exists
+	// Verify recipient exists and belongs to the current workspace
 	var recipient usersDomain.User
-	if err := h.db.Where("id = ?", req.RecipientID).First(&recipient).Error; err != nil {
+	if err := db.Scopes(sharedModels.WorkspaceMembers).Where("id = ?", req.RecipientID).First(&recipient).Error; err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Recipient not found"})
 		return
 	}
+	if !recipient.IsActive() {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Recipient is deactivated"})
+		return
+	}
+	if !checkNotSuspended(c, db, userID.(string), "") {
+		return
+	}
+	blocked, err := filter.DirectMessagesBlocked(db, userID.(string), recipient.ID)
+	if err != nil {
+		logging.FromContext(c.Request.Context()).Error("Error checking blocks", slog.String("user_id", userID.(string)), slog.Any("error", err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to send direct message"})
+		return
+	}
+	if blocked {
+		c.JSON(http.StatusForbidden, gin.H{"error": "You cannot send direct messages to this user"})
+		return
+	}
+
+	body, err := richtext.Build(richtext.Input{Format: req.Format, Content: req.Content, Blocks: req.Blocks}, richtext.Options{})
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid message: " + err.Error()})
+		return
+	}
 
 	// Create direct message
 	message := messageDomain.Message{
-		Content:    req.Content,
 		SenderID:   userID.(string),
 		ReceiverID: &req.RecipientID,
 	}
+	message.SetBody(body)
 
-	if err := h.db.Create(&message).Error; err != nil {
+	if err := db.Create(&message).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to send direct message"})
 		return
 	}
+	metrics.MessagesSent.WithLabelValues(metrics.MessageDirect).Inc()
+	enqueueUnfurl(c, db, &message)
 
-	// Load user relation for response
-	h.db.Preload("Sender").Preload("Receiver").First(&message, message.ID)
+	// Load sender and receiver for response
+	messages := []messageDomain.Message{message}
+	_ = loadAuthors(db, messages)
+	message = messages[0]
 
 	c.JSON(http.StatusCreated, message)
 }
 
+// executeCommand runs a slash command and maps command errors to HTTP responses
+func (h *MessageHandler) executeCommand(c *gin.Context, userID, channelID, content string) {
+	db := h.db.WithContext(c.Request.Context())
+
+	result, err := h.commands.Execute(&commands.Context{
+		Ctx:       c.Request.Context(),
+		DB:        db,
+		UserID:    userID,
+		ChannelID: channelID,
+	}, content)
+	if err != nil {
+		cmdErr, ok := commands.AsError(err)
+		if !ok {
+			logging.FromContext(c.Request.Context()).Error("Error executing command", slog.String("channel_id", channelID), slog.Any("error", err))
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to execute command"})
+			return
+		}
+
+		status := http.StatusBadRequest
+		switch cmdErr.Code {
+		case constants.ForbiddenError:
+			status = http.StatusForbidden
+		case constants.CommandNotFoundError, constants.UserNotFoundError:
+			status = http.StatusNotFound
+		case constants.ConflictError:
+			status = http.StatusConflict
+		case constants.CommandFailedError:
+			status = http.StatusBadGateway
+		}
+		c.JSON(status, gin.H{"error": cmdErr.Message, "code": cmdErr.Code})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// checkNotSuspended responds with an error and returns false when the user is suspended from posting
+// in the channels of projectID, or in the workspace when projectID is empty
+func checkNotSuspended(c *gin.Context, db *gorm.DB, userID, projectID string) bool {
+	suspension, err := screening.ActiveSuspension(db, userID, projectID)
+	if err != nil {
+		logging.FromContext(c.Request.Context()).Error("Error checking suspensions", slog.String("user_id", userID), slog.Any("error", err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to send message"})
+		return false
+	}
+	if suspension != nil {
+		c.JSON(http.StatusForbidden, gin.H{"error": screening.SuspensionMessage(suspension)})
+		return false
+	}
+	return true
+}
+
+// checkNotBlocked responds with an error and returns false when channelID is a direct conversation
+// and either of its two members blocked the other
+func checkNotBlocked(c *gin.Context, db *gorm.DB, userID, channelID string) bool {
+	blocked, err := filter.ConversationBlocked(db, userID, channelID)
+	if err != nil {
+		logging.FromContext(c.Request.Context()).Error("Error checking blocks", slog.String("channel_id", channelID), slog.Any("error", err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to send message"})
+		return false
+	}
+	if blocked {
+		c.JSON(http.StatusForbidden, gin.H{"error": "You cannot send direct messages to this user"})
+		return false
+	}
+	return true
+}
+
+// enqueueUnfurl schedules the link previews of a sent message; the message is delivered without them on failure
+func enqueueUnfurl(c *gin.Context, db *gorm.DB, message *messageDomain.Message) {
+	if err := unfurl.Enqueue(db, message); err != nil {
+		logging.FromContext(c.Request.Context()).Warn("Failed to schedule link previews", slog.String("message_id", message.ID), slog.Any("error", err))
+	}
+}
+
+// loadAuthors sets the Sender and Receiver of each message from the users table
+// Deactivated users stay attached to their messages but are shown as "Deactivated user"
+func loadAuthors(db *gorm.DB, messages []messageDomain.Message) error {
+	ids := make([]string, 0, len(messages))
+	for _, message := range messages {
+		ids = append(ids, message.SenderID)
+		if message.ReceiverID != nil {
+			ids = append(ids, *message.ReceiverID)
+		}
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+
+	var users []usersDomain.User
+	if err := db.Where("id IN ?", ids).Find(&users).Error; err != nil {
+		return err
+	}
+	authors := make(map[string]*messageDomain.Author, len(users))
+	for i := range users {
+		authors[users[i].ID] = messageDomain.NewAuthor(&users[i])
+	}
+
+	for i := range messages {
+		messages[i].Sender = authors[messages[i].SenderID]
+		if messages[i].ReceiverID != nil {
+			messages[i].Receiver = authors[*messages[i].ReceiverID]
+		}
+	}
+	return nil
+}
+
 // DirectMessageRequest represents the request body for direct messages
 type DirectMessageRequest struct {
-	Content     string `json:"content" binding:"required"`
-	RecipientID string `json:"recipient_id" binding:"required"`
+	Content     string           `json:"content"`
+	Format      int              `json:"format"` // richtext format version of Content, 0 for literal text
+	Blocks      []richtext.Block `json:"blocks,omitempty"`
+	RecipientID string           `json:"recipient_id" binding:"required"`
 }
 
 // MessageListResponse represents the response for message listing
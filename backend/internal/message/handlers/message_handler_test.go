@@ -0,0 +1,241 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/gorm"
+	blockingDomain "thothix-backend/internal/blocking/domain"
+	chatDomain "thothix-backend/internal/chat/domain"
+	messageDomain "thothix-backend/internal/message/domain"
+	messageDto "thothix-backend/internal/message/dto"
+	moderationDomain "thothix-backend/internal/moderation/domain"
+	projectDomain "thothix-backend/internal/project/domain"
+	sharedModels "thothix-backend/internal/shared/models"
+	sharedTesting "thothix-backend/internal/shared/testing"
+	usersDomain "thothix-backend/internal/users/domain"
+)
+
+type MessageHandlerTestSuite struct {
+	suite.Suite
+	container *sharedTesting.PostgresTestContainer
+}
+
+func (suite *MessageHandlerTestSuite) SetupSuite() {
+	gin.SetMode(gin.TestMode)
+	suite.container = sharedTesting.GetSharedTestContainer(
+		suite.T(),
+		"message/handlers",
+		[]interface{}{
+			&usersDomain.User{}, &sharedModels.UserRole{},
+			&chatDomain.Channel{}, &chatDomain.ChannelMember{}, &messageDomain.Message{},
+			&moderationDomain.Suspension{}, &moderationDomain.BlockedWordList{}, &moderationDomain.Report{}, &blockingDomain.Block{},
+			&projectDomain.Project{}, &projectDomain.ProjectMember{},
+		},
+	)
+}
+
+// createUser stores a user with the user system role
+func (suite *MessageHandlerTestSuite) createUser(db *gorm.DB, name string) string {
+	user := &usersDomain.User{Email: name + "@example.com", Name: name, SystemRole: sharedModels.RoleUser}
+	user.ID = uuid.New().String()
+	assert.NoError(suite.T(), db.Create(user).Error)
+	return user.ID
+}
+
+// createChannel stores a project channel whose members are memberIDs
+func (suite *MessageHandlerTestSuite) createChannel(db *gorm.DB, memberIDs ...string) string {
+	return suite.createProjectChannel(db, uuid.New().String(), memberIDs...).ID
+}
+
+// createProjectChannel stores a channel of projectID whose members are memberIDs
+func (suite *MessageHandlerTestSuite) createProjectChannel(db *gorm.DB, projectID string, memberIDs ...string) *chatDomain.Channel {
+	channel := &chatDomain.Channel{WorkspaceID: uuid.New().String(), ProjectID: projectID, Name: "conversation"}
+	assert.NoError(suite.T(), db.Create(channel).Error)
+	for _, memberID := range memberIDs {
+		assert.NoError(suite.T(), db.Create(&chatDomain.ChannelMember{ChannelID: channel.ID, UserID: memberID}).Error)
+	}
+	return channel
+}
+
+// send posts content to channelID as userID through the message routes
+func (suite *MessageHandlerTestSuite) send(db *gorm.DB, userID, channelID, content string) *httptest.ResponseRecorder {
+	router := gin.New()
+	router.Use(func(c *gin.Context) {
+		c.Set("clerk_user_id", userID)
+		c.Next()
+	})
+	router.POST("/channels/:id/messages", NewMessageHandler(db).SendMessage)
+
+	body, _ := json.Marshal(messageDto.MessageCreateRequest{Content: content})
+	req, _ := http.NewRequest("POST", "/channels/"+channelID+"/messages", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+// sendDirect posts content to recipientID as userID through the direct message route
+func (suite *MessageHandlerTestSuite) sendDirect(db *gorm.DB, userID, recipientID, content string) *httptest.ResponseRecorder {
+	router := gin.New()
+	router.Use(func(c *gin.Context) {
+		c.Set("clerk_user_id", userID)
+		c.Next()
+	})
+	router.POST("/messages/direct", NewMessageHandler(db).CreateDirectMessage)
+
+	body, _ := json.Marshal(DirectMessageRequest{RecipientID: recipientID, Content: content})
+	req, _ := http.NewRequest("POST", "/messages/direct", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func (suite *MessageHandlerTestSuite) TestCreateDirectMessage_BlockedEitherWay() {
+	suite.container.WithTransaction(func(db *gorm.DB) {
+		// Arrange
+		sender := suite.createUser(db, "direct-sender")
+		blocker := suite.createUser(db, "direct-blocker")
+		bystander := suite.createUser(db, "direct-bystander")
+		assert.NoError(suite.T(), db.Create(&blockingDomain.Block{BlockerID: blocker, BlockedID: sender, Type: blockingDomain.TypeBlock}).Error)
+
+		// Act
+		toBlocker := suite.sendDirect(db, sender, blocker, "hello")
+		fromBlocker := suite.sendDirect(db, blocker, sender, "hello")
+		toBystander := suite.sendDirect(db, sender, bystander, "hello")
+
+		// Assert
+		assert.Equal(suite.T(), http.StatusForbidden, toBlocker.Code)
+		assert.Equal(suite.T(), http.StatusForbidden, fromBlocker.Code)
+		assert.Equal(suite.T(), http.StatusCreated, toBystander.Code)
+
+		var stored int64
+		db.Model(&messageDomain.Message{}).Where("receiver_id IN ?", []string{sender, blocker}).Count(&stored)
+		assert.Equal(suite.T(), int64(0), stored)
+	})
+}
+
+func (suite *MessageHandlerTestSuite) TestSendMessage_BlockedDirectConversation() {
+	suite.container.WithTransaction(func(db *gorm.DB) {
+		// Arrange
+		sender := suite.createUser(db, "dm-sender")
+		blocker := suite.createUser(db, "dm-blocker")
+		bystander := suite.createUser(db, "dm-bystander")
+		conversation := suite.createChannel(db, sender, blocker)
+		shared := suite.createChannel(db, sender, blocker, bystander)
+		assert.NoError(suite.T(), db.Create(&blockingDomain.Block{BlockerID: blocker, BlockedID: sender, Type: blockingDomain.TypeBlock}).Error)
+
+		// Act
+		toBlocker := suite.send(db, sender, conversation, "hello")
+		fromBlocker := suite.send(db, blocker, conversation, "hello")
+		inShared := suite.send(db, sender, shared, "hello")
+
+		// Assert
+		assert.Equal(suite.T(), http.StatusForbidden, toBlocker.Code)
+		assert.Equal(suite.T(), http.StatusForbidden, fromBlocker.Code)
+		assert.Equal(suite.T(), http.StatusCreated, inShared.Code, "blocks do not stop messages in channels shared with others")
+
+		var stored int64
+		db.Model(&messageDomain.Message{}).Where("channel_id = ?", conversation).Count(&stored)
+		assert.Equal(suite.T(), int64(0), stored)
+	})
+}
+
+func (suite *MessageHandlerTestSuite) TestSendMessage_MutedDirectConversation() {
+	suite.container.WithTransaction(func(db *gorm.DB) {
+		// Arrange
+		sender := suite.createUser(db, "dm-muted-sender")
+		muter := suite.createUser(db, "dm-muter")
+		conversation := suite.createChannel(db, sender, muter)
+		assert.NoError(suite.T(), db.Create(&blockingDomain.Block{BlockerID: muter, BlockedID: sender, Type: blockingDomain.TypeMute}).Error)
+
+		// Act
+		w := suite.send(db, sender, conversation, "hello")
+
+		// Assert
+		assert.Equal(suite.T(), http.StatusCreated, w.Code, "muted users can still send direct messages")
+	})
+}
+
+func (suite *MessageHandlerTestSuite) TestSendMessage_SuspendedUser() {
+	suite.container.WithTransaction(func(db *gorm.DB) {
+		// Arrange
+		sender := suite.createUser(db, "dm-suspended")
+		other := suite.createUser(db, "dm-other")
+		conversation := suite.createChannel(db, sender, other)
+		assert.NoError(suite.T(), db.Create(&moderationDomain.Suspension{WorkspaceID: uuid.New().String(), UserID: sender, Reason: "spam"}).Error)
+
+		// Act
+		w := suite.send(db, sender, conversation, "hello")
+
+		// Assert
+		assert.Equal(suite.T(), http.StatusForbidden, w.Code)
+		assert.Contains(suite.T(), w.Body.String(), "suspended")
+	})
+}
+
+func (suite *MessageHandlerTestSuite) TestSendMessage_CommandScreenedForBlockedWords() {
+	suite.container.WithTransaction(func(db *gorm.DB) {
+		// Arrange
+		sender := suite.createUser(db, "me-sender")
+		channel := suite.createProjectChannel(db, uuid.New().String(), sender, suite.createUser(db, "me-other"), suite.createUser(db, "me-third"))
+		assert.NoError(suite.T(), db.Create(&moderationDomain.BlockedWordList{
+			WorkspaceID: channel.WorkspaceID, ProjectID: channel.ProjectID, Mode: moderationDomain.FilterReject, Words: []string{"forbidden"},
+		}).Error)
+
+		// Act
+		rejected := suite.send(db, sender, channel.ID, "/me says forbidden things")
+		allowed := suite.send(db, sender, channel.ID, "/me waves")
+
+		// Assert
+		assert.Equal(suite.T(), http.StatusBadRequest, rejected.Code)
+		assert.Contains(suite.T(), rejected.Body.String(), "forbidden")
+		assert.Equal(suite.T(), http.StatusOK, allowed.Code)
+
+		var contents []string
+		db.Model(&messageDomain.Message{}).Where("channel_id = ?", channel.ID).Pluck("content", &contents)
+		assert.Equal(suite.T(), []string{"waves"}, contents)
+	})
+}
+
+func (suite *MessageHandlerTestSuite) TestSendMessage_InviteToPrivateChannel() {
+	suite.container.WithTransaction(func(db *gorm.DB) {
+		// Arrange
+		member := suite.createUser(db, "invite-member")
+		manager := suite.createUser(db, "invite-manager")
+		assert.NoError(suite.T(), db.Model(&usersDomain.User{}).Where("id = ?", manager).Update("system_role", sharedModels.RoleManager).Error)
+		colleague := suite.createUser(db, "invite-colleague")
+		outsider := suite.createUser(db, "invite-outsider")
+
+		project := &projectDomain.Project{WorkspaceID: uuid.New().String(), Name: "private"}
+		assert.NoError(suite.T(), db.Create(project).Error)
+		assert.NoError(suite.T(), db.Create(&projectDomain.ProjectMember{ProjectID: project.ID, UserID: colleague, Role: "member"}).Error)
+		channel := suite.createProjectChannel(db, project.ID, member, manager)
+
+		// Act
+		byMember := suite.send(db, member, channel.ID, "/invite @"+colleague)
+		outsiderByManager := suite.send(db, manager, channel.ID, "/invite @"+outsider)
+		colleagueByManager := suite.send(db, manager, channel.ID, "/invite @"+colleague)
+
+		// Assert
+		assert.Equal(suite.T(), http.StatusForbidden, byMember.Code, "regular members cannot invite to a private channel")
+		assert.Equal(suite.T(), http.StatusForbidden, outsiderByManager.Code, "users without project access cannot join")
+		assert.Equal(suite.T(), http.StatusOK, colleagueByManager.Code)
+
+		var members []string
+		db.Model(&chatDomain.ChannelMember{}).Where("channel_id = ?", channel.ID).Pluck("user_id", &members)
+		assert.ElementsMatch(suite.T(), []string{member, manager, colleague}, members)
+	})
+}
+
+func TestMessageHandlerTestSuite(t *testing.T) {
+	suite.Run(t, new(MessageHandlerTestSuite))
+}
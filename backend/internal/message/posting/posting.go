@@ -0,0 +1,57 @@
+// Package posting stores the messages posted in channels, whether sent by a user, a slash command or the
+// scheduler, so every path runs the same moderation checks
+package posting
+
+import (
+	"log/slog"
+
+	"gorm.io/gorm"
+
+	chatDomain "thothix-backend/internal/chat/domain"
+	messageDomain "thothix-backend/internal/message/domain"
+	"thothix-backend/internal/moderation/screening"
+	"thothix-backend/internal/shared/constants"
+	"thothix-backend/internal/shared/dto"
+	"thothix-backend/internal/shared/logging"
+)
+
+// Post screens message against the blocked-word list of its channel's project, then stores it
+// A list in reject mode refuses the message with a BlockedWordsError; a list in flag mode lets it through
+// and reports it to the project's moderators. Direct messages are not screened
+func Post(db *gorm.DB, message *messageDomain.Message) error {
+	projectID, err := channelProject(db, message.ChannelID)
+	if err != nil {
+		return err
+	}
+
+	verdict, err := screening.Check(db, projectID, message.DisplayText())
+	if err != nil {
+		return err
+	}
+	if verdict != nil && verdict.Rejects() {
+		return dto.NewError(constants.BlockedWordsError, verdict.Message(), nil)
+	}
+
+	if err := db.Create(message).Error; err != nil {
+		return err
+	}
+	if verdict != nil {
+		// The message is sent either way; a missing report is logged rather than failing the post
+		if err := screening.Flag(db, message, projectID, verdict); err != nil {
+			logging.FromContext(db.Statement.Context).Error("Failed to flag message", slog.String("message_id", message.ID), slog.Any("error", err))
+		}
+	}
+	return nil
+}
+
+// channelProject returns the project of the channel, or an empty string for direct messages
+func channelProject(db *gorm.DB, channelID *string) (string, error) {
+	if channelID == nil {
+		return "", nil
+	}
+	var channel chatDomain.Channel
+	if err := db.Select("id", "project_id").Where("id = ?", *channelID).First(&channel).Error; err != nil {
+		return "", err
+	}
+	return channel.ProjectID, nil
+}
@@ -0,0 +1,268 @@
+package richtext
+
+import (
+	"errors"
+	"fmt"
+	"mime"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// MaxContentLength bounds the characters of a message source, Markdown or structured text
+	MaxContentLength = 16000
+
+	maxBlocks         = 50
+	maxAttachments    = 10
+	maxButtons        = 5 // Per actions block
+	maxLabelLength    = 80
+	maxActionIDLength = 255
+	maxNameLength     = 255
+)
+
+var (
+	// ErrUnsupportedFormat is returned for format versions this server does not know
+	ErrUnsupportedFormat = errors.New("unsupported message format")
+
+	// ErrEmpty is returned for messages with nothing to show
+	ErrEmpty = errors.New("message is empty")
+)
+
+// Input is the content of a message as sent by a client or an integration
+type Input struct {
+	Format  int     // One of the format versions; FormatPlain when the client does not declare one
+	Content string  // Literal text or Markdown, depending on Format
+	Blocks  []Block // Structured blocks shown after Content; FormatRich only
+}
+
+// Options relax validation for trusted senders
+type Options struct {
+	AllowButtons bool // Buttons are posted by integrations such as custom commands, never by users
+}
+
+// Body is validated message content with its stored renderings
+type Body struct {
+	Format    int
+	Source    string // Sanitized Content of the input
+	Document  *Document
+	PlainText string
+	HTML      string
+}
+
+// Build validates and sanitizes input, parses it into a document and renders it
+// Control and bidirectional override characters are removed, links to unsafe URLs are shown as text,
+// and raw HTML is escaped; only invalid structured blocks and limits make Build fail
+func Build(input Input, options Options) (*Body, error) {
+	if input.Format != FormatPlain && input.Format != FormatRich {
+		return nil, fmt.Errorf("%w: %d", ErrUnsupportedFormat, input.Format)
+	}
+	source := sanitize(input.Content)
+	if utf8.RuneCountInString(source) > MaxContentLength {
+		return nil, fmt.Errorf("message is longer than %d characters", MaxContentLength)
+	}
+
+	doc := &Document{Version: input.Format}
+	if input.Format == FormatPlain {
+		if len(input.Blocks) > 0 {
+			return nil, fmt.Errorf("structured blocks require format %d", FormatRich)
+		}
+		doc.Blocks = plainBlocks(source)
+	} else {
+		doc.Blocks = parseMarkdown(source)
+		blocks, err := structuredBlocks(input.Blocks, options)
+		if err != nil {
+			return nil, err
+		}
+		doc.Blocks = append(doc.Blocks, blocks...)
+	}
+
+	if len(doc.Blocks) == 0 {
+		return nil, ErrEmpty
+	}
+	if len(doc.Blocks) > maxBlocks {
+		return nil, fmt.Errorf("message has more than %d blocks", maxBlocks)
+	}
+	return render(input.Format, source, doc), nil
+}
+
+// Plain builds the body of a message written by the server, such as command output or a reminder
+func Plain(content string) *Body {
+	source := sanitize(content)
+	return render(FormatPlain, source, &Document{Version: FormatPlain, Blocks: plainBlocks(source)})
+}
+
+func render(format int, source string, doc *Document) *Body {
+	return &Body{Format: format, Source: source, Document: doc, PlainText: PlainText(doc), HTML: HTML(doc)}
+}
+
+// plainBlocks splits literal text into paragraphs at blank lines, keeping its line breaks
+func plainBlocks(source string) []Block {
+	var blocks []Block
+	for _, paragraph := range strings.Split(source, "\n\n") {
+		paragraph = strings.Trim(paragraph, "\n")
+		if strings.TrimSpace(paragraph) == "" {
+			continue
+		}
+		var inlines []Inline
+		for i, line := range strings.Split(paragraph, "\n") {
+			if i > 0 {
+				inlines = append(inlines, Inline{Type: InlineBreak})
+			}
+			if line != "" {
+				inlines = append(inlines, Inline{Type: InlineText, Text: line})
+			}
+		}
+		blocks = append(blocks, Block{Type: BlockParagraph, Inlines: inlines})
+	}
+	return blocks
+}
+
+// structuredBlocks validates the blocks sent next to the Markdown content
+func structuredBlocks(blocks []Block, options Options) ([]Block, error) {
+	result := make([]Block, 0, len(blocks))
+	attachments := 0
+	for i, block := range blocks {
+		var err error
+		switch block.Type {
+		case BlockCode:
+			block, err = codeBlock(block)
+		case BlockQuote:
+			block, err = quoteBlock(block)
+		case BlockAttachment:
+			attachments++
+			if attachments > maxAttachments {
+				return nil, fmt.Errorf("message has more than %d attachments", maxAttachments)
+			}
+			block, err = attachmentBlock(block)
+		case BlockActions:
+			if !options.AllowButtons {
+				return nil, errors.New("buttons can only be posted by integrations")
+			}
+			block, err = actionsBlock(block)
+		default:
+			err = fmt.Errorf("unsupported block type %q, paragraphs and lists are written in the content", block.Type)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("block %d: %w", i+1, err)
+		}
+		result = append(result, block)
+	}
+	return result, nil
+}
+
+func codeBlock(block Block) (Block, error) {
+	text := sanitize(block.Text)
+	if strings.TrimSpace(text) == "" {
+		return Block{}, errors.New("code text is required")
+	}
+	if utf8.RuneCountInString(text) > MaxContentLength {
+		return Block{}, fmt.Errorf("code is longer than %d characters", MaxContentLength)
+	}
+	language := ""
+	if block.Language != "" {
+		if language = codeLanguage(block.Language); language == "" {
+			return Block{}, fmt.Errorf("invalid code language %q", block.Language)
+		}
+	}
+	return Block{Type: BlockCode, Language: language, Text: text}, nil
+}
+
+// quoteBlock parses the Markdown text of a quote into its nested blocks
+func quoteBlock(block Block) (Block, error) {
+	text := sanitize(block.Text)
+	if utf8.RuneCountInString(text) > MaxContentLength {
+		return Block{}, fmt.Errorf("quote is longer than %d characters", MaxContentLength)
+	}
+	blocks := parseBlocks(strings.Split(text, "\n"), 1)
+	if len(blocks) == 0 {
+		return Block{}, errors.New("quote text is required")
+	}
+	return Block{Type: BlockQuote, Blocks: blocks}, nil
+}
+
+func attachmentBlock(block Block) (Block, error) {
+	attachment := block.Attachment
+	if attachment == nil {
+		return Block{}, errors.New("attachment is required")
+	}
+	url := SafeURL(attachment.URL)
+	if url == "" || strings.HasPrefix(url, "mailto:") {
+		return Block{}, errors.New("attachment URL must be an http or https URL")
+	}
+	name := strings.TrimSpace(sanitize(attachment.Name))
+	if name == "" || utf8.RuneCountInString(name) > maxNameLength {
+		return Block{}, fmt.Errorf("attachment name is required and at most %d characters", maxNameLength)
+	}
+	if attachment.FileID != "" {
+		if _, err := uuid.Parse(attachment.FileID); err != nil {
+			return Block{}, errors.New("attachment file ID is invalid")
+		}
+	}
+	if attachment.MimeType != "" {
+		if _, _, err := mime.ParseMediaType(attachment.MimeType); err != nil {
+			return Block{}, errors.New("attachment MIME type is invalid")
+		}
+	}
+	if attachment.Size < 0 {
+		return Block{}, errors.New("attachment size cannot be negative")
+	}
+	return Block{Type: BlockAttachment, Attachment: &Attachment{
+		FileID:   attachment.FileID,
+		URL:      url,
+		Name:     name,
+		MimeType: attachment.MimeType,
+		Size:     attachment.Size,
+	}}, nil
+}
+
+func actionsBlock(block Block) (Block, error) {
+	if len(block.Buttons) == 0 || len(block.Buttons) > maxButtons {
+		return Block{}, fmt.Errorf("actions need between 1 and %d buttons", maxButtons)
+	}
+	buttons := make([]Button, 0, len(block.Buttons))
+	for _, button := range block.Buttons {
+		label := strings.TrimSpace(sanitize(button.Label))
+		if label == "" || utf8.RuneCountInString(label) > maxLabelLength || strings.Contains(label, "\n") {
+			return Block{}, fmt.Errorf("button label is required and at most %d characters on one line", maxLabelLength)
+		}
+		if (button.ActionID == "") == (button.URL == "") {
+			return Block{}, errors.New("a button needs either an action ID or a URL")
+		}
+		if len(button.ActionID) > maxActionIDLength {
+			return Block{}, fmt.Errorf("button action ID is longer than %d characters", maxActionIDLength)
+		}
+		url := ""
+		if button.URL != "" {
+			if url = SafeURL(button.URL); url == "" || strings.HasPrefix(url, "mailto:") {
+				return Block{}, errors.New("button URL must be an http or https URL")
+			}
+		}
+		switch button.Style {
+		case ButtonDefault, ButtonPrimary, ButtonDanger:
+		default:
+			return Block{}, fmt.Errorf("unsupported button style %q", button.Style)
+		}
+		buttons = append(buttons, Button{Label: label, ActionID: sanitize(button.ActionID), URL: url, Style: button.Style})
+	}
+	return Block{Type: BlockActions, Buttons: buttons}, nil
+}
+
+// sanitize normalizes line endings and removes control characters other than newlines and tabs,
+// bidirectional overrides that can disguise text, and invalid UTF-8
+func sanitize(text string) string {
+	text = strings.ReplaceAll(strings.ToValidUTF8(text, ""), "\r\n", "\n")
+	return strings.Map(func(r rune) rune {
+		if r != '\n' && r != '\t' && (unicode.IsControl(r) || isBidiControl(r)) {
+			return -1
+		}
+		return r
+	}, text)
+}
+
+// isBidiControl reports whether r is an embedding, override or isolate character
+func isBidiControl(r rune) bool {
+	return (r >= '\u202a' && r <= '\u202e') || (r >= '\u2066' && r <= '\u2069')
+}
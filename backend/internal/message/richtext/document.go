@@ -0,0 +1,90 @@
+// Package richtext defines the format of message content: a constrained Markdown subset parsed into a
+// document tree, plus structured blocks for code, quotes, attachments and buttons. Documents are
+// validated and sanitized on the server and rendered to plain text and HTML for notifications and search.
+package richtext
+
+// Format versions declared by clients when sending a message
+const (
+	FormatPlain = 0 // Content is literal text, the format of messages sent before rich text
+	FormatRich  = 1 // Content is Markdown (subset v1) and may be followed by structured blocks
+
+	LatestFormat = FormatRich
+)
+
+// Document is the parsed content of a message
+type Document struct {
+	Version int     `json:"version"`
+	Blocks  []Block `json:"blocks"`
+}
+
+// BlockType identifies the kind of a block
+type BlockType string
+
+const (
+	BlockParagraph  BlockType = "paragraph"  // Inlines, with line breaks as break inlines
+	BlockList       BlockType = "list"       // Items, numbered when Ordered
+	BlockCode       BlockType = "code"       // Text shown verbatim, highlighted as Language
+	BlockQuote      BlockType = "quote"      // Nested Blocks, or Text parsed as Markdown when sent as a structured block
+	BlockAttachment BlockType = "attachment" // A linked file
+	BlockActions    BlockType = "actions"    // Buttons, only posted by integrations
+)
+
+// Block is a top-level element of a document
+type Block struct {
+	Type       BlockType   `json:"type"`
+	Inlines    []Inline    `json:"inlines,omitempty"`
+	Items      [][]Inline  `json:"items,omitempty"`
+	Ordered    bool        `json:"ordered,omitempty"`
+	Language   string      `json:"language,omitempty"`
+	Text       string      `json:"text,omitempty"`
+	Blocks     []Block     `json:"blocks,omitempty"`
+	Attachment *Attachment `json:"attachment,omitempty"`
+	Buttons    []Button    `json:"buttons,omitempty"`
+}
+
+// InlineType identifies the kind of an inline element
+type InlineType string
+
+const (
+	InlineText     InlineType = "text"
+	InlineBreak    InlineType = "break"
+	InlineStrong   InlineType = "strong"
+	InlineEmphasis InlineType = "emphasis"
+	InlineStrike   InlineType = "strike"
+	InlineCode     InlineType = "code"
+	InlineLink     InlineType = "link"
+)
+
+// Inline is an element of a paragraph or list item; formatting elements hold their content in Children
+type Inline struct {
+	Type     InlineType `json:"type"`
+	Text     string     `json:"text,omitempty"`
+	URL      string     `json:"url,omitempty"`
+	Children []Inline   `json:"children,omitempty"`
+}
+
+// Attachment is a file linked from a message
+type Attachment struct {
+	FileID   string `json:"file_id,omitempty"`
+	URL      string `json:"url"`
+	Name     string `json:"name"`
+	MimeType string `json:"mime_type,omitempty"`
+	Size     int64  `json:"size,omitempty"`
+}
+
+// ButtonStyle is the emphasis of a button
+type ButtonStyle string
+
+const (
+	ButtonDefault ButtonStyle = ""
+	ButtonPrimary ButtonStyle = "primary"
+	ButtonDanger  ButtonStyle = "danger"
+)
+
+// Button is an action offered by an integration: it either opens URL or reports ActionID back to it
+type Button struct {
+	Label    string      `json:"label"`
+	ActionID string      `json:"action_id,omitempty"`
+	URL      string      `json:"url,omitempty"`
+	Style    ButtonStyle `json:"style,omitempty"`
+}
@@ -0,0 +1,397 @@
+package richtext
+
+import (
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+const (
+	// maxQuoteDepth bounds nested quotes; deeper markers are kept as text
+	maxQuoteDepth = 3
+
+	// maxInlineDepth bounds nested formatting; deeper delimiters are kept as text
+	maxInlineDepth = 8
+)
+
+var (
+	// codeLanguagePattern accepts language names such as go, c++, c# or objective-c
+	codeLanguagePattern = regexp.MustCompile(`^[A-Za-z0-9_+#.-]{1,32}$`)
+
+	// orderedItemPattern matches the marker of a numbered list item
+	orderedItemPattern = regexp.MustCompile(`^[0-9]{1,9}[.)] `)
+
+	// autolinkPattern matches a bare http or https link
+	autolinkPattern = regexp.MustCompile(`^https?://[^\s<>"'` + "`" + `]+`)
+)
+
+// parseMarkdown parses the supported Markdown subset: paragraphs, fenced code, quotes, flat lists and
+// inline strong, emphasis, strikethrough, code and links. Anything else, including raw HTML, headings and
+// images, is kept as text
+func parseMarkdown(source string) []Block {
+	return parseBlocks(strings.Split(source, "\n"), 0)
+}
+
+// parseBlocks parses lines into blocks; depth counts the quotes around them
+func parseBlocks(lines []string, depth int) []Block {
+	var blocks []Block
+	var paragraph []string
+	flush := func() {
+		if len(paragraph) > 0 {
+			blocks = append(blocks, Block{Type: BlockParagraph, Inlines: parseInlines(strings.Join(paragraph, "\n"), 0, true)})
+			paragraph = nil
+		}
+	}
+
+	for i := 0; i < len(lines); {
+		line := strings.TrimSpace(lines[i])
+		switch {
+		case line == "":
+			flush()
+			i++
+
+		case strings.HasPrefix(line, "```"):
+			flush()
+			info := line[3:]
+			// A fence closed on its own line, as chat users often write it
+			if end := strings.Index(info, "```"); end >= 0 {
+				blocks = append(blocks, Block{Type: BlockCode, Text: info[:end]})
+				i++
+				continue
+			}
+			var code []string
+			for i++; i < len(lines) && !strings.HasPrefix(strings.TrimSpace(lines[i]), "```"); i++ {
+				code = append(code, lines[i])
+			}
+			i++ // Closing fence; an unclosed block runs to the end of the message
+			blocks = append(blocks, Block{Type: BlockCode, Language: codeLanguage(info), Text: strings.Join(code, "\n")})
+
+		case strings.HasPrefix(line, ">") && depth < maxQuoteDepth:
+			flush()
+			var quoted []string
+			for ; i < len(lines) && strings.HasPrefix(strings.TrimSpace(lines[i]), ">"); i++ {
+				inner := strings.TrimPrefix(strings.TrimSpace(lines[i]), ">")
+				quoted = append(quoted, strings.TrimPrefix(inner, " "))
+			}
+			blocks = append(blocks, Block{Type: BlockQuote, Blocks: parseBlocks(quoted, depth+1)})
+
+		case listItem(line) != "":
+			flush()
+			ordered := orderedItemPattern.MatchString(line)
+			list := Block{Type: BlockList, Ordered: ordered}
+			for ; i < len(lines); i++ {
+				item := strings.TrimSpace(lines[i])
+				marker := listItem(item)
+				if marker == "" || orderedItemPattern.MatchString(item) != ordered {
+					break
+				}
+				list.Items = append(list.Items, parseInlines(strings.TrimSpace(item[len(marker):]), 0, true))
+			}
+			blocks = append(blocks, list)
+
+		default:
+			paragraph = append(paragraph, line)
+			i++
+		}
+	}
+	flush()
+	return blocks
+}
+
+// listItem returns the list marker starting line, or "" when line is not a list item
+func listItem(line string) string {
+	for _, marker := range []string{"- ", "* ", "+ "} {
+		if strings.HasPrefix(line, marker) {
+			return marker
+		}
+	}
+	return orderedItemPattern.FindString(line)
+}
+
+// codeLanguage returns the language of a code fence, or "" when it is not a plausible name
+func codeLanguage(info string) string {
+	info = strings.TrimSpace(info)
+	if !codeLanguagePattern.MatchString(info) {
+		return ""
+	}
+	return strings.ToLower(info)
+}
+
+// inlineParser parses the inline elements of one paragraph or list item
+type inlineParser struct {
+	source  string
+	depth   int
+	links   bool // False inside link text, since links do not nest
+	out     []Inline
+	text    strings.Builder // Pending text, flushed before each other element
+	noClose map[string]bool // Delimiters known to have no closer in the rest of source
+}
+
+// parseInlines parses source into inline elements; depth counts the formatting elements around it
+func parseInlines(source string, depth int, links bool) []Inline {
+	p := &inlineParser{source: source, depth: depth, links: links, noClose: make(map[string]bool)}
+	p.parse()
+	return p.out
+}
+
+func (p *inlineParser) parse() {
+	s := p.source
+	for i := 0; i < len(s); {
+		c := s[i]
+		switch {
+		case c == '\\' && i+1 < len(s) && isPunctuation(s[i+1]):
+			p.text.WriteByte(s[i+1])
+			i += 2
+
+		case c == '\n':
+			p.emit(Inline{Type: InlineBreak})
+			i++
+
+		case c == '`':
+			run := backtickRun(s[i:])
+			if end := closingBackticks(s, i+run, run); end >= 0 {
+				p.emit(Inline{Type: InlineCode, Text: trimCodeSpan(s[i+run : end])})
+				i = end + run
+			} else {
+				p.text.WriteString(s[i : i+run])
+				i += run
+			}
+
+		case strings.HasPrefix(s[i:], "**"):
+			i = p.delimited(i, "**", InlineStrong)
+
+		case strings.HasPrefix(s[i:], "~~"):
+			i = p.delimited(i, "~~", InlineStrike)
+
+		case c == '*' || (c == '_' && (i == 0 || !isWordByte(s[i-1]))):
+			i = p.delimited(i, string(c), InlineEmphasis)
+
+		case c == '[' && p.links:
+			i = p.link(i)
+
+		case c == 'h' && p.links && (i == 0 || !isWordByte(s[i-1])) && autolinkPattern.MatchString(s[i:]):
+			i = p.autolink(i)
+
+		default:
+			p.text.WriteByte(c)
+			i++
+		}
+	}
+	p.flushText()
+}
+
+// delimited parses a formatting element opened by delimiter at i, or keeps the delimiter as text
+// when it is not closed, and returns the position after what it consumed
+func (p *inlineParser) delimited(i int, delimiter string, kind InlineType) int {
+	start := i + len(delimiter)
+	if p.depth < maxInlineDepth && !p.noClose[delimiter] && start < len(p.source) && !isSpace(p.source[start]) {
+		end := p.closer(start, delimiter)
+		if end < 0 {
+			p.noClose[delimiter] = true
+		} else {
+			p.emit(Inline{Type: kind, Children: parseInlines(p.source[start:end], p.depth+1, p.links)})
+			return end + len(delimiter)
+		}
+	}
+	p.text.WriteString(delimiter)
+	return start
+}
+
+// closer finds the delimiter closing an element whose content starts at from, skipping escapes and
+// code spans; a single * or _ does not close on a doubled one, and closers must follow non-space
+func (p *inlineParser) closer(from int, delimiter string) int {
+	s := p.source
+	for j := from; j < len(s); {
+		switch {
+		case s[j] == '\\':
+			j += 2
+		case s[j] == '`':
+			run := backtickRun(s[j:])
+			if end := closingBackticks(s, j+run, run); end >= 0 {
+				j = end + run
+			} else {
+				j += run
+			}
+		case strings.HasPrefix(s[j:], delimiter):
+			if len(delimiter) == 1 && j+1 < len(s) && s[j+1] == delimiter[0] {
+				j += 2
+				continue
+			}
+			if j > from && !isSpace(s[j-1]) && (delimiter != "_" || j+1 == len(s) || !isWordByte(s[j+1])) {
+				return j
+			}
+			j += len(delimiter)
+		default:
+			j++
+		}
+	}
+	return -1
+}
+
+// link parses [text](url) at i; a link to an unsafe URL keeps its text and drops the URL
+func (p *inlineParser) link(i int) int {
+	s := p.source
+	if !p.noClose["]"] {
+		if closeText := p.closer(i+1, "]"); closeText < 0 {
+			p.noClose["]"] = true
+		} else if strings.HasPrefix(s[closeText:], "](") {
+			if closeURL := closingParenthesis(s[closeText+2:]); closeURL >= 0 {
+				destination := s[closeText+2 : closeText+2+closeURL]
+				if !strings.ContainsAny(destination, " \t\n") {
+					children := parseInlines(s[i+1:closeText], p.depth+1, false)
+					if safe := SafeURL(destination); safe != "" {
+						p.emit(Inline{Type: InlineLink, URL: safe, Children: children})
+					} else {
+						p.flushText()
+						p.out = append(p.out, children...)
+					}
+					return closeText + 2 + closeURL + 1
+				}
+			}
+		}
+	}
+	p.text.WriteByte('[')
+	return i + 1
+}
+
+// autolink turns a bare link at i into a link element; punctuation ending a sentence is not part of it
+func (p *inlineParser) autolink(i int) int {
+	match := autolinkPattern.FindString(p.source[i:])
+	link := strings.TrimRight(match, ".,;:!?*_~")
+	if strings.HasSuffix(link, ")") && strings.Count(link, "(") < strings.Count(link, ")") {
+		link = strings.TrimSuffix(link, ")")
+	}
+	safe := SafeURL(link)
+	if safe == "" {
+		p.text.WriteString(link)
+	} else {
+		p.emit(Inline{Type: InlineLink, URL: safe, Children: []Inline{{Type: InlineText, Text: link}}})
+	}
+	return i + len(link)
+}
+
+// emit appends an element after the pending text
+func (p *inlineParser) emit(inline Inline) {
+	p.flushText()
+	p.out = append(p.out, inline)
+}
+
+func (p *inlineParser) flushText() {
+	if p.text.Len() > 0 {
+		p.out = append(p.out, Inline{Type: InlineText, Text: p.text.String()})
+		p.text.Reset()
+	}
+}
+
+// SafeURL returns the normalized form of an absolute http, https or mailto URL, or "" for any other URL,
+// which keeps javascript:, data: and similar links out of rendered messages
+func SafeURL(raw string) string {
+	u, err := url.Parse(strings.TrimSpace(raw))
+	if err != nil {
+		return ""
+	}
+	switch strings.ToLower(u.Scheme) {
+	case "http", "https":
+		if u.Host == "" {
+			return ""
+		}
+	case "mailto":
+		if u.Opaque == "" {
+			return ""
+		}
+	default:
+		return ""
+	}
+	u.Scheme = strings.ToLower(u.Scheme)
+	return u.String()
+}
+
+// closingParenthesis returns the position of the parenthesis closing a link destination, allowing
+// balanced parentheses inside it as in Wikipedia links, or -1
+func closingParenthesis(s string) int {
+	depth := 0
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '(':
+			depth++
+		case ')':
+			if depth == 0 {
+				return i
+			}
+			depth--
+		}
+	}
+	return -1
+}
+
+// backtickRun returns the number of backticks s starts with
+func backtickRun(s string) int {
+	n := 0
+	for n < len(s) && s[n] == '`' {
+		n++
+	}
+	return n
+}
+
+// closingBackticks returns the position of the next run of exactly n backticks from i, or -1
+func closingBackticks(s string, i, n int) int {
+	for i < len(s) {
+		j := strings.IndexByte(s[i:], '`')
+		if j < 0 {
+			return -1
+		}
+		j += i
+		run := backtickRun(s[j:])
+		if run == n {
+			return j
+		}
+		i = j + run
+	}
+	return -1
+}
+
+// trimCodeSpan strips the single spaces padding a code span, as in “ `code` “
+func trimCodeSpan(code string) string {
+	if len(code) > 2 && code[0] == ' ' && code[len(code)-1] == ' ' && strings.TrimSpace(code) != "" {
+		return code[1 : len(code)-1]
+	}
+	return code
+}
+
+// EscapeMarkdown escapes literal text so that, parsed as Markdown, it reads as the same text
+// Formatting delimiters are escaped wherever they are, and list markers at the start of a line
+func EscapeMarkdown(text string) string {
+	var b strings.Builder
+	for i, line := range strings.Split(text, "\n") {
+		if i > 0 {
+			b.WriteByte('\n')
+		}
+		trimmed := strings.TrimLeft(line, " \t")
+		b.WriteString(line[:len(line)-len(trimmed)])
+		markerEnd := -1 // Index of the punctuation ending a list marker
+		if marker := listItem(trimmed); marker != "" {
+			markerEnd = len(marker) - 2
+		}
+		for j := 0; j < len(trimmed); j++ {
+			if j == markerEnd || strings.IndexByte("\\`*_~[]>", trimmed[j]) >= 0 {
+				b.WriteByte('\\')
+			}
+			b.WriteByte(trimmed[j])
+		}
+	}
+	return b.String()
+}
+
+func isPunctuation(c byte) bool {
+	return strings.IndexByte("!\"#$%&'()*+,-./:;<=>?@[\\]^_`{|}~", c) >= 0
+}
+
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n'
+}
+
+// isWordByte reports whether c is part of a word, so snake_case and URLs do not open emphasis;
+// bytes of multi-byte characters count as word bytes
+func isWordByte(c byte) bool {
+	return c >= 0x80 || c == '_' || ('0' <= c && c <= '9') || ('a' <= c && c <= 'z') || ('A' <= c && c <= 'Z')
+}
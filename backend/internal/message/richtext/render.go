@@ -0,0 +1,170 @@
+package richtext
+
+import (
+	"html"
+	"strconv"
+	"strings"
+)
+
+// PlainText renders a document without formatting, for notifications and the search index
+// Links keep their target after their text, and buttons are listed by label
+func PlainText(doc *Document) string {
+	var b strings.Builder
+	writeTextBlocks(&b, doc.Blocks)
+	return b.String()
+}
+
+func writeTextBlocks(b *strings.Builder, blocks []Block) {
+	for i, block := range blocks {
+		if i > 0 {
+			b.WriteString("\n\n")
+		}
+		switch block.Type {
+		case BlockParagraph:
+			writeTextInlines(b, block.Inlines)
+		case BlockList:
+			for n, item := range block.Items {
+				if n > 0 {
+					b.WriteByte('\n')
+				}
+				if block.Ordered {
+					b.WriteString(strconv.Itoa(n+1) + ". ")
+				} else {
+					b.WriteString("- ")
+				}
+				writeTextInlines(b, item)
+			}
+		case BlockCode:
+			b.WriteString(block.Text)
+		case BlockQuote:
+			var quoted strings.Builder
+			writeTextBlocks(&quoted, block.Blocks)
+			for n, line := range strings.Split(quoted.String(), "\n") {
+				if n > 0 {
+					b.WriteByte('\n')
+				}
+				b.WriteString(strings.TrimRight("> "+line, " "))
+			}
+		case BlockAttachment:
+			b.WriteString("Attachment: " + block.Attachment.Name)
+		case BlockActions:
+			for n, button := range block.Buttons {
+				if n > 0 {
+					b.WriteByte(' ')
+				}
+				b.WriteString("[" + button.Label + "]")
+			}
+		}
+	}
+}
+
+func writeTextInlines(b *strings.Builder, inlines []Inline) {
+	for _, inline := range inlines {
+		switch inline.Type {
+		case InlineText, InlineCode:
+			b.WriteString(inline.Text)
+		case InlineBreak:
+			b.WriteByte('\n')
+		case InlineLink:
+			var text strings.Builder
+			writeTextInlines(&text, inline.Children)
+			b.WriteString(text.String())
+			if text.String() != inline.URL {
+				b.WriteString(" (" + inline.URL + ")")
+			}
+		default:
+			writeTextInlines(b, inline.Children)
+		}
+	}
+}
+
+// HTML renders a document as an HTML fragment. Every text is escaped and links only carry safe URLs,
+// so the fragment can be inserted in a page or an email as is
+func HTML(doc *Document) string {
+	var b strings.Builder
+	writeHTMLBlocks(&b, doc.Blocks)
+	return b.String()
+}
+
+func writeHTMLBlocks(b *strings.Builder, blocks []Block) {
+	for _, block := range blocks {
+		switch block.Type {
+		case BlockParagraph:
+			b.WriteString("<p>")
+			writeHTMLInlines(b, block.Inlines)
+			b.WriteString("</p>")
+		case BlockList:
+			tag := "ul"
+			if block.Ordered {
+				tag = "ol"
+			}
+			b.WriteString("<" + tag + ">")
+			for _, item := range block.Items {
+				b.WriteString("<li>")
+				writeHTMLInlines(b, item)
+				b.WriteString("</li>")
+			}
+			b.WriteString("</" + tag + ">")
+		case BlockCode:
+			b.WriteString("<pre><code")
+			if block.Language != "" {
+				b.WriteString(` class="language-` + html.EscapeString(block.Language) + `"`)
+			}
+			b.WriteString(">" + html.EscapeString(block.Text) + "</code></pre>")
+		case BlockQuote:
+			b.WriteString("<blockquote>")
+			writeHTMLBlocks(b, block.Blocks)
+			b.WriteString("</blockquote>")
+		case BlockAttachment:
+			b.WriteString(`<p class="attachment">`)
+			writeAnchor(b, block.Attachment.URL, "", html.EscapeString(block.Attachment.Name))
+			b.WriteString("</p>")
+		case BlockActions:
+			b.WriteString(`<div class="actions">`)
+			for _, button := range block.Buttons {
+				class := "button"
+				if button.Style != ButtonDefault {
+					class += " button-" + string(button.Style)
+				}
+				label := html.EscapeString(button.Label)
+				if button.URL != "" {
+					writeAnchor(b, button.URL, class, label)
+				} else {
+					b.WriteString(`<button type="button" class="` + class + `" data-action-id="` + html.EscapeString(button.ActionID) + `">` + label + "</button>")
+				}
+			}
+			b.WriteString("</div>")
+		}
+	}
+}
+
+func writeHTMLInlines(b *strings.Builder, inlines []Inline) {
+	for _, inline := range inlines {
+		switch inline.Type {
+		case InlineText:
+			b.WriteString(html.EscapeString(inline.Text))
+		case InlineBreak:
+			b.WriteString("<br>")
+		case InlineCode:
+			b.WriteString("<code>" + html.EscapeString(inline.Text) + "</code>")
+		case InlineStrong, InlineEmphasis, InlineStrike:
+			tag := map[InlineType]string{InlineStrong: "strong", InlineEmphasis: "em", InlineStrike: "del"}[inline.Type]
+			b.WriteString("<" + tag + ">")
+			writeHTMLInlines(b, inline.Children)
+			b.WriteString("</" + tag + ">")
+		case InlineLink:
+			var text strings.Builder
+			writeHTMLInlines(&text, inline.Children)
+			writeAnchor(b, inline.URL, "", text.String())
+		}
+	}
+}
+
+// writeAnchor writes a link opening outside the app; content must already be escaped
+func writeAnchor(b *strings.Builder, url, class, content string) {
+	b.WriteString(`<a href="` + html.EscapeString(url) + `"`)
+	if class != "" {
+		b.WriteString(` class="` + class + `"`)
+	}
+	b.WriteString(` rel="nofollow noopener noreferrer" target="_blank">` + content + "</a>")
+}
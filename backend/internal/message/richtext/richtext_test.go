@@ -0,0 +1,168 @@
+package richtext
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func build(t *testing.T, input Input, options Options) *Body {
+	t.Helper()
+	body, err := Build(input, options)
+	require.NoError(t, err)
+	return body
+}
+
+func TestBuild_MarkdownSubset(t *testing.T) {
+	content := "Release **v2** is _out_, see [notes](https://example.com/notes) or https://example.com/x.\n" +
+		"Use `make ~~all~~` not ~~make~~\n\n" +
+		"- one\n- *two*\n\n" +
+		"1. first\n2. second\n\n" +
+		"> quoted **text**\n> > nested\n\n" +
+		"```go\nfmt.Println(\"<hi>\")\n```"
+
+	body := build(t, Input{Format: FormatRich, Content: content}, Options{})
+
+	assert.Equal(t, FormatRich, body.Format)
+	assert.Equal(t, content, body.Source)
+	require.Len(t, body.Document.Blocks, 5)
+	assert.Equal(t, []BlockType{BlockParagraph, BlockList, BlockList, BlockQuote, BlockCode}, blockTypes(body.Document.Blocks))
+	assert.Equal(t, "go", body.Document.Blocks[4].Language)
+	assert.Equal(t, BlockQuote, body.Document.Blocks[3].Blocks[1].Type, "quotes nest")
+
+	assert.Equal(t,
+		`<p>Release <strong>v2</strong> is <em>out</em>, see <a href="https://example.com/notes" rel="nofollow noopener noreferrer" target="_blank">notes</a>`+
+			` or <a href="https://example.com/x" rel="nofollow noopener noreferrer" target="_blank">https://example.com/x</a>.<br>`+
+			`Use <code>make ~~all~~</code> not <del>make</del></p>`+
+			`<ul><li>one</li><li><em>two</em></li></ul>`+
+			`<ol><li>first</li><li>second</li></ol>`+
+			`<blockquote><p>quoted <strong>text</strong></p><blockquote><p>nested</p></blockquote></blockquote>`+
+			`<pre><code class="language-go">fmt.Println(&#34;&lt;hi&gt;&#34;)</code></pre>`,
+		body.HTML)
+	assert.Equal(t,
+		"Release v2 is out, see notes (https://example.com/notes) or https://example.com/x.\nUse make ~~all~~ not make\n\n"+
+			"- one\n- two\n\n1. first\n2. second\n\n> quoted text\n>\n> > nested\n\nfmt.Println(\"<hi>\")",
+		body.PlainText)
+}
+
+func TestBuild_KeepsUnsupportedSyntaxAsText(t *testing.T) {
+	cases := map[string]string{
+		"snake_case_name and 2 * 3 * 4": "<p>snake_case_name and 2 * 3 * 4</p>",
+		"# heading":                     "<p># heading</p>",
+		"**unclosed and `open":          "<p>**unclosed and `open</p>",
+		`\*not emphasis\*`:              "<p>*not emphasis*</p>",
+		"![alt](https://t.example/p)":   `<p>!<a href="https://t.example/p" rel="nofollow noopener noreferrer" target="_blank">alt</a></p>`,
+	}
+	for content, expected := range cases {
+		assert.Equal(t, expected, build(t, Input{Format: FormatRich, Content: content}, Options{}).HTML, content)
+	}
+}
+
+func TestBuild_SanitizesDangerousConstructs(t *testing.T) {
+	content := "<script>alert(1)</script> [click](javascript:alert(1)) [mail](mailto:a@example.com)\u202egnp.exe\x00"
+
+	body := build(t, Input{Format: FormatRich, Content: content}, Options{})
+
+	assert.Equal(t,
+		`<p>&lt;script&gt;alert(1)&lt;/script&gt; click <a href="mailto:a@example.com" rel="nofollow noopener noreferrer" target="_blank">mail</a>gnp.exe</p>`,
+		body.HTML)
+	assert.NotContains(t, body.Source, "\u202e")
+	assert.NotContains(t, body.Source, "\x00")
+}
+
+func TestBuild_PlainFormatIsLiteral(t *testing.T) {
+	body := build(t, Input{Content: "**not bold** <b>\nsecond line\n\n\nnext"}, Options{})
+
+	assert.Equal(t, FormatPlain, body.Format)
+	assert.Equal(t, "<p>**not bold** &lt;b&gt;<br>second line</p><p>next</p>", body.HTML)
+	assert.Equal(t, "**not bold** <b>\nsecond line\n\nnext", body.PlainText)
+}
+
+func TestBuild_StructuredBlocks(t *testing.T) {
+	input := Input{
+		Format:  FormatRich,
+		Content: "Deploy ready",
+		Blocks: []Block{
+			{Type: BlockCode, Language: "Bash", Text: "make deploy"},
+			{Type: BlockQuote, Text: "from *ops*"},
+			{Type: BlockAttachment, Attachment: &Attachment{URL: "https://files.example.com/r.pdf", Name: "report.pdf", MimeType: "application/pdf"}},
+			{Type: BlockActions, Buttons: []Button{
+				{Label: "Approve", ActionID: "approve", Style: ButtonPrimary},
+				{Label: "Open", URL: "https://ci.example.com/1"},
+			}},
+		},
+	}
+
+	body := build(t, input, Options{AllowButtons: true})
+
+	assert.Equal(t, []BlockType{BlockParagraph, BlockCode, BlockQuote, BlockAttachment, BlockActions}, blockTypes(body.Document.Blocks))
+	assert.Contains(t, body.HTML, `<pre><code class="language-bash">make deploy</code></pre>`)
+	assert.Contains(t, body.HTML, `<blockquote><p>from <em>ops</em></p></blockquote>`)
+	assert.Contains(t, body.HTML, `<button type="button" class="button button-primary" data-action-id="approve">Approve</button>`)
+	assert.Contains(t, body.HTML, `<a href="https://ci.example.com/1" class="button" rel="nofollow noopener noreferrer" target="_blank">Open</a>`)
+	assert.Equal(t, "Deploy ready\n\nmake deploy\n\n> from ops\n\nAttachment: report.pdf\n\n[Approve] [Open]", body.PlainText)
+}
+
+func TestBuild_RejectsInvalidInput(t *testing.T) {
+	button := Block{Type: BlockActions, Buttons: []Button{{Label: "Go", ActionID: "go"}}}
+	cases := []struct {
+		name    string
+		input   Input
+		options Options
+	}{
+		{"unknown format", Input{Format: 7, Content: "hi"}, Options{}},
+		{"empty", Input{Format: FormatRich, Content: " \n\n "}, Options{}},
+		{"too long", Input{Content: strings.Repeat("a", MaxContentLength+1)}, Options{}},
+		{"blocks in plain format", Input{Content: "hi", Blocks: []Block{{Type: BlockCode, Text: "x"}}}, Options{}},
+		{"buttons from a user", Input{Format: FormatRich, Content: "hi", Blocks: []Block{button}}, Options{}},
+		{"paragraph block", Input{Format: FormatRich, Blocks: []Block{{Type: BlockParagraph}}}, Options{}},
+		{"script attachment", Input{Format: FormatRich, Blocks: []Block{{Type: BlockAttachment, Attachment: &Attachment{URL: "javascript:alert(1)", Name: "x"}}}}, Options{}},
+		{"button without action", Input{Format: FormatRich, Blocks: []Block{{Type: BlockActions, Buttons: []Button{{Label: "Go"}}}}}, Options{AllowButtons: true}},
+		{"data URL button", Input{Format: FormatRich, Blocks: []Block{{Type: BlockActions, Buttons: []Button{{Label: "Go", URL: "data:text/html,x"}}}}}, Options{AllowButtons: true}},
+		{"bad language", Input{Format: FormatRich, Blocks: []Block{{Type: BlockCode, Language: "a b", Text: "x"}}}, Options{}},
+	}
+	for _, tc := range cases {
+		_, err := Build(tc.input, tc.options)
+		assert.Error(t, err, tc.name)
+	}
+
+	_, err := Build(Input{Format: 7}, Options{})
+	assert.True(t, errors.Is(err, ErrUnsupportedFormat))
+}
+
+func TestParseInlines_BoundsNesting(t *testing.T) {
+	content := strings.Repeat("*a ", 20) + strings.Repeat(" b*", 20) + strings.Repeat("**", 5000)
+
+	body := build(t, Input{Format: FormatRich, Content: content}, Options{})
+
+	assert.NotEmpty(t, body.HTML)
+}
+
+func TestPlain(t *testing.T) {
+	body := Plain("set the channel topic: <b>*launch*</b>")
+
+	assert.Equal(t, FormatPlain, body.Format)
+	assert.Equal(t, "<p>set the channel topic: &lt;b&gt;*launch*&lt;/b&gt;</p>", body.HTML)
+	assert.Equal(t, "set the channel topic: <b>*launch*</b>", body.PlainText)
+}
+
+func TestEscapeMarkdown_ReadsAsLiteralText(t *testing.T) {
+	literal := "- not a list\n2. nor this\n> nor a quote\n```\n**not bold** _or_ `code` [link](x) ~~strike~~ back\\slash"
+
+	body, err := Build(Input{Format: FormatRich, Content: EscapeMarkdown(literal)}, Options{})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []BlockType{BlockParagraph}, blockTypes(body.Document.Blocks))
+	assert.Equal(t, literal, body.PlainText)
+}
+
+func blockTypes(blocks []Block) []BlockType {
+	types := make([]BlockType, len(blocks))
+	for i, block := range blocks {
+		types[i] = block.Type
+	}
+	return types
+}
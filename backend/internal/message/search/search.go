@@ -0,0 +1,142 @@
+package search
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"gorm.io/gorm"
+
+	"thothix-backend/internal/encryption"
+)
+
+const (
+	messagesTable = "messages"
+	filesTable    = "files"
+	tokensTable   = "message_search_tokens"
+)
+
+// RegisterGORM indexes the words of every new message when encryption is enabled
+func RegisterGORM(db *gorm.DB) error {
+	return db.Callback().Create().After("gorm:create").Register("search:index_messages", indexCreated)
+}
+
+// indexCreated writes the search tokens of the messages created by the statement, from their plain text
+// rendering, or from their content for messages stored without one
+func indexCreated(db *gorm.DB) {
+	stmt := db.Statement
+	keyring := encryption.Default()
+	if keyring == nil || db.Error != nil || stmt.Schema == nil || stmt.Schema.Table != messagesTable {
+		return
+	}
+	idField, contentField, textField := stmt.Schema.LookUpField("ID"), stmt.Schema.LookUpField("Content"), stmt.Schema.LookUpField("PlainText")
+
+	tx := db.Session(&gorm.Session{NewDB: true})
+	forEachElement(stmt.ReflectValue, func(elem reflect.Value) {
+		id, _ := idField.ValueOf(stmt.Context, elem)
+		content, _ := contentField.ValueOf(stmt.Context, elem)
+		if text, _ := textField.ValueOf(stmt.Context, elem); fmt.Sprint(text) != "" {
+			content = text
+		}
+		if err := Index(tx, keyring.Index(), fmt.Sprint(id), fmt.Sprint(content)); err != nil {
+			_ = db.AddError(fmt.Errorf("search: failed to index message: %w", err))
+		}
+	})
+}
+
+// Index replaces the search tokens of a message
+// Raw statements keep the tokens out of the audit log, which would otherwise record one entry per word
+func Index(tx *gorm.DB, index *encryption.BlindIndex, messageID, content string) error {
+	if err := tx.Exec("DELETE FROM "+tokensTable+" WHERE message_id = ?", messageID).Error; err != nil {
+		return err
+	}
+	tokens := index.Tokens(content)
+	if len(tokens) == 0 {
+		return nil
+	}
+
+	values := make([]string, len(tokens))
+	args := make([]interface{}, 0, 2*len(tokens))
+	for i, token := range tokens {
+		values[i] = "(?, ?)"
+		args = append(args, messageID, token)
+	}
+	return tx.Exec("INSERT INTO "+tokensTable+" (message_id, token) VALUES "+strings.Join(values, ", ")+" ON CONFLICT DO NOTHING", args...).Error
+}
+
+// Matching restricts a query on messages to those containing every word of query
+// With encryption enabled it looks the words up in the blind index, since the stored content is
+// ciphertext; otherwise it matches the plain text rendering, or the content of older messages
+func Matching(query string) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		keyring := encryption.Default()
+		if keyring == nil {
+			for _, word := range strings.Fields(query) {
+				db = db.Where("COALESCE(NULLIF(messages.plain_text, ''), messages.content) ILIKE ?", "%"+escapeLike(word)+"%")
+			}
+			return db
+		}
+
+		// Messages not yet re-indexed after a key rotation are found through the retired keys
+		tokens, words := keyring.Index().QueryTokens(query)
+		if words == 0 {
+			return db.Where("1 = 0")
+		}
+		matches := db.Session(&gorm.Session{NewDB: true}).
+			Table(tokensTable).
+			Select("message_id").
+			Where("token IN ?", tokens).
+			Group("message_id").
+			Having("COUNT(*) = ?", words)
+		return db.Where("messages.id IN (?)", matches)
+	}
+}
+
+// EncryptedColumns lists the encrypted columns of the message domain for the re-encryption job
+// Re-encrypted messages are re-indexed from the same text as indexCreated, so search covers legacy
+// plain text once it is encrypted
+func EncryptedColumns(keyring *encryption.Keyring) []encryption.Column {
+	return []encryption.Column{
+		{
+			Table:  messagesTable,
+			Column: "content",
+			OnRewrite: func(tx *gorm.DB, id, plaintext string) error {
+				var rendered int64
+				if err := tx.Table(messagesTable).Where("id::text = ? AND plain_text <> ''", id).Count(&rendered).Error; err != nil || rendered > 0 {
+					return err
+				}
+				return Index(tx, keyring.Index(), id, plaintext)
+			},
+		},
+		{
+			Table:  messagesTable,
+			Column: "plain_text",
+			OnRewrite: func(tx *gorm.DB, id, plaintext string) error {
+				return Index(tx, keyring.Index(), id, plaintext)
+			},
+		},
+		{Table: messagesTable, Column: "html"},
+		{Table: messagesTable, Column: "document"},
+		{Table: filesTable, Column: "url"},
+	}
+}
+
+// escapeLike escapes the LIKE wildcards in s
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(s)
+}
+
+// forEachElement calls fn for a struct value or for every struct in a slice
+func forEachElement(value reflect.Value, fn func(reflect.Value)) {
+	value = reflect.Indirect(value)
+	switch value.Kind() {
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < value.Len(); i++ {
+			if elem := reflect.Indirect(value.Index(i)); elem.Kind() == reflect.Struct {
+				fn(elem)
+			}
+		}
+	case reflect.Struct:
+		fn(value)
+	}
+}
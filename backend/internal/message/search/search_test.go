@@ -0,0 +1,139 @@
+package search
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/gorm"
+	"thothix-backend/internal/encryption"
+	messageDomain "thothix-backend/internal/message/domain"
+	"thothix-backend/internal/message/richtext"
+	sharedTesting "thothix-backend/internal/shared/testing"
+)
+
+type SearchTestSuite struct {
+	suite.Suite
+	container *sharedTesting.PostgresTestContainer
+	keyring   *encryption.Keyring
+}
+
+func (suite *SearchTestSuite) SetupSuite() {
+	suite.container = sharedTesting.GetSharedTestContainer(
+		suite.T(),
+		"message/search",
+		[]interface{}{&messageDomain.Message{}, &messageDomain.File{}, &messageDomain.MessageSearchToken{}},
+	)
+	require.NoError(suite.T(), RegisterGORM(suite.container.DB))
+	suite.keyring = suite.newKeyring(1, "app-secret", nil)
+	encryption.SetDefault(suite.keyring)
+}
+
+func (suite *SearchTestSuite) TearDownSuite() {
+	encryption.SetDefault(nil)
+}
+
+func (suite *SearchTestSuite) newKeyring(version int, secret string, retired map[int]string) *encryption.Keyring {
+	wrapper, err := encryption.NewLocalWrapper(version, secret, retired)
+	require.NoError(suite.T(), err)
+	index, err := encryption.NewBlindIndex("index-secret")
+	require.NoError(suite.T(), err)
+	return encryption.NewKeyring(wrapper, index)
+}
+
+// storedContent reads the raw content column, bypassing the serializer
+func (suite *SearchTestSuite) storedContent(db *gorm.DB, id string) string {
+	var content string
+	require.NoError(suite.T(), db.Table("messages").Select("content").Where("id = ?", id).Scan(&content).Error)
+	return content
+}
+
+func (suite *SearchTestSuite) TestCreate_EncryptsAndIndexes() {
+	suite.container.WithTransaction(func(db *gorm.DB) {
+		// Arrange
+		channelID := uuid.New().String()
+		message := &messageDomain.Message{SenderID: "user-1", ChannelID: &channelID, Content: "Deploy the API tonight"}
+		other := &messageDomain.Message{SenderID: "user-1", ChannelID: &channelID, Content: "Lunch?"}
+
+		// Act
+		require.NoError(suite.T(), db.Create(message).Error)
+		require.NoError(suite.T(), db.Create(other).Error)
+
+		var loaded messageDomain.Message
+		require.NoError(suite.T(), db.First(&loaded, "id = ?", message.ID).Error)
+		var found []messageDomain.Message
+		require.NoError(suite.T(), db.Where("channel_id = ?", channelID).Scopes(Matching("api DEPLOY")).Find(&found).Error)
+
+		// Assert
+		assert.True(suite.T(), encryption.IsEncrypted(suite.storedContent(db, message.ID)))
+		assert.NotContains(suite.T(), suite.storedContent(db, message.ID), "Deploy")
+		assert.Equal(suite.T(), "Deploy the API tonight", loaded.Content)
+		assert.Len(suite.T(), found, 1)
+		assert.Equal(suite.T(), message.ID, found[0].ID)
+	})
+}
+
+func (suite *SearchTestSuite) TestCreate_IndexesPlainTextRendering() {
+	suite.container.WithTransaction(func(db *gorm.DB) {
+		// Arrange: words only found in a structured block
+		channelID := uuid.New().String()
+		body, err := richtext.Build(richtext.Input{
+			Format:  richtext.FormatRich,
+			Content: "Rollback **steps**",
+			Blocks:  []richtext.Block{{Type: richtext.BlockCode, Text: "kubectl rollout undo"}},
+		}, richtext.Options{})
+		require.NoError(suite.T(), err)
+		message := &messageDomain.Message{SenderID: "user-1", ChannelID: &channelID}
+		message.SetBody(body)
+
+		// Act
+		require.NoError(suite.T(), db.Create(message).Error)
+		var found []messageDomain.Message
+		require.NoError(suite.T(), db.Where("channel_id = ?", channelID).Scopes(Matching("kubectl steps")).Find(&found).Error)
+
+		// Assert
+		require.Len(suite.T(), found, 1)
+		assert.Equal(suite.T(), body.Document, found[0].Document)
+		assert.Equal(suite.T(), "<p>Rollback <strong>steps</strong></p><pre><code>kubectl rollout undo</code></pre>", found[0].HTML)
+	})
+}
+
+func (suite *SearchTestSuite) TestReencryptor_MovesLegacyAndRetiredValues() {
+	suite.container.WithTransaction(func(db *gorm.DB) {
+		// Arrange: one legacy plain text message and one under the previous key
+		channelID := uuid.New().String()
+		legacyID := uuid.New().String()
+		require.NoError(suite.T(), db.Exec(
+			"INSERT INTO messages (id, sender_id, channel_id, content, created_at, updated_at) VALUES (?, 'user-1', ?, 'legacy release notes', now(), now())",
+			legacyID, channelID,
+		).Error)
+		old := &messageDomain.Message{SenderID: "user-1", ChannelID: &channelID, Content: "old release plan"}
+		require.NoError(suite.T(), db.Create(old).Error)
+
+		rotated := suite.newKeyring(2, "new-secret", map[int]string{1: "app-secret"})
+		encryption.SetDefault(rotated)
+		defer encryption.SetDefault(suite.keyring)
+
+		// Act
+		result, err := encryption.NewReencryptor(db, rotated, EncryptedColumns(rotated)...).RunOnce(context.Background())
+		require.NoError(suite.T(), err)
+		var found []messageDomain.Message
+		require.NoError(suite.T(), db.Where("channel_id = ?", channelID).Scopes(Matching("release")).Find(&found).Error)
+
+		// Assert
+		assert.Equal(suite.T(), 0, result.Failed)
+		assert.GreaterOrEqual(suite.T(), result.Rewritten, 2)
+		for _, id := range []string{legacyID, old.ID} {
+			assert.True(suite.T(), strings.HasPrefix(suite.storedContent(db, id), encryption.EnvelopePrefix("local-v2")))
+		}
+		assert.Len(suite.T(), found, 2)
+	})
+}
+
+func TestSearchTestSuite(t *testing.T) {
+	suite.Run(t, new(SearchTestSuite))
+}
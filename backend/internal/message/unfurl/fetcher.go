@@ -0,0 +1,209 @@
+package unfurl
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"strings"
+	"time"
+
+	"thothix-backend/internal/egress"
+)
+
+const (
+	defaultFetchTimeout = 5 * time.Second
+	defaultMaxBytes     = 512 * 1024
+	defaultMaxRedirects = 3
+
+	// maxOEmbedBytes bounds oEmbed documents, which only carry a few fields
+	maxOEmbedBytes = 64 * 1024
+
+	userAgent = "ThothixBot/1.0 (+link previews)"
+)
+
+var (
+	// ErrBlockedAddress is returned when a URL, or a redirect it leads to, resolves to a non-public address
+	ErrBlockedAddress = egress.ErrBlockedAddress
+
+	// ErrUnsupportedURL is returned for URLs other than plain http and https ones
+	ErrUnsupportedURL = egress.ErrUnsupportedURL
+)
+
+// FetcherOptions configure a Fetcher; zero values use the defaults
+type FetcherOptions struct {
+	Timeout      time.Duration // Total time for a page, its redirects and its oEmbed document, 5s when zero
+	MaxBytes     int64         // Bytes of a page read for metadata, 512 KiB when zero
+	MaxRedirects int           // Redirects followed, 3 when zero
+}
+
+// Metadata is what a page says about itself
+type Metadata struct {
+	URL         string // Final URL after redirects
+	Title       string
+	Description string
+	ImageURL    string
+	SiteName    string
+}
+
+// Fetcher downloads pages for link previews without reaching internal services: every connection,
+// including those of redirects, is checked against the resolved address, so neither private hosts
+// nor names resolving to them can be reached
+type Fetcher struct {
+	client   *http.Client
+	maxBytes int64
+	permit   func(netip.AddrPort) bool // Addresses that may be dialed; tests allow their local server
+}
+
+func NewFetcher(options FetcherOptions) *Fetcher {
+	if options.Timeout <= 0 {
+		options.Timeout = defaultFetchTimeout
+	}
+	if options.MaxBytes <= 0 {
+		options.MaxBytes = defaultMaxBytes
+	}
+	if options.MaxRedirects <= 0 {
+		options.MaxRedirects = defaultMaxRedirects
+	}
+
+	f := &Fetcher{
+		maxBytes: options.MaxBytes,
+		permit:   func(addr netip.AddrPort) bool { return egress.IsPublic(addr.Addr()) },
+	}
+	f.client = egress.NewClient(egress.ClientOptions{
+		Timeout:      options.Timeout,
+		MaxRedirects: options.MaxRedirects,
+		Permit:       func(addr netip.AddrPort) bool { return f.permit(addr) },
+	})
+	return f
+}
+
+// Fetch reads the metadata of a page from its Open Graph tags, falling back to its oEmbed document
+// and standard tags; a link to an image is previewed as that image
+func (f *Fetcher) Fetch(ctx context.Context, rawURL string) (*Metadata, error) {
+	target, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrUnsupportedURL, err)
+	}
+	if err := egress.CheckURL(target); err != nil {
+		return nil, err
+	}
+
+	resp, err := f.get(ctx, target.String(), "text/html,application/xhtml+xml;q=0.9,image/*;q=0.8")
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	final := resp.Request.URL
+
+	contentType := resp.Header.Get("Content-Type")
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	switch {
+	case strings.HasPrefix(mediaType, "image/"):
+		return &Metadata{URL: final.String(), ImageURL: final.String()}, nil
+	case mediaType != "text/html" && mediaType != "application/xhtml+xml":
+		return nil, fmt.Errorf("unfurl: unsupported content type %q", mediaType)
+	}
+
+	page := parseHTML(io.LimitReader(resp.Body, f.maxBytes), contentType)
+	metadata := &Metadata{
+		URL:         final.String(),
+		Title:       page.title(),
+		Description: page.description(),
+		ImageURL:    resolve(final, page.image()),
+		SiteName:    page.siteName(),
+	}
+
+	if page.oEmbedURL != "" && (metadata.Title == "" || metadata.ImageURL == "") {
+		if oembed, err := f.fetchOEmbed(ctx, resolve(final, page.oEmbedURL)); err == nil {
+			metadata.Title = firstNonEmpty(metadata.Title, normalize(oembed.Title))
+			metadata.Description = firstNonEmpty(metadata.Description, normalize(oembed.AuthorName))
+			metadata.ImageURL = firstNonEmpty(metadata.ImageURL, resolve(final, oembed.ThumbnailURL))
+			metadata.SiteName = firstNonEmpty(metadata.SiteName, normalize(oembed.ProviderName))
+		}
+	}
+
+	metadata.Title = clip(metadata.Title, maxTitleLength)
+	metadata.Description = clip(metadata.Description, maxDescriptionLength)
+	metadata.SiteName = clip(metadata.SiteName, maxTitleLength)
+	if metadata.Title == "" && metadata.Description == "" && metadata.ImageURL == "" {
+		return nil, errors.New("unfurl: page has no metadata")
+	}
+	return metadata, nil
+}
+
+// oEmbed holds the oEmbed fields used in previews
+type oEmbed struct {
+	Title        string `json:"title"`
+	AuthorName   string `json:"author_name"`
+	ProviderName string `json:"provider_name"`
+	ThumbnailURL string `json:"thumbnail_url"`
+}
+
+// fetchOEmbed reads the JSON oEmbed document a page links to
+func (f *Fetcher) fetchOEmbed(ctx context.Context, rawURL string) (*oEmbed, error) {
+	if rawURL == "" {
+		return nil, ErrUnsupportedURL
+	}
+	resp, err := f.get(ctx, rawURL, "application/json")
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var document oEmbed
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxOEmbedBytes)).Decode(&document); err != nil {
+		return nil, fmt.Errorf("unfurl: invalid oEmbed document: %w", err)
+	}
+	return &document, nil
+}
+
+// get requests rawURL and returns the response of a successful request
+func (f *Fetcher) get(ctx context.Context, rawURL, accept string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrUnsupportedURL, err)
+	}
+	req.Header.Set("User-Agent", userAgent)
+	req.Header.Set("Accept", accept)
+
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		resp.Body.Close()
+		return nil, fmt.Errorf("unfurl: %s returned status %d", req.URL.Host, resp.StatusCode)
+	}
+	return resp, nil
+}
+
+// resolve makes ref absolute against base, keeping only http and https results
+func resolve(base *url.URL, ref string) string {
+	if ref == "" {
+		return ""
+	}
+	parsed, err := url.Parse(ref)
+	if err != nil {
+		return ""
+	}
+	absolute := base.ResolveReference(parsed)
+	if egress.CheckURL(absolute) != nil {
+		return ""
+	}
+	return absolute.String()
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if value != "" {
+			return value
+		}
+	}
+	return ""
+}
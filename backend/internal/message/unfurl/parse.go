@@ -0,0 +1,132 @@
+package unfurl
+
+import (
+	"io"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+	"golang.org/x/net/html/charset"
+)
+
+const (
+	maxTitleLength       = 300
+	maxDescriptionLength = 1000
+)
+
+// page holds the metadata tags found in the head of an HTML document
+type page struct {
+	meta      map[string]string // First value of each Open Graph, Twitter and description meta tag
+	titleText string
+	oEmbedURL string
+}
+
+// parseHTML reads the head of an HTML document, stopping at the body or at the end of r
+func parseHTML(r io.Reader, contentType string) *page {
+	p := &page{meta: make(map[string]string)}
+	if decoded, err := charset.NewReader(r, contentType); err == nil {
+		r = decoded
+	}
+
+	tokenizer := html.NewTokenizer(r)
+	inTitle := false
+	for {
+		switch tokenizer.Next() {
+		case html.ErrorToken:
+			return p
+
+		case html.TextToken:
+			if inTitle && p.titleText == "" {
+				p.titleText = string(tokenizer.Text())
+			}
+
+		case html.EndTagToken:
+			name, _ := tokenizer.TagName()
+			switch atom.Lookup(name) {
+			case atom.Title:
+				inTitle = false
+			case atom.Head:
+				return p
+			}
+
+		case html.StartTagToken, html.SelfClosingTagToken:
+			name, hasAttributes := tokenizer.TagName()
+			switch atom.Lookup(name) {
+			case atom.Body:
+				return p
+			case atom.Title:
+				inTitle = true
+			case atom.Meta:
+				if hasAttributes {
+					p.addMeta(attributes(tokenizer))
+				}
+			case atom.Link:
+				if hasAttributes {
+					p.addLink(attributes(tokenizer))
+				}
+			}
+		}
+	}
+}
+
+// attributes reads the attributes of the current tag, with lowercase keys
+func attributes(tokenizer *html.Tokenizer) map[string]string {
+	attrs := make(map[string]string)
+	for {
+		key, value, more := tokenizer.TagAttr()
+		attrs[strings.ToLower(string(key))] = string(value)
+		if !more {
+			return attrs
+		}
+	}
+}
+
+// addMeta records Open Graph tags (property="og:*") and named tags such as description and twitter:*
+func (p *page) addMeta(attrs map[string]string) {
+	key := strings.ToLower(firstNonEmpty(attrs["property"], attrs["name"]))
+	if key == "" || attrs["content"] == "" {
+		return
+	}
+	if _, seen := p.meta[key]; !seen {
+		p.meta[key] = attrs["content"]
+	}
+}
+
+// addLink records the JSON oEmbed discovery link
+func (p *page) addLink(attrs map[string]string) {
+	if p.oEmbedURL == "" && strings.EqualFold(attrs["rel"], "alternate") &&
+		strings.EqualFold(attrs["type"], "application/json+oembed") {
+		p.oEmbedURL = attrs["href"]
+	}
+}
+
+func (p *page) title() string {
+	return normalize(firstNonEmpty(p.meta["og:title"], p.meta["twitter:title"], p.titleText))
+}
+
+func (p *page) description() string {
+	return normalize(firstNonEmpty(p.meta["og:description"], p.meta["twitter:description"], p.meta["description"]))
+}
+
+func (p *page) image() string {
+	return strings.TrimSpace(firstNonEmpty(p.meta["og:image:secure_url"], p.meta["og:image"], p.meta["og:image:url"], p.meta["twitter:image"]))
+}
+
+func (p *page) siteName() string {
+	return normalize(p.meta["og:site_name"])
+}
+
+// normalize collapses whitespace and drops invalid UTF-8, so previews render as a single clean line
+func normalize(value string) string {
+	return strings.Join(strings.Fields(strings.ToValidUTF8(value, "")), " ")
+}
+
+// clip shortens value to at most limit characters, marking the cut with an ellipsis
+func clip(value string, limit int) string {
+	if utf8.RuneCountInString(value) <= limit {
+		return value
+	}
+	runes := []rune(value)
+	return strings.TrimSpace(string(runes[:limit-1])) + "…"
+}
@@ -0,0 +1,276 @@
+package unfurl
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"log/slog"
+	"regexp"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	chatDomain "thothix-backend/internal/chat/domain"
+	"thothix-backend/internal/encryption"
+	"thothix-backend/internal/jobs/queue"
+	messageDomain "thothix-backend/internal/message/domain"
+	"thothix-backend/internal/shared/logging"
+)
+
+const (
+	// MaxPreviewsPerMessage bounds the links unfurled in one message
+	MaxPreviewsPerMessage = 3
+
+	// cacheTTL is how long a fetched preview is reused before the page is fetched again
+	cacheTTL = 24 * time.Hour
+
+	// failureTTL is how long a failed fetch is remembered, so a broken link is not fetched for every message
+	failureTTL = time.Hour
+
+	previewsTable        = "link_previews"
+	messagePreviewsTable = "message_link_previews"
+)
+
+// urlPattern finds http and https links in message content
+var urlPattern = regexp.MustCompile(`https?://[^\s<>"'` + "`" + `]+`)
+
+// ExtractURLs returns the distinct links of content in order of appearance, up to MaxPreviewsPerMessage
+// Punctuation ending a sentence or closing a parenthesis around the link is not part of it
+func ExtractURLs(content string) []string {
+	var urls []string
+	seen := make(map[string]bool)
+	for _, match := range urlPattern.FindAllString(content, -1) {
+		link := strings.TrimRight(match, ".,;:!?*_~")
+		if strings.HasSuffix(link, ")") && strings.Count(link, "(") < strings.Count(link, ")") {
+			link = strings.TrimSuffix(link, ")")
+		}
+		if seen[link] {
+			continue
+		}
+		seen[link] = true
+		urls = append(urls, link)
+		if len(urls) == MaxPreviewsPerMessage {
+			break
+		}
+	}
+	return urls
+}
+
+// unfurlPayload identifies the message a job unfurls
+type unfurlPayload struct {
+	MessageID string `json:"message_id"`
+}
+
+// unfurlKind fetches previews on their own queue, so slow sites do not hold up other jobs
+var unfurlKind = queue.Register(queue.Default, "message.unfurl", queue.Options{
+	Queue:       "unfurl",
+	MaxAttempts: 3,
+	Timeout:     time.Minute,
+}, func(ctx context.Context, db *gorm.DB, payload unfurlPayload) error {
+	return defaultUnfurler.UnfurlMessage(ctx, db, payload.MessageID)
+})
+
+// defaultUnfurler runs the unfurl jobs
+var defaultUnfurler = NewUnfurler(NewFetcher(FetcherOptions{}))
+
+// Enqueue schedules the unfurling of a new message when it has links and its channel allows previews
+// Commands and system messages are never unfurled
+func Enqueue(db *gorm.DB, message *messageDomain.Message) error {
+	if message.Type != "" && message.Type != messageDomain.MessageTypeText && message.Type != messageDomain.MessageTypeAction {
+		return nil
+	}
+	if len(ExtractURLs(message.Content)) == 0 {
+		return nil
+	}
+	if enabled, err := previewsEnabled(db, message); err != nil || !enabled {
+		return err
+	}
+	_, err := unfurlKind.Enqueue(db, unfurlPayload{MessageID: message.ID}, queue.Unique(message.ID))
+	return err
+}
+
+// previewsEnabled reports whether the channel of message allows link previews; direct messages always do
+func previewsEnabled(db *gorm.DB, message *messageDomain.Message) (bool, error) {
+	if message.ChannelID == nil {
+		return true, nil
+	}
+	var channel chatDomain.Channel
+	if err := db.Select("id", "link_previews_disabled").Where("id = ?", *message.ChannelID).First(&channel).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return false, nil
+		}
+		return false, err
+	}
+	return !channel.LinkPreviewsDisabled, nil
+}
+
+// Unfurler attaches link previews to messages, fetching pages that are not cached
+type Unfurler struct {
+	fetcher *Fetcher
+	now     func() time.Time
+}
+
+func NewUnfurler(fetcher *Fetcher) *Unfurler {
+	return &Unfurler{fetcher: fetcher, now: time.Now}
+}
+
+// UnfurlMessage attaches a preview for each link of a message
+// Pages that cannot be fetched are skipped and remembered as failed; only database errors are returned,
+// so the job is retried for them and not for broken links
+func (u *Unfurler) UnfurlMessage(ctx context.Context, db *gorm.DB, messageID string) error {
+	var message messageDomain.Message
+	if err := db.Where("id = ?", messageID).First(&message).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			// Deleted before its links were unfurled
+			return nil
+		}
+		return err
+	}
+	// Previews may have been turned off since the message was sent
+	if enabled, err := previewsEnabled(db, &message); err != nil || !enabled {
+		return err
+	}
+
+	for position, link := range ExtractURLs(message.Content) {
+		preview, err := u.preview(ctx, db, link)
+		if err != nil {
+			return err
+		}
+		if preview.Failed || !preview.HasContent() {
+			continue
+		}
+		// A raw statement keeps the attachment out of the audit log, like search tokens
+		if err := db.Exec("INSERT INTO "+messagePreviewsTable+" (message_id, preview_id, position) VALUES (?, ?, ?) ON CONFLICT DO NOTHING",
+			message.ID, preview.ID, position).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// preview returns the cached preview of link, fetching the page when it is missing or stale
+func (u *Unfurler) preview(ctx context.Context, db *gorm.DB, link string) (*messageDomain.LinkPreview, error) {
+	key := urlKey(link)
+	now := u.now()
+
+	var cached messageDomain.LinkPreview
+	err := db.Where("url_key = ?", key).First(&cached).Error
+	switch {
+	case err == nil:
+		ttl := cacheTTL
+		if cached.Failed {
+			ttl = failureTTL
+		}
+		if now.Sub(cached.FetchedAt) < ttl {
+			return &cached, nil
+		}
+	case !errors.Is(err, gorm.ErrRecordNotFound):
+		return nil, err
+	}
+
+	preview := messageDomain.LinkPreview{URLKey: key, URL: link, FetchedAt: now}
+	columns := []string{"url", "title", "description", "image_url", "site_name", "failed", "fetched_at", "updated_at"}
+	metadata, fetchErr := u.fetcher.Fetch(ctx, link)
+	if fetchErr != nil {
+		logging.FromContext(ctx).Info("Link preview unavailable", slog.Any("error", fetchErr))
+		preview.Failed = true
+		// A failed refresh keeps the metadata already shown on earlier messages
+		columns = []string{"failed", "fetched_at", "updated_at"}
+	} else {
+		preview.Title = metadata.Title
+		preview.Description = metadata.Description
+		preview.ImageURL = metadata.ImageURL
+		preview.SiteName = metadata.SiteName
+	}
+
+	if err := db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "url_key"}},
+		DoUpdates: clause.AssignmentColumns(columns),
+	}).Create(&preview).Error; err != nil {
+		return nil, err
+	}
+	return &preview, nil
+}
+
+// urlKey identifies a link in the cache; with encryption enabled it is a keyed hash, so the stored key
+// does not reveal which links were shared
+func urlKey(link string) string {
+	if keyring := encryption.Default(); keyring != nil {
+		return keyring.Index().Key(link)
+	}
+	sum := sha256.Sum256([]byte(link))
+	return hex.EncodeToString(sum[:])
+}
+
+// Attach sets the Previews of each message, leaving out channels where previews were turned off
+func Attach(db *gorm.DB, messages []messageDomain.Message) error {
+	ids := make([]string, 0, len(messages))
+	channelIDs := make([]string, 0, len(messages))
+	for _, message := range messages {
+		ids = append(ids, message.ID)
+		if message.ChannelID != nil {
+			channelIDs = append(channelIDs, *message.ChannelID)
+		}
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+
+	var links []messageDomain.MessageLinkPreview
+	if err := db.Where("message_id IN ?", ids).Order("position").Find(&links).Error; err != nil {
+		return err
+	}
+	if len(links) == 0 {
+		return nil
+	}
+
+	var disabled []string
+	if len(channelIDs) > 0 {
+		if err := db.Model(&chatDomain.Channel{}).Where("id IN ? AND link_previews_disabled", channelIDs).Pluck("id", &disabled).Error; err != nil {
+			return err
+		}
+	}
+	hidden := make(map[string]bool, len(disabled))
+	for _, id := range disabled {
+		hidden[id] = true
+	}
+
+	previewIDs := make([]string, 0, len(links))
+	for _, link := range links {
+		previewIDs = append(previewIDs, link.PreviewID)
+	}
+	var previews []messageDomain.LinkPreview
+	if err := db.Where("id IN ?", previewIDs).Find(&previews).Error; err != nil {
+		return err
+	}
+	byID := make(map[string]*messageDomain.LinkPreview, len(previews))
+	for i := range previews {
+		byID[previews[i].ID] = &previews[i]
+	}
+
+	byMessage := make(map[string][]*messageDomain.LinkPreview)
+	for _, link := range links {
+		if preview := byID[link.PreviewID]; preview != nil && preview.HasContent() {
+			byMessage[link.MessageID] = append(byMessage[link.MessageID], preview)
+		}
+	}
+	for i := range messages {
+		if messages[i].ChannelID != nil && hidden[*messages[i].ChannelID] {
+			continue
+		}
+		messages[i].Previews = byMessage[messages[i].ID]
+	}
+	return nil
+}
+
+// EncryptedColumns lists the encrypted columns of link previews for the re-encryption job
+func EncryptedColumns() []encryption.Column {
+	columns := make([]encryption.Column, 0, 5)
+	for _, column := range []string{"url", "title", "description", "image_url", "site_name"} {
+		columns = append(columns, encryption.Column{Table: previewsTable, Column: column})
+	}
+	return columns
+}
@@ -0,0 +1,195 @@
+package unfurl
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/gorm"
+
+	chatDomain "thothix-backend/internal/chat/domain"
+	jobsDomain "thothix-backend/internal/jobs/domain"
+	messageDomain "thothix-backend/internal/message/domain"
+	sharedTesting "thothix-backend/internal/shared/testing"
+)
+
+func TestExtractURLs(t *testing.T) {
+	cases := []struct {
+		content  string
+		expected []string
+	}{
+		{"no links here", nil},
+		{"see https://example.com/a.", []string{"https://example.com/a"}},
+		{"(docs at https://example.com/docs)", []string{"https://example.com/docs"}},
+		{"https://en.wikipedia.org/wiki/Go_(language)!", []string{"https://en.wikipedia.org/wiki/Go_(language)"}},
+		{"http://a.test and http://a.test again", []string{"http://a.test"}},
+		{"<https://b.test/x?y=1>, ftp://c.test", []string{"https://b.test/x?y=1"}},
+		{"https://1.test https://2.test https://3.test https://4.test", []string{"https://1.test", "https://2.test", "https://3.test"}},
+	}
+	for _, tc := range cases {
+		assert.Equal(t, tc.expected, ExtractURLs(tc.content), tc.content)
+	}
+}
+
+type UnfurlTestSuite struct {
+	suite.Suite
+	container *sharedTesting.PostgresTestContainer
+	server    *httptest.Server
+	hits      atomic.Int32
+	unfurler  *Unfurler
+}
+
+func (suite *UnfurlTestSuite) SetupSuite() {
+	suite.container = sharedTesting.GetSharedTestContainer(
+		suite.T(),
+		"message/unfurl",
+		[]interface{}{
+			&chatDomain.Channel{}, &messageDomain.Message{}, &messageDomain.LinkPreview{},
+			&messageDomain.MessageLinkPreview{}, &jobsDomain.Job{},
+		},
+	)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/article", func(w http.ResponseWriter, r *http.Request) {
+		suite.hits.Add(1)
+		w.Header().Set("Content-Type", "text/html")
+		fmt.Fprint(w, articleHTML)
+	})
+	mux.HandleFunc("/missing", func(w http.ResponseWriter, r *http.Request) {
+		suite.hits.Add(1)
+		http.NotFound(w, r)
+	})
+	suite.server = httptest.NewServer(mux)
+	suite.unfurler = NewUnfurler(localFetcher(FetcherOptions{}, suite.server))
+}
+
+func (suite *UnfurlTestSuite) TearDownSuite() {
+	suite.server.Close()
+}
+
+func (suite *UnfurlTestSuite) SetupTest() {
+	suite.hits.Store(0)
+}
+
+func (suite *UnfurlTestSuite) createMessage(db *gorm.DB, channel *chatDomain.Channel, content string) *messageDomain.Message {
+	message := &messageDomain.Message{SenderID: "user-1", ChannelID: &channel.ID, Content: content}
+	require.NoError(suite.T(), db.Create(message).Error)
+	return message
+}
+
+func (suite *UnfurlTestSuite) createChannel(db *gorm.DB, disabled bool) *chatDomain.Channel {
+	channel := &chatDomain.Channel{Name: "general", WorkspaceID: "workspace-1", LinkPreviewsDisabled: disabled}
+	require.NoError(suite.T(), db.Create(channel).Error)
+	return channel
+}
+
+func (suite *UnfurlTestSuite) TestUnfurlMessage_AttachesAndCachesPreviews() {
+	suite.container.WithTransaction(func(db *gorm.DB) {
+		// Arrange
+		channel := suite.createChannel(db, false)
+		first := suite.createMessage(db, channel, "Read "+suite.server.URL+"/article.")
+		second := suite.createMessage(db, channel, "Again: "+suite.server.URL+"/article")
+
+		// Act
+		require.NoError(suite.T(), suite.unfurler.UnfurlMessage(context.Background(), db, first.ID))
+		require.NoError(suite.T(), suite.unfurler.UnfurlMessage(context.Background(), db, second.ID))
+		messages := []messageDomain.Message{*first, *second}
+		require.NoError(suite.T(), Attach(db, messages))
+
+		// Assert
+		assert.Equal(suite.T(), int32(1), suite.hits.Load(), "the second message uses the cached preview")
+		for _, message := range messages {
+			require.Len(suite.T(), message.Previews, 1)
+			assert.Equal(suite.T(), "Release notes & more", message.Previews[0].Title)
+			assert.Equal(suite.T(), suite.server.URL+"/images/cover.png", message.Previews[0].ImageURL)
+		}
+	})
+}
+
+func (suite *UnfurlTestSuite) TestUnfurlMessage_RemembersFailures() {
+	suite.container.WithTransaction(func(db *gorm.DB) {
+		// Arrange
+		channel := suite.createChannel(db, false)
+		first := suite.createMessage(db, channel, suite.server.URL+"/missing")
+		second := suite.createMessage(db, channel, suite.server.URL+"/missing")
+
+		// Act
+		require.NoError(suite.T(), suite.unfurler.UnfurlMessage(context.Background(), db, first.ID))
+		require.NoError(suite.T(), suite.unfurler.UnfurlMessage(context.Background(), db, second.ID))
+		messages := []messageDomain.Message{*first}
+		require.NoError(suite.T(), Attach(db, messages))
+
+		// Assert
+		assert.Equal(suite.T(), int32(1), suite.hits.Load())
+		assert.Empty(suite.T(), messages[0].Previews)
+		var preview messageDomain.LinkPreview
+		require.NoError(suite.T(), db.Where("url_key = ?", urlKey(suite.server.URL+"/missing")).First(&preview).Error)
+		assert.True(suite.T(), preview.Failed)
+	})
+}
+
+func (suite *UnfurlTestSuite) TestDisabledChannel_IsNotUnfurled() {
+	suite.container.WithTransaction(func(db *gorm.DB) {
+		// Arrange
+		channel := suite.createChannel(db, true)
+		message := suite.createMessage(db, channel, suite.server.URL+"/article")
+
+		// Act
+		require.NoError(suite.T(), Enqueue(db, message))
+		require.NoError(suite.T(), suite.unfurler.UnfurlMessage(context.Background(), db, message.ID))
+
+		// Assert
+		var jobs int64
+		require.NoError(suite.T(), db.Model(&jobsDomain.Job{}).Where("kind = ?", unfurlKind.Name()).Count(&jobs).Error)
+		assert.Zero(suite.T(), jobs)
+		assert.Zero(suite.T(), suite.hits.Load())
+	})
+}
+
+func (suite *UnfurlTestSuite) TestAttach_HidesPreviewsOnceDisabled() {
+	suite.container.WithTransaction(func(db *gorm.DB) {
+		// Arrange
+		channel := suite.createChannel(db, false)
+		message := suite.createMessage(db, channel, suite.server.URL+"/article")
+		require.NoError(suite.T(), suite.unfurler.UnfurlMessage(context.Background(), db, message.ID))
+		require.NoError(suite.T(), db.Model(channel).Update("link_previews_disabled", true).Error)
+
+		// Act
+		messages := []messageDomain.Message{*message}
+		require.NoError(suite.T(), Attach(db, messages))
+
+		// Assert
+		assert.Empty(suite.T(), messages[0].Previews)
+	})
+}
+
+func (suite *UnfurlTestSuite) TestEnqueue_OnlyMessagesWithLinks() {
+	suite.container.WithTransaction(func(db *gorm.DB) {
+		// Arrange
+		channel := suite.createChannel(db, false)
+		plain := suite.createMessage(db, channel, "no links")
+		linked := suite.createMessage(db, channel, "see "+suite.server.URL+"/article")
+
+		// Act
+		require.NoError(suite.T(), Enqueue(db, plain))
+		require.NoError(suite.T(), Enqueue(db, linked))
+		require.NoError(suite.T(), Enqueue(db, linked))
+
+		// Assert
+		var jobs []jobsDomain.Job
+		require.NoError(suite.T(), db.Where("kind = ?", unfurlKind.Name()).Find(&jobs).Error)
+		require.Len(suite.T(), jobs, 1, "unfurl jobs are unique per message")
+		require.NotNil(suite.T(), jobs[0].UniqueKey)
+		assert.Equal(suite.T(), linked.ID, *jobs[0].UniqueKey)
+	})
+}
+
+func TestUnfurlTestSuite(t *testing.T) {
+	suite.Run(t, new(UnfurlTestSuite))
+}
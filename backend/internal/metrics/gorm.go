@@ -0,0 +1,95 @@
+package metrics
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"gorm.io/gorm"
+)
+
+const (
+	// startTimeKey stores the statement start time on the GORM instance
+	startTimeKey = "metrics:start_time"
+
+	// dbName labels the connection pool stats
+	dbName = "thothix"
+)
+
+// RegisterGORM records query durations through GORM callbacks and exports the connection pool stats
+func RegisterGORM(db *gorm.DB) error {
+	callbacks := db.Callback()
+	for _, op := range []struct {
+		name   string
+		before func(name string, fn func(*gorm.DB)) error
+		after  func(name string, fn func(*gorm.DB)) error
+	}{
+		{"create", callbacks.Create().Before("gorm:create").Register, callbacks.Create().After("gorm:create").Register},
+		{"query", callbacks.Query().Before("gorm:query").Register, callbacks.Query().After("gorm:query").Register},
+		{"update", callbacks.Update().Before("gorm:update").Register, callbacks.Update().After("gorm:update").Register},
+		{"delete", callbacks.Delete().Before("gorm:delete").Register, callbacks.Delete().After("gorm:delete").Register},
+		{"row", callbacks.Row().Before("gorm:row").Register, callbacks.Row().After("gorm:row").Register},
+		{"raw", callbacks.Raw().Before("gorm:raw").Register, callbacks.Raw().After("gorm:raw").Register},
+	} {
+		if err := op.before(fmt.Sprintf("metrics:before_%s", op.name), startTimer); err != nil {
+			return err
+		}
+		if err := op.after(fmt.Sprintf("metrics:after_%s", op.name), observeDuration(op.name)); err != nil {
+			return err
+		}
+	}
+
+	sqlDB, err := db.DB()
+	if err != nil {
+		return err
+	}
+	if err := Registry.Register(&dbStatsCollector{db: db, descriptor: collectors.NewDBStatsCollector(sqlDB, dbName)}); err != nil {
+		if _, ok := err.(prometheus.AlreadyRegisteredError); !ok {
+			return err
+		}
+	}
+	return nil
+}
+
+// dbStatsCollector exports the stats of the pool the database currently uses, which changes
+// when credential rotation swaps it
+type dbStatsCollector struct {
+	db         *gorm.DB
+	descriptor prometheus.Collector
+}
+
+func (c *dbStatsCollector) Describe(ch chan<- *prometheus.Desc) {
+	c.descriptor.Describe(ch)
+}
+
+func (c *dbStatsCollector) Collect(ch chan<- prometheus.Metric) {
+	sqlDB, err := c.db.DB()
+	if err != nil {
+		return
+	}
+	collectors.NewDBStatsCollector(sqlDB, dbName).Collect(ch)
+}
+
+func startTimer(db *gorm.DB) {
+	db.InstanceSet(startTimeKey, time.Now())
+}
+
+func observeDuration(operation string) func(*gorm.DB) {
+	return func(db *gorm.DB) {
+		value, ok := db.InstanceGet(startTimeKey)
+		if !ok {
+			return
+		}
+		start, ok := value.(time.Time)
+		if !ok {
+			return
+		}
+
+		table := db.Statement.Table
+		if table == "" {
+			table = "unknown" // Raw SQL does not name a model
+		}
+		DBQueryDuration.WithLabelValues(operation, table).Observe(time.Since(start).Seconds())
+	}
+}
@@ -0,0 +1,116 @@
+package metrics
+
+import (
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// namespace prefixes every metric exported by the backend
+const namespace = "thothix"
+
+// Webhook processing outcomes
+const (
+	WebhookProcessed = "processed" // Handled successfully
+	WebhookIgnored   = "ignored"   // Event type not handled
+	WebhookInvalid   = "invalid"   // Payload failed validation
+	WebhookFailed    = "failed"    // System error while handling
+	WebhookRejected  = "rejected"  // Failed signature or freshness checks
+)
+
+// Message kinds
+const (
+	MessageChannel = "channel" // Posted in a channel by a user
+	MessageDirect  = "direct"  // Direct message between two users
+	MessageCommand = "command" // Posted in a channel by a slash command
+)
+
+// Job outcomes
+const (
+	JobSucceeded = "succeeded" // Completed
+	JobRetried   = "retried"   // Failed, queued again with backoff
+	JobDead      = "dead"      // Failed permanently or on its last attempt
+)
+
+// Registry holds the backend metrics; it is separate from the global registry so tests and
+// libraries cannot register into the scraped set by accident
+var Registry = prometheus.NewRegistry()
+
+var (
+	// HTTPRequests counts handled requests by route template, so IDs do not create new series
+	HTTPRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "http",
+		Name:      "requests_total",
+		Help:      "HTTP requests by method, route template and status code.",
+	}, []string{"method", "route", "status"})
+
+	// HTTPRequestDuration observes request latency by route template
+	HTTPRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "http",
+		Name:      "request_duration_seconds",
+		Help:      "HTTP request latency by method and route template.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "route"})
+
+	// DBQueryDuration observes GORM statement durations by operation and table
+	DBQueryDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "db",
+		Name:      "query_duration_seconds",
+		Help:      "GORM query duration by operation and table.",
+		Buckets:   []float64{.0005, .001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5},
+	}, []string{"operation", "table"})
+
+	// WebhookEvents counts incoming webhooks by event type and outcome
+	WebhookEvents = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "webhook",
+		Name:      "events_total",
+		Help:      "Incoming webhooks by event type and processing outcome.",
+	}, []string{"event_type", "outcome"})
+
+	// MessagesSent counts stored messages by kind (channel, direct or command)
+	MessagesSent = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "messages_sent_total",
+		Help:      "Messages sent by kind.",
+	}, []string{"kind"})
+
+	// JobsProcessed counts background job attempts by kind and outcome
+	JobsProcessed = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "jobs",
+		Name:      "processed_total",
+		Help:      "Background job attempts by kind and outcome.",
+	}, []string{"kind", "outcome"})
+
+	// ChannelsCreated counts created channels
+	ChannelsCreated = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "channels_created_total",
+		Help:      "Channels created.",
+	})
+)
+
+func init() {
+	Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		HTTPRequests,
+		HTTPRequestDuration,
+		DBQueryDuration,
+		WebhookEvents,
+		MessagesSent,
+		JobsProcessed,
+		ChannelsCreated,
+	)
+}
+
+// Handler serves the registry in the Prometheus exposition format
+func Handler() http.Handler {
+	return promhttp.HandlerFor(Registry, promhttp.HandlerOpts{Registry: Registry})
+}
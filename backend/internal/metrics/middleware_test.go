@@ -0,0 +1,57 @@
+package metrics
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func newMetricsRouter(token string) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(Middleware())
+	router.GET("/channels/:id", func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+	router.GET("/metrics", RequireToken(token), gin.WrapH(Handler()))
+	return router
+}
+
+func get(router *gin.Engine, path, token string) *httptest.ResponseRecorder {
+	req, _ := http.NewRequest("GET", path, nil)
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestMiddleware_CountsByRouteTemplate(t *testing.T) {
+	router := newMetricsRouter("secret")
+	before := testutil.ToFloat64(HTTPRequests.WithLabelValues("GET", "/channels/:id", "200"))
+	unmatchedBefore := testutil.ToFloat64(HTTPRequests.WithLabelValues("GET", "unmatched", "404"))
+
+	get(router, "/channels/1", "")
+	get(router, "/channels/2", "")
+	get(router, "/missing", "")
+
+	assert.Equal(t, before+2, testutil.ToFloat64(HTTPRequests.WithLabelValues("GET", "/channels/:id", "200")))
+	assert.Equal(t, unmatchedBefore+1, testutil.ToFloat64(HTTPRequests.WithLabelValues("GET", "unmatched", "404")))
+}
+
+func TestRequireToken(t *testing.T) {
+	router := newMetricsRouter("secret")
+
+	assert.Equal(t, http.StatusUnauthorized, get(router, "/metrics", "").Code)
+	assert.Equal(t, http.StatusUnauthorized, get(router, "/metrics", "wrong").Code)
+
+	w := get(router, "/metrics", "secret")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.True(t, strings.Contains(w.Body.String(), "thothix_http_requests_total"))
+}
@@ -0,0 +1,39 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// RequireActiveUser middleware rejects requests from users that have been deactivated
+// Users are resolved by their Clerk ID, the subject of the session token, not by their local ID.
+// Users that are not synced to the local database yet are let through so they can call /auth/sync
+func RequireActiveUser(db *gorm.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		clerkID, exists := c.Get("clerk_user_id")
+		if !exists {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+			c.Abort()
+			return
+		}
+
+		var count int64
+		if err := db.Table("users").
+			Where("clerk_id = ? AND deactivated_at IS NOT NULL", clerkID.(string)).
+			Count(&count).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check user status"})
+			c.Abort()
+			return
+		}
+
+		if count > 0 {
+			c.JSON(http.StatusForbidden, gin.H{"error": "User account is deactivated"})
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
@@ -0,0 +1,71 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/gorm"
+	sharedModels "thothix-backend/internal/shared/models"
+	sharedTesting "thothix-backend/internal/shared/testing"
+	usersDomain "thothix-backend/internal/users/domain"
+)
+
+type ActiveUserTestSuite struct {
+	suite.Suite
+	container *sharedTesting.PostgresTestContainer
+}
+
+func (suite *ActiveUserTestSuite) SetupSuite() {
+	gin.SetMode(gin.TestMode)
+	suite.container = sharedTesting.GetSharedTestContainer(suite.T(), "middleware", []interface{}{&usersDomain.User{}})
+}
+
+// createUser stores a user synced from Clerk, with a local uuid distinct from the Clerk ID
+func (suite *ActiveUserTestSuite) createUser(db *gorm.DB, clerkID string, deactivated bool) {
+	user := &usersDomain.User{ClerkID: &clerkID, Email: clerkID + "@example.com", Name: clerkID, SystemRole: sharedModels.RoleUser}
+	user.ID = uuid.New().String()
+	if deactivated {
+		now := time.Now()
+		user.DeactivatedAt = &now
+	}
+	assert.NoError(suite.T(), db.Create(user).Error)
+}
+
+func (suite *ActiveUserTestSuite) request(db *gorm.DB, clerkID string) int {
+	router := gin.New()
+	router.Use(func(c *gin.Context) {
+		c.Set("clerk_user_id", clerkID)
+		c.Next()
+	})
+	router.GET("/me", RequireActiveUser(db), func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	req, _ := http.NewRequest("GET", "/me", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w.Code
+}
+
+func (suite *ActiveUserTestSuite) TestRequireActiveUser_ResolvesByClerkID() {
+	suite.container.WithTransaction(func(db *gorm.DB) {
+		// Arrange
+		suite.createUser(db, "user_2abcActiveQx8Zb1T0nM4pLk", false)
+		suite.createUser(db, "user_2abcDeactivatedR5vW9yHc3", true)
+
+		// Act & Assert
+		assert.Equal(suite.T(), http.StatusOK, suite.request(db, "user_2abcActiveQx8Zb1T0nM4pLk"))
+		assert.Equal(suite.T(), http.StatusForbidden, suite.request(db, "user_2abcDeactivatedR5vW9yHc3"))
+		assert.Equal(suite.T(), http.StatusOK, suite.request(db, "user_2abcNotSyncedYetJ7kP2sD"), "unsynced users can call /auth/sync")
+	})
+}
+
+func TestActiveUserTestSuite(t *testing.T) {
+	suite.Run(t, new(ActiveUserTestSuite))
+}
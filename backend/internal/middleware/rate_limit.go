@@ -0,0 +1,70 @@
+package middleware
+
+import (
+	"log/slog"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"thothix-backend/internal/ratelimit"
+	"thothix-backend/internal/shared/logging"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Rate limit response headers
+const (
+	RateLimitLimitHeader     = "X-RateLimit-Limit"
+	RateLimitRemainingHeader = "X-RateLimit-Remaining"
+	RateLimitResetHeader     = "X-RateLimit-Reset"
+)
+
+// RateLimit middleware throttles a route group with a token bucket per client
+// Clients are identified by user ID once authentication has verified it, else by IP, and each group has its
+// own buckets. Unverified credentials are never used, since a client could send a new one per request to
+// get a fresh bucket. Requests are let through if the store fails
+func RateLimit(store ratelimit.Store, group string, limit ratelimit.Limit) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !limit.Enabled() {
+			c.Next()
+			return
+		}
+
+		result, err := store.Take(c.Request.Context(), group+":"+rateLimitKey(c), limit)
+		if err != nil {
+			logging.FromContext(c.Request.Context()).Error("Rate limit store error", slog.String("group", group), slog.Any("error", err))
+			c.Next()
+			return
+		}
+
+		c.Header(RateLimitLimitHeader, strconv.Itoa(result.Limit))
+		c.Header(RateLimitRemainingHeader, strconv.Itoa(result.Remaining))
+		c.Header(RateLimitResetHeader, strconv.Itoa(ceilSeconds(result.ResetAfter)))
+
+		if !result.Allowed {
+			c.Header("Retry-After", strconv.Itoa(ceilSeconds(result.RetryAfter)))
+			c.JSON(http.StatusTooManyRequests, gin.H{"error": "Rate limit exceeded"})
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// rateLimitKey identifies the client of a request
+// The IP is the peer address unless the request came through one of the router's trusted proxies
+func rateLimitKey(c *gin.Context) string {
+	if userID, exists := c.Get("clerk_user_id"); exists {
+		if id, ok := userID.(string); ok && id != "" {
+			return "user:" + id
+		}
+	}
+	return "ip:" + c.ClientIP()
+}
+
+// ceilSeconds rounds a duration up to whole seconds, as used by Retry-After
+func ceilSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
@@ -0,0 +1,105 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"thothix-backend/internal/ratelimit"
+)
+
+func newRateLimitedRouter(limit ratelimit.Limit) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(func(c *gin.Context) {
+		if userID := c.GetHeader("X-Test-User"); userID != "" {
+			c.Set("clerk_user_id", userID)
+		}
+		c.Next()
+	})
+	router.POST("/messages", RateLimit(ratelimit.NewMemoryStore(), "messages", limit), func(c *gin.Context) {
+		c.Status(http.StatusCreated)
+	})
+	return router
+}
+
+func sendRateLimited(router *gin.Engine, userID string) *httptest.ResponseRecorder {
+	req, _ := http.NewRequest("POST", "/messages", nil)
+	if userID != "" {
+		req.Header.Set("X-Test-User", userID)
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestRateLimit_RejectsWithHeaders(t *testing.T) {
+	router := newRateLimitedRouter(ratelimit.Limit{Requests: 1, Period: time.Minute})
+
+	allowed := sendRateLimited(router, "user-1")
+	rejected := sendRateLimited(router, "user-1")
+
+	assert.Equal(t, http.StatusCreated, allowed.Code)
+	assert.Equal(t, "1", allowed.Header().Get(RateLimitLimitHeader))
+	assert.Equal(t, "0", allowed.Header().Get(RateLimitRemainingHeader))
+
+	assert.Equal(t, http.StatusTooManyRequests, rejected.Code)
+	assert.Equal(t, "60", rejected.Header().Get("Retry-After"))
+	assert.Equal(t, "60", rejected.Header().Get(RateLimitResetHeader))
+}
+
+func TestRateLimit_KeysByUserThenIP(t *testing.T) {
+	router := newRateLimitedRouter(ratelimit.Limit{Requests: 1, Period: time.Minute})
+
+	assert.Equal(t, http.StatusCreated, sendRateLimited(router, "user-1").Code)
+	assert.Equal(t, http.StatusCreated, sendRateLimited(router, "user-2").Code)
+	assert.Equal(t, http.StatusCreated, sendRateLimited(router, "").Code)
+	assert.Equal(t, http.StatusTooManyRequests, sendRateLimited(router, "").Code)
+}
+
+func TestRateLimit_UnverifiedTokensShareTheIPBucket(t *testing.T) {
+	router := newRateLimitedRouter(ratelimit.Limit{Requests: 1, Period: time.Minute})
+
+	for i, token := range []string{"first", "second"} {
+		req, _ := http.NewRequest("POST", "/messages", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		if i == 0 {
+			assert.Equal(t, http.StatusCreated, w.Code)
+		} else {
+			assert.Equal(t, http.StatusTooManyRequests, w.Code, "a new token must not get a fresh bucket")
+		}
+	}
+}
+
+func TestRateLimit_IgnoresForwardedForFromUntrustedPeers(t *testing.T) {
+	router := newRateLimitedRouter(ratelimit.Limit{Requests: 1, Period: time.Minute})
+	assert.NoError(t, router.SetTrustedProxies(nil))
+
+	for i, forwarded := range []string{"203.0.113.1", "203.0.113.2"} {
+		req, _ := http.NewRequest("POST", "/messages", nil)
+		req.RemoteAddr = "198.51.100.9:4000"
+		req.Header.Set("X-Forwarded-For", forwarded)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		if i == 0 {
+			assert.Equal(t, http.StatusCreated, w.Code)
+		} else {
+			assert.Equal(t, http.StatusTooManyRequests, w.Code, "a spoofed X-Forwarded-For must not get a fresh bucket")
+		}
+	}
+}
+
+func TestRateLimit_DisabledLimitPassesThrough(t *testing.T) {
+	router := newRateLimitedRouter(ratelimit.Limit{})
+
+	for i := 0; i < 5; i++ {
+		assert.Equal(t, http.StatusCreated, sendRateLimited(router, "user-1").Code)
+	}
+}
@@ -1,6 +1,7 @@
 package middleware
 
 import (
+	"errors"
 	"net/http"
 
 	sharedModels "thothix-backend/internal/shared/models"
@@ -28,7 +29,7 @@ func RequirePermission(db *gorm.DB, permission sharedModels.Permission, resource
 		}
 
 		// Check permission
-		if !sharedModels.HasUserPermission(db, userID.(string), permission, resourceType, resourceID) {
+		if !sharedModels.HasUserPermission(db.WithContext(c.Request.Context()), userID.(string), permission, resourceType, resourceID) {
 			c.JSON(http.StatusForbidden, gin.H{"error": "Insufficient permissions"})
 			c.Abort()
 			return
@@ -39,6 +40,7 @@ func RequirePermission(db *gorm.DB, permission sharedModels.Permission, resource
 }
 
 // RequireSystemRole middleware to check if user has specific system role
+// Within a workspace the user's role in that workspace is checked instead
 func RequireSystemRole(db *gorm.DB, role sharedModels.RoleType) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		userID, exists := c.Get("clerk_user_id")
@@ -49,7 +51,7 @@ func RequireSystemRole(db *gorm.DB, role sharedModels.RoleType) gin.HandlerFunc
 		}
 
 		// Get user's system role from database
-		userRole, err := sharedModels.GetUserRole(db, userID.(string))
+		userRole, err := sharedModels.GetUserRole(db.WithContext(c.Request.Context()), userID.(string))
 		if err != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get user role"})
 			c.Abort()
@@ -67,6 +69,34 @@ func RequireSystemRole(db *gorm.DB, role sharedModels.RoleType) gin.HandlerFunc
 	}
 }
 
+// RequirePlatformAdmin middleware to check that the user is a platform administrator
+// Unlike RequireSystemRole the workspace of the request is ignored, so workspace admins are refused
+func RequirePlatformAdmin(db *gorm.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, exists := c.Get("clerk_user_id")
+		if !exists {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+			c.Abort()
+			return
+		}
+
+		systemRole, err := sharedModels.GetSystemRole(db.WithContext(c.Request.Context()), userID.(string))
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get user role"})
+			c.Abort()
+			return
+		}
+
+		if err != nil || systemRole != sharedModels.RoleAdmin {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Platform administrator required"})
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
 // RequireProjectAccess middleware to check if user can access a project
 func RequireProjectAccess(db *gorm.DB) gin.HandlerFunc {
 	return func(c *gin.Context) {
@@ -86,7 +116,7 @@ func RequireProjectAccess(db *gorm.DB) gin.HandlerFunc {
 
 		// Check if user has access to the project
 		resourceType := "project"
-		if !sharedModels.HasUserPermission(db, userID.(string), sharedModels.PermissionProjectRead, &resourceType, &projectID) {
+		if !sharedModels.HasUserPermission(db.WithContext(c.Request.Context()), userID.(string), sharedModels.PermissionProjectRead, &resourceType, &projectID) {
 			c.JSON(http.StatusForbidden, gin.H{"error": "Access denied to project"})
 			c.Abort()
 			return
@@ -115,7 +145,7 @@ func RequireChannelAccess(db *gorm.DB) gin.HandlerFunc {
 
 		// Check if user has access to the channel
 		resourceType := "channel"
-		if !sharedModels.HasUserPermission(db, userID.(string), sharedModels.PermissionChannelRead, &resourceType, &channelID) {
+		if !sharedModels.HasUserPermission(db.WithContext(c.Request.Context()), userID.(string), sharedModels.PermissionChannelRead, &resourceType, &channelID) {
 			c.JSON(http.StatusForbidden, gin.H{"error": "Access denied to channel"})
 			c.Abort()
 			return
@@ -0,0 +1,82 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/gorm"
+	sharedMiddleware "thothix-backend/internal/shared/middleware"
+	sharedModels "thothix-backend/internal/shared/models"
+	sharedTesting "thothix-backend/internal/shared/testing"
+	usersDomain "thothix-backend/internal/users/domain"
+)
+
+type RBACTestSuite struct {
+	suite.Suite
+	container *sharedTesting.PostgresTestContainer
+}
+
+func (suite *RBACTestSuite) SetupSuite() {
+	gin.SetMode(gin.TestMode)
+	suite.container = sharedTesting.GetSharedTestContainer(suite.T(), "middleware/rbac", []interface{}{&usersDomain.User{}, &sharedModels.UserRole{}})
+}
+
+// createUser stores a user with the given system role and, when workspaceID is set, an admin membership of that workspace
+func (suite *RBACTestSuite) createUser(db *gorm.DB, name string, systemRole sharedModels.RoleType, workspaceID string) string {
+	user := &usersDomain.User{Email: name + "@example.com", Name: name, SystemRole: systemRole}
+	user.ID = uuid.New().String()
+	assert.NoError(suite.T(), db.Create(user).Error)
+
+	if workspaceID != "" {
+		resourceType := sharedModels.ResourceTypeWorkspace
+		assert.NoError(suite.T(), db.Create(&sharedModels.UserRole{
+			UserID:       user.ID,
+			Role:         sharedModels.RoleAdmin,
+			ResourceType: &resourceType,
+			ResourceID:   &workspaceID,
+		}).Error)
+	}
+	return user.ID
+}
+
+// request calls a route guarded by guard as userID, within workspaceID
+func (suite *RBACTestSuite) request(guard gin.HandlerFunc, userID, workspaceID string) int {
+	router := gin.New()
+	router.Use(func(c *gin.Context) {
+		c.Set("clerk_user_id", userID)
+		c.Request = c.Request.WithContext(sharedMiddleware.WithWorkspaceID(c.Request.Context(), workspaceID))
+		c.Next()
+	})
+	router.GET("/admin", guard, func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	req, _ := http.NewRequest("GET", "/admin", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w.Code
+}
+
+func (suite *RBACTestSuite) TestRequirePlatformAdmin_IgnoresWorkspaceRole() {
+	suite.container.WithTransaction(func(db *gorm.DB) {
+		// Arrange
+		workspaceID := uuid.New().String()
+		workspaceAdmin := suite.createUser(db, "workspace-admin", sharedModels.RoleUser, workspaceID)
+		platformAdmin := suite.createUser(db, "platform-admin", sharedModels.RoleAdmin, "")
+
+		// Act & Assert
+		assert.Equal(suite.T(), http.StatusOK, suite.request(RequireSystemRole(db, sharedModels.RoleAdmin), workspaceAdmin, workspaceID))
+		assert.Equal(suite.T(), http.StatusForbidden, suite.request(RequirePlatformAdmin(db), workspaceAdmin, workspaceID))
+		assert.Equal(suite.T(), http.StatusOK, suite.request(RequirePlatformAdmin(db), platformAdmin, workspaceID))
+		assert.Equal(suite.T(), http.StatusForbidden, suite.request(RequirePlatformAdmin(db), uuid.New().String(), ""))
+	})
+}
+
+func TestRBACTestSuite(t *testing.T) {
+	suite.Run(t, new(RBACTestSuite))
+}
@@ -0,0 +1,68 @@
+package middleware
+
+import (
+	"net/http"
+
+	sharedMiddleware "thothix-backend/internal/shared/middleware"
+	sharedModels "thothix-backend/internal/shared/models"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// ResolveWorkspace middleware sets the current workspace in the request context
+// The workspace comes from the X-Workspace-ID header, or else from the active Clerk organization.
+// The user must be a member of it. Requests naming no workspace continue unscoped, see RequireWorkspace
+func ResolveWorkspace(db *gorm.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, exists := c.Get("clerk_user_id")
+		if !exists {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+			c.Abort()
+			return
+		}
+
+		query := db.WithContext(c.Request.Context()).Table("workspaces").Select("id")
+		if header := c.GetHeader(sharedMiddleware.WorkspaceHeader); header != "" {
+			query = query.Where("id::text = ?", header)
+		} else if orgID, ok := c.Get("clerk_org_id"); ok {
+			query = query.Where("clerk_org_id = ?", orgID.(string))
+		} else {
+			c.Next()
+			return
+		}
+
+		var workspace struct {
+			ID string
+		}
+		if err := query.Take(&workspace).Error; err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Workspace not found"})
+			c.Abort()
+			return
+		}
+
+		if _, err := sharedModels.GetWorkspaceRole(db.WithContext(c.Request.Context()), userID.(string), workspace.ID); err != nil {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Not a member of this workspace"})
+			c.Abort()
+			return
+		}
+
+		c.Set("workspace_id", workspace.ID)
+		c.Request = c.Request.WithContext(sharedMiddleware.WithWorkspaceID(c.Request.Context(), workspace.ID))
+		c.Next()
+	}
+}
+
+// RequireWorkspace middleware rejects requests without a current workspace
+// Use it after ResolveWorkspace on routes whose data belongs to a workspace
+func RequireWorkspace() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if _, ok := sharedMiddleware.WorkspaceIDFromContext(c.Request.Context()); !ok {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Workspace required: send the " + sharedMiddleware.WorkspaceHeader + " header or select a Clerk organization"})
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
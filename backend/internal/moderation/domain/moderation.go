@@ -0,0 +1,213 @@
+package domain
+
+import (
+	"strings"
+	"time"
+	"unicode"
+
+	commonModels "thothix-backend/internal/common/models"
+	"thothix-backend/internal/encryption"
+)
+
+// Reason is why a message was reported
+type Reason string
+
+const (
+	ReasonSpam          Reason = "spam"
+	ReasonHarassment    Reason = "harassment"
+	ReasonHate          Reason = "hate"
+	ReasonInappropriate Reason = "inappropriate"
+	ReasonOther         Reason = "other"
+	ReasonBlockedWords  Reason = "blocked_words" // Raised by a blocked-word list in flag mode, never by a user
+)
+
+// Status is the state of a report in the moderation queue
+type Status string
+
+const (
+	StatusOpen            Status = "open"
+	StatusDismissed       Status = "dismissed"
+	StatusMessageDeleted  Status = "message_deleted"
+	StatusAuthorSuspended Status = "author_suspended"
+)
+
+// Report is a message reported by a user, or flagged by a blocked-word list, waiting for a moderator
+// The report keeps an excerpt of the message, so the evidence remains once the message is deleted
+type Report struct {
+	commonModels.BaseModel
+	WorkspaceID    string     `json:"workspace_id" gorm:"type:uuid;not null;index"`
+	MessageID      string     `json:"message_id" gorm:"not null;index"`  // No foreign key: reports outlive the messages they are about
+	ChannelID      *string    `json:"channel_id,omitempty"`              // Nil for direct messages
+	ProjectID      *string    `json:"project_id,omitempty" gorm:"index"` // Project of the channel; its moderators see the report
+	AuthorID       string     `json:"author_id" gorm:"not null;index"`
+	ReporterID     *string    `json:"reporter_id,omitempty" gorm:"index"` // Nil for reports raised by a blocked-word list
+	Reason         Reason     `json:"reason" gorm:"not null"`
+	Details        string     `json:"details" gorm:"serializer:encrypted"`
+	Excerpt        string     `json:"excerpt" gorm:"serializer:encrypted"` // Text of the message when it was reported
+	Status         Status     `json:"status" gorm:"not null;default:'open';index"`
+	ResolutionNote string     `json:"resolution_note"`
+	ResolvedBy     *string    `json:"resolved_by,omitempty"`
+	ResolvedAt     *time.Time `json:"resolved_at,omitempty"`
+}
+
+func (Report) TableName() string {
+	return "moderation_reports"
+}
+
+// EncryptedColumns lists the report columns the re-encryptor moves to the current key
+func EncryptedColumns() []encryption.Column {
+	return []encryption.Column{
+		{Table: Report{}.TableName(), Column: "details"},
+		{Table: Report{}.TableName(), Column: "excerpt"},
+	}
+}
+
+// IsOpen reports whether the report still waits for a moderator
+func (r *Report) IsOpen() bool {
+	return r.Status == StatusOpen
+}
+
+// Moderator lets a user who is neither an admin nor a manager moderate the reports of one project
+type Moderator struct {
+	commonModels.BaseModel
+	WorkspaceID string `json:"workspace_id" gorm:"type:uuid;not null;index"`
+	ProjectID   string `json:"project_id" gorm:"not null;uniqueIndex:idx_moderators_project_user"`
+	UserID      string `json:"user_id" gorm:"not null;uniqueIndex:idx_moderators_project_user"`
+}
+
+func (Moderator) TableName() string {
+	return "moderators"
+}
+
+// Suspension keeps a user from posting in a workspace, or in the channels of one project when it was
+// decided by a project moderator. It ends when it expires or is lifted; lifted suspensions are kept as a record
+type Suspension struct {
+	commonModels.BaseModel
+	WorkspaceID string     `json:"workspace_id" gorm:"type:uuid;not null;index"`
+	UserID      string     `json:"user_id" gorm:"not null;index"`
+	ProjectID   *string    `json:"project_id,omitempty"` // Nil suspends the user in the whole workspace
+	ReportID    *string    `json:"report_id,omitempty"`
+	Reason      string     `json:"reason"`
+	ExpiresAt   *time.Time `json:"expires_at,omitempty"` // Nil suspends the user until the suspension is lifted
+	LiftedAt    *time.Time `json:"lifted_at,omitempty"`
+	LiftedBy    *string    `json:"lifted_by,omitempty"`
+}
+
+func (Suspension) TableName() string {
+	return "moderation_suspensions"
+}
+
+// IsActive reports whether the suspension still applies at now
+func (s *Suspension) IsActive(now time.Time) bool {
+	return s.LiftedAt == nil && (s.ExpiresAt == nil || s.ExpiresAt.After(now))
+}
+
+// ActionType is a decision recorded in the moderation log
+type ActionType string
+
+const (
+	ActionDismiss        ActionType = "dismiss"
+	ActionDeleteMessage  ActionType = "delete_message"
+	ActionSuspendAuthor  ActionType = "suspend_author"
+	ActionLiftSuspension ActionType = "lift_suspension"
+)
+
+// Action is an entry of the moderation log, written for every decision a moderator takes
+type Action struct {
+	commonModels.BaseModel
+	WorkspaceID  string     `json:"workspace_id" gorm:"type:uuid;not null;index"`
+	ModeratorID  string     `json:"moderator_id" gorm:"not null;index"`
+	Type         ActionType `json:"type" gorm:"not null"`
+	ProjectID    *string    `json:"project_id,omitempty" gorm:"index"` // Scope of the decision; nil for the whole workspace
+	ReportID     *string    `json:"report_id,omitempty"`
+	MessageID    *string    `json:"message_id,omitempty"`
+	TargetUserID *string    `json:"target_user_id,omitempty"`
+	Note         string     `json:"note"`
+}
+
+func (Action) TableName() string {
+	return "moderation_actions"
+}
+
+// FilterMode is what happens to a message containing a blocked word
+type FilterMode string
+
+const (
+	FilterReject FilterMode = "reject" // The message is refused
+	FilterFlag   FilterMode = "flag"   // The message is sent and reported to the moderators
+)
+
+const (
+	// MaxBlockedWords and MaxBlockedWordLength bound a blocked-word list
+	MaxBlockedWords      = 500
+	MaxBlockedWordLength = 100
+)
+
+// BlockedWordList holds the words and phrases that cannot be posted in the channels of a project
+type BlockedWordList struct {
+	commonModels.BaseModel
+	WorkspaceID string     `json:"workspace_id" gorm:"type:uuid;not null;index"`
+	ProjectID   string     `json:"project_id" gorm:"not null;uniqueIndex"`
+	Mode        FilterMode `json:"mode" gorm:"not null"`
+	Words       []string   `json:"words" gorm:"serializer:json;type:text"` // Normalized by NormalizeWords
+}
+
+func (BlockedWordList) TableName() string {
+	return "blocked_word_lists"
+}
+
+// Match returns the entries of the list found in text. Matching ignores case and punctuation and is done
+// on whole words, so an entry does not match inside a longer word; entries of several words match those
+// words in sequence
+func (l *BlockedWordList) Match(text string) []string {
+	tokens := tokenize(text)
+	var matched []string
+	for _, word := range l.Words {
+		if containsSequence(tokens, strings.Fields(word)) {
+			matched = append(matched, word)
+		}
+	}
+	return matched
+}
+
+// NormalizeWords lowercases the entries of a list, reduces each to its words separated by single spaces,
+// and drops empty and duplicate entries
+func NormalizeWords(words []string) []string {
+	normalized := make([]string, 0, len(words))
+	seen := make(map[string]bool, len(words))
+	for _, word := range words {
+		entry := strings.Join(tokenize(word), " ")
+		if entry == "" || seen[entry] {
+			continue
+		}
+		seen[entry] = true
+		normalized = append(normalized, entry)
+	}
+	return normalized
+}
+
+// tokenize splits text into lowercase words of letters and digits
+func tokenize(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+}
+
+func containsSequence(tokens, sequence []string) bool {
+	if len(sequence) == 0 {
+		return false
+	}
+	for i := 0; i+len(sequence) <= len(tokens); i++ {
+		found := true
+		for j, word := range sequence {
+			if tokens[i+j] != word {
+				found = false
+				break
+			}
+		}
+		if found {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,46 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBlockedWordList_Match(t *testing.T) {
+	list := &BlockedWordList{Words: NormalizeWords([]string{"Scam", "free  money!", "crème"})}
+
+	tests := []struct {
+		name string
+		text string
+		want []string
+	}{
+		{"whole word ignoring case", "This is a SCAM.", []string{"scam"}},
+		{"not inside a longer word", "scampi for dinner", nil},
+		{"phrase across punctuation", "get free, money now", []string{"free money"}},
+		{"phrase words out of order", "money for free", nil},
+		{"accented letters", "la Crème brûlée", []string{"crème"}},
+		{"several entries", "free money, no scam", []string{"scam", "free money"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, list.Match(tt.text))
+		})
+	}
+}
+
+func TestNormalizeWords(t *testing.T) {
+	words := NormalizeWords([]string{" Spam ", "spam", "Free\tMoney", "!!!", ""})
+
+	assert.Equal(t, []string{"spam", "free money"}, words)
+}
+
+func TestSuspension_IsActive(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	later, earlier := now.Add(time.Hour), now.Add(-time.Hour)
+
+	assert.True(t, (&Suspension{}).IsActive(now), "until lifted")
+	assert.True(t, (&Suspension{ExpiresAt: &later}).IsActive(now))
+	assert.False(t, (&Suspension{ExpiresAt: &earlier}).IsActive(now), "expired")
+	assert.False(t, (&Suspension{LiftedAt: &earlier}).IsActive(now), "lifted")
+}
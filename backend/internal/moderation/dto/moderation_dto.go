@@ -0,0 +1,225 @@
+package dto
+
+import (
+	"thothix-backend/internal/shared/dto"
+)
+
+// === Report DTOs ===
+
+// ReportDto represents a reported message in API responses
+type ReportDto struct {
+	ID             string  `json:"id"`
+	MessageID      string  `json:"message_id"`
+	ChannelID      *string `json:"channel_id,omitempty"`
+	ProjectID      *string `json:"project_id,omitempty"`
+	AuthorID       string  `json:"author_id"`
+	ReporterID     *string `json:"reporter_id,omitempty"` // Missing for reports raised by a blocked-word list
+	Reason         string  `json:"reason"`
+	Details        string  `json:"details,omitempty"`
+	Excerpt        string  `json:"excerpt"`
+	Status         string  `json:"status"`
+	ResolutionNote string  `json:"resolution_note,omitempty"`
+	ResolvedBy     *string `json:"resolved_by,omitempty"`
+	ResolvedAt     string  `json:"resolved_at,omitempty"`
+	CreatedAt      string  `json:"created_at"`
+}
+
+// ReportMessageRequest reports a message the user can read to the moderators
+type ReportMessageRequest struct {
+	MessageID string `json:"message_id" binding:"required"`
+	Reason    string `json:"reason" binding:"required,oneof=spam harassment hate inappropriate other"`
+	Details   string `json:"details" binding:"max=1000"`
+}
+
+// ReportListRequest filters and paginates the moderation queue
+type ReportListRequest struct {
+	dto.PaginationRequest
+	Status string `form:"status" binding:"omitempty,oneof=open dismissed message_deleted author_suspended"` // Defaults to open
+}
+
+// ReportListDto is a page of reports
+type ReportListDto = dto.PaginatedListResponse[ReportDto]
+
+// ResolveReportRequest decides an open report
+// Suspensions last duration_hours, or until they are lifted when it is missing
+type ResolveReportRequest struct {
+	Action        string `json:"action" binding:"required,oneof=dismiss delete_message suspend_author"`
+	Note          string `json:"note" binding:"max=500"`
+	DurationHours *int   `json:"duration_hours" binding:"omitempty,min=1,max=8760"`
+}
+
+// ReportResponse wraps a single ReportDto response
+type ReportResponse struct {
+	*dto.Response[*ReportDto]
+}
+
+func NewReportResponse(producer func() dto.Validation[*ReportDto]) *ReportResponse {
+	return &ReportResponse{
+		Response: dto.NewResponse(producer),
+	}
+}
+
+// ReportListResponse wraps a page of reports
+type ReportListResponse struct {
+	*dto.Response[*ReportListDto]
+}
+
+func NewReportListResponse(producer func() dto.Validation[*ReportListDto]) *ReportListResponse {
+	return &ReportListResponse{
+		Response: dto.NewResponse(producer),
+	}
+}
+
+// === Moderation log DTOs ===
+
+// ActionDto represents an entry of the moderation log in API responses
+type ActionDto struct {
+	ID           string  `json:"id"`
+	ModeratorID  string  `json:"moderator_id"`
+	Type         string  `json:"type"`
+	ProjectID    *string `json:"project_id,omitempty"`
+	ReportID     *string `json:"report_id,omitempty"`
+	MessageID    *string `json:"message_id,omitempty"`
+	TargetUserID *string `json:"target_user_id,omitempty"`
+	Note         string  `json:"note,omitempty"`
+	CreatedAt    string  `json:"created_at"`
+}
+
+// ActionListRequest paginates the moderation log
+type ActionListRequest struct {
+	dto.PaginationRequest
+}
+
+// ActionListDto is a page of the moderation log
+type ActionListDto = dto.PaginatedListResponse[ActionDto]
+
+// ActionListResponse wraps a page of the moderation log
+type ActionListResponse struct {
+	*dto.Response[*ActionListDto]
+}
+
+func NewActionListResponse(producer func() dto.Validation[*ActionListDto]) *ActionListResponse {
+	return &ActionListResponse{
+		Response: dto.NewResponse(producer),
+	}
+}
+
+// === Suspension DTOs ===
+
+// SuspensionDto represents a suspension in API responses
+type SuspensionDto struct {
+	ID        string  `json:"id"`
+	UserID    string  `json:"user_id"`
+	ProjectID *string `json:"project_id,omitempty"` // Missing for suspensions covering the whole workspace
+	ReportID  *string `json:"report_id,omitempty"`
+	Reason    string  `json:"reason,omitempty"`
+	Active    bool    `json:"active"`
+	CreatedBy string  `json:"created_by,omitempty"`
+	CreatedAt string  `json:"created_at"`
+	ExpiresAt string  `json:"expires_at,omitempty"`
+	LiftedBy  *string `json:"lifted_by,omitempty"`
+	LiftedAt  string  `json:"lifted_at,omitempty"`
+}
+
+// SuspensionResponse wraps a single SuspensionDto response
+type SuspensionResponse struct {
+	*dto.Response[*SuspensionDto]
+}
+
+func NewSuspensionResponse(producer func() dto.Validation[*SuspensionDto]) *SuspensionResponse {
+	return &SuspensionResponse{
+		Response: dto.NewResponse(producer),
+	}
+}
+
+// SuspensionListResponse wraps a list of SuspensionDto
+type SuspensionListResponse struct {
+	*dto.Response[[]SuspensionDto]
+}
+
+func NewSuspensionListResponse(producer func() dto.Validation[[]SuspensionDto]) *SuspensionListResponse {
+	return &SuspensionListResponse{
+		Response: dto.NewResponse(producer),
+	}
+}
+
+// === Moderator DTOs ===
+
+// ModeratorDto represents a project moderator in API responses
+type ModeratorDto struct {
+	ID        string `json:"id"`
+	ProjectID string `json:"project_id"`
+	UserID    string `json:"user_id"`
+	CreatedBy string `json:"created_by,omitempty"`
+	CreatedAt string `json:"created_at"`
+}
+
+// AddModeratorRequest lets a workspace member moderate the reports of a project
+type AddModeratorRequest struct {
+	ProjectID string `json:"project_id" binding:"required"`
+	UserID    string `json:"user_id" binding:"required"`
+}
+
+// ModeratorResponse wraps a single ModeratorDto response
+type ModeratorResponse struct {
+	*dto.Response[*ModeratorDto]
+}
+
+func NewModeratorResponse(producer func() dto.Validation[*ModeratorDto]) *ModeratorResponse {
+	return &ModeratorResponse{
+		Response: dto.NewResponse(producer),
+	}
+}
+
+// ModeratorListResponse wraps a list of ModeratorDto
+type ModeratorListResponse struct {
+	*dto.Response[[]ModeratorDto]
+}
+
+func NewModeratorListResponse(producer func() dto.Validation[[]ModeratorDto]) *ModeratorListResponse {
+	return &ModeratorListResponse{
+		Response: dto.NewResponse(producer),
+	}
+}
+
+// === Blocked-word list DTOs ===
+
+// BlockedWordsDto represents the blocked-word list of a project in API responses
+type BlockedWordsDto struct {
+	ID        string   `json:"id"`
+	ProjectID string   `json:"project_id"`
+	Mode      string   `json:"mode"`
+	Words     []string `json:"words"`
+	UpdatedBy string   `json:"updated_by,omitempty"`
+	UpdatedAt string   `json:"updated_at"`
+}
+
+// SetBlockedWordsRequest creates or replaces the blocked-word list of a project
+// Words are matched as whole words ignoring case; an entry of several words matches them in sequence
+type SetBlockedWordsRequest struct {
+	ProjectID string   `json:"project_id" binding:"required"`
+	Mode      string   `json:"mode" binding:"required,oneof=reject flag"`
+	Words     []string `json:"words" binding:"required,min=1"`
+}
+
+// BlockedWordsResponse wraps a single BlockedWordsDto response
+type BlockedWordsResponse struct {
+	*dto.Response[*BlockedWordsDto]
+}
+
+func NewBlockedWordsResponse(producer func() dto.Validation[*BlockedWordsDto]) *BlockedWordsResponse {
+	return &BlockedWordsResponse{
+		Response: dto.NewResponse(producer),
+	}
+}
+
+// BlockedWordsListResponse wraps a list of BlockedWordsDto
+type BlockedWordsListResponse struct {
+	*dto.Response[[]BlockedWordsDto]
+}
+
+func NewBlockedWordsListResponse(producer func() dto.Validation[[]BlockedWordsDto]) *BlockedWordsListResponse {
+	return &BlockedWordsListResponse{
+		Response: dto.NewResponse(producer),
+	}
+}
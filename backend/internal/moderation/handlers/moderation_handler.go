@@ -0,0 +1,565 @@
+package handlers
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	moderationDto "thothix-backend/internal/moderation/dto"
+	"thothix-backend/internal/moderation/service"
+	"thothix-backend/internal/shared/constants"
+	"thothix-backend/internal/shared/dto"
+	"thothix-backend/internal/shared/handlers"
+)
+
+type ModerationHandler struct {
+	moderationService service.ModerationServiceInterface
+}
+
+func NewModerationHandler(moderationService service.ModerationServiceInterface) *ModerationHandler {
+	return &ModerationHandler{
+		moderationService: moderationService,
+	}
+}
+
+// ReportMessage godoc
+// @Summary Report a message
+// @Description Report a message the authenticated user can read to the moderators of the workspace. A message can be reported once per user while the report is open
+// @Tags moderation
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param report body moderationDto.ReportMessageRequest true "Report"
+// @Success 201 {object} moderationDto.ReportDto
+// @Failure 400 {object} dto.ErrorViewModel
+// @Failure 404 {object} dto.ErrorViewModel
+// @Failure 409 {object} dto.ErrorViewModel
+// @Failure 500 {object} dto.ErrorViewModel
+// @Router /moderation/reports [post]
+func (h *ModerationHandler) ReportMessage(c *gin.Context) {
+	wrapper := handlers.WrapContext(c)
+
+	var request moderationDto.ReportMessageRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		wrapper.BadRequestErrorResponse("Invalid request payload")
+		return
+	}
+
+	response := h.scopedService(c).ReportMessage(&request)
+
+	response.Match(
+		// Exception case
+		func(err error) interface{} {
+			wrapper.SystemErrorResponse(err, "Failed to report message: %s", request.MessageID)
+			return nil
+		},
+		// Success case
+		func(result *moderationDto.ReportDto) interface{} {
+			wrapper.CreatedResponse(result)
+			return nil
+		},
+		// Validation failure case
+		func(errors []dto.Error) interface{} {
+			respondWithFailure(wrapper, errors, "Message", request.MessageID, "Message report validation failed")
+			return nil
+		},
+	)
+}
+
+// ListReports godoc
+// @Summary List the moderation queue
+// @Description List the reports of the current workspace, oldest first. Admins and managers see every report, project moderators those of their projects
+// @Tags moderation
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param status query string false "open (default), dismissed, message_deleted or author_suspended"
+// @Param page query int false "Page number (default: 1)"
+// @Param per_page query int false "Items per page (default: 20, max: 100)"
+// @Success 200 {object} moderationDto.ReportListDto
+// @Failure 400 {object} dto.ErrorViewModel
+// @Failure 403 {object} dto.ErrorViewModel
+// @Failure 500 {object} dto.ErrorViewModel
+// @Router /moderation/reports [get]
+func (h *ModerationHandler) ListReports(c *gin.Context) {
+	wrapper := handlers.WrapContext(c)
+
+	var request moderationDto.ReportListRequest
+	if err := c.ShouldBindQuery(&request); err != nil {
+		wrapper.BadRequestErrorResponse("Invalid query parameters")
+		return
+	}
+
+	// Set defaults
+	if request.Page == 0 {
+		request.Page = 1
+	}
+	if request.PerPage == 0 {
+		request.PerPage = 20
+	}
+
+	response := h.scopedService(c).ListReports(&request)
+
+	response.Match(
+		// Exception case
+		func(err error) interface{} {
+			wrapper.SystemErrorResponse(err, "Failed to retrieve reports")
+			return nil
+		},
+		// Success case
+		func(result *moderationDto.ReportListDto) interface{} {
+			wrapper.SuccessResponse(result)
+			return nil
+		},
+		// Validation failure case
+		func(errors []dto.Error) interface{} {
+			respondWithFailure(wrapper, errors, "Reports", "", "Report list validation failed")
+			return nil
+		},
+	)
+}
+
+// ResolveReport godoc
+// @Summary Resolve a report
+// @Description Dismiss an open report, delete the reported message, or suspend its author from posting. Project moderators suspend in the channels of the project, admins and managers in the whole workspace; a suspension lasts duration_hours, or until it is lifted. The decision is written to the moderation log
+// @Tags moderation
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Report ID"
+// @Param decision body moderationDto.ResolveReportRequest true "Decision"
+// @Success 200 {object} moderationDto.ReportDto
+// @Failure 400 {object} dto.ErrorViewModel
+// @Failure 403 {object} dto.ErrorViewModel
+// @Failure 404 {object} dto.ErrorViewModel
+// @Failure 409 {object} dto.ErrorViewModel
+// @Failure 500 {object} dto.ErrorViewModel
+// @Router /moderation/reports/{id}/resolve [post]
+func (h *ModerationHandler) ResolveReport(c *gin.Context) {
+	wrapper := handlers.WrapContext(c)
+	reportID := c.Param("id")
+
+	var request moderationDto.ResolveReportRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		wrapper.BadRequestErrorResponse("Invalid request payload")
+		return
+	}
+
+	response := h.scopedService(c).ResolveReport(reportID, &request)
+
+	response.Match(
+		// Exception case
+		func(err error) interface{} {
+			wrapper.SystemErrorResponse(err, "Failed to resolve report: %s", reportID)
+			return nil
+		},
+		// Success case
+		func(result *moderationDto.ReportDto) interface{} {
+			wrapper.SuccessResponse(result)
+			return nil
+		},
+		// Validation failure case
+		func(errors []dto.Error) interface{} {
+			respondWithFailure(wrapper, errors, "Report", reportID, "Report resolution validation failed")
+			return nil
+		},
+	)
+}
+
+// ListActions godoc
+// @Summary List the moderation log
+// @Description List the decisions taken by moderators, most recent first. Project moderators see the decisions about their projects
+// @Tags moderation
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param page query int false "Page number (default: 1)"
+// @Param per_page query int false "Items per page (default: 20, max: 100)"
+// @Success 200 {object} moderationDto.ActionListDto
+// @Failure 400 {object} dto.ErrorViewModel
+// @Failure 403 {object} dto.ErrorViewModel
+// @Failure 500 {object} dto.ErrorViewModel
+// @Router /moderation/actions [get]
+func (h *ModerationHandler) ListActions(c *gin.Context) {
+	wrapper := handlers.WrapContext(c)
+
+	var request moderationDto.ActionListRequest
+	if err := c.ShouldBindQuery(&request); err != nil {
+		wrapper.BadRequestErrorResponse("Invalid query parameters")
+		return
+	}
+
+	// Set defaults
+	if request.Page == 0 {
+		request.Page = 1
+	}
+	if request.PerPage == 0 {
+		request.PerPage = 20
+	}
+
+	response := h.scopedService(c).ListActions(&request)
+
+	response.Match(
+		// Exception case
+		func(err error) interface{} {
+			wrapper.SystemErrorResponse(err, "Failed to retrieve moderation log")
+			return nil
+		},
+		// Success case
+		func(result *moderationDto.ActionListDto) interface{} {
+			wrapper.SuccessResponse(result)
+			return nil
+		},
+		// Validation failure case
+		func(errors []dto.Error) interface{} {
+			respondWithFailure(wrapper, errors, "Moderation log", "", "Moderation log validation failed")
+			return nil
+		},
+	)
+}
+
+// ListSuspensions godoc
+// @Summary List suspensions
+// @Description List the active suspensions, most recent first. Project moderators see the suspensions of their projects
+// @Tags moderation
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param include_inactive query bool false "Include lifted and expired suspensions"
+// @Success 200 {array} moderationDto.SuspensionDto
+// @Failure 400 {object} dto.ErrorViewModel
+// @Failure 403 {object} dto.ErrorViewModel
+// @Failure 500 {object} dto.ErrorViewModel
+// @Router /moderation/suspensions [get]
+func (h *ModerationHandler) ListSuspensions(c *gin.Context) {
+	wrapper := handlers.WrapContext(c)
+
+	includeInactive := false
+	if value := c.Query("include_inactive"); value != "" {
+		parsed, err := strconv.ParseBool(value)
+		if err != nil {
+			wrapper.BadRequestErrorResponse("Invalid query parameters")
+			return
+		}
+		includeInactive = parsed
+	}
+
+	response := h.scopedService(c).ListSuspensions(includeInactive)
+
+	response.Match(
+		// Exception case
+		func(err error) interface{} {
+			wrapper.SystemErrorResponse(err, "Failed to retrieve suspensions")
+			return nil
+		},
+		// Success case
+		func(result []moderationDto.SuspensionDto) interface{} {
+			wrapper.SuccessResponse(result)
+			return nil
+		},
+		// Validation failure case
+		func(errors []dto.Error) interface{} {
+			respondWithFailure(wrapper, errors, "Suspensions", "", "Suspension list validation failed")
+			return nil
+		},
+	)
+}
+
+// LiftSuspension godoc
+// @Summary Lift a suspension
+// @Description End a suspension before it expires, so the user can post again. The decision is written to the moderation log
+// @Tags moderation
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Suspension ID"
+// @Success 200 {object} moderationDto.SuspensionDto
+// @Failure 403 {object} dto.ErrorViewModel
+// @Failure 404 {object} dto.ErrorViewModel
+// @Failure 409 {object} dto.ErrorViewModel
+// @Failure 500 {object} dto.ErrorViewModel
+// @Router /moderation/suspensions/{id}/lift [post]
+func (h *ModerationHandler) LiftSuspension(c *gin.Context) {
+	wrapper := handlers.WrapContext(c)
+	suspensionID := c.Param("id")
+
+	response := h.scopedService(c).LiftSuspension(suspensionID)
+
+	response.Match(
+		// Exception case
+		func(err error) interface{} {
+			wrapper.SystemErrorResponse(err, "Failed to lift suspension: %s", suspensionID)
+			return nil
+		},
+		// Success case
+		func(result *moderationDto.SuspensionDto) interface{} {
+			wrapper.SuccessResponse(result)
+			return nil
+		},
+		// Validation failure case
+		func(errors []dto.Error) interface{} {
+			respondWithFailure(wrapper, errors, "Suspension", suspensionID, "Suspension lift validation failed")
+			return nil
+		},
+	)
+}
+
+// ListModerators godoc
+// @Summary List project moderators
+// @Description List the members who moderate the reports of a project of the current workspace (admins and managers)
+// @Tags moderation
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} moderationDto.ModeratorDto
+// @Failure 403 {object} dto.ErrorViewModel
+// @Failure 500 {object} dto.ErrorViewModel
+// @Router /moderation/moderators [get]
+func (h *ModerationHandler) ListModerators(c *gin.Context) {
+	wrapper := handlers.WrapContext(c)
+
+	response := h.scopedService(c).ListModerators()
+
+	response.Match(
+		// Exception case
+		func(err error) interface{} {
+			wrapper.SystemErrorResponse(err, "Failed to retrieve moderators")
+			return nil
+		},
+		// Success case
+		func(result []moderationDto.ModeratorDto) interface{} {
+			wrapper.SuccessResponse(result)
+			return nil
+		},
+		// Validation failure case
+		func(errors []dto.Error) interface{} {
+			wrapper.ValidationErrorResponse(errors, "Moderator list validation failed")
+			return nil
+		},
+	)
+}
+
+// AddModerator godoc
+// @Summary Add a project moderator
+// @Description Let a member of the workspace moderate the reports of a project (admins and managers)
+// @Tags moderation
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param moderator body moderationDto.AddModeratorRequest true "Moderator"
+// @Success 201 {object} moderationDto.ModeratorDto
+// @Failure 400 {object} dto.ErrorViewModel
+// @Failure 403 {object} dto.ErrorViewModel
+// @Failure 404 {object} dto.ErrorViewModel
+// @Failure 409 {object} dto.ErrorViewModel
+// @Failure 500 {object} dto.ErrorViewModel
+// @Router /moderation/moderators [post]
+func (h *ModerationHandler) AddModerator(c *gin.Context) {
+	wrapper := handlers.WrapContext(c)
+
+	var request moderationDto.AddModeratorRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		wrapper.BadRequestErrorResponse("Invalid request payload")
+		return
+	}
+
+	response := h.scopedService(c).AddModerator(&request)
+
+	response.Match(
+		// Exception case
+		func(err error) interface{} {
+			wrapper.SystemErrorResponse(err, "Failed to add moderator %s to project %s", request.UserID, request.ProjectID)
+			return nil
+		},
+		// Success case
+		func(result *moderationDto.ModeratorDto) interface{} {
+			wrapper.CreatedResponse(result)
+			return nil
+		},
+		// Validation failure case
+		func(errors []dto.Error) interface{} {
+			respondWithFailure(wrapper, errors, "Project or user", request.ProjectID+"/"+request.UserID, "Moderator validation failed")
+			return nil
+		},
+	)
+}
+
+// RemoveModerator godoc
+// @Summary Remove a project moderator
+// @Description Stop a member from moderating a project (admins and managers)
+// @Tags moderation
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Moderator ID"
+// @Success 200 {object} dto.ErrorViewModel
+// @Failure 403 {object} dto.ErrorViewModel
+// @Failure 404 {object} dto.ErrorViewModel
+// @Failure 500 {object} dto.ErrorViewModel
+// @Router /moderation/moderators/{id} [delete]
+func (h *ModerationHandler) RemoveModerator(c *gin.Context) {
+	wrapper := handlers.WrapContext(c)
+	moderatorID := c.Param("id")
+
+	response := h.scopedService(c).RemoveModerator(moderatorID)
+
+	response.Match(
+		// Exception case
+		func(err error) interface{} {
+			wrapper.SystemErrorResponse(err, "Failed to remove moderator: %s", moderatorID)
+			return nil
+		},
+		// Success case
+		func(result *moderationDto.ModeratorDto) interface{} {
+			wrapper.NoContentResponse()
+			return nil
+		},
+		// Validation failure case
+		func(errors []dto.Error) interface{} {
+			respondWithFailure(wrapper, errors, "Moderator", moderatorID, "Moderator removal validation failed")
+			return nil
+		},
+	)
+}
+
+// ListBlockedWords godoc
+// @Summary List blocked-word lists
+// @Description List the blocked-word lists of the projects of the current workspace (admins and managers)
+// @Tags moderation
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} moderationDto.BlockedWordsDto
+// @Failure 403 {object} dto.ErrorViewModel
+// @Failure 500 {object} dto.ErrorViewModel
+// @Router /moderation/blocked-words [get]
+func (h *ModerationHandler) ListBlockedWords(c *gin.Context) {
+	wrapper := handlers.WrapContext(c)
+
+	response := h.scopedService(c).ListBlockedWords()
+
+	response.Match(
+		// Exception case
+		func(err error) interface{} {
+			wrapper.SystemErrorResponse(err, "Failed to retrieve blocked-word lists")
+			return nil
+		},
+		// Success case
+		func(result []moderationDto.BlockedWordsDto) interface{} {
+			wrapper.SuccessResponse(result)
+			return nil
+		},
+		// Validation failure case
+		func(errors []dto.Error) interface{} {
+			wrapper.ValidationErrorResponse(errors, "Blocked-word list validation failed")
+			return nil
+		},
+	)
+}
+
+// SetBlockedWords godoc
+// @Summary Set the blocked words of a project
+// @Description Create or replace the blocked-word list of a project (admins and managers). Messages sent to its channels that contain one of the words are refused in reject mode, or sent and reported to the moderators in flag mode. Words match whole words, ignoring case
+// @Tags moderation
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param list body moderationDto.SetBlockedWordsRequest true "Blocked words"
+// @Success 200 {object} moderationDto.BlockedWordsDto
+// @Failure 400 {object} dto.ErrorViewModel
+// @Failure 403 {object} dto.ErrorViewModel
+// @Failure 404 {object} dto.ErrorViewModel
+// @Failure 500 {object} dto.ErrorViewModel
+// @Router /moderation/blocked-words [put]
+func (h *ModerationHandler) SetBlockedWords(c *gin.Context) {
+	wrapper := handlers.WrapContext(c)
+
+	var request moderationDto.SetBlockedWordsRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		wrapper.BadRequestErrorResponse("Invalid request payload")
+		return
+	}
+
+	response := h.scopedService(c).SetBlockedWords(&request)
+
+	response.Match(
+		// Exception case
+		func(err error) interface{} {
+			wrapper.SystemErrorResponse(err, "Failed to set blocked words of project %s", request.ProjectID)
+			return nil
+		},
+		// Success case
+		func(result *moderationDto.BlockedWordsDto) interface{} {
+			wrapper.SuccessResponse(result)
+			return nil
+		},
+		// Validation failure case
+		func(errors []dto.Error) interface{} {
+			respondWithFailure(wrapper, errors, "Project", request.ProjectID, "Blocked-word list validation failed")
+			return nil
+		},
+	)
+}
+
+// DeleteBlockedWords godoc
+// @Summary Delete a blocked-word list
+// @Description Remove the blocked-word list of a project, so its messages are no longer checked (admins and managers)
+// @Tags moderation
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Blocked-word list ID"
+// @Success 200 {object} dto.ErrorViewModel
+// @Failure 403 {object} dto.ErrorViewModel
+// @Failure 404 {object} dto.ErrorViewModel
+// @Failure 500 {object} dto.ErrorViewModel
+// @Router /moderation/blocked-words/{id} [delete]
+func (h *ModerationHandler) DeleteBlockedWords(c *gin.Context) {
+	wrapper := handlers.WrapContext(c)
+	listID := c.Param("id")
+
+	response := h.scopedService(c).DeleteBlockedWords(listID)
+
+	response.Match(
+		// Exception case
+		func(err error) interface{} {
+			wrapper.SystemErrorResponse(err, "Failed to delete blocked-word list: %s", listID)
+			return nil
+		},
+		// Success case
+		func(result *moderationDto.BlockedWordsDto) interface{} {
+			wrapper.NoContentResponse()
+			return nil
+		},
+		// Validation failure case
+		func(errors []dto.Error) interface{} {
+			respondWithFailure(wrapper, errors, "Blocked-word list", listID, "Blocked-word list removal validation failed")
+			return nil
+		},
+	)
+}
+
+// respondWithFailure maps service errors to not found, forbidden, conflict or validation responses
+func respondWithFailure(wrapper *handlers.ContextWrapper, errors []dto.Error, resource, identifier, logMessage string) {
+	switch {
+	case len(errors) > 0 && errors[0].Code == constants.NotFoundError:
+		wrapper.NotFoundErrorResponse(resource, identifier)
+	case len(errors) > 0 && errors[0].Code == constants.ForbiddenError:
+		wrapper.ForbiddenErrorResponse(errors[0].Message)
+	case len(errors) > 0 && errors[0].Code == constants.ConflictError:
+		wrapper.ConflictErrorResponse(errors[0].Message)
+	case len(errors) > 0 && errors[0].Code == constants.UnauthorizedError:
+		wrapper.UnauthorizedErrorResponse(errors[0].Message)
+	default:
+		wrapper.ValidationErrorResponse(errors, "%s", logMessage)
+	}
+}
+
+// scopedService binds the service to the request context when supported,
+// so reports and decisions are resolved in the current workspace and attributed to the current user
+func (h *ModerationHandler) scopedService(c *gin.Context) service.ModerationServiceInterface {
+	if aware, ok := h.moderationService.(service.ContextAwareModerationService); ok {
+		return aware.WithContext(c.Request.Context())
+	}
+	return h.moderationService
+}
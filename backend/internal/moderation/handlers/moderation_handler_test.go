@@ -0,0 +1,198 @@
+package handlers
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	moderationDto "thothix-backend/internal/moderation/dto"
+	"thothix-backend/internal/shared/dto"
+)
+
+// MockModerationService is a mock implementation of the ModerationService
+type MockModerationService struct {
+	mock.Mock
+}
+
+func (m *MockModerationService) ReportMessage(req *moderationDto.ReportMessageRequest) *moderationDto.ReportResponse {
+	args := m.Called(req)
+	return args.Get(0).(*moderationDto.ReportResponse)
+}
+
+func (m *MockModerationService) ListReports(req *moderationDto.ReportListRequest) *moderationDto.ReportListResponse {
+	args := m.Called(req)
+	return args.Get(0).(*moderationDto.ReportListResponse)
+}
+
+func (m *MockModerationService) ResolveReport(id string, req *moderationDto.ResolveReportRequest) *moderationDto.ReportResponse {
+	args := m.Called(id, req)
+	return args.Get(0).(*moderationDto.ReportResponse)
+}
+
+func (m *MockModerationService) ListActions(req *moderationDto.ActionListRequest) *moderationDto.ActionListResponse {
+	args := m.Called(req)
+	return args.Get(0).(*moderationDto.ActionListResponse)
+}
+
+func (m *MockModerationService) ListSuspensions(includeInactive bool) *moderationDto.SuspensionListResponse {
+	args := m.Called(includeInactive)
+	return args.Get(0).(*moderationDto.SuspensionListResponse)
+}
+
+func (m *MockModerationService) LiftSuspension(id string) *moderationDto.SuspensionResponse {
+	args := m.Called(id)
+	return args.Get(0).(*moderationDto.SuspensionResponse)
+}
+
+func (m *MockModerationService) ListModerators() *moderationDto.ModeratorListResponse {
+	args := m.Called()
+	return args.Get(0).(*moderationDto.ModeratorListResponse)
+}
+
+func (m *MockModerationService) AddModerator(req *moderationDto.AddModeratorRequest) *moderationDto.ModeratorResponse {
+	args := m.Called(req)
+	return args.Get(0).(*moderationDto.ModeratorResponse)
+}
+
+func (m *MockModerationService) RemoveModerator(id string) *moderationDto.ModeratorResponse {
+	args := m.Called(id)
+	return args.Get(0).(*moderationDto.ModeratorResponse)
+}
+
+func (m *MockModerationService) ListBlockedWords() *moderationDto.BlockedWordsListResponse {
+	args := m.Called()
+	return args.Get(0).(*moderationDto.BlockedWordsListResponse)
+}
+
+func (m *MockModerationService) SetBlockedWords(req *moderationDto.SetBlockedWordsRequest) *moderationDto.BlockedWordsResponse {
+	args := m.Called(req)
+	return args.Get(0).(*moderationDto.BlockedWordsResponse)
+}
+
+func (m *MockModerationService) DeleteBlockedWords(id string) *moderationDto.BlockedWordsResponse {
+	args := m.Called(id)
+	return args.Get(0).(*moderationDto.BlockedWordsResponse)
+}
+
+type ModerationHandlerTestSuite struct {
+	suite.Suite
+	mockService *MockModerationService
+	router      *gin.Engine
+}
+
+func (suite *ModerationHandlerTestSuite) SetupSuite() {
+	gin.SetMode(gin.TestMode)
+}
+
+func (suite *ModerationHandlerTestSuite) SetupTest() {
+	suite.mockService = new(MockModerationService)
+	handler := NewModerationHandler(suite.mockService)
+
+	suite.router = gin.New()
+	suite.router.POST("/moderation/reports", handler.ReportMessage)
+	suite.router.GET("/moderation/reports", handler.ListReports)
+	suite.router.POST("/moderation/reports/:id/resolve", handler.ResolveReport)
+	suite.router.PUT("/moderation/blocked-words", handler.SetBlockedWords)
+}
+
+func (suite *ModerationHandlerTestSuite) TestReportMessage_Created() {
+	// Arrange
+	mockResponse := moderationDto.NewReportResponse(func() dto.Validation[*moderationDto.ReportDto] {
+		return dto.Success(&moderationDto.ReportDto{ID: "report-1", MessageID: "message-1", Reason: "spam", Status: "open"})
+	})
+
+	suite.mockService.On("ReportMessage", &moderationDto.ReportMessageRequest{MessageID: "message-1", Reason: "spam"}).Return(mockResponse)
+
+	// Act
+	req, _ := http.NewRequest("POST", "/moderation/reports", bytes.NewBufferString(`{"message_id":"message-1","reason":"spam"}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(suite.T(), http.StatusCreated, w.Code)
+	assert.Contains(suite.T(), w.Body.String(), "report-1")
+}
+
+func (suite *ModerationHandlerTestSuite) TestReportMessage_InvalidReason() {
+	// Act
+	req, _ := http.NewRequest("POST", "/moderation/reports", bytes.NewBufferString(`{"message_id":"message-1","reason":"blocked_words"}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(suite.T(), http.StatusBadRequest, w.Code)
+	suite.mockService.AssertNotCalled(suite.T(), "ReportMessage", mock.Anything)
+}
+
+func (suite *ModerationHandlerTestSuite) TestListReports_NotAModerator() {
+	// Arrange
+	mockResponse := moderationDto.NewReportListResponse(func() dto.Validation[*moderationDto.ReportListDto] {
+		return dto.Failure[*moderationDto.ReportListDto](dto.NewError("FORBIDDEN", "Only admins, managers and moderators can moderate", nil))
+	})
+
+	suite.mockService.On("ListReports", mock.MatchedBy(func(req *moderationDto.ReportListRequest) bool {
+		return req.Page == 1 && req.PerPage == 20 && req.Status == ""
+	})).Return(mockResponse)
+
+	// Act
+	req, _ := http.NewRequest("GET", "/moderation/reports", nil)
+	w := httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(suite.T(), http.StatusForbidden, w.Code)
+}
+
+func (suite *ModerationHandlerTestSuite) TestResolveReport_AlreadyResolved() {
+	// Arrange
+	mockResponse := moderationDto.NewReportResponse(func() dto.Validation[*moderationDto.ReportDto] {
+		return dto.Invalid[*moderationDto.ReportDto](dto.NewError("CONFLICT", "Report was already resolved", nil))
+	})
+	hours := 24
+
+	suite.mockService.On("ResolveReport", "report-1", &moderationDto.ResolveReportRequest{Action: "suspend_author", DurationHours: &hours}).Return(mockResponse)
+
+	// Act
+	req, _ := http.NewRequest("POST", "/moderation/reports/report-1/resolve", bytes.NewBufferString(`{"action":"suspend_author","duration_hours":24}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(suite.T(), http.StatusConflict, w.Code)
+}
+
+func (suite *ModerationHandlerTestSuite) TestResolveReport_UnknownAction() {
+	// Act
+	req, _ := http.NewRequest("POST", "/moderation/reports/report-1/resolve", bytes.NewBufferString(`{"action":"ban"}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(suite.T(), http.StatusBadRequest, w.Code)
+	suite.mockService.AssertNotCalled(suite.T(), "ResolveReport", mock.Anything, mock.Anything)
+}
+
+func (suite *ModerationHandlerTestSuite) TestSetBlockedWords_InvalidMode() {
+	// Act
+	req, _ := http.NewRequest("PUT", "/moderation/blocked-words", bytes.NewBufferString(`{"project_id":"project-1","mode":"shadow","words":["scam"]}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(suite.T(), http.StatusBadRequest, w.Code)
+	suite.mockService.AssertNotCalled(suite.T(), "SetBlockedWords", mock.Anything)
+}
+
+func TestModerationHandlerTestSuite(t *testing.T) {
+	suite.Run(t, new(ModerationHandlerTestSuite))
+}
@@ -0,0 +1,34 @@
+package handlers
+
+import (
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"thothix-backend/internal/middleware"
+	"thothix-backend/internal/moderation/service"
+	sharedModels "thothix-backend/internal/shared/models"
+)
+
+// RegisterModerationRoutes registers the message report, moderation queue and moderation settings routes of the current workspace
+func RegisterModerationRoutes(router *gin.RouterGroup, db *gorm.DB) {
+	moderationService := service.NewModerationService(db)
+	moderationHandler := NewModerationHandler(moderationService)
+
+	moderation := router.Group("/moderation")
+	// Any member can report a message; the queue is checked against the user's remit by the service,
+	// since project moderators are regular members
+	moderation.POST("/reports", moderationHandler.ReportMessage)
+	moderation.GET("/reports", moderationHandler.ListReports)
+	moderation.POST("/reports/:id/resolve", moderationHandler.ResolveReport)
+	moderation.GET("/actions", moderationHandler.ListActions)
+	moderation.GET("/suspensions", moderationHandler.ListSuspensions)
+	moderation.POST("/suspensions/:id/lift", moderationHandler.LiftSuspension)
+
+	settings := moderation.Group("")
+	settings.Use(middleware.RequireSystemRole(db, sharedModels.RoleManager))
+	settings.GET("/moderators", moderationHandler.ListModerators)
+	settings.POST("/moderators", moderationHandler.AddModerator)
+	settings.DELETE("/moderators/:id", moderationHandler.RemoveModerator)
+	settings.GET("/blocked-words", moderationHandler.ListBlockedWords)
+	settings.PUT("/blocked-words", moderationHandler.SetBlockedWords)
+	settings.DELETE("/blocked-words/:id", moderationHandler.DeleteBlockedWords)
+}
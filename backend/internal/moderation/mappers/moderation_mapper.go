@@ -0,0 +1,168 @@
+package mappers
+
+import (
+	"time"
+
+	"thothix-backend/internal/moderation/domain"
+	moderationDto "thothix-backend/internal/moderation/dto"
+)
+
+// ModerationMapper handles conversion between reports, suspensions, moderators, blocked-word lists and their DTOs
+type ModerationMapper struct{}
+
+// NewModerationMapper creates a new ModerationMapper instance
+func NewModerationMapper() *ModerationMapper {
+	return &ModerationMapper{}
+}
+
+// ReportToDto converts a Report to ReportDto
+func (m *ModerationMapper) ReportToDto(report *domain.Report) *moderationDto.ReportDto {
+	if report == nil {
+		return nil
+	}
+
+	dto := &moderationDto.ReportDto{
+		ID:             report.ID,
+		MessageID:      report.MessageID,
+		ChannelID:      report.ChannelID,
+		ProjectID:      report.ProjectID,
+		AuthorID:       report.AuthorID,
+		ReporterID:     report.ReporterID,
+		Reason:         string(report.Reason),
+		Details:        report.Details,
+		Excerpt:        report.Excerpt,
+		Status:         string(report.Status),
+		ResolutionNote: report.ResolutionNote,
+		ResolvedBy:     report.ResolvedBy,
+		CreatedAt:      report.CreatedAt.UTC().Format(time.RFC3339),
+	}
+	if report.ResolvedAt != nil {
+		dto.ResolvedAt = report.ResolvedAt.UTC().Format(time.RFC3339)
+	}
+	return dto
+}
+
+// ReportsToDtos converts a slice of Report to ReportDto
+func (m *ModerationMapper) ReportsToDtos(reports []domain.Report) []moderationDto.ReportDto {
+	dtos := make([]moderationDto.ReportDto, 0, len(reports))
+	for i := range reports {
+		dtos = append(dtos, *m.ReportToDto(&reports[i]))
+	}
+	return dtos
+}
+
+// ActionToDto converts an Action to ActionDto
+func (m *ModerationMapper) ActionToDto(action *domain.Action) *moderationDto.ActionDto {
+	if action == nil {
+		return nil
+	}
+
+	return &moderationDto.ActionDto{
+		ID:           action.ID,
+		ModeratorID:  action.ModeratorID,
+		Type:         string(action.Type),
+		ProjectID:    action.ProjectID,
+		ReportID:     action.ReportID,
+		MessageID:    action.MessageID,
+		TargetUserID: action.TargetUserID,
+		Note:         action.Note,
+		CreatedAt:    action.CreatedAt.UTC().Format(time.RFC3339),
+	}
+}
+
+// ActionsToDtos converts a slice of Action to ActionDto
+func (m *ModerationMapper) ActionsToDtos(actions []domain.Action) []moderationDto.ActionDto {
+	dtos := make([]moderationDto.ActionDto, 0, len(actions))
+	for i := range actions {
+		dtos = append(dtos, *m.ActionToDto(&actions[i]))
+	}
+	return dtos
+}
+
+// SuspensionToDto converts a Suspension to SuspensionDto; now decides whether it is still active
+func (m *ModerationMapper) SuspensionToDto(suspension *domain.Suspension, now time.Time) *moderationDto.SuspensionDto {
+	if suspension == nil {
+		return nil
+	}
+
+	dto := &moderationDto.SuspensionDto{
+		ID:        suspension.ID,
+		UserID:    suspension.UserID,
+		ProjectID: suspension.ProjectID,
+		ReportID:  suspension.ReportID,
+		Reason:    suspension.Reason,
+		Active:    suspension.IsActive(now),
+		CreatedBy: suspension.CreatedBy,
+		CreatedAt: suspension.CreatedAt.UTC().Format(time.RFC3339),
+		LiftedBy:  suspension.LiftedBy,
+	}
+	if suspension.ExpiresAt != nil {
+		dto.ExpiresAt = suspension.ExpiresAt.UTC().Format(time.RFC3339)
+	}
+	if suspension.LiftedAt != nil {
+		dto.LiftedAt = suspension.LiftedAt.UTC().Format(time.RFC3339)
+	}
+	return dto
+}
+
+// SuspensionsToDtos converts a slice of Suspension to SuspensionDto
+func (m *ModerationMapper) SuspensionsToDtos(suspensions []domain.Suspension, now time.Time) []moderationDto.SuspensionDto {
+	dtos := make([]moderationDto.SuspensionDto, 0, len(suspensions))
+	for i := range suspensions {
+		dtos = append(dtos, *m.SuspensionToDto(&suspensions[i], now))
+	}
+	return dtos
+}
+
+// ModeratorToDto converts a Moderator to ModeratorDto
+func (m *ModerationMapper) ModeratorToDto(moderator *domain.Moderator) *moderationDto.ModeratorDto {
+	if moderator == nil {
+		return nil
+	}
+
+	return &moderationDto.ModeratorDto{
+		ID:        moderator.ID,
+		ProjectID: moderator.ProjectID,
+		UserID:    moderator.UserID,
+		CreatedBy: moderator.CreatedBy,
+		CreatedAt: moderator.CreatedAt.UTC().Format(time.RFC3339),
+	}
+}
+
+// ModeratorsToDtos converts a slice of Moderator to ModeratorDto
+func (m *ModerationMapper) ModeratorsToDtos(moderators []domain.Moderator) []moderationDto.ModeratorDto {
+	dtos := make([]moderationDto.ModeratorDto, 0, len(moderators))
+	for i := range moderators {
+		dtos = append(dtos, *m.ModeratorToDto(&moderators[i]))
+	}
+	return dtos
+}
+
+// BlockedWordsToDto converts a BlockedWordList to BlockedWordsDto
+func (m *ModerationMapper) BlockedWordsToDto(list *domain.BlockedWordList) *moderationDto.BlockedWordsDto {
+	if list == nil {
+		return nil
+	}
+
+	words := list.Words
+	if words == nil {
+		words = []string{}
+	}
+	return &moderationDto.BlockedWordsDto{
+		ID:        list.ID,
+		ProjectID: list.ProjectID,
+		Mode:      string(list.Mode),
+		Words:     words,
+		UpdatedBy: list.UpdatedBy,
+		UpdatedAt: list.UpdatedAt.UTC().Format(time.RFC3339),
+	}
+}
+
+// BlockedWordsToDtos converts a slice of BlockedWordList to BlockedWordsDto
+func (m *ModerationMapper) BlockedWordsToDtos(lists []domain.BlockedWordList) []moderationDto.BlockedWordsDto {
+	dtos := make([]moderationDto.BlockedWordsDto, 0, len(lists))
+	for i := range lists {
+		dtos = append(dtos, *m.BlockedWordsToDto(&lists[i]))
+	}
+	return dtos
+}
@@ -0,0 +1,105 @@
+// Package screening holds the moderation checks run when a message is posted: suspensions of the sender
+// and the blocked-word list of the project
+package screening
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+
+	messageDomain "thothix-backend/internal/message/domain"
+	"thothix-backend/internal/moderation/domain"
+	sharedMiddleware "thothix-backend/internal/shared/middleware"
+	sharedModels "thothix-backend/internal/shared/models"
+)
+
+// ActiveSuspension returns a suspension keeping userID from posting in the channels of projectID, or the
+// workspace-wide one when projectID is empty, as for direct messages. It returns nil when the user can post
+// Suspensions are looked up in the workspace carried by db's context
+func ActiveSuspension(db *gorm.DB, userID, projectID string) (*domain.Suspension, error) {
+	query := db.Scopes(sharedModels.InWorkspace("moderation_suspensions")).
+		Where("user_id = ? AND lifted_at IS NULL AND (expires_at IS NULL OR expires_at > ?)", userID, time.Now().UTC())
+	if projectID == "" {
+		query = query.Where("project_id IS NULL")
+	} else {
+		query = query.Where("project_id IS NULL OR project_id = ?", projectID)
+	}
+
+	var suspensions []domain.Suspension
+	if err := query.Order("expires_at DESC NULLS FIRST").Limit(1).Find(&suspensions).Error; err != nil {
+		return nil, err
+	}
+	if len(suspensions) == 0 {
+		return nil, nil
+	}
+	return &suspensions[0], nil
+}
+
+// SuspensionMessage explains a suspension to the suspended user
+func SuspensionMessage(suspension *domain.Suspension) string {
+	if suspension.ExpiresAt == nil {
+		return "You are suspended from posting"
+	}
+	return "You are suspended from posting until " + suspension.ExpiresAt.UTC().Format(time.RFC3339)
+}
+
+// Verdict is the outcome of checking a message against a blocked-word list
+type Verdict struct {
+	Mode  domain.FilterMode
+	Words []string // Entries of the list found in the message
+}
+
+// Rejects reports whether the message must be refused rather than sent and flagged
+func (v *Verdict) Rejects() bool {
+	return v.Mode == domain.FilterReject
+}
+
+// Message explains a rejection to the sender
+func (v *Verdict) Message() string {
+	return "Message contains blocked words: " + strings.Join(v.Words, ", ")
+}
+
+// Check checks text against the blocked-word list of projectID
+// It returns nil when the project has no list or the text contains none of its words
+func Check(db *gorm.DB, projectID, text string) (*Verdict, error) {
+	if projectID == "" {
+		return nil, nil
+	}
+
+	var lists []domain.BlockedWordList
+	if err := db.Scopes(sharedModels.InWorkspace("blocked_word_lists")).Where("project_id = ?", projectID).Limit(1).Find(&lists).Error; err != nil {
+		return nil, err
+	}
+	if len(lists) == 0 {
+		return nil, nil
+	}
+	matched := lists[0].Match(text)
+	if len(matched) == 0 {
+		return nil, nil
+	}
+	return &Verdict{Mode: lists[0].Mode, Words: matched}, nil
+}
+
+// Flag files a report for a message that was sent despite matching a list in flag mode,
+// so it shows up in the moderation queue of the project
+func Flag(db *gorm.DB, message *messageDomain.Message, projectID string, verdict *Verdict) error {
+	workspaceID, ok := sharedMiddleware.WorkspaceIDFromContext(db.Statement.Context)
+	if !ok {
+		return fmt.Errorf("screening: a current workspace is required to flag message %s", message.ID)
+	}
+
+	report := domain.Report{
+		WorkspaceID: workspaceID,
+		MessageID:   message.ID,
+		ChannelID:   message.ChannelID,
+		ProjectID:   &projectID,
+		AuthorID:    message.SenderID,
+		Reason:      domain.ReasonBlockedWords,
+		Details:     "Matched: " + strings.Join(verdict.Words, ", "),
+		Excerpt:     message.DisplayText(),
+		Status:      domain.StatusOpen,
+	}
+	return db.Create(&report).Error
+}
@@ -0,0 +1,77 @@
+package service
+
+import (
+	"errors"
+	"slices"
+
+	"gorm.io/gorm"
+
+	"thothix-backend/internal/moderation/domain"
+	"thothix-backend/internal/shared/constants"
+	"thothix-backend/internal/shared/dto"
+	sharedMiddleware "thothix-backend/internal/shared/middleware"
+	sharedModels "thothix-backend/internal/shared/models"
+)
+
+// authority is what the current user may moderate in the current workspace: everything for admins and
+// managers, the reports of the projects they were made moderator of for other members
+type authority struct {
+	userID      string
+	workspaceID string
+	workspace   bool     // Admin or manager of the workspace
+	projects    []string // Moderated projects, for members who are not admins or managers
+}
+
+// covers reports whether a report, suspension or log entry of projectID is in the user's remit
+// Workspace-wide items, such as reports of direct messages, are only covered for admins and managers
+func (a *authority) covers(projectID *string) bool {
+	if a.workspace {
+		return true
+	}
+	return projectID != nil && slices.Contains(a.projects, *projectID)
+}
+
+// scope restricts a query of reports, suspensions or log entries to the user's remit
+func (a *authority) scope(db *gorm.DB) *gorm.DB {
+	db = db.Where("workspace_id = ?", a.workspaceID)
+	if a.workspace {
+		return db
+	}
+	return db.Where("project_id IN ?", a.projects)
+}
+
+// authority resolves the remit of the current user, failing for members who cannot moderate anything
+func (s *ModerationService) authority() (*authority, *dto.Error) {
+	workspaceID, failure := s.workspace()
+	if failure != nil {
+		return nil, failure
+	}
+	userID, ok := sharedMiddleware.UserIDFromContext(s.db.Statement.Context)
+	if !ok {
+		err := dto.NewError(constants.UnauthorizedError, "User not authenticated", nil)
+		return nil, &err
+	}
+	forbidden := dto.NewError(constants.ForbiddenError, "Only admins, managers and moderators can moderate", nil)
+
+	role, err := sharedModels.GetUserRole(s.db, userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, &forbidden
+		}
+		panic(err)
+	}
+	a := &authority{userID: userID, workspaceID: workspaceID}
+	if role == sharedModels.RoleAdmin || role == sharedModels.RoleManager {
+		a.workspace = true
+		return a, nil
+	}
+
+	if err := s.db.Model(&domain.Moderator{}).Where("workspace_id = ? AND user_id = ?", workspaceID, userID).
+		Pluck("project_id", &a.projects).Error; err != nil {
+		panic(err)
+	}
+	if len(a.projects) == 0 {
+		return nil, &forbidden
+	}
+	return a, nil
+}
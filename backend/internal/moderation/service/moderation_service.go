@@ -0,0 +1,563 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+
+	messageDomain "thothix-backend/internal/message/domain"
+	"thothix-backend/internal/moderation/domain"
+	moderationDto "thothix-backend/internal/moderation/dto"
+	"thothix-backend/internal/moderation/mappers"
+	retentionDomain "thothix-backend/internal/retention/domain"
+	retentionService "thothix-backend/internal/retention/service"
+	schedulingService "thothix-backend/internal/scheduling/service"
+	"thothix-backend/internal/shared/constants"
+	"thothix-backend/internal/shared/dto"
+	sharedMiddleware "thothix-backend/internal/shared/middleware"
+	sharedModels "thothix-backend/internal/shared/models"
+	usersDomain "thothix-backend/internal/users/domain"
+)
+
+type ModerationService struct {
+	db     *gorm.DB
+	mapper *mappers.ModerationMapper
+	now    func() time.Time
+}
+
+func NewModerationService(db *gorm.DB) *ModerationService {
+	return &ModerationService{
+		db:     db,
+		mapper: mappers.NewModerationMapper(),
+		now:    time.Now,
+	}
+}
+
+// WithContext returns a copy of the service whose database session carries ctx
+func (s *ModerationService) WithContext(ctx context.Context) *ModerationService {
+	return &ModerationService{
+		db:     s.db.WithContext(ctx),
+		mapper: s.mapper,
+		now:    s.now,
+	}
+}
+
+// ReportMessage reports a message the current user can read to the moderators
+// Messages the user cannot read are reported as not found so their existence is not revealed
+func (s *ModerationService) ReportMessage(req *moderationDto.ReportMessageRequest) *moderationDto.ReportResponse {
+	return moderationDto.NewReportResponse(func() dto.Validation[*moderationDto.ReportDto] {
+		workspaceID, failure := s.workspace()
+		if failure != nil {
+			return dto.Failure[*moderationDto.ReportDto](*failure)
+		}
+		userID, ok := sharedMiddleware.UserIDFromContext(s.db.Statement.Context)
+		if !ok {
+			return dto.Failure[*moderationDto.ReportDto](dto.NewError(constants.UnauthorizedError, "User not authenticated", nil))
+		}
+
+		message := schedulingService.ReadableMessage(s.db, userID, req.MessageID)
+		if message == nil {
+			return dto.Invalid[*moderationDto.ReportDto](dto.NewError(constants.NotFoundError, "Message not found", nil))
+		}
+		if message.SenderID == userID {
+			return dto.Failure[*moderationDto.ReportDto](dto.NewError(constants.ValidationError, "You cannot report your own message", nil))
+		}
+
+		var count int64
+		if err := s.db.Model(&domain.Report{}).
+			Where("message_id = ? AND reporter_id = ? AND status = ?", message.ID, userID, domain.StatusOpen).
+			Count(&count).Error; err != nil {
+			panic(err)
+		}
+		if count > 0 {
+			return dto.Invalid[*moderationDto.ReportDto](dto.NewError(constants.ConflictError, "You already reported this message", nil))
+		}
+
+		report := domain.Report{
+			WorkspaceID: workspaceID,
+			MessageID:   message.ID,
+			ChannelID:   message.ChannelID,
+			ProjectID:   s.messageProject(message),
+			AuthorID:    message.SenderID,
+			ReporterID:  &userID,
+			Reason:      domain.Reason(req.Reason),
+			Details:     req.Details,
+			Excerpt:     message.DisplayText(),
+			Status:      domain.StatusOpen,
+		}
+		if err := s.db.Create(&report).Error; err != nil {
+			panic(err)
+		}
+		return dto.Success(s.mapper.ReportToDto(&report))
+	})
+}
+
+// ListReports lists the reports the current user moderates, oldest first so the queue is worked in order
+func (s *ModerationService) ListReports(req *moderationDto.ReportListRequest) *moderationDto.ReportListResponse {
+	return moderationDto.NewReportListResponse(func() dto.Validation[*moderationDto.ReportListDto] {
+		if req == nil || req.Page < 1 || req.PerPage < 1 || req.PerPage > 100 {
+			return dto.Failure[*moderationDto.ReportListDto](dto.NewError(constants.ValidationError, "Page must be positive and per page between 1 and 100", nil))
+		}
+		auth, failure := s.authority()
+		if failure != nil {
+			return dto.Failure[*moderationDto.ReportListDto](*failure)
+		}
+
+		status := domain.StatusOpen
+		if req.Status != "" {
+			status = domain.Status(req.Status)
+		}
+		query := auth.scope(s.db.Model(&domain.Report{})).Where("status = ?", status)
+
+		var total int64
+		if err := query.Count(&total).Error; err != nil {
+			panic(err)
+		}
+		var reports []domain.Report
+		if err := query.Order("created_at").Offset((req.Page - 1) * req.PerPage).Limit(req.PerPage).Find(&reports).Error; err != nil {
+			panic(err)
+		}
+		return dto.Success(dto.NewPaginatedListResponse(s.mapper.ReportsToDtos(reports), total, req.Page, req.PerPage))
+	})
+}
+
+// ResolveReport decides an open report: dismiss it, delete the message, or suspend its author
+// Deleting a message resolves the other open reports about it too. Project moderators suspend authors
+// in the channels of the project, admins and managers in the whole workspace. Every decision is logged
+func (s *ModerationService) ResolveReport(id string, req *moderationDto.ResolveReportRequest) *moderationDto.ReportResponse {
+	return moderationDto.NewReportResponse(func() dto.Validation[*moderationDto.ReportDto] {
+		auth, failure := s.authority()
+		if failure != nil {
+			return dto.Failure[*moderationDto.ReportDto](*failure)
+		}
+
+		var report domain.Report
+		if err := s.db.Where("id::text = ? AND workspace_id = ?", id, auth.workspaceID).First(&report).Error; err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return dto.Invalid[*moderationDto.ReportDto](dto.NewError(constants.NotFoundError, "Report not found", nil))
+			}
+			panic(err)
+		}
+		if !auth.covers(report.ProjectID) {
+			return dto.Invalid[*moderationDto.ReportDto](dto.NewError(constants.NotFoundError, "Report not found", nil))
+		}
+		if !report.IsOpen() {
+			return dto.Invalid[*moderationDto.ReportDto](dto.NewError(constants.ConflictError, "Report was already resolved", nil))
+		}
+
+		now := s.now().UTC()
+		action := domain.Action{
+			WorkspaceID:  auth.workspaceID,
+			ModeratorID:  auth.userID,
+			Type:         domain.ActionType(req.Action),
+			ProjectID:    report.ProjectID,
+			ReportID:     &report.ID,
+			MessageID:    &report.MessageID,
+			TargetUserID: &report.AuthorID,
+			Note:         req.Note,
+		}
+		var suspension *domain.Suspension
+
+		switch action.Type {
+		case domain.ActionDismiss:
+			report.Status = domain.StatusDismissed
+		case domain.ActionDeleteMessage:
+			if s.messageHeld(&report) {
+				return dto.Invalid[*moderationDto.ReportDto](dto.NewError(constants.ConflictError, "A legal hold covers this message, so it cannot be deleted", nil))
+			}
+			report.Status = domain.StatusMessageDeleted
+		case domain.ActionSuspendAuthor:
+			if failure := s.checkSuspendable(auth, report.AuthorID); failure != nil {
+				return dto.Failure[*moderationDto.ReportDto](*failure)
+			}
+			report.Status = domain.StatusAuthorSuspended
+			suspension = &domain.Suspension{
+				WorkspaceID: auth.workspaceID,
+				UserID:      report.AuthorID,
+				ReportID:    &report.ID,
+				Reason:      req.Note,
+			}
+			if !auth.workspace {
+				suspension.ProjectID = report.ProjectID
+			}
+			if req.DurationHours != nil {
+				expiresAt := now.Add(time.Duration(*req.DurationHours) * time.Hour)
+				suspension.ExpiresAt = &expiresAt
+			}
+			action.ProjectID = suspension.ProjectID
+		default:
+			return dto.Failure[*moderationDto.ReportDto](dto.NewError(constants.ValidationError, "Action must be dismiss, delete_message or suspend_author", nil))
+		}
+		report.ResolutionNote = req.Note
+		report.ResolvedBy = &auth.userID
+		report.ResolvedAt = &now
+
+		if err := s.db.Transaction(func(tx *gorm.DB) error {
+			if err := tx.Model(&report).Select("status", "resolution_note", "resolved_by", "resolved_at").Updates(&report).Error; err != nil {
+				return err
+			}
+			if report.Status == domain.StatusMessageDeleted {
+				if err := deleteMessage(tx, &report); err != nil {
+					return err
+				}
+			}
+			if suspension != nil {
+				if err := tx.Create(suspension).Error; err != nil {
+					return err
+				}
+			}
+			return tx.Create(&action).Error
+		}); err != nil {
+			panic(err)
+		}
+		return dto.Success(s.mapper.ReportToDto(&report))
+	})
+}
+
+// ListActions lists the moderation log of the decisions in the current user's remit, most recent first
+func (s *ModerationService) ListActions(req *moderationDto.ActionListRequest) *moderationDto.ActionListResponse {
+	return moderationDto.NewActionListResponse(func() dto.Validation[*moderationDto.ActionListDto] {
+		if req == nil || req.Page < 1 || req.PerPage < 1 || req.PerPage > 100 {
+			return dto.Failure[*moderationDto.ActionListDto](dto.NewError(constants.ValidationError, "Page must be positive and per page between 1 and 100", nil))
+		}
+		auth, failure := s.authority()
+		if failure != nil {
+			return dto.Failure[*moderationDto.ActionListDto](*failure)
+		}
+
+		query := auth.scope(s.db.Model(&domain.Action{}))
+		var total int64
+		if err := query.Count(&total).Error; err != nil {
+			panic(err)
+		}
+		var actions []domain.Action
+		if err := query.Order("created_at DESC").Offset((req.Page - 1) * req.PerPage).Limit(req.PerPage).Find(&actions).Error; err != nil {
+			panic(err)
+		}
+		return dto.Success(dto.NewPaginatedListResponse(s.mapper.ActionsToDtos(actions), total, req.Page, req.PerPage))
+	})
+}
+
+// ListSuspensions lists the active suspensions in the current user's remit, and the lifted and expired ones when asked
+func (s *ModerationService) ListSuspensions(includeInactive bool) *moderationDto.SuspensionListResponse {
+	return moderationDto.NewSuspensionListResponse(func() dto.Validation[[]moderationDto.SuspensionDto] {
+		auth, failure := s.authority()
+		if failure != nil {
+			return dto.Failure[[]moderationDto.SuspensionDto](*failure)
+		}
+
+		now := s.now().UTC()
+		query := auth.scope(s.db.Model(&domain.Suspension{}))
+		if !includeInactive {
+			query = query.Where("lifted_at IS NULL AND (expires_at IS NULL OR expires_at > ?)", now)
+		}
+		var suspensions []domain.Suspension
+		if err := query.Order("created_at DESC").Find(&suspensions).Error; err != nil {
+			panic(err)
+		}
+		return dto.Success(s.mapper.SuspensionsToDtos(suspensions, now))
+	})
+}
+
+// LiftSuspension ends a suspension before it expires, so the user can post again
+func (s *ModerationService) LiftSuspension(id string) *moderationDto.SuspensionResponse {
+	return moderationDto.NewSuspensionResponse(func() dto.Validation[*moderationDto.SuspensionDto] {
+		auth, failure := s.authority()
+		if failure != nil {
+			return dto.Failure[*moderationDto.SuspensionDto](*failure)
+		}
+
+		var suspension domain.Suspension
+		if err := s.db.Where("id::text = ? AND workspace_id = ?", id, auth.workspaceID).First(&suspension).Error; err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return dto.Invalid[*moderationDto.SuspensionDto](dto.NewError(constants.NotFoundError, "Suspension not found", nil))
+			}
+			panic(err)
+		}
+		if !auth.covers(suspension.ProjectID) {
+			return dto.Invalid[*moderationDto.SuspensionDto](dto.NewError(constants.NotFoundError, "Suspension not found", nil))
+		}
+		now := s.now().UTC()
+		if !suspension.IsActive(now) {
+			return dto.Invalid[*moderationDto.SuspensionDto](dto.NewError(constants.ConflictError, "Suspension is no longer active", nil))
+		}
+
+		suspension.LiftedAt = &now
+		suspension.LiftedBy = &auth.userID
+		action := domain.Action{
+			WorkspaceID:  auth.workspaceID,
+			ModeratorID:  auth.userID,
+			Type:         domain.ActionLiftSuspension,
+			ProjectID:    suspension.ProjectID,
+			ReportID:     suspension.ReportID,
+			TargetUserID: &suspension.UserID,
+		}
+		if err := s.db.Transaction(func(tx *gorm.DB) error {
+			if err := tx.Model(&suspension).Select("lifted_at", "lifted_by").Updates(&suspension).Error; err != nil {
+				return err
+			}
+			return tx.Create(&action).Error
+		}); err != nil {
+			panic(err)
+		}
+		return dto.Success(s.mapper.SuspensionToDto(&suspension, now))
+	})
+}
+
+// ListModerators lists the project moderators of the current workspace
+func (s *ModerationService) ListModerators() *moderationDto.ModeratorListResponse {
+	return moderationDto.NewModeratorListResponse(func() dto.Validation[[]moderationDto.ModeratorDto] {
+		workspaceID, failure := s.workspace()
+		if failure != nil {
+			return dto.Failure[[]moderationDto.ModeratorDto](*failure)
+		}
+
+		var moderators []domain.Moderator
+		if err := s.db.Where("workspace_id = ?", workspaceID).Order("project_id, created_at").Find(&moderators).Error; err != nil {
+			panic(err)
+		}
+		return dto.Success(s.mapper.ModeratorsToDtos(moderators))
+	})
+}
+
+// AddModerator lets a member of the workspace moderate the reports of a project
+func (s *ModerationService) AddModerator(req *moderationDto.AddModeratorRequest) *moderationDto.ModeratorResponse {
+	return moderationDto.NewModeratorResponse(func() dto.Validation[*moderationDto.ModeratorDto] {
+		workspaceID, projectID, failure := s.project(req.ProjectID)
+		if failure != nil {
+			return dto.Failure[*moderationDto.ModeratorDto](*failure)
+		}
+
+		var user usersDomain.User
+		if err := s.db.Scopes(sharedModels.WorkspaceMembers).Where("id = ?", req.UserID).First(&user).Error; err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return dto.Invalid[*moderationDto.ModeratorDto](dto.NewError(constants.NotFoundError, "User not found", nil))
+			}
+			panic(err)
+		}
+		if !user.IsActive() {
+			return dto.Invalid[*moderationDto.ModeratorDto](dto.NewError(constants.NotFoundError, "User not found", nil))
+		}
+
+		var count int64
+		if err := s.db.Model(&domain.Moderator{}).Where("project_id = ? AND user_id = ?", projectID, user.ID).Count(&count).Error; err != nil {
+			panic(err)
+		}
+		if count > 0 {
+			return dto.Invalid[*moderationDto.ModeratorDto](dto.NewError(constants.ConflictError, "User already moderates this project", nil))
+		}
+
+		moderator := domain.Moderator{WorkspaceID: workspaceID, ProjectID: projectID, UserID: user.ID}
+		if err := s.db.Create(&moderator).Error; err != nil {
+			panic(err)
+		}
+		return dto.Success(s.mapper.ModeratorToDto(&moderator))
+	})
+}
+
+// RemoveModerator stops a member from moderating a project
+func (s *ModerationService) RemoveModerator(id string) *moderationDto.ModeratorResponse {
+	return moderationDto.NewModeratorResponse(func() dto.Validation[*moderationDto.ModeratorDto] {
+		workspaceID, failure := s.workspace()
+		if failure != nil {
+			return dto.Failure[*moderationDto.ModeratorDto](*failure)
+		}
+
+		var moderator domain.Moderator
+		if err := s.db.Where("id::text = ? AND workspace_id = ?", id, workspaceID).First(&moderator).Error; err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return dto.Invalid[*moderationDto.ModeratorDto](dto.NewError(constants.NotFoundError, "Moderator not found", nil))
+			}
+			panic(err)
+		}
+		if err := s.db.Delete(&moderator).Error; err != nil {
+			panic(err)
+		}
+		return dto.Success(s.mapper.ModeratorToDto(&moderator))
+	})
+}
+
+// ListBlockedWords lists the blocked-word lists of the projects of the current workspace
+func (s *ModerationService) ListBlockedWords() *moderationDto.BlockedWordsListResponse {
+	return moderationDto.NewBlockedWordsListResponse(func() dto.Validation[[]moderationDto.BlockedWordsDto] {
+		workspaceID, failure := s.workspace()
+		if failure != nil {
+			return dto.Failure[[]moderationDto.BlockedWordsDto](*failure)
+		}
+
+		var lists []domain.BlockedWordList
+		if err := s.db.Where("workspace_id = ?", workspaceID).Order("created_at").Find(&lists).Error; err != nil {
+			panic(err)
+		}
+		return dto.Success(s.mapper.BlockedWordsToDtos(lists))
+	})
+}
+
+// SetBlockedWords creates the blocked-word list of a project, or replaces its words and mode
+func (s *ModerationService) SetBlockedWords(req *moderationDto.SetBlockedWordsRequest) *moderationDto.BlockedWordsResponse {
+	return moderationDto.NewBlockedWordsResponse(func() dto.Validation[*moderationDto.BlockedWordsDto] {
+		mode := domain.FilterMode(req.Mode)
+		if mode != domain.FilterReject && mode != domain.FilterFlag {
+			return dto.Failure[*moderationDto.BlockedWordsDto](dto.NewError(constants.ValidationError, "Mode must be reject or flag", nil))
+		}
+		words := domain.NormalizeWords(req.Words)
+		if len(words) == 0 || len(words) > domain.MaxBlockedWords {
+			return dto.Failure[*moderationDto.BlockedWordsDto](dto.NewError(constants.ValidationError,
+				fmt.Sprintf("A list needs between 1 and %d words", domain.MaxBlockedWords), map[string]string{"words": "out of range"}))
+		}
+		for _, word := range words {
+			if len([]rune(word)) > domain.MaxBlockedWordLength {
+				return dto.Failure[*moderationDto.BlockedWordsDto](dto.NewError(constants.ValidationError,
+					fmt.Sprintf("Words are at most %d characters", domain.MaxBlockedWordLength), map[string]string{"words": word}))
+			}
+		}
+
+		workspaceID, projectID, failure := s.project(req.ProjectID)
+		if failure != nil {
+			return dto.Failure[*moderationDto.BlockedWordsDto](*failure)
+		}
+
+		var list domain.BlockedWordList
+		err := s.db.Where("project_id = ?", projectID).First(&list).Error
+		switch {
+		case err == gorm.ErrRecordNotFound:
+			list = domain.BlockedWordList{WorkspaceID: workspaceID, ProjectID: projectID, Mode: mode, Words: words}
+			if err := s.db.Create(&list).Error; err != nil {
+				panic(err)
+			}
+		case err != nil:
+			panic(err)
+		default:
+			list.Mode = mode
+			list.Words = words
+			if err := s.db.Model(&list).Select("mode", "words").Updates(&list).Error; err != nil {
+				panic(err)
+			}
+		}
+		return dto.Success(s.mapper.BlockedWordsToDto(&list))
+	})
+}
+
+// DeleteBlockedWords removes the blocked-word list of a project
+func (s *ModerationService) DeleteBlockedWords(id string) *moderationDto.BlockedWordsResponse {
+	return moderationDto.NewBlockedWordsResponse(func() dto.Validation[*moderationDto.BlockedWordsDto] {
+		workspaceID, failure := s.workspace()
+		if failure != nil {
+			return dto.Failure[*moderationDto.BlockedWordsDto](*failure)
+		}
+
+		var list domain.BlockedWordList
+		if err := s.db.Where("id::text = ? AND workspace_id = ?", id, workspaceID).First(&list).Error; err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return dto.Invalid[*moderationDto.BlockedWordsDto](dto.NewError(constants.NotFoundError, "Blocked-word list not found", nil))
+			}
+			panic(err)
+		}
+		if err := s.db.Delete(&list).Error; err != nil {
+			panic(err)
+		}
+		return dto.Success(s.mapper.BlockedWordsToDto(&list))
+	})
+}
+
+// workspace returns the current workspace
+func (s *ModerationService) workspace() (string, *dto.Error) {
+	workspaceID, ok := sharedMiddleware.WorkspaceIDFromContext(s.db.Statement.Context)
+	if !ok {
+		err := dto.NewError(constants.ValidationError, "A current workspace is required", nil)
+		return "", &err
+	}
+	return workspaceID, nil
+}
+
+// project validates a project of the current workspace
+func (s *ModerationService) project(projectID string) (string, string, *dto.Error) {
+	workspaceID, failure := s.workspace()
+	if failure != nil {
+		return "", "", failure
+	}
+
+	var id string
+	if err := s.db.Table("projects").Select("id").Where("id::text = ? AND workspace_id = ?", projectID, workspaceID).Scan(&id).Error; err != nil {
+		panic(err)
+	}
+	if id == "" {
+		err := dto.NewError(constants.NotFoundError, "Project not found", nil)
+		return "", "", &err
+	}
+	return workspaceID, id, nil
+}
+
+// messageProject returns the project of the channel of a message, nil for direct messages and channels outside projects
+func (s *ModerationService) messageProject(message *messageDomain.Message) *string {
+	if message.ChannelID == nil {
+		return nil
+	}
+	var projectID string
+	if err := s.db.Table("channels").Select("project_id").Where("id = ?", *message.ChannelID).Scan(&projectID).Error; err != nil {
+		panic(err)
+	}
+	if projectID == "" {
+		return nil
+	}
+	return &projectID
+}
+
+// messageHeld reports whether a legal hold preserves the reported message
+func (s *ModerationService) messageHeld(report *domain.Report) bool {
+	scope := &retentionService.Scope{Level: retentionDomain.LevelProject, WorkspaceID: report.WorkspaceID}
+	if report.ChannelID != nil {
+		scope.Level = retentionDomain.LevelChannel
+		scope.ResourceID = *report.ChannelID
+	}
+	if report.ProjectID != nil {
+		scope.ProjectID = *report.ProjectID
+	}
+	held, err := retentionService.IsHeld(s.db, scope)
+	if err != nil {
+		panic(err)
+	}
+	return held
+}
+
+// checkSuspendable refuses suspending oneself, and lets only admins and managers suspend one of them
+func (s *ModerationService) checkSuspendable(auth *authority, userID string) *dto.Error {
+	if userID == auth.userID {
+		err := dto.NewError(constants.ValidationError, "You cannot suspend yourself", nil)
+		return &err
+	}
+	if auth.workspace {
+		return nil
+	}
+	role, err := sharedModels.GetUserRole(s.db, userID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		panic(err)
+	}
+	if err == nil && (role == sharedModels.RoleAdmin || role == sharedModels.RoleManager) {
+		failure := dto.NewError(constants.ForbiddenError, "Only admins and managers can suspend an admin or a manager", nil)
+		return &failure
+	}
+	return nil
+}
+
+// deleteMessage deletes a reported message with its files, and resolves the other open reports about it
+// A message already gone, deleted by its retention policy for instance, only has its reports resolved
+func deleteMessage(tx *gorm.DB, report *domain.Report) error {
+	if err := tx.Where("message_id = ?", report.MessageID).Delete(&messageDomain.File{}).Error; err != nil {
+		return err
+	}
+	if err := tx.Where("id = ?", report.MessageID).Delete(&messageDomain.Message{}).Error; err != nil {
+		return err
+	}
+	return tx.Model(&domain.Report{}).
+		Where("message_id = ? AND status = ? AND id <> ?", report.MessageID, domain.StatusOpen, report.ID).
+		Updates(map[string]interface{}{
+			"status":          domain.StatusMessageDeleted,
+			"resolution_note": report.ResolutionNote,
+			"resolved_by":     report.ResolvedBy,
+			"resolved_at":     report.ResolvedAt,
+		}).Error
+}
@@ -0,0 +1,28 @@
+package service
+
+import (
+	"context"
+
+	moderationDto "thothix-backend/internal/moderation/dto"
+)
+
+// ModerationServiceInterface defines the contract for message reports, moderator decisions and blocked-word lists using Response pattern
+type ModerationServiceInterface interface {
+	ReportMessage(req *moderationDto.ReportMessageRequest) *moderationDto.ReportResponse
+	ListReports(req *moderationDto.ReportListRequest) *moderationDto.ReportListResponse
+	ResolveReport(id string, req *moderationDto.ResolveReportRequest) *moderationDto.ReportResponse
+	ListActions(req *moderationDto.ActionListRequest) *moderationDto.ActionListResponse
+	ListSuspensions(includeInactive bool) *moderationDto.SuspensionListResponse
+	LiftSuspension(id string) *moderationDto.SuspensionResponse
+	ListModerators() *moderationDto.ModeratorListResponse
+	AddModerator(req *moderationDto.AddModeratorRequest) *moderationDto.ModeratorResponse
+	RemoveModerator(id string) *moderationDto.ModeratorResponse
+	ListBlockedWords() *moderationDto.BlockedWordsListResponse
+	SetBlockedWords(req *moderationDto.SetBlockedWordsRequest) *moderationDto.BlockedWordsResponse
+	DeleteBlockedWords(id string) *moderationDto.BlockedWordsResponse
+}
+
+// ContextAwareModerationService is implemented by services that can bind their database session to a request context
+type ContextAwareModerationService interface {
+	WithContext(ctx context.Context) *ModerationService
+}
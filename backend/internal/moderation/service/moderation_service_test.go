@@ -0,0 +1,288 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/gorm"
+	chatDomain "thothix-backend/internal/chat/domain"
+	messageDomain "thothix-backend/internal/message/domain"
+	"thothix-backend/internal/moderation/domain"
+	moderationDto "thothix-backend/internal/moderation/dto"
+	"thothix-backend/internal/moderation/screening"
+	projectDomain "thothix-backend/internal/project/domain"
+	retentionDomain "thothix-backend/internal/retention/domain"
+	"thothix-backend/internal/shared/dto"
+	sharedMiddleware "thothix-backend/internal/shared/middleware"
+	sharedModels "thothix-backend/internal/shared/models"
+	sharedTesting "thothix-backend/internal/shared/testing"
+	usersDomain "thothix-backend/internal/users/domain"
+	workspaceDomain "thothix-backend/internal/workspace/domain"
+)
+
+type ModerationServiceTestSuite struct {
+	suite.Suite
+	container *sharedTesting.PostgresTestContainer
+}
+
+func (suite *ModerationServiceTestSuite) SetupSuite() {
+	suite.container = sharedTesting.GetSharedTestContainer(
+		suite.T(),
+		"moderation/service",
+		[]interface{}{
+			&usersDomain.User{}, &sharedModels.UserRole{}, &workspaceDomain.Workspace{}, &projectDomain.Project{},
+			&chatDomain.Channel{}, &chatDomain.ChannelMember{}, &messageDomain.Message{}, &messageDomain.File{},
+			&retentionDomain.LegalHold{}, &domain.Report{}, &domain.Moderator{}, &domain.Suspension{},
+			&domain.Action{}, &domain.BlockedWordList{},
+		},
+	)
+}
+
+// fixture is a workspace with a project channel, where the author posted a message the reporter can read
+type fixture struct {
+	workspace *workspaceDomain.Workspace
+	project   *projectDomain.Project
+	channel   *chatDomain.Channel
+	author    *usersDomain.User
+	reporter  *usersDomain.User
+	message   *messageDomain.Message
+}
+
+func (suite *ModerationServiceTestSuite) createFixture(db *gorm.DB, slug string) *fixture {
+	workspace := &workspaceDomain.Workspace{Name: slug, Slug: slug}
+	assert.NoError(suite.T(), db.Create(workspace).Error)
+	project := &projectDomain.Project{WorkspaceID: workspace.ID, Name: slug}
+	assert.NoError(suite.T(), db.Create(project).Error)
+	channel := &chatDomain.Channel{WorkspaceID: workspace.ID, ProjectID: project.ID, Name: "general"}
+	assert.NoError(suite.T(), db.Create(channel).Error)
+
+	f := &fixture{workspace: workspace, project: project, channel: channel}
+	f.author = suite.createMember(db, slug+"-author", workspace, sharedModels.RoleUser)
+	f.reporter = suite.createMember(db, slug+"-reporter", workspace, sharedModels.RoleUser)
+	suite.joinChannel(db, channel, f.author, f.reporter)
+
+	f.message = &messageDomain.Message{SenderID: f.author.ID, ChannelID: &channel.ID, Content: "buy my course", PlainText: "buy my course"}
+	assert.NoError(suite.T(), db.Create(f.message).Error)
+	return f
+}
+
+// createMember stores a user with the given role in workspace
+func (suite *ModerationServiceTestSuite) createMember(db *gorm.DB, name string, workspace *workspaceDomain.Workspace, role sharedModels.RoleType) *usersDomain.User {
+	user := &usersDomain.User{Email: name + "@example.com", Name: name, SystemRole: sharedModels.RoleUser}
+	user.ID = uuid.New().String()
+	assert.NoError(suite.T(), db.Create(user).Error)
+
+	resourceType := sharedModels.ResourceTypeWorkspace
+	assert.NoError(suite.T(), db.Create(&sharedModels.UserRole{
+		UserID: user.ID, Role: role, ResourceType: &resourceType, ResourceID: &workspace.ID,
+	}).Error)
+	return user
+}
+
+func (suite *ModerationServiceTestSuite) joinChannel(db *gorm.DB, channel *chatDomain.Channel, users ...*usersDomain.User) {
+	for _, user := range users {
+		assert.NoError(suite.T(), db.Create(&chatDomain.ChannelMember{ChannelID: channel.ID, UserID: user.ID}).Error)
+	}
+}
+
+// createModerator stores a member who moderates project
+func (suite *ModerationServiceTestSuite) createModerator(db *gorm.DB, name string, workspace *workspaceDomain.Workspace, projectID string) *usersDomain.User {
+	user := suite.createMember(db, name, workspace, sharedModels.RoleUser)
+	assert.NoError(suite.T(), db.Create(&domain.Moderator{WorkspaceID: workspace.ID, ProjectID: projectID, UserID: user.ID}).Error)
+	return user
+}
+
+// contextFor returns a request context of the given user in workspace
+func (suite *ModerationServiceTestSuite) contextFor(userID string, workspace *workspaceDomain.Workspace) context.Context {
+	return sharedMiddleware.WithWorkspaceID(sharedMiddleware.WithUserID(context.Background(), userID), workspace.ID)
+}
+
+// serviceFor creates a service acting as the given user in workspace
+func (suite *ModerationServiceTestSuite) serviceFor(db *gorm.DB, userID string, workspace *workspaceDomain.Workspace) *ModerationService {
+	return NewModerationService(db).WithContext(suite.contextFor(userID, workspace))
+}
+
+func (suite *ModerationServiceTestSuite) report(db *gorm.DB, f *fixture, reporter *usersDomain.User) *moderationDto.ReportDto {
+	response := suite.serviceFor(db, reporter.ID, f.workspace).ReportMessage(&moderationDto.ReportMessageRequest{MessageID: f.message.ID, Reason: "spam"})
+	return sharedTesting.AssertSuccessWithValue(suite.T(), response.Response)
+}
+
+func (suite *ModerationServiceTestSuite) TestReportMessage_ReadersOnly() {
+	suite.container.WithTransaction(func(db *gorm.DB) {
+		// Arrange
+		f := suite.createFixture(db, "report-readers")
+		outsider := suite.createMember(db, "report-readers-outsider", f.workspace, sharedModels.RoleUser)
+		request := &moderationDto.ReportMessageRequest{MessageID: f.message.ID, Reason: "spam", Details: "selling"}
+
+		// Act
+		byOutsider := suite.serviceFor(db, outsider.ID, f.workspace).ReportMessage(request)
+		byAuthor := suite.serviceFor(db, f.author.ID, f.workspace).ReportMessage(request)
+		byReporter := suite.serviceFor(db, f.reporter.ID, f.workspace).ReportMessage(request)
+		again := suite.serviceFor(db, f.reporter.ID, f.workspace).ReportMessage(request)
+
+		// Assert
+		sharedTesting.AssertValidationErrorWithCode(suite.T(), byOutsider.Response, "NOT_FOUND")
+		sharedTesting.AssertValidationErrorWithCode(suite.T(), byAuthor.Response, "VALIDATION_ERROR")
+		report := sharedTesting.AssertSuccessWithValue(suite.T(), byReporter.Response)
+		assert.Equal(suite.T(), f.author.ID, report.AuthorID)
+		assert.Equal(suite.T(), f.project.ID, *report.ProjectID)
+		assert.Equal(suite.T(), "buy my course", report.Excerpt)
+		sharedTesting.AssertValidationErrorWithCode(suite.T(), again.Response, "CONFLICT")
+	})
+}
+
+func (suite *ModerationServiceTestSuite) TestListReports_ScopedToModeratedProjects() {
+	suite.container.WithTransaction(func(db *gorm.DB) {
+		// Arrange
+		f := suite.createFixture(db, "queue-scope")
+		suite.report(db, f, f.reporter)
+		other := &projectDomain.Project{WorkspaceID: f.workspace.ID, Name: "other"}
+		assert.NoError(suite.T(), db.Create(other).Error)
+		moderator := suite.createModerator(db, "queue-scope-moderator", f.workspace, f.project.ID)
+		otherModerator := suite.createModerator(db, "queue-scope-other", f.workspace, other.ID)
+		manager := suite.createMember(db, "queue-scope-manager", f.workspace, sharedModels.RoleManager)
+		page := &moderationDto.ReportListRequest{PaginationRequest: dto.PaginationRequest{Page: 1, PerPage: 20}}
+
+		// Act & Assert
+		sharedTesting.AssertValidationErrorWithCode(suite.T(), suite.serviceFor(db, f.reporter.ID, f.workspace).ListReports(page).Response, "FORBIDDEN")
+		sharedTesting.AssertPaginatedCount(suite.T(), suite.serviceFor(db, moderator.ID, f.workspace).ListReports(page).Response, 1)
+		sharedTesting.AssertPaginatedCount(suite.T(), suite.serviceFor(db, otherModerator.ID, f.workspace).ListReports(page).Response, 0)
+		sharedTesting.AssertPaginatedCount(suite.T(), suite.serviceFor(db, manager.ID, f.workspace).ListReports(page).Response, 1)
+	})
+}
+
+func (suite *ModerationServiceTestSuite) TestResolveReport_DeleteMessageResolvesEveryReport() {
+	suite.container.WithTransaction(func(db *gorm.DB) {
+		// Arrange
+		f := suite.createFixture(db, "resolve-delete")
+		second := suite.createMember(db, "resolve-delete-second", f.workspace, sharedModels.RoleUser)
+		suite.joinChannel(db, f.channel, second)
+		first := suite.report(db, f, f.reporter)
+		suite.report(db, f, second)
+		moderator := suite.createModerator(db, "resolve-delete-moderator", f.workspace, f.project.ID)
+		service := suite.serviceFor(db, moderator.ID, f.workspace)
+
+		// Act
+		response := service.ResolveReport(first.ID, &moderationDto.ResolveReportRequest{Action: "delete_message", Note: "spam"})
+
+		// Assert
+		resolved := sharedTesting.AssertSuccessWithValue(suite.T(), response.Response)
+		assert.Equal(suite.T(), "message_deleted", resolved.Status)
+		assert.Equal(suite.T(), moderator.ID, *resolved.ResolvedBy)
+		var messages int64
+		assert.NoError(suite.T(), db.Model(&messageDomain.Message{}).Where("id = ?", f.message.ID).Count(&messages).Error)
+		assert.Zero(suite.T(), messages)
+		var open int64
+		assert.NoError(suite.T(), db.Model(&domain.Report{}).Where("message_id = ? AND status = ?", f.message.ID, domain.StatusOpen).Count(&open).Error)
+		assert.Zero(suite.T(), open)
+		log := sharedTesting.AssertSuccessWithValue(suite.T(), service.ListActions(&moderationDto.ActionListRequest{PaginationRequest: dto.PaginationRequest{Page: 1, PerPage: 20}}).Response)
+		assert.Len(suite.T(), log.Items, 1)
+		assert.Equal(suite.T(), "delete_message", log.Items[0].Type)
+		sharedTesting.AssertValidationErrorWithCode(suite.T(), service.ResolveReport(first.ID, &moderationDto.ResolveReportRequest{Action: "dismiss"}).Response, "CONFLICT")
+	})
+}
+
+func (suite *ModerationServiceTestSuite) TestResolveReport_LegalHoldKeepsMessage() {
+	suite.container.WithTransaction(func(db *gorm.DB) {
+		// Arrange
+		f := suite.createFixture(db, "resolve-held")
+		report := suite.report(db, f, f.reporter)
+		assert.NoError(suite.T(), db.Create(&retentionDomain.LegalHold{WorkspaceID: f.workspace.ID, Level: retentionDomain.LevelProject, ResourceID: f.project.ID}).Error)
+		manager := suite.createMember(db, "resolve-held-manager", f.workspace, sharedModels.RoleManager)
+
+		// Act
+		response := suite.serviceFor(db, manager.ID, f.workspace).ResolveReport(report.ID, &moderationDto.ResolveReportRequest{Action: "delete_message"})
+
+		// Assert
+		sharedTesting.AssertValidationErrorWithCode(suite.T(), response.Response, "CONFLICT")
+		var messages int64
+		assert.NoError(suite.T(), db.Model(&messageDomain.Message{}).Where("id = ?", f.message.ID).Count(&messages).Error)
+		assert.Equal(suite.T(), int64(1), messages)
+	})
+}
+
+func (suite *ModerationServiceTestSuite) TestResolveReport_ModeratorSuspendsInProject() {
+	suite.container.WithTransaction(func(db *gorm.DB) {
+		// Arrange
+		f := suite.createFixture(db, "resolve-suspend")
+		report := suite.report(db, f, f.reporter)
+		moderator := suite.createModerator(db, "resolve-suspend-moderator", f.workspace, f.project.ID)
+		service := suite.serviceFor(db, moderator.ID, f.workspace)
+		hours := 24
+		authorDB := db.WithContext(suite.contextFor(f.author.ID, f.workspace))
+
+		// Act
+		response := service.ResolveReport(report.ID, &moderationDto.ResolveReportRequest{Action: "suspend_author", DurationHours: &hours})
+
+		// Assert
+		assert.Equal(suite.T(), "author_suspended", sharedTesting.AssertSuccessWithValue(suite.T(), response.Response).Status)
+		inProject, err := screening.ActiveSuspension(authorDB, f.author.ID, f.project.ID)
+		assert.NoError(suite.T(), err)
+		assert.NotNil(suite.T(), inProject)
+		assert.NotNil(suite.T(), inProject.ExpiresAt)
+		inWorkspace, err := screening.ActiveSuspension(authorDB, f.author.ID, "")
+		assert.NoError(suite.T(), err)
+		assert.Nil(suite.T(), inWorkspace, "project moderators do not suspend in direct messages")
+
+		lifted := service.LiftSuspension(inProject.ID)
+		assert.False(suite.T(), sharedTesting.AssertSuccessWithValue(suite.T(), lifted.Response).Active)
+		afterLift, err := screening.ActiveSuspension(authorDB, f.author.ID, f.project.ID)
+		assert.NoError(suite.T(), err)
+		assert.Nil(suite.T(), afterLift)
+		sharedTesting.AssertPaginatedCount(suite.T(), service.ListActions(&moderationDto.ActionListRequest{PaginationRequest: dto.PaginationRequest{Page: 1, PerPage: 20}}).Response, 2)
+	})
+}
+
+func (suite *ModerationServiceTestSuite) TestResolveReport_ModeratorCannotSuspendManager() {
+	suite.container.WithTransaction(func(db *gorm.DB) {
+		// Arrange
+		f := suite.createFixture(db, "suspend-manager")
+		manager := suite.createMember(db, "suspend-manager-manager", f.workspace, sharedModels.RoleManager)
+		f.message.SenderID = manager.ID
+		assert.NoError(suite.T(), db.Model(f.message).Update("sender_id", manager.ID).Error)
+		report := suite.report(db, f, f.reporter)
+		moderator := suite.createModerator(db, "suspend-manager-moderator", f.workspace, f.project.ID)
+
+		// Act
+		response := suite.serviceFor(db, moderator.ID, f.workspace).ResolveReport(report.ID, &moderationDto.ResolveReportRequest{Action: "suspend_author"})
+
+		// Assert
+		sharedTesting.AssertValidationErrorWithCode(suite.T(), response.Response, "FORBIDDEN")
+	})
+}
+
+func (suite *ModerationServiceTestSuite) TestBlockedWords_CheckAndFlag() {
+	suite.container.WithTransaction(func(db *gorm.DB) {
+		// Arrange
+		f := suite.createFixture(db, "blocked-words")
+		manager := suite.createMember(db, "blocked-words-manager", f.workspace, sharedModels.RoleManager)
+		set := suite.serviceFor(db, manager.ID, f.workspace).SetBlockedWords(&moderationDto.SetBlockedWordsRequest{
+			ProjectID: f.project.ID, Mode: "flag", Words: []string{"Course", "course", "free money"},
+		})
+		authorDB := db.WithContext(suite.contextFor(f.author.ID, f.workspace))
+
+		// Act
+		verdict, err := screening.Check(authorDB, f.project.ID, f.message.PlainText)
+		assert.NoError(suite.T(), err)
+		assert.NoError(suite.T(), screening.Flag(authorDB, f.message, f.project.ID, verdict))
+		elsewhere, err := screening.Check(authorDB, uuid.New().String(), f.message.PlainText)
+
+		// Assert
+		assert.Equal(suite.T(), []string{"course", "free money"}, sharedTesting.AssertSuccessWithValue(suite.T(), set.Response).Words)
+		assert.False(suite.T(), verdict.Rejects())
+		assert.Equal(suite.T(), []string{"course"}, verdict.Words)
+		assert.NoError(suite.T(), err)
+		assert.Nil(suite.T(), elsewhere)
+		var flagged domain.Report
+		assert.NoError(suite.T(), db.Where("message_id = ?", f.message.ID).First(&flagged).Error)
+		assert.Equal(suite.T(), domain.ReasonBlockedWords, flagged.Reason)
+		assert.Nil(suite.T(), flagged.ReporterID)
+	})
+}
+
+func TestModerationServiceTestSuite(t *testing.T) {
+	suite.Run(t, new(ModerationServiceTestSuite))
+}
@@ -0,0 +1,80 @@
+package domain
+
+import (
+	"sort"
+	"time"
+)
+
+// Status is the presence shown next to a user
+type Status string
+
+const (
+	StatusOnline  Status = "online"  // At least one connection is active
+	StatusAway    Status = "away"    // Connected, but every connection reports the user idle
+	StatusOffline Status = "offline" // No connection sent a heartbeat recently
+)
+
+// Connection is one client connection, such as a browser tab, reporting presence through heartbeats
+type Connection struct {
+	UserID       string    `gorm:"primaryKey"`
+	ConnectionID string    `gorm:"primaryKey"`
+	Status       Status    `gorm:"not null"`
+	LastSeenAt   time.Time `gorm:"not null;index"`
+}
+
+// TableName specifies the table name for the Connection model
+func (Connection) TableName() string {
+	return "presence_connections"
+}
+
+// TypingIndicator records that a user is typing in a channel or direct conversation until it expires
+type TypingIndicator struct {
+	Scope     string    `gorm:"primaryKey"` // See ChannelScope and DirectScope
+	UserID    string    `gorm:"primaryKey"`
+	ExpiresAt time.Time `gorm:"not null;index"`
+}
+
+// TableName specifies the table name for the TypingIndicator model
+func (TypingIndicator) TableName() string {
+	return "typing_indicators"
+}
+
+// Presence is the status of a user resolved from their connections
+type Presence struct {
+	UserID     string
+	Status     Status
+	LastSeenAt *time.Time // Last heartbeat of any connection, nil when none is known
+}
+
+// Resolve combines the connections of a user: online if any active connection is online, away if
+// every active connection is away, offline when no connection sent a heartbeat within timeout
+func Resolve(userID string, connections []Connection, now time.Time, timeout time.Duration) Presence {
+	presence := Presence{UserID: userID, Status: StatusOffline}
+	for _, connection := range connections {
+		if presence.LastSeenAt == nil || connection.LastSeenAt.After(*presence.LastSeenAt) {
+			lastSeen := connection.LastSeenAt
+			presence.LastSeenAt = &lastSeen
+		}
+		if now.Sub(connection.LastSeenAt) > timeout {
+			continue
+		}
+		if connection.Status == StatusOnline {
+			presence.Status = StatusOnline
+		} else if presence.Status == StatusOffline {
+			presence.Status = StatusAway
+		}
+	}
+	return presence
+}
+
+// ChannelScope is the typing scope of a channel
+func ChannelScope(channelID string) string {
+	return "channel:" + channelID
+}
+
+// DirectScope is the typing scope of the direct conversation between two users, the same from both sides
+func DirectScope(userID, otherUserID string) string {
+	users := []string{userID, otherUserID}
+	sort.Strings(users)
+	return "dm:" + users[0] + ":" + users[1]
+}
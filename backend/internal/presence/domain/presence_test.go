@@ -0,0 +1,49 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResolve(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	timeout := 90 * time.Second
+	recent, stale := now.Add(-10*time.Second), now.Add(-5*time.Minute)
+
+	tests := []struct {
+		name        string
+		connections []Connection
+		want        Status
+	}{
+		{"no connections", nil, StatusOffline},
+		{"online tab wins over away tab", []Connection{{Status: StatusAway, LastSeenAt: recent}, {Status: StatusOnline, LastSeenAt: recent}}, StatusOnline},
+		{"all tabs away", []Connection{{Status: StatusAway, LastSeenAt: recent}}, StatusAway},
+		{"stale online tab is ignored", []Connection{{Status: StatusOnline, LastSeenAt: stale}, {Status: StatusAway, LastSeenAt: recent}}, StatusAway},
+		{"only stale tabs", []Connection{{Status: StatusOnline, LastSeenAt: stale}}, StatusOffline},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, Resolve("user-1", tt.connections, now, timeout).Status)
+		})
+	}
+}
+
+func TestResolve_LastSeenIsLatestHeartbeat(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	latest := now.Add(-time.Hour)
+
+	presence := Resolve("user-1", []Connection{
+		{Status: StatusOnline, LastSeenAt: now.Add(-2 * time.Hour)},
+		{Status: StatusAway, LastSeenAt: latest},
+	}, now, 90*time.Second)
+
+	assert.Equal(t, StatusOffline, presence.Status)
+	assert.Equal(t, latest, *presence.LastSeenAt)
+}
+
+func TestDirectScope_IsSymmetric(t *testing.T) {
+	assert.Equal(t, DirectScope("user-a", "user-b"), DirectScope("user-b", "user-a"))
+	assert.NotEqual(t, DirectScope("user-a", "user-b"), DirectScope("user-a", "user-c"))
+}
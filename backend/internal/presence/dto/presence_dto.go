@@ -0,0 +1,73 @@
+package dto
+
+import (
+	"thothix-backend/internal/shared/dto"
+)
+
+// === Presence DTOs ===
+
+// PresenceDto represents the status of a user in API responses
+type PresenceDto struct {
+	UserID     string `json:"user_id"`
+	Status     string `json:"status"` // online, away or offline
+	LastSeenAt string `json:"last_seen_at,omitempty"`
+}
+
+// HeartbeatRequest reports that a client connection is alive
+// Clients send one every 30 seconds and whenever the user goes idle or comes back
+type HeartbeatRequest struct {
+	ConnectionID string `json:"connection_id" binding:"required,max=64"`
+	Status       string `json:"status" binding:"omitempty,oneof=online away"` // Defaults to online
+}
+
+// PresenceQueryRequest asks for the status of several users, such as a member list
+type PresenceQueryRequest struct {
+	UserIDs []string `json:"user_ids" binding:"required"`
+}
+
+// PresenceResponse wraps a single PresenceDto response
+type PresenceResponse struct {
+	*dto.Response[*PresenceDto]
+}
+
+func NewPresenceResponse(producer func() dto.Validation[*PresenceDto]) *PresenceResponse {
+	return &PresenceResponse{
+		Response: dto.NewResponse(producer),
+	}
+}
+
+// PresenceListResponse wraps a list of PresenceDto
+type PresenceListResponse struct {
+	*dto.Response[[]PresenceDto]
+}
+
+func NewPresenceListResponse(producer func() dto.Validation[[]PresenceDto]) *PresenceListResponse {
+	return &PresenceListResponse{
+		Response: dto.NewResponse(producer),
+	}
+}
+
+// === Typing DTOs ===
+
+// TypingTarget is the conversation a typing indicator belongs to: a channel or a direct conversation
+type TypingTarget struct {
+	ChannelID   string // Set for a channel
+	RecipientID string // Set for the direct conversation with this user
+}
+
+// TypingDto represents a user typing in a conversation
+type TypingDto struct {
+	UserID    string `json:"user_id"`
+	ExpiresAt string `json:"expires_at"`
+}
+
+// TypingListResponse wraps the users typing in a conversation
+type TypingListResponse struct {
+	*dto.Response[[]TypingDto]
+}
+
+func NewTypingListResponse(producer func() dto.Validation[[]TypingDto]) *TypingListResponse {
+	return &TypingListResponse{
+		Response: dto.NewResponse(producer),
+	}
+}
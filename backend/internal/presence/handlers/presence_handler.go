@@ -0,0 +1,279 @@
+package handlers
+
+import (
+	"github.com/gin-gonic/gin"
+
+	presenceDto "thothix-backend/internal/presence/dto"
+	"thothix-backend/internal/presence/service"
+	"thothix-backend/internal/shared/constants"
+	"thothix-backend/internal/shared/dto"
+	"thothix-backend/internal/shared/handlers"
+)
+
+type PresenceHandler struct {
+	presenceService service.PresenceServiceInterface
+}
+
+func NewPresenceHandler(presenceService service.PresenceServiceInterface) *PresenceHandler {
+	return &PresenceHandler{
+		presenceService: presenceService,
+	}
+}
+
+// Heartbeat godoc
+// @Summary Report a live connection
+// @Description Record that a client connection is alive, online or away. Clients send one every 30 seconds; a user with no heartbeat for 90 seconds is offline
+// @Tags presence
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param heartbeat body presenceDto.HeartbeatRequest true "Connection and status"
+// @Success 200 {object} presenceDto.PresenceDto
+// @Failure 400 {object} dto.ErrorViewModel
+// @Failure 500 {object} dto.ErrorViewModel
+// @Router /presence/heartbeat [post]
+func (h *PresenceHandler) Heartbeat(c *gin.Context) {
+	var request presenceDto.HeartbeatRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		handlers.WrapContext(c).BadRequestErrorResponse("Invalid request payload")
+		return
+	}
+
+	h.respondWithPresence(c, h.scopedService(c).Heartbeat(&request), "record heartbeat")
+}
+
+// Disconnect godoc
+// @Summary Close a connection
+// @Description Forget a client connection, such as a closed tab, so the user's status updates without waiting for the timeout
+// @Tags presence
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param connectionId path string true "Connection ID"
+// @Success 200 {object} presenceDto.PresenceDto
+// @Failure 400 {object} dto.ErrorViewModel
+// @Failure 500 {object} dto.ErrorViewModel
+// @Router /presence/connections/{connectionId} [delete]
+func (h *PresenceHandler) Disconnect(c *gin.Context) {
+	h.respondWithPresence(c, h.scopedService(c).Disconnect(c.Param("connectionId")), "close connection")
+}
+
+// GetPresence godoc
+// @Summary Get the presence of several users
+// @Description Get the status of up to 200 users, such as a member list; users outside the current workspace are left out
+// @Tags presence
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param query body presenceDto.PresenceQueryRequest true "User IDs"
+// @Success 200 {array} presenceDto.PresenceDto
+// @Failure 400 {object} dto.ErrorViewModel
+// @Failure 500 {object} dto.ErrorViewModel
+// @Router /presence/query [post]
+func (h *PresenceHandler) GetPresence(c *gin.Context) {
+	wrapper := handlers.WrapContext(c)
+
+	var request presenceDto.PresenceQueryRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		wrapper.BadRequestErrorResponse("Invalid request payload")
+		return
+	}
+
+	response := h.scopedService(c).GetPresence(&request)
+
+	response.Match(
+		// Exception case
+		func(err error) interface{} {
+			wrapper.SystemErrorResponse(err, "Failed to retrieve presence")
+			return nil
+		},
+		// Success case
+		func(result []presenceDto.PresenceDto) interface{} {
+			wrapper.SuccessResponse(result)
+			return nil
+		},
+		// Validation failure case
+		func(errors []dto.Error) interface{} {
+			wrapper.ValidationErrorResponse(errors, "Presence query validation failed")
+			return nil
+		},
+	)
+}
+
+// StartChannelTyping godoc
+// @Summary Start typing in a channel
+// @Description Mark the current user as typing in a channel for 6 seconds; repeat while typing. Returns the other users typing there
+// @Tags presence
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Channel ID"
+// @Success 200 {array} presenceDto.TypingDto
+// @Failure 403 {object} dto.ErrorViewModel
+// @Failure 404 {object} dto.ErrorViewModel
+// @Failure 500 {object} dto.ErrorViewModel
+// @Router /presence/typing/channels/{id} [post]
+func (h *PresenceHandler) StartChannelTyping(c *gin.Context) {
+	target := presenceDto.TypingTarget{ChannelID: c.Param("id")}
+	h.respondWithTyping(c, h.scopedService(c).StartTyping(target), "start typing", target)
+}
+
+// StopChannelTyping godoc
+// @Summary Stop typing in a channel
+// @Description Clear the current user's typing indicator in a channel. Returns the other users typing there
+// @Tags presence
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Channel ID"
+// @Success 200 {array} presenceDto.TypingDto
+// @Failure 403 {object} dto.ErrorViewModel
+// @Failure 404 {object} dto.ErrorViewModel
+// @Failure 500 {object} dto.ErrorViewModel
+// @Router /presence/typing/channels/{id} [delete]
+func (h *PresenceHandler) StopChannelTyping(c *gin.Context) {
+	target := presenceDto.TypingTarget{ChannelID: c.Param("id")}
+	h.respondWithTyping(c, h.scopedService(c).StopTyping(target), "stop typing", target)
+}
+
+// GetChannelTyping godoc
+// @Summary List users typing in a channel
+// @Description List the other users typing in a channel
+// @Tags presence
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Channel ID"
+// @Success 200 {array} presenceDto.TypingDto
+// @Failure 403 {object} dto.ErrorViewModel
+// @Failure 404 {object} dto.ErrorViewModel
+// @Failure 500 {object} dto.ErrorViewModel
+// @Router /presence/typing/channels/{id} [get]
+func (h *PresenceHandler) GetChannelTyping(c *gin.Context) {
+	target := presenceDto.TypingTarget{ChannelID: c.Param("id")}
+	h.respondWithTyping(c, h.scopedService(c).GetTyping(target), "list typing users", target)
+}
+
+// StartDirectTyping godoc
+// @Summary Start typing to a user
+// @Description Mark the current user as typing in the direct conversation with a user for 6 seconds; repeat while typing
+// @Tags presence
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param userId path string true "Recipient user ID"
+// @Success 200 {array} presenceDto.TypingDto
+// @Failure 403 {object} dto.ErrorViewModel
+// @Failure 404 {object} dto.ErrorViewModel
+// @Failure 500 {object} dto.ErrorViewModel
+// @Router /presence/typing/direct/{userId} [post]
+func (h *PresenceHandler) StartDirectTyping(c *gin.Context) {
+	target := presenceDto.TypingTarget{RecipientID: c.Param("userId")}
+	h.respondWithTyping(c, h.scopedService(c).StartTyping(target), "start typing", target)
+}
+
+// StopDirectTyping godoc
+// @Summary Stop typing to a user
+// @Description Clear the current user's typing indicator in the direct conversation with a user
+// @Tags presence
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param userId path string true "Recipient user ID"
+// @Success 200 {array} presenceDto.TypingDto
+// @Failure 404 {object} dto.ErrorViewModel
+// @Failure 500 {object} dto.ErrorViewModel
+// @Router /presence/typing/direct/{userId} [delete]
+func (h *PresenceHandler) StopDirectTyping(c *gin.Context) {
+	target := presenceDto.TypingTarget{RecipientID: c.Param("userId")}
+	h.respondWithTyping(c, h.scopedService(c).StopTyping(target), "stop typing", target)
+}
+
+// GetDirectTyping godoc
+// @Summary Check whether a user is typing to me
+// @Description List the typing indicator of the other user in a direct conversation
+// @Tags presence
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param userId path string true "Other user ID"
+// @Success 200 {array} presenceDto.TypingDto
+// @Failure 404 {object} dto.ErrorViewModel
+// @Failure 500 {object} dto.ErrorViewModel
+// @Router /presence/typing/direct/{userId} [get]
+func (h *PresenceHandler) GetDirectTyping(c *gin.Context) {
+	target := presenceDto.TypingTarget{RecipientID: c.Param("userId")}
+	h.respondWithTyping(c, h.scopedService(c).GetTyping(target), "list typing users", target)
+}
+
+// respondWithPresence maps the presence of the current user to an HTTP response
+func (h *PresenceHandler) respondWithPresence(c *gin.Context, response *presenceDto.PresenceResponse, action string) {
+	wrapper := handlers.WrapContext(c)
+
+	response.Match(
+		// Exception case
+		func(err error) interface{} {
+			wrapper.SystemErrorResponse(err, "Failed to %s", action)
+			return nil
+		},
+		// Success case
+		func(result *presenceDto.PresenceDto) interface{} {
+			wrapper.SuccessResponse(result)
+			return nil
+		},
+		// Validation failure case
+		func(errors []dto.Error) interface{} {
+			if len(errors) > 0 && errors[0].Code == constants.UnauthorizedError {
+				wrapper.UnauthorizedErrorResponse(errors[0].Message)
+			} else {
+				wrapper.ValidationErrorResponse(errors, "Failed to %s", action)
+			}
+			return nil
+		},
+	)
+}
+
+// respondWithTyping maps the users typing in a conversation to an HTTP response
+func (h *PresenceHandler) respondWithTyping(c *gin.Context, response *presenceDto.TypingListResponse, action string, target presenceDto.TypingTarget) {
+	wrapper := handlers.WrapContext(c)
+	resource, identifier := "Channel", target.ChannelID
+	if target.RecipientID != "" {
+		resource, identifier = "Recipient", target.RecipientID
+	}
+
+	response.Match(
+		// Exception case
+		func(err error) interface{} {
+			wrapper.SystemErrorResponse(err, "Failed to %s for %s: %s", action, resource, identifier)
+			return nil
+		},
+		// Success case
+		func(result []presenceDto.TypingDto) interface{} {
+			wrapper.SuccessResponse(result)
+			return nil
+		},
+		// Validation failure case
+		func(errors []dto.Error) interface{} {
+			switch {
+			case len(errors) > 0 && errors[0].Code == constants.NotFoundError:
+				wrapper.NotFoundErrorResponse(resource, identifier)
+			case len(errors) > 0 && errors[0].Code == constants.ForbiddenError:
+				wrapper.ForbiddenErrorResponse(errors[0].Message)
+			case len(errors) > 0 && errors[0].Code == constants.UnauthorizedError:
+				wrapper.UnauthorizedErrorResponse(errors[0].Message)
+			default:
+				wrapper.ValidationErrorResponse(errors, "Failed to %s for %s: %s", action, resource, identifier)
+			}
+			return nil
+		},
+	)
+}
+
+// scopedService binds the service to the request context when supported,
+// so presence is resolved for the current user and workspace
+func (h *PresenceHandler) scopedService(c *gin.Context) service.PresenceServiceInterface {
+	if aware, ok := h.presenceService.(service.ContextAwarePresenceService); ok {
+		return aware.WithContext(c.Request.Context())
+	}
+	return h.presenceService
+}
@@ -0,0 +1,163 @@
+package handlers
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	presenceDto "thothix-backend/internal/presence/dto"
+	"thothix-backend/internal/shared/dto"
+)
+
+// MockPresenceService is a mock implementation of the PresenceService
+type MockPresenceService struct {
+	mock.Mock
+}
+
+func (m *MockPresenceService) Heartbeat(req *presenceDto.HeartbeatRequest) *presenceDto.PresenceResponse {
+	args := m.Called(req)
+	return args.Get(0).(*presenceDto.PresenceResponse)
+}
+
+func (m *MockPresenceService) Disconnect(connectionID string) *presenceDto.PresenceResponse {
+	args := m.Called(connectionID)
+	return args.Get(0).(*presenceDto.PresenceResponse)
+}
+
+func (m *MockPresenceService) GetPresence(req *presenceDto.PresenceQueryRequest) *presenceDto.PresenceListResponse {
+	args := m.Called(req)
+	return args.Get(0).(*presenceDto.PresenceListResponse)
+}
+
+func (m *MockPresenceService) StartTyping(target presenceDto.TypingTarget) *presenceDto.TypingListResponse {
+	args := m.Called(target)
+	return args.Get(0).(*presenceDto.TypingListResponse)
+}
+
+func (m *MockPresenceService) StopTyping(target presenceDto.TypingTarget) *presenceDto.TypingListResponse {
+	args := m.Called(target)
+	return args.Get(0).(*presenceDto.TypingListResponse)
+}
+
+func (m *MockPresenceService) GetTyping(target presenceDto.TypingTarget) *presenceDto.TypingListResponse {
+	args := m.Called(target)
+	return args.Get(0).(*presenceDto.TypingListResponse)
+}
+
+type PresenceHandlerTestSuite struct {
+	suite.Suite
+	mockService *MockPresenceService
+	router      *gin.Engine
+}
+
+func (suite *PresenceHandlerTestSuite) SetupSuite() {
+	gin.SetMode(gin.TestMode)
+}
+
+func (suite *PresenceHandlerTestSuite) SetupTest() {
+	suite.mockService = new(MockPresenceService)
+	handler := NewPresenceHandler(suite.mockService)
+
+	suite.router = gin.New()
+	suite.router.POST("/presence/heartbeat", handler.Heartbeat)
+	suite.router.POST("/presence/query", handler.GetPresence)
+	suite.router.POST("/presence/typing/channels/:id", handler.StartChannelTyping)
+	suite.router.GET("/presence/typing/direct/:userId", handler.GetDirectTyping)
+}
+
+func (suite *PresenceHandlerTestSuite) TestHeartbeat_Success() {
+	// Arrange
+	mockResponse := presenceDto.NewPresenceResponse(func() dto.Validation[*presenceDto.PresenceDto] {
+		return dto.Success(&presenceDto.PresenceDto{UserID: "user-1", Status: "away"})
+	})
+
+	suite.mockService.On("Heartbeat", &presenceDto.HeartbeatRequest{ConnectionID: "tab-1", Status: "away"}).Return(mockResponse)
+
+	// Act
+	req, _ := http.NewRequest("POST", "/presence/heartbeat", bytes.NewBufferString(`{"connection_id":"tab-1","status":"away"}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(suite.T(), http.StatusOK, w.Code)
+	assert.Contains(suite.T(), w.Body.String(), `"status":"away"`)
+}
+
+func (suite *PresenceHandlerTestSuite) TestHeartbeat_InvalidStatus() {
+	// Act
+	req, _ := http.NewRequest("POST", "/presence/heartbeat", bytes.NewBufferString(`{"connection_id":"tab-1","status":"busy"}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(suite.T(), http.StatusBadRequest, w.Code)
+	suite.mockService.AssertNotCalled(suite.T(), "Heartbeat", mock.Anything)
+}
+
+func (suite *PresenceHandlerTestSuite) TestGetPresence_Success() {
+	// Arrange
+	mockResponse := presenceDto.NewPresenceListResponse(func() dto.Validation[[]presenceDto.PresenceDto] {
+		return dto.Success([]presenceDto.PresenceDto{
+			{UserID: "user-1", Status: "online"},
+			{UserID: "user-2", Status: "offline"},
+		})
+	})
+
+	suite.mockService.On("GetPresence", &presenceDto.PresenceQueryRequest{UserIDs: []string{"user-1", "user-2"}}).Return(mockResponse)
+
+	// Act
+	req, _ := http.NewRequest("POST", "/presence/query", bytes.NewBufferString(`{"user_ids":["user-1","user-2"]}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(suite.T(), http.StatusOK, w.Code)
+	assert.Contains(suite.T(), w.Body.String(), `"status":"online"`)
+	assert.Contains(suite.T(), w.Body.String(), `"status":"offline"`)
+}
+
+func (suite *PresenceHandlerTestSuite) TestStartChannelTyping_Forbidden() {
+	// Arrange
+	mockResponse := presenceDto.NewTypingListResponse(func() dto.Validation[[]presenceDto.TypingDto] {
+		return dto.Failure[[]presenceDto.TypingDto](dto.NewError("FORBIDDEN", "Access denied to this channel", nil))
+	})
+
+	suite.mockService.On("StartTyping", presenceDto.TypingTarget{ChannelID: "channel-1"}).Return(mockResponse)
+
+	// Act
+	req, _ := http.NewRequest("POST", "/presence/typing/channels/channel-1", nil)
+	w := httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(suite.T(), http.StatusForbidden, w.Code)
+}
+
+func (suite *PresenceHandlerTestSuite) TestGetDirectTyping_RecipientNotFound() {
+	// Arrange
+	mockResponse := presenceDto.NewTypingListResponse(func() dto.Validation[[]presenceDto.TypingDto] {
+		return dto.Failure[[]presenceDto.TypingDto](dto.NewError("NOT_FOUND", "Recipient not found", nil))
+	})
+
+	suite.mockService.On("GetTyping", presenceDto.TypingTarget{RecipientID: "user-9"}).Return(mockResponse)
+
+	// Act
+	req, _ := http.NewRequest("GET", "/presence/typing/direct/user-9", nil)
+	w := httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(suite.T(), http.StatusNotFound, w.Code)
+}
+
+func TestPresenceHandlerTestSuite(t *testing.T) {
+	suite.Run(t, new(PresenceHandlerTestSuite))
+}
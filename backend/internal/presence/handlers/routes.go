@@ -0,0 +1,28 @@
+package handlers
+
+import (
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"thothix-backend/internal/presence/service"
+	"thothix-backend/internal/presence/store"
+)
+
+// RegisterPresenceRoutes registers the presence and typing routes of the current workspace
+// Clients poll them until a push channel is available
+func RegisterPresenceRoutes(router *gin.RouterGroup, db *gorm.DB, presenceStore store.Store) {
+	presenceService := service.NewPresenceService(db, presenceStore)
+	presenceHandler := NewPresenceHandler(presenceService)
+
+	presence := router.Group("/presence")
+	presence.POST("/heartbeat", presenceHandler.Heartbeat)
+	presence.DELETE("/connections/:connectionId", presenceHandler.Disconnect)
+	presence.POST("/query", presenceHandler.GetPresence)
+
+	typing := presence.Group("/typing")
+	typing.GET("/channels/:id", presenceHandler.GetChannelTyping)
+	typing.POST("/channels/:id", presenceHandler.StartChannelTyping)
+	typing.DELETE("/channels/:id", presenceHandler.StopChannelTyping)
+	typing.GET("/direct/:userId", presenceHandler.GetDirectTyping)
+	typing.POST("/direct/:userId", presenceHandler.StartDirectTyping)
+	typing.DELETE("/direct/:userId", presenceHandler.StopDirectTyping)
+}
@@ -0,0 +1,40 @@
+package mappers
+
+import (
+	"time"
+
+	"thothix-backend/internal/presence/domain"
+	presenceDto "thothix-backend/internal/presence/dto"
+)
+
+// PresenceMapper handles conversion between presence models and DTOs
+type PresenceMapper struct{}
+
+// NewPresenceMapper creates a new PresenceMapper instance
+func NewPresenceMapper() *PresenceMapper {
+	return &PresenceMapper{}
+}
+
+// PresenceToDto converts a resolved Presence to PresenceDto
+func (m *PresenceMapper) PresenceToDto(presence domain.Presence) presenceDto.PresenceDto {
+	result := presenceDto.PresenceDto{
+		UserID: presence.UserID,
+		Status: string(presence.Status),
+	}
+	if presence.LastSeenAt != nil {
+		result.LastSeenAt = presence.LastSeenAt.UTC().Format(time.RFC3339)
+	}
+	return result
+}
+
+// TypingToDtos converts typing indicators to TypingDtos
+func (m *PresenceMapper) TypingToDtos(indicators []domain.TypingIndicator) []presenceDto.TypingDto {
+	result := make([]presenceDto.TypingDto, 0, len(indicators))
+	for _, indicator := range indicators {
+		result = append(result, presenceDto.TypingDto{
+			UserID:    indicator.UserID,
+			ExpiresAt: indicator.ExpiresAt.UTC().Format(time.RFC3339Nano),
+		})
+	}
+	return result
+}
@@ -0,0 +1,302 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+
+	"thothix-backend/internal/blocking/filter"
+	"thothix-backend/internal/presence/domain"
+	presenceDto "thothix-backend/internal/presence/dto"
+	"thothix-backend/internal/presence/mappers"
+	"thothix-backend/internal/presence/store"
+	"thothix-backend/internal/shared/constants"
+	"thothix-backend/internal/shared/dto"
+	sharedMiddleware "thothix-backend/internal/shared/middleware"
+	sharedModels "thothix-backend/internal/shared/models"
+	usersDomain "thothix-backend/internal/users/domain"
+)
+
+const (
+	// OfflineAfter is how long a connection stays active without a heartbeat; clients beat every 30 seconds
+	OfflineAfter = 90 * time.Second
+
+	// TypingTTL is how long a typing indicator lasts; clients repeat StartTyping while the user types
+	TypingTTL = 6 * time.Second
+
+	// maxPresenceQuery caps the users of one bulk presence query
+	maxPresenceQuery = 200
+)
+
+// PresenceService tracks presence from connection heartbeats and typing indicators per conversation
+// There is no push channel yet, so clients poll GetPresence and GetTyping
+type PresenceService struct {
+	db     *gorm.DB
+	store  store.Store
+	mapper *mappers.PresenceMapper
+	now    func() time.Time
+}
+
+func NewPresenceService(db *gorm.DB, presenceStore store.Store) *PresenceService {
+	return &PresenceService{
+		db:     db,
+		store:  presenceStore,
+		mapper: mappers.NewPresenceMapper(),
+		now:    time.Now,
+	}
+}
+
+// WithContext returns a copy of the service whose database session carries ctx
+func (s *PresenceService) WithContext(ctx context.Context) *PresenceService {
+	return &PresenceService{
+		db:     s.db.WithContext(ctx),
+		store:  s.store,
+		mapper: s.mapper,
+		now:    s.now,
+	}
+}
+
+func (s *PresenceService) ctx() context.Context {
+	return s.db.Statement.Context
+}
+
+// Heartbeat records that a connection of the current user is alive and returns the user's resulting presence
+func (s *PresenceService) Heartbeat(req *presenceDto.HeartbeatRequest) *presenceDto.PresenceResponse {
+	return presenceDto.NewPresenceResponse(func() dto.Validation[*presenceDto.PresenceDto] {
+		if req == nil || req.ConnectionID == "" {
+			return dto.Failure[*presenceDto.PresenceDto](dto.NewError(constants.ValidationError, "Connection ID is required", nil))
+		}
+		status := domain.Status(req.Status)
+		switch status {
+		case "":
+			status = domain.StatusOnline
+		case domain.StatusOnline, domain.StatusAway:
+		default:
+			return dto.Failure[*presenceDto.PresenceDto](dto.NewError(constants.ValidationError, "Status must be online or away", nil))
+		}
+
+		userID, ok := sharedMiddleware.UserIDFromContext(s.ctx())
+		if !ok {
+			return dto.Failure[*presenceDto.PresenceDto](dto.NewError(constants.UnauthorizedError, "User not authenticated", nil))
+		}
+
+		if err := s.store.Heartbeat(s.ctx(), domain.Connection{
+			UserID:       userID,
+			ConnectionID: req.ConnectionID,
+			Status:       status,
+			LastSeenAt:   s.now(),
+		}); err != nil {
+			panic(err)
+		}
+		return dto.Success(s.presenceOf(userID))
+	})
+}
+
+// Disconnect forgets a connection of the current user and returns the user's resulting presence
+func (s *PresenceService) Disconnect(connectionID string) *presenceDto.PresenceResponse {
+	return presenceDto.NewPresenceResponse(func() dto.Validation[*presenceDto.PresenceDto] {
+		if connectionID == "" {
+			return dto.Failure[*presenceDto.PresenceDto](dto.NewError(constants.ValidationError, "Connection ID is required", nil))
+		}
+
+		userID, ok := sharedMiddleware.UserIDFromContext(s.ctx())
+		if !ok {
+			return dto.Failure[*presenceDto.PresenceDto](dto.NewError(constants.UnauthorizedError, "User not authenticated", nil))
+		}
+
+		if err := s.store.Disconnect(s.ctx(), userID, connectionID); err != nil {
+			panic(err)
+		}
+		return dto.Success(s.presenceOf(userID))
+	})
+}
+
+// GetPresence returns the presence of the requested users that are members of the current workspace,
+// in request order; other users are left out
+func (s *PresenceService) GetPresence(req *presenceDto.PresenceQueryRequest) *presenceDto.PresenceListResponse {
+	return presenceDto.NewPresenceListResponse(func() dto.Validation[[]presenceDto.PresenceDto] {
+		if req == nil || len(req.UserIDs) == 0 {
+			return dto.Failure[[]presenceDto.PresenceDto](dto.NewError(constants.ValidationError, "At least one user ID is required", nil))
+		}
+		if len(req.UserIDs) > maxPresenceQuery {
+			return dto.Failure[[]presenceDto.PresenceDto](dto.NewError(constants.ValidationError, "Too many user IDs", map[string]string{
+				"user_ids": "at most 200 users per query",
+			}))
+		}
+
+		var members []string
+		if err := s.db.Model(&usersDomain.User{}).Scopes(sharedModels.WorkspaceMembers).
+			Where("id IN ?", req.UserIDs).Pluck("id", &members).Error; err != nil {
+			panic(err)
+		}
+		visible := make(map[string]bool, len(members))
+		for _, id := range members {
+			visible[id] = true
+		}
+
+		connections, err := s.store.Connections(s.ctx(), members)
+		if err != nil {
+			panic(err)
+		}
+		byUser := make(map[string][]domain.Connection, len(members))
+		for _, connection := range connections {
+			byUser[connection.UserID] = append(byUser[connection.UserID], connection)
+		}
+
+		now := s.now()
+		result := make([]presenceDto.PresenceDto, 0, len(members))
+		for _, id := range req.UserIDs {
+			if !visible[id] {
+				continue
+			}
+			visible[id] = false // Report duplicates once
+			result = append(result, s.mapper.PresenceToDto(domain.Resolve(id, byUser[id], now, OfflineAfter)))
+		}
+		return dto.Success(result)
+	})
+}
+
+// StartTyping marks the current user as typing in target for TypingTTL and returns the other users typing there
+func (s *PresenceService) StartTyping(target presenceDto.TypingTarget) *presenceDto.TypingListResponse {
+	return presenceDto.NewTypingListResponse(func() dto.Validation[[]presenceDto.TypingDto] {
+		userID, scope, failure := s.resolveScope(target, sharedModels.PermissionMessageCreate)
+		if failure != nil {
+			return dto.Failure[[]presenceDto.TypingDto](*failure)
+		}
+
+		if err := s.store.StartTyping(s.ctx(), domain.TypingIndicator{
+			Scope:     scope,
+			UserID:    userID,
+			ExpiresAt: s.now().Add(TypingTTL),
+		}); err != nil {
+			panic(err)
+		}
+		return dto.Success(s.typingIn(scope, userID))
+	})
+}
+
+// StopTyping clears the current user's typing indicator in target, such as after sending the message
+func (s *PresenceService) StopTyping(target presenceDto.TypingTarget) *presenceDto.TypingListResponse {
+	return presenceDto.NewTypingListResponse(func() dto.Validation[[]presenceDto.TypingDto] {
+		userID, scope, failure := s.resolveScope(target, sharedModels.PermissionChannelRead)
+		if failure != nil {
+			return dto.Failure[[]presenceDto.TypingDto](*failure)
+		}
+
+		if err := s.store.StopTyping(s.ctx(), scope, userID); err != nil {
+			panic(err)
+		}
+		return dto.Success(s.typingIn(scope, userID))
+	})
+}
+
+// GetTyping lists the other users typing in target
+func (s *PresenceService) GetTyping(target presenceDto.TypingTarget) *presenceDto.TypingListResponse {
+	return presenceDto.NewTypingListResponse(func() dto.Validation[[]presenceDto.TypingDto] {
+		userID, scope, failure := s.resolveScope(target, sharedModels.PermissionChannelRead)
+		if failure != nil {
+			return dto.Failure[[]presenceDto.TypingDto](*failure)
+		}
+		return dto.Success(s.typingIn(scope, userID))
+	})
+}
+
+// resolveScope authenticates the caller and returns the typing scope of target
+// Channels require channelPermission on the channel; direct conversations require an active recipient in the
+// current workspace, and starting to type requires the DM permission and no block between the two users,
+// like sending a direct message does
+func (s *PresenceService) resolveScope(target presenceDto.TypingTarget, channelPermission sharedModels.Permission) (string, string, *dto.Error) {
+	fail := func(code, message string) (string, string, *dto.Error) {
+		err := dto.NewError(code, message, nil)
+		return "", "", &err
+	}
+
+	userID, ok := sharedMiddleware.UserIDFromContext(s.ctx())
+	if !ok {
+		return fail(constants.UnauthorizedError, "User not authenticated")
+	}
+
+	switch {
+	case target.ChannelID != "":
+		var count int64
+		if err := s.db.Table("channels").Scopes(sharedModels.InWorkspace("channels")).
+			Where("id::text = ?", target.ChannelID).Count(&count).Error; err != nil {
+			panic(err)
+		}
+		if count == 0 {
+			return fail(constants.NotFoundError, "Channel not found")
+		}
+		resourceType := "channel"
+		if !sharedModels.HasUserPermission(s.db, userID, channelPermission, &resourceType, &target.ChannelID) {
+			return fail(constants.ForbiddenError, "Access denied to this channel")
+		}
+		return userID, domain.ChannelScope(target.ChannelID), nil
+
+	case target.RecipientID != "":
+		if target.RecipientID == userID {
+			return fail(constants.ValidationError, "Cannot type to yourself")
+		}
+		if channelPermission == sharedModels.PermissionMessageCreate &&
+			!sharedModels.HasUserPermission(s.db, userID, sharedModels.PermissionDMCreate, nil, nil) {
+			return fail(constants.ForbiddenError, "Cannot create direct messages")
+		}
+		var recipient usersDomain.User
+		if err := s.db.Scopes(sharedModels.WorkspaceMembers).Where("id = ?", target.RecipientID).First(&recipient).Error; err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return fail(constants.NotFoundError, "Recipient not found")
+			}
+			panic(err)
+		}
+		if !recipient.IsActive() {
+			return fail(constants.NotFoundError, "Recipient not found")
+		}
+		if channelPermission == sharedModels.PermissionMessageCreate {
+			blocked, err := filter.DirectMessagesBlocked(s.db, userID, recipient.ID)
+			if err != nil {
+				panic(err)
+			}
+			if blocked {
+				return fail(constants.ForbiddenError, "You cannot send direct messages to this user")
+			}
+		}
+		return userID, domain.DirectScope(userID, target.RecipientID), nil
+
+	default:
+		return fail(constants.ValidationError, "A channel or recipient is required")
+	}
+}
+
+// presenceOf resolves the presence of one user
+func (s *PresenceService) presenceOf(userID string) *presenceDto.PresenceDto {
+	connections, err := s.store.Connections(s.ctx(), []string{userID})
+	if err != nil {
+		panic(err)
+	}
+	presence := s.mapper.PresenceToDto(domain.Resolve(userID, connections, s.now(), OfflineAfter))
+	return &presence
+}
+
+// typingIn lists the indicators of scope except the caller's own
+// Typing is activity fanned out to the others in the scope, so it is hidden from a caller who blocked or
+// muted the typing user, or whom that user blocked
+func (s *PresenceService) typingIn(scope, userID string) []presenceDto.TypingDto {
+	indicators, err := s.store.Typing(s.ctx(), scope, s.now())
+	if err != nil {
+		panic(err)
+	}
+	others := indicators[:0]
+	for _, indicator := range indicators {
+		if indicator.UserID == userID {
+			continue
+		}
+		recipients, err := filter.Recipients(s.db, indicator.UserID, []string{userID})
+		if err != nil {
+			panic(err)
+		}
+		if len(recipients) > 0 {
+			others = append(others, indicator)
+		}
+	}
+	return s.mapper.TypingToDtos(others)
+}
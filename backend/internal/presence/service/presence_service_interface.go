@@ -0,0 +1,22 @@
+package service
+
+import (
+	"context"
+
+	presenceDto "thothix-backend/internal/presence/dto"
+)
+
+// PresenceServiceInterface defines the contract for presence and typing operations using Response pattern
+type PresenceServiceInterface interface {
+	Heartbeat(req *presenceDto.HeartbeatRequest) *presenceDto.PresenceResponse
+	Disconnect(connectionID string) *presenceDto.PresenceResponse
+	GetPresence(req *presenceDto.PresenceQueryRequest) *presenceDto.PresenceListResponse
+	StartTyping(target presenceDto.TypingTarget) *presenceDto.TypingListResponse
+	StopTyping(target presenceDto.TypingTarget) *presenceDto.TypingListResponse
+	GetTyping(target presenceDto.TypingTarget) *presenceDto.TypingListResponse
+}
+
+// ContextAwarePresenceService is implemented by services that can bind their database session to a request context
+type ContextAwarePresenceService interface {
+	WithContext(ctx context.Context) *PresenceService
+}
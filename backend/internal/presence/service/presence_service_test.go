@@ -0,0 +1,154 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/gorm"
+	blockingDomain "thothix-backend/internal/blocking/domain"
+	"thothix-backend/internal/presence/domain"
+	presenceDto "thothix-backend/internal/presence/dto"
+	"thothix-backend/internal/presence/store"
+	sharedMiddleware "thothix-backend/internal/shared/middleware"
+	sharedModels "thothix-backend/internal/shared/models"
+	sharedTesting "thothix-backend/internal/shared/testing"
+	usersDomain "thothix-backend/internal/users/domain"
+	workspaceDomain "thothix-backend/internal/workspace/domain"
+)
+
+type PresenceServiceTestSuite struct {
+	suite.Suite
+	container *sharedTesting.PostgresTestContainer
+}
+
+func (suite *PresenceServiceTestSuite) SetupSuite() {
+	suite.container = sharedTesting.GetSharedTestContainer(
+		suite.T(),
+		"presence/service",
+		[]interface{}{&usersDomain.User{}, &sharedModels.UserRole{}, &workspaceDomain.Workspace{}, &blockingDomain.Block{}},
+	)
+}
+
+// createWorkspace stores a workspace
+func (suite *PresenceServiceTestSuite) createWorkspace(db *gorm.DB, slug string) *workspaceDomain.Workspace {
+	workspace := &workspaceDomain.Workspace{Name: slug, Slug: slug}
+	assert.NoError(suite.T(), db.Create(workspace).Error)
+	return workspace
+}
+
+// createMember stores a user who is a member of workspace
+func (suite *PresenceServiceTestSuite) createMember(db *gorm.DB, testName string, workspace *workspaceDomain.Workspace) *usersDomain.User {
+	user := &usersDomain.User{Email: testName + "@example.com", Name: "User " + testName, SystemRole: sharedModels.RoleUser}
+	user.ID = uuid.New().String()
+	assert.NoError(suite.T(), db.Create(user).Error)
+
+	resourceType := sharedModels.ResourceTypeWorkspace
+	assert.NoError(suite.T(), db.Create(&sharedModels.UserRole{
+		UserID: user.ID, Role: sharedModels.RoleUser, ResourceType: &resourceType, ResourceID: &workspace.ID,
+	}).Error)
+	return user
+}
+
+// serviceFor creates a service acting as the given user in workspace, sharing presenceStore
+func (suite *PresenceServiceTestSuite) serviceFor(db *gorm.DB, presenceStore store.Store, userID, workspaceID string) *PresenceService {
+	ctx := sharedMiddleware.WithWorkspaceID(sharedMiddleware.WithUserID(context.Background(), userID), workspaceID)
+	return NewPresenceService(db, presenceStore).WithContext(ctx)
+}
+
+func (suite *PresenceServiceTestSuite) TestGetPresence_OnlyReportsWorkspaceMembers() {
+	suite.container.WithTransaction(func(db *gorm.DB) {
+		// Arrange
+		workspace := suite.createWorkspace(db, "presence-members")
+		other := suite.createWorkspace(db, "presence-members-other")
+		alice := suite.createMember(db, "TestGetPresence_alice", workspace)
+		bob := suite.createMember(db, "TestGetPresence_bob", workspace)
+		outsider := suite.createMember(db, "TestGetPresence_outsider", other)
+		presenceStore := store.NewMemoryStore()
+		sharedTesting.AssertSuccessWithValue(suite.T(), suite.serviceFor(db, presenceStore, alice.ID, workspace.ID).
+			Heartbeat(&presenceDto.HeartbeatRequest{ConnectionID: "tab-1"}).Response)
+		sharedTesting.AssertSuccessWithValue(suite.T(), suite.serviceFor(db, presenceStore, outsider.ID, other.ID).
+			Heartbeat(&presenceDto.HeartbeatRequest{ConnectionID: "tab-1"}).Response)
+
+		// Act
+		response := suite.serviceFor(db, presenceStore, bob.ID, workspace.ID).GetPresence(&presenceDto.PresenceQueryRequest{
+			UserIDs: []string{alice.ID, bob.ID, outsider.ID},
+		})
+
+		// Assert
+		presences := sharedTesting.AssertSuccessWithValue(suite.T(), response.Response)
+		assert.Len(suite.T(), presences, 2)
+		assert.Equal(suite.T(), presenceDto.PresenceDto{UserID: alice.ID, Status: "online", LastSeenAt: presences[0].LastSeenAt}, presences[0])
+		assert.Equal(suite.T(), presenceDto.PresenceDto{UserID: bob.ID, Status: "offline"}, presences[1])
+	})
+}
+
+func (suite *PresenceServiceTestSuite) TestDirectTyping_IsVisibleToRecipient() {
+	suite.container.WithTransaction(func(db *gorm.DB) {
+		// Arrange
+		workspace := suite.createWorkspace(db, "presence-typing")
+		alice := suite.createMember(db, "TestDirectTyping_alice", workspace)
+		bob := suite.createMember(db, "TestDirectTyping_bob", workspace)
+		presenceStore := store.NewMemoryStore()
+		now := time.Now()
+		aliceService := suite.serviceFor(db, presenceStore, alice.ID, workspace.ID)
+		aliceService.now = func() time.Time { return now }
+
+		// Act
+		started := aliceService.StartTyping(presenceDto.TypingTarget{RecipientID: bob.ID})
+		seen := suite.serviceFor(db, presenceStore, bob.ID, workspace.ID).GetTyping(presenceDto.TypingTarget{RecipientID: alice.ID})
+
+		// Assert
+		assert.Empty(suite.T(), sharedTesting.AssertSuccessWithValue(suite.T(), started.Response))
+		typing := sharedTesting.AssertSuccessWithValue(suite.T(), seen.Response)
+		assert.Len(suite.T(), typing, 1)
+		assert.Equal(suite.T(), alice.ID, typing[0].UserID)
+	})
+}
+
+func (suite *PresenceServiceTestSuite) TestChannelTyping_HiddenFromUsersWhoMutedTheTypist() {
+	suite.container.WithTransaction(func(db *gorm.DB) {
+		// Arrange
+		workspace := suite.createWorkspace(db, "presence-muted")
+		alice := suite.createMember(db, "TestChannelTyping_muted_alice", workspace)
+		bob := suite.createMember(db, "TestChannelTyping_muted_bob", workspace)
+		carol := suite.createMember(db, "TestChannelTyping_muted_carol", workspace)
+		assert.NoError(suite.T(), db.Create(&blockingDomain.Block{BlockerID: bob.ID, BlockedID: alice.ID, Type: blockingDomain.TypeMute}).Error)
+		presenceStore := store.NewMemoryStore()
+		scope := domain.ChannelScope(uuid.New().String())
+		assert.NoError(suite.T(), presenceStore.StartTyping(context.Background(), domain.TypingIndicator{Scope: scope, UserID: alice.ID, ExpiresAt: time.Now().Add(TypingTTL)}))
+
+		// Act
+		seenByBob := suite.serviceFor(db, presenceStore, bob.ID, workspace.ID).typingIn(scope, bob.ID)
+		seenByCarol := suite.serviceFor(db, presenceStore, carol.ID, workspace.ID).typingIn(scope, carol.ID)
+
+		// Assert
+		assert.Empty(suite.T(), seenByBob)
+		assert.Len(suite.T(), seenByCarol, 1)
+		assert.Equal(suite.T(), alice.ID, seenByCarol[0].UserID)
+	})
+}
+
+func (suite *PresenceServiceTestSuite) TestStartTyping_RecipientOutsideWorkspace() {
+	suite.container.WithTransaction(func(db *gorm.DB) {
+		// Arrange
+		workspace := suite.createWorkspace(db, "presence-outside")
+		other := suite.createWorkspace(db, "presence-outside-other")
+		alice := suite.createMember(db, "TestStartTyping_outside_alice", workspace)
+		outsider := suite.createMember(db, "TestStartTyping_outside_outsider", other)
+
+		// Act
+		response := suite.serviceFor(db, store.NewMemoryStore(), alice.ID, workspace.ID).
+			StartTyping(presenceDto.TypingTarget{RecipientID: outsider.ID})
+
+		// Assert
+		sharedTesting.AssertValidationErrorWithCode(suite.T(), response.Response, "NOT_FOUND")
+	})
+}
+
+func TestPresenceServiceTestSuite(t *testing.T) {
+	suite.Run(t, new(PresenceServiceTestSuite))
+}
@@ -0,0 +1,131 @@
+package store
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"thothix-backend/internal/presence/domain"
+)
+
+// MemoryStore keeps presence in process memory
+type MemoryStore struct {
+	mu          sync.Mutex
+	connections map[string]map[string]domain.Connection // By user, then connection
+	typing      map[string]map[string]time.Time         // Expiry by scope, then user
+	now         func() time.Time
+	retention   time.Duration
+	lastSweep   time.Time
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		connections: make(map[string]map[string]domain.Connection),
+		typing:      make(map[string]map[string]time.Time),
+		now:         time.Now,
+		retention:   defaultRetention,
+	}
+}
+
+func (s *MemoryStore) Heartbeat(_ context.Context, connection domain.Connection) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.sweep()
+
+	if s.connections[connection.UserID] == nil {
+		s.connections[connection.UserID] = make(map[string]domain.Connection)
+	}
+	s.connections[connection.UserID][connection.ConnectionID] = connection
+	return nil
+}
+
+func (s *MemoryStore) Disconnect(_ context.Context, userID, connectionID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.connections[userID], connectionID)
+	if len(s.connections[userID]) == 0 {
+		delete(s.connections, userID)
+	}
+	return nil
+}
+
+func (s *MemoryStore) Connections(_ context.Context, userIDs []string) ([]domain.Connection, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var connections []domain.Connection
+	for _, userID := range userIDs {
+		for _, connection := range s.connections[userID] {
+			connections = append(connections, connection)
+		}
+	}
+	return connections, nil
+}
+
+func (s *MemoryStore) StartTyping(_ context.Context, indicator domain.TypingIndicator) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.sweep()
+
+	if s.typing[indicator.Scope] == nil {
+		s.typing[indicator.Scope] = make(map[string]time.Time)
+	}
+	s.typing[indicator.Scope][indicator.UserID] = indicator.ExpiresAt
+	return nil
+}
+
+func (s *MemoryStore) StopTyping(_ context.Context, scope, userID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.typing[scope], userID)
+	if len(s.typing[scope]) == 0 {
+		delete(s.typing, scope)
+	}
+	return nil
+}
+
+func (s *MemoryStore) Typing(_ context.Context, scope string, now time.Time) ([]domain.TypingIndicator, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var indicators []domain.TypingIndicator
+	for userID, expiresAt := range s.typing[scope] {
+		if expiresAt.After(now) {
+			indicators = append(indicators, domain.TypingIndicator{Scope: scope, UserID: userID, ExpiresAt: expiresAt})
+		}
+	}
+	sort.Slice(indicators, func(i, j int) bool { return indicators[i].UserID < indicators[j].UserID })
+	return indicators, nil
+}
+
+// sweep drops old connections and expired typing indicators at most once per sweep interval
+func (s *MemoryStore) sweep() {
+	now := s.now()
+	if now.Sub(s.lastSweep) < sweepInterval {
+		return
+	}
+	for userID, connections := range s.connections {
+		for connectionID, connection := range connections {
+			if now.Sub(connection.LastSeenAt) >= s.retention {
+				delete(connections, connectionID)
+			}
+		}
+		if len(connections) == 0 {
+			delete(s.connections, userID)
+		}
+	}
+	for scope, users := range s.typing {
+		for userID, expiresAt := range users {
+			if !expiresAt.After(now) {
+				delete(users, userID)
+			}
+		}
+		if len(users) == 0 {
+			delete(s.typing, scope)
+		}
+	}
+	s.lastSweep = now
+}
@@ -0,0 +1,63 @@
+package store
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"thothix-backend/internal/presence/domain"
+)
+
+func TestMemoryStore_HeartbeatAndDisconnect(t *testing.T) {
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	store := NewMemoryStore()
+	ctx := context.Background()
+
+	_ = store.Heartbeat(ctx, domain.Connection{UserID: "user-1", ConnectionID: "tab-1", Status: domain.StatusOnline, LastSeenAt: now})
+	_ = store.Heartbeat(ctx, domain.Connection{UserID: "user-1", ConnectionID: "tab-1", Status: domain.StatusAway, LastSeenAt: now})
+	_ = store.Heartbeat(ctx, domain.Connection{UserID: "user-1", ConnectionID: "tab-2", Status: domain.StatusOnline, LastSeenAt: now})
+	_ = store.Heartbeat(ctx, domain.Connection{UserID: "user-2", ConnectionID: "tab-1", Status: domain.StatusOnline, LastSeenAt: now})
+	_ = store.Disconnect(ctx, "user-1", "tab-2")
+
+	connections, err := store.Connections(ctx, []string{"user-1"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []domain.Connection{{UserID: "user-1", ConnectionID: "tab-1", Status: domain.StatusAway, LastSeenAt: now}}, connections)
+}
+
+func TestMemoryStore_TypingExpires(t *testing.T) {
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	store := NewMemoryStore()
+	ctx := context.Background()
+
+	_ = store.StartTyping(ctx, domain.TypingIndicator{Scope: "channel:1", UserID: "user-1", ExpiresAt: now.Add(6 * time.Second)})
+	_ = store.StartTyping(ctx, domain.TypingIndicator{Scope: "channel:1", UserID: "user-2", ExpiresAt: now.Add(2 * time.Second)})
+	_ = store.StartTyping(ctx, domain.TypingIndicator{Scope: "channel:2", UserID: "user-3", ExpiresAt: now.Add(6 * time.Second)})
+
+	typing, err := store.Typing(ctx, "channel:1", now.Add(3*time.Second))
+
+	assert.NoError(t, err)
+	assert.Len(t, typing, 1)
+	assert.Equal(t, "user-1", typing[0].UserID)
+
+	_ = store.StopTyping(ctx, "channel:1", "user-1")
+	typing, _ = store.Typing(ctx, "channel:1", now)
+	assert.Len(t, typing, 1)
+	assert.Equal(t, "user-2", typing[0].UserID)
+}
+
+func TestMemoryStore_SweepsOldConnections(t *testing.T) {
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	store := NewMemoryStore()
+	store.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	_ = store.Heartbeat(ctx, domain.Connection{UserID: "user-1", ConnectionID: "tab-1", Status: domain.StatusOnline, LastSeenAt: now})
+	now = now.Add(2 * defaultRetention)
+	_ = store.Heartbeat(ctx, domain.Connection{UserID: "user-2", ConnectionID: "tab-1", Status: domain.StatusOnline, LastSeenAt: now})
+
+	assert.Len(t, store.connections, 1)
+	assert.Contains(t, store.connections, "user-2")
+}
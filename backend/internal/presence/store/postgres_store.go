@@ -0,0 +1,101 @@
+package store
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+
+	"thothix-backend/internal/presence/domain"
+	"thothix-backend/internal/shared/logging"
+)
+
+// PostgresStore keeps presence in the database, shared by every replica
+// Rows are written with raw SQL upserts, so heartbeats bypass the audit callbacks
+type PostgresStore struct {
+	db        *gorm.DB
+	now       func() time.Time
+	retention time.Duration
+
+	mu        sync.Mutex
+	lastSweep time.Time
+}
+
+func NewPostgresStore(db *gorm.DB) *PostgresStore {
+	return &PostgresStore{
+		db:        db,
+		now:       time.Now,
+		retention: defaultRetention,
+	}
+}
+
+func (s *PostgresStore) Heartbeat(ctx context.Context, connection domain.Connection) error {
+	s.sweep(ctx)
+	return s.db.WithContext(ctx).Exec(
+		`INSERT INTO presence_connections (user_id, connection_id, status, last_seen_at) VALUES (?, ?, ?, ?)
+		ON CONFLICT (user_id, connection_id) DO UPDATE SET status = EXCLUDED.status, last_seen_at = EXCLUDED.last_seen_at`,
+		connection.UserID, connection.ConnectionID, connection.Status, connection.LastSeenAt,
+	).Error
+}
+
+func (s *PostgresStore) Disconnect(ctx context.Context, userID, connectionID string) error {
+	return s.db.WithContext(ctx).Exec(
+		"DELETE FROM presence_connections WHERE user_id = ? AND connection_id = ?", userID, connectionID,
+	).Error
+}
+
+func (s *PostgresStore) Connections(ctx context.Context, userIDs []string) ([]domain.Connection, error) {
+	var connections []domain.Connection
+	if len(userIDs) == 0 {
+		return connections, nil
+	}
+	err := s.db.WithContext(ctx).
+		Raw("SELECT user_id, connection_id, status, last_seen_at FROM presence_connections WHERE user_id IN ?", userIDs).
+		Scan(&connections).Error
+	return connections, err
+}
+
+func (s *PostgresStore) StartTyping(ctx context.Context, indicator domain.TypingIndicator) error {
+	s.sweep(ctx)
+	return s.db.WithContext(ctx).Exec(
+		`INSERT INTO typing_indicators (scope, user_id, expires_at) VALUES (?, ?, ?)
+		ON CONFLICT (scope, user_id) DO UPDATE SET expires_at = EXCLUDED.expires_at`,
+		indicator.Scope, indicator.UserID, indicator.ExpiresAt,
+	).Error
+}
+
+func (s *PostgresStore) StopTyping(ctx context.Context, scope, userID string) error {
+	return s.db.WithContext(ctx).Exec(
+		"DELETE FROM typing_indicators WHERE scope = ? AND user_id = ?", scope, userID,
+	).Error
+}
+
+func (s *PostgresStore) Typing(ctx context.Context, scope string, now time.Time) ([]domain.TypingIndicator, error) {
+	var indicators []domain.TypingIndicator
+	err := s.db.WithContext(ctx).
+		Raw("SELECT scope, user_id, expires_at FROM typing_indicators WHERE scope = ? AND expires_at > ? ORDER BY user_id", scope, now).
+		Scan(&indicators).Error
+	return indicators, err
+}
+
+// sweep deletes old connections and expired typing indicators at most once per sweep interval per instance
+func (s *PostgresStore) sweep(ctx context.Context) {
+	now := s.now()
+	s.mu.Lock()
+	if now.Sub(s.lastSweep) < sweepInterval {
+		s.mu.Unlock()
+		return
+	}
+	s.lastSweep = now
+	s.mu.Unlock()
+
+	db := s.db.WithContext(ctx)
+	if err := db.Exec("DELETE FROM presence_connections WHERE last_seen_at < ?", now.Add(-s.retention)).Error; err != nil {
+		logging.FromContext(ctx).Warn("Failed to delete old presence connections", slog.Any("error", err))
+	}
+	if err := db.Exec("DELETE FROM typing_indicators WHERE expires_at <= ?", now).Error; err != nil {
+		logging.FromContext(ctx).Warn("Failed to delete expired typing indicators", slog.Any("error", err))
+	}
+}
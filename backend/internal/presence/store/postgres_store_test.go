@@ -0,0 +1,73 @@
+package store
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/gorm"
+
+	"thothix-backend/internal/presence/domain"
+	sharedTesting "thothix-backend/internal/shared/testing"
+)
+
+type PostgresStoreTestSuite struct {
+	suite.Suite
+	container *sharedTesting.PostgresTestContainer
+}
+
+func (suite *PostgresStoreTestSuite) SetupSuite() {
+	suite.container = sharedTesting.GetSharedTestContainer(suite.T(), "presence/store", []interface{}{
+		&domain.Connection{},
+		&domain.TypingIndicator{},
+	})
+}
+
+func (suite *PostgresStoreTestSuite) TestHeartbeat_UpsertsConnection() {
+	suite.container.WithTransaction(func(db *gorm.DB) {
+		// Arrange
+		now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+		store := NewPostgresStore(db)
+		store.now = func() time.Time { return now }
+		ctx := context.Background()
+
+		// Act
+		errA := store.Heartbeat(ctx, domain.Connection{UserID: "user-1", ConnectionID: "tab-1", Status: domain.StatusOnline, LastSeenAt: now})
+		errB := store.Heartbeat(ctx, domain.Connection{UserID: "user-1", ConnectionID: "tab-1", Status: domain.StatusAway, LastSeenAt: now.Add(time.Second)})
+		connections, err := store.Connections(ctx, []string{"user-1", "user-2"})
+
+		// Assert
+		assert.NoError(suite.T(), errA)
+		assert.NoError(suite.T(), errB)
+		assert.NoError(suite.T(), err)
+		assert.Len(suite.T(), connections, 1)
+		assert.Equal(suite.T(), domain.StatusAway, connections[0].Status)
+		assert.True(suite.T(), connections[0].LastSeenAt.Equal(now.Add(time.Second)))
+	})
+}
+
+func (suite *PostgresStoreTestSuite) TestTyping_ExcludesExpiredIndicators() {
+	suite.container.WithTransaction(func(db *gorm.DB) {
+		// Arrange
+		now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+		store := NewPostgresStore(db)
+		store.now = func() time.Time { return now }
+		ctx := context.Background()
+		_ = store.StartTyping(ctx, domain.TypingIndicator{Scope: "channel:1", UserID: "user-1", ExpiresAt: now.Add(6 * time.Second)})
+		_ = store.StartTyping(ctx, domain.TypingIndicator{Scope: "channel:1", UserID: "user-2", ExpiresAt: now.Add(2 * time.Second)})
+
+		// Act
+		typing, err := store.Typing(ctx, "channel:1", now.Add(3*time.Second))
+
+		// Assert
+		assert.NoError(suite.T(), err)
+		assert.Len(suite.T(), typing, 1)
+		assert.Equal(suite.T(), "user-1", typing[0].UserID)
+	})
+}
+
+func TestPostgresStoreTestSuite(t *testing.T) {
+	suite.Run(t, new(PostgresStoreTestSuite))
+}
@@ -0,0 +1,52 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+
+	"thothix-backend/internal/presence/domain"
+)
+
+const (
+	// KindMemory keeps presence in process memory; use it with a single instance
+	KindMemory = "memory"
+	// KindPostgres keeps presence in the database so every replica sees every connection
+	KindPostgres = "postgres"
+
+	// defaultRetention is how long a silent connection is kept, so "last seen" stays known for a day
+	defaultRetention = 24 * time.Hour
+
+	// sweepInterval is the minimum time between deletions of old connections and expired typing indicators
+	sweepInterval = time.Minute
+)
+
+// Store keeps client connections and typing indicators
+type Store interface {
+	// Heartbeat records that a connection is alive with the given status
+	Heartbeat(ctx context.Context, connection domain.Connection) error
+	// Disconnect forgets a connection, such as a closed tab
+	Disconnect(ctx context.Context, userID, connectionID string) error
+	// Connections returns the known connections of the given users
+	Connections(ctx context.Context, userIDs []string) ([]domain.Connection, error)
+	// StartTyping records or extends a typing indicator
+	StartTyping(ctx context.Context, indicator domain.TypingIndicator) error
+	// StopTyping removes a typing indicator
+	StopTyping(ctx context.Context, scope, userID string) error
+	// Typing returns the indicators of scope that have not expired at now
+	Typing(ctx context.Context, scope string, now time.Time) ([]domain.TypingIndicator, error)
+}
+
+// New creates the store selected by kind
+func New(kind string, db *gorm.DB) (Store, error) {
+	switch kind {
+	case "", KindMemory:
+		return NewMemoryStore(), nil
+	case KindPostgres:
+		return NewPostgresStore(db), nil
+	default:
+		return nil, fmt.Errorf("unknown presence store %q", kind)
+	}
+}
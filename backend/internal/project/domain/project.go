@@ -9,6 +9,7 @@ import (
 // Project represents a project entity in the project domain
 type Project struct {
 	commonModels.BaseModel
+	WorkspaceID string `json:"workspace_id" gorm:"index"`
 	Name        string `json:"name"`
 	Description string `json:"description"`
 }
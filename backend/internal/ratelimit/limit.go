@@ -0,0 +1,109 @@
+package ratelimit
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Limit configures a token bucket: up to Requests calls in a burst, refilled evenly over Period
+type Limit struct {
+	Requests int
+	Period   time.Duration
+}
+
+// Enabled reports whether the limit throttles anything; the zero Limit disables rate limiting
+func (l Limit) Enabled() bool {
+	return l.Requests > 0 && l.Period > 0
+}
+
+// rate returns how many tokens are added to the bucket per second
+func (l Limit) rate() float64 {
+	return float64(l.Requests) / l.Period.Seconds()
+}
+
+// ParseLimit parses a limit written as "<requests>/<period>", for example "60/m" or "100/30s"
+// The period is s, m, h or a Go duration. An empty value or "off" disables the limit
+func ParseLimit(value string) (Limit, error) {
+	value = strings.TrimSpace(value)
+	if value == "" || value == "off" {
+		return Limit{}, nil
+	}
+
+	requestsPart, periodPart, ok := strings.Cut(value, "/")
+	if !ok {
+		return Limit{}, fmt.Errorf("invalid rate limit %q: expected <requests>/<period>", value)
+	}
+
+	requests, err := strconv.Atoi(strings.TrimSpace(requestsPart))
+	if err != nil || requests < 0 {
+		return Limit{}, fmt.Errorf("invalid rate limit %q: requests must be a non-negative integer", value)
+	}
+
+	var period time.Duration
+	switch periodPart = strings.TrimSpace(periodPart); periodPart {
+	case "s":
+		period = time.Second
+	case "m":
+		period = time.Minute
+	case "h":
+		period = time.Hour
+	default:
+		period, err = time.ParseDuration(periodPart)
+		if err != nil || period <= 0 {
+			return Limit{}, fmt.Errorf("invalid rate limit %q: unknown period %q", value, periodPart)
+		}
+	}
+
+	return Limit{Requests: requests, Period: period}, nil
+}
+
+// Result describes the outcome of taking a token from a bucket
+type Result struct {
+	Allowed    bool
+	Limit      int           // Bucket capacity
+	Remaining  int           // Whole tokens left after this request
+	RetryAfter time.Duration // Wait before the next token is available; zero when allowed
+	ResetAfter time.Duration // Wait until the bucket is full again
+}
+
+// bucket is the persisted state of a token bucket
+type bucket struct {
+	Tokens    float64
+	UpdatedAt time.Time
+}
+
+// newBucket returns a full bucket for limit
+func newBucket(limit Limit, now time.Time) bucket {
+	return bucket{Tokens: float64(limit.Requests), UpdatedAt: now}
+}
+
+// take refills the bucket for the time elapsed since its last update and tries to consume one token
+func (b bucket) take(limit Limit, now time.Time) (bucket, Result) {
+	capacity := float64(limit.Requests)
+	rate := limit.rate()
+
+	elapsed := now.Sub(b.UpdatedAt).Seconds()
+	if elapsed < 0 {
+		elapsed = 0 // Clock skew between replicas must not drain the bucket
+	}
+	tokens := math.Min(capacity, b.Tokens+elapsed*rate)
+
+	result := Result{Limit: limit.Requests}
+	if tokens >= 1 {
+		tokens--
+		result.Allowed = true
+	} else {
+		result.RetryAfter = secondsToDuration((1 - tokens) / rate)
+	}
+	result.Remaining = int(math.Floor(tokens))
+	result.ResetAfter = secondsToDuration((capacity - tokens) / rate)
+
+	return bucket{Tokens: tokens, UpdatedAt: now}, result
+}
+
+func secondsToDuration(seconds float64) time.Duration {
+	return time.Duration(seconds * float64(time.Second))
+}
@@ -0,0 +1,69 @@
+package ratelimit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseLimit(t *testing.T) {
+	tests := []struct {
+		value    string
+		expected Limit
+		wantErr  bool
+	}{
+		{value: "60/m", expected: Limit{Requests: 60, Period: time.Minute}},
+		{value: "10/s", expected: Limit{Requests: 10, Period: time.Second}},
+		{value: "1000/h", expected: Limit{Requests: 1000, Period: time.Hour}},
+		{value: "5/30s", expected: Limit{Requests: 5, Period: 30 * time.Second}},
+		{value: "", expected: Limit{}},
+		{value: "off", expected: Limit{}},
+		{value: "60", wantErr: true},
+		{value: "x/m", wantErr: true},
+		{value: "60/fortnight", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			limit, err := ParseLimit(tt.value)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, limit)
+		})
+	}
+}
+
+func TestBucketTake_ExhaustsAndRefills(t *testing.T) {
+	limit := Limit{Requests: 2, Period: 2 * time.Second} // One token per second
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	b := newBucket(limit, now)
+
+	b, first := b.take(limit, now)
+	b, second := b.take(limit, now)
+	b, third := b.take(limit, now)
+
+	assert.True(t, first.Allowed)
+	assert.Equal(t, 1, first.Remaining)
+	assert.True(t, second.Allowed)
+	assert.Equal(t, 0, second.Remaining)
+	assert.False(t, third.Allowed)
+	assert.Equal(t, time.Second, third.RetryAfter)
+	assert.Equal(t, 2*time.Second, third.ResetAfter)
+
+	_, refilled := b.take(limit, now.Add(time.Second))
+	assert.True(t, refilled.Allowed)
+}
+
+func TestBucketTake_IgnoresClockSkew(t *testing.T) {
+	limit := Limit{Requests: 1, Period: time.Minute}
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	b := bucket{Tokens: 0.5, UpdatedAt: now}
+
+	_, result := b.take(limit, now.Add(-time.Hour))
+
+	assert.False(t, result.Allowed)
+}
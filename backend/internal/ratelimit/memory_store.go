@@ -0,0 +1,60 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// MemoryStore keeps token buckets in process memory
+type MemoryStore struct {
+	mu            sync.Mutex
+	buckets       map[string]memoryBucket
+	now           func() time.Time
+	sweepInterval time.Duration
+	lastSweep     time.Time
+}
+
+// memoryBucket is a bucket with the time it is full again, after which it can be dropped
+type memoryBucket struct {
+	bucket
+	fullAt time.Time
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		buckets:       make(map[string]memoryBucket),
+		now:           time.Now,
+		sweepInterval: defaultSweepInterval,
+	}
+}
+
+// Take consumes a token from the bucket for key
+func (s *MemoryStore) Take(_ context.Context, key string, limit Limit) (Result, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	s.sweep(now)
+
+	current, ok := s.buckets[key]
+	if !ok {
+		current = memoryBucket{bucket: newBucket(limit, now)}
+	}
+	updated, result := current.take(limit, now)
+	s.buckets[key] = memoryBucket{bucket: updated, fullAt: now.Add(result.ResetAfter)}
+	return result, nil
+}
+
+// sweep drops buckets that have refilled, at most once per sweep interval, so memory stays bounded by active clients
+func (s *MemoryStore) sweep(now time.Time) {
+	if now.Sub(s.lastSweep) < s.sweepInterval {
+		return
+	}
+	for key, b := range s.buckets {
+		if !now.Before(b.fullAt) {
+			delete(s.buckets, key)
+		}
+	}
+	s.lastSweep = now
+}
@@ -0,0 +1,50 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemoryStore_KeysHaveSeparateBuckets(t *testing.T) {
+	store := NewMemoryStore()
+	limit := Limit{Requests: 1, Period: time.Minute}
+
+	first, _ := store.Take(context.Background(), "messages:user:1", limit)
+	second, _ := store.Take(context.Background(), "messages:user:1", limit)
+	other, _ := store.Take(context.Background(), "messages:user:2", limit)
+
+	assert.True(t, first.Allowed)
+	assert.False(t, second.Allowed)
+	assert.True(t, other.Allowed)
+}
+
+func TestMemoryStore_SweepsIdleBuckets(t *testing.T) {
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	store := NewMemoryStore()
+	store.now = func() time.Time { return now }
+	limit := Limit{Requests: 1, Period: time.Minute}
+
+	_, _ = store.Take(context.Background(), "auth:ip:1.2.3.4", limit)
+	now = now.Add(2 * defaultSweepInterval)
+	_, _ = store.Take(context.Background(), "auth:ip:5.6.7.8", limit)
+
+	assert.Len(t, store.buckets, 1)
+	assert.Contains(t, store.buckets, "auth:ip:5.6.7.8")
+}
+
+func TestMemoryStore_KeepsBucketsOfLongPeriodsUntilRefilled(t *testing.T) {
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	store := NewMemoryStore()
+	store.now = func() time.Time { return now }
+	limit := Limit{Requests: 1, Period: 24 * time.Hour}
+
+	first, _ := store.Take(context.Background(), "exports:user:1", limit)
+	now = now.Add(2 * defaultSweepInterval)
+	second, _ := store.Take(context.Background(), "exports:user:1", limit)
+
+	assert.True(t, first.Allowed)
+	assert.False(t, second.Allowed)
+}
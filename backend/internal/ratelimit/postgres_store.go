@@ -0,0 +1,94 @@
+package ratelimit
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+
+	"thothix-backend/internal/shared/logging"
+)
+
+// Bucket is the database row backing a token bucket in the Postgres store
+type Bucket struct {
+	Key       string    `gorm:"primaryKey"`
+	Tokens    float64   `gorm:"not null"`
+	UpdatedAt time.Time `gorm:"not null;index"`
+	FullAt    time.Time `gorm:"index"` // When the bucket has refilled and can be dropped
+}
+
+// TableName specifies the table name for the Bucket model
+func (Bucket) TableName() string {
+	return "rate_limit_buckets"
+}
+
+// PostgresStore keeps token buckets in the database, shared by every replica
+// Buckets are read and written with raw SQL under a row lock, so they bypass the audit callbacks
+type PostgresStore struct {
+	db            *gorm.DB
+	now           func() time.Time
+	sweepInterval time.Duration
+
+	mu        sync.Mutex
+	lastSweep time.Time
+}
+
+func NewPostgresStore(db *gorm.DB) *PostgresStore {
+	return &PostgresStore{
+		db:            db,
+		now:           time.Now,
+		sweepInterval: defaultSweepInterval,
+	}
+}
+
+// Take consumes a token from the bucket for key
+func (s *PostgresStore) Take(ctx context.Context, key string, limit Limit) (Result, error) {
+	now := s.now()
+	s.sweep(ctx, now)
+
+	var result Result
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		full := newBucket(limit, now)
+		if err := tx.Exec(
+			"INSERT INTO rate_limit_buckets (key, tokens, updated_at, full_at) VALUES (?, ?, ?, ?) ON CONFLICT (key) DO NOTHING",
+			key, full.Tokens, full.UpdatedAt, full.UpdatedAt,
+		).Error; err != nil {
+			return err
+		}
+
+		var current bucket
+		if err := tx.Raw("SELECT tokens, updated_at FROM rate_limit_buckets WHERE key = ? FOR UPDATE", key).
+			Scan(&current).Error; err != nil {
+			return err
+		}
+
+		var updated bucket
+		updated, result = current.take(limit, now)
+		return tx.Exec(
+			"UPDATE rate_limit_buckets SET tokens = ?, updated_at = ?, full_at = ? WHERE key = ?",
+			updated.Tokens, updated.UpdatedAt, now.Add(result.ResetAfter), key,
+		).Error
+	})
+	return result, err
+}
+
+// sweep deletes buckets that have refilled, at most once per sweep interval per instance
+// Rows written before full_at existed fall back to the sweep interval
+func (s *PostgresStore) sweep(ctx context.Context, now time.Time) {
+	s.mu.Lock()
+	if now.Sub(s.lastSweep) < s.sweepInterval {
+		s.mu.Unlock()
+		return
+	}
+	s.lastSweep = now
+	s.mu.Unlock()
+
+	if err := s.db.WithContext(ctx).Exec(
+		"DELETE FROM rate_limit_buckets WHERE full_at <= ? OR (full_at IS NULL AND updated_at < ?)",
+		now, now.Add(-s.sweepInterval),
+	).Error; err != nil {
+		logging.FromContext(ctx).Warn("Failed to delete idle rate limit buckets", slog.Any("error", err))
+	}
+}
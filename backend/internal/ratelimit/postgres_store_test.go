@@ -0,0 +1,69 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/gorm"
+	sharedTesting "thothix-backend/internal/shared/testing"
+)
+
+type PostgresStoreTestSuite struct {
+	suite.Suite
+	container *sharedTesting.PostgresTestContainer
+}
+
+func (suite *PostgresStoreTestSuite) SetupSuite() {
+	suite.container = sharedTesting.GetSharedTestContainer(suite.T(), "ratelimit", []interface{}{&Bucket{}})
+}
+
+func (suite *PostgresStoreTestSuite) TestTake_SharesBucketAcrossStores() {
+	suite.container.WithTransaction(func(db *gorm.DB) {
+		// Arrange
+		now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+		clock := func() time.Time { return now }
+		first, second := NewPostgresStore(db), NewPostgresStore(db)
+		first.now, second.now = clock, clock
+		limit := Limit{Requests: 2, Period: time.Minute}
+
+		// Act
+		a, errA := first.Take(context.Background(), "messages:user:1", limit)
+		b, errB := second.Take(context.Background(), "messages:user:1", limit)
+		c, errC := first.Take(context.Background(), "messages:user:1", limit)
+
+		// Assert
+		assert.NoError(suite.T(), errA)
+		assert.NoError(suite.T(), errB)
+		assert.NoError(suite.T(), errC)
+		assert.True(suite.T(), a.Allowed)
+		assert.True(suite.T(), b.Allowed)
+		assert.False(suite.T(), c.Allowed)
+		assert.Equal(suite.T(), 30*time.Second, c.RetryAfter)
+	})
+}
+
+func (suite *PostgresStoreTestSuite) TestTake_RefillsOverTime() {
+	suite.container.WithTransaction(func(db *gorm.DB) {
+		// Arrange
+		now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+		store := NewPostgresStore(db)
+		store.now = func() time.Time { return now }
+		limit := Limit{Requests: 1, Period: time.Minute}
+		_, _ = store.Take(context.Background(), "auth:user:1", limit)
+
+		// Act
+		now = now.Add(time.Minute)
+		result, err := store.Take(context.Background(), "auth:user:1", limit)
+
+		// Assert
+		assert.NoError(suite.T(), err)
+		assert.True(suite.T(), result.Allowed)
+	})
+}
+
+func TestPostgresStoreTestSuite(t *testing.T) {
+	suite.Run(t, new(PostgresStoreTestSuite))
+}
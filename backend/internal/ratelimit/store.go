@@ -0,0 +1,37 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	// StoreMemory keeps buckets in process memory; use it with a single instance
+	StoreMemory = "memory"
+	// StorePostgres keeps buckets in the database so limits are shared by every replica
+	StorePostgres = "postgres"
+
+	// defaultSweepInterval is how often idle buckets are dropped. A bucket is only dropped once it has
+	// refilled completely, which takes up to its limit period, since a full bucket is equivalent to a missing one
+	defaultSweepInterval = time.Hour
+)
+
+// Store takes tokens from the bucket identified by key
+type Store interface {
+	Take(ctx context.Context, key string, limit Limit) (Result, error)
+}
+
+// NewStore creates the store selected by kind
+func NewStore(kind string, db *gorm.DB) (Store, error) {
+	switch kind {
+	case "", StoreMemory:
+		return NewMemoryStore(), nil
+	case StorePostgres:
+		return NewPostgresStore(db), nil
+	default:
+		return nil, fmt.Errorf("unknown rate limit store %q", kind)
+	}
+}
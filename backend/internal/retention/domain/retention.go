@@ -0,0 +1,72 @@
+package domain
+
+import (
+	"time"
+
+	commonModels "thothix-backend/internal/common/models"
+)
+
+// Level is the kind of resource a retention policy or a legal hold applies to
+type Level string
+
+const (
+	LevelWorkspace Level = "workspace"
+	LevelProject   Level = "project"
+	LevelChannel   Level = "channel"
+)
+
+// IsValid reports whether the level is one of the supported resource kinds
+func (l Level) IsValid() bool {
+	switch l {
+	case LevelWorkspace, LevelProject, LevelChannel:
+		return true
+	}
+	return false
+}
+
+const (
+	// MinRetentionDays and MaxRetentionDays bound the retention period of a policy
+	MinRetentionDays = 1
+	MaxRetentionDays = 36500
+)
+
+// Policy sets how long the messages and files of a workspace, project or channel are kept
+// The most specific policy wins: channel over project over workspace. Content covered by no policy is kept forever
+type Policy struct {
+	commonModels.BaseModel
+	WorkspaceID   string `json:"workspace_id" gorm:"type:uuid;not null;index"`
+	Level         Level  `json:"level" gorm:"not null;uniqueIndex:idx_retention_policies_resource"`
+	ResourceID    string `json:"resource_id" gorm:"not null;uniqueIndex:idx_retention_policies_resource"`
+	RetentionDays *int   `json:"retention_days"` // Nil keeps content forever, overriding a less specific policy
+}
+
+func (Policy) TableName() string {
+	return "retention_policies"
+}
+
+// KeepsForever reports whether the policy disables purging for its resource
+func (p *Policy) KeepsForever() bool {
+	return p.RetentionDays == nil
+}
+
+// Cutoff returns the creation time before which content under the policy is purged
+func (p *Policy) Cutoff(now time.Time) time.Time {
+	return now.AddDate(0, 0, -*p.RetentionDays)
+}
+
+// LegalHold suspends purging for a workspace, project or channel until it is released
+// Released holds are kept as a record of when preservation ended
+type LegalHold struct {
+	commonModels.BaseModel
+	WorkspaceID string     `json:"workspace_id" gorm:"type:uuid;not null;index"`
+	Level       Level      `json:"level" gorm:"not null;index:idx_legal_holds_resource"`
+	ResourceID  string     `json:"resource_id" gorm:"not null;index:idx_legal_holds_resource"`
+	Reason      string     `json:"reason"`
+	ReleasedAt  *time.Time `json:"released_at,omitempty" gorm:"index"`
+	ReleasedBy  *string    `json:"released_by,omitempty"`
+}
+
+// IsActive reports whether the hold still blocks purging
+func (h *LegalHold) IsActive() bool {
+	return h.ReleasedAt == nil
+}
@@ -0,0 +1,126 @@
+package dto
+
+import (
+	"thothix-backend/internal/shared/dto"
+)
+
+// === Retention policy DTOs ===
+
+// PolicyDto represents a retention policy in API responses
+type PolicyDto struct {
+	ID            string `json:"id"`
+	Level         string `json:"level"`
+	ResourceID    string `json:"resource_id"`
+	RetentionDays *int   `json:"retention_days"` // Null keeps content forever
+	UpdatedBy     string `json:"updated_by,omitempty"`
+	UpdatedAt     string `json:"updated_at"`
+}
+
+// SetPolicyRequest creates or replaces the policy of a resource
+// A null or missing retention_days keeps content forever, overriding a less specific policy
+type SetPolicyRequest struct {
+	Level         string `json:"level" binding:"required,oneof=workspace project channel"`
+	ResourceID    string `json:"resource_id"` // Defaults to the current workspace for workspace policies
+	RetentionDays *int   `json:"retention_days"`
+}
+
+// PolicyResponse wraps a single PolicyDto response
+type PolicyResponse struct {
+	*dto.Response[*PolicyDto]
+}
+
+func NewPolicyResponse(producer func() dto.Validation[*PolicyDto]) *PolicyResponse {
+	return &PolicyResponse{
+		Response: dto.NewResponse(producer),
+	}
+}
+
+// PolicyListResponse wraps a list of PolicyDto
+type PolicyListResponse struct {
+	*dto.Response[[]PolicyDto]
+}
+
+func NewPolicyListResponse(producer func() dto.Validation[[]PolicyDto]) *PolicyListResponse {
+	return &PolicyListResponse{
+		Response: dto.NewResponse(producer),
+	}
+}
+
+// === Legal hold DTOs ===
+
+// LegalHoldDto represents a legal hold in API responses
+type LegalHoldDto struct {
+	ID         string  `json:"id"`
+	Level      string  `json:"level"`
+	ResourceID string  `json:"resource_id"`
+	Reason     string  `json:"reason"`
+	Active     bool    `json:"active"`
+	CreatedBy  string  `json:"created_by,omitempty"`
+	CreatedAt  string  `json:"created_at"`
+	ReleasedBy *string `json:"released_by,omitempty"`
+	ReleasedAt string  `json:"released_at,omitempty"`
+}
+
+// CreateLegalHoldRequest places a legal hold on a resource
+type CreateLegalHoldRequest struct {
+	Level      string `json:"level" binding:"required,oneof=workspace project channel"`
+	ResourceID string `json:"resource_id"` // Defaults to the current workspace for workspace holds
+	Reason     string `json:"reason" binding:"required,max=500"`
+}
+
+// LegalHoldResponse wraps a single LegalHoldDto response
+type LegalHoldResponse struct {
+	*dto.Response[*LegalHoldDto]
+}
+
+func NewLegalHoldResponse(producer func() dto.Validation[*LegalHoldDto]) *LegalHoldResponse {
+	return &LegalHoldResponse{
+		Response: dto.NewResponse(producer),
+	}
+}
+
+// LegalHoldListResponse wraps a list of LegalHoldDto
+type LegalHoldListResponse struct {
+	*dto.Response[[]LegalHoldDto]
+}
+
+func NewLegalHoldListResponse(producer func() dto.Validation[[]LegalHoldDto]) *LegalHoldListResponse {
+	return &LegalHoldListResponse{
+		Response: dto.NewResponse(producer),
+	}
+}
+
+// === Dry-run report DTOs ===
+
+// ScopeReportDto describes what the purge would delete in one channel, or among the files of one project
+// that are not attached to a message
+type ScopeReportDto struct {
+	Level         string `json:"level"` // channel or project
+	ResourceID    string `json:"resource_id"`
+	Name          string `json:"name"`
+	PolicyLevel   string `json:"policy_level"` // Level of the policy that applies
+	RetentionDays int    `json:"retention_days"`
+	Cutoff        string `json:"cutoff"` // Content created before this time is purged
+	Held          bool   `json:"held"`   // A legal hold applies, so nothing is deleted
+	Messages      int64  `json:"messages"`
+	Files         int64  `json:"files"`
+}
+
+// RetentionReportDto is the dry-run report of the next purge in the current workspace
+type RetentionReportDto struct {
+	GeneratedAt string           `json:"generated_at"`
+	Scopes      []ScopeReportDto `json:"scopes"`
+	Messages    int64            `json:"messages"` // Total that would be deleted, held scopes excluded
+	Files       int64            `json:"files"`
+}
+
+// RetentionReportResponse wraps a RetentionReportDto response
+type RetentionReportResponse struct {
+	*dto.Response[*RetentionReportDto]
+}
+
+func NewRetentionReportResponse(producer func() dto.Validation[*RetentionReportDto]) *RetentionReportResponse {
+	return &RetentionReportResponse{
+		Response: dto.NewResponse(producer),
+	}
+}
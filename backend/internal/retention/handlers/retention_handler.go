@@ -0,0 +1,326 @@
+package handlers
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	retentionDto "thothix-backend/internal/retention/dto"
+	"thothix-backend/internal/retention/service"
+	"thothix-backend/internal/shared/constants"
+	"thothix-backend/internal/shared/dto"
+	"thothix-backend/internal/shared/handlers"
+)
+
+type RetentionHandler struct {
+	retentionService service.RetentionServiceInterface
+}
+
+func NewRetentionHandler(retentionService service.RetentionServiceInterface) *RetentionHandler {
+	return &RetentionHandler{
+		retentionService: retentionService,
+	}
+}
+
+// ListPolicies godoc
+// @Summary List retention policies
+// @Description List the retention policies of the current workspace, from the workspace policy to channel policies (admin only)
+// @Tags retention
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} retentionDto.PolicyDto
+// @Failure 403 {object} dto.ErrorViewModel
+// @Failure 500 {object} dto.ErrorViewModel
+// @Router /retention/policies [get]
+func (h *RetentionHandler) ListPolicies(c *gin.Context) {
+	wrapper := handlers.WrapContext(c)
+
+	response := h.scopedService(c).ListPolicies()
+
+	response.Match(
+		// Exception case
+		func(err error) interface{} {
+			wrapper.SystemErrorResponse(err, "Failed to retrieve retention policies")
+			return nil
+		},
+		// Success case
+		func(result []retentionDto.PolicyDto) interface{} {
+			wrapper.SuccessResponse(result)
+			return nil
+		},
+		// Validation failure case
+		func(errors []dto.Error) interface{} {
+			wrapper.ValidationErrorResponse(errors, "Retention policy list validation failed")
+			return nil
+		},
+	)
+}
+
+// SetPolicy godoc
+// @Summary Set a retention policy
+// @Description Create or replace the retention policy of the workspace, a project or a channel (admin only). The most specific policy wins; a null retention_days keeps content forever
+// @Tags retention
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param policy body retentionDto.SetPolicyRequest true "Policy"
+// @Success 200 {object} retentionDto.PolicyDto
+// @Failure 400 {object} dto.ErrorViewModel
+// @Failure 403 {object} dto.ErrorViewModel
+// @Failure 404 {object} dto.ErrorViewModel
+// @Failure 500 {object} dto.ErrorViewModel
+// @Router /retention/policies [put]
+func (h *RetentionHandler) SetPolicy(c *gin.Context) {
+	wrapper := handlers.WrapContext(c)
+
+	var request retentionDto.SetPolicyRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		wrapper.BadRequestErrorResponse("Invalid request payload")
+		return
+	}
+
+	response := h.scopedService(c).SetPolicy(&request)
+
+	response.Match(
+		// Exception case
+		func(err error) interface{} {
+			wrapper.SystemErrorResponse(err, "Failed to set retention policy of %s %s", request.Level, request.ResourceID)
+			return nil
+		},
+		// Success case
+		func(result *retentionDto.PolicyDto) interface{} {
+			wrapper.SuccessResponse(result)
+			return nil
+		},
+		// Validation failure case
+		func(errors []dto.Error) interface{} {
+			respondWithFailure(wrapper, errors, request.Level, request.ResourceID, "Retention policy validation failed")
+			return nil
+		},
+	)
+}
+
+// DeletePolicy godoc
+// @Summary Delete a retention policy
+// @Description Remove a retention policy so the less specific one applies again (admin only)
+// @Tags retention
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Policy ID"
+// @Success 204
+// @Failure 403 {object} dto.ErrorViewModel
+// @Failure 404 {object} dto.ErrorViewModel
+// @Failure 500 {object} dto.ErrorViewModel
+// @Router /retention/policies/{id} [delete]
+func (h *RetentionHandler) DeletePolicy(c *gin.Context) {
+	wrapper := handlers.WrapContext(c)
+	policyID := c.Param("id")
+
+	response := h.scopedService(c).DeletePolicy(policyID)
+
+	response.Match(
+		// Exception case
+		func(err error) interface{} {
+			wrapper.SystemErrorResponse(err, "Failed to delete retention policy: %s", policyID)
+			return nil
+		},
+		// Success case
+		func(result *retentionDto.PolicyDto) interface{} {
+			wrapper.NoContentResponse()
+			return nil
+		},
+		// Validation failure case
+		func(errors []dto.Error) interface{} {
+			respondWithFailure(wrapper, errors, "Retention policy", policyID, "Retention policy removal validation failed")
+			return nil
+		},
+	)
+}
+
+// ListLegalHolds godoc
+// @Summary List legal holds
+// @Description List the legal holds of the current workspace, most recent first (admin only)
+// @Tags retention
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param include_released query bool false "Include released holds"
+// @Success 200 {array} retentionDto.LegalHoldDto
+// @Failure 400 {object} dto.ErrorViewModel
+// @Failure 403 {object} dto.ErrorViewModel
+// @Failure 500 {object} dto.ErrorViewModel
+// @Router /retention/holds [get]
+func (h *RetentionHandler) ListLegalHolds(c *gin.Context) {
+	wrapper := handlers.WrapContext(c)
+
+	includeReleased := false
+	if value := c.Query("include_released"); value != "" {
+		parsed, err := strconv.ParseBool(value)
+		if err != nil {
+			wrapper.BadRequestErrorResponse("Invalid query parameters")
+			return
+		}
+		includeReleased = parsed
+	}
+
+	response := h.scopedService(c).ListLegalHolds(includeReleased)
+
+	response.Match(
+		// Exception case
+		func(err error) interface{} {
+			wrapper.SystemErrorResponse(err, "Failed to retrieve legal holds")
+			return nil
+		},
+		// Success case
+		func(result []retentionDto.LegalHoldDto) interface{} {
+			wrapper.SuccessResponse(result)
+			return nil
+		},
+		// Validation failure case
+		func(errors []dto.Error) interface{} {
+			wrapper.ValidationErrorResponse(errors, "Legal hold list validation failed")
+			return nil
+		},
+	)
+}
+
+// CreateLegalHold godoc
+// @Summary Place a legal hold
+// @Description Suspend the retention purge for the workspace, a project or a channel until the hold is released (admin only)
+// @Tags retention
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param hold body retentionDto.CreateLegalHoldRequest true "Legal hold"
+// @Success 201 {object} retentionDto.LegalHoldDto
+// @Failure 400 {object} dto.ErrorViewModel
+// @Failure 403 {object} dto.ErrorViewModel
+// @Failure 404 {object} dto.ErrorViewModel
+// @Failure 500 {object} dto.ErrorViewModel
+// @Router /retention/holds [post]
+func (h *RetentionHandler) CreateLegalHold(c *gin.Context) {
+	wrapper := handlers.WrapContext(c)
+
+	var request retentionDto.CreateLegalHoldRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		wrapper.BadRequestErrorResponse("Invalid request payload")
+		return
+	}
+
+	response := h.scopedService(c).CreateLegalHold(&request)
+
+	response.Match(
+		// Exception case
+		func(err error) interface{} {
+			wrapper.SystemErrorResponse(err, "Failed to place legal hold on %s %s", request.Level, request.ResourceID)
+			return nil
+		},
+		// Success case
+		func(result *retentionDto.LegalHoldDto) interface{} {
+			wrapper.CreatedResponse(result)
+			return nil
+		},
+		// Validation failure case
+		func(errors []dto.Error) interface{} {
+			respondWithFailure(wrapper, errors, request.Level, request.ResourceID, "Legal hold validation failed")
+			return nil
+		},
+	)
+}
+
+// ReleaseLegalHold godoc
+// @Summary Release a legal hold
+// @Description End a legal hold; the purge resumes on its next pass unless another hold applies (admin only)
+// @Tags retention
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Legal hold ID"
+// @Success 200 {object} retentionDto.LegalHoldDto
+// @Failure 403 {object} dto.ErrorViewModel
+// @Failure 404 {object} dto.ErrorViewModel
+// @Failure 409 {object} dto.ErrorViewModel
+// @Failure 500 {object} dto.ErrorViewModel
+// @Router /retention/holds/{id}/release [post]
+func (h *RetentionHandler) ReleaseLegalHold(c *gin.Context) {
+	wrapper := handlers.WrapContext(c)
+	holdID := c.Param("id")
+
+	response := h.scopedService(c).ReleaseLegalHold(holdID)
+
+	response.Match(
+		// Exception case
+		func(err error) interface{} {
+			wrapper.SystemErrorResponse(err, "Failed to release legal hold: %s", holdID)
+			return nil
+		},
+		// Success case
+		func(result *retentionDto.LegalHoldDto) interface{} {
+			wrapper.SuccessResponse(result)
+			return nil
+		},
+		// Validation failure case
+		func(errors []dto.Error) interface{} {
+			respondWithFailure(wrapper, errors, "Legal hold", holdID, "Legal hold release validation failed")
+			return nil
+		},
+	)
+}
+
+// Report godoc
+// @Summary Retention dry-run report
+// @Description List, per channel and project, what the next retention purge would delete in the current workspace, without deleting anything (admin only)
+// @Tags retention
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} retentionDto.RetentionReportDto
+// @Failure 403 {object} dto.ErrorViewModel
+// @Failure 500 {object} dto.ErrorViewModel
+// @Router /retention/report [get]
+func (h *RetentionHandler) Report(c *gin.Context) {
+	wrapper := handlers.WrapContext(c)
+
+	response := h.scopedService(c).Report()
+
+	response.Match(
+		// Exception case
+		func(err error) interface{} {
+			wrapper.SystemErrorResponse(err, "Failed to build retention report")
+			return nil
+		},
+		// Success case
+		func(result *retentionDto.RetentionReportDto) interface{} {
+			wrapper.SuccessResponse(result)
+			return nil
+		},
+		// Validation failure case
+		func(errors []dto.Error) interface{} {
+			wrapper.ValidationErrorResponse(errors, "Retention report validation failed")
+			return nil
+		},
+	)
+}
+
+// respondWithFailure maps service errors to not found, conflict or validation responses
+func respondWithFailure(wrapper *handlers.ContextWrapper, errors []dto.Error, resource, identifier, logMessage string) {
+	switch {
+	case len(errors) > 0 && errors[0].Code == constants.NotFoundError:
+		wrapper.NotFoundErrorResponse(resource, identifier)
+	case len(errors) > 0 && errors[0].Code == constants.ConflictError:
+		wrapper.ConflictErrorResponse(errors[0].Message)
+	default:
+		wrapper.ValidationErrorResponse(errors, "%s", logMessage)
+	}
+}
+
+// scopedService binds the service to the request context when supported,
+// so policies and holds are resolved in the current workspace and attributed to the admin
+func (h *RetentionHandler) scopedService(c *gin.Context) service.RetentionServiceInterface {
+	if aware, ok := h.retentionService.(service.ContextAwareRetentionService); ok {
+		return aware.WithContext(c.Request.Context())
+	}
+	return h.retentionService
+}
@@ -0,0 +1,148 @@
+package handlers
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	retentionDto "thothix-backend/internal/retention/dto"
+	"thothix-backend/internal/shared/dto"
+)
+
+// MockRetentionService is a mock implementation of the RetentionService
+type MockRetentionService struct {
+	mock.Mock
+}
+
+func (m *MockRetentionService) ListPolicies() *retentionDto.PolicyListResponse {
+	args := m.Called()
+	return args.Get(0).(*retentionDto.PolicyListResponse)
+}
+
+func (m *MockRetentionService) SetPolicy(req *retentionDto.SetPolicyRequest) *retentionDto.PolicyResponse {
+	args := m.Called(req)
+	return args.Get(0).(*retentionDto.PolicyResponse)
+}
+
+func (m *MockRetentionService) DeletePolicy(id string) *retentionDto.PolicyResponse {
+	args := m.Called(id)
+	return args.Get(0).(*retentionDto.PolicyResponse)
+}
+
+func (m *MockRetentionService) ListLegalHolds(includeReleased bool) *retentionDto.LegalHoldListResponse {
+	args := m.Called(includeReleased)
+	return args.Get(0).(*retentionDto.LegalHoldListResponse)
+}
+
+func (m *MockRetentionService) CreateLegalHold(req *retentionDto.CreateLegalHoldRequest) *retentionDto.LegalHoldResponse {
+	args := m.Called(req)
+	return args.Get(0).(*retentionDto.LegalHoldResponse)
+}
+
+func (m *MockRetentionService) ReleaseLegalHold(id string) *retentionDto.LegalHoldResponse {
+	args := m.Called(id)
+	return args.Get(0).(*retentionDto.LegalHoldResponse)
+}
+
+func (m *MockRetentionService) Report() *retentionDto.RetentionReportResponse {
+	args := m.Called()
+	return args.Get(0).(*retentionDto.RetentionReportResponse)
+}
+
+type RetentionHandlerTestSuite struct {
+	suite.Suite
+	mockService *MockRetentionService
+	router      *gin.Engine
+}
+
+func (suite *RetentionHandlerTestSuite) SetupSuite() {
+	gin.SetMode(gin.TestMode)
+}
+
+func (suite *RetentionHandlerTestSuite) SetupTest() {
+	suite.mockService = new(MockRetentionService)
+	handler := NewRetentionHandler(suite.mockService)
+
+	suite.router = gin.New()
+	suite.router.PUT("/retention/policies", handler.SetPolicy)
+	suite.router.POST("/retention/holds/:id/release", handler.ReleaseLegalHold)
+	suite.router.GET("/retention/report", handler.Report)
+}
+
+func (suite *RetentionHandlerTestSuite) TestSetPolicy_InvalidLevel() {
+	// Act
+	req, _ := http.NewRequest("PUT", "/retention/policies", bytes.NewBufferString(`{"level":"user","retention_days":30}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(suite.T(), http.StatusBadRequest, w.Code)
+	suite.mockService.AssertNotCalled(suite.T(), "SetPolicy", mock.Anything)
+}
+
+func (suite *RetentionHandlerTestSuite) TestSetPolicy_NullKeepsForever() {
+	// Arrange
+	mockResponse := retentionDto.NewPolicyResponse(func() dto.Validation[*retentionDto.PolicyDto] {
+		return dto.Success(&retentionDto.PolicyDto{ID: "policy-1", Level: "channel", ResourceID: "channel-1"})
+	})
+
+	suite.mockService.On("SetPolicy", &retentionDto.SetPolicyRequest{Level: "channel", ResourceID: "channel-1"}).Return(mockResponse)
+
+	// Act
+	req, _ := http.NewRequest("PUT", "/retention/policies", bytes.NewBufferString(`{"level":"channel","resource_id":"channel-1","retention_days":null}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(suite.T(), http.StatusOK, w.Code)
+	assert.Contains(suite.T(), w.Body.String(), `"retention_days":null`)
+}
+
+func (suite *RetentionHandlerTestSuite) TestReleaseLegalHold_AlreadyReleased() {
+	// Arrange
+	mockResponse := retentionDto.NewLegalHoldResponse(func() dto.Validation[*retentionDto.LegalHoldDto] {
+		return dto.Invalid[*retentionDto.LegalHoldDto](dto.NewError("CONFLICT", "Legal hold was already released", nil))
+	})
+
+	suite.mockService.On("ReleaseLegalHold", "hold-1").Return(mockResponse)
+
+	// Act
+	req, _ := http.NewRequest("POST", "/retention/holds/hold-1/release", nil)
+	w := httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(suite.T(), http.StatusConflict, w.Code)
+}
+
+func (suite *RetentionHandlerTestSuite) TestReport() {
+	// Arrange
+	mockResponse := retentionDto.NewRetentionReportResponse(func() dto.Validation[*retentionDto.RetentionReportDto] {
+		return dto.Success(&retentionDto.RetentionReportDto{
+			Scopes:   []retentionDto.ScopeReportDto{{Level: "channel", ResourceID: "channel-1", Messages: 12}},
+			Messages: 12,
+		})
+	})
+
+	suite.mockService.On("Report").Return(mockResponse)
+
+	// Act
+	req, _ := http.NewRequest("GET", "/retention/report", nil)
+	w := httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(suite.T(), http.StatusOK, w.Code)
+	assert.Contains(suite.T(), w.Body.String(), "channel-1")
+}
+
+func TestRetentionHandlerTestSuite(t *testing.T) {
+	suite.Run(t, new(RetentionHandlerTestSuite))
+}
@@ -0,0 +1,25 @@
+package handlers
+
+import (
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"thothix-backend/internal/middleware"
+	"thothix-backend/internal/retention/service"
+	sharedModels "thothix-backend/internal/shared/models"
+)
+
+// RegisterRetentionRoutes registers the admin-only retention policy, legal hold and report routes of the current workspace
+func RegisterRetentionRoutes(router *gin.RouterGroup, db *gorm.DB) {
+	retentionService := service.NewRetentionService(db)
+	retentionHandler := NewRetentionHandler(retentionService)
+
+	retention := router.Group("/retention")
+	retention.Use(middleware.RequireSystemRole(db, sharedModels.RoleAdmin))
+	retention.GET("/policies", retentionHandler.ListPolicies)
+	retention.PUT("/policies", retentionHandler.SetPolicy)
+	retention.DELETE("/policies/:id", retentionHandler.DeletePolicy)
+	retention.GET("/holds", retentionHandler.ListLegalHolds)
+	retention.POST("/holds", retentionHandler.CreateLegalHold)
+	retention.POST("/holds/:id/release", retentionHandler.ReleaseLegalHold)
+	retention.GET("/report", retentionHandler.Report)
+}
@@ -0,0 +1,72 @@
+package mappers
+
+import (
+	"time"
+
+	"thothix-backend/internal/retention/domain"
+	retentionDto "thothix-backend/internal/retention/dto"
+)
+
+// RetentionMapper handles conversion between retention policies, legal holds and their DTOs
+type RetentionMapper struct{}
+
+// NewRetentionMapper creates a new RetentionMapper instance
+func NewRetentionMapper() *RetentionMapper {
+	return &RetentionMapper{}
+}
+
+// PolicyToDto converts a Policy to PolicyDto
+func (m *RetentionMapper) PolicyToDto(policy *domain.Policy) *retentionDto.PolicyDto {
+	if policy == nil {
+		return nil
+	}
+
+	return &retentionDto.PolicyDto{
+		ID:            policy.ID,
+		Level:         string(policy.Level),
+		ResourceID:    policy.ResourceID,
+		RetentionDays: policy.RetentionDays,
+		UpdatedBy:     policy.UpdatedBy,
+		UpdatedAt:     policy.UpdatedAt.UTC().Format(time.RFC3339),
+	}
+}
+
+// PoliciesToDtos converts a slice of Policy to PolicyDto
+func (m *RetentionMapper) PoliciesToDtos(policies []domain.Policy) []retentionDto.PolicyDto {
+	dtos := make([]retentionDto.PolicyDto, 0, len(policies))
+	for i := range policies {
+		dtos = append(dtos, *m.PolicyToDto(&policies[i]))
+	}
+	return dtos
+}
+
+// LegalHoldToDto converts a LegalHold to LegalHoldDto
+func (m *RetentionMapper) LegalHoldToDto(hold *domain.LegalHold) *retentionDto.LegalHoldDto {
+	if hold == nil {
+		return nil
+	}
+
+	dto := &retentionDto.LegalHoldDto{
+		ID:         hold.ID,
+		Level:      string(hold.Level),
+		ResourceID: hold.ResourceID,
+		Reason:     hold.Reason,
+		Active:     hold.IsActive(),
+		CreatedBy:  hold.CreatedBy,
+		CreatedAt:  hold.CreatedAt.UTC().Format(time.RFC3339),
+		ReleasedBy: hold.ReleasedBy,
+	}
+	if hold.ReleasedAt != nil {
+		dto.ReleasedAt = hold.ReleasedAt.UTC().Format(time.RFC3339)
+	}
+	return dto
+}
+
+// LegalHoldsToDtos converts a slice of LegalHold to LegalHoldDto
+func (m *RetentionMapper) LegalHoldsToDtos(holds []domain.LegalHold) []retentionDto.LegalHoldDto {
+	dtos := make([]retentionDto.LegalHoldDto, 0, len(holds))
+	for i := range holds {
+		dtos = append(dtos, *m.LegalHoldToDto(&holds[i]))
+	}
+	return dtos
+}
@@ -0,0 +1,184 @@
+package purger
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	auditDomain "thothix-backend/internal/audit/domain"
+	auditHooks "thothix-backend/internal/audit/hooks"
+	"thothix-backend/internal/health"
+	"thothix-backend/internal/retention/domain"
+	"thothix-backend/internal/retention/service"
+	"thothix-backend/internal/shared/logging"
+)
+
+// batchSize is the number of messages, or project files, deleted per transaction
+const batchSize = 500
+
+// Purger deletes the messages and files that outlived their retention policy
+// Each batch runs in its own transaction that re-checks legal holds, locks its rows with SKIP LOCKED
+// and records one audit entry, so replicas can purge concurrently and a hold placed mid-purge stops it
+type Purger struct {
+	db  *gorm.DB
+	now func() time.Time
+}
+
+// PurgeResult counts what a pass did
+type PurgeResult struct {
+	Messages int // Messages deleted
+	Files    int // Files deleted, with their message or on their own
+	Held     int // Scopes skipped because of a legal hold
+}
+
+func NewPurger(db *gorm.DB) *Purger {
+	return &Purger{db: db, now: time.Now}
+}
+
+// Run makes a pass every interval until ctx is cancelled, beating heartbeat as each pass starts
+func (p *Purger) Run(ctx context.Context, interval time.Duration, heartbeat *health.Heartbeat) {
+	logger := logging.FromContext(ctx)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		heartbeat.Beat()
+		result, err := p.RunOnce(ctx)
+		switch {
+		case err != nil && ctx.Err() == nil:
+			logger.Error("Retention purge failed", slog.Any("error", err))
+		case err == nil && (result.Messages > 0 || result.Files > 0):
+			logger.Info("Retention purge completed",
+				slog.Int("messages", result.Messages), slog.Int("files", result.Files), slog.Int("held", result.Held))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunOnce purges every scope of every workspace with a retention policy
+func (p *Purger) RunOnce(ctx context.Context) (PurgeResult, error) {
+	var result PurgeResult
+	db := p.db.WithContext(ctx)
+
+	scopes, err := service.Scopes(db, "")
+	if err != nil {
+		return result, fmt.Errorf("resolve retention scopes: %w", err)
+	}
+
+	now := p.now()
+	for i := range scopes {
+		scope := &scopes[i]
+		if scope.Held {
+			result.Held++
+			continue
+		}
+		if err := p.purge(db, scope, scope.Cutoff(now), &result); err != nil {
+			return result, fmt.Errorf("purge %s %s: %w", scope.Level, scope.ResourceID, err)
+		}
+	}
+	return result, nil
+}
+
+// purge deletes the expired content of one scope, a batch at a time
+func (p *Purger) purge(db *gorm.DB, scope *service.Scope, cutoff time.Time, result *PurgeResult) error {
+	for {
+		var messages, files int64
+		held := false
+
+		err := db.Transaction(func(tx *gorm.DB) error {
+			var err error
+			if held, err = service.IsHeld(tx, scope); err != nil || held {
+				return err
+			}
+
+			if scope.Level == domain.LevelChannel {
+				messages, files, err = purgeMessages(tx, scope, cutoff)
+			} else {
+				files, err = purgeProjectFiles(tx, scope, cutoff)
+			}
+			if err != nil || messages+files == 0 {
+				return err
+			}
+
+			return auditHooks.Record(tx, auditDomain.AuditActionPurge, string(scope.Level)+"s", scope.ResourceID, map[string]interface{}{
+				"messages":       messages,
+				"files":          files,
+				"cutoff":         cutoff.UTC().Format(time.RFC3339),
+				"policy_level":   scope.Policy.Level,
+				"retention_days": *scope.Policy.RetentionDays,
+			})
+		})
+		if err != nil {
+			return err
+		}
+
+		if held {
+			result.Held++
+			return nil
+		}
+		result.Messages += int(messages)
+		result.Files += int(files)
+
+		// A short batch means nothing is left; rows locked by another replica are its to delete
+		batch := messages
+		if scope.Level == domain.LevelProject {
+			batch = files
+		}
+		if batch < batchSize {
+			return nil
+		}
+	}
+}
+
+// purgeMessages deletes a batch of expired messages of a channel with their files
+// Search tokens, pins and saved items go with the messages through their foreign keys;
+// moderation reports have none and stay as records, without the excerpt of the message
+func purgeMessages(tx *gorm.DB, scope *service.Scope, cutoff time.Time) (int64, int64, error) {
+	var ids []string
+	if err := service.ExpiredMessages(tx, scope, cutoff).
+		Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+		Order("created_at").Limit(batchSize).Pluck("id", &ids).Error; err != nil {
+		return 0, 0, err
+	}
+	if len(ids) == 0 {
+		return 0, 0, nil
+	}
+
+	files := tx.Exec("DELETE FROM files WHERE message_id IN ?", ids)
+	if files.Error != nil {
+		return 0, 0, files.Error
+	}
+	if err := tx.Exec("UPDATE moderation_reports SET excerpt = '' WHERE message_id IN ?", ids).Error; err != nil {
+		return 0, 0, err
+	}
+	messages := tx.Exec("DELETE FROM messages WHERE id IN ?", ids)
+	if messages.Error != nil {
+		return 0, 0, messages.Error
+	}
+	return messages.RowsAffected, files.RowsAffected, nil
+}
+
+// purgeProjectFiles deletes a batch of expired project files that are not attached to a message
+func purgeProjectFiles(tx *gorm.DB, scope *service.Scope, cutoff time.Time) (int64, error) {
+	var ids []string
+	if err := service.ExpiredFiles(tx, scope, cutoff).
+		Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+		Order("created_at").Limit(batchSize).Pluck("id", &ids).Error; err != nil {
+		return 0, err
+	}
+	if len(ids) == 0 {
+		return 0, nil
+	}
+
+	files := tx.Exec("DELETE FROM files WHERE id IN ?", ids)
+	return files.RowsAffected, files.Error
+}
@@ -0,0 +1,124 @@
+package purger
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/gorm"
+	auditDomain "thothix-backend/internal/audit/domain"
+	chatDomain "thothix-backend/internal/chat/domain"
+	messageDomain "thothix-backend/internal/message/domain"
+	moderationDomain "thothix-backend/internal/moderation/domain"
+	projectDomain "thothix-backend/internal/project/domain"
+	"thothix-backend/internal/retention/domain"
+	sharedTesting "thothix-backend/internal/shared/testing"
+	workspaceDomain "thothix-backend/internal/workspace/domain"
+)
+
+type PurgerTestSuite struct {
+	suite.Suite
+	container *sharedTesting.PostgresTestContainer
+}
+
+func (suite *PurgerTestSuite) SetupSuite() {
+	suite.container = sharedTesting.GetSharedTestContainer(
+		suite.T(),
+		"retention/purger",
+		[]interface{}{
+			&workspaceDomain.Workspace{}, &projectDomain.Project{}, &chatDomain.Channel{},
+			&messageDomain.Message{}, &messageDomain.File{}, &auditDomain.AuditLog{},
+			&moderationDomain.Report{}, &domain.Policy{}, &domain.LegalHold{},
+		},
+	)
+}
+
+// createMessage stores a channel message created daysAgo, with one attached file
+func (suite *PurgerTestSuite) createMessage(db *gorm.DB, channel *chatDomain.Channel, daysAgo int) *messageDomain.Message {
+	message := &messageDomain.Message{SenderID: "user-1", ChannelID: &channel.ID, Content: "message"}
+	message.CreatedAt = time.Now().AddDate(0, 0, -daysAgo)
+	assert.NoError(suite.T(), db.Create(message).Error)
+	assert.NoError(suite.T(), db.Create(&messageDomain.File{MessageID: &message.ID, URL: "https://files.example.com/a"}).Error)
+	return message
+}
+
+func (suite *PurgerTestSuite) TestRunOnce_PurgesExpiredContentAndHonorsHolds() {
+	suite.container.WithTransaction(func(db *gorm.DB) {
+		// Arrange
+		workspace := &workspaceDomain.Workspace{Name: "purge", Slug: "purge"}
+		assert.NoError(suite.T(), db.Create(workspace).Error)
+		project := &projectDomain.Project{WorkspaceID: workspace.ID, Name: "purge"}
+		assert.NoError(suite.T(), db.Create(project).Error)
+		general := &chatDomain.Channel{WorkspaceID: workspace.ID, ProjectID: project.ID, Name: "general"}
+		held := &chatDomain.Channel{WorkspaceID: workspace.ID, ProjectID: project.ID, Name: "held"}
+		assert.NoError(suite.T(), db.Create(general).Error)
+		assert.NoError(suite.T(), db.Create(held).Error)
+
+		expired := suite.createMessage(db, general, 40)
+		recent := suite.createMessage(db, general, 5)
+		preserved := suite.createMessage(db, held, 40)
+		report := &moderationDomain.Report{WorkspaceID: workspace.ID, MessageID: expired.ID, AuthorID: "user-1", Reason: moderationDomain.ReasonSpam, Excerpt: expired.Content}
+		assert.NoError(suite.T(), db.Create(report).Error)
+		looseFile := &messageDomain.File{ProjectID: &project.ID, URL: "https://files.example.com/b"}
+		looseFile.CreatedAt = time.Now().AddDate(0, 0, -40)
+		assert.NoError(suite.T(), db.Create(looseFile).Error)
+
+		days := 30
+		assert.NoError(suite.T(), db.Create(&domain.Policy{WorkspaceID: workspace.ID, Level: domain.LevelWorkspace, ResourceID: workspace.ID, RetentionDays: &days}).Error)
+		assert.NoError(suite.T(), db.Create(&domain.LegalHold{WorkspaceID: workspace.ID, Level: domain.LevelChannel, ResourceID: held.ID, Reason: "litigation"}).Error)
+
+		// Act
+		result, err := NewPurger(db).RunOnce(context.Background())
+
+		// Assert
+		assert.NoError(suite.T(), err)
+		assert.Equal(suite.T(), PurgeResult{Messages: 1, Files: 2, Held: 1}, result)
+
+		var remaining []string
+		assert.NoError(suite.T(), db.Model(&messageDomain.Message{}).Pluck("id", &remaining).Error)
+		assert.ElementsMatch(suite.T(), []string{recent.ID, preserved.ID}, remaining)
+		assert.NotContains(suite.T(), remaining, expired.ID)
+
+		var files int64
+		assert.NoError(suite.T(), db.Model(&messageDomain.File{}).Count(&files).Error)
+		assert.Equal(suite.T(), int64(2), files)
+
+		var stored moderationDomain.Report
+		assert.NoError(suite.T(), db.First(&stored, "id = ?", report.ID).Error)
+		assert.Empty(suite.T(), stored.Excerpt)
+
+		var purges int64
+		assert.NoError(suite.T(), db.Model(&auditDomain.AuditLog{}).Where("action = ?", auditDomain.AuditActionPurge).Count(&purges).Error)
+		assert.Equal(suite.T(), int64(2), purges)
+	})
+}
+
+func (suite *PurgerTestSuite) TestRunOnce_KeepForeverOverridesWorkspacePolicy() {
+	suite.container.WithTransaction(func(db *gorm.DB) {
+		// Arrange
+		workspace := &workspaceDomain.Workspace{Name: "purge-forever", Slug: "purge-forever"}
+		assert.NoError(suite.T(), db.Create(workspace).Error)
+		project := &projectDomain.Project{WorkspaceID: workspace.ID, Name: "archive"}
+		assert.NoError(suite.T(), db.Create(project).Error)
+		channel := &chatDomain.Channel{WorkspaceID: workspace.ID, ProjectID: project.ID, Name: "archive"}
+		assert.NoError(suite.T(), db.Create(channel).Error)
+		suite.createMessage(db, channel, 400)
+
+		days := 7
+		assert.NoError(suite.T(), db.Create(&domain.Policy{WorkspaceID: workspace.ID, Level: domain.LevelWorkspace, ResourceID: workspace.ID, RetentionDays: &days}).Error)
+		assert.NoError(suite.T(), db.Create(&domain.Policy{WorkspaceID: workspace.ID, Level: domain.LevelProject, ResourceID: project.ID}).Error)
+
+		// Act
+		result, err := NewPurger(db).RunOnce(context.Background())
+
+		// Assert
+		assert.NoError(suite.T(), err)
+		assert.Equal(suite.T(), PurgeResult{}, result)
+	})
+}
+
+func TestPurgerTestSuite(t *testing.T) {
+	suite.Run(t, new(PurgerTestSuite))
+}
@@ -0,0 +1,285 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+
+	"thothix-backend/internal/retention/domain"
+	retentionDto "thothix-backend/internal/retention/dto"
+	"thothix-backend/internal/retention/mappers"
+	"thothix-backend/internal/shared/constants"
+	"thothix-backend/internal/shared/dto"
+	sharedMiddleware "thothix-backend/internal/shared/middleware"
+)
+
+type RetentionService struct {
+	db     *gorm.DB
+	mapper *mappers.RetentionMapper
+	now    func() time.Time
+}
+
+func NewRetentionService(db *gorm.DB) *RetentionService {
+	return &RetentionService{
+		db:     db,
+		mapper: mappers.NewRetentionMapper(),
+		now:    time.Now,
+	}
+}
+
+// WithContext returns a copy of the service whose database session carries ctx
+func (s *RetentionService) WithContext(ctx context.Context) *RetentionService {
+	return &RetentionService{
+		db:     s.db.WithContext(ctx),
+		mapper: s.mapper,
+		now:    s.now,
+	}
+}
+
+// ListPolicies lists the retention policies of the current workspace, from the least to the most specific
+func (s *RetentionService) ListPolicies() *retentionDto.PolicyListResponse {
+	return retentionDto.NewPolicyListResponse(func() dto.Validation[[]retentionDto.PolicyDto] {
+		workspaceID, failure := s.workspace()
+		if failure != nil {
+			return dto.Failure[[]retentionDto.PolicyDto](*failure)
+		}
+
+		var policies []domain.Policy
+		if err := s.db.Where("workspace_id = ?", workspaceID).
+			Order(fmt.Sprintf("CASE level WHEN '%s' THEN 0 WHEN '%s' THEN 1 ELSE 2 END, created_at", domain.LevelWorkspace, domain.LevelProject)).
+			Find(&policies).Error; err != nil {
+			panic(err)
+		}
+		return dto.Success(s.mapper.PoliciesToDtos(policies))
+	})
+}
+
+// SetPolicy creates the policy of a workspace, project or channel, or replaces its retention period
+func (s *RetentionService) SetPolicy(req *retentionDto.SetPolicyRequest) *retentionDto.PolicyResponse {
+	return retentionDto.NewPolicyResponse(func() dto.Validation[*retentionDto.PolicyDto] {
+		if req.RetentionDays != nil && (*req.RetentionDays < domain.MinRetentionDays || *req.RetentionDays > domain.MaxRetentionDays) {
+			return dto.Failure[*retentionDto.PolicyDto](dto.NewError(constants.ValidationError,
+				fmt.Sprintf("Retention must be between %d and %d days, or null to keep forever", domain.MinRetentionDays, domain.MaxRetentionDays),
+				map[string]string{"retention_days": "out of range"}))
+		}
+
+		workspaceID, level, resourceID, failure := s.resource(req.Level, req.ResourceID)
+		if failure != nil {
+			return dto.Failure[*retentionDto.PolicyDto](*failure)
+		}
+
+		var policy domain.Policy
+		err := s.db.Where("level = ? AND resource_id = ?", level, resourceID).First(&policy).Error
+		switch {
+		case err == gorm.ErrRecordNotFound:
+			policy = domain.Policy{WorkspaceID: workspaceID, Level: level, ResourceID: resourceID, RetentionDays: req.RetentionDays}
+			if err := s.db.Create(&policy).Error; err != nil {
+				panic(err)
+			}
+		case err != nil:
+			panic(err)
+		default:
+			policy.RetentionDays = req.RetentionDays
+			// Select so a null period, which keeps content forever, is written too
+			if err := s.db.Model(&policy).Select("retention_days").Updates(&policy).Error; err != nil {
+				panic(err)
+			}
+		}
+		return dto.Success(s.mapper.PolicyToDto(&policy))
+	})
+}
+
+// DeletePolicy removes a policy, so the less specific one applies again
+func (s *RetentionService) DeletePolicy(id string) *retentionDto.PolicyResponse {
+	return retentionDto.NewPolicyResponse(func() dto.Validation[*retentionDto.PolicyDto] {
+		workspaceID, failure := s.workspace()
+		if failure != nil {
+			return dto.Failure[*retentionDto.PolicyDto](*failure)
+		}
+
+		var policy domain.Policy
+		if err := s.db.Where("id::text = ? AND workspace_id = ?", id, workspaceID).First(&policy).Error; err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return dto.Invalid[*retentionDto.PolicyDto](dto.NewError(constants.NotFoundError, "Retention policy not found", nil))
+			}
+			panic(err)
+		}
+		if err := s.db.Delete(&policy).Error; err != nil {
+			panic(err)
+		}
+		return dto.Success(s.mapper.PolicyToDto(&policy))
+	})
+}
+
+// ListLegalHolds lists the active legal holds of the current workspace, and the released ones when asked
+func (s *RetentionService) ListLegalHolds(includeReleased bool) *retentionDto.LegalHoldListResponse {
+	return retentionDto.NewLegalHoldListResponse(func() dto.Validation[[]retentionDto.LegalHoldDto] {
+		workspaceID, failure := s.workspace()
+		if failure != nil {
+			return dto.Failure[[]retentionDto.LegalHoldDto](*failure)
+		}
+
+		query := s.db.Where("workspace_id = ?", workspaceID)
+		if !includeReleased {
+			query = query.Where("released_at IS NULL")
+		}
+		var holds []domain.LegalHold
+		if err := query.Order("created_at DESC").Find(&holds).Error; err != nil {
+			panic(err)
+		}
+		return dto.Success(s.mapper.LegalHoldsToDtos(holds))
+	})
+}
+
+// CreateLegalHold suspends purging for a workspace, project or channel
+func (s *RetentionService) CreateLegalHold(req *retentionDto.CreateLegalHoldRequest) *retentionDto.LegalHoldResponse {
+	return retentionDto.NewLegalHoldResponse(func() dto.Validation[*retentionDto.LegalHoldDto] {
+		workspaceID, level, resourceID, failure := s.resource(req.Level, req.ResourceID)
+		if failure != nil {
+			return dto.Failure[*retentionDto.LegalHoldDto](*failure)
+		}
+
+		hold := domain.LegalHold{WorkspaceID: workspaceID, Level: level, ResourceID: resourceID, Reason: req.Reason}
+		if err := s.db.Create(&hold).Error; err != nil {
+			panic(err)
+		}
+		return dto.Success(s.mapper.LegalHoldToDto(&hold))
+	})
+}
+
+// ReleaseLegalHold ends a legal hold; the purge resumes on its next pass unless another hold applies
+func (s *RetentionService) ReleaseLegalHold(id string) *retentionDto.LegalHoldResponse {
+	return retentionDto.NewLegalHoldResponse(func() dto.Validation[*retentionDto.LegalHoldDto] {
+		workspaceID, failure := s.workspace()
+		if failure != nil {
+			return dto.Failure[*retentionDto.LegalHoldDto](*failure)
+		}
+
+		var hold domain.LegalHold
+		if err := s.db.Where("id::text = ? AND workspace_id = ?", id, workspaceID).First(&hold).Error; err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return dto.Invalid[*retentionDto.LegalHoldDto](dto.NewError(constants.NotFoundError, "Legal hold not found", nil))
+			}
+			panic(err)
+		}
+		if !hold.IsActive() {
+			return dto.Invalid[*retentionDto.LegalHoldDto](dto.NewError(constants.ConflictError, "Legal hold was already released", nil))
+		}
+
+		now := s.now().UTC()
+		hold.ReleasedAt = &now
+		if userID, ok := sharedMiddleware.UserIDFromContext(s.db.Statement.Context); ok {
+			hold.ReleasedBy = &userID
+		}
+		if err := s.db.Model(&hold).Select("released_at", "released_by").Updates(&hold).Error; err != nil {
+			panic(err)
+		}
+		return dto.Success(s.mapper.LegalHoldToDto(&hold))
+	})
+}
+
+// Report lists what the next purge would delete in the current workspace, without deleting anything
+func (s *RetentionService) Report() *retentionDto.RetentionReportResponse {
+	return retentionDto.NewRetentionReportResponse(func() dto.Validation[*retentionDto.RetentionReportDto] {
+		workspaceID, failure := s.workspace()
+		if failure != nil {
+			return dto.Failure[*retentionDto.RetentionReportDto](*failure)
+		}
+
+		scopes, err := Scopes(s.db, workspaceID)
+		if err != nil {
+			panic(err)
+		}
+
+		now := s.now().UTC()
+		report := &retentionDto.RetentionReportDto{
+			GeneratedAt: now.Format(time.RFC3339),
+			Scopes:      make([]retentionDto.ScopeReportDto, 0, len(scopes)),
+		}
+		for i := range scopes {
+			scope := &scopes[i]
+			cutoff := scope.Cutoff(now)
+			entry := retentionDto.ScopeReportDto{
+				Level:         string(scope.Level),
+				ResourceID:    scope.ResourceID,
+				Name:          scope.Name,
+				PolicyLevel:   string(scope.Policy.Level),
+				RetentionDays: *scope.Policy.RetentionDays,
+				Cutoff:        cutoff.Format(time.RFC3339),
+				Held:          scope.Held,
+			}
+			if scope.Level == domain.LevelChannel {
+				if err := ExpiredMessages(s.db, scope, cutoff).Count(&entry.Messages).Error; err != nil {
+					panic(err)
+				}
+			}
+			if err := ExpiredFiles(s.db, scope, cutoff).Count(&entry.Files).Error; err != nil {
+				panic(err)
+			}
+			if entry.Messages == 0 && entry.Files == 0 {
+				continue
+			}
+
+			report.Scopes = append(report.Scopes, entry)
+			if !entry.Held {
+				report.Messages += entry.Messages
+				report.Files += entry.Files
+			}
+		}
+		return dto.Success(report)
+	})
+}
+
+// workspace returns the current workspace
+func (s *RetentionService) workspace() (string, *dto.Error) {
+	workspaceID, ok := sharedMiddleware.WorkspaceIDFromContext(s.db.Statement.Context)
+	if !ok {
+		err := dto.NewError(constants.ValidationError, "A current workspace is required", nil)
+		return "", &err
+	}
+	return workspaceID, nil
+}
+
+// resource validates the target of a policy or a hold, which must belong to the current workspace
+// Workspace-level targets default to the current workspace
+func (s *RetentionService) resource(rawLevel, resourceID string) (string, domain.Level, string, *dto.Error) {
+	workspaceID, failure := s.workspace()
+	if failure != nil {
+		return "", "", "", failure
+	}
+
+	fail := func(code, message string) (string, domain.Level, string, *dto.Error) {
+		err := dto.NewError(code, message, nil)
+		return "", "", "", &err
+	}
+
+	level := domain.Level(rawLevel)
+	switch level {
+	case domain.LevelWorkspace:
+		if resourceID != "" && resourceID != workspaceID {
+			return fail(constants.ValidationError, "Workspace policies and holds apply to the current workspace")
+		}
+		return workspaceID, level, workspaceID, nil
+	case domain.LevelProject, domain.LevelChannel:
+		if resourceID == "" {
+			return fail(constants.ValidationError, "resource_id is required")
+		}
+	default:
+		return fail(constants.ValidationError, "Level must be workspace, project or channel")
+	}
+
+	table, name := "projects", "Project"
+	if level == domain.LevelChannel {
+		table, name = "channels", "Channel"
+	}
+	var id string
+	if err := s.db.Table(table).Select("id").Where("id::text = ? AND workspace_id = ?", resourceID, workspaceID).Scan(&id).Error; err != nil {
+		panic(err)
+	}
+	if id == "" {
+		return fail(constants.NotFoundError, name+" not found")
+	}
+	return workspaceID, level, id, nil
+}
@@ -0,0 +1,23 @@
+package service
+
+import (
+	"context"
+
+	retentionDto "thothix-backend/internal/retention/dto"
+)
+
+// RetentionServiceInterface defines the contract for retention policies and legal holds using Response pattern
+type RetentionServiceInterface interface {
+	ListPolicies() *retentionDto.PolicyListResponse
+	SetPolicy(req *retentionDto.SetPolicyRequest) *retentionDto.PolicyResponse
+	DeletePolicy(id string) *retentionDto.PolicyResponse
+	ListLegalHolds(includeReleased bool) *retentionDto.LegalHoldListResponse
+	CreateLegalHold(req *retentionDto.CreateLegalHoldRequest) *retentionDto.LegalHoldResponse
+	ReleaseLegalHold(id string) *retentionDto.LegalHoldResponse
+	Report() *retentionDto.RetentionReportResponse
+}
+
+// ContextAwareRetentionService is implemented by services that can bind their database session to a request context
+type ContextAwareRetentionService interface {
+	WithContext(ctx context.Context) *RetentionService
+}
@@ -0,0 +1,144 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/gorm"
+	chatDomain "thothix-backend/internal/chat/domain"
+	messageDomain "thothix-backend/internal/message/domain"
+	projectDomain "thothix-backend/internal/project/domain"
+	"thothix-backend/internal/retention/domain"
+	retentionDto "thothix-backend/internal/retention/dto"
+	sharedMiddleware "thothix-backend/internal/shared/middleware"
+	sharedTesting "thothix-backend/internal/shared/testing"
+	workspaceDomain "thothix-backend/internal/workspace/domain"
+)
+
+type RetentionServiceTestSuite struct {
+	suite.Suite
+	container *sharedTesting.PostgresTestContainer
+}
+
+func (suite *RetentionServiceTestSuite) SetupSuite() {
+	suite.container = sharedTesting.GetSharedTestContainer(
+		suite.T(),
+		"retention/service",
+		[]interface{}{
+			&workspaceDomain.Workspace{}, &projectDomain.Project{}, &chatDomain.Channel{},
+			&messageDomain.Message{}, &messageDomain.File{}, &domain.Policy{}, &domain.LegalHold{},
+		},
+	)
+}
+
+// fixture is a workspace with a project holding a general and a legal channel, each with one old message
+type fixture struct {
+	workspace *workspaceDomain.Workspace
+	project   *projectDomain.Project
+	general   *chatDomain.Channel
+	legal     *chatDomain.Channel
+}
+
+func (suite *RetentionServiceTestSuite) createFixture(db *gorm.DB, slug string) *fixture {
+	workspace := &workspaceDomain.Workspace{Name: slug, Slug: slug}
+	assert.NoError(suite.T(), db.Create(workspace).Error)
+	project := &projectDomain.Project{WorkspaceID: workspace.ID, Name: "compliance"}
+	assert.NoError(suite.T(), db.Create(project).Error)
+
+	f := &fixture{workspace: workspace, project: project}
+	for _, channel := range []**chatDomain.Channel{&f.general, &f.legal} {
+		*channel = &chatDomain.Channel{WorkspaceID: workspace.ID, ProjectID: project.ID, Name: uuid.New().String()}
+		assert.NoError(suite.T(), db.Create(*channel).Error)
+		old := &messageDomain.Message{SenderID: "user-1", ChannelID: &(*channel).ID, Content: "old"}
+		old.CreatedAt = time.Now().AddDate(0, 0, -60)
+		assert.NoError(suite.T(), db.Create(old).Error)
+	}
+	return f
+}
+
+// serviceFor creates a service acting as an admin in workspace
+func (suite *RetentionServiceTestSuite) serviceFor(db *gorm.DB, workspace *workspaceDomain.Workspace) *RetentionService {
+	ctx := sharedMiddleware.WithWorkspaceID(sharedMiddleware.WithUserID(context.Background(), "admin-1"), workspace.ID)
+	return NewRetentionService(db).WithContext(ctx)
+}
+
+func (suite *RetentionServiceTestSuite) TestSetPolicy_ValidatesResource() {
+	suite.container.WithTransaction(func(db *gorm.DB) {
+		// Arrange
+		f := suite.createFixture(db, "retention-validate")
+		other := suite.createFixture(db, "retention-validate-other")
+		service := suite.serviceFor(db, f.workspace)
+		days := 30
+		tooLong := domain.MaxRetentionDays + 1
+
+		// Act
+		foreign := service.SetPolicy(&retentionDto.SetPolicyRequest{Level: "channel", ResourceID: other.general.ID, RetentionDays: &days})
+		invalid := service.SetPolicy(&retentionDto.SetPolicyRequest{Level: "channel", ResourceID: "not-a-uuid", RetentionDays: &days})
+		outOfRange := service.SetPolicy(&retentionDto.SetPolicyRequest{Level: "workspace", RetentionDays: &tooLong})
+		workspacePolicy := service.SetPolicy(&retentionDto.SetPolicyRequest{Level: "workspace", RetentionDays: &days})
+
+		// Assert
+		sharedTesting.AssertValidationErrorWithCode(suite.T(), foreign.Response, "NOT_FOUND")
+		sharedTesting.AssertValidationErrorWithCode(suite.T(), invalid.Response, "NOT_FOUND")
+		sharedTesting.AssertValidationErrorWithCode(suite.T(), outOfRange.Response, "VALIDATION_ERROR")
+		policy := sharedTesting.AssertSuccessWithValue(suite.T(), workspacePolicy.Response)
+		assert.Equal(suite.T(), f.workspace.ID, policy.ResourceID)
+	})
+}
+
+func (suite *RetentionServiceTestSuite) TestReport_MostSpecificPolicyAndHoldsApply() {
+	suite.container.WithTransaction(func(db *gorm.DB) {
+		// Arrange
+		f := suite.createFixture(db, "retention-report")
+		service := suite.serviceFor(db, f.workspace)
+		thirty := 30
+		sharedTesting.AssertSuccessWithValue(suite.T(), service.SetPolicy(&retentionDto.SetPolicyRequest{Level: "project", ResourceID: f.project.ID, RetentionDays: &thirty}).Response)
+		// The legal channel keeps its content forever despite the project policy
+		sharedTesting.AssertSuccessWithValue(suite.T(), service.SetPolicy(&retentionDto.SetPolicyRequest{Level: "channel", ResourceID: f.legal.ID}).Response)
+
+		// Act
+		report := sharedTesting.AssertSuccessWithValue(suite.T(), service.Report().Response)
+		hold := sharedTesting.AssertSuccessWithValue(suite.T(), service.CreateLegalHold(&retentionDto.CreateLegalHoldRequest{Level: "channel", ResourceID: f.general.ID, Reason: "litigation"}).Response)
+		held := sharedTesting.AssertSuccessWithValue(suite.T(), service.Report().Response)
+
+		// Assert
+		assert.Len(suite.T(), report.Scopes, 1)
+		assert.Equal(suite.T(), f.general.ID, report.Scopes[0].ResourceID)
+		assert.Equal(suite.T(), "project", report.Scopes[0].PolicyLevel)
+		assert.Equal(suite.T(), int64(1), report.Messages)
+
+		assert.True(suite.T(), hold.Active)
+		assert.Len(suite.T(), held.Scopes, 1)
+		assert.True(suite.T(), held.Scopes[0].Held)
+		assert.Zero(suite.T(), held.Messages)
+	})
+}
+
+func (suite *RetentionServiceTestSuite) TestReleaseLegalHold_OnlyOnce() {
+	suite.container.WithTransaction(func(db *gorm.DB) {
+		// Arrange
+		f := suite.createFixture(db, "retention-release")
+		service := suite.serviceFor(db, f.workspace)
+		hold := sharedTesting.AssertSuccessWithValue(suite.T(), service.CreateLegalHold(&retentionDto.CreateLegalHoldRequest{Level: "workspace", Reason: "audit"}).Response)
+
+		// Act
+		released := service.ReleaseLegalHold(hold.ID)
+		again := service.ReleaseLegalHold(hold.ID)
+
+		// Assert
+		result := sharedTesting.AssertSuccessWithValue(suite.T(), released.Response)
+		assert.False(suite.T(), result.Active)
+		assert.Equal(suite.T(), "admin-1", *result.ReleasedBy)
+		sharedTesting.AssertValidationErrorWithCode(suite.T(), again.Response, "CONFLICT")
+		active := sharedTesting.AssertSuccessWithValue(suite.T(), service.ListLegalHolds(false).Response)
+		assert.Empty(suite.T(), active)
+	})
+}
+
+func TestRetentionServiceTestSuite(t *testing.T) {
+	suite.Run(t, new(RetentionServiceTestSuite))
+}
@@ -0,0 +1,161 @@
+package service
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+
+	"thothix-backend/internal/retention/domain"
+)
+
+// Scope is a unit of content the purge handles on its own: the messages of a channel, with their files,
+// or the files of a project that are not attached to a message
+type Scope struct {
+	Level       domain.Level // LevelChannel or LevelProject
+	ResourceID  string
+	Name        string
+	WorkspaceID string
+	ProjectID   string
+	Policy      *domain.Policy // Most specific policy that applies, never one that keeps content forever
+	Held        bool           // An active legal hold covers the scope
+}
+
+// Cutoff returns the creation time before which the content of the scope is purged
+func (s *Scope) Cutoff(now time.Time) time.Time {
+	return s.Policy.Cutoff(now)
+}
+
+// resourceKey identifies a policy or a hold by the resource it applies to
+type resourceKey struct {
+	level domain.Level
+	id    string
+}
+
+// Scopes resolves the policy of every channel and project with purgeable content, in workspaceID or,
+// when it is empty, in every workspace that has a policy
+// Scopes whose most specific policy keeps content forever, or that no policy covers, are left out
+func Scopes(db *gorm.DB, workspaceID string) ([]Scope, error) {
+	inScope := func(query *gorm.DB) *gorm.DB {
+		if workspaceID != "" {
+			return query.Where("workspace_id = ?", workspaceID)
+		}
+		return query
+	}
+
+	var policies []domain.Policy
+	if err := inScope(db.Model(&domain.Policy{})).Find(&policies).Error; err != nil {
+		return nil, err
+	}
+	if len(policies) == 0 {
+		return nil, nil
+	}
+	policyOf := make(map[resourceKey]*domain.Policy, len(policies))
+	workspaceIDs := make(map[string]struct{})
+	for i := range policies {
+		policyOf[resourceKey{policies[i].Level, policies[i].ResourceID}] = &policies[i]
+		workspaceIDs[policies[i].WorkspaceID] = struct{}{}
+	}
+
+	var holds []domain.LegalHold
+	if err := inScope(db.Model(&domain.LegalHold{})).Where("released_at IS NULL").Find(&holds).Error; err != nil {
+		return nil, err
+	}
+	held := make(map[resourceKey]bool, len(holds))
+	for _, hold := range holds {
+		held[resourceKey{hold.Level, hold.ResourceID}] = true
+	}
+
+	ids := make([]string, 0, len(workspaceIDs))
+	for id := range workspaceIDs {
+		ids = append(ids, id)
+	}
+
+	type resource struct {
+		ID          string
+		WorkspaceID string
+		ProjectID   string
+		Name        string
+	}
+	var projects, channels []resource
+	if err := db.Table("projects").Select("id, workspace_id, name").Where("workspace_id IN ?", ids).Order("name").Scan(&projects).Error; err != nil {
+		return nil, err
+	}
+	if err := db.Table("channels").Select("id, workspace_id, project_id, name").Where("workspace_id IN ?", ids).Order("name").Scan(&channels).Error; err != nil {
+		return nil, err
+	}
+
+	// resolve walks from the most specific resource to the workspace and stops at the first policy
+	resolve := func(keys ...resourceKey) (*domain.Policy, bool) {
+		var policy *domain.Policy
+		isHeld := false
+		for _, key := range keys {
+			if policy == nil {
+				policy = policyOf[key]
+			}
+			isHeld = isHeld || held[key]
+		}
+		return policy, isHeld
+	}
+
+	var scopes []Scope
+	add := func(level domain.Level, r resource, keys ...resourceKey) {
+		policy, isHeld := resolve(keys...)
+		if policy == nil || policy.KeepsForever() {
+			return
+		}
+		scopes = append(scopes, Scope{
+			Level:       level,
+			ResourceID:  r.ID,
+			Name:        r.Name,
+			WorkspaceID: r.WorkspaceID,
+			ProjectID:   r.ProjectID,
+			Policy:      policy,
+			Held:        isHeld,
+		})
+	}
+
+	workspaceKey := func(id string) resourceKey { return resourceKey{domain.LevelWorkspace, id} }
+	for _, channel := range channels {
+		add(domain.LevelChannel, channel,
+			resourceKey{domain.LevelChannel, channel.ID},
+			resourceKey{domain.LevelProject, channel.ProjectID},
+			workspaceKey(channel.WorkspaceID))
+	}
+	for _, project := range projects {
+		project.ProjectID = project.ID
+		add(domain.LevelProject, project,
+			resourceKey{domain.LevelProject, project.ID},
+			workspaceKey(project.WorkspaceID))
+	}
+	return scopes, nil
+}
+
+// IsHeld reports whether an active legal hold covers the scope now, for a check inside the purge transaction
+func IsHeld(db *gorm.DB, scope *Scope) (bool, error) {
+	covers := db.Session(&gorm.Session{NewDB: true}).
+		Where("level = ? AND resource_id = ?", domain.LevelWorkspace, scope.WorkspaceID).
+		Or("level = ? AND resource_id = ?", domain.LevelProject, scope.ProjectID)
+	if scope.Level == domain.LevelChannel {
+		covers = covers.Or("level = ? AND resource_id = ?", domain.LevelChannel, scope.ResourceID)
+	}
+
+	var count int64
+	if err := db.Model(&domain.LegalHold{}).Where("released_at IS NULL").Where(covers).Count(&count).Error; err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// ExpiredMessages selects the messages of a channel scope created before cutoff
+func ExpiredMessages(db *gorm.DB, scope *Scope, cutoff time.Time) *gorm.DB {
+	return db.Table("messages").Where("channel_id = ? AND created_at < ?", scope.ResourceID, cutoff)
+}
+
+// ExpiredFiles selects the files the purge deletes with a scope: those attached to expired messages of a channel,
+// or those of a project created before cutoff that are not attached to a message
+func ExpiredFiles(db *gorm.DB, scope *Scope, cutoff time.Time) *gorm.DB {
+	if scope.Level == domain.LevelChannel {
+		return db.Table("files").Where("message_id IN (?)", ExpiredMessages(db.Session(&gorm.Session{NewDB: true}), scope, cutoff).Select("id"))
+	}
+	return db.Table("files").Where("project_id = ? AND message_id IS NULL AND created_at < ?", scope.ResourceID, cutoff)
+}
@@ -0,0 +1,43 @@
+package domain
+
+import (
+	"time"
+
+	commonModels "thothix-backend/internal/common/models"
+	_ "thothix-backend/internal/encryption" // Registers the "encrypted" serializer used by the models
+	"thothix-backend/internal/message/richtext"
+)
+
+// MaxScheduleAhead caps how far in the future a message or reminder can be scheduled
+const MaxScheduleAhead = 365 * 24 * time.Hour
+
+// Status is the delivery state of a scheduled message
+type Status string
+
+const (
+	StatusPending Status = "pending" // Waiting for SendAt
+	StatusSent    Status = "sent"    // Delivered, see MessageID
+	StatusFailed  Status = "failed"  // Not delivered, see FailureReason
+)
+
+// ScheduledMessage is a channel or direct message that the scheduler sends on behalf of its sender at SendAt
+// Permissions are checked when it is scheduled and again when it is sent
+type ScheduledMessage struct {
+	commonModels.BaseModel
+	WorkspaceID   string           `json:"workspace_id" gorm:"type:uuid;not null;index"`
+	SenderID      string           `json:"sender_id" gorm:"not null;index"`
+	ChannelID     *string          `json:"channel_id,omitempty"`
+	ReceiverID    *string          `json:"receiver_id,omitempty"`
+	Content       string           `json:"content" gorm:"serializer:encrypted"` // Encrypted at rest like the message it becomes
+	Format        int              `json:"format" gorm:"not null;default:0"`    // richtext format version of Content
+	Blocks        []richtext.Block `json:"blocks,omitempty" gorm:"serializer:encrypted"`
+	SendAt        time.Time        `json:"send_at" gorm:"not null;index"`
+	Status        Status           `json:"status" gorm:"not null;default:'pending';index"`
+	MessageID     *string          `json:"message_id,omitempty"` // Message created at delivery
+	FailureReason string           `json:"failure_reason,omitempty"`
+}
+
+// IsPending reports whether the message can still be edited or cancelled
+func (m *ScheduledMessage) IsPending() bool {
+	return m.Status == StatusPending
+}
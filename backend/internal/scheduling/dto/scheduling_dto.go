@@ -0,0 +1,115 @@
+package dto
+
+import (
+	"time"
+
+	"thothix-backend/internal/message/richtext"
+	"thothix-backend/internal/shared/dto"
+)
+
+// === Scheduled message DTOs ===
+
+// ScheduledMessageDto represents a scheduled message in API responses
+type ScheduledMessageDto struct {
+	ID            string           `json:"id"`
+	ChannelID     *string          `json:"channel_id,omitempty"`
+	RecipientID   *string          `json:"recipient_id,omitempty"`
+	Content       string           `json:"content"`
+	Format        int              `json:"format"`
+	Blocks        []richtext.Block `json:"blocks,omitempty"`
+	SendAt        string           `json:"send_at"`
+	Status        string           `json:"status"` // pending, sent or failed
+	MessageID     *string          `json:"message_id,omitempty"`
+	FailureReason string           `json:"failure_reason,omitempty"`
+	CreatedAt     string           `json:"created_at"`
+}
+
+// CreateScheduledMessageRequest schedules a message to a channel or, with recipient_id, a direct message
+// Format and Blocks declare how Content is written, as for messages sent right away
+type CreateScheduledMessageRequest struct {
+	ChannelID   *string          `json:"channel_id,omitempty"`
+	RecipientID *string          `json:"recipient_id,omitempty"`
+	Content     string           `json:"content" binding:"required"`
+	Format      int              `json:"format"`
+	Blocks      []richtext.Block `json:"blocks,omitempty"`
+	SendAt      time.Time        `json:"send_at" binding:"required"`
+}
+
+// UpdateScheduledMessageRequest edits a pending scheduled message
+// Omitted fields are kept; an empty blocks list removes the blocks
+type UpdateScheduledMessageRequest struct {
+	Content *string          `json:"content,omitempty"`
+	Format  *int             `json:"format,omitempty"`
+	Blocks  []richtext.Block `json:"blocks,omitempty"`
+	SendAt  *time.Time       `json:"send_at,omitempty"`
+}
+
+// ScheduledMessageResponse wraps a single ScheduledMessageDto response
+type ScheduledMessageResponse struct {
+	*dto.Response[*ScheduledMessageDto]
+}
+
+func NewScheduledMessageResponse(producer func() dto.Validation[*ScheduledMessageDto]) *ScheduledMessageResponse {
+	return &ScheduledMessageResponse{
+		Response: dto.NewResponse(producer),
+	}
+}
+
+// ScheduledMessageListResponse wraps a list of ScheduledMessageDto
+type ScheduledMessageListResponse struct {
+	*dto.Response[[]ScheduledMessageDto]
+}
+
+func NewScheduledMessageListResponse(producer func() dto.Validation[[]ScheduledMessageDto]) *ScheduledMessageListResponse {
+	return &ScheduledMessageListResponse{
+		Response: dto.NewResponse(producer),
+	}
+}
+
+// === Reminder DTOs ===
+
+// ReminderDto represents a reminder in API responses
+type ReminderDto struct {
+	ID        string  `json:"id"`
+	Text      string  `json:"text"`
+	RemindAt  string  `json:"remind_at"`
+	ChannelID *string `json:"channel_id,omitempty"`
+	MessageID *string `json:"message_id,omitempty"`
+	Delivered bool    `json:"delivered"`
+	CreatedAt string  `json:"created_at"`
+}
+
+// CreateReminderRequest sets a reminder, optionally about a message the user can read
+type CreateReminderRequest struct {
+	Text      string    `json:"text" binding:"required"`
+	RemindAt  time.Time `json:"remind_at" binding:"required"`
+	MessageID *string   `json:"message_id,omitempty"`
+}
+
+// UpdateReminderRequest edits a reminder that has not been delivered
+type UpdateReminderRequest struct {
+	Text     *string    `json:"text,omitempty"`
+	RemindAt *time.Time `json:"remind_at,omitempty"`
+}
+
+// ReminderResponse wraps a single ReminderDto response
+type ReminderResponse struct {
+	*dto.Response[*ReminderDto]
+}
+
+func NewReminderResponse(producer func() dto.Validation[*ReminderDto]) *ReminderResponse {
+	return &ReminderResponse{
+		Response: dto.NewResponse(producer),
+	}
+}
+
+// ReminderListResponse wraps a list of ReminderDto
+type ReminderListResponse struct {
+	*dto.Response[[]ReminderDto]
+}
+
+func NewReminderListResponse(producer func() dto.Validation[[]ReminderDto]) *ReminderListResponse {
+	return &ReminderListResponse{
+		Response: dto.NewResponse(producer),
+	}
+}
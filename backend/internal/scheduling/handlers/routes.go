@@ -0,0 +1,25 @@
+package handlers
+
+import (
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"thothix-backend/internal/scheduling/service"
+)
+
+// RegisterSchedulingRoutes registers the scheduled message and reminder routes of the current workspace
+func RegisterSchedulingRoutes(router *gin.RouterGroup, db *gorm.DB) {
+	schedulingService := service.NewSchedulingService(db)
+	schedulingHandler := NewSchedulingHandler(schedulingService)
+
+	scheduled := router.Group("/scheduled-messages")
+	scheduled.GET("", schedulingHandler.ListScheduledMessages)
+	scheduled.POST("", schedulingHandler.ScheduleMessage)
+	scheduled.PATCH("/:id", schedulingHandler.UpdateScheduledMessage)
+	scheduled.DELETE("/:id", schedulingHandler.CancelScheduledMessage)
+
+	reminders := router.Group("/reminders")
+	reminders.GET("", schedulingHandler.ListReminders)
+	reminders.POST("", schedulingHandler.CreateReminder)
+	reminders.PATCH("/:id", schedulingHandler.UpdateReminder)
+	reminders.DELETE("/:id", schedulingHandler.DeleteReminder)
+}
@@ -0,0 +1,408 @@
+package handlers
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	schedulingDto "thothix-backend/internal/scheduling/dto"
+	"thothix-backend/internal/scheduling/service"
+	"thothix-backend/internal/shared/constants"
+	"thothix-backend/internal/shared/dto"
+	"thothix-backend/internal/shared/handlers"
+)
+
+type SchedulingHandler struct {
+	schedulingService service.SchedulingServiceInterface
+}
+
+func NewSchedulingHandler(schedulingService service.SchedulingServiceInterface) *SchedulingHandler {
+	return &SchedulingHandler{
+		schedulingService: schedulingService,
+	}
+}
+
+// ListScheduledMessages godoc
+// @Summary List scheduled messages
+// @Description List the authenticated user's scheduled messages in the current workspace. Pending ones come in delivery order, sent and failed ones most recent first
+// @Tags scheduled-messages
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param status query string false "pending (default), sent or failed"
+// @Success 200 {array} schedulingDto.ScheduledMessageDto
+// @Failure 400 {object} dto.ErrorViewModel
+// @Failure 500 {object} dto.ErrorViewModel
+// @Router /scheduled-messages [get]
+func (h *SchedulingHandler) ListScheduledMessages(c *gin.Context) {
+	wrapper := handlers.WrapContext(c)
+
+	response := h.scopedService(c).ListScheduledMessages(c.Query("status"))
+
+	response.Match(
+		// Exception case
+		func(err error) interface{} {
+			wrapper.SystemErrorResponse(err, "Failed to retrieve scheduled messages")
+			return nil
+		},
+		// Success case
+		func(result []schedulingDto.ScheduledMessageDto) interface{} {
+			wrapper.SuccessResponse(result)
+			return nil
+		},
+		// Validation failure case
+		func(errors []dto.Error) interface{} {
+			respondWithFailure(wrapper, errors, "Scheduled message", "", "Scheduled message list validation failed")
+			return nil
+		},
+	)
+}
+
+// ScheduleMessage godoc
+// @Summary Schedule a message
+// @Description Schedule a message to a channel or, with recipient_id, a direct message. Permissions are checked now and again when it is sent
+// @Tags scheduled-messages
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param message body schedulingDto.CreateScheduledMessageRequest true "Message to schedule"
+// @Success 201 {object} schedulingDto.ScheduledMessageDto
+// @Failure 400 {object} dto.ErrorViewModel
+// @Failure 403 {object} dto.ErrorViewModel
+// @Failure 404 {object} dto.ErrorViewModel
+// @Failure 409 {object} dto.ErrorViewModel
+// @Failure 500 {object} dto.ErrorViewModel
+// @Router /scheduled-messages [post]
+func (h *SchedulingHandler) ScheduleMessage(c *gin.Context) {
+	wrapper := handlers.WrapContext(c)
+
+	var request schedulingDto.CreateScheduledMessageRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		wrapper.BadRequestErrorResponse("Invalid request payload")
+		return
+	}
+
+	response := h.scopedService(c).ScheduleMessage(&request)
+
+	response.Match(
+		// Exception case
+		func(err error) interface{} {
+			wrapper.SystemErrorResponse(err, "Failed to schedule message")
+			return nil
+		},
+		// Success case
+		func(result *schedulingDto.ScheduledMessageDto) interface{} {
+			wrapper.CreatedResponse(result)
+			return nil
+		},
+		// Validation failure case
+		func(errors []dto.Error) interface{} {
+			respondWithFailure(wrapper, errors, deliveryTarget(&request), deliveryTargetID(&request), "Scheduled message validation failed")
+			return nil
+		},
+	)
+}
+
+// UpdateScheduledMessage godoc
+// @Summary Edit a scheduled message
+// @Description Change the content or the delivery time of a pending scheduled message
+// @Tags scheduled-messages
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Scheduled message ID"
+// @Param message body schedulingDto.UpdateScheduledMessageRequest true "Changes"
+// @Success 200 {object} schedulingDto.ScheduledMessageDto
+// @Failure 400 {object} dto.ErrorViewModel
+// @Failure 404 {object} dto.ErrorViewModel
+// @Failure 409 {object} dto.ErrorViewModel
+// @Failure 500 {object} dto.ErrorViewModel
+// @Router /scheduled-messages/{id} [patch]
+func (h *SchedulingHandler) UpdateScheduledMessage(c *gin.Context) {
+	wrapper := handlers.WrapContext(c)
+	messageID := c.Param("id")
+
+	var request schedulingDto.UpdateScheduledMessageRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		wrapper.BadRequestErrorResponse("Invalid request payload")
+		return
+	}
+
+	response := h.scopedService(c).UpdateScheduledMessage(messageID, &request)
+
+	response.Match(
+		// Exception case
+		func(err error) interface{} {
+			wrapper.SystemErrorResponse(err, "Failed to update scheduled message: %s", messageID)
+			return nil
+		},
+		// Success case
+		func(result *schedulingDto.ScheduledMessageDto) interface{} {
+			wrapper.SuccessResponse(result)
+			return nil
+		},
+		// Validation failure case
+		func(errors []dto.Error) interface{} {
+			respondWithFailure(wrapper, errors, "Scheduled message", messageID, "Scheduled message update validation failed")
+			return nil
+		},
+	)
+}
+
+// CancelScheduledMessage godoc
+// @Summary Cancel a scheduled message
+// @Description Delete a pending scheduled message before it is sent
+// @Tags scheduled-messages
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Scheduled message ID"
+// @Success 204
+// @Failure 404 {object} dto.ErrorViewModel
+// @Failure 409 {object} dto.ErrorViewModel
+// @Failure 500 {object} dto.ErrorViewModel
+// @Router /scheduled-messages/{id} [delete]
+func (h *SchedulingHandler) CancelScheduledMessage(c *gin.Context) {
+	wrapper := handlers.WrapContext(c)
+	messageID := c.Param("id")
+
+	response := h.scopedService(c).CancelScheduledMessage(messageID)
+
+	response.Match(
+		// Exception case
+		func(err error) interface{} {
+			wrapper.SystemErrorResponse(err, "Failed to cancel scheduled message: %s", messageID)
+			return nil
+		},
+		// Success case
+		func(result *schedulingDto.ScheduledMessageDto) interface{} {
+			wrapper.NoContentResponse()
+			return nil
+		},
+		// Validation failure case
+		func(errors []dto.Error) interface{} {
+			respondWithFailure(wrapper, errors, "Scheduled message", messageID, "Scheduled message cancellation validation failed")
+			return nil
+		},
+	)
+}
+
+// ListReminders godoc
+// @Summary List reminders
+// @Description List the authenticated user's reminders in the current workspace. Pending ones come in delivery order, delivered ones most recent first
+// @Tags reminders
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param delivered query bool false "List delivered reminders instead of pending ones"
+// @Success 200 {array} schedulingDto.ReminderDto
+// @Failure 400 {object} dto.ErrorViewModel
+// @Failure 500 {object} dto.ErrorViewModel
+// @Router /reminders [get]
+func (h *SchedulingHandler) ListReminders(c *gin.Context) {
+	wrapper := handlers.WrapContext(c)
+
+	delivered := false
+	if value := c.Query("delivered"); value != "" {
+		parsed, err := strconv.ParseBool(value)
+		if err != nil {
+			wrapper.BadRequestErrorResponse("Invalid query parameters")
+			return
+		}
+		delivered = parsed
+	}
+
+	response := h.scopedService(c).ListReminders(delivered)
+
+	response.Match(
+		// Exception case
+		func(err error) interface{} {
+			wrapper.SystemErrorResponse(err, "Failed to retrieve reminders")
+			return nil
+		},
+		// Success case
+		func(result []schedulingDto.ReminderDto) interface{} {
+			wrapper.SuccessResponse(result)
+			return nil
+		},
+		// Validation failure case
+		func(errors []dto.Error) interface{} {
+			respondWithFailure(wrapper, errors, "Reminder", "", "Reminder list validation failed")
+			return nil
+		},
+	)
+}
+
+// CreateReminder godoc
+// @Summary Set a reminder
+// @Description Set a reminder, optionally about a message. It is delivered as a direct message from the system at remind_at
+// @Tags reminders
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param reminder body schedulingDto.CreateReminderRequest true "Reminder"
+// @Success 201 {object} schedulingDto.ReminderDto
+// @Failure 400 {object} dto.ErrorViewModel
+// @Failure 404 {object} dto.ErrorViewModel
+// @Failure 409 {object} dto.ErrorViewModel
+// @Failure 500 {object} dto.ErrorViewModel
+// @Router /reminders [post]
+func (h *SchedulingHandler) CreateReminder(c *gin.Context) {
+	wrapper := handlers.WrapContext(c)
+
+	var request schedulingDto.CreateReminderRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		wrapper.BadRequestErrorResponse("Invalid request payload")
+		return
+	}
+
+	response := h.scopedService(c).CreateReminder(&request)
+
+	response.Match(
+		// Exception case
+		func(err error) interface{} {
+			wrapper.SystemErrorResponse(err, "Failed to create reminder")
+			return nil
+		},
+		// Success case
+		func(result *schedulingDto.ReminderDto) interface{} {
+			wrapper.CreatedResponse(result)
+			return nil
+		},
+		// Validation failure case
+		func(errors []dto.Error) interface{} {
+			messageID := ""
+			if request.MessageID != nil {
+				messageID = *request.MessageID
+			}
+			respondWithFailure(wrapper, errors, "Message", messageID, "Reminder validation failed")
+			return nil
+		},
+	)
+}
+
+// UpdateReminder godoc
+// @Summary Edit a reminder
+// @Description Change the text or the time of a reminder that has not been delivered
+// @Tags reminders
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Reminder ID"
+// @Param reminder body schedulingDto.UpdateReminderRequest true "Changes"
+// @Success 200 {object} schedulingDto.ReminderDto
+// @Failure 400 {object} dto.ErrorViewModel
+// @Failure 404 {object} dto.ErrorViewModel
+// @Failure 409 {object} dto.ErrorViewModel
+// @Failure 500 {object} dto.ErrorViewModel
+// @Router /reminders/{id} [patch]
+func (h *SchedulingHandler) UpdateReminder(c *gin.Context) {
+	wrapper := handlers.WrapContext(c)
+	reminderID := c.Param("id")
+
+	var request schedulingDto.UpdateReminderRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		wrapper.BadRequestErrorResponse("Invalid request payload")
+		return
+	}
+
+	response := h.scopedService(c).UpdateReminder(reminderID, &request)
+
+	response.Match(
+		// Exception case
+		func(err error) interface{} {
+			wrapper.SystemErrorResponse(err, "Failed to update reminder: %s", reminderID)
+			return nil
+		},
+		// Success case
+		func(result *schedulingDto.ReminderDto) interface{} {
+			wrapper.SuccessResponse(result)
+			return nil
+		},
+		// Validation failure case
+		func(errors []dto.Error) interface{} {
+			respondWithFailure(wrapper, errors, "Reminder", reminderID, "Reminder update validation failed")
+			return nil
+		},
+	)
+}
+
+// DeleteReminder godoc
+// @Summary Delete a reminder
+// @Description Cancel a pending reminder or remove a delivered one
+// @Tags reminders
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Reminder ID"
+// @Success 204
+// @Failure 404 {object} dto.ErrorViewModel
+// @Failure 500 {object} dto.ErrorViewModel
+// @Router /reminders/{id} [delete]
+func (h *SchedulingHandler) DeleteReminder(c *gin.Context) {
+	wrapper := handlers.WrapContext(c)
+	reminderID := c.Param("id")
+
+	response := h.scopedService(c).DeleteReminder(reminderID)
+
+	response.Match(
+		// Exception case
+		func(err error) interface{} {
+			wrapper.SystemErrorResponse(err, "Failed to delete reminder: %s", reminderID)
+			return nil
+		},
+		// Success case
+		func(result *schedulingDto.ReminderDto) interface{} {
+			wrapper.NoContentResponse()
+			return nil
+		},
+		// Validation failure case
+		func(errors []dto.Error) interface{} {
+			respondWithFailure(wrapper, errors, "Reminder", reminderID, "Reminder removal validation failed")
+			return nil
+		},
+	)
+}
+
+// deliveryTarget names the resource a scheduled message is addressed to, for not found responses
+func deliveryTarget(request *schedulingDto.CreateScheduledMessageRequest) string {
+	if request.RecipientID != nil && request.ChannelID == nil {
+		return "Recipient"
+	}
+	return "Channel"
+}
+
+func deliveryTargetID(request *schedulingDto.CreateScheduledMessageRequest) string {
+	switch {
+	case request.ChannelID != nil:
+		return *request.ChannelID
+	case request.RecipientID != nil:
+		return *request.RecipientID
+	}
+	return ""
+}
+
+// respondWithFailure maps service errors to not found, forbidden, conflict or validation responses
+func respondWithFailure(wrapper *handlers.ContextWrapper, errors []dto.Error, resource, identifier, logMessage string) {
+	switch {
+	case len(errors) > 0 && errors[0].Code == constants.NotFoundError:
+		wrapper.NotFoundErrorResponse(resource, identifier)
+	case len(errors) > 0 && errors[0].Code == constants.ForbiddenError:
+		wrapper.ForbiddenErrorResponse(errors[0].Message)
+	case len(errors) > 0 && errors[0].Code == constants.ConflictError:
+		wrapper.ConflictErrorResponse(errors[0].Message)
+	case len(errors) > 0 && errors[0].Code == constants.UnauthorizedError:
+		wrapper.UnauthorizedErrorResponse(errors[0].Message)
+	default:
+		wrapper.ValidationErrorResponse(errors, "%s", logMessage)
+	}
+}
+
+// scopedService binds the service to the request context when supported,
+// so the current user and workspace reach the permission checks
+func (h *SchedulingHandler) scopedService(c *gin.Context) service.SchedulingServiceInterface {
+	if aware, ok := h.schedulingService.(service.ContextAwareSchedulingService); ok {
+		return aware.WithContext(c.Request.Context())
+	}
+	return h.schedulingService
+}
@@ -0,0 +1,164 @@
+package handlers
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	schedulingDto "thothix-backend/internal/scheduling/dto"
+	"thothix-backend/internal/shared/dto"
+)
+
+// MockSchedulingService is a mock implementation of the SchedulingService
+type MockSchedulingService struct {
+	mock.Mock
+}
+
+func (m *MockSchedulingService) ListScheduledMessages(status string) *schedulingDto.ScheduledMessageListResponse {
+	args := m.Called(status)
+	return args.Get(0).(*schedulingDto.ScheduledMessageListResponse)
+}
+
+func (m *MockSchedulingService) ScheduleMessage(req *schedulingDto.CreateScheduledMessageRequest) *schedulingDto.ScheduledMessageResponse {
+	args := m.Called(req)
+	return args.Get(0).(*schedulingDto.ScheduledMessageResponse)
+}
+
+func (m *MockSchedulingService) UpdateScheduledMessage(id string, req *schedulingDto.UpdateScheduledMessageRequest) *schedulingDto.ScheduledMessageResponse {
+	args := m.Called(id, req)
+	return args.Get(0).(*schedulingDto.ScheduledMessageResponse)
+}
+
+func (m *MockSchedulingService) CancelScheduledMessage(id string) *schedulingDto.ScheduledMessageResponse {
+	args := m.Called(id)
+	return args.Get(0).(*schedulingDto.ScheduledMessageResponse)
+}
+
+func (m *MockSchedulingService) ListReminders(delivered bool) *schedulingDto.ReminderListResponse {
+	args := m.Called(delivered)
+	return args.Get(0).(*schedulingDto.ReminderListResponse)
+}
+
+func (m *MockSchedulingService) CreateReminder(req *schedulingDto.CreateReminderRequest) *schedulingDto.ReminderResponse {
+	args := m.Called(req)
+	return args.Get(0).(*schedulingDto.ReminderResponse)
+}
+
+func (m *MockSchedulingService) UpdateReminder(id string, req *schedulingDto.UpdateReminderRequest) *schedulingDto.ReminderResponse {
+	args := m.Called(id, req)
+	return args.Get(0).(*schedulingDto.ReminderResponse)
+}
+
+func (m *MockSchedulingService) DeleteReminder(id string) *schedulingDto.ReminderResponse {
+	args := m.Called(id)
+	return args.Get(0).(*schedulingDto.ReminderResponse)
+}
+
+type SchedulingHandlerTestSuite struct {
+	suite.Suite
+	mockService *MockSchedulingService
+	router      *gin.Engine
+}
+
+func (suite *SchedulingHandlerTestSuite) SetupSuite() {
+	gin.SetMode(gin.TestMode)
+}
+
+func (suite *SchedulingHandlerTestSuite) SetupTest() {
+	suite.mockService = new(MockSchedulingService)
+	handler := NewSchedulingHandler(suite.mockService)
+
+	suite.router = gin.New()
+	suite.router.POST("/scheduled-messages", handler.ScheduleMessage)
+	suite.router.DELETE("/scheduled-messages/:id", handler.CancelScheduledMessage)
+	suite.router.GET("/reminders", handler.ListReminders)
+}
+
+func (suite *SchedulingHandlerTestSuite) TestScheduleMessage_ChannelForbidden() {
+	// Arrange
+	mockResponse := schedulingDto.NewScheduledMessageResponse(func() dto.Validation[*schedulingDto.ScheduledMessageDto] {
+		return dto.Failure[*schedulingDto.ScheduledMessageDto](dto.NewError("FORBIDDEN", "Cannot send messages to this channel", nil))
+	})
+
+	suite.mockService.On("ScheduleMessage", mock.MatchedBy(func(req *schedulingDto.CreateScheduledMessageRequest) bool {
+		return req.ChannelID != nil && *req.ChannelID == "channel-1" && req.Content == "later"
+	})).Return(mockResponse)
+
+	// Act
+	body := `{"channel_id":"channel-1","content":"later","send_at":"2030-01-01T09:00:00Z"}`
+	req, _ := http.NewRequest("POST", "/scheduled-messages", bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(suite.T(), http.StatusForbidden, w.Code)
+}
+
+func (suite *SchedulingHandlerTestSuite) TestScheduleMessage_MissingSendAt() {
+	// Act
+	req, _ := http.NewRequest("POST", "/scheduled-messages", bytes.NewBufferString(`{"channel_id":"channel-1","content":"later"}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(suite.T(), http.StatusBadRequest, w.Code)
+	suite.mockService.AssertNotCalled(suite.T(), "ScheduleMessage", mock.Anything)
+}
+
+func (suite *SchedulingHandlerTestSuite) TestCancelScheduledMessage_AlreadySent() {
+	// Arrange
+	mockResponse := schedulingDto.NewScheduledMessageResponse(func() dto.Validation[*schedulingDto.ScheduledMessageDto] {
+		return dto.Invalid[*schedulingDto.ScheduledMessageDto](dto.NewError("CONFLICT", "Scheduled message was already sent", nil))
+	})
+
+	suite.mockService.On("CancelScheduledMessage", "scheduled-1").Return(mockResponse)
+
+	// Act
+	req, _ := http.NewRequest("DELETE", "/scheduled-messages/scheduled-1", nil)
+	w := httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(suite.T(), http.StatusConflict, w.Code)
+	assert.Contains(suite.T(), w.Body.String(), "already sent")
+}
+
+func (suite *SchedulingHandlerTestSuite) TestListReminders_DeliveredFilter() {
+	// Arrange
+	mockResponse := schedulingDto.NewReminderListResponse(func() dto.Validation[[]schedulingDto.ReminderDto] {
+		return dto.Success([]schedulingDto.ReminderDto{{ID: "reminder-1", Delivered: true}})
+	})
+
+	suite.mockService.On("ListReminders", true).Return(mockResponse)
+
+	// Act
+	req, _ := http.NewRequest("GET", "/reminders?delivered=true", nil)
+	w := httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(suite.T(), http.StatusOK, w.Code)
+	assert.Contains(suite.T(), w.Body.String(), "reminder-1")
+}
+
+func (suite *SchedulingHandlerTestSuite) TestListReminders_InvalidFilter() {
+	// Act
+	req, _ := http.NewRequest("GET", "/reminders?delivered=maybe", nil)
+	w := httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(suite.T(), http.StatusBadRequest, w.Code)
+	suite.mockService.AssertNotCalled(suite.T(), "ListReminders", mock.Anything)
+}
+
+func TestSchedulingHandlerTestSuite(t *testing.T) {
+	suite.Run(t, new(SchedulingHandlerTestSuite))
+}
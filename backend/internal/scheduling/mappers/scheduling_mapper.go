@@ -0,0 +1,73 @@
+package mappers
+
+import (
+	"time"
+
+	messageDomain "thothix-backend/internal/message/domain"
+	"thothix-backend/internal/scheduling/domain"
+	schedulingDto "thothix-backend/internal/scheduling/dto"
+)
+
+// SchedulingMapper handles conversion between scheduled messages, reminders and their DTOs
+type SchedulingMapper struct{}
+
+// NewSchedulingMapper creates a new SchedulingMapper instance
+func NewSchedulingMapper() *SchedulingMapper {
+	return &SchedulingMapper{}
+}
+
+// ScheduledMessageToDto converts a ScheduledMessage to ScheduledMessageDto
+func (m *SchedulingMapper) ScheduledMessageToDto(message *domain.ScheduledMessage) *schedulingDto.ScheduledMessageDto {
+	if message == nil {
+		return nil
+	}
+
+	return &schedulingDto.ScheduledMessageDto{
+		ID:            message.ID,
+		ChannelID:     message.ChannelID,
+		RecipientID:   message.ReceiverID,
+		Content:       message.Content,
+		Format:        message.Format,
+		Blocks:        message.Blocks,
+		SendAt:        message.SendAt.UTC().Format(time.RFC3339),
+		Status:        string(message.Status),
+		MessageID:     message.MessageID,
+		FailureReason: message.FailureReason,
+		CreatedAt:     message.CreatedAt.Format(time.RFC3339),
+	}
+}
+
+// ScheduledMessagesToDtos converts a list of ScheduledMessages to ScheduledMessageDtos
+func (m *SchedulingMapper) ScheduledMessagesToDtos(messages []domain.ScheduledMessage) []schedulingDto.ScheduledMessageDto {
+	result := make([]schedulingDto.ScheduledMessageDto, 0, len(messages))
+	for i := range messages {
+		result = append(result, *m.ScheduledMessageToDto(&messages[i]))
+	}
+	return result
+}
+
+// ReminderToDto converts a Reminder to ReminderDto
+func (m *SchedulingMapper) ReminderToDto(reminder *messageDomain.Reminder) *schedulingDto.ReminderDto {
+	if reminder == nil {
+		return nil
+	}
+
+	return &schedulingDto.ReminderDto{
+		ID:        reminder.ID,
+		Text:      reminder.Text,
+		RemindAt:  reminder.RemindAt.UTC().Format(time.RFC3339),
+		ChannelID: reminder.ChannelID,
+		MessageID: reminder.MessageID,
+		Delivered: reminder.Delivered,
+		CreatedAt: reminder.CreatedAt.Format(time.RFC3339),
+	}
+}
+
+// RemindersToDtos converts a list of Reminders to ReminderDtos
+func (m *SchedulingMapper) RemindersToDtos(reminders []messageDomain.Reminder) []schedulingDto.ReminderDto {
+	result := make([]schedulingDto.ReminderDto, 0, len(reminders))
+	for i := range reminders {
+		result = append(result, *m.ReminderToDto(&reminders[i]))
+	}
+	return result
+}
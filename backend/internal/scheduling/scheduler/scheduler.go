@@ -0,0 +1,305 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"thothix-backend/internal/encryption"
+	"thothix-backend/internal/health"
+	"thothix-backend/internal/message/commands"
+	messageDomain "thothix-backend/internal/message/domain"
+	"thothix-backend/internal/message/posting"
+	"thothix-backend/internal/message/richtext"
+	"thothix-backend/internal/message/unfurl"
+	"thothix-backend/internal/metrics"
+	"thothix-backend/internal/scheduling/domain"
+	"thothix-backend/internal/scheduling/service"
+	"thothix-backend/internal/shared/logging"
+	sharedMiddleware "thothix-backend/internal/shared/middleware"
+	sharedModels "thothix-backend/internal/shared/models"
+	usersDomain "thothix-backend/internal/users/domain"
+)
+
+const (
+	// batchSize is the number of due items claimed per transaction
+	batchSize = 100
+
+	// maxQuoteLength caps the excerpt of the message quoted in a reminder
+	maxQuoteLength = 280
+)
+
+// Scheduler sends due scheduled messages and delivers due reminders
+// Due rows are claimed with SELECT ... FOR UPDATE SKIP LOCKED, so any number of replicas can run it:
+// each row is handled by exactly one of them, and a row left by a crashed replica is picked up on the next pass
+type Scheduler struct {
+	db  *gorm.DB
+	now func() time.Time
+}
+
+// RunResult counts what a pass did
+type RunResult struct {
+	Sent     int // Scheduled messages delivered
+	Failed   int // Scheduled messages the sender may no longer send
+	Reminded int // Reminders delivered
+	Skipped  int // Reminders of users who left the workspace or were deactivated
+}
+
+func NewScheduler(db *gorm.DB) *Scheduler {
+	return &Scheduler{db: db, now: time.Now}
+}
+
+// EncryptedColumns lists the encrypted columns of scheduled messages for the re-encryption job
+func EncryptedColumns() []encryption.Column {
+	return []encryption.Column{{Table: "scheduled_messages", Column: "content"}}
+}
+
+// Run makes a pass every interval until ctx is cancelled, beating heartbeat as each pass starts
+func (s *Scheduler) Run(ctx context.Context, interval time.Duration, heartbeat *health.Heartbeat) {
+	logger := logging.FromContext(ctx)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		heartbeat.Beat()
+		result, err := s.RunOnce(ctx)
+		switch {
+		case err != nil && ctx.Err() == nil:
+			logger.Error("Scheduler pass failed", slog.Any("error", err))
+		case err == nil && result != (RunResult{}):
+			logger.Info("Scheduler pass completed",
+				slog.Int("sent", result.Sent), slog.Int("failed", result.Failed),
+				slog.Int("reminded", result.Reminded), slog.Int("skipped", result.Skipped))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunOnce handles everything due, a batch at a time
+func (s *Scheduler) RunOnce(ctx context.Context) (RunResult, error) {
+	var result RunResult
+	for _, pass := range []func(context.Context, *RunResult) (int, error){s.sendMessages, s.deliverReminders} {
+		for {
+			claimed, err := pass(ctx, &result)
+			if err != nil {
+				return result, err
+			}
+			if claimed < batchSize {
+				break
+			}
+		}
+	}
+	return result, nil
+}
+
+// sendMessages claims a batch of due scheduled messages and sends them, returning how many were claimed
+// Items that hit an error stay pending and are retried on the next pass
+func (s *Scheduler) sendMessages(ctx context.Context, result *RunResult) (int, error) {
+	logger := logging.FromContext(ctx)
+	var due []domain.ScheduledMessage
+	handled := 0
+
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status = ? AND send_at <= ?", domain.StatusPending, s.now()).
+			Order("send_at").Limit(batchSize).Find(&due).Error; err != nil {
+			return err
+		}
+
+		for i := range due {
+			message := &due[i]
+			if err := item(tx, message.SenderID, message.WorkspaceID, func(scoped *gorm.DB) error {
+				return s.send(scoped, message, result)
+			}); err != nil {
+				logger.Error("Failed to send scheduled message", slog.String("scheduled_message_id", message.ID), slog.Any("error", err))
+				continue
+			}
+			handled++
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, fmt.Errorf("send scheduled messages: %w", err)
+	}
+	if handled == 0 {
+		return 0, nil // Everything claimed failed, so stop instead of claiming the same rows again
+	}
+	return len(due), nil
+}
+
+// send delivers one scheduled message as SendMessage would, or marks it failed when its sender may no longer
+// send it, including when the two members of a direct conversation blocked each other, or a blocked-word list
+// of the channel's project refuses it
+func (s *Scheduler) send(db *gorm.DB, scheduled *domain.ScheduledMessage, result *RunResult) error {
+	fail := func(reason string) error {
+		result.Failed++
+		return db.Model(scheduled).Updates(map[string]interface{}{
+			"status":         domain.StatusFailed,
+			"failure_reason": reason,
+		}).Error
+	}
+
+	if failure := service.CheckDelivery(db, scheduled.SenderID, scheduled.ChannelID, scheduled.ReceiverID); failure != nil {
+		return fail(failure.Message)
+	}
+	body, err := service.MessageBody(scheduled)
+	if err != nil {
+		return fail("Invalid message: " + err.Error())
+	}
+
+	message := messageDomain.Message{
+		SenderID:   scheduled.SenderID,
+		ChannelID:  scheduled.ChannelID,
+		ReceiverID: scheduled.ReceiverID,
+	}
+	message.SetBody(body)
+	if err := posting.Post(db, &message); err != nil {
+		rejection, ok := commands.AsError(err)
+		if !ok {
+			return err
+		}
+		return fail(rejection.Message)
+	}
+	if err := db.Model(scheduled).Updates(map[string]interface{}{
+		"status":     domain.StatusSent,
+		"message_id": message.ID,
+	}).Error; err != nil {
+		return err
+	}
+	// The message is delivered without link previews when they cannot be scheduled
+	if err := unfurl.Enqueue(db, &message); err != nil {
+		logging.FromContext(db.Statement.Context).Warn("Failed to schedule link previews", slog.String("message_id", message.ID), slog.Any("error", err))
+	}
+
+	result.Sent++
+	if message.ChannelID != nil {
+		metrics.MessagesSent.WithLabelValues(metrics.MessageChannel).Inc()
+	} else {
+		metrics.MessagesSent.WithLabelValues(metrics.MessageDirect).Inc()
+	}
+	return nil
+}
+
+// deliverReminders claims a batch of due reminders and delivers them, returning how many were claimed
+func (s *Scheduler) deliverReminders(ctx context.Context, result *RunResult) (int, error) {
+	logger := logging.FromContext(ctx)
+	var due []messageDomain.Reminder
+	handled := 0
+
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("delivered = ? AND remind_at <= ?", false, s.now()).
+			Order("remind_at").Limit(batchSize).Find(&due).Error; err != nil {
+			return err
+		}
+
+		for i := range due {
+			reminder := &due[i]
+			workspaceID := ""
+			if reminder.WorkspaceID != nil {
+				workspaceID = *reminder.WorkspaceID
+			}
+			if err := item(tx, reminder.UserID, workspaceID, func(scoped *gorm.DB) error {
+				return s.remind(scoped, reminder, result)
+			}); err != nil {
+				logger.Error("Failed to deliver reminder", slog.String("reminder_id", reminder.ID), slog.Any("error", err))
+				continue
+			}
+			handled++
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, fmt.Errorf("deliver reminders: %w", err)
+	}
+	if handled == 0 {
+		return 0, nil
+	}
+	return len(due), nil
+}
+
+// remind delivers one reminder as a system direct message to its owner
+// Reminders of users who were deactivated or left the workspace are marked delivered without a message
+func (s *Scheduler) remind(db *gorm.DB, reminder *messageDomain.Reminder, result *RunResult) error {
+	var user usersDomain.User
+	err := db.Scopes(sharedModels.WorkspaceMembers).Where("id = ?", reminder.UserID).First(&user).Error
+	switch {
+	case err == gorm.ErrRecordNotFound || (err == nil && !user.IsActive()):
+		result.Skipped++
+		return db.Model(reminder).Update("delivered", true).Error
+	case err != nil:
+		return err
+	}
+
+	message := messageDomain.Message{
+		SenderID:   reminder.UserID,
+		ReceiverID: &reminder.UserID,
+		Type:       messageDomain.MessageTypeSystem,
+	}
+	message.SetBody(reminderBody(db, reminder))
+	if err := db.Create(&message).Error; err != nil {
+		return err
+	}
+	if err := db.Model(reminder).Update("delivered", true).Error; err != nil {
+		return err
+	}
+
+	result.Reminded++
+	metrics.MessagesSent.WithLabelValues(metrics.MessageDirect).Inc()
+	return nil
+}
+
+// item runs fn in a savepoint as userID in workspaceID, so permissions resolve as they would for a request
+// of that user, and a failing item rolls back alone without aborting the batch
+// The permission helpers panic on database errors, which are returned as errors here
+func item(tx *gorm.DB, userID, workspaceID string, fn func(*gorm.DB) error) error {
+	ctx := sharedMiddleware.WithUserID(tx.Statement.Context, userID)
+	if workspaceID != "" {
+		ctx = sharedMiddleware.WithWorkspaceID(ctx, workspaceID)
+	}
+
+	return tx.WithContext(ctx).Transaction(func(scoped *gorm.DB) (err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				err = fmt.Errorf("panic: %v", r)
+			}
+		}()
+		return fn(scoped)
+	})
+}
+
+// reminderBody builds the text of a reminder followed, while the user can still read it, by a quote block
+// with the start of the message it is about
+func reminderBody(db *gorm.DB, reminder *messageDomain.Reminder) *richtext.Body {
+	content := "Reminder: " + reminder.Text
+	if reminder.MessageID == nil {
+		return richtext.Plain(content)
+	}
+	message := service.ReadableMessage(db, reminder.UserID, *reminder.MessageID)
+	if message == nil {
+		return richtext.Plain(content)
+	}
+
+	excerpt := message.DisplayText()
+	if runes := []rune(excerpt); len(runes) > maxQuoteLength {
+		excerpt = string(runes[:maxQuoteLength]) + "…"
+	}
+	body, err := richtext.Build(richtext.Input{
+		Format:  richtext.FormatRich,
+		Content: richtext.EscapeMarkdown(content),
+		Blocks:  []richtext.Block{{Type: richtext.BlockQuote, Text: richtext.EscapeMarkdown(excerpt)}},
+	}, richtext.Options{})
+	if err != nil {
+		return richtext.Plain(content) // Text too long once escaped; the reminder itself still goes out
+	}
+	return body
+}
@@ -0,0 +1,273 @@
+package scheduler
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/gorm"
+	blockingDomain "thothix-backend/internal/blocking/domain"
+	chatDomain "thothix-backend/internal/chat/domain"
+	jobsDomain "thothix-backend/internal/jobs/domain"
+	messageDomain "thothix-backend/internal/message/domain"
+	"thothix-backend/internal/message/richtext"
+	moderationDomain "thothix-backend/internal/moderation/domain"
+	"thothix-backend/internal/scheduling/domain"
+	sharedModels "thothix-backend/internal/shared/models"
+	sharedTesting "thothix-backend/internal/shared/testing"
+	usersDomain "thothix-backend/internal/users/domain"
+	workspaceDomain "thothix-backend/internal/workspace/domain"
+)
+
+type SchedulerTestSuite struct {
+	suite.Suite
+	container *sharedTesting.PostgresTestContainer
+}
+
+func (suite *SchedulerTestSuite) SetupSuite() {
+	suite.container = sharedTesting.GetSharedTestContainer(
+		suite.T(),
+		"scheduling/scheduler",
+		[]interface{}{
+			&usersDomain.User{}, &sharedModels.UserRole{}, &workspaceDomain.Workspace{},
+			&chatDomain.Channel{}, &chatDomain.ChannelMember{}, &messageDomain.Message{},
+			&messageDomain.Reminder{}, &domain.ScheduledMessage{}, &moderationDomain.Suspension{}, &blockingDomain.Block{},
+			&moderationDomain.BlockedWordList{}, &moderationDomain.Report{}, &jobsDomain.Job{},
+		},
+	)
+}
+
+// createMember stores a user of workspace
+func (suite *SchedulerTestSuite) createMember(db *gorm.DB, name string, workspace *workspaceDomain.Workspace) *usersDomain.User {
+	user := &usersDomain.User{Email: name + "@example.com", Name: name, SystemRole: sharedModels.RoleUser}
+	user.ID = uuid.New().String()
+	assert.NoError(suite.T(), db.Create(user).Error)
+
+	resourceType := sharedModels.ResourceTypeWorkspace
+	assert.NoError(suite.T(), db.Create(&sharedModels.UserRole{
+		UserID: user.ID, Role: sharedModels.RoleUser, ResourceType: &resourceType, ResourceID: &workspace.ID,
+	}).Error)
+	return user
+}
+
+func (suite *SchedulerTestSuite) TestRunOnce_RechecksPermissionsAtDelivery() {
+	suite.container.WithTransaction(func(db *gorm.DB) {
+		// Arrange
+		workspace := &workspaceDomain.Workspace{Name: "scheduler-send", Slug: "scheduler-send"}
+		assert.NoError(suite.T(), db.Create(workspace).Error)
+		channel := &chatDomain.Channel{WorkspaceID: workspace.ID, Name: "private", ProjectID: uuid.New().String()}
+		assert.NoError(suite.T(), db.Create(channel).Error)
+		member := suite.createMember(db, "scheduler-send-member", workspace)
+		removed := suite.createMember(db, "scheduler-send-removed", workspace)
+		assert.NoError(suite.T(), db.Create(&chatDomain.ChannelMember{ChannelID: channel.ID, UserID: member.ID}).Error)
+
+		due := time.Now().Add(-time.Minute)
+		allowed := &domain.ScheduledMessage{WorkspaceID: workspace.ID, SenderID: member.ID, ChannelID: &channel.ID, Content: "ship it", SendAt: due, Status: domain.StatusPending}
+		revoked := &domain.ScheduledMessage{WorkspaceID: workspace.ID, SenderID: removed.ID, ChannelID: &channel.ID, Content: "too late", SendAt: due, Status: domain.StatusPending}
+		future := &domain.ScheduledMessage{WorkspaceID: workspace.ID, SenderID: member.ID, ChannelID: &channel.ID, Content: "tomorrow", SendAt: time.Now().Add(time.Hour), Status: domain.StatusPending}
+		for _, message := range []*domain.ScheduledMessage{allowed, revoked, future} {
+			assert.NoError(suite.T(), db.Create(message).Error)
+		}
+
+		// Act
+		result, err := NewScheduler(db).RunOnce(context.Background())
+
+		// Assert
+		assert.NoError(suite.T(), err)
+		assert.Equal(suite.T(), RunResult{Sent: 1, Failed: 1}, result)
+
+		assert.NoError(suite.T(), db.First(allowed, "id = ?", allowed.ID).Error)
+		assert.Equal(suite.T(), domain.StatusSent, allowed.Status)
+		var sent messageDomain.Message
+		assert.NoError(suite.T(), db.First(&sent, "id = ?", *allowed.MessageID).Error)
+		assert.Equal(suite.T(), "ship it", sent.Content)
+
+		assert.NoError(suite.T(), db.First(revoked, "id = ?", revoked.ID).Error)
+		assert.Equal(suite.T(), domain.StatusFailed, revoked.Status)
+		assert.NotEmpty(suite.T(), revoked.FailureReason)
+
+		assert.NoError(suite.T(), db.First(future, "id = ?", future.ID).Error)
+		assert.True(suite.T(), future.IsPending())
+	})
+}
+
+func (suite *SchedulerTestSuite) TestRunOnce_ScreensBlockedWords() {
+	suite.container.WithTransaction(func(db *gorm.DB) {
+		// Arrange
+		workspace := &workspaceDomain.Workspace{Name: "scheduler-screen", Slug: "scheduler-screen"}
+		assert.NoError(suite.T(), db.Create(workspace).Error)
+		rejecting := &chatDomain.Channel{WorkspaceID: workspace.ID, Name: "rejecting", ProjectID: uuid.New().String()}
+		flagging := &chatDomain.Channel{WorkspaceID: workspace.ID, Name: "flagging", ProjectID: uuid.New().String()}
+		assert.NoError(suite.T(), db.Create(rejecting).Error)
+		assert.NoError(suite.T(), db.Create(flagging).Error)
+		member := suite.createMember(db, "scheduler-screen-member", workspace)
+		for _, channel := range []*chatDomain.Channel{rejecting, flagging} {
+			assert.NoError(suite.T(), db.Create(&chatDomain.ChannelMember{ChannelID: channel.ID, UserID: member.ID}).Error)
+		}
+		assert.NoError(suite.T(), db.Create(&moderationDomain.BlockedWordList{WorkspaceID: workspace.ID, ProjectID: rejecting.ProjectID, Mode: moderationDomain.FilterReject, Words: []string{"forbidden"}}).Error)
+		assert.NoError(suite.T(), db.Create(&moderationDomain.BlockedWordList{WorkspaceID: workspace.ID, ProjectID: flagging.ProjectID, Mode: moderationDomain.FilterFlag, Words: []string{"forbidden"}}).Error)
+
+		due := time.Now().Add(-time.Minute)
+		refused := &domain.ScheduledMessage{WorkspaceID: workspace.ID, SenderID: member.ID, ChannelID: &rejecting.ID, Content: "a forbidden word", SendAt: due, Status: domain.StatusPending}
+		flagged := &domain.ScheduledMessage{WorkspaceID: workspace.ID, SenderID: member.ID, ChannelID: &flagging.ID, Content: "a forbidden word", SendAt: due, Status: domain.StatusPending}
+		assert.NoError(suite.T(), db.Create(refused).Error)
+		assert.NoError(suite.T(), db.Create(flagged).Error)
+
+		// Act
+		result, err := NewScheduler(db).RunOnce(context.Background())
+
+		// Assert
+		assert.NoError(suite.T(), err)
+		assert.Equal(suite.T(), RunResult{Sent: 1, Failed: 1}, result)
+
+		assert.NoError(suite.T(), db.First(refused, "id = ?", refused.ID).Error)
+		assert.Equal(suite.T(), domain.StatusFailed, refused.Status)
+		assert.Contains(suite.T(), refused.FailureReason, "forbidden")
+
+		assert.NoError(suite.T(), db.First(flagged, "id = ?", flagged.ID).Error)
+		assert.Equal(suite.T(), domain.StatusSent, flagged.Status)
+		var reports []moderationDomain.Report
+		assert.NoError(suite.T(), db.Where("message_id = ?", *flagged.MessageID).Find(&reports).Error)
+		assert.Len(suite.T(), reports, 1)
+		assert.Equal(suite.T(), moderationDomain.ReasonBlockedWords, reports[0].Reason)
+	})
+}
+
+func (suite *SchedulerTestSuite) TestRunOnce_SendsFormattedMessagesWithLinkPreviews() {
+	suite.container.WithTransaction(func(db *gorm.DB) {
+		// Arrange
+		workspace := &workspaceDomain.Workspace{Name: "scheduler-format", Slug: "scheduler-format"}
+		assert.NoError(suite.T(), db.Create(workspace).Error)
+		channel := &chatDomain.Channel{WorkspaceID: workspace.ID, Name: "release", ProjectID: uuid.New().String()}
+		assert.NoError(suite.T(), db.Create(channel).Error)
+		member := suite.createMember(db, "scheduler-format-member", workspace)
+		assert.NoError(suite.T(), db.Create(&chatDomain.ChannelMember{ChannelID: channel.ID, UserID: member.ID}).Error)
+
+		scheduled := &domain.ScheduledMessage{
+			WorkspaceID: workspace.ID, SenderID: member.ID, ChannelID: &channel.ID,
+			Content: "**ship** it, notes at https://example.com/notes", Format: richtext.FormatRich,
+			Blocks: []richtext.Block{{Type: richtext.BlockCode, Text: "make release"}},
+			SendAt: time.Now().Add(-time.Minute), Status: domain.StatusPending,
+		}
+		assert.NoError(suite.T(), db.Create(scheduled).Error)
+
+		// Act
+		result, err := NewScheduler(db).RunOnce(context.Background())
+
+		// Assert
+		assert.NoError(suite.T(), err)
+		assert.Equal(suite.T(), RunResult{Sent: 1}, result)
+
+		assert.NoError(suite.T(), db.First(scheduled, "id = ?", scheduled.ID).Error)
+		var message messageDomain.Message
+		assert.NoError(suite.T(), db.First(&message, "id = ?", *scheduled.MessageID).Error)
+		assert.Equal(suite.T(), richtext.FormatRich, message.Format)
+		assert.Contains(suite.T(), message.HTML, "<strong>ship</strong>")
+		assert.Equal(suite.T(), richtext.BlockCode, message.Document.Blocks[len(message.Document.Blocks)-1].Type)
+
+		var unfurls int64
+		assert.NoError(suite.T(), db.Model(&jobsDomain.Job{}).Where("kind = ?", "message.unfurl").Count(&unfurls).Error)
+		assert.Equal(suite.T(), int64(1), unfurls)
+	})
+}
+
+func (suite *SchedulerTestSuite) TestRunOnce_FailsMessagesToBlockedConversations() {
+	suite.container.WithTransaction(func(db *gorm.DB) {
+		// Arrange
+		workspace := &workspaceDomain.Workspace{Name: "scheduler-block", Slug: "scheduler-block"}
+		assert.NoError(suite.T(), db.Create(workspace).Error)
+		channel := &chatDomain.Channel{WorkspaceID: workspace.ID, Name: "conversation", ProjectID: uuid.New().String()}
+		assert.NoError(suite.T(), db.Create(channel).Error)
+		sender := suite.createMember(db, "scheduler-block-sender", workspace)
+		blocker := suite.createMember(db, "scheduler-block-blocker", workspace)
+		for _, user := range []*usersDomain.User{sender, blocker} {
+			assert.NoError(suite.T(), db.Create(&chatDomain.ChannelMember{ChannelID: channel.ID, UserID: user.ID}).Error)
+		}
+		assert.NoError(suite.T(), db.Create(&blockingDomain.Block{BlockerID: blocker.ID, BlockedID: sender.ID, Type: blockingDomain.TypeBlock}).Error)
+
+		scheduled := &domain.ScheduledMessage{WorkspaceID: workspace.ID, SenderID: sender.ID, ChannelID: &channel.ID, Content: "hello", SendAt: time.Now().Add(-time.Minute), Status: domain.StatusPending}
+		assert.NoError(suite.T(), db.Create(scheduled).Error)
+
+		// Act
+		result, err := NewScheduler(db).RunOnce(context.Background())
+
+		// Assert
+		assert.NoError(suite.T(), err)
+		assert.Equal(suite.T(), RunResult{Failed: 1}, result)
+
+		assert.NoError(suite.T(), db.First(scheduled, "id = ?", scheduled.ID).Error)
+		assert.Equal(suite.T(), domain.StatusFailed, scheduled.Status)
+		assert.Equal(suite.T(), "You cannot send direct messages to this user", scheduled.FailureReason)
+	})
+}
+
+func (suite *SchedulerTestSuite) TestRunOnce_DeliversRemindersAsSystemMessages() {
+	suite.container.WithTransaction(func(db *gorm.DB) {
+		// Arrange
+		workspace := &workspaceDomain.Workspace{Name: "scheduler-remind", Slug: "scheduler-remind"}
+		assert.NoError(suite.T(), db.Create(workspace).Error)
+		member := suite.createMember(db, "scheduler-remind-member", workspace)
+		departed := &usersDomain.User{Email: "departed@example.com", Name: "departed", SystemRole: sharedModels.RoleUser}
+		departed.ID = uuid.New().String()
+		assert.NoError(suite.T(), db.Create(departed).Error)
+
+		due := time.Now().Add(-time.Minute)
+		reminder := &messageDomain.Reminder{UserID: member.ID, WorkspaceID: &workspace.ID, Text: "water the plants", RemindAt: due}
+		orphan := &messageDomain.Reminder{UserID: departed.ID, WorkspaceID: &workspace.ID, Text: "never", RemindAt: due}
+		assert.NoError(suite.T(), db.Create(reminder).Error)
+		assert.NoError(suite.T(), db.Create(orphan).Error)
+
+		// Act
+		result, err := NewScheduler(db).RunOnce(context.Background())
+
+		// Assert
+		assert.NoError(suite.T(), err)
+		assert.Equal(suite.T(), RunResult{Reminded: 1, Skipped: 1}, result)
+
+		var messages []messageDomain.Message
+		assert.NoError(suite.T(), db.Where("receiver_id = ?", member.ID).Find(&messages).Error)
+		assert.Len(suite.T(), messages, 1)
+		assert.Equal(suite.T(), "Reminder: water the plants", messages[0].Content)
+		assert.Equal(suite.T(), messageDomain.MessageTypeSystem, messages[0].Type)
+
+		var pending int64
+		assert.NoError(suite.T(), db.Model(&messageDomain.Reminder{}).Where("delivered = ?", false).Count(&pending).Error)
+		assert.Zero(suite.T(), pending)
+	})
+}
+
+func (suite *SchedulerTestSuite) TestRunOnce_QuotesTheRemindedMessage() {
+	suite.container.WithTransaction(func(db *gorm.DB) {
+		// Arrange
+		workspace := &workspaceDomain.Workspace{Name: "scheduler-quote", Slug: "scheduler-quote"}
+		assert.NoError(suite.T(), db.Create(workspace).Error)
+		member := suite.createMember(db, "scheduler-quote-member", workspace)
+		original := messageDomain.Message{SenderID: member.ID, ReceiverID: &member.ID}
+		original.SetBody(richtext.Plain("> not a nested quote\n*not emphasis*"))
+		assert.NoError(suite.T(), db.Create(&original).Error)
+
+		reminder := &messageDomain.Reminder{UserID: member.ID, WorkspaceID: &workspace.ID, MessageID: &original.ID, Text: "reply_to_this", RemindAt: time.Now().Add(-time.Minute)}
+		assert.NoError(suite.T(), db.Create(reminder).Error)
+
+		// Act
+		result, err := NewScheduler(db).RunOnce(context.Background())
+
+		// Assert
+		assert.NoError(suite.T(), err)
+		assert.Equal(suite.T(), RunResult{Reminded: 1}, result)
+
+		var message messageDomain.Message
+		assert.NoError(suite.T(), db.Where("receiver_id = ? AND type = ?", member.ID, messageDomain.MessageTypeSystem).First(&message).Error)
+		assert.Equal(suite.T(), richtext.FormatRich, message.Format)
+		assert.Len(suite.T(), message.Document.Blocks, 2)
+		assert.Equal(suite.T(), richtext.BlockQuote, message.Document.Blocks[1].Type)
+		assert.Equal(suite.T(), "Reminder: reply_to_this\n\n> > not a nested quote\n> *not emphasis*", message.PlainText)
+	})
+}
+
+func TestSchedulerTestSuite(t *testing.T) {
+	suite.Run(t, new(SchedulerTestSuite))
+}
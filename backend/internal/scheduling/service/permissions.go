@@ -0,0 +1,121 @@
+package service
+
+import (
+	"gorm.io/gorm"
+
+	"thothix-backend/internal/blocking/filter"
+	"thothix-backend/internal/message/commands"
+	messageDomain "thothix-backend/internal/message/domain"
+	"thothix-backend/internal/message/richtext"
+	"thothix-backend/internal/moderation/screening"
+	"thothix-backend/internal/scheduling/domain"
+	"thothix-backend/internal/shared/constants"
+	"thothix-backend/internal/shared/dto"
+	sharedModels "thothix-backend/internal/shared/models"
+	usersDomain "thothix-backend/internal/users/domain"
+)
+
+// CheckDelivery reports why userID cannot send a message to the channel or recipient, nil when it can
+// Roles are resolved in the workspace carried by db's context, so the scheduler applies the same rules
+// at delivery as the API does when the message is scheduled
+func CheckDelivery(db *gorm.DB, userID string, channelID, recipientID *string) *dto.Error {
+	fail := func(code, message string) *dto.Error {
+		err := dto.NewError(code, message, nil)
+		return &err
+	}
+
+	switch {
+	case channelID != nil && recipientID != nil, channelID == nil && recipientID == nil:
+		return fail(constants.ValidationError, "Exactly one of channel_id and recipient_id is required")
+
+	case channelID != nil:
+		var channels []struct{ ProjectID string }
+		if err := db.Table("channels").Select("project_id").Scopes(sharedModels.InWorkspace("channels")).Where("id::text = ?", *channelID).Find(&channels).Error; err != nil {
+			panic(err)
+		}
+		if len(channels) == 0 {
+			return fail(constants.NotFoundError, "Channel not found")
+		}
+		resourceType := "channel"
+		if !sharedModels.HasUserPermission(db, userID, sharedModels.PermissionMessageCreate, &resourceType, channelID) {
+			return fail(constants.ForbiddenError, "Cannot send messages to this channel")
+		}
+		if failure := checkNotSuspended(db, userID, channels[0].ProjectID); failure != nil {
+			return failure
+		}
+		blocked, err := filter.ConversationBlocked(db, userID, *channelID)
+		if err != nil {
+			panic(err)
+		}
+		if blocked {
+			return fail(constants.ForbiddenError, "You cannot send direct messages to this user")
+		}
+
+	default:
+		if !sharedModels.HasUserPermission(db, userID, sharedModels.PermissionDMCreate, nil, nil) {
+			return fail(constants.ForbiddenError, "Cannot create direct messages")
+		}
+		var recipient usersDomain.User
+		if err := db.Scopes(sharedModels.WorkspaceMembers).Where("id = ?", *recipientID).First(&recipient).Error; err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return fail(constants.NotFoundError, "Recipient not found")
+			}
+			panic(err)
+		}
+		if !recipient.IsActive() {
+			return fail(constants.NotFoundError, "Recipient not found")
+		}
+		if failure := checkNotSuspended(db, userID, ""); failure != nil {
+			return failure
+		}
+		blocked, err := filter.DirectMessagesBlocked(db, userID, recipient.ID)
+		if err != nil {
+			panic(err)
+		}
+		if blocked {
+			return fail(constants.ForbiddenError, "You cannot send direct messages to this user")
+		}
+	}
+	return nil
+}
+
+// MessageBody builds the body of the message a scheduled message becomes, as messages sent right away are built
+func MessageBody(scheduled *domain.ScheduledMessage) (*richtext.Body, error) {
+	return richtext.Build(richtext.Input{Format: scheduled.Format, Content: commands.Unescape(scheduled.Content), Blocks: scheduled.Blocks}, richtext.Options{})
+}
+
+// checkNotSuspended reports a suspension keeping userID from posting in the channels of projectID,
+// or in the workspace when projectID is empty
+func checkNotSuspended(db *gorm.DB, userID, projectID string) *dto.Error {
+	suspension, err := screening.ActiveSuspension(db, userID, projectID)
+	if err != nil {
+		panic(err)
+	}
+	if suspension == nil {
+		return nil
+	}
+	failure := dto.NewError(constants.ForbiddenError, screening.SuspensionMessage(suspension), nil)
+	return &failure
+}
+
+// ReadableMessage loads a message userID can read: one in a channel they have access to, or a direct message
+// they sent or received. It returns nil for other messages so their existence is not revealed
+func ReadableMessage(db *gorm.DB, userID, messageID string) *messageDomain.Message {
+	var message messageDomain.Message
+	if err := db.Where("id::text = ?", messageID).First(&message).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil
+		}
+		panic(err)
+	}
+
+	if message.ChannelID != nil {
+		resourceType := "channel"
+		if !sharedModels.HasUserPermission(db, userID, sharedModels.PermissionChannelRead, &resourceType, message.ChannelID) {
+			return nil
+		}
+	} else if message.SenderID != userID && (message.ReceiverID == nil || *message.ReceiverID != userID) {
+		return nil
+	}
+	return &message
+}
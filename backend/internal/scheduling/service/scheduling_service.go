@@ -0,0 +1,405 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+
+	"thothix-backend/internal/message/commands"
+	messageDomain "thothix-backend/internal/message/domain"
+	"thothix-backend/internal/scheduling/domain"
+	schedulingDto "thothix-backend/internal/scheduling/dto"
+	"thothix-backend/internal/scheduling/mappers"
+	"thothix-backend/internal/shared/constants"
+	"thothix-backend/internal/shared/dto"
+	sharedMiddleware "thothix-backend/internal/shared/middleware"
+)
+
+const (
+	// maxPendingPerUser caps the scheduled messages and the reminders a user can have waiting in a workspace
+	maxPendingPerUser = 100
+
+	// maxListed caps the items returned by the list endpoints
+	maxListed = 100
+)
+
+type SchedulingService struct {
+	db     *gorm.DB
+	mapper *mappers.SchedulingMapper
+	now    func() time.Time
+}
+
+func NewSchedulingService(db *gorm.DB) *SchedulingService {
+	return &SchedulingService{
+		db:     db,
+		mapper: mappers.NewSchedulingMapper(),
+		now:    time.Now,
+	}
+}
+
+// WithContext returns a copy of the service whose database session carries ctx
+func (s *SchedulingService) WithContext(ctx context.Context) *SchedulingService {
+	return &SchedulingService{
+		db:     s.db.WithContext(ctx),
+		mapper: s.mapper,
+		now:    s.now,
+	}
+}
+
+// ListScheduledMessages lists the current user's scheduled messages in the workspace with the given status,
+// pending by default, in delivery order
+func (s *SchedulingService) ListScheduledMessages(status string) *schedulingDto.ScheduledMessageListResponse {
+	return schedulingDto.NewScheduledMessageListResponse(func() dto.Validation[[]schedulingDto.ScheduledMessageDto] {
+		switch domain.Status(status) {
+		case "":
+			status = string(domain.StatusPending)
+		case domain.StatusPending, domain.StatusSent, domain.StatusFailed:
+		default:
+			return dto.Failure[[]schedulingDto.ScheduledMessageDto](dto.NewError(constants.ValidationError, "Status must be pending, sent or failed", nil))
+		}
+
+		userID, workspaceID, failure := s.caller()
+		if failure != nil {
+			return dto.Failure[[]schedulingDto.ScheduledMessageDto](*failure)
+		}
+
+		order := "send_at"
+		if domain.Status(status) != domain.StatusPending {
+			order = "send_at DESC" // Most recent history first
+		}
+		var messages []domain.ScheduledMessage
+		if err := s.db.Where("sender_id = ? AND workspace_id = ? AND status = ?", userID, workspaceID, status).
+			Order(order).Limit(maxListed).Find(&messages).Error; err != nil {
+			panic(err)
+		}
+		return dto.Success(s.mapper.ScheduledMessagesToDtos(messages))
+	})
+}
+
+// ScheduleMessage schedules a message the current user is allowed to send now
+func (s *SchedulingService) ScheduleMessage(req *schedulingDto.CreateScheduledMessageRequest) *schedulingDto.ScheduledMessageResponse {
+	return schedulingDto.NewScheduledMessageResponse(func() dto.Validation[*schedulingDto.ScheduledMessageDto] {
+		if req == nil || strings.TrimSpace(req.Content) == "" {
+			return dto.Failure[*schedulingDto.ScheduledMessageDto](dto.NewError(constants.ValidationError, "Content is required", nil))
+		}
+		if commands.IsCommand(req.Content) {
+			return dto.Failure[*schedulingDto.ScheduledMessageDto](dto.NewError(constants.ValidationError, "Slash commands cannot be scheduled", nil))
+		}
+		if failure := s.checkTime(req.SendAt, "send_at"); failure != nil {
+			return dto.Failure[*schedulingDto.ScheduledMessageDto](*failure)
+		}
+
+		userID, workspaceID, failure := s.caller()
+		if failure != nil {
+			return dto.Failure[*schedulingDto.ScheduledMessageDto](*failure)
+		}
+		if failure := CheckDelivery(s.db, userID, req.ChannelID, req.RecipientID); failure != nil {
+			return dto.Failure[*schedulingDto.ScheduledMessageDto](*failure)
+		}
+
+		var pending int64
+		if err := s.db.Model(&domain.ScheduledMessage{}).
+			Where("sender_id = ? AND workspace_id = ? AND status = ?", userID, workspaceID, domain.StatusPending).
+			Count(&pending).Error; err != nil {
+			panic(err)
+		}
+		if pending >= maxPendingPerUser {
+			return dto.Invalid[*schedulingDto.ScheduledMessageDto](dto.NewError(constants.ConflictError, "Too many scheduled messages", nil))
+		}
+
+		message := domain.ScheduledMessage{
+			WorkspaceID: workspaceID,
+			SenderID:    userID,
+			ChannelID:   req.ChannelID,
+			ReceiverID:  req.RecipientID,
+			Content:     req.Content,
+			Format:      req.Format,
+			Blocks:      req.Blocks,
+			SendAt:      req.SendAt.UTC(),
+			Status:      domain.StatusPending,
+		}
+		if _, err := MessageBody(&message); err != nil {
+			return dto.Failure[*schedulingDto.ScheduledMessageDto](dto.NewError(constants.ValidationError, "Invalid message: "+err.Error(), nil))
+		}
+		if err := s.db.Create(&message).Error; err != nil {
+			panic(err)
+		}
+		return dto.Success(s.mapper.ScheduledMessageToDto(&message))
+	})
+}
+
+// UpdateScheduledMessage edits the content or delivery time of a pending scheduled message
+func (s *SchedulingService) UpdateScheduledMessage(id string, req *schedulingDto.UpdateScheduledMessageRequest) *schedulingDto.ScheduledMessageResponse {
+	return schedulingDto.NewScheduledMessageResponse(func() dto.Validation[*schedulingDto.ScheduledMessageDto] {
+		if req == nil || (req.Content == nil && req.Format == nil && req.Blocks == nil && req.SendAt == nil) {
+			return dto.Failure[*schedulingDto.ScheduledMessageDto](dto.NewError(constants.ValidationError, "Nothing to update", nil))
+		}
+		if req.Content != nil && strings.TrimSpace(*req.Content) == "" {
+			return dto.Failure[*schedulingDto.ScheduledMessageDto](dto.NewError(constants.ValidationError, "Content cannot be empty", nil))
+		}
+		if req.Content != nil && commands.IsCommand(*req.Content) {
+			return dto.Failure[*schedulingDto.ScheduledMessageDto](dto.NewError(constants.ValidationError, "Slash commands cannot be scheduled", nil))
+		}
+		if req.SendAt != nil {
+			if failure := s.checkTime(*req.SendAt, "send_at"); failure != nil {
+				return dto.Failure[*schedulingDto.ScheduledMessageDto](*failure)
+			}
+		}
+
+		message, failure := s.pendingMessage(id)
+		if failure != nil {
+			return dto.Failure[*schedulingDto.ScheduledMessageDto](*failure)
+		}
+
+		updates := map[string]interface{}{}
+		if req.Content != nil {
+			message.Content = *req.Content
+			updates["content"] = message.Content
+		}
+		if req.Format != nil {
+			message.Format = *req.Format
+			updates["format"] = message.Format
+		}
+		if req.Blocks != nil {
+			message.Blocks = req.Blocks
+			updates["blocks"] = message.Blocks
+		}
+		if _, err := MessageBody(message); err != nil {
+			return dto.Failure[*schedulingDto.ScheduledMessageDto](dto.NewError(constants.ValidationError, "Invalid message: "+err.Error(), nil))
+		}
+		if req.SendAt != nil {
+			message.SendAt = req.SendAt.UTC()
+			updates["send_at"] = message.SendAt
+		}
+		// Select with the struct so the content and blocks go through the encrypted serializer
+		if err := s.db.Model(message).Select(keys(updates)).Updates(message).Error; err != nil {
+			panic(err)
+		}
+		return dto.Success(s.mapper.ScheduledMessageToDto(message))
+	})
+}
+
+// CancelScheduledMessage deletes a pending scheduled message
+func (s *SchedulingService) CancelScheduledMessage(id string) *schedulingDto.ScheduledMessageResponse {
+	return schedulingDto.NewScheduledMessageResponse(func() dto.Validation[*schedulingDto.ScheduledMessageDto] {
+		message, failure := s.pendingMessage(id)
+		if failure != nil {
+			return dto.Failure[*schedulingDto.ScheduledMessageDto](*failure)
+		}
+
+		if err := s.db.Delete(message).Error; err != nil {
+			panic(err)
+		}
+		return dto.Success(s.mapper.ScheduledMessageToDto(message))
+	})
+}
+
+// ListReminders lists the current user's reminders in the workspace, pending ones in delivery order
+// or, with delivered, the most recently delivered first
+func (s *SchedulingService) ListReminders(delivered bool) *schedulingDto.ReminderListResponse {
+	return schedulingDto.NewReminderListResponse(func() dto.Validation[[]schedulingDto.ReminderDto] {
+		userID, workspaceID, failure := s.caller()
+		if failure != nil {
+			return dto.Failure[[]schedulingDto.ReminderDto](*failure)
+		}
+
+		order := "remind_at"
+		if delivered {
+			order = "remind_at DESC"
+		}
+		var reminders []messageDomain.Reminder
+		if err := s.db.Where("user_id = ? AND workspace_id = ? AND delivered = ?", userID, workspaceID, delivered).
+			Order(order).Limit(maxListed).Find(&reminders).Error; err != nil {
+			panic(err)
+		}
+		return dto.Success(s.mapper.RemindersToDtos(reminders))
+	})
+}
+
+// CreateReminder sets a reminder for the current user, optionally about a message they can read
+func (s *SchedulingService) CreateReminder(req *schedulingDto.CreateReminderRequest) *schedulingDto.ReminderResponse {
+	return schedulingDto.NewReminderResponse(func() dto.Validation[*schedulingDto.ReminderDto] {
+		if req == nil || strings.TrimSpace(req.Text) == "" {
+			return dto.Failure[*schedulingDto.ReminderDto](dto.NewError(constants.ValidationError, "Text is required", nil))
+		}
+		if failure := s.checkTime(req.RemindAt, "remind_at"); failure != nil {
+			return dto.Failure[*schedulingDto.ReminderDto](*failure)
+		}
+
+		userID, workspaceID, failure := s.caller()
+		if failure != nil {
+			return dto.Failure[*schedulingDto.ReminderDto](*failure)
+		}
+
+		var pending int64
+		if err := s.db.Model(&messageDomain.Reminder{}).
+			Where("user_id = ? AND workspace_id = ? AND delivered = ?", userID, workspaceID, false).
+			Count(&pending).Error; err != nil {
+			panic(err)
+		}
+		if pending >= maxPendingPerUser {
+			return dto.Invalid[*schedulingDto.ReminderDto](dto.NewError(constants.ConflictError, "Too many pending reminders", nil))
+		}
+
+		reminder := messageDomain.Reminder{
+			UserID:      userID,
+			WorkspaceID: &workspaceID,
+			Text:        req.Text,
+			RemindAt:    req.RemindAt.UTC(),
+		}
+		if req.MessageID != nil {
+			message := ReadableMessage(s.db, userID, *req.MessageID)
+			if message == nil {
+				return dto.Invalid[*schedulingDto.ReminderDto](dto.NewError(constants.NotFoundError, "Message not found", nil))
+			}
+			reminder.MessageID = &message.ID
+			reminder.ChannelID = message.ChannelID
+		}
+		if err := s.db.Create(&reminder).Error; err != nil {
+			panic(err)
+		}
+		return dto.Success(s.mapper.ReminderToDto(&reminder))
+	})
+}
+
+// UpdateReminder edits the text or time of a reminder that has not been delivered
+func (s *SchedulingService) UpdateReminder(id string, req *schedulingDto.UpdateReminderRequest) *schedulingDto.ReminderResponse {
+	return schedulingDto.NewReminderResponse(func() dto.Validation[*schedulingDto.ReminderDto] {
+		if req == nil || (req.Text == nil && req.RemindAt == nil) {
+			return dto.Failure[*schedulingDto.ReminderDto](dto.NewError(constants.ValidationError, "Nothing to update", nil))
+		}
+		if req.Text != nil && strings.TrimSpace(*req.Text) == "" {
+			return dto.Failure[*schedulingDto.ReminderDto](dto.NewError(constants.ValidationError, "Text cannot be empty", nil))
+		}
+		if req.RemindAt != nil {
+			if failure := s.checkTime(*req.RemindAt, "remind_at"); failure != nil {
+				return dto.Failure[*schedulingDto.ReminderDto](*failure)
+			}
+		}
+
+		reminder, failure := s.pendingReminder(id)
+		if failure != nil {
+			return dto.Failure[*schedulingDto.ReminderDto](*failure)
+		}
+
+		updates := map[string]interface{}{}
+		if req.Text != nil {
+			reminder.Text = *req.Text
+			updates["text"] = reminder.Text
+		}
+		if req.RemindAt != nil {
+			reminder.RemindAt = req.RemindAt.UTC()
+			updates["remind_at"] = reminder.RemindAt
+		}
+		if err := s.db.Model(reminder).Updates(updates).Error; err != nil {
+			panic(err)
+		}
+		return dto.Success(s.mapper.ReminderToDto(reminder))
+	})
+}
+
+// DeleteReminder cancels a reminder of the current user, delivered or not
+func (s *SchedulingService) DeleteReminder(id string) *schedulingDto.ReminderResponse {
+	return schedulingDto.NewReminderResponse(func() dto.Validation[*schedulingDto.ReminderDto] {
+		userID, workspaceID, failure := s.caller()
+		if failure != nil {
+			return dto.Failure[*schedulingDto.ReminderDto](*failure)
+		}
+
+		var reminder messageDomain.Reminder
+		if err := s.db.Where("id::text = ? AND user_id = ? AND workspace_id = ?", id, userID, workspaceID).First(&reminder).Error; err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return dto.Invalid[*schedulingDto.ReminderDto](dto.NewError(constants.NotFoundError, "Reminder not found", nil))
+			}
+			panic(err)
+		}
+		if err := s.db.Delete(&reminder).Error; err != nil {
+			panic(err)
+		}
+		return dto.Success(s.mapper.ReminderToDto(&reminder))
+	})
+}
+
+// caller returns the current user and workspace
+func (s *SchedulingService) caller() (string, string, *dto.Error) {
+	userID, ok := sharedMiddleware.UserIDFromContext(s.db.Statement.Context)
+	if !ok {
+		err := dto.NewError(constants.UnauthorizedError, "User not authenticated", nil)
+		return "", "", &err
+	}
+	workspaceID, ok := sharedMiddleware.WorkspaceIDFromContext(s.db.Statement.Context)
+	if !ok {
+		err := dto.NewError(constants.ValidationError, "A current workspace is required", nil)
+		return "", "", &err
+	}
+	return userID, workspaceID, nil
+}
+
+// checkTime requires a delivery time in the future and within MaxScheduleAhead
+func (s *SchedulingService) checkTime(at time.Time, field string) *dto.Error {
+	now := s.now()
+	switch {
+	case !at.After(now):
+		err := dto.NewError(constants.ValidationError, "Time must be in the future", map[string]string{field: "must be in the future"})
+		return &err
+	case at.Sub(now) > domain.MaxScheduleAhead:
+		err := dto.NewError(constants.ValidationError, "Time is too far in the future", map[string]string{field: "must be within a year"})
+		return &err
+	}
+	return nil
+}
+
+// pendingMessage loads a scheduled message of the current user that has not been sent
+// Sent and failed messages are reported as conflicts, since they can no longer change
+func (s *SchedulingService) pendingMessage(id string) (*domain.ScheduledMessage, *dto.Error) {
+	userID, workspaceID, failure := s.caller()
+	if failure != nil {
+		return nil, failure
+	}
+
+	var message domain.ScheduledMessage
+	if err := s.db.Where("id::text = ? AND sender_id = ? AND workspace_id = ?", id, userID, workspaceID).First(&message).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			err := dto.NewError(constants.NotFoundError, "Scheduled message not found", nil)
+			return nil, &err
+		}
+		panic(err)
+	}
+	if !message.IsPending() {
+		err := dto.NewError(constants.ConflictError, "Scheduled message was already "+string(message.Status), nil)
+		return nil, &err
+	}
+	return &message, nil
+}
+
+// pendingReminder loads a reminder of the current user that has not been delivered
+func (s *SchedulingService) pendingReminder(id string) (*messageDomain.Reminder, *dto.Error) {
+	userID, workspaceID, failure := s.caller()
+	if failure != nil {
+		return nil, failure
+	}
+
+	var reminder messageDomain.Reminder
+	if err := s.db.Where("id::text = ? AND user_id = ? AND workspace_id = ?", id, userID, workspaceID).First(&reminder).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			err := dto.NewError(constants.NotFoundError, "Reminder not found", nil)
+			return nil, &err
+		}
+		panic(err)
+	}
+	if reminder.Delivered {
+		err := dto.NewError(constants.ConflictError, "Reminder was already delivered", nil)
+		return nil, &err
+	}
+	return &reminder, nil
+}
+
+func keys(values map[string]interface{}) []string {
+	result := make([]string, 0, len(values))
+	for key := range values {
+		result = append(result, key)
+	}
+	return result
+}
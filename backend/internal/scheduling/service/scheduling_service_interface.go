@@ -0,0 +1,24 @@
+package service
+
+import (
+	"context"
+
+	schedulingDto "thothix-backend/internal/scheduling/dto"
+)
+
+// SchedulingServiceInterface defines the contract for scheduled messages and reminders using Response pattern
+type SchedulingServiceInterface interface {
+	ListScheduledMessages(status string) *schedulingDto.ScheduledMessageListResponse
+	ScheduleMessage(req *schedulingDto.CreateScheduledMessageRequest) *schedulingDto.ScheduledMessageResponse
+	UpdateScheduledMessage(id string, req *schedulingDto.UpdateScheduledMessageRequest) *schedulingDto.ScheduledMessageResponse
+	CancelScheduledMessage(id string) *schedulingDto.ScheduledMessageResponse
+	ListReminders(delivered bool) *schedulingDto.ReminderListResponse
+	CreateReminder(req *schedulingDto.CreateReminderRequest) *schedulingDto.ReminderResponse
+	UpdateReminder(id string, req *schedulingDto.UpdateReminderRequest) *schedulingDto.ReminderResponse
+	DeleteReminder(id string) *schedulingDto.ReminderResponse
+}
+
+// ContextAwareSchedulingService is implemented by services that can bind their database session to a request context
+type ContextAwareSchedulingService interface {
+	WithContext(ctx context.Context) *SchedulingService
+}
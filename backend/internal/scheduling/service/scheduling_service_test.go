@@ -0,0 +1,198 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/gorm"
+	blockingDomain "thothix-backend/internal/blocking/domain"
+	chatDomain "thothix-backend/internal/chat/domain"
+	messageDomain "thothix-backend/internal/message/domain"
+	moderationDomain "thothix-backend/internal/moderation/domain"
+	"thothix-backend/internal/scheduling/domain"
+	schedulingDto "thothix-backend/internal/scheduling/dto"
+	sharedMiddleware "thothix-backend/internal/shared/middleware"
+	sharedModels "thothix-backend/internal/shared/models"
+	sharedTesting "thothix-backend/internal/shared/testing"
+	usersDomain "thothix-backend/internal/users/domain"
+	workspaceDomain "thothix-backend/internal/workspace/domain"
+)
+
+type SchedulingServiceTestSuite struct {
+	suite.Suite
+	container *sharedTesting.PostgresTestContainer
+}
+
+func (suite *SchedulingServiceTestSuite) SetupSuite() {
+	suite.container = sharedTesting.GetSharedTestContainer(
+		suite.T(),
+		"scheduling/service",
+		[]interface{}{
+			&usersDomain.User{}, &sharedModels.UserRole{}, &workspaceDomain.Workspace{},
+			&chatDomain.Channel{}, &chatDomain.ChannelMember{}, &messageDomain.Message{},
+			&messageDomain.Reminder{}, &domain.ScheduledMessage{}, &moderationDomain.Suspension{}, &blockingDomain.Block{},
+		},
+	)
+}
+
+// fixture is a workspace with a private channel, a member of it and a message the member posted there
+type fixture struct {
+	workspace *workspaceDomain.Workspace
+	channel   *chatDomain.Channel
+	member    *usersDomain.User
+	message   *messageDomain.Message
+}
+
+func (suite *SchedulingServiceTestSuite) createFixture(db *gorm.DB, slug string) *fixture {
+	workspace := &workspaceDomain.Workspace{Name: slug, Slug: slug}
+	assert.NoError(suite.T(), db.Create(workspace).Error)
+
+	channel := &chatDomain.Channel{WorkspaceID: workspace.ID, Name: "private", ProjectID: uuid.New().String()}
+	assert.NoError(suite.T(), db.Create(channel).Error)
+
+	member := suite.createMember(db, slug+"-member", workspace)
+	assert.NoError(suite.T(), db.Create(&chatDomain.ChannelMember{ChannelID: channel.ID, UserID: member.ID}).Error)
+
+	message := &messageDomain.Message{SenderID: member.ID, ChannelID: &channel.ID, Content: "release notes"}
+	assert.NoError(suite.T(), db.Create(message).Error)
+
+	return &fixture{workspace: workspace, channel: channel, member: member, message: message}
+}
+
+// createMember stores a user of workspace
+func (suite *SchedulingServiceTestSuite) createMember(db *gorm.DB, name string, workspace *workspaceDomain.Workspace) *usersDomain.User {
+	user := &usersDomain.User{Email: name + "@example.com", Name: name, SystemRole: sharedModels.RoleUser}
+	user.ID = uuid.New().String()
+	assert.NoError(suite.T(), db.Create(user).Error)
+
+	resourceType := sharedModels.ResourceTypeWorkspace
+	assert.NoError(suite.T(), db.Create(&sharedModels.UserRole{
+		UserID: user.ID, Role: sharedModels.RoleUser, ResourceType: &resourceType, ResourceID: &workspace.ID,
+	}).Error)
+	return user
+}
+
+// serviceFor creates a service acting as the given user in workspace
+func (suite *SchedulingServiceTestSuite) serviceFor(db *gorm.DB, userID string, workspace *workspaceDomain.Workspace) *SchedulingService {
+	ctx := sharedMiddleware.WithWorkspaceID(sharedMiddleware.WithUserID(context.Background(), userID), workspace.ID)
+	return NewSchedulingService(db).WithContext(ctx)
+}
+
+func (suite *SchedulingServiceTestSuite) TestScheduleMessage_Validation() {
+	suite.container.WithTransaction(func(db *gorm.DB) {
+		// Arrange
+		f := suite.createFixture(db, "schedule-validation")
+		service := suite.serviceFor(db, f.member.ID, f.workspace)
+		later := time.Now().Add(time.Hour)
+
+		// Act
+		past := service.ScheduleMessage(&schedulingDto.CreateScheduledMessageRequest{ChannelID: &f.channel.ID, Content: "hi", SendAt: time.Now().Add(-time.Minute)})
+		tooFar := service.ScheduleMessage(&schedulingDto.CreateScheduledMessageRequest{ChannelID: &f.channel.ID, Content: "hi", SendAt: time.Now().Add(2 * domain.MaxScheduleAhead)})
+		both := service.ScheduleMessage(&schedulingDto.CreateScheduledMessageRequest{ChannelID: &f.channel.ID, RecipientID: &f.member.ID, Content: "hi", SendAt: later})
+		command := service.ScheduleMessage(&schedulingDto.CreateScheduledMessageRequest{ChannelID: &f.channel.ID, Content: "/topic later", SendAt: later})
+		unknown := service.ScheduleMessage(&schedulingDto.CreateScheduledMessageRequest{ChannelID: stringPtr("not-a-uuid"), Content: "hi", SendAt: later})
+
+		// Assert
+		sharedTesting.AssertValidationErrorWithCode(suite.T(), past.Response, "VALIDATION_ERROR")
+		sharedTesting.AssertValidationErrorWithCode(suite.T(), tooFar.Response, "VALIDATION_ERROR")
+		sharedTesting.AssertValidationErrorWithCode(suite.T(), both.Response, "VALIDATION_ERROR")
+		sharedTesting.AssertValidationErrorWithCode(suite.T(), command.Response, "VALIDATION_ERROR")
+		sharedTesting.AssertValidationErrorWithCode(suite.T(), unknown.Response, "NOT_FOUND")
+	})
+}
+
+func (suite *SchedulingServiceTestSuite) TestScheduleMessage_RequiresChannelAccess() {
+	suite.container.WithTransaction(func(db *gorm.DB) {
+		// Arrange
+		f := suite.createFixture(db, "schedule-access")
+		outsider := suite.createMember(db, "schedule-access-outsider", f.workspace)
+		request := &schedulingDto.CreateScheduledMessageRequest{ChannelID: &f.channel.ID, Content: "standup in 5", SendAt: time.Now().Add(time.Hour)}
+
+		// Act
+		byOutsider := suite.serviceFor(db, outsider.ID, f.workspace).ScheduleMessage(request)
+		byMember := suite.serviceFor(db, f.member.ID, f.workspace).ScheduleMessage(request)
+
+		// Assert
+		sharedTesting.AssertValidationErrorWithCode(suite.T(), byOutsider.Response, "FORBIDDEN")
+		scheduled := sharedTesting.AssertSuccessWithValue(suite.T(), byMember.Response)
+		assert.Equal(suite.T(), "pending", scheduled.Status)
+		assert.Equal(suite.T(), "standup in 5", scheduled.Content)
+	})
+}
+
+func (suite *SchedulingServiceTestSuite) TestScheduleMessage_DirectMessageBlockedEitherWay() {
+	suite.container.WithTransaction(func(db *gorm.DB) {
+		// Arrange
+		f := suite.createFixture(db, "schedule-blocked")
+		blocker := suite.createMember(db, "schedule-blocked-blocker", f.workspace)
+		assert.NoError(suite.T(), db.Create(&blockingDomain.Block{BlockerID: blocker.ID, BlockedID: f.member.ID, Type: blockingDomain.TypeBlock}).Error)
+		later := time.Now().Add(time.Hour)
+
+		// Act
+		toBlocker := suite.serviceFor(db, f.member.ID, f.workspace).ScheduleMessage(&schedulingDto.CreateScheduledMessageRequest{RecipientID: &blocker.ID, Content: "hi", SendAt: later})
+		fromBlocker := suite.serviceFor(db, blocker.ID, f.workspace).ScheduleMessage(&schedulingDto.CreateScheduledMessageRequest{RecipientID: &f.member.ID, Content: "hi", SendAt: later})
+
+		// Assert
+		sharedTesting.AssertValidationErrorWithCode(suite.T(), toBlocker.Response, "FORBIDDEN")
+		sharedTesting.AssertValidationErrorWithCode(suite.T(), fromBlocker.Response, "FORBIDDEN")
+	})
+}
+
+func (suite *SchedulingServiceTestSuite) TestUpdateAndCancel_PendingOnly() {
+	suite.container.WithTransaction(func(db *gorm.DB) {
+		// Arrange
+		f := suite.createFixture(db, "schedule-pending")
+		service := suite.serviceFor(db, f.member.ID, f.workspace)
+		scheduled := sharedTesting.AssertSuccessWithValue(suite.T(), service.ScheduleMessage(&schedulingDto.CreateScheduledMessageRequest{
+			ChannelID: &f.channel.ID, Content: "draft", SendAt: time.Now().Add(time.Hour),
+		}).Response)
+		sent := &domain.ScheduledMessage{WorkspaceID: f.workspace.ID, SenderID: f.member.ID, ChannelID: &f.channel.ID, Content: "old", SendAt: time.Now(), Status: domain.StatusSent}
+		assert.NoError(suite.T(), db.Create(sent).Error)
+		other := suite.createMember(db, "schedule-pending-other", f.workspace)
+
+		// Act
+		updated := service.UpdateScheduledMessage(scheduled.ID, &schedulingDto.UpdateScheduledMessageRequest{Content: stringPtr("final")})
+		byOther := suite.serviceFor(db, other.ID, f.workspace).CancelScheduledMessage(scheduled.ID)
+		cancelSent := service.CancelScheduledMessage(sent.ID)
+		cancelled := service.CancelScheduledMessage(scheduled.ID)
+
+		// Assert
+		assert.Equal(suite.T(), "final", sharedTesting.AssertSuccessWithValue(suite.T(), updated.Response).Content)
+		sharedTesting.AssertValidationErrorWithCode(suite.T(), byOther.Response, "NOT_FOUND")
+		sharedTesting.AssertValidationErrorWithCode(suite.T(), cancelSent.Response, "CONFLICT")
+		sharedTesting.AssertSuccessWithValue(suite.T(), cancelled.Response)
+		listed := sharedTesting.AssertSuccessWithValue(suite.T(), service.ListScheduledMessages("").Response)
+		assert.Empty(suite.T(), listed)
+	})
+}
+
+func (suite *SchedulingServiceTestSuite) TestCreateReminder_AboutReadableMessageOnly() {
+	suite.container.WithTransaction(func(db *gorm.DB) {
+		// Arrange
+		f := suite.createFixture(db, "reminder-message")
+		outsider := suite.createMember(db, "reminder-message-outsider", f.workspace)
+		request := &schedulingDto.CreateReminderRequest{Text: "review", RemindAt: time.Now().Add(time.Hour), MessageID: &f.message.ID}
+
+		// Act
+		byOutsider := suite.serviceFor(db, outsider.ID, f.workspace).CreateReminder(request)
+		byMember := suite.serviceFor(db, f.member.ID, f.workspace).CreateReminder(request)
+
+		// Assert
+		sharedTesting.AssertValidationErrorWithCode(suite.T(), byOutsider.Response, "NOT_FOUND")
+		reminder := sharedTesting.AssertSuccessWithValue(suite.T(), byMember.Response)
+		assert.Equal(suite.T(), &f.channel.ID, reminder.ChannelID)
+		assert.False(suite.T(), reminder.Delivered)
+	})
+}
+
+func stringPtr(value string) *string {
+	return &value
+}
+
+func TestSchedulingServiceTestSuite(t *testing.T) {
+	suite.Run(t, new(SchedulingServiceTestSuite))
+}
@@ -0,0 +1,81 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// Conn is a long-lived connection, such as a WebSocket, that survives http.Server.Shutdown
+// because it was hijacked; Close must send a close frame before releasing the connection
+type Conn interface {
+	Close(ctx context.Context) error
+}
+
+// Connections tracks hijacked connections so they can be closed cleanly on shutdown
+type Connections struct {
+	mu     sync.Mutex
+	conns  map[Conn]struct{}
+	closed bool
+}
+
+// NewConnections creates an empty registry
+func NewConnections() *Connections {
+	return &Connections{conns: make(map[Conn]struct{})}
+}
+
+// ErrShuttingDown is returned by Add once CloseAll has started
+var ErrShuttingDown = errors.New("server is shutting down")
+
+// Add tracks conn until the returned function is called when the connection ends
+func (c *Connections) Add(conn Conn) (func(), error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.closed {
+		return nil, ErrShuttingDown
+	}
+	c.conns[conn] = struct{}{}
+	return func() {
+		c.mu.Lock()
+		delete(c.conns, conn)
+		c.mu.Unlock()
+	}, nil
+}
+
+// Len returns the number of tracked connections
+func (c *Connections) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.conns)
+}
+
+// CloseAll closes every tracked connection concurrently and rejects new ones
+func (c *Connections) CloseAll(ctx context.Context) error {
+	c.mu.Lock()
+	c.closed = true
+	conns := make([]Conn, 0, len(c.conns))
+	for conn := range c.conns {
+		conns = append(conns, conn)
+	}
+	c.conns = make(map[Conn]struct{})
+	c.mu.Unlock()
+
+	var (
+		wg   sync.WaitGroup
+		mu   sync.Mutex
+		errs []error
+	)
+	for _, conn := range conns {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := conn.Close(ctx); err != nil {
+				mu.Lock()
+				errs = append(errs, err)
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+	return errors.Join(errs...)
+}
@@ -0,0 +1,78 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"time"
+
+	"thothix-backend/internal/config"
+	"thothix-backend/internal/shared/logging"
+)
+
+// Step is a named shutdown action run after the HTTP server has drained
+type Step struct {
+	Name string
+	Stop func(ctx context.Context) error
+}
+
+// New creates the HTTP server with the timeouts and header limit from cfg
+func New(cfg *config.Config, handler http.Handler) *http.Server {
+	return &http.Server{
+		Addr:              ":" + cfg.Port,
+		Handler:           handler,
+		ReadHeaderTimeout: cfg.HTTPReadHeaderTimeout,
+		ReadTimeout:       cfg.HTTPReadTimeout,
+		WriteTimeout:      cfg.HTTPWriteTimeout,
+		IdleTimeout:       cfg.HTTPIdleTimeout,
+		MaxHeaderBytes:    cfg.HTTPMaxHeaderBytes,
+	}
+}
+
+// Run serves srv on ln until ctx is cancelled or the server fails, then shuts down gracefully:
+// it stops accepting connections, waits for in-flight requests and runs steps in order.
+// The whole shutdown shares timeout; a failing step is logged and the remaining steps still run
+func Run(ctx context.Context, srv *http.Server, ln net.Listener, timeout time.Duration, steps ...Step) error {
+	logger := logging.FromContext(ctx)
+
+	serveErr := make(chan error, 1)
+	go func() {
+		if err := srv.Serve(ln); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			serveErr <- err
+		}
+		close(serveErr)
+	}()
+
+	var runErr error
+	select {
+	case <-ctx.Done():
+		logger.Info("Shutdown signal received, draining HTTP connections")
+	case err := <-serveErr:
+		runErr = fmt.Errorf("server failed: %w", err)
+		logger.Error("Server failed, shutting down", slog.Any("error", err))
+	}
+
+	// The signal context is already cancelled, so the deadline starts from a fresh one
+	shutdownCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), timeout)
+	defer cancel()
+
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		logger.Error("HTTP drain incomplete", slog.Any("error", err))
+		runErr = errors.Join(runErr, fmt.Errorf("http: %w", err))
+	}
+
+	for _, step := range steps {
+		start := time.Now()
+		if err := step.Stop(shutdownCtx); err != nil {
+			logger.Error("Shutdown step failed", slog.String("step", step.Name), slog.Any("error", err))
+			runErr = errors.Join(runErr, fmt.Errorf("%s: %w", step.Name, err))
+			continue
+		}
+		logger.Info("Shutdown step completed", slog.String("step", step.Name), slog.Duration("duration", time.Since(start)))
+	}
+
+	return runErr
+}
@@ -0,0 +1,114 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"thothix-backend/internal/config"
+)
+
+func TestNew_AppliesConfig(t *testing.T) {
+	cfg := &config.Config{
+		Port:                  "30000",
+		HTTPReadHeaderTimeout: time.Second,
+		HTTPReadTimeout:       2 * time.Second,
+		HTTPWriteTimeout:      3 * time.Second,
+		HTTPIdleTimeout:       4 * time.Second,
+		HTTPMaxHeaderBytes:    8192,
+	}
+
+	srv := New(cfg, http.NotFoundHandler())
+
+	assert.Equal(t, ":30000", srv.Addr)
+	assert.Equal(t, time.Second, srv.ReadHeaderTimeout)
+	assert.Equal(t, 2*time.Second, srv.ReadTimeout)
+	assert.Equal(t, 3*time.Second, srv.WriteTimeout)
+	assert.Equal(t, 4*time.Second, srv.IdleTimeout)
+	assert.Equal(t, 8192, srv.MaxHeaderBytes)
+}
+
+func TestRun_DrainsRequestsThenRunsStepsInOrder(t *testing.T) {
+	// Arrange
+	started := make(chan struct{})
+	release := make(chan struct{})
+	srv := &http.Server{Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		<-release
+		_, _ = io.WriteString(w, "done")
+	})}
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	var order []string
+	step := func(name string, err error) Step {
+		return Step{Name: name, Stop: func(context.Context) error {
+			order = append(order, name)
+			return err
+		}}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	runErr := make(chan error, 1)
+	go func() {
+		runErr <- Run(ctx, srv, ln, 5*time.Second, step("websockets", nil), step("workers", errors.New("stuck")), step("database", nil))
+	}()
+
+	response := make(chan string, 1)
+	go func() {
+		resp, err := http.Get("http://" + ln.Addr().String())
+		if err != nil {
+			response <- err.Error()
+			return
+		}
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(resp.Body)
+		response <- string(body)
+	}()
+	<-started
+
+	// Act
+	cancel()
+	time.Sleep(50 * time.Millisecond)
+	assert.Empty(t, order, "steps must wait for in-flight requests")
+	close(release)
+
+	// Assert
+	assert.Equal(t, "done", <-response)
+	err = <-runErr
+	assert.ErrorContains(t, err, "workers: stuck")
+	assert.Equal(t, []string{"websockets", "workers", "database"}, order)
+}
+
+type fakeConn struct{ closed bool }
+
+func (f *fakeConn) Close(context.Context) error {
+	f.closed = true
+	return nil
+}
+
+func TestConnections_CloseAll(t *testing.T) {
+	connections := NewConnections()
+	kept, gone := &fakeConn{}, &fakeConn{}
+
+	_, err := connections.Add(kept)
+	require.NoError(t, err)
+	remove, err := connections.Add(gone)
+	require.NoError(t, err)
+	remove()
+
+	assert.Equal(t, 1, connections.Len())
+	assert.NoError(t, connections.CloseAll(context.Background()))
+	assert.True(t, kept.closed)
+	assert.False(t, gone.closed)
+
+	_, err = connections.Add(&fakeConn{})
+	assert.ErrorIs(t, err, ErrShuttingDown)
+}
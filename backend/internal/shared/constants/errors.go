@@ -9,8 +9,18 @@ const (
 	ConflictError   = "CONFLICT"
 
 	// User specific errors
-	UserNotFoundError = "USER_NOT_FOUND"
-	UserExistsError   = "USER_EXISTS"
+	UserNotFoundError    = "USER_NOT_FOUND"
+	UserExistsError      = "USER_EXISTS"
+	UserDeactivatedError = "USER_DEACTIVATED"
+	UserPurgedError      = "USER_PURGED"
+
+	// Command errors
+	CommandNotFoundError = "COMMAND_NOT_FOUND"
+	CommandUsageError    = "COMMAND_USAGE"
+	CommandFailedError   = "COMMAND_FAILED"
+
+	// Moderation errors
+	BlockedWordsError = "BLOCKED_WORDS"
 
 	// Authorization errors
 	UnauthorizedError = "UNAUTHORIZED"
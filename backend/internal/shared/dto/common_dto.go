@@ -1,8 +1,11 @@
 package dto
 
 import (
+	"context"
 	"fmt"
-	"log"
+	"log/slog"
+
+	"thothix-backend/internal/shared/logging"
 )
 
 // Error represents a validation/business error with structured information.
@@ -281,16 +284,16 @@ func ValidationErrorResponse(errors []Error) ErrorViewModel {
 }
 
 // LoggedSystemErrorResponse creates a system error response with automatic logging.
-// Logs the error with the provided context message and returns a standardized ErrorViewModel.
-func LoggedSystemErrorResponse(err error, logMessage string, logArgs ...interface{}) ErrorViewModel {
-	log.Printf(logMessage+": %v", append(logArgs, err)...)
+// Logs the error through the request logger of ctx and returns a standardized ErrorViewModel.
+func LoggedSystemErrorResponse(ctx context.Context, err error, logMessage string, logArgs ...interface{}) ErrorViewModel {
+	logging.FromContext(ctx).ErrorContext(ctx, fmt.Sprintf(logMessage, logArgs...), slog.Any("error", err))
 	return SystemErrorResponse(err)
 }
 
 // LoggedValidationErrorResponse creates a validation error response with automatic logging.
-// Logs the validation errors with the provided context message and returns a standardized ErrorViewModel.
-func LoggedValidationErrorResponse(errors []Error, logMessage string, logArgs ...interface{}) ErrorViewModel {
-	log.Printf(logMessage+": %v", append(logArgs, errors)...)
+// Logs the validation errors through the request logger of ctx and returns a standardized ErrorViewModel.
+func LoggedValidationErrorResponse(ctx context.Context, errors []Error, logMessage string, logArgs ...interface{}) ErrorViewModel {
+	logging.FromContext(ctx).WarnContext(ctx, fmt.Sprintf(logMessage, logArgs...), slog.Any("errors", errors))
 	return ValidationErrorResponse(errors)
 }
 
@@ -1,35 +1,41 @@
 package handlers
 
 import (
-	"log"
+	"context"
+	"log/slog"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
 	"gorm.io/gorm"
 
+	"thothix-backend/internal/metrics"
 	"thothix-backend/internal/shared/dto"
+	"thothix-backend/internal/shared/logging"
 	sharedMiddleware "thothix-backend/internal/shared/middleware"
 	usersDto "thothix-backend/internal/users/dto"
 	"thothix-backend/internal/users/service"
 )
 
+// webhookActorID identifies changes made by Clerk webhooks in created_by/updated_by and the audit log
+const webhookActorID = "clerk_webhook"
+
 type AuthHandler struct {
-	db               *gorm.DB
-	userService      service.UserServiceInterface
-	clerkUserService service.ClerkUserServiceInterface
-	userServiceImpl  *service.UserService // For legacy webhook methods
+	db              *gorm.DB
+	userServiceImpl *service.UserService // Bound to the request context per call
 }
 
 func NewAuthHandler(db *gorm.DB) *AuthHandler {
-	userServiceImpl := service.NewUserService(db)
 	return &AuthHandler{
-		db:               db,
-		userService:      userServiceImpl,
-		clerkUserService: userServiceImpl,
-		userServiceImpl:  userServiceImpl,
+		db:              db,
+		userServiceImpl: service.NewUserService(db),
 	}
 }
 
+// userService returns the user service bound to ctx, used for both core and Clerk operations
+func (h *AuthHandler) userService(ctx context.Context) *service.UserService {
+	return h.userServiceImpl.WithContext(ctx)
+}
+
 // SyncUser sincronizza l'utente da Clerk con il database locale
 // @Summary Sync user from Clerk
 // @Description Synchronize user data from Clerk to local database
@@ -86,7 +92,7 @@ func (h *AuthHandler) SyncUser(c *gin.Context) {
 		AvatarURL: clerkImageURL.(string),
 	}
 	// Utilizza il servizio per sincronizzare l'utente
-	output := h.clerkUserService.SyncUserFromClerk(clerkSyncReq)
+	output := h.userService(c.Request.Context()).SyncUserFromClerk(clerkSyncReq)
 
 	output.Match(
 		// Exception
@@ -126,7 +132,7 @@ func (h *AuthHandler) GetCurrentUser(c *gin.Context) {
 		return
 	}
 
-	output := h.userService.GetUserByID(clerkUserID.(string))
+	output := h.userService(c.Request.Context()).GetUserByID(clerkUserID.(string))
 
 	output.Match(
 		// Exception
@@ -168,7 +174,9 @@ func (h *AuthHandler) GetCurrentUser(c *gin.Context) {
 func (h *AuthHandler) WebhookHandler(c *gin.Context) {
 	// Get typed webhook event from middleware
 	webhookID, _ := sharedMiddleware.GetWebhookIDFromContext(c)
-	log.Printf("Processing Clerk webhook %s", webhookID)
+	c.Request = c.Request.WithContext(logging.With(c.Request.Context(), slog.String("webhook_id", webhookID)))
+	logger := logging.FromContext(c.Request.Context())
+	logger.Info("Processing Clerk webhook")
 
 	event, exists := sharedMiddleware.GetWebhookEventFromContext(c)
 	if !exists {
@@ -176,28 +184,48 @@ func (h *AuthHandler) WebhookHandler(c *gin.Context) {
 		return
 	}
 
-	log.Printf("Processing webhook event type: %s", event.Type)
+	// The outcome follows the response status, unhandled event types are counted as ignored
+	ignored := false
+	defer func() {
+		outcome := metrics.WebhookProcessed
+		switch {
+		case c.Writer.Status() >= http.StatusInternalServerError:
+			outcome = metrics.WebhookFailed
+		case c.Writer.Status() >= http.StatusBadRequest:
+			outcome = metrics.WebhookInvalid
+		case ignored:
+			outcome = metrics.WebhookIgnored
+		}
+		metrics.WebhookEvents.WithLabelValues(event.Type, outcome).Inc()
+	}()
+
+	c.Request = c.Request.WithContext(logging.With(c.Request.Context(), slog.String("event_type", event.Type)))
+	logger = logging.FromContext(c.Request.Context())
+	logger.Info("Processing webhook event")
+
+	// Attribute webhook changes to Clerk rather than to an anonymous actor
+	userService := h.userService(sharedMiddleware.WithUserID(c.Request.Context(), webhookActorID))
 
 	switch event.Type {
 	case "user.created":
 		if userData, ok := sharedMiddleware.GetWebhookUserDataFromContext(c); ok {
-			response := h.clerkUserService.ProcessClerkWebhook(userData)
+			response := userService.ProcessClerkWebhook(userData)
 			response.Match(
 				func(err error) interface{} {
-					c.JSON(http.StatusInternalServerError, dto.LoggedSystemErrorResponse(err, "Error handling user.created webhook %s", webhookID))
+					c.JSON(http.StatusInternalServerError, dto.LoggedSystemErrorResponse(c.Request.Context(), err, "Error handling user.created webhook %s", webhookID))
 					return nil
 				},
 				func(syncResponse *usersDto.ClerkUserSyncDto) interface{} {
-					log.Printf("Created user %s from webhook %s", syncResponse.User.ID, webhookID)
+					logger.Info("Created user from webhook", slog.String("user_id", syncResponse.User.ID))
 					return nil
 				},
 				func(errors []dto.Error) interface{} {
-					c.JSON(http.StatusBadRequest, dto.LoggedValidationErrorResponse(errors, "Validation error handling user.created webhook %s", webhookID))
+					c.JSON(http.StatusBadRequest, dto.LoggedValidationErrorResponse(c.Request.Context(), errors, "Validation error handling user.created webhook %s", webhookID))
 					return nil
 				},
 			)
 		} else {
-			log.Printf("Missing user data for user.created webhook %s", webhookID)
+			logger.Warn("Missing user data in webhook")
 			c.JSON(http.StatusBadRequest, dto.ErrorResponse{
 				Error:   "missing_user_data",
 				Message: "Missing user data",
@@ -207,23 +235,23 @@ func (h *AuthHandler) WebhookHandler(c *gin.Context) {
 
 	case "user.updated":
 		if userData, ok := sharedMiddleware.GetWebhookUserDataFromContext(c); ok {
-			response := h.clerkUserService.ProcessClerkWebhook(userData)
+			response := userService.ProcessClerkWebhook(userData)
 			response.Match(
 				func(err error) interface{} {
-					c.JSON(http.StatusInternalServerError, dto.LoggedSystemErrorResponse(err, "Error handling user.updated webhook %s", webhookID))
+					c.JSON(http.StatusInternalServerError, dto.LoggedSystemErrorResponse(c.Request.Context(), err, "Error handling user.updated webhook %s", webhookID))
 					return nil
 				},
 				func(syncResponse *usersDto.ClerkUserSyncDto) interface{} {
-					log.Printf("Updated user %s from webhook %s", syncResponse.User.ID, webhookID)
+					logger.Info("Updated user from webhook", slog.String("user_id", syncResponse.User.ID))
 					return nil
 				},
 				func(errors []dto.Error) interface{} {
-					c.JSON(http.StatusBadRequest, dto.LoggedValidationErrorResponse(errors, "Validation error handling user.updated webhook %s", webhookID))
+					c.JSON(http.StatusBadRequest, dto.LoggedValidationErrorResponse(c.Request.Context(), errors, "Validation error handling user.updated webhook %s", webhookID))
 					return nil
 				},
 			)
 		} else {
-			log.Printf("Missing user data for user.updated webhook %s", webhookID)
+			logger.Warn("Missing user data in webhook")
 			c.JSON(http.StatusBadRequest, dto.ErrorResponse{
 				Error:   "missing_user_data",
 				Message: "Missing user data",
@@ -234,12 +262,12 @@ func (h *AuthHandler) WebhookHandler(c *gin.Context) {
 	case "user.deleted":
 		if userData, ok := sharedMiddleware.GetWebhookUserDataFromContext(c); ok {
 			// First find the user by Clerk ID to get internal ID
-			getUserDto := h.userService.GetUserByClerkID(userData.ID)
+			getUserDto := userService.GetUserByClerkID(userData.ID)
 			var userID string
 
 			getUserDto.Match(
 				func(err error) interface{} {
-					c.JSON(http.StatusInternalServerError, dto.LoggedSystemErrorResponse(err, "Error finding user for deletion webhook %s", webhookID))
+					c.JSON(http.StatusInternalServerError, dto.LoggedSystemErrorResponse(c.Request.Context(), err, "Error finding user for deletion webhook %s", webhookID))
 					return nil
 				},
 				func(user *usersDto.UserDto) interface{} {
@@ -247,31 +275,32 @@ func (h *AuthHandler) WebhookHandler(c *gin.Context) {
 					return nil
 				},
 				func(errors []dto.Error) interface{} {
-					log.Printf("User not found for deletion webhook %s: %v", webhookID, errors)
+					logger.Info("User not found for deletion webhook", slog.Any("errors", errors))
 					// User already doesn't exist, consider it successful
 					return nil
 				},
 			)
 
+			// Deleting the Clerk account deactivates the local user; purging personal data is a separate admin action
 			if userID != "" {
-				deleteResponse := h.userService.DeleteUser(userID)
-				deleteResponse.Match(
+				deactivateResponse := userService.DeactivateUser(userID)
+				deactivateResponse.Match(
 					func(err error) interface{} {
-						c.JSON(http.StatusInternalServerError, dto.LoggedSystemErrorResponse(err, "Error deleting user from webhook %s", webhookID))
+						c.JSON(http.StatusInternalServerError, dto.LoggedSystemErrorResponse(c.Request.Context(), err, "Error deactivating user from webhook %s", webhookID))
 						return nil
 					},
-					func(message string) interface{} {
-						log.Printf("Deleted user from webhook %s", webhookID)
+					func(user *usersDto.UserDto) interface{} {
+						logger.Info("Deactivated user from webhook", slog.String("user_id", user.ID))
 						return nil
 					},
 					func(errors []dto.Error) interface{} {
-						c.JSON(http.StatusBadRequest, dto.LoggedValidationErrorResponse(errors, "Validation error deleting user from webhook %s", webhookID))
+						c.JSON(http.StatusBadRequest, dto.LoggedValidationErrorResponse(c.Request.Context(), errors, "Validation error deactivating user from webhook %s", webhookID))
 						return nil
 					},
 				)
 			}
 		} else {
-			log.Printf("Missing user data for user.deleted webhook %s", webhookID)
+			logger.Warn("Missing user data in webhook")
 			c.JSON(http.StatusBadRequest, dto.ErrorResponse{
 				Error:   "missing_user_data",
 				Message: "Missing user data",
@@ -280,7 +309,8 @@ func (h *AuthHandler) WebhookHandler(c *gin.Context) {
 		}
 
 	default:
-		log.Printf("Ignoring unhandled webhook event type: %s (ID: %s)", event.Type, webhookID)
+		ignored = true
+		logger.Info("Ignoring unhandled webhook event type")
 	}
 
 	c.JSON(http.StatusOK, map[string]interface{}{
@@ -1,11 +1,12 @@
 package handlers
 
 import (
-	"log"
+	"log/slog"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
 	"thothix-backend/internal/shared/dto"
+	"thothix-backend/internal/shared/logging"
 )
 
 // ContextWrapper wraps gin.Context to provide convenience methods for standardized error responses.
@@ -20,24 +21,29 @@ func WrapContext(c *gin.Context) *ContextWrapper {
 	return &ContextWrapper{Context: c}
 }
 
+// logger returns the request logger carrying the request ID, route and user
+func (c *ContextWrapper) logger() *slog.Logger {
+	return logging.FromContext(c.Request.Context())
+}
+
 // SystemErrorResponse sends a standardized system error response with logging.
 // Use this for internal server errors, database errors, and other system-level failures.
 func (c *ContextWrapper) SystemErrorResponse(err error, logMessage string, logArgs ...interface{}) {
-	response := dto.LoggedSystemErrorResponse(err, logMessage, logArgs...)
+	response := dto.LoggedSystemErrorResponse(c.Request.Context(), err, logMessage, logArgs...)
 	c.JSON(http.StatusInternalServerError, response)
 }
 
 // ValidationErrorResponse sends a standardized validation error response with logging.
 // Use this for input validation failures, business rule violations, and data validation errors.
 func (c *ContextWrapper) ValidationErrorResponse(errors []dto.Error, logMessage string, logArgs ...interface{}) {
-	response := dto.LoggedValidationErrorResponse(errors, logMessage, logArgs...)
+	response := dto.LoggedValidationErrorResponse(c.Request.Context(), errors, logMessage, logArgs...)
 	c.JSON(http.StatusBadRequest, response)
 }
 
 // NotFoundErrorResponse sends a standardized not found error response with logging.
 // Use this when a requested resource cannot be found.
 func (c *ContextWrapper) NotFoundErrorResponse(resourceType, identifier string) {
-	log.Printf("Resource not found: %s with identifier %s", resourceType, identifier)
+	c.logger().WarnContext(c.Request.Context(), "resource not found", slog.String("resource_type", resourceType), slog.String("identifier", identifier))
 	response := dto.ErrorViewModel{
 		Success: false,
 		Error:   "not_found",
@@ -49,7 +55,7 @@ func (c *ContextWrapper) NotFoundErrorResponse(resourceType, identifier string)
 // UnauthorizedErrorResponse sends a standardized unauthorized error response.
 // Use this when authentication is required but missing or invalid.
 func (c *ContextWrapper) UnauthorizedErrorResponse(message string) {
-	log.Printf("Unauthorized access attempt: %s", message)
+	c.logger().WarnContext(c.Request.Context(), "unauthorized access attempt", slog.String("reason", message))
 	response := dto.ErrorViewModel{
 		Success: false,
 		Error:   "unauthorized",
@@ -61,7 +67,7 @@ func (c *ContextWrapper) UnauthorizedErrorResponse(message string) {
 // ForbiddenErrorResponse sends a standardized forbidden error response.
 // Use this when the user is authenticated but lacks permission for the requested operation.
 func (c *ContextWrapper) ForbiddenErrorResponse(message string) {
-	log.Printf("Forbidden access attempt: %s", message)
+	c.logger().WarnContext(c.Request.Context(), "forbidden access attempt", slog.String("reason", message))
 	response := dto.ErrorViewModel{
 		Success: false,
 		Error:   "forbidden",
@@ -73,7 +79,7 @@ func (c *ContextWrapper) ForbiddenErrorResponse(message string) {
 // BadRequestErrorResponse sends a standardized bad request error response.
 // Use this for malformed requests, missing required parameters, etc.
 func (c *ContextWrapper) BadRequestErrorResponse(message string) {
-	log.Printf("Bad request: %s", message)
+	c.logger().WarnContext(c.Request.Context(), "bad request", slog.String("reason", message))
 	response := dto.ErrorViewModel{
 		Success: false,
 		Error:   "bad_request",
@@ -85,7 +91,7 @@ func (c *ContextWrapper) BadRequestErrorResponse(message string) {
 // ConflictErrorResponse sends a standardized conflict error response.
 // Use this when a resource already exists (e.g., duplicate email, username).
 func (c *ContextWrapper) ConflictErrorResponse(message string) {
-	log.Printf("Conflict error: %s", message)
+	c.logger().WarnContext(c.Request.Context(), "conflict", slog.String("reason", message))
 	response := dto.ErrorViewModel{
 		Success: false,
 		Error:   "conflict",
@@ -112,6 +118,15 @@ func (c *ContextWrapper) CreatedResponse(data interface{}) {
 	})
 }
 
+// AcceptedResponse sends a standardized accepted response with data.
+// Use this for operations that continue in the background, returning the tracking resource.
+func (c *ContextWrapper) AcceptedResponse(data interface{}) {
+	c.JSON(http.StatusAccepted, gin.H{
+		"success": true,
+		"data":    data,
+	})
+}
+
 // NoContentResponse sends a standardized no content response.
 // Use this for successful operations that don't return data (like DELETE).
 func (c *ContextWrapper) NoContentResponse() {
@@ -4,20 +4,57 @@ import (
 	"net/http"
 
 	"github.com/gin-gonic/gin"
+
+	"thothix-backend/internal/buildinfo"
+	"thothix-backend/internal/health"
 )
 
-// HealthCheck godoc
-// @Summary Health check endpoint
-// @Description Get the health status of the API
+type HealthHandler struct {
+	checker *health.Checker
+}
+
+func NewHealthHandler(checker *health.Checker) *HealthHandler {
+	return &HealthHandler{checker: checker}
+}
+
+// Live godoc
+// @Summary Liveness probe
+// @Description Report that the process is running, without checking dependencies
 // @Tags health
-// @Accept json
 // @Produce json
-// @Success 200 {object} map[string]interface{}
-// @Router /health [get]
-func HealthCheck(c *gin.Context) {
-	c.JSON(http.StatusOK, gin.H{
-		"status":  "ok",
-		"message": "Thothix API is running",
-		"version": "1.0.0",
+// @Success 200 {object} health.Report
+// @Router /health/live [get]
+func (h *HealthHandler) Live(c *gin.Context) {
+	c.JSON(http.StatusOK, health.Report{
+		Status:  health.StatusUp,
+		Version: buildinfo.Version,
+		Commit:  buildinfo.Commit,
+		Checks:  map[string]health.CheckResult{},
 	})
 }
+
+// Ready godoc
+// @Summary Readiness probe
+// @Description Check the database, migrations, Vault and background workers, with per-check status and latency
+// @Tags health
+// @Produce json
+// @Success 200 {object} health.Report
+// @Failure 503 {object} health.Report
+// @Router /health/ready [get]
+func (h *HealthHandler) Ready(c *gin.Context) {
+	results := h.checker.Run(c.Request.Context())
+
+	report := health.Report{
+		Status:  health.StatusUp,
+		Version: buildinfo.Version,
+		Commit:  buildinfo.Commit,
+		Checks:  results,
+	}
+	status := http.StatusOK
+	if !health.Healthy(results) {
+		report.Status = health.StatusDown
+		status = http.StatusServiceUnavailable
+	}
+
+	c.JSON(status, report)
+}
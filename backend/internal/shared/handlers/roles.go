@@ -1,9 +1,12 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
 
+	sharedMiddleware "thothix-backend/internal/shared/middleware"
 	sharedModels "thothix-backend/internal/shared/models"
+	workspaceDomain "thothix-backend/internal/workspace/domain"
 
 	"github.com/gin-gonic/gin"
 	"gorm.io/gorm"
@@ -19,42 +22,88 @@ func NewRoleHandler(db *gorm.DB) *RoleHandler {
 
 // AssignUserRole godoc
 // @Summary Assign role to user
-// @Description Assign a role to a user (system, project, or channel specific)
+// @Description Set the role of a member of the current workspace. Users who are not members yet are sent an invitation with the role, which they accept through /workspaces/invitations
 // @Tags roles
 // @Accept json
 // @Produce json
 // @Security BearerAuth
 // @Param role body AssignRoleRequest true "Role assignment"
-// @Success 201 {object} sharedModels.UserRole
+// @Success 200 {object} sharedModels.UserRole
+// @Success 202 {object} workspaceDomain.Invitation
 // @Failure 400 {object} map[string]interface{}
 // @Failure 403 {object} map[string]interface{}
 // @Router /api/v1/roles [post]
 func (h *RoleHandler) AssignUserRole(c *gin.Context) {
+	db := h.db.WithContext(c.Request.Context())
+
 	var req AssignRoleRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	// Create role assignment
-	userRole := sharedModels.UserRole{
-		UserID:       req.UserID,
-		Role:         req.Role,
-		ResourceType: req.ResourceType,
-		ResourceID:   req.ResourceID,
+	if _, ok := sharedModels.RolePermissions[req.Role]; !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid role"})
+		return
+	}
+
+	workspaceID, ok := sharedMiddleware.WorkspaceIDFromContext(c.Request.Context())
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Workspace required"})
+		return
+	}
+
+	var count int64
+	db.Table("users").Where("id = ? AND deactivated_at IS NULL", req.UserID).Count(&count)
+	if count == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "User not found"})
+		return
 	}
 
-	if err := h.db.Create(&userRole).Error; err != nil {
+	// A user has a single role per workspace, so an existing membership is updated in place
+	var userRole sharedModels.UserRole
+	err := db.Where("user_id = ? AND resource_type = ? AND resource_id = ?", req.UserID, sharedModels.ResourceTypeWorkspace, workspaceID).
+		First(&userRole).Error
+	if err == nil {
+		userRole.Role = req.Role
+		if err := db.Save(&userRole).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to assign role"})
+			return
+		}
+		c.JSON(http.StatusOK, userRole)
+		return
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to assign role"})
 		return
 	}
 
-	c.JSON(http.StatusCreated, userRole)
+	// Users join a workspace only by accepting an invitation; inviting again updates the offered role
+	var invitation workspaceDomain.Invitation
+	err = db.Where("workspace_id = ? AND user_id = ?", workspaceID, req.UserID).First(&invitation).Error
+	switch {
+	case err == nil:
+		invitation.Role = req.Role
+		err = db.Save(&invitation).Error
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		invitation = workspaceDomain.Invitation{
+			WorkspaceID: workspaceID,
+			UserID:      req.UserID,
+			Role:        req.Role,
+		}
+		err = db.Create(&invitation).Error
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to invite user"})
+		return
+	}
+
+	c.JSON(http.StatusAccepted, invitation)
 }
 
 // GetUserRoles godoc
 // @Summary Get user roles
-// @Description Get all roles assigned to a user
+// @Description Get the roles assigned to a user in the current workspace
 // @Tags roles
 // @Accept json
 // @Produce json
@@ -64,10 +113,12 @@ func (h *RoleHandler) AssignUserRole(c *gin.Context) {
 // @Failure 404 {object} map[string]interface{}
 // @Router /api/v1/users/{userId}/roles [get]
 func (h *RoleHandler) GetUserRoles(c *gin.Context) {
+	db := h.db.WithContext(c.Request.Context())
+
 	userID := c.Param("userId")
 
 	var roles []sharedModels.UserRole
-	if err := h.db.Where("user_id = ?", userID).Find(&roles).Error; err != nil {
+	if err := db.Scopes(inCurrentWorkspace).Where("user_id = ?", userID).Find(&roles).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get user roles"})
 		return
 	}
@@ -77,7 +128,7 @@ func (h *RoleHandler) GetUserRoles(c *gin.Context) {
 
 // RevokeUserRole godoc
 // @Summary Revoke user role
-// @Description Revoke a specific role from a user
+// @Description Revoke a role in the current workspace, removing the user from the workspace
 // @Tags roles
 // @Accept json
 // @Produce json
@@ -87,9 +138,11 @@ func (h *RoleHandler) GetUserRoles(c *gin.Context) {
 // @Failure 404 {object} map[string]interface{}
 // @Router /api/v1/roles/{roleId} [delete]
 func (h *RoleHandler) RevokeUserRole(c *gin.Context) {
+	db := h.db.WithContext(c.Request.Context())
+
 	roleID := c.Param("roleId")
 
-	if err := h.db.Delete(&sharedModels.UserRole{}, "id = ?", roleID).Error; err != nil {
+	if err := db.Scopes(inCurrentWorkspace).Delete(&sharedModels.UserRole{}, "id = ?", roleID).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to revoke role"})
 		return
 	}
@@ -98,9 +151,17 @@ func (h *RoleHandler) RevokeUserRole(c *gin.Context) {
 }
 
 // AssignRoleRequest represents the request body for role assignment
+// The role applies to the current workspace
 type AssignRoleRequest struct {
-	Role         sharedModels.RoleType `json:"role" binding:"required"`
-	ResourceType *string               `json:"resource_type,omitempty"`
-	ResourceID   *string               `json:"resource_id,omitempty"`
-	UserID       string                `json:"user_id" binding:"required"`
+	Role   sharedModels.RoleType `json:"role" binding:"required"`
+	UserID string                `json:"user_id" binding:"required"`
+}
+
+// inCurrentWorkspace limits role queries to memberships of the workspace carried by the db context
+func inCurrentWorkspace(db *gorm.DB) *gorm.DB {
+	workspaceID, ok := sharedMiddleware.WorkspaceIDFromContext(db.Statement.Context)
+	if !ok {
+		return db
+	}
+	return db.Where("resource_type = ? AND resource_id = ?", sharedModels.ResourceTypeWorkspace, workspaceID)
 }
@@ -0,0 +1,73 @@
+package logging
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"gorm.io/gorm"
+	gormLogger "gorm.io/gorm/logger"
+)
+
+// GormLogger writes GORM logs through the request logger of the statement context,
+// so queries are correlated with the request that ran them
+type GormLogger struct {
+	level         gormLogger.LogLevel
+	slowThreshold time.Duration
+}
+
+// NewGormLogger creates a GORM logger; queries slower than slowThreshold are logged as warnings
+func NewGormLogger(level gormLogger.LogLevel, slowThreshold time.Duration) *GormLogger {
+	return &GormLogger{level: level, slowThreshold: slowThreshold}
+}
+
+// LogMode returns a copy of the logger with the given level
+func (l *GormLogger) LogMode(level gormLogger.LogLevel) gormLogger.Interface {
+	copied := *l
+	copied.level = level
+	return &copied
+}
+
+func (l *GormLogger) Info(ctx context.Context, msg string, args ...interface{}) {
+	if l.level >= gormLogger.Info {
+		FromContext(ctx).InfoContext(ctx, fmt.Sprintf(msg, args...))
+	}
+}
+
+func (l *GormLogger) Warn(ctx context.Context, msg string, args ...interface{}) {
+	if l.level >= gormLogger.Warn {
+		FromContext(ctx).WarnContext(ctx, fmt.Sprintf(msg, args...))
+	}
+}
+
+func (l *GormLogger) Error(ctx context.Context, msg string, args ...interface{}) {
+	if l.level >= gormLogger.Error {
+		FromContext(ctx).ErrorContext(ctx, fmt.Sprintf(msg, args...))
+	}
+}
+
+// Trace logs a finished query: failures as errors, slow queries as warnings and the rest at debug level
+// Record-not-found is an expected outcome and is not reported as an error
+func (l *GormLogger) Trace(ctx context.Context, begin time.Time, fc func() (string, int64), err error) {
+	if l.level <= gormLogger.Silent {
+		return
+	}
+
+	elapsed := time.Since(begin)
+	logger := FromContext(ctx)
+	attrs := func() []any {
+		sql, rows := fc()
+		return []any{slog.String("sql", sql), slog.Int64("rows", rows), slog.Duration("duration", elapsed)}
+	}
+
+	switch {
+	case err != nil && !errors.Is(err, gorm.ErrRecordNotFound) && l.level >= gormLogger.Error:
+		logger.ErrorContext(ctx, "query failed", append(attrs(), slog.Any("error", err))...)
+	case l.slowThreshold > 0 && elapsed > l.slowThreshold && l.level >= gormLogger.Warn:
+		logger.WarnContext(ctx, "slow query", attrs()...)
+	case l.level >= gormLogger.Info:
+		logger.DebugContext(ctx, "query", attrs()...)
+	}
+}
@@ -0,0 +1,69 @@
+package logging
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+)
+
+// loggerKey is the context key of the request logger
+type loggerKey struct{}
+
+// Setup installs the process-wide slog logger
+// Production writes JSON for log shipping, other environments write human-readable text.
+// The standard log package is redirected to it, so existing log.Printf calls are structured too
+func Setup(environment, level string) *slog.Logger {
+	logger := New(os.Stdout, environment, level)
+	slog.SetDefault(logger)
+	return logger
+}
+
+// New creates a logger writing to w in the format used for environment
+func New(w io.Writer, environment, level string) *slog.Logger {
+	options := &slog.HandlerOptions{Level: ParseLevel(level)}
+
+	var handler slog.Handler
+	if environment == "production" {
+		handler = slog.NewJSONHandler(w, options)
+	} else {
+		handler = slog.NewTextHandler(w, options)
+	}
+	return slog.New(handler)
+}
+
+// ParseLevel converts a level name (debug, info, warn, error) to a slog level, defaulting to info
+func ParseLevel(level string) slog.Level {
+	switch strings.ToLower(strings.TrimSpace(level)) {
+	case "debug":
+		return slog.LevelDebug
+	case "warn", "warning":
+		return slog.LevelWarn
+	case "error":
+		return slog.LevelError
+	default:
+		return slog.LevelInfo
+	}
+}
+
+// WithLogger returns a copy of ctx carrying logger
+func WithLogger(ctx context.Context, logger *slog.Logger) context.Context {
+	return context.WithValue(ctx, loggerKey{}, logger)
+}
+
+// FromContext returns the request logger stored in ctx, or the default logger
+// The request logger carries the request ID, route and Clerk user ID of the current request
+func FromContext(ctx context.Context) *slog.Logger {
+	if ctx != nil {
+		if logger, ok := ctx.Value(loggerKey{}).(*slog.Logger); ok {
+			return logger
+		}
+	}
+	return slog.Default()
+}
+
+// With returns a copy of ctx whose logger has the given attributes added
+func With(ctx context.Context, args ...any) context.Context {
+	return WithLogger(ctx, FromContext(ctx).With(args...))
+}
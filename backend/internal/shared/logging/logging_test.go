@@ -0,0 +1,69 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+	gormLogger "gorm.io/gorm/logger"
+)
+
+func TestNew_JSONInProduction(t *testing.T) {
+	var buf bytes.Buffer
+	logger := New(&buf, "production", "info")
+
+	logger.Info("hello", slog.String("request_id", "req-1"))
+
+	var entry map[string]interface{}
+	assert.NoError(t, json.Unmarshal(buf.Bytes(), &entry))
+	assert.Equal(t, "hello", entry["msg"])
+	assert.Equal(t, "req-1", entry["request_id"])
+}
+
+func TestNew_TextInDevelopment(t *testing.T) {
+	var buf bytes.Buffer
+	logger := New(&buf, "development", "debug")
+
+	logger.Debug("hello", slog.String("route", "/api/v1/users"))
+
+	assert.Contains(t, buf.String(), "msg=hello")
+	assert.Contains(t, buf.String(), "route=/api/v1/users")
+}
+
+func TestFromContext_CarriesAttributes(t *testing.T) {
+	var buf bytes.Buffer
+	ctx := WithLogger(context.Background(), New(&buf, "production", "info"))
+	ctx = With(ctx, slog.String("user_id", "user-1"))
+
+	FromContext(ctx).Info("handled")
+
+	assert.Contains(t, buf.String(), `"user_id":"user-1"`)
+	assert.Same(t, slog.Default(), FromContext(context.Background()))
+}
+
+func TestGormLogger_Trace(t *testing.T) {
+	var buf bytes.Buffer
+	ctx := WithLogger(context.Background(), New(&buf, "production", "debug"))
+	logger := NewGormLogger(gormLogger.Warn, 100*time.Millisecond)
+	query := func() (string, int64) { return "SELECT 1", 1 }
+
+	logger.Trace(ctx, time.Now(), query, gorm.ErrRecordNotFound)
+	assert.Empty(t, buf.String(), "record not found is not an error")
+
+	logger.Trace(ctx, time.Now(), query, errors.New("connection refused"))
+	assert.Contains(t, buf.String(), `"msg":"query failed"`)
+
+	buf.Reset()
+	logger.Trace(ctx, time.Now().Add(-time.Second), query, nil)
+	assert.Contains(t, buf.String(), `"msg":"slow query"`)
+
+	buf.Reset()
+	logger.Trace(ctx, time.Now(), query, nil)
+	assert.Empty(t, buf.String(), "fast queries are only logged at info level")
+}
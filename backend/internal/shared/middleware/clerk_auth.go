@@ -15,6 +15,11 @@ import (
 	clerkhttp "github.com/clerk/clerk-sdk-go/v2/http"
 	"github.com/clerk/clerk-sdk-go/v2/user"
 	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	"thothix-backend/internal/metrics"
+	"thothix-backend/internal/tracing"
 )
 
 // ClerkAuthSDK middleware using official Clerk SDK middleware
@@ -38,8 +43,13 @@ func ClerkAuthSDK(clerkSecretKey string) gin.HandlerFunc {
 			}
 
 			// Get user details from Clerk API for additional user information
-			ctx := r.Context()
+			ctx, span := tracing.Start(r.Context(), "clerk.user.get", trace.WithSpanKind(trace.SpanKindClient))
 			userDetails, err := user.Get(ctx, claims.Subject)
+			if err != nil {
+				span.RecordError(err)
+				span.SetStatus(codes.Error, err.Error())
+			}
+			span.End()
 			if err != nil {
 				// Log the error but continue with claims data only
 				// This makes the middleware more resilient to Clerk API issues
@@ -90,6 +100,11 @@ func ClerkAuthSDK(clerkSecretKey string) gin.HandlerFunc {
 				}
 			}
 
+			// The active Clerk organization selects the workspace when no header is sent
+			if claims.ActiveOrganizationID != "" {
+				c.Set("clerk_org_id", claims.ActiveOrganizationID)
+			}
+
 			c.Next()
 		})
 
@@ -161,7 +176,7 @@ func ClerkWebhookHandler(webhookSecret string) gin.HandlerFunc {
 		id := c.GetHeader("svix-id")
 
 		if timestamp == "" || signature == "" {
-			c.JSON(http.StatusBadRequest, gin.H{
+			rejectWebhook(c, http.StatusBadRequest, gin.H{
 				"error": "Missing required webhook headers (svix-timestamp, svix-signature)",
 			})
 			return
@@ -170,7 +185,7 @@ func ClerkWebhookHandler(webhookSecret string) gin.HandlerFunc {
 		// Read request body
 		body, err := c.GetRawData()
 		if err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{
+			rejectWebhook(c, http.StatusBadRequest, gin.H{
 				"error":   "Failed to read request body",
 				"details": err.Error(),
 			})
@@ -179,7 +194,7 @@ func ClerkWebhookHandler(webhookSecret string) gin.HandlerFunc {
 
 		// Verify webhook signature using Svix algorithm
 		if !verifyWebhookSignature(body, signature, timestamp, webhookSecret) {
-			c.JSON(http.StatusUnauthorized, gin.H{
+			rejectWebhook(c, http.StatusUnauthorized, gin.H{
 				"error": "Invalid webhook signature",
 			})
 			return
@@ -188,7 +203,7 @@ func ClerkWebhookHandler(webhookSecret string) gin.HandlerFunc {
 		// Parse webhook event
 		var event WebhookEvent
 		if err := json.Unmarshal(body, &event); err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{
+			rejectWebhook(c, http.StatusBadRequest, gin.H{
 				"error":   "Invalid webhook payload",
 				"details": err.Error(),
 			})
@@ -198,7 +213,7 @@ func ClerkWebhookHandler(webhookSecret string) gin.HandlerFunc {
 		// Validate event timestamp (not older than 5 minutes)
 		now := time.Now().Unix()
 		if now-event.Timestamp > 300 {
-			c.JSON(http.StatusBadRequest, gin.H{
+			rejectWebhook(c, http.StatusBadRequest, gin.H{
 				"error": "Webhook event too old",
 			})
 			return
@@ -220,6 +235,12 @@ func ClerkWebhookHandler(webhookSecret string) gin.HandlerFunc {
 	}
 }
 
+// rejectWebhook stops a webhook that failed verification and counts the rejection
+func rejectWebhook(c *gin.Context, status int, body gin.H) {
+	metrics.WebhookEvents.WithLabelValues("unknown", metrics.WebhookRejected).Inc()
+	c.AbortWithStatusJSON(status, body)
+}
+
 // verifyWebhookSignature implements Svix signature verification algorithm
 func verifyWebhookSignature(payload []byte, signature, timestamp, secret string) bool {
 	// Parse timestamp
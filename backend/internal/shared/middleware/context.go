@@ -2,24 +2,99 @@ package middleware
 
 import (
 	"context"
+	"log/slog"
 
 	"github.com/gin-gonic/gin"
+
+	"thothix-backend/internal/shared/logging"
 )
 
 // contextKey is a type for context keys to avoid collisions
 type contextKey string
 
-const userIDKey contextKey = "user_id"
+const (
+	userIDKey    contextKey = "user_id"
+	clientIPKey  contextKey = "client_ip"
+	requestIDKey contextKey = "request_id"
+	workspaceKey contextKey = "workspace_id"
+)
+
+// RequestIDHeader is the header used to correlate a request across services and logs
+const RequestIDHeader = "X-Request-ID"
 
-// SetUserContext sets the user ID in the request context
+// WorkspaceHeader selects the current workspace; without it the active Clerk organization is used
+const WorkspaceHeader = "X-Workspace-ID"
+
+// SetUserContext sets the user ID, client IP and request ID in the request context
+// Handlers pass the request context to GORM so the audit callbacks can read these values
 func SetUserContext() gin.HandlerFunc {
 	return func(c *gin.Context) {
+		ctx := c.Request.Context()
+
 		// Get user ID from Clerk middleware
 		if userID, exists := c.Get("clerk_user_id"); exists {
-			// Set user ID in context for GORM hooks
-			ctx := context.WithValue(c.Request.Context(), userIDKey, userID)
-			c.Request = c.Request.WithContext(ctx)
+			if id, ok := userID.(string); ok {
+				ctx = context.WithValue(ctx, userIDKey, id)
+				ctx = logging.With(ctx, slog.String("user_id", id))
+			}
+		}
+
+		ctx = context.WithValue(ctx, clientIPKey, c.ClientIP())
+		if RequestIDFromContext(ctx) == "" {
+			if requestID := c.GetHeader(RequestIDHeader); requestID != "" {
+				ctx = context.WithValue(ctx, requestIDKey, requestID)
+			}
 		}
+
+		c.Request = c.Request.WithContext(ctx)
 		c.Next()
 	}
 }
+
+// WithUserID returns a copy of ctx carrying the given user ID
+// Use this for background work that acts on behalf of a user or a system actor
+func WithUserID(ctx context.Context, userID string) context.Context {
+	return context.WithValue(ctx, userIDKey, userID)
+}
+
+// UserIDFromContext extracts the user ID set by SetUserContext
+func UserIDFromContext(ctx context.Context) (string, bool) {
+	if ctx == nil {
+		return "", false
+	}
+	userID, ok := ctx.Value(userIDKey).(string)
+	return userID, ok && userID != ""
+}
+
+// ClientIPFromContext extracts the client IP set by SetUserContext
+func ClientIPFromContext(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	ip, _ := ctx.Value(clientIPKey).(string)
+	return ip
+}
+
+// RequestIDFromContext extracts the request ID set by RequestID or SetUserContext
+func RequestIDFromContext(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	requestID, _ := ctx.Value(requestIDKey).(string)
+	return requestID
+}
+
+// WithWorkspaceID returns a copy of ctx scoped to the given workspace
+// Queries and permission checks run with this context only see data of that workspace
+func WithWorkspaceID(ctx context.Context, workspaceID string) context.Context {
+	return context.WithValue(ctx, workspaceKey, workspaceID)
+}
+
+// WorkspaceIDFromContext extracts the current workspace set by the workspace middleware
+func WorkspaceIDFromContext(ctx context.Context) (string, bool) {
+	if ctx == nil {
+		return "", false
+	}
+	workspaceID, ok := ctx.Value(workspaceKey).(string)
+	return workspaceID, ok && workspaceID != ""
+}
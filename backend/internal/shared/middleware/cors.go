@@ -1,41 +1,148 @@
 package middleware
 
 import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
 	"github.com/gin-gonic/gin"
 )
 
-func CORS() gin.HandlerFunc {
-	return gin.HandlerFunc(func(c *gin.Context) {
-		origin := c.Request.Header.Get("Origin")
+// corsAllowedMethods and corsAllowedHeaders are answered to every allowed preflight
+var (
+	corsAllowedMethods = []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"}
+	corsAllowedHeaders = []string{"Accept", "Authorization", "Content-Type", "X-CSRF-Token", RequestIDHeader, WorkspaceHeader}
+)
+
+// CORSPolicy decides which browser origins may call the API
+type CORSPolicy struct {
+	AllowedOrigins   []string      // Exact origins, wildcard subdomains such as "https://*.thothix.io", or "*"
+	AllowCredentials bool          // Sent only to listed origins, never to those admitted by "*"
+	ExposedHeaders   []string      // Response headers readable by the client
+	MaxAge           time.Duration // Preflight cache duration, zero to leave it to the browser
+}
 
-		allowedOrigins := []string{
-			"http://localhost:30001",
-			"https://thothix.io",
-			"https://thothix.com",
+// CORS answers preflight requests and adds the CORS headers for allowed origins
+// Requests from other origins get no CORS headers, so browsers block their responses
+func CORS(policy CORSPolicy) gin.HandlerFunc {
+	origins := newOriginMatcher(policy.AllowedOrigins)
+	allowMethods := strings.Join(corsAllowedMethods, ", ")
+	allowHeaders := strings.Join(corsAllowedHeaders, ", ")
+	exposeHeaders := strings.Join(policy.ExposedHeaders, ", ")
+	maxAge := strconv.Itoa(int(policy.MaxAge.Seconds()))
+
+	return func(c *gin.Context) {
+		origin := c.GetHeader("Origin")
+		preflight := c.Request.Method == http.MethodOptions && c.GetHeader("Access-Control-Request-Method") != ""
+
+		// The response depends on the origin, so shared caches must key on it
+		header := c.Writer.Header()
+		header.Add("Vary", "Origin")
+		if preflight {
+			header.Add("Vary", "Access-Control-Request-Method")
+			header.Add("Vary", "Access-Control-Request-Headers")
 		}
 
-		// Verifica se l'origin è permesso
-		isAllowed := false
-		for _, allowed := range allowedOrigins {
-			if origin == allowed {
-				isAllowed = true
-				break
+		if origin == "" {
+			c.Next()
+			return
+		}
+		listed := origins.match(origin)
+		if !listed && !origins.any {
+			if preflight {
+				c.AbortWithStatus(http.StatusForbidden)
+				return
 			}
+			c.Next()
+			return
 		}
 
-		if isAllowed {
-			c.Writer.Header().Set("Access-Control-Allow-Origin", origin)
+		if listed {
+			header.Set("Access-Control-Allow-Origin", origin)
+			if policy.AllowCredentials {
+				header.Set("Access-Control-Allow-Credentials", "true")
+			}
+		} else {
+			// Origins admitted by "*" get the wildcard itself, which browsers never combine with credentials
+			header.Set("Access-Control-Allow-Origin", "*")
 		}
 
-		c.Writer.Header().Set("Access-Control-Allow-Credentials", "true")
-		c.Writer.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-		c.Writer.Header().Set("Access-Control-Allow-Headers", "Accept, Authorization, Content-Type, X-CSRF-Token")
-
-		if c.Request.Method == "OPTIONS" {
-			c.AbortWithStatus(204)
+		if preflight {
+			header.Set("Access-Control-Allow-Methods", allowMethods)
+			header.Set("Access-Control-Allow-Headers", allowHeaders)
+			if policy.MaxAge > 0 {
+				header.Set("Access-Control-Max-Age", maxAge)
+			}
+			c.AbortWithStatus(http.StatusNoContent)
 			return
 		}
 
+		if exposeHeaders != "" {
+			header.Set("Access-Control-Expose-Headers", exposeHeaders)
+		}
 		c.Next()
-	})
+	}
+}
+
+// originMatcher matches origins against exact origins and "scheme://*.domain[:port]" patterns
+// A "*" pattern is kept apart in any, since it admits origins without listing them
+type originMatcher struct {
+	any       bool
+	exact     map[string]bool
+	wildcards []wildcardOrigin
+}
+
+// wildcardOrigin matches any subdomain: "https://*.thothix.io" becomes prefix "https://" and suffix ".thothix.io"
+type wildcardOrigin struct {
+	prefix string
+	suffix string
+}
+
+func newOriginMatcher(patterns []string) *originMatcher {
+	m := &originMatcher{exact: make(map[string]bool)}
+	for _, pattern := range patterns {
+		pattern = strings.ToLower(strings.TrimSuffix(pattern, "/"))
+		switch {
+		case pattern == "*":
+			m.any = true
+		case strings.Contains(pattern, "://*."):
+			scheme, host, _ := strings.Cut(pattern, "://*")
+			m.wildcards = append(m.wildcards, wildcardOrigin{prefix: scheme + "://", suffix: host})
+		default:
+			m.exact[pattern] = true
+		}
+	}
+	return m
+}
+
+// match reports whether origin is listed by an exact or wildcard subdomain pattern
+func (m *originMatcher) match(origin string) bool {
+	origin = strings.ToLower(origin)
+	if m.exact[origin] {
+		return true
+	}
+	for _, wildcard := range m.wildcards {
+		if !strings.HasPrefix(origin, wildcard.prefix) || !strings.HasSuffix(origin, wildcard.suffix) {
+			continue
+		}
+		subdomain := origin[len(wildcard.prefix) : len(origin)-len(wildcard.suffix)]
+		if isSubdomain(subdomain) {
+			return true
+		}
+	}
+	return false
+}
+
+// isSubdomain reports whether s is one or more DNS labels, so a pattern never matches across a port or path
+func isSubdomain(s string) bool {
+	if s == "" || strings.HasPrefix(s, ".") || strings.HasPrefix(s, "-") || strings.HasSuffix(s, ".") || strings.Contains(s, "..") {
+		return false
+	}
+	for _, r := range s {
+		if !(r >= 'a' && r <= 'z' || r >= '0' && r <= '9' || r == '-' || r == '.') {
+			return false
+		}
+	}
+	return true
 }
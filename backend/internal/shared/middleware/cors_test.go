@@ -0,0 +1,117 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+var testCORSPolicy = CORSPolicy{
+	AllowedOrigins:   []string{"https://thothix.io", "https://*.thothix.dev"},
+	AllowCredentials: true,
+	ExposedHeaders:   []string{RequestIDHeader, "X-RateLimit-Remaining"},
+	MaxAge:           10 * time.Minute,
+}
+
+func serveCORS(policy CORSPolicy, method, origin string, preflight bool) *httptest.ResponseRecorder {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(CORS(policy))
+	router.PATCH("/messages", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	req, _ := http.NewRequest(method, "/messages", nil)
+	if origin != "" {
+		req.Header.Set("Origin", origin)
+	}
+	if preflight {
+		req.Header.Set("Access-Control-Request-Method", "PATCH")
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestCORS_AllowedPreflight(t *testing.T) {
+	w := serveCORS(testCORSPolicy, http.MethodOptions, "https://thothix.io", true)
+
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	assert.Equal(t, "https://thothix.io", w.Header().Get("Access-Control-Allow-Origin"))
+	assert.Equal(t, "true", w.Header().Get("Access-Control-Allow-Credentials"))
+	assert.Contains(t, w.Header().Get("Access-Control-Allow-Methods"), "PATCH")
+	assert.Contains(t, w.Header().Get("Access-Control-Allow-Headers"), WorkspaceHeader)
+	assert.Equal(t, "600", w.Header().Get("Access-Control-Max-Age"))
+	assert.Contains(t, w.Header().Values("Vary"), "Origin")
+	assert.Contains(t, w.Header().Values("Vary"), "Access-Control-Request-Method")
+}
+
+func TestCORS_ActualRequestExposesHeaders(t *testing.T) {
+	w := serveCORS(testCORSPolicy, http.MethodPatch, "https://thothix.io", false)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "https://thothix.io", w.Header().Get("Access-Control-Allow-Origin"))
+	assert.Equal(t, "X-Request-ID, X-RateLimit-Remaining", w.Header().Get("Access-Control-Expose-Headers"))
+	assert.Empty(t, w.Header().Get("Access-Control-Allow-Methods"))
+}
+
+func TestCORS_DisallowedOriginGetsNoCredentials(t *testing.T) {
+	w := serveCORS(testCORSPolicy, http.MethodPatch, "https://evil.example", false)
+	preflight := serveCORS(testCORSPolicy, http.MethodOptions, "https://evil.example", true)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Empty(t, w.Header().Get("Access-Control-Allow-Origin"))
+	assert.Empty(t, w.Header().Get("Access-Control-Allow-Credentials"))
+	assert.Equal(t, []string{"Origin"}, w.Header().Values("Vary"))
+	assert.Equal(t, http.StatusForbidden, preflight.Code)
+}
+
+func TestCORS_WildcardSubdomains(t *testing.T) {
+	cases := map[string]bool{
+		"https://app.thothix.dev":           true,
+		"https://pr-12.preview.thothix.dev": true,
+		"https://APP.thothix.dev":           true,
+		"https://thothix.dev":               false,
+		"http://app.thothix.dev":            false,
+		"https://evil.com/.thothix.dev":     false,
+		"https://app.thothix.dev:8443":      false,
+		"https://evilthothix.dev":           false,
+	}
+
+	for origin, allowed := range cases {
+		w := serveCORS(testCORSPolicy, http.MethodPatch, origin, false)
+
+		if allowed {
+			assert.Equal(t, origin, w.Header().Get("Access-Control-Allow-Origin"), origin)
+		} else {
+			assert.Empty(t, w.Header().Get("Access-Control-Allow-Origin"), origin)
+		}
+	}
+}
+
+func TestCORS_CredentialsDisabled(t *testing.T) {
+	policy := CORSPolicy{AllowedOrigins: []string{"*"}}
+
+	w := serveCORS(policy, http.MethodPatch, "https://anywhere.example", false)
+
+	assert.Equal(t, "*", w.Header().Get("Access-Control-Allow-Origin"))
+	assert.Empty(t, w.Header().Get("Access-Control-Allow-Credentials"))
+}
+
+func TestCORS_AnyOriginNeverGetsCredentials(t *testing.T) {
+	policy := CORSPolicy{AllowedOrigins: []string{"https://thothix.io", "*"}, AllowCredentials: true}
+
+	listed := serveCORS(policy, http.MethodPatch, "https://thothix.io", false)
+	other := serveCORS(policy, http.MethodPatch, "https://anywhere.example", false)
+	preflight := serveCORS(policy, http.MethodOptions, "https://anywhere.example", true)
+
+	assert.Equal(t, "https://thothix.io", listed.Header().Get("Access-Control-Allow-Origin"))
+	assert.Equal(t, "true", listed.Header().Get("Access-Control-Allow-Credentials"))
+	assert.Equal(t, "*", other.Header().Get("Access-Control-Allow-Origin"))
+	assert.Empty(t, other.Header().Get("Access-Control-Allow-Credentials"))
+	assert.Equal(t, http.StatusNoContent, preflight.Code)
+	assert.Equal(t, "*", preflight.Header().Get("Access-Control-Allow-Origin"))
+	assert.Empty(t, preflight.Header().Get("Access-Control-Allow-Credentials"))
+}
@@ -1,26 +1,62 @@
 package middleware
 
 import (
-	"fmt"
+	"log/slog"
 	"time"
 
 	"github.com/gin-gonic/gin"
+
+	"thothix-backend/internal/shared/logging"
+	"thothix-backend/internal/tracing"
 )
 
+// Logger middleware stores a request logger in the context and logs each completed request
+// The logger carries the request ID, route and trace ID; SetUserContext adds the Clerk user ID once authenticated.
+// Use it after RequestID and the tracing middleware
 func Logger() gin.HandlerFunc {
-	return gin.LoggerWithFormatter(func(param gin.LogFormatterParams) string {
-		return fmt.Sprintf("%s - [%s] %q %s %s %d %s %q %s\"\n",
-			param.ClientIP,
-			param.TimeStamp.Format(time.RFC1123),
-			param.Method,
-			param.Path,
-			param.Request.Proto,
-			param.StatusCode,
-			param.Latency,
-			param.Request.UserAgent(),
-			param.ErrorMessage,
-		)
-	})
+	return func(c *gin.Context) {
+		start := time.Now()
+
+		route := c.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+		attrs := []any{
+			slog.String("request_id", RequestIDFromContext(c.Request.Context())),
+			slog.String("route", route),
+		}
+		if traceID := tracing.TraceID(c.Request.Context()); traceID != "" {
+			attrs = append(attrs, slog.String("trace_id", traceID))
+		}
+		ctx := logging.With(c.Request.Context(), attrs...)
+		c.Request = c.Request.WithContext(ctx)
+
+		c.Next()
+
+		// Handlers may have replaced the request context, so read the logger back from it
+		ctx = c.Request.Context()
+		attrs = []any{
+			slog.String("method", c.Request.Method),
+			slog.String("path", c.Request.URL.Path),
+			slog.Int("status", c.Writer.Status()),
+			slog.Duration("latency", time.Since(start)),
+			slog.String("client_ip", c.ClientIP()),
+			slog.String("user_agent", c.Request.UserAgent()),
+		}
+		if errors := c.Errors.ByType(gin.ErrorTypePrivate).String(); errors != "" {
+			attrs = append(attrs, slog.String("errors", errors))
+		}
+
+		logger := logging.FromContext(ctx)
+		switch status := c.Writer.Status(); {
+		case status >= 500:
+			logger.ErrorContext(ctx, "request completed", attrs...)
+		case status >= 400:
+			logger.WarnContext(ctx, "request completed", attrs...)
+		default:
+			logger.InfoContext(ctx, "request completed", attrs...)
+		}
+	}
 }
 
 func Recovery() gin.HandlerFunc {
@@ -0,0 +1,45 @@
+package middleware
+
+import (
+	"context"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// maxRequestIDLength bounds client supplied request IDs so they cannot bloat logs
+const maxRequestIDLength = 128
+
+// RequestID middleware accepts the caller's X-Request-ID or generates one
+// The ID is echoed in the response and stored in the request context for logs and the audit trail
+func RequestID() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		requestID := c.GetHeader(RequestIDHeader)
+		if !isValidRequestID(requestID) {
+			requestID = uuid.New().String()
+			c.Request.Header.Set(RequestIDHeader, requestID)
+		}
+
+		c.Header(RequestIDHeader, requestID)
+		c.Request = c.Request.WithContext(WithRequestID(c.Request.Context(), requestID))
+		c.Next()
+	}
+}
+
+// WithRequestID returns a copy of ctx carrying the given request ID
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDKey, requestID)
+}
+
+// isValidRequestID accepts non-empty printable ASCII IDs of bounded length
+func isValidRequestID(requestID string) bool {
+	if requestID == "" || len(requestID) > maxRequestIDLength {
+		return false
+	}
+	for _, r := range requestID {
+		if r < 0x21 || r > 0x7e {
+			return false
+		}
+	}
+	return true
+}
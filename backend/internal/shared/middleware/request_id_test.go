@@ -0,0 +1,48 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func serveWithRequestID(requestID string) (*httptest.ResponseRecorder, string) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(RequestID())
+
+	var seen string
+	router.GET("/", func(c *gin.Context) {
+		seen = RequestIDFromContext(c.Request.Context())
+		c.Status(http.StatusOK)
+	})
+
+	req, _ := http.NewRequest("GET", "/", nil)
+	if requestID != "" {
+		req.Header.Set(RequestIDHeader, requestID)
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w, seen
+}
+
+func TestRequestID_AcceptsClientID(t *testing.T) {
+	w, seen := serveWithRequestID("client-request-1")
+
+	assert.Equal(t, "client-request-1", seen)
+	assert.Equal(t, "client-request-1", w.Header().Get(RequestIDHeader))
+}
+
+func TestRequestID_GeneratesWhenMissingOrInvalid(t *testing.T) {
+	for _, requestID := range []string{"", "has spaces", strings.Repeat("x", maxRequestIDLength+1)} {
+		w, seen := serveWithRequestID(requestID)
+
+		assert.NotEmpty(t, seen)
+		assert.NotEqual(t, requestID, seen)
+		assert.Equal(t, seen, w.Header().Get(RequestIDHeader))
+	}
+}
@@ -1,11 +1,18 @@
 package models
 
 import (
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 	"gorm.io/gorm"
 
 	commonModels "thothix-backend/internal/common/models"
+	sharedMiddleware "thothix-backend/internal/shared/middleware"
+	"thothix-backend/internal/tracing"
 )
 
+// ResourceTypeWorkspace marks a UserRole as a workspace membership carrying the user's role in that workspace
+const ResourceTypeWorkspace = "workspace"
+
 // RoleType defines the available role types
 type RoleType string
 
@@ -120,23 +127,94 @@ type ChannelProvider interface {
 	GetIsPrivate() bool
 }
 
-// GetUserRole gets the user's system role from database
-// This is a temporary implementation that uses the old models
-// Will be updated once all models are migrated to domains
+// GetUserRole gets the user's role from database
+// When db carries a workspace in its context the role is the user's role in that workspace,
+// and users who are not members of it have no role at all.
+// Without a workspace the platform-level system role is used.
+// Deactivated users are treated as missing, so they lose every permission and their memberships are frozen
 func GetUserRole(db *gorm.DB, userID string) (RoleType, error) {
-	// This will be updated to use usersDomain.User once migration is complete
+	systemRole, err := GetSystemRole(db, userID)
+	if err != nil {
+		return systemRole, err
+	}
+
+	workspaceID, ok := sharedMiddleware.WorkspaceIDFromContext(db.Statement.Context)
+	if !ok {
+		return systemRole, nil
+	}
+	return GetWorkspaceRole(db, userID, workspaceID)
+}
+
+// GetSystemRole gets the user's platform-level system role, whatever workspace db carries in its context
+// Deactivated users are treated as missing
+func GetSystemRole(db *gorm.DB, userID string) (RoleType, error) {
 	var result struct {
 		SystemRole RoleType `json:"system_role"`
 	}
 
-	if err := db.Table("users").Select("system_role").Where("id = ?", userID).First(&result).Error; err != nil {
+	if err := db.Table("users").Select("system_role").Where("id = ? AND deactivated_at IS NULL", userID).First(&result).Error; err != nil {
 		return RoleUser, err // Default to user role on error
 	}
 	return result.SystemRole, nil
 }
 
+// GetWorkspaceRole gets the user's role in a workspace
+// Returns gorm.ErrRecordNotFound when the user is not a member of the workspace
+func GetWorkspaceRole(db *gorm.DB, userID, workspaceID string) (RoleType, error) {
+	var membership UserRole
+	if err := db.Where("user_id = ? AND resource_type = ? AND resource_id = ?", userID, ResourceTypeWorkspace, workspaceID).
+		First(&membership).Error; err != nil {
+		return RoleUser, err
+	}
+	return membership.Role, nil
+}
+
+// InWorkspace scopes a query to the workspace carried by the db context
+// Queries without a workspace are left unfiltered
+func InWorkspace(table string) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		workspaceID, ok := sharedMiddleware.WorkspaceIDFromContext(db.Statement.Context)
+		if !ok {
+			return db
+		}
+		return db.Where(table+".workspace_id = ?", workspaceID)
+	}
+}
+
+// WorkspaceMembers scopes a users query to the members of the workspace carried by the db context
+// Queries without a workspace are left unfiltered
+func WorkspaceMembers(db *gorm.DB) *gorm.DB {
+	workspaceID, ok := sharedMiddleware.WorkspaceIDFromContext(db.Statement.Context)
+	if !ok {
+		return db
+	}
+	return db.Where("users.id::text IN (?)", db.Session(&gorm.Session{NewDB: true}).Table("user_roles").
+		Select("user_id").
+		Where("resource_type = ? AND resource_id = ?", ResourceTypeWorkspace, workspaceID))
+}
+
 // HasUserPermission checks if a user has a specific permission
+// Each check is traced as a span parenting the role and access queries it runs
 func HasUserPermission(db *gorm.DB, userID string, permission Permission, resourceType, resourceID *string) bool {
+	ctx, span := tracing.Start(db.Statement.Context, "rbac.HasUserPermission", trace.WithAttributes(
+		attribute.String("rbac.user_id", userID),
+		attribute.String("rbac.permission", string(permission)),
+	))
+	defer span.End()
+	if resourceType != nil && resourceID != nil {
+		span.SetAttributes(
+			attribute.String("rbac.resource_type", *resourceType),
+			attribute.String("rbac.resource_id", *resourceID),
+		)
+	}
+
+	allowed := hasUserPermission(db.WithContext(ctx), userID, permission, resourceType, resourceID)
+	span.SetAttributes(attribute.Bool("rbac.allowed", allowed))
+	return allowed
+}
+
+// hasUserPermission resolves the user's role and checks resource constraints
+func hasUserPermission(db *gorm.DB, userID string, permission Permission, resourceType, resourceID *string) bool {
 	// Get user's system role
 	userRole, err := GetUserRole(db, userID)
 	if err != nil {
@@ -166,7 +244,7 @@ func hasChannelAccess(db *gorm.DB, userID, channelID string, permission Permissi
 	var channel struct {
 		ProjectID string `json:"project_id"`
 	}
-	if err := db.Table("channels").Select("project_id").Where("id = ?", channelID).First(&channel).Error; err != nil {
+	if err := db.Table("channels").Select("project_id").Scopes(InWorkspace("channels")).Where("id = ?", channelID).First(&channel).Error; err != nil {
 		return false
 	}
 
@@ -201,6 +279,12 @@ func hasChannelAccess(db *gorm.DB, userID, channelID string, permission Permissi
 
 // hasProjectAccess checks if user has access to a specific project
 func hasProjectAccess(db *gorm.DB, userID, projectID string, permission Permission) bool {
+	// Projects of other workspaces are invisible
+	var count int64
+	if err := db.Table("projects").Scopes(InWorkspace("projects")).Where("id = ?", projectID).Count(&count).Error; err != nil || count == 0 {
+		return false
+	}
+
 	userRole, err := GetUserRole(db, userID)
 	if err != nil {
 		return false
@@ -212,7 +296,6 @@ func hasProjectAccess(db *gorm.DB, userID, projectID string, permission Permissi
 	}
 
 	// For regular users and external users, check if they are project members
-	var count int64
 	db.Table("project_members").Where("project_id = ? AND user_id = ?", projectID, userID).Count(&count)
 	return count > 0
 }
@@ -227,8 +310,8 @@ func HasUserPermissionSimple(userID string, permission Permission) bool {
 // swagger:model UserRole
 type UserRole struct {
 	commonModels.BaseModel
-	ResourceID   *string  `json:"resource_id,omitempty"`   // For project/channel specific roles (not used in simplified system)
-	ResourceType *string  `json:"resource_type,omitempty"` // "project", "channel", null for system roles (not used in simplified system)
+	ResourceID   *string  `json:"resource_id,omitempty"`   // Workspace ID for workspace memberships
+	ResourceType *string  `json:"resource_type,omitempty"` // "workspace" for workspace memberships, null for legacy system roles
 	UserID       string   `json:"user_id"`
 	Role         RoleType `json:"role"`
 }
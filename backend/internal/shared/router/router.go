@@ -1,47 +1,96 @@
 package router
 
 import (
+	"log/slog"
+	"os"
+
+	auditHandlers "thothix-backend/internal/audit/handlers"
+	blockingHandlers "thothix-backend/internal/blocking/handlers"
+	bookmarkHandlers "thothix-backend/internal/bookmarks/handlers"
 	chatHandlers "thothix-backend/internal/chat/handlers"
 	"thothix-backend/internal/config"
+	gdprHandlers "thothix-backend/internal/gdpr/handlers"
+	"thothix-backend/internal/health"
+	jobHandlers "thothix-backend/internal/jobs/handlers"
 	messageHandlers "thothix-backend/internal/message/handlers"
+	"thothix-backend/internal/metrics"
 	"thothix-backend/internal/middleware"
+	moderationHandlers "thothix-backend/internal/moderation/handlers"
+	presenceHandlers "thothix-backend/internal/presence/handlers"
+	presence "thothix-backend/internal/presence/store"
 	projectHandlers "thothix-backend/internal/project/handlers"
+	"thothix-backend/internal/ratelimit"
+	retentionHandlers "thothix-backend/internal/retention/handlers"
+	schedulingHandlers "thothix-backend/internal/scheduling/handlers"
 	sharedHandlers "thothix-backend/internal/shared/handlers"
 	sharedMiddleware "thothix-backend/internal/shared/middleware"
 	sharedModels "thothix-backend/internal/shared/models"
+	"thothix-backend/internal/tracing"
 	userHandlers "thothix-backend/internal/users/handlers"
+	workspaceHandlers "thothix-backend/internal/workspace/handlers"
 
 	"github.com/gin-gonic/gin"
 	swaggerFiles "github.com/swaggo/files"
 	ginSwagger "github.com/swaggo/gin-swagger"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
 	"gorm.io/gorm"
 )
 
-func Setup(db *gorm.DB, cfg *config.Config) *gin.Engine {
+// Setup builds the API router; checker runs the readiness checks behind /health/ready
+func Setup(db *gorm.DB, cfg *config.Config, checker *health.Checker) *gin.Engine {
 	if cfg.Environment == "production" {
 		gin.SetMode(gin.ReleaseMode)
 	}
 
-	r := gin.Default()
+	r := gin.New()
+
+	// Client IPs, used by rate limits and the audit log, come from X-Forwarded-For only behind a trusted proxy
+	if err := r.SetTrustedProxies(cfg.TrustedProxies); err != nil {
+		fatal("Invalid trusted proxies configuration", err)
+	}
 
 	// Middleware globali
-	r.Use(sharedMiddleware.CORS())
+	r.Use(sharedMiddleware.RequestID())
+	r.Use(otelgin.Middleware(tracing.ServiceName))
 	r.Use(sharedMiddleware.Logger())
+	r.Use(metrics.Middleware())
+	r.Use(sharedMiddleware.CORS(corsPolicy(cfg)))
 	r.Use(sharedMiddleware.Recovery())
 
+	// Metrics on the public port only behind a token; the admin listener serves them otherwise
+	if cfg.MetricsToken != "" {
+		r.GET("/metrics", metrics.RequireToken(cfg.MetricsToken), gin.WrapH(metrics.Handler()))
+	}
+
 	// Swagger documentation
 	url := ginSwagger.URL("http://localhost:30000/swagger/doc.json") // The url pointing to API definition
 	r.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler, url))
 
-	// Health check
-	r.GET("/health", sharedHandlers.HealthCheck)
+	// Health probes; /health is kept as an alias of liveness for existing checks
+	healthHandler := sharedHandlers.NewHealthHandler(checker)
+	r.GET("/health", healthHandler.Live)
+	r.GET("/health/live", healthHandler.Live)
+	r.GET("/health/ready", healthHandler.Ready)
 
 	// Initialize handlers
 	authHandler := sharedHandlers.NewAuthHandler(db)
 	projectHandler := projectHandlers.NewProjectHandler(db)
 	channelHandler := chatHandlers.NewChannelHandler(db)
 	messageHandler := messageHandlers.NewMessageHandler(db)
+	commandHandler := messageHandlers.NewCommandHandler(db)
 	roleHandler := sharedHandlers.NewRoleHandler(db)
+
+	// Rate limiting per route group
+	rateLimitStore, err := ratelimit.NewStore(cfg.RateLimitStore, db)
+	if err != nil {
+		fatal("Invalid rate limit configuration", err)
+	}
+
+	presenceStore, err := presence.New(cfg.PresenceStore, db)
+	if err != nil {
+		fatal("Invalid presence configuration", err)
+	}
+
 	// API routes
 	v1 := r.Group("/api/v1")
 
@@ -50,31 +99,55 @@ func Setup(db *gorm.DB, cfg *config.Config) *gin.Engine {
 
 	// Webhook di Clerk (middleware + handler pattern)
 	auth.POST("/webhooks/clerk",
+		middleware.RateLimit(rateLimitStore, "webhooks", mustParseLimit(cfg.RateLimitWebhooks)),
 		sharedMiddleware.ClerkWebhookHandler(cfg.ClerkWebhookSecret),
+		sharedMiddleware.SetUserContext(), // Request metadata for the audit log
 		authHandler.WebhookHandler,
 	)
 
 	// Protected routes con Clerk SDK Auth
 	protected := v1.Group("/")
 	protected.Use(sharedMiddleware.ClerkAuthSDK(cfg.ClerkSecretKey))
+	protected.Use(middleware.RequireActiveUser(db))  // Deactivated users cannot authenticate
 	protected.Use(sharedMiddleware.SetUserContext()) // Add user context for GORM hooks
+	protected.Use(middleware.ResolveWorkspace(db))   // Current workspace from header or Clerk organization
 
 	// Auth routes (sync with Clerk)
 	authProtected := protected.Group("/auth")
+	authProtected.Use(middleware.RateLimit(rateLimitStore, "auth", mustParseLimit(cfg.RateLimitAuth)))
 	authProtected.POST("/sync", authHandler.SyncUser)
 	authProtected.GET("/me", authHandler.GetCurrentUser)
-	authProtected.POST("/import-users", middleware.RequireSystemRole(db, sharedModels.RoleAdmin), authHandler.ImportUsers)
+	authProtected.POST("/import-users", middleware.RequirePlatformAdmin(db), authHandler.ImportUsers)
+
+	// Workspaces the user belongs to
+	workspaceHandlers.RegisterWorkspaceRoutes(protected, db)
+
+	// Audit log (platform admin only)
+	auditHandlers.RegisterAuditRoutes(protected, db)
+
+	// GDPR data subject requests (platform admin only)
+	gdprHandlers.RegisterGDPRRoutes(protected, db)
+
+	// Background jobs (platform admin only)
+	jobHandlers.RegisterJobRoutes(protected, db)
+
+	// Routes below act on the data of the current workspace
+	scoped := protected.Group("/")
+	scoped.Use(middleware.RequireWorkspace())
 
 	// Users - using the new vertical slice structure
-	userHandlers.RegisterUserRoutes(protected, db)
+	userHandlers.RegisterUserRoutes(scoped, db)
+
+	// Presence and typing indicators
+	presenceHandlers.RegisterPresenceRoutes(scoped, db, presenceStore)
 
-	// Roles management (only admins can manage roles)
-	roles := protected.Group("/roles")
+	// Roles management in the current workspace (only admins can manage roles)
+	roles := scoped.Group("/roles")
 	roles.POST("", middleware.RequireSystemRole(db, sharedModels.RoleAdmin), roleHandler.AssignUserRole)
 	roles.DELETE("/:roleId", middleware.RequireSystemRole(db, sharedModels.RoleAdmin), roleHandler.RevokeUserRole)
 
 	// Projects
-	projects := protected.Group("/projects")
+	projects := scoped.Group("/projects")
 	projects.GET("", projectHandler.GetProjects)
 	projects.POST("", middleware.RequirePermission(db, sharedModels.PermissionProjectCreate, nil), projectHandler.CreateProject)
 	projects.GET("/:id", middleware.RequireProjectAccess(db), projectHandler.GetProject)
@@ -84,34 +157,73 @@ func Setup(db *gorm.DB, cfg *config.Config) *gin.Engine {
 	projects.DELETE("/:id/members/:userId", middleware.RequirePermission(db, sharedModels.PermissionProjectManage, stringPtr("project")), projectHandler.RemoveMember)
 
 	// Channels
-	channels := protected.Group("/channels")
+	channels := scoped.Group("/channels")
 	channels.GET("", channelHandler.GetChats)
 	channels.POST("", middleware.RequirePermission(db, sharedModels.PermissionChannelCreate, nil), channelHandler.CreateChat)
 	channels.GET("/:id", middleware.RequireChannelAccess(db), channelHandler.GetChat)
 	channels.POST("/:id/join", channelHandler.JoinChannel)
+	channels.PUT("/:id/link-previews", middleware.RequireChannelAccess(db), channelHandler.UpdateLinkPreviews)
 	channels.GET("/:id/messages", middleware.RequireChannelAccess(db), messageHandler.GetMessages)
-	channels.POST("/:id/messages", middleware.RequireChannelAccess(db), messageHandler.SendMessage)
+	channels.GET("/:id/messages/search", middleware.RequireChannelAccess(db), messageHandler.SearchMessages)
+	channels.POST("/:id/messages", middleware.RateLimit(rateLimitStore, "messages", mustParseLimit(cfg.RateLimitMessages)), middleware.RequireChannelAccess(db), messageHandler.SendMessage)
+
+	// Direct messages share the message rate limit; blocks between the two users are enforced by the handler
+	messages := scoped.Group("/messages")
+	messages.POST("/direct", middleware.RateLimit(rateLimitStore, "messages", mustParseLimit(cfg.RateLimitMessages)), messageHandler.CreateDirectMessage)
+
+	// Pinned messages and saved items
+	bookmarkHandlers.RegisterBookmarkRoutes(scoped, db)
+
+	// Scheduled messages and reminders
+	schedulingHandlers.RegisterSchedulingRoutes(scoped, db)
+
+	// Retention policies, legal holds and the purge dry-run (admin only)
+	retentionHandlers.RegisterRetentionRoutes(scoped, db)
+
+	// Message reports, the moderation queue, suspensions and blocked-word lists
+	moderationHandlers.RegisterModerationRoutes(scoped, db)
+
+	// Blocked and muted users
+	blockingHandlers.RegisterBlockRoutes(scoped, db)
+
+	// Slash commands (custom commands are managed by admins)
+	commands := scoped.Group("/commands")
+	commands.GET("", commandHandler.GetCommands)
+	commands.POST("", middleware.RequireSystemRole(db, sharedModels.RoleAdmin), commandHandler.CreateCustomCommand)
+	commands.DELETE("/:id", middleware.RequireSystemRole(db, sharedModels.RoleAdmin), commandHandler.DeleteCustomCommand)
 
 	// WebSocket endpoint
 	r.GET("/ws", func(c *gin.Context) {
-		// TODO: Implement WebSocket handler with Clerk auth
+		// TODO: Implement WebSocket handler with Clerk auth; register each upgraded connection in a
+		// server.Connections and add its CloseAll as a shutdown step in main so clients get a close frame
 		c.JSON(200, gin.H{"message": "WebSocket endpoint - TODO"})
 	})
 
 	return r
 }
 
+// corsPolicy reads the CORS settings from cfg
+func corsPolicy(cfg *config.Config) sharedMiddleware.CORSPolicy {
+	return sharedMiddleware.CORSPolicy{
+		AllowedOrigins:   cfg.CORSAllowedOrigins,
+		AllowCredentials: cfg.CORSAllowCredentials,
+		ExposedHeaders:   cfg.CORSExposedHeaders,
+		MaxAge:           cfg.CORSMaxAge,
+	}
+}
+
 // SetupTestRouter creates a router without authentication middleware for testing
 func SetupTestRouter(db *gorm.DB, cfg *config.Config) *gin.Engine {
 	if cfg.Environment == "production" {
 		gin.SetMode(gin.ReleaseMode)
 	}
 
-	r := gin.Default()
+	r := gin.New()
 
 	// Basic middleware for tests
-	r.Use(sharedMiddleware.CORS())
+	r.Use(sharedMiddleware.RequestID())
 	r.Use(sharedMiddleware.Logger())
+	r.Use(sharedMiddleware.CORS(corsPolicy(cfg)))
 	r.Use(sharedMiddleware.Recovery())
 
 	// Mock authentication middleware for tests
@@ -123,8 +235,11 @@ func SetupTestRouter(db *gorm.DB, cfg *config.Config) *gin.Engine {
 	})
 	r.Use(sharedMiddleware.SetUserContext()) // Add user context for GORM hooks
 
-	// Health check
-	r.GET("/health", sharedHandlers.HealthCheck)
+	// Health probes
+	healthHandler := sharedHandlers.NewHealthHandler(health.NewChecker())
+	r.GET("/health", healthHandler.Live)
+	r.GET("/health/live", healthHandler.Live)
+	r.GET("/health/ready", healthHandler.Ready)
 
 	// Initialize handlers
 	authHandler := sharedHandlers.NewAuthHandler(db)
@@ -158,11 +273,30 @@ func SetupTestRouter(db *gorm.DB, cfg *config.Config) *gin.Engine {
 	channels.GET("/:id", channelHandler.GetChat)
 	channels.POST("/:id/join", channelHandler.JoinChannel)
 	channels.GET("/:id/messages", messageHandler.GetMessages)
+	channels.GET("/:id/messages/search", messageHandler.SearchMessages)
 	channels.POST("/:id/messages", messageHandler.SendMessage)
 
+	// Presence (in-memory store in tests)
+	presenceHandlers.RegisterPresenceRoutes(v1, db, presence.NewMemoryStore())
+
 	return r
 }
 
+// fatal logs a configuration error found while building the router and stops startup
+func fatal(message string, err error) {
+	slog.Error(message, slog.Any("error", err))
+	os.Exit(1)
+}
+
+// mustParseLimit parses a configured rate limit, stopping startup when it is invalid
+func mustParseLimit(value string) ratelimit.Limit {
+	limit, err := ratelimit.ParseLimit(value)
+	if err != nil {
+		fatal("Invalid rate limit configuration", err)
+	}
+	return limit
+}
+
 // Helper function to create string pointer
 func stringPtr(s string) *string {
 	return &s
@@ -0,0 +1,97 @@
+package testing
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/hashicorp/vault/api"
+	"github.com/testcontainers/testcontainers-go"
+	"github.com/testcontainers/testcontainers-go/modules/vault"
+)
+
+// VaultTestContainer is a Vault dev server for integration tests
+type VaultTestContainer struct {
+	Address   string
+	RootToken string
+	Root      *api.Client // Client authenticated with the root token, for test setup
+	Container *vault.VaultContainer
+	Context   context.Context
+}
+
+// VaultTestConfig holds configuration for the Vault test container
+type VaultTestConfig struct {
+	Image     string   // Vault image to use (default: "hashicorp/vault:1.15")
+	RootToken string   // Dev server root token (default: "root-token")
+	Commands  []string // Vault CLI commands run after startup, such as "auth enable approle"
+}
+
+// DefaultVaultTestConfig returns a default configuration for the Vault test container
+func DefaultVaultTestConfig() VaultTestConfig {
+	return VaultTestConfig{
+		Image:     "hashicorp/vault:1.15",
+		RootToken: "root-token",
+	}
+}
+
+// NewVaultTestContainer starts a Vault dev server, which is unsealed and keeps everything in memory
+func NewVaultTestContainer(t *testing.T, config ...VaultTestConfig) *VaultTestContainer {
+	t.Helper()
+
+	ctx := context.Background()
+
+	cfg := DefaultVaultTestConfig()
+	if len(config) > 0 {
+		cfg = config[0]
+	}
+
+	// Disable testcontainers reaper to avoid port conflicts on Windows
+	os.Setenv("TESTCONTAINERS_RYUK_DISABLED", "true")
+
+	opts := []testcontainers.ContainerCustomizer{
+		testcontainers.WithImage(cfg.Image),
+		vault.WithToken(cfg.RootToken),
+	}
+	if len(cfg.Commands) > 0 {
+		opts = append(opts, vault.WithInitCommand(cfg.Commands...))
+	}
+
+	vaultContainer, err := vault.RunContainer(ctx, opts...)
+	if err != nil {
+		t.Fatalf("Failed to start Vault container: %v", err)
+	}
+
+	t.Cleanup(func() {
+		if vaultContainer != nil {
+			_ = vaultContainer.Terminate(ctx)
+		}
+	})
+
+	address, err := vaultContainer.HttpHostAddress(ctx)
+	if err != nil {
+		t.Fatalf("Failed to get Vault address: %v", err)
+	}
+
+	rootConfig := api.DefaultConfig()
+	rootConfig.Address = address
+	root, err := api.NewClient(rootConfig)
+	if err != nil {
+		t.Fatalf("Failed to create Vault client: %v", err)
+	}
+	root.SetToken(cfg.RootToken)
+
+	return &VaultTestContainer{
+		Address:   address,
+		RootToken: cfg.RootToken,
+		Root:      root,
+		Container: vaultContainer,
+		Context:   ctx,
+	}
+}
+
+// ClientConfig returns an API configuration pointing at the container
+func (tc *VaultTestContainer) ClientConfig() *api.Config {
+	config := api.DefaultConfig()
+	config.Address = tc.Address
+	return config
+}
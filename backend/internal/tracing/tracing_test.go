@@ -0,0 +1,27 @@
+package tracing
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+func TestSetup_WithoutEndpointInstallsPropagator(t *testing.T) {
+	shutdown, err := Setup(context.Background(), "", "test", "dev")
+
+	assert.NoError(t, err)
+	assert.NoError(t, shutdown(context.Background()))
+	assert.ElementsMatch(t, []string{"traceparent", "tracestate", "baggage"}, otel.GetTextMapPropagator().Fields())
+}
+
+func TestTraceID(t *testing.T) {
+	provider := sdktrace.NewTracerProvider()
+	ctx, span := provider.Tracer("test").Start(context.Background(), "request")
+	defer span.End()
+
+	assert.Equal(t, "", TraceID(context.Background()))
+	assert.Equal(t, span.SpanContext().TraceID().String(), TraceID(ctx))
+}
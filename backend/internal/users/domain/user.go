@@ -7,17 +7,31 @@ import (
 	sharedModels "thothix-backend/internal/shared/models"
 )
 
+const (
+	// DeactivatedUserName is shown instead of the name of a deactivated user
+	DeactivatedUserName = "Deactivated user"
+	// PurgedUserName replaces the name of a user whose personal data was purged
+	PurgedUserName = "Deleted user"
+	// TombstoneUserID is the shared identity that erased users' messages are reassigned to
+	TombstoneUserID = "00000000-0000-0000-0000-000000000000"
+)
+
 // User represents a user entity in the users domain
+// Users are never hard deleted: they are deactivated, and personal data is removed by purging
 type User struct {
 	commonModels.BaseModel
 	// Clerk user ID (optional - NULL for manually created users)
-	ClerkID    *string               `json:"clerk_id" gorm:"uniqueIndex"` // NULL for manual users, unique for Clerk users
-	Email      string                `json:"email"`
-	Name       string                `json:"name"`
-	Username   string                `json:"username"`
-	AvatarURL  string                `json:"avatar_url"`
+	// Personal data is tagged so the append-only audit log never holds it, since erasure could not remove it there
+	ClerkID    *string               `json:"clerk_id" gorm:"uniqueIndex" audit:"redact"` // NULL for manual users, unique for Clerk users
+	Email      string                `json:"email" audit:"redact"`
+	Name       string                `json:"name" audit:"redact"`
+	Username   string                `json:"username" audit:"redact"`
+	AvatarURL  string                `json:"avatar_url" audit:"redact"`
 	SystemRole sharedModels.RoleType `json:"system_role" gorm:"default:'user'"` // Default system role
 	LastSync   time.Time             `json:"last_sync"`                         // When we last synced with Clerk
+	// Lifecycle - a deactivated user cannot authenticate and a purged user has been anonymized
+	DeactivatedAt *time.Time `json:"deactivated_at,omitempty" gorm:"index"`
+	PurgedAt      *time.Time `json:"purged_at,omitempty"`
 }
 
 // TableName specifies the table name for the User model
@@ -39,3 +53,58 @@ func (u *User) SyncFromClerk(data ClerkUserData) {
 	u.AvatarURL = data.AvatarURL
 	u.LastSync = time.Now()
 }
+
+// NewTombstoneUser returns the shared identity that replaces erased users as message authors
+func NewTombstoneUser() *User {
+	now := time.Now()
+	user := &User{Name: PurgedUserName, SystemRole: sharedModels.RoleExternal}
+	user.ID = TombstoneUserID
+	user.DeactivatedAt = &now
+	user.PurgedAt = &now
+	return user
+}
+
+// IsActive reports whether the user can authenticate and act
+func (u *User) IsActive() bool {
+	return u.DeactivatedAt == nil
+}
+
+// IsPurged reports whether the user's personal data has been removed
+func (u *User) IsPurged() bool {
+	return u.PurgedAt != nil
+}
+
+// Deactivate marks the user as deactivated, keeping the original deactivation time if already set
+func (u *User) Deactivate(at time.Time) {
+	if u.DeactivatedAt == nil {
+		u.DeactivatedAt = &at
+	}
+}
+
+// Reactivate restores a deactivated user
+func (u *User) Reactivate() {
+	u.DeactivatedAt = nil
+}
+
+// Anonymize removes personal data while keeping the row so messages and audit entries still resolve
+func (u *User) Anonymize(at time.Time) {
+	u.Deactivate(at)
+	u.ClerkID = nil
+	u.Email = ""
+	u.Name = PurgedUserName
+	u.Username = ""
+	u.AvatarURL = ""
+	u.PurgedAt = &at
+}
+
+// DisplayName returns the name shown to other users next to the user's messages
+func (u *User) DisplayName() string {
+	switch {
+	case u.IsPurged():
+		return PurgedUserName
+	case !u.IsActive():
+		return DeactivatedUserName
+	default:
+		return u.Name
+	}
+}
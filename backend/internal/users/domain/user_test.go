@@ -66,6 +66,52 @@ func (suite *UserDomainTestSuite) TestClerkUserData() {
 	assert.Equal(suite.T(), "https://example.com/avatar.jpg", clerkData.AvatarURL)
 }
 
+func (suite *UserDomainTestSuite) TestDeactivateAndReactivate() {
+	// Arrange
+	user := &User{Name: "Test User"}
+	deactivatedAt := time.Now()
+
+	// Act
+	user.Deactivate(deactivatedAt)
+	user.Deactivate(deactivatedAt.Add(time.Hour))
+
+	// Assert
+	assert.False(suite.T(), user.IsActive())
+	assert.Equal(suite.T(), deactivatedAt, *user.DeactivatedAt)
+	assert.Equal(suite.T(), DeactivatedUserName, user.DisplayName())
+
+	// Act
+	user.Reactivate()
+
+	// Assert
+	assert.True(suite.T(), user.IsActive())
+	assert.Equal(suite.T(), "Test User", user.DisplayName())
+}
+
+func (suite *UserDomainTestSuite) TestAnonymize() {
+	// Arrange
+	clerkID := "clerk-123"
+	user := &User{
+		ClerkID:   &clerkID,
+		Email:     "test@example.com",
+		Name:      "Test User",
+		Username:  "testuser",
+		AvatarURL: "https://example.com/avatar.jpg",
+	}
+
+	// Act
+	user.Anonymize(time.Now())
+
+	// Assert
+	assert.Nil(suite.T(), user.ClerkID)
+	assert.Empty(suite.T(), user.Email)
+	assert.Empty(suite.T(), user.Username)
+	assert.Empty(suite.T(), user.AvatarURL)
+	assert.False(suite.T(), user.IsActive())
+	assert.True(suite.T(), user.IsPurged())
+	assert.Equal(suite.T(), PurgedUserName, user.DisplayName())
+}
+
 func TestUserDomainTestSuite(t *testing.T) {
 	suite.Run(t, new(UserDomainTestSuite))
 }
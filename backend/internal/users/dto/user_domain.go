@@ -9,14 +9,16 @@ import (
 
 // UserDto represents the user data structure (domain mapping)
 type UserDto struct {
-	ID        string `json:"id"`
-	Email     string `json:"email"`
-	Name      string `json:"name"`
-	ClerkID   string `json:"clerk_id,omitempty"`
-	Username  string `json:"username,omitempty"`
-	AvatarURL string `json:"avatar_url,omitempty"`
-	CreatedAt string `json:"created_at"`
-	UpdatedAt string `json:"updated_at"`
+	ID            string `json:"id"`
+	Email         string `json:"email"`
+	Name          string `json:"name"`
+	ClerkID       string `json:"clerk_id,omitempty"`
+	Username      string `json:"username,omitempty"`
+	AvatarURL     string `json:"avatar_url,omitempty"`
+	IsActive      bool   `json:"is_active"`
+	DeactivatedAt string `json:"deactivated_at,omitempty"`
+	CreatedAt     string `json:"created_at"`
+	UpdatedAt     string `json:"updated_at"`
 }
 
 // UserListDto represents paginated user list data
@@ -123,12 +123,19 @@ func TestCompleteUserLifecycle(t *testing.T) {
 
 		assert.Equal(t, http.StatusOK, w.Code)
 
-		// Step 5: Verify deletion (should return 404)
+		// Step 5: Verify deactivation (user is kept but no longer active)
 		req, _ = http.NewRequest("GET", "/api/v1/users/"+userID, nil)
 		w = httptest.NewRecorder()
 		testRouter.ServeHTTP(w, req)
 
-		assert.Equal(t, http.StatusNotFound, w.Code)
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var deactivatedResponse map[string]interface{}
+		err = json.Unmarshal(w.Body.Bytes(), &deactivatedResponse)
+		assert.NoError(t, err)
+
+		deactivatedData := deactivatedResponse["data"].(map[string]interface{})
+		assert.Equal(t, false, deactivatedData["is_active"])
 	})
 }
 
@@ -3,6 +3,7 @@ package handlers
 import (
 	"github.com/gin-gonic/gin"
 	"gorm.io/gorm"
+	"thothix-backend/internal/middleware"
 	"thothix-backend/internal/users/service"
 )
 
@@ -16,5 +17,7 @@ func RegisterUserRoutes(router *gin.RouterGroup, db *gorm.DB) {
 	users.GET("", userHandler.GetUsers)
 	users.POST("", userHandler.CreateUser)
 	users.PUT("/:id", userHandler.UpdateUser)
-	users.DELETE("/:id", userHandler.DeleteUser)
+	users.DELETE("/:id", middleware.RequirePlatformAdmin(db), userHandler.DeleteUser) // Deactivates, see PurgeUser for removal of personal data
+	users.POST("/:id/reactivate", middleware.RequirePlatformAdmin(db), userHandler.ReactivateUser)
+	users.POST("/:id/purge", middleware.RequirePlatformAdmin(db), userHandler.PurgeUser)
 }
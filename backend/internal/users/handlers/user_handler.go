@@ -50,7 +50,7 @@ func (h *UserHandler) GetUsers(c *gin.Context) {
 	}
 
 	// Get response from service using the Response pattern
-	response := h.userService.GetUsers(&request)
+	response := h.scopedService(c).GetUsers(&request)
 
 	// Use Match pattern to handle all three cases with wrapper methods
 	response.Match(
@@ -90,7 +90,7 @@ func (h *UserHandler) GetUserByID(c *gin.Context) {
 	userID := c.Param("id")
 
 	// Get response from service
-	response := h.userService.GetUserByID(userID)
+	response := h.scopedService(c).GetUserByID(userID)
 
 	// Use Match pattern to handle all three cases with wrapper methods
 	response.Match(
@@ -140,7 +140,7 @@ func (h *UserHandler) CreateUser(c *gin.Context) {
 	}
 
 	// Get response from service
-	response := h.userService.CreateUser(&request)
+	response := h.scopedService(c).CreateUser(&request)
 
 	// Use Match pattern to handle all three cases with wrapper methods
 	response.Match(
@@ -192,7 +192,7 @@ func (h *UserHandler) UpdateUser(c *gin.Context) {
 	}
 
 	// Get response from service
-	response := h.userService.UpdateUser(userID, &request)
+	response := h.scopedService(c).UpdateUser(userID, &request)
 
 	// Use Match pattern to handle all three cases with wrapper methods
 	response.Match(
@@ -220,8 +220,8 @@ func (h *UserHandler) UpdateUser(c *gin.Context) {
 }
 
 // DeleteUser godoc
-// @Summary Delete a user
-// @Description Delete a user by ID
+// @Summary Deactivate a user
+// @Description Deactivate a user by ID. The user can no longer authenticate, but messages and memberships are kept. Use the purge endpoint to remove personal data. Platform admin only
 // @Tags users
 // @Accept json
 // @Produce json
@@ -237,7 +237,7 @@ func (h *UserHandler) DeleteUser(c *gin.Context) {
 	userID := c.Param("id")
 
 	// Get response from service
-	response := h.userService.DeleteUser(userID)
+	response := h.scopedService(c).DeleteUser(userID)
 
 	// Use Match pattern to handle all three cases with wrapper methods
 	response.Match(
@@ -263,3 +263,106 @@ func (h *UserHandler) DeleteUser(c *gin.Context) {
 		},
 	)
 }
+
+// ReactivateUser godoc
+// @Summary Reactivate a user
+// @Description Restore a deactivated user by ID. Platform admin only
+// @Tags users
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "User ID"
+// @Success 200 {object} usersDto.UserResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 409 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /users/{id}/reactivate [post]
+func (h *UserHandler) ReactivateUser(c *gin.Context) {
+	wrapper := handlers.WrapContext(c)
+	userID := c.Param("id")
+
+	// Get response from service
+	response := h.scopedService(c).ReactivateUser(userID)
+
+	// Use Match pattern to handle all three cases with wrapper methods
+	response.Match(
+		// Exception case
+		func(err error) interface{} {
+			wrapper.SystemErrorResponse(err, "Failed to reactivate user with ID: %s", userID)
+			return nil
+		},
+		// Success case
+		func(result *usersDto.UserDto) interface{} {
+			wrapper.SuccessResponse(result)
+			return nil
+		},
+		// Validation failure case
+		func(errors []dto.Error) interface{} {
+			switch {
+			case len(errors) > 0 && errors[0].Code == "USER_NOT_FOUND":
+				wrapper.NotFoundErrorResponse("User", userID)
+			case len(errors) > 0 && errors[0].Code == "USER_PURGED":
+				wrapper.ConflictErrorResponse("Purged users cannot be reactivated")
+			default:
+				wrapper.ValidationErrorResponse(errors, "User reactivation validation failed for ID: %s", userID)
+			}
+			return nil
+		},
+	)
+}
+
+// PurgeUser godoc
+// @Summary Purge a user
+// @Description Anonymize a user's personal data and remove their memberships and roles. Messages are kept and shown as "Deleted user". Platform admin only
+// @Tags users
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "User ID"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 403 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /users/{id}/purge [post]
+func (h *UserHandler) PurgeUser(c *gin.Context) {
+	wrapper := handlers.WrapContext(c)
+	userID := c.Param("id")
+
+	// Get response from service
+	response := h.scopedService(c).PurgeUser(userID)
+
+	// Use Match pattern to handle all three cases with wrapper methods
+	response.Match(
+		// Exception case
+		func(err error) interface{} {
+			wrapper.SystemErrorResponse(err, "Failed to purge user with ID: %s", userID)
+			return nil
+		},
+		// Success case
+		func(result string) interface{} {
+			wrapper.DeletedResponse(result)
+			return nil
+		},
+		// Validation failure case
+		func(errors []dto.Error) interface{} {
+			// Check if it's a not found error
+			if len(errors) > 0 && errors[0].Code == "USER_NOT_FOUND" {
+				wrapper.NotFoundErrorResponse("User", userID)
+			} else {
+				wrapper.ValidationErrorResponse(errors, "User purge validation failed for ID: %s", userID)
+			}
+			return nil
+		},
+	)
+}
+
+// scopedService binds the service to the request context when supported,
+// so changes made through it are attributed to the current user in the audit log
+func (h *UserHandler) scopedService(c *gin.Context) service.UserServiceInterface {
+	if aware, ok := h.userService.(service.ContextAwareUserService); ok {
+		return aware.WithContext(c.Request.Context())
+	}
+	return h.userService
+}
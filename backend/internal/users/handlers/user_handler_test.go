@@ -50,6 +50,21 @@ func (m *MockUserService) DeleteUser(userID string) *usersDto.DeleteUserResponse
 	return args.Get(0).(*usersDto.DeleteUserResponse)
 }
 
+func (m *MockUserService) DeactivateUser(userID string) *usersDto.UpdateUserResponse {
+	args := m.Called(userID)
+	return args.Get(0).(*usersDto.UpdateUserResponse)
+}
+
+func (m *MockUserService) ReactivateUser(userID string) *usersDto.UpdateUserResponse {
+	args := m.Called(userID)
+	return args.Get(0).(*usersDto.UpdateUserResponse)
+}
+
+func (m *MockUserService) PurgeUser(userID string) *usersDto.DeleteUserResponse {
+	args := m.Called(userID)
+	return args.Get(0).(*usersDto.DeleteUserResponse)
+}
+
 func (m *MockUserService) SyncUserFromClerk(req *usersDto.ClerkUserSyncRequest) *usersDto.CreateUserResponse {
 	args := m.Called(req)
 	return args.Get(0).(*usersDto.CreateUserResponse)
@@ -90,6 +105,8 @@ func (suite *UserHandlerTestSuite) SetupTest() {
 	suite.router.GET("/users", suite.handler.GetUsers)
 	suite.router.PUT("/users/:id", suite.handler.UpdateUser)
 	suite.router.DELETE("/users/:id", suite.handler.DeleteUser)
+	suite.router.POST("/users/:id/reactivate", suite.handler.ReactivateUser)
+	suite.router.POST("/users/:id/purge", suite.handler.PurgeUser)
 }
 
 func (suite *UserHandlerTestSuite) TestNewUserHandler() {
@@ -230,7 +247,7 @@ func (suite *UserHandlerTestSuite) TestUpdateUser_Success() {
 func (suite *UserHandlerTestSuite) TestDeleteUser_Success() {
 	// Arrange
 	mockResponse := usersDto.NewDeleteUserResponse(func() dto.Validation[string] {
-		return dto.Success("User deleted successfully")
+		return dto.Success("User deactivated successfully")
 	})
 
 	suite.mockService.On("DeleteUser", "test-id").Return(mockResponse)
@@ -244,6 +261,57 @@ func (suite *UserHandlerTestSuite) TestDeleteUser_Success() {
 	assert.Equal(suite.T(), http.StatusOK, w.Code)
 }
 
+func (suite *UserHandlerTestSuite) TestReactivateUser_Success() {
+	// Arrange
+	mockResponse := usersDto.NewUpdateUserResponse(func() dto.Validation[*usersDto.UserDto] {
+		return dto.Success(&usersDto.UserDto{ID: "test-id", IsActive: true})
+	})
+
+	suite.mockService.On("ReactivateUser", "test-id").Return(mockResponse)
+
+	// Act
+	req, _ := http.NewRequest("POST", "/users/test-id/reactivate", nil)
+	w := httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(suite.T(), http.StatusOK, w.Code)
+}
+
+func (suite *UserHandlerTestSuite) TestReactivateUser_Purged() {
+	// Arrange
+	mockResponse := usersDto.NewUpdateUserResponse(func() dto.Validation[*usersDto.UserDto] {
+		return dto.Invalid[*usersDto.UserDto](dto.NewError("USER_PURGED", "Purged users cannot be reactivated", nil))
+	})
+
+	suite.mockService.On("ReactivateUser", "test-id").Return(mockResponse)
+
+	// Act
+	req, _ := http.NewRequest("POST", "/users/test-id/reactivate", nil)
+	w := httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(suite.T(), http.StatusConflict, w.Code)
+}
+
+func (suite *UserHandlerTestSuite) TestPurgeUser_NotFound() {
+	// Arrange
+	mockResponse := usersDto.NewDeleteUserResponse(func() dto.Validation[string] {
+		return dto.Invalid[string](dto.NewError("USER_NOT_FOUND", "User not found", nil))
+	})
+
+	suite.mockService.On("PurgeUser", "missing-id").Return(mockResponse)
+
+	// Act
+	req, _ := http.NewRequest("POST", "/users/missing-id/purge", nil)
+	w := httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(suite.T(), http.StatusNotFound, w.Code)
+}
+
 func (suite *UserHandlerTestSuite) TestGetUsers_Success() {
 	// Arrange
 	userListDto := &usersDto.UserListDto{}
@@ -28,15 +28,23 @@ func (m *UserMapper) ModelToDto(user *domain.User) *usersDto.UserDto {
 		clerkID = *user.ClerkID
 	}
 
+	// Handle nullable DeactivatedAt
+	var deactivatedAt string
+	if user.DeactivatedAt != nil {
+		deactivatedAt = user.DeactivatedAt.Format(time.RFC3339)
+	}
+
 	return &usersDto.UserDto{
-		ID:        user.ID,
-		ClerkID:   clerkID, // Convert from *string to string (empty if nil)
-		Email:     user.Email,
-		Name:      user.Name,
-		Username:  user.Username,
-		AvatarURL: user.AvatarURL,
-		CreatedAt: user.CreatedAt.Format(time.RFC3339),
-		UpdatedAt: user.UpdatedAt.Format(time.RFC3339),
+		ID:            user.ID,
+		ClerkID:       clerkID, // Convert from *string to string (empty if nil)
+		Email:         user.Email,
+		Name:          user.Name,
+		Username:      user.Username,
+		AvatarURL:     user.AvatarURL,
+		IsActive:      user.IsActive(),
+		DeactivatedAt: deactivatedAt,
+		CreatedAt:     user.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:     user.UpdatedAt.Format(time.RFC3339),
 	}
 }
 
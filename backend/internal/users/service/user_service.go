@@ -1,13 +1,22 @@
 package service
 
 import (
+	"context"
+	"time"
+
 	"gorm.io/gorm"
 
+	auditDomain "thothix-backend/internal/audit/domain"
+	auditHooks "thothix-backend/internal/audit/hooks"
+	chatDomain "thothix-backend/internal/chat/domain"
+	projectDomain "thothix-backend/internal/project/domain"
 	"thothix-backend/internal/shared/dto"
 	sharedMiddleware "thothix-backend/internal/shared/middleware"
+	sharedModels "thothix-backend/internal/shared/models"
 	"thothix-backend/internal/users/domain"
 	usersDto "thothix-backend/internal/users/dto"
 	"thothix-backend/internal/users/mappers"
+	workspaceDomain "thothix-backend/internal/workspace/domain"
 )
 
 type UserService struct {
@@ -22,6 +31,15 @@ func NewUserService(db *gorm.DB) *UserService {
 	}
 }
 
+// WithContext returns a copy of the service whose database session carries ctx
+// The audit callbacks read the acting user and request metadata from this context
+func (s *UserService) WithContext(ctx context.Context) *UserService {
+	return &UserService{
+		db:     s.db.WithContext(ctx),
+		mapper: s.mapper,
+	}
+}
+
 // GetUserByID retrieves a user by ID using Response pattern with lazy evaluation
 func (s *UserService) GetUserByID(userID string) *usersDto.GetUserResponse {
 	return usersDto.NewGetUserResponse(func() dto.Validation[*usersDto.UserDto] {
@@ -38,7 +56,7 @@ func (s *UserService) GetUserByID(userID string) *usersDto.GetUserResponse {
 
 		// Business logic - this can panic and will be caught by Try()
 		var user domain.User
-		if err := s.db.Where("id = ?", userID).First(&user).Error; err != nil {
+		if err := s.db.Scopes(sharedModels.WorkspaceMembers).Where("id = ?", userID).First(&user).Error; err != nil {
 			if err == gorm.ErrRecordNotFound {
 				return dto.Invalid[*usersDto.UserDto](dto.NewError("USER_NOT_FOUND", "User not found", nil))
 			}
@@ -105,7 +123,7 @@ func (s *UserService) GetUsers(req *dto.PaginationRequest) *usersDto.GetUsersRes
 		var total int64
 
 		// Count total users
-		if err := s.db.Model(&domain.User{}).Count(&total).Error; err != nil {
+		if err := s.db.Model(&domain.User{}).Scopes(sharedModels.WorkspaceMembers).Count(&total).Error; err != nil {
 			panic(err)
 		}
 
@@ -113,7 +131,7 @@ func (s *UserService) GetUsers(req *dto.PaginationRequest) *usersDto.GetUsersRes
 		offset := (req.Page - 1) * req.PerPage
 
 		// Get users with pagination
-		if err := s.db.Offset(offset).Limit(req.PerPage).Find(&users).Error; err != nil {
+		if err := s.db.Scopes(sharedModels.WorkspaceMembers).Offset(offset).Limit(req.PerPage).Find(&users).Error; err != nil {
 			panic(err)
 		}
 
@@ -153,9 +171,25 @@ func (s *UserService) CreateUser(req *usersDto.CreateUserRequest) *usersDto.Crea
 			return dto.Invalid[*usersDto.UserDto](dto.NewError("CONFLICT", "User with this email already exists", nil))
 		}
 
-		// Create new user
+		// Create new user, joining the current workspace with their default role
 		user := s.mapper.CreateRequestToModel(req)
-		if err := s.db.Create(user).Error; err != nil {
+		err := s.db.Transaction(func(tx *gorm.DB) error {
+			if err := tx.Create(user).Error; err != nil {
+				return err
+			}
+			workspaceID, ok := sharedMiddleware.WorkspaceIDFromContext(tx.Statement.Context)
+			if !ok {
+				return nil
+			}
+			resourceType := sharedModels.ResourceTypeWorkspace
+			return tx.Create(&sharedModels.UserRole{
+				ResourceID:   &workspaceID,
+				ResourceType: &resourceType,
+				UserID:       user.ID,
+				Role:         user.SystemRole,
+			}).Error
+		})
+		if err != nil {
 			panic(err)
 		}
 
@@ -184,7 +218,7 @@ func (s *UserService) UpdateUser(userID string, req *usersDto.UpdateUserRequest)
 
 		// Get existing user
 		var user domain.User
-		if err := s.db.Where("id = ?", userID).First(&user).Error; err != nil {
+		if err := s.db.Scopes(sharedModels.WorkspaceMembers).Where("id = ?", userID).First(&user).Error; err != nil {
 			if err == gorm.ErrRecordNotFound {
 				return dto.Invalid[*usersDto.UserDto](dto.NewError("USER_NOT_FOUND", "User not found", nil))
 			}
@@ -204,8 +238,104 @@ func (s *UserService) UpdateUser(userID string, req *usersDto.UpdateUserRequest)
 	})
 }
 
-// DeleteUser deletes a user using Response pattern
+// DeleteUser deactivates a user using Response pattern
+// Users are never hard deleted here: messages, memberships and roles keep referencing them. Use PurgeUser to remove personal data
 func (s *UserService) DeleteUser(userID string) *usersDto.DeleteUserResponse {
+	return usersDto.NewDeleteUserResponse(func() dto.Validation[string] {
+		return s.DeactivateUser(userID).Match(
+			func(err error) interface{} {
+				panic(err) // Propagate to the outer Try()
+			},
+			func(*usersDto.UserDto) interface{} {
+				return dto.Success("User deactivated successfully")
+			},
+			func(errors []dto.Error) interface{} {
+				return dto.Invalid[string](errors...)
+			},
+		).(dto.Validation[string])
+	})
+}
+
+// DeactivateUser deactivates a user using Response pattern
+// A deactivated user cannot authenticate, is shown as "Deactivated user" and keeps frozen memberships
+func (s *UserService) DeactivateUser(userID string) *usersDto.UpdateUserResponse {
+	return usersDto.NewUpdateUserResponse(func() dto.Validation[*usersDto.UserDto] {
+		var validationErrors []dto.Error
+
+		// Validation
+		if userID == "" {
+			validationErrors = append(validationErrors, dto.NewError("VALIDATION_ERROR", "User ID cannot be empty", nil))
+		}
+
+		if len(validationErrors) > 0 {
+			return dto.Failure[*usersDto.UserDto](validationErrors...)
+		}
+
+		// Get existing user
+		var user domain.User
+		if err := s.db.Scopes(sharedModels.WorkspaceMembers).Where("id = ?", userID).First(&user).Error; err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return dto.Invalid[*usersDto.UserDto](dto.NewError("USER_NOT_FOUND", "User not found", nil))
+			}
+			panic(err)
+		}
+
+		// Deactivation is idempotent so repeated webhooks don't fail
+		if user.IsActive() {
+			user.Deactivate(time.Now())
+			if err := s.db.Model(&user).Update("deactivated_at", user.DeactivatedAt).Error; err != nil {
+				panic(err)
+			}
+		}
+
+		userDto := s.mapper.ModelToDto(&user)
+		return dto.Success(userDto)
+	})
+}
+
+// ReactivateUser restores a deactivated user using Response pattern
+func (s *UserService) ReactivateUser(userID string) *usersDto.UpdateUserResponse {
+	return usersDto.NewUpdateUserResponse(func() dto.Validation[*usersDto.UserDto] {
+		var validationErrors []dto.Error
+
+		// Validation
+		if userID == "" {
+			validationErrors = append(validationErrors, dto.NewError("VALIDATION_ERROR", "User ID cannot be empty", nil))
+		}
+
+		if len(validationErrors) > 0 {
+			return dto.Failure[*usersDto.UserDto](validationErrors...)
+		}
+
+		// Get existing user
+		var user domain.User
+		if err := s.db.Scopes(sharedModels.WorkspaceMembers).Where("id = ?", userID).First(&user).Error; err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return dto.Invalid[*usersDto.UserDto](dto.NewError("USER_NOT_FOUND", "User not found", nil))
+			}
+			panic(err)
+		}
+
+		// Purged users have no personal data left to restore
+		if user.IsPurged() {
+			return dto.Invalid[*usersDto.UserDto](dto.NewError("USER_PURGED", "Purged users cannot be reactivated", nil))
+		}
+
+		if !user.IsActive() {
+			user.Reactivate()
+			if err := s.db.Model(&user).Update("deactivated_at", nil).Error; err != nil {
+				panic(err)
+			}
+		}
+
+		userDto := s.mapper.ModelToDto(&user)
+		return dto.Success(userDto)
+	})
+}
+
+// PurgeUser anonymizes a user and removes their memberships and roles using Response pattern
+// The row is kept as a tombstone so messages and audit entries still resolve to "Deleted user"
+func (s *UserService) PurgeUser(userID string) *usersDto.DeleteUserResponse {
 	return usersDto.NewDeleteUserResponse(func() dto.Validation[string] {
 		var validationErrors []dto.Error
 
@@ -227,12 +357,37 @@ func (s *UserService) DeleteUser(userID string) *usersDto.DeleteUserResponse {
 			panic(err)
 		}
 
-		// Delete user
-		if err := s.db.Delete(&user).Error; err != nil {
+		err := s.db.Transaction(func(tx *gorm.DB) error {
+			if err := tx.Where("user_id = ?", user.ID).Delete(&chatDomain.ChannelMember{}).Error; err != nil {
+				return err
+			}
+			if err := tx.Where("user_id = ?", user.ID).Delete(&projectDomain.ProjectMember{}).Error; err != nil {
+				return err
+			}
+			if err := tx.Where("user_id = ?", user.ID).Delete(&sharedModels.UserRole{}).Error; err != nil {
+				return err
+			}
+			if err := tx.Where("user_id = ?", user.ID).Delete(&workspaceDomain.Invitation{}).Error; err != nil {
+				return err
+			}
+
+			// The callbacks would copy the erased profile into the append-only audit log, so the update
+			// is left out of it and recorded without its before-state
+			user.Anonymize(time.Now())
+			anonymized := []string{"clerk_id", "email", "name", "username", "avatar_url", "deactivated_at", "purged_at"}
+			if err := auditHooks.WithoutAudit(tx).Model(&user).Select(anonymized).Updates(&user).Error; err != nil {
+				return err
+			}
+			return auditHooks.Record(tx, auditDomain.AuditActionUpdate, user.TableName(), user.ID, map[string]interface{}{
+				"purged":         true,
+				"changed_fields": anonymized,
+			})
+		})
+		if err != nil {
 			panic(err)
 		}
 
-		return dto.Success("User deleted successfully")
+		return dto.Success("User purged successfully")
 	})
 }
 
@@ -1,6 +1,8 @@
 package service
 
 import (
+	"context"
+
 	"thothix-backend/internal/shared/dto"
 	sharedMiddleware "thothix-backend/internal/shared/middleware"
 	usersDto "thothix-backend/internal/users/dto"
@@ -15,6 +17,11 @@ type UserServiceInterface interface {
 	CreateUser(req *usersDto.CreateUserRequest) *usersDto.CreateUserResponse
 	UpdateUser(userID string, req *usersDto.UpdateUserRequest) *usersDto.UpdateUserResponse
 	DeleteUser(userID string) *usersDto.DeleteUserResponse
+
+	// Lifecycle operations - DeleteUser deactivates, PurgeUser is the only real removal of personal data
+	DeactivateUser(userID string) *usersDto.UpdateUserResponse
+	ReactivateUser(userID string) *usersDto.UpdateUserResponse
+	PurgeUser(userID string) *usersDto.DeleteUserResponse
 }
 
 // ClerkUserServiceInterface defines the contract for Clerk-specific user operations
@@ -23,3 +30,8 @@ type ClerkUserServiceInterface interface {
 	SyncUserFromClerk(req *usersDto.ClerkUserSyncRequest) *usersDto.CreateUserResponse
 	ProcessClerkWebhook(userData *sharedMiddleware.UserWebhookData) *usersDto.ClerkSyncUserResponse
 }
+
+// ContextAwareUserService is implemented by services that can bind their database session to a request context
+type ContextAwareUserService interface {
+	WithContext(ctx context.Context) *UserService
+}
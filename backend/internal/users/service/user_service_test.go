@@ -2,15 +2,21 @@ package service
 
 import (
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/suite"
 	"gorm.io/gorm"
+	auditDomain "thothix-backend/internal/audit/domain"
+	chatDomain "thothix-backend/internal/chat/domain"
+	projectDomain "thothix-backend/internal/project/domain"
 	"thothix-backend/internal/shared/dto"
+	sharedModels "thothix-backend/internal/shared/models"
 	sharedTesting "thothix-backend/internal/shared/testing"
 	"thothix-backend/internal/users/domain"
 	usersDto "thothix-backend/internal/users/dto"
+	workspaceDomain "thothix-backend/internal/workspace/domain"
 )
 
 type UserServiceTestSuite struct {
@@ -23,7 +29,8 @@ func (suite *UserServiceTestSuite) SetupSuite() {
 	suite.container = sharedTesting.GetSharedTestContainer(
 		suite.T(),
 		"users/service",
-		[]interface{}{&domain.User{}},
+		// Membership, role, invitation and audit tables are needed by PurgeUser
+		[]interface{}{&domain.User{}, &chatDomain.ChannelMember{}, &projectDomain.ProjectMember{}, &sharedModels.UserRole{}, &workspaceDomain.Invitation{}, &auditDomain.AuditLog{}},
 	)
 }
 
@@ -274,12 +281,72 @@ func (suite *UserServiceTestSuite) TestDeleteUser_Success() {
 
 		// Assert
 		message := sharedTesting.AssertSuccessWithValue(suite.T(), response.Response)
-		assert.Equal(suite.T(), "User deleted successfully", message)
+		assert.Equal(suite.T(), "User deactivated successfully", message)
 
-		// Verify user is deleted
-		var count int64
-		db.Model(&domain.User{}).Where("id = ?", user.ID).Count(&count)
-		assert.Equal(suite.T(), int64(0), count)
+		// Verify user is kept but deactivated
+		var stored domain.User
+		assert.NoError(suite.T(), db.Where("id = ?", user.ID).First(&stored).Error)
+		assert.False(suite.T(), stored.IsActive())
+	})
+}
+
+func (suite *UserServiceTestSuite) TestReactivateUser_Success() {
+	// Use transaction for test isolation
+	suite.container.WithTransaction(func(db *gorm.DB) {
+		// Arrange - use unique data for this test
+		testName := "TestReactivateUser_Success"
+		user := suite.generateUniqueTestUser(testName)
+		user.ID = uuid.New().String()
+		user.Deactivate(time.Now())
+		err := db.Create(user).Error
+		assert.NoError(suite.T(), err)
+
+		// Create service with transaction DB
+		service := NewUserService(db)
+
+		// Act
+		response := service.ReactivateUser(user.ID)
+
+		// Assert
+		userResponse := sharedTesting.AssertSuccessWithValue(suite.T(), response.Response)
+		assert.True(suite.T(), userResponse.IsActive)
+	})
+}
+
+func (suite *UserServiceTestSuite) TestPurgeUser_AnonymizesUser() {
+	// Use transaction for test isolation
+	suite.container.WithTransaction(func(db *gorm.DB) {
+		// Arrange - use unique data for this test
+		testName := "TestPurgeUser_AnonymizesUser"
+		user := suite.generateUniqueTestUser(testName)
+		user.ID = uuid.New().String()
+		err := db.Create(user).Error
+		assert.NoError(suite.T(), err)
+
+		// Create service with transaction DB
+		service := NewUserService(db)
+
+		// Act
+		response := service.PurgeUser(user.ID)
+
+		// Assert
+		sharedTesting.AssertSuccessWithValue(suite.T(), response.Response)
+
+		var stored domain.User
+		assert.NoError(suite.T(), db.Where("id = ?", user.ID).First(&stored).Error)
+		assert.True(suite.T(), stored.IsPurged())
+		assert.Empty(suite.T(), stored.Email)
+		assert.Equal(suite.T(), domain.PurgedUserName, stored.Name)
+
+		// The purge is recorded without the erased profile
+		var entry auditDomain.AuditLog
+		assert.NoError(suite.T(), db.Where("entity_id = ? AND action = ?", user.ID, auditDomain.AuditActionUpdate).First(&entry).Error)
+		assert.Empty(suite.T(), entry.Before)
+		assert.NotContains(suite.T(), string(entry.After), user.Email)
+
+		// Purged users cannot be reactivated
+		reactivate := service.ReactivateUser(user.ID)
+		sharedTesting.AssertValidationErrorWithCode(suite.T(), reactivate.Response, "USER_PURGED")
 	})
 }
 
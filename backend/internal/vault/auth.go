@@ -0,0 +1,167 @@
+package vault
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/hashicorp/vault/api"
+)
+
+// Auth methods selected by VAULT_AUTH_METHOD
+const (
+	AuthMethodToken      = "token"
+	AuthMethodAppRole    = "approle"
+	AuthMethodKubernetes = "kubernetes"
+)
+
+// defaultServiceAccountTokenFile is where Kubernetes mounts the pod's service account token
+const defaultServiceAccountTokenFile = "/var/run/secrets/kubernetes.io/serviceaccount/token"
+
+// AuthMethod obtains a Vault token; Login sets it on the client and returns the auth secret,
+// or nil when the token cannot be renewed
+type AuthMethod interface {
+	Name() string
+	Login(ctx context.Context, client *api.Client) (*api.Secret, error)
+}
+
+// TokenAuth uses a static token, renewed while Vault allows it
+type TokenAuth struct {
+	Token string
+}
+
+func (a *TokenAuth) Name() string { return AuthMethodToken }
+
+func (a *TokenAuth) Login(ctx context.Context, client *api.Client) (*api.Secret, error) {
+	client.SetToken(a.Token)
+
+	self, err := client.Auth().Token().LookupSelfWithContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up vault token: %w", err)
+	}
+	if renewable, _ := self.TokenIsRenewable(); !renewable {
+		return nil, nil // Root and periodic tokens without a TTL need no watcher
+	}
+
+	// Renewing once yields the auth secret the lifetime watcher works from
+	secret, err := client.Auth().Token().RenewSelfWithContext(ctx, 0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to renew vault token: %w", err)
+	}
+	return secret, nil
+}
+
+// AppRoleAuth logs in with a role ID and secret ID read from files, so they never sit in the environment
+type AppRoleAuth struct {
+	Mount        string
+	RoleIDFile   string
+	SecretIDFile string
+}
+
+func (a *AppRoleAuth) Name() string { return AuthMethodAppRole }
+
+func (a *AppRoleAuth) Login(ctx context.Context, client *api.Client) (*api.Secret, error) {
+	roleID, err := readCredential(a.RoleIDFile)
+	if err != nil {
+		return nil, err
+	}
+	// Secret IDs are re-read on every login so rotated files are picked up
+	secretID, err := readCredential(a.SecretIDFile)
+	if err != nil {
+		return nil, err
+	}
+
+	return login(ctx, client, fmt.Sprintf("auth/%s/login", a.Mount), map[string]interface{}{
+		"role_id":   roleID,
+		"secret_id": secretID,
+	})
+}
+
+// KubernetesAuth logs in with the pod's service account token
+type KubernetesAuth struct {
+	Mount     string
+	Role      string
+	TokenFile string
+}
+
+func (a *KubernetesAuth) Name() string { return AuthMethodKubernetes }
+
+func (a *KubernetesAuth) Login(ctx context.Context, client *api.Client) (*api.Secret, error) {
+	jwt, err := readCredential(a.TokenFile)
+	if err != nil {
+		return nil, err
+	}
+
+	return login(ctx, client, fmt.Sprintf("auth/%s/login", a.Mount), map[string]interface{}{
+		"role": a.Role,
+		"jwt":  jwt,
+	})
+}
+
+// AuthFromEnv builds the auth method selected by VAULT_AUTH_METHOD (default "token")
+func AuthFromEnv() (AuthMethod, error) {
+	switch method := getEnv("VAULT_AUTH_METHOD", AuthMethodToken); method {
+	case AuthMethodToken:
+		token := os.Getenv("VAULT_TOKEN")
+		if token == "" {
+			return nil, fmt.Errorf("VAULT_TOKEN environment variable not set")
+		}
+		return &TokenAuth{Token: token}, nil
+	case AuthMethodAppRole:
+		auth := &AppRoleAuth{
+			Mount:        getEnv("VAULT_APPROLE_MOUNT", "approle"),
+			RoleIDFile:   os.Getenv("VAULT_ROLE_ID_FILE"),
+			SecretIDFile: os.Getenv("VAULT_SECRET_ID_FILE"),
+		}
+		if auth.RoleIDFile == "" || auth.SecretIDFile == "" {
+			return nil, fmt.Errorf("VAULT_ROLE_ID_FILE and VAULT_SECRET_ID_FILE must be set for approle auth")
+		}
+		return auth, nil
+	case AuthMethodKubernetes:
+		auth := &KubernetesAuth{
+			Mount:     getEnv("VAULT_KUBERNETES_MOUNT", "kubernetes"),
+			Role:      os.Getenv("VAULT_KUBERNETES_ROLE"),
+			TokenFile: getEnv("VAULT_KUBERNETES_TOKEN_FILE", defaultServiceAccountTokenFile),
+		}
+		if auth.Role == "" {
+			return nil, fmt.Errorf("VAULT_KUBERNETES_ROLE must be set for kubernetes auth")
+		}
+		return auth, nil
+	default:
+		return nil, fmt.Errorf("unknown VAULT_AUTH_METHOD %q", method)
+	}
+}
+
+// login writes credentials to a login endpoint and sets the issued token on the client
+func login(ctx context.Context, client *api.Client, path string, data map[string]interface{}) (*api.Secret, error) {
+	secret, err := client.Logical().WriteWithContext(ctx, path, data)
+	if err != nil {
+		return nil, fmt.Errorf("vault login failed: %w", err)
+	}
+	if secret == nil || secret.Auth == nil || secret.Auth.ClientToken == "" {
+		return nil, fmt.Errorf("vault login returned no token")
+	}
+	client.SetToken(secret.Auth.ClientToken)
+	return secret, nil
+}
+
+// readCredential reads a credential file, trimming the trailing newline most tools write
+func readCredential(path string) (string, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read vault credential: %w", err)
+	}
+	credential := strings.TrimSpace(string(content))
+	if credential == "" {
+		return "", fmt.Errorf("vault credential file %s is empty", path)
+	}
+	return credential, nil
+}
+
+func getEnv(key, defaultValue string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return defaultValue
+}
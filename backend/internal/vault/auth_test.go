@@ -0,0 +1,102 @@
+package vault
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/vault/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeLoginServer answers login requests on path with a renewable token and records the request body
+func fakeLoginServer(t *testing.T, path string, received *map[string]string) *api.Client {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != path {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_ = json.NewDecoder(r.Body).Decode(received)
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"auth": map[string]interface{}{"client_token": "issued-token", "renewable": true, "lease_duration": 60},
+		})
+	}))
+	t.Cleanup(server.Close)
+
+	config := api.DefaultConfig()
+	config.Address = server.URL
+	client, err := api.NewClient(config)
+	require.NoError(t, err)
+	return client
+}
+
+func writeCredential(t *testing.T, name, content string) string {
+	path := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+func TestAppRoleAuth_Login(t *testing.T) {
+	var received map[string]string
+	client := fakeLoginServer(t, "/v1/auth/approle/login", &received)
+	auth := &AppRoleAuth{
+		Mount:        "approle",
+		RoleIDFile:   writeCredential(t, "role-id", "role-123\n"),
+		SecretIDFile: writeCredential(t, "secret-id", "secret-456\n"),
+	}
+
+	secret, err := auth.Login(context.Background(), client)
+
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"role_id": "role-123", "secret_id": "secret-456"}, received)
+	assert.Equal(t, "issued-token", client.Token())
+	assert.True(t, secret.Auth.Renewable)
+}
+
+func TestAppRoleAuth_EmptyCredentialFile(t *testing.T) {
+	auth := &AppRoleAuth{
+		Mount:        "approle",
+		RoleIDFile:   writeCredential(t, "role-id", "role-123"),
+		SecretIDFile: writeCredential(t, "secret-id", "\n"),
+	}
+
+	_, err := auth.Login(context.Background(), nil)
+
+	assert.ErrorContains(t, err, "is empty")
+}
+
+func TestKubernetesAuth_Login(t *testing.T) {
+	var received map[string]string
+	client := fakeLoginServer(t, "/v1/auth/k8s/login", &received)
+	auth := &KubernetesAuth{Mount: "k8s", Role: "thothix-api", TokenFile: writeCredential(t, "token", "service-account-jwt")}
+
+	_, err := auth.Login(context.Background(), client)
+
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"role": "thothix-api", "jwt": "service-account-jwt"}, received)
+	assert.Equal(t, "issued-token", client.Token())
+}
+
+func TestAuthFromEnv(t *testing.T) {
+	t.Setenv("VAULT_AUTH_METHOD", "approle")
+	t.Setenv("VAULT_ROLE_ID_FILE", "/run/secrets/role-id")
+	t.Setenv("VAULT_SECRET_ID_FILE", "/run/secrets/secret-id")
+
+	auth, err := AuthFromEnv()
+
+	require.NoError(t, err)
+	assert.Equal(t, &AppRoleAuth{Mount: "approle", RoleIDFile: "/run/secrets/role-id", SecretIDFile: "/run/secrets/secret-id"}, auth)
+
+	t.Setenv("VAULT_AUTH_METHOD", "kubernetes")
+	_, err = AuthFromEnv()
+	assert.ErrorContains(t, err, "VAULT_KUBERNETES_ROLE")
+
+	t.Setenv("VAULT_AUTH_METHOD", "ldap")
+	_, err = AuthFromEnv()
+	assert.ErrorContains(t, err, `unknown VAULT_AUTH_METHOD "ldap"`)
+}
@@ -1,9 +1,10 @@
 package vault
 
 import (
+	"context"
 	"fmt"
-	"log"
 	"os"
+	"sync"
 
 	"github.com/hashicorp/vault/api"
 )
@@ -11,8 +12,17 @@ import (
 type Client struct {
 	client *api.Client
 	mount  string
+	auth   AuthMethod
+
+	mu     sync.Mutex
+	secret *api.Secret // Auth secret of the current token, nil when it is not renewable
 }
 
+var (
+	sharedMu sync.Mutex
+	shared   *Client
+)
+
 type DatabaseConfig struct {
 	Host     string `json:"host"`
 	Port     string `json:"port"`
@@ -31,8 +41,13 @@ type AppConfig struct {
 	JWTSecret     string `json:"jwt_secret"`
 	EncryptionKey string `json:"encryption_key"`
 	Environment   string `json:"environment"`
+
+	// Key rotation: the version of EncryptionKey and the "<version>:<key>" pairs it replaced
+	EncryptionKeyVersion  string `json:"encryption_key_version"`
+	EncryptionRetiredKeys string `json:"encryption_retired_keys"`
 }
 
+// NewVaultClient creates a client for VAULT_ADDR and logs in with the method from AuthFromEnv
 func NewVaultClient() (*Client, error) {
 	config := api.DefaultConfig()
 	config.Address = os.Getenv("VAULT_ADDR")
@@ -40,26 +55,52 @@ func NewVaultClient() (*Client, error) {
 		config.Address = "http://localhost:8200"
 	}
 
+	auth, err := AuthFromEnv()
+	if err != nil {
+		return nil, err
+	}
+
+	return NewClient(context.Background(), config, getEnv("VAULT_MOUNT", "thothix"), auth)
+}
+
+// NewClient creates a client for config and logs in with auth
+func NewClient(ctx context.Context, config *api.Config, mount string, auth AuthMethod) (*Client, error) {
 	client, err := api.NewClient(config)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create vault client: %w", err)
 	}
 
-	token := os.Getenv("VAULT_TOKEN")
-	if token == "" {
-		return nil, fmt.Errorf("VAULT_TOKEN environment variable not set")
+	v := &Client{
+		client: client,
+		mount:  mount,
+		auth:   auth,
 	}
-	client.SetToken(token)
+	if err := v.login(ctx); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
 
-	mount := os.Getenv("VAULT_MOUNT")
-	if mount == "" {
-		mount = "thothix"
+// Shared returns the process-wide client, created by NewVaultClient on first use
+// Configuration loading and the token watcher share it, so the service logs in once
+func Shared() (*Client, error) {
+	sharedMu.Lock()
+	defer sharedMu.Unlock()
+	if shared != nil {
+		return shared, nil
 	}
 
-	return &Client{
-		client: client,
-		mount:  mount,
-	}, nil
+	client, err := NewVaultClient()
+	if err != nil {
+		return nil, err
+	}
+	shared = client
+	return shared, nil
+}
+
+// API returns the underlying Vault API client
+func (v *Client) API() *api.Client {
+	return v.client
 }
 
 func (v *Client) GetDatabaseConfig() (*DatabaseConfig, error) {
@@ -78,11 +119,11 @@ func (v *Client) GetDatabaseConfig() (*DatabaseConfig, error) {
 	}
 
 	return &DatabaseConfig{
-		Host:     data["host"].(string),
-		Port:     data["port"].(string),
-		Username: data["username"].(string),
-		Password: data["password"].(string),
-		Database: data["database"].(string),
+		Host:     stringField(data, "host"),
+		Port:     stringField(data, "port"),
+		Username: stringField(data, "username"),
+		Password: stringField(data, "password"),
+		Database: stringField(data, "database"),
 	}, nil
 }
 
@@ -102,9 +143,9 @@ func (v *Client) GetClerkConfig() (*ClerkConfig, error) {
 	}
 
 	return &ClerkConfig{
-		SecretKey:      data["secret_key"].(string),
-		WebhookSecret:  data["webhook_secret"].(string),
-		PublishableKey: data["publishable_key"].(string),
+		SecretKey:      stringField(data, "secret_key"),
+		WebhookSecret:  stringField(data, "webhook_secret"),
+		PublishableKey: stringField(data, "publishable_key"),
 	}, nil
 }
 
@@ -124,58 +165,83 @@ func (v *Client) GetAppConfig() (*AppConfig, error) {
 	}
 
 	return &AppConfig{
-		JWTSecret:     data["jwt_secret"].(string),
-		EncryptionKey: data["encryption_key"].(string),
-		Environment:   data["environment"].(string),
+		JWTSecret:     stringField(data, "jwt_secret"),
+		EncryptionKey: stringField(data, "encryption_key"),
+		Environment:   stringField(data, "environment"),
+
+		EncryptionKeyVersion:  stringField(data, "encryption_key_version"),
+		EncryptionRetiredKeys: stringField(data, "encryption_retired_keys"),
 	}, nil
 }
 
-// LoadConfigFromVault loads all configuration from Vault
-func LoadConfigFromVault() error {
-	useVault := os.Getenv("USE_VAULT")
-	if useVault != "true" {
-		log.Println("Vault integration disabled, using environment variables")
-		return nil
+// stringField returns a string secret field, or "" when it is missing so validation can report it
+func stringField(data map[string]interface{}, key string) string {
+	value, _ := data[key].(string)
+	return value
+}
+
+// HealthCheck verifies the Vault server at VAULT_ADDR is initialized and unsealed
+// It uses the unauthenticated sys/health endpoint, so no token is required
+func HealthCheck(ctx context.Context) error {
+	config := api.DefaultConfig()
+	if os.Getenv("VAULT_ADDR") == "" {
+		config.Address = "http://localhost:8200"
 	}
 
-	log.Println("🔐 Loading configuration from Vault...")
+	client, err := api.NewClient(config)
+	if err != nil {
+		return fmt.Errorf("failed to create vault client: %w", err)
+	}
 
-	vaultClient, err := NewVaultClient()
+	health, err := client.Sys().HealthWithContext(ctx)
 	if err != nil {
-		return fmt.Errorf("failed to initialize vault client: %w", err)
+		return fmt.Errorf("vault unreachable: %w", err)
+	}
+	if !health.Initialized {
+		return fmt.Errorf("vault is not initialized")
+	}
+	if health.Sealed {
+		return fmt.Errorf("vault is sealed")
 	}
+	return nil
+}
 
-	// Load database config
-	dbConfig, err := vaultClient.GetDatabaseConfig()
+// LoadSecrets reads the database, Clerk and app secrets from Vault, keyed by the
+// environment variable each one overrides; config.Load applies them as its top layer
+func LoadSecrets() (map[string]string, error) {
+	vaultClient, err := Shared()
 	if err != nil {
-		return fmt.Errorf("failed to load database config: %w", err)
+		return nil, fmt.Errorf("failed to initialize vault client: %w", err)
 	}
 
-	// Set environment variables from Vault
-	os.Setenv("DB_HOST", dbConfig.Host)
-	os.Setenv("DB_PORT", dbConfig.Port)
-	os.Setenv("DB_USER", dbConfig.Username)
-	os.Setenv("DB_PASSWORD", dbConfig.Password)
-	os.Setenv("DB_NAME", dbConfig.Database)
+	dbConfig, err := vaultClient.GetDatabaseConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load database config: %w", err)
+	}
 
-	// Load Clerk config
 	clerkConfig, err := vaultClient.GetClerkConfig()
 	if err != nil {
-		return fmt.Errorf("failed to load clerk config: %w", err)
+		return nil, fmt.Errorf("failed to load clerk config: %w", err)
 	}
 
-	os.Setenv("CLERK_SECRET_KEY", clerkConfig.SecretKey)
-	os.Setenv("CLERK_WEBHOOK_SECRET", clerkConfig.WebhookSecret)
-
-	// Load app config
 	appConfig, err := vaultClient.GetAppConfig()
 	if err != nil {
-		return fmt.Errorf("failed to load app config: %w", err)
-	}
-
-	os.Setenv("JWT_SECRET", appConfig.JWTSecret)
-	os.Setenv("ENVIRONMENT", appConfig.Environment)
-
-	log.Println("✅ Configuration loaded from Vault successfully")
-	return nil
+		return nil, fmt.Errorf("failed to load app config: %w", err)
+	}
+
+	return map[string]string{
+		"DB_HOST":              dbConfig.Host,
+		"DB_PORT":              dbConfig.Port,
+		"DB_USER":              dbConfig.Username,
+		"DB_PASSWORD":          dbConfig.Password,
+		"DB_NAME":              dbConfig.Database,
+		"CLERK_SECRET_KEY":     clerkConfig.SecretKey,
+		"CLERK_WEBHOOK_SECRET": clerkConfig.WebhookSecret,
+		"JWT_SECRET":           appConfig.JWTSecret,
+		"ENCRYPTION_KEY":       appConfig.EncryptionKey,
+		"ENVIRONMENT":          appConfig.Environment,
+
+		"ENCRYPTION_KEY_VERSION":  appConfig.EncryptionKeyVersion,
+		"ENCRYPTION_RETIRED_KEYS": appConfig.EncryptionRetiredKeys,
+	}, nil
 }
@@ -0,0 +1,112 @@
+package vault
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/vault/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+
+	sharedTesting "thothix-backend/internal/shared/testing"
+)
+
+type VaultClientTestSuite struct {
+	suite.Suite
+	vault *sharedTesting.VaultTestContainer
+}
+
+func (suite *VaultClientTestSuite) SetupSuite() {
+	suite.vault = sharedTesting.NewVaultTestContainer(suite.T())
+
+	// Short-lived AppRole tokens so renewal and re-login happen within the test
+	root := suite.vault.Root
+	suite.Require().NoError(root.Sys().PutPolicy("thothix", `path "secret/data/*" { capabilities = ["read"] }`))
+	suite.Require().NoError(root.Sys().EnableAuthWithOptions("approle", &api.EnableAuthOptions{Type: "approle"}))
+	_, err := root.Logical().Write("auth/approle/role/thothix", map[string]interface{}{
+		"token_policies": "thothix",
+		"token_ttl":      "2s",
+		"token_max_ttl":  "4s",
+	})
+	suite.Require().NoError(err)
+}
+
+// appRoleAuth writes the role's credentials to files, as an orchestrator would mount them
+func (suite *VaultClientTestSuite) appRoleAuth() *AppRoleAuth {
+	root := suite.vault.Root
+	roleID, err := root.Logical().Read("auth/approle/role/thothix/role-id")
+	suite.Require().NoError(err)
+	secretID, err := root.Logical().Write("auth/approle/role/thothix/secret-id", nil)
+	suite.Require().NoError(err)
+
+	return &AppRoleAuth{
+		Mount:        "approle",
+		RoleIDFile:   writeCredential(suite.T(), "role-id", roleID.Data["role_id"].(string)),
+		SecretIDFile: writeCredential(suite.T(), "secret-id", secretID.Data["secret_id"].(string)),
+	}
+}
+
+func (suite *VaultClientTestSuite) TestAppRole_ReadsSecrets() {
+	// Arrange
+	_, err := suite.vault.Root.KVv2("secret").Put(context.Background(), "database", map[string]interface{}{
+		"host": "db", "port": "5432", "username": "thothix", "password": "pw", "database": "thothix-db",
+	})
+	suite.Require().NoError(err)
+
+	client, err := NewClient(context.Background(), suite.vault.ClientConfig(), "secret", suite.appRoleAuth())
+	suite.Require().NoError(err)
+
+	// Act
+	dbConfig, err := client.GetDatabaseConfig()
+
+	// Assert
+	suite.Require().NoError(err)
+	assert.Equal(suite.T(), "db", dbConfig.Host)
+	assert.Equal(suite.T(), "thothix", dbConfig.Username)
+	assert.Equal(suite.T(), "thothix-db", dbConfig.Database)
+}
+
+func (suite *VaultClientTestSuite) TestWatchToken_LogsInAgainAfterMaxTTL() {
+	// Arrange
+	client, err := NewClient(context.Background(), suite.vault.ClientConfig(), "secret", suite.appRoleAuth())
+	suite.Require().NoError(err)
+	firstToken := client.API().Token()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// Act
+	go client.WatchToken(ctx)
+
+	// Assert: a new token is issued once the first one reaches its max TTL, and it works
+	assert.Eventually(suite.T(), func() bool {
+		return client.API().Token() != firstToken
+	}, 15*time.Second, 200*time.Millisecond)
+	_, err = client.API().Auth().Token().LookupSelf()
+	assert.NoError(suite.T(), err)
+}
+
+func (suite *VaultClientTestSuite) TestWatchToken_RootTokenIsNotWatched() {
+	// Arrange
+	client, err := NewClient(context.Background(), suite.vault.ClientConfig(), "secret", &TokenAuth{Token: suite.vault.RootToken})
+	suite.Require().NoError(err)
+
+	// Act
+	done := make(chan struct{})
+	go func() {
+		client.WatchToken(context.Background())
+		close(done)
+	}()
+
+	// Assert
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		suite.T().Fatal("watcher kept running for a root token")
+	}
+}
+
+func TestVaultClientTestSuite(t *testing.T) {
+	suite.Run(t, new(VaultClientTestSuite))
+}
@@ -0,0 +1,70 @@
+package vault
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/vault/api"
+)
+
+// DatabaseCredentials is a short-lived database login issued by the database secrets engine
+type DatabaseCredentials struct {
+	Username string
+	Password string
+	Lease    *api.Secret // Lease to renew; Vault drops the login when it expires
+}
+
+// DatabaseCredentials requests new credentials for role from the database secrets engine at mount
+func (v *Client) DatabaseCredentials(ctx context.Context, mount, role string) (*DatabaseCredentials, error) {
+	secret, err := v.client.Logical().ReadWithContext(ctx, fmt.Sprintf("%s/creds/%s", mount, role))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read database credentials: %w", err)
+	}
+	if secret == nil || secret.Data == nil {
+		return nil, fmt.Errorf("no database credentials returned for role %s", role)
+	}
+
+	credentials := &DatabaseCredentials{
+		Username: stringField(secret.Data, "username"),
+		Password: stringField(secret.Data, "password"),
+		Lease:    secret,
+	}
+	if credentials.Username == "" || credentials.Password == "" {
+		return nil, fmt.Errorf("incomplete database credentials returned for role %s", role)
+	}
+	return credentials, nil
+}
+
+// WatchLease renews lease until it can no longer be extended, which the lifetime watcher reports
+// shortly before expiry so there is time to replace it. It returns nil then, or ctx.Err() when cancelled
+func (v *Client) WatchLease(ctx context.Context, lease *api.Secret) error {
+	// The watcher gives up at once on leases that cannot be renewed, so replace them at two thirds of their TTL
+	if !lease.Renewable {
+		timer := time.NewTimer(time.Duration(lease.LeaseDuration) * time.Second * 2 / 3)
+		defer timer.Stop()
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-timer.C:
+			return nil
+		}
+	}
+
+	watcher, err := v.client.NewLifetimeWatcher(&api.LifetimeWatcherInput{Secret: lease})
+	if err != nil {
+		return fmt.Errorf("failed to create lease watcher: %w", err)
+	}
+	go watcher.Start()
+	defer watcher.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-watcher.DoneCh():
+			return nil
+		case <-watcher.RenewCh():
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package vault
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/vault/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeCredentialsServer answers reads of database/creds/app with data
+func fakeCredentialsServer(t *testing.T, data map[string]interface{}) *Client {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/database/creds/app" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"lease_id": "database/creds/app/abc", "renewable": true, "lease_duration": 3600, "data": data,
+		})
+	}))
+	t.Cleanup(server.Close)
+
+	config := api.DefaultConfig()
+	config.Address = server.URL
+	client, err := api.NewClient(config)
+	require.NoError(t, err)
+	return &Client{client: client}
+}
+
+func TestDatabaseCredentials(t *testing.T) {
+	client := fakeCredentialsServer(t, map[string]interface{}{"username": "v-app-123", "password": "generated"})
+
+	credentials, err := client.DatabaseCredentials(context.Background(), "database", "app")
+
+	require.NoError(t, err)
+	assert.Equal(t, "v-app-123", credentials.Username)
+	assert.Equal(t, "generated", credentials.Password)
+	assert.Equal(t, "database/creds/app/abc", credentials.Lease.LeaseID)
+	assert.Equal(t, 3600, credentials.Lease.LeaseDuration)
+}
+
+func TestDatabaseCredentials_Incomplete(t *testing.T) {
+	client := fakeCredentialsServer(t, map[string]interface{}{"username": "v-app-123"})
+
+	_, err := client.DatabaseCredentials(context.Background(), "database", "app")
+
+	assert.ErrorContains(t, err, "incomplete database credentials")
+}
+
+func TestWatchLease_NonRenewableExpires(t *testing.T) {
+	client := &Client{}
+	lease := &api.Secret{LeaseID: "database/creds/app/abc", LeaseDuration: 0}
+
+	err := client.WatchLease(context.Background(), lease)
+
+	assert.NoError(t, err)
+}
+
+func TestWatchLease_Cancelled(t *testing.T) {
+	client := &Client{}
+	lease := &api.Secret{LeaseID: "database/creds/app/abc", LeaseDuration: 3600}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	err := client.WatchLease(ctx, lease)
+
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
@@ -0,0 +1,109 @@
+package vault
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/hashicorp/vault/api"
+
+	"thothix-backend/internal/shared/logging"
+)
+
+const (
+	// Backoff between failed logins
+	minLoginBackoff = time.Second
+	maxLoginBackoff = time.Minute
+)
+
+// login authenticates with the client's auth method and stores the auth secret for the watcher
+func (v *Client) login(ctx context.Context) error {
+	secret, err := v.auth.Login(ctx, v.client)
+	if err != nil {
+		return fmt.Errorf("vault %s auth failed: %w", v.auth.Name(), err)
+	}
+
+	v.mu.Lock()
+	v.secret = secret
+	v.mu.Unlock()
+	return nil
+}
+
+// currentSecret returns the auth secret of the current token
+func (v *Client) currentSecret() *api.Secret {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	return v.secret
+}
+
+// WatchToken keeps the token alive until ctx is cancelled: it renews the token while Vault
+// allows it and logs in again once renewal fails or the token reaches its maximum TTL
+// Tokens that cannot be renewed, such as root tokens, are left alone
+func (v *Client) WatchToken(ctx context.Context) {
+	logger := logging.FromContext(ctx).With(slog.String("auth_method", v.auth.Name()))
+
+	for {
+		secret := v.currentSecret()
+		if secret == nil {
+			logger.Info("Vault token is not renewable, token watcher stopped")
+			return
+		}
+
+		watcher, err := v.client.NewLifetimeWatcher(&api.LifetimeWatcherInput{Secret: secret})
+		if err != nil {
+			logger.Error("Failed to create vault token watcher", slog.Any("error", err))
+			return
+		}
+		go watcher.Start()
+
+		if !v.watch(ctx, watcher, logger) {
+			return
+		}
+		if !v.relogin(ctx, logger) {
+			return
+		}
+	}
+}
+
+// watch follows renewals until the watcher is done; it returns false when ctx was cancelled
+func (v *Client) watch(ctx context.Context, watcher *api.LifetimeWatcher, logger *slog.Logger) bool {
+	defer watcher.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return false
+		case err := <-watcher.DoneCh():
+			if err != nil {
+				logger.Warn("Vault token renewal failed", slog.Any("error", err))
+			} else {
+				logger.Info("Vault token reached its maximum TTL")
+			}
+			return true
+		case renewal := <-watcher.RenewCh():
+			if renewal.Secret != nil && renewal.Secret.Auth != nil {
+				logger.Debug("Vault token renewed", slog.Int("lease_seconds", renewal.Secret.Auth.LeaseDuration))
+			}
+		}
+	}
+}
+
+// relogin retries the login with exponential backoff; it returns false when ctx was cancelled
+func (v *Client) relogin(ctx context.Context, logger *slog.Logger) bool {
+	backoff := minLoginBackoff
+	for {
+		err := v.login(ctx)
+		if err == nil {
+			logger.Info("Logged in to vault again")
+			return true
+		}
+		logger.Error("Vault login failed", slog.Any("error", err), slog.Duration("retry_in", backoff))
+
+		select {
+		case <-ctx.Done():
+			return false
+		case <-time.After(backoff):
+		}
+		backoff = min(backoff*2, maxLoginBackoff)
+	}
+}
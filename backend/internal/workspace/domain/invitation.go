@@ -0,0 +1,20 @@
+package domain
+
+import (
+	commonModels "thothix-backend/internal/common/models"
+	sharedModels "thothix-backend/internal/shared/models"
+)
+
+// Invitation offers a user a role in a workspace
+// The membership is only created when the invited user accepts; CreatedBy records who sent the invitation
+type Invitation struct {
+	commonModels.BaseModel
+	WorkspaceID string                `json:"workspace_id" gorm:"uniqueIndex:idx_invitation_workspace_user"`
+	UserID      string                `json:"user_id" gorm:"uniqueIndex:idx_invitation_workspace_user;index"`
+	Role        sharedModels.RoleType `json:"role"`
+}
+
+// TableName specifies the table name for the Invitation model
+func (Invitation) TableName() string {
+	return "workspace_invitations"
+}
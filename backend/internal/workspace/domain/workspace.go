@@ -0,0 +1,19 @@
+package domain
+
+import (
+	commonModels "thothix-backend/internal/common/models"
+)
+
+// Workspace is a tenant owning projects, channels and custom commands
+// Users join a workspace through a UserRole membership carrying their role in it
+type Workspace struct {
+	commonModels.BaseModel
+	Name       string  `json:"name"`
+	Slug       string  `json:"slug" gorm:"uniqueIndex"`
+	ClerkOrgID *string `json:"clerk_org_id,omitempty" gorm:"uniqueIndex"` // Clerk organization mapped to this workspace
+}
+
+// TableName specifies the table name for the Workspace model
+func (Workspace) TableName() string {
+	return "workspaces"
+}
@@ -0,0 +1,80 @@
+package dto
+
+import (
+	"thothix-backend/internal/shared/dto"
+)
+
+// === Workspace DTOs ===
+
+// WorkspaceDto represents a workspace in API responses, with the caller's role in it
+type WorkspaceDto struct {
+	ID         string `json:"id"`
+	Name       string `json:"name"`
+	Slug       string `json:"slug"`
+	ClerkOrgID string `json:"clerk_org_id,omitempty"`
+	Role       string `json:"role,omitempty"`
+	CreatedAt  string `json:"created_at"`
+}
+
+// CreateWorkspaceRequest represents a request to create a workspace
+// The slug is derived from the name when omitted
+type CreateWorkspaceRequest struct {
+	Name       string  `json:"name" binding:"required"`
+	Slug       string  `json:"slug"`
+	ClerkOrgID *string `json:"clerk_org_id,omitempty"`
+}
+
+// InvitationDto represents a pending invitation to join a workspace in API responses
+type InvitationDto struct {
+	ID            string `json:"id"`
+	WorkspaceID   string `json:"workspace_id"`
+	WorkspaceName string `json:"workspace_name,omitempty"`
+	UserID        string `json:"user_id"`
+	Role          string `json:"role"`
+	InvitedBy     string `json:"invited_by,omitempty"`
+	CreatedAt     string `json:"created_at"`
+}
+
+// WorkspaceResponse wraps a single WorkspaceDto response
+type WorkspaceResponse struct {
+	*dto.Response[*WorkspaceDto]
+}
+
+func NewWorkspaceResponse(producer func() dto.Validation[*WorkspaceDto]) *WorkspaceResponse {
+	return &WorkspaceResponse{
+		Response: dto.NewResponse(producer),
+	}
+}
+
+// WorkspaceListResponse wraps a list of WorkspaceDto
+type WorkspaceListResponse struct {
+	*dto.Response[[]WorkspaceDto]
+}
+
+func NewWorkspaceListResponse(producer func() dto.Validation[[]WorkspaceDto]) *WorkspaceListResponse {
+	return &WorkspaceListResponse{
+		Response: dto.NewResponse(producer),
+	}
+}
+
+// InvitationResponse wraps a single InvitationDto response
+type InvitationResponse struct {
+	*dto.Response[*InvitationDto]
+}
+
+func NewInvitationResponse(producer func() dto.Validation[*InvitationDto]) *InvitationResponse {
+	return &InvitationResponse{
+		Response: dto.NewResponse(producer),
+	}
+}
+
+// InvitationListResponse wraps the pending invitations of the current user
+type InvitationListResponse struct {
+	*dto.Response[[]InvitationDto]
+}
+
+func NewInvitationListResponse(producer func() dto.Validation[[]InvitationDto]) *InvitationListResponse {
+	return &InvitationListResponse{
+		Response: dto.NewResponse(producer),
+	}
+}
@@ -0,0 +1,24 @@
+package handlers
+
+import (
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"thothix-backend/internal/middleware"
+	"thothix-backend/internal/workspace/service"
+)
+
+// RegisterWorkspaceRoutes registers the workspace routes
+// These routes are not scoped to a current workspace; members and roles are managed through /roles,
+// where admins invite users who then accept here
+func RegisterWorkspaceRoutes(router *gin.RouterGroup, db *gorm.DB) {
+	workspaceService := service.NewWorkspaceService(db)
+	workspaceHandler := NewWorkspaceHandler(workspaceService)
+
+	workspaces := router.Group("/workspaces")
+	workspaces.GET("", workspaceHandler.GetMyWorkspaces)
+	workspaces.GET("/invitations", workspaceHandler.GetMyInvitations)
+	workspaces.POST("/invitations/:id/accept", workspaceHandler.AcceptInvitation)
+	workspaces.DELETE("/invitations/:id", workspaceHandler.DeclineInvitation)
+	workspaces.GET("/:id", workspaceHandler.GetWorkspace)
+	workspaces.POST("", middleware.RequirePlatformAdmin(db), workspaceHandler.CreateWorkspace)
+}
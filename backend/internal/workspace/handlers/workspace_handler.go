@@ -0,0 +1,272 @@
+package handlers
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"thothix-backend/internal/shared/constants"
+	"thothix-backend/internal/shared/dto"
+	"thothix-backend/internal/shared/handlers"
+	workspaceDto "thothix-backend/internal/workspace/dto"
+	"thothix-backend/internal/workspace/service"
+)
+
+type WorkspaceHandler struct {
+	workspaceService service.WorkspaceServiceInterface
+}
+
+func NewWorkspaceHandler(workspaceService service.WorkspaceServiceInterface) *WorkspaceHandler {
+	return &WorkspaceHandler{
+		workspaceService: workspaceService,
+	}
+}
+
+// GetMyWorkspaces godoc
+// @Summary List my workspaces
+// @Description List the workspaces the authenticated user belongs to, with their role in each
+// @Tags workspaces
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} workspaceDto.WorkspaceDto
+// @Failure 500 {object} dto.ErrorViewModel
+// @Router /workspaces [get]
+func (h *WorkspaceHandler) GetMyWorkspaces(c *gin.Context) {
+	wrapper := handlers.WrapContext(c)
+
+	response := h.scopedService(c).GetMyWorkspaces()
+
+	response.Match(
+		// Exception case
+		func(err error) interface{} {
+			wrapper.SystemErrorResponse(err, "Failed to retrieve workspaces")
+			return nil
+		},
+		// Success case
+		func(result []workspaceDto.WorkspaceDto) interface{} {
+			wrapper.SuccessResponse(result)
+			return nil
+		},
+		// Validation failure case
+		func(errors []dto.Error) interface{} {
+			wrapper.ValidationErrorResponse(errors, "Workspace list validation failed")
+			return nil
+		},
+	)
+}
+
+// GetWorkspace godoc
+// @Summary Get workspace by ID
+// @Description Get a workspace the authenticated user belongs to
+// @Tags workspaces
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Workspace ID"
+// @Success 200 {object} workspaceDto.WorkspaceDto
+// @Failure 404 {object} dto.ErrorViewModel
+// @Failure 500 {object} dto.ErrorViewModel
+// @Router /workspaces/{id} [get]
+func (h *WorkspaceHandler) GetWorkspace(c *gin.Context) {
+	wrapper := handlers.WrapContext(c)
+	workspaceID := c.Param("id")
+
+	response := h.scopedService(c).GetWorkspace(workspaceID)
+
+	response.Match(
+		// Exception case
+		func(err error) interface{} {
+			wrapper.SystemErrorResponse(err, "Failed to retrieve workspace with ID: %s", workspaceID)
+			return nil
+		},
+		// Success case
+		func(result *workspaceDto.WorkspaceDto) interface{} {
+			wrapper.SuccessResponse(result)
+			return nil
+		},
+		// Validation failure case
+		func(errors []dto.Error) interface{} {
+			if len(errors) > 0 && errors[0].Code == constants.NotFoundError {
+				wrapper.NotFoundErrorResponse("Workspace", workspaceID)
+			} else {
+				wrapper.ValidationErrorResponse(errors, "Workspace retrieval validation failed for ID: %s", workspaceID)
+			}
+			return nil
+		},
+	)
+}
+
+// CreateWorkspace godoc
+// @Summary Create a workspace
+// @Description Create a workspace, optionally mapped to a Clerk organization. The creator becomes its admin. Platform admin only
+// @Tags workspaces
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param workspace body workspaceDto.CreateWorkspaceRequest true "Workspace data"
+// @Success 201 {object} workspaceDto.WorkspaceDto
+// @Failure 400 {object} dto.ErrorViewModel
+// @Failure 409 {object} dto.ErrorViewModel
+// @Failure 500 {object} dto.ErrorViewModel
+// @Router /workspaces [post]
+func (h *WorkspaceHandler) CreateWorkspace(c *gin.Context) {
+	wrapper := handlers.WrapContext(c)
+
+	var request workspaceDto.CreateWorkspaceRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		wrapper.BadRequestErrorResponse("Invalid request payload")
+		return
+	}
+
+	response := h.scopedService(c).CreateWorkspace(&request)
+
+	response.Match(
+		// Exception case
+		func(err error) interface{} {
+			wrapper.SystemErrorResponse(err, "Failed to create workspace")
+			return nil
+		},
+		// Success case
+		func(result *workspaceDto.WorkspaceDto) interface{} {
+			wrapper.CreatedResponse(result)
+			return nil
+		},
+		// Validation failure case
+		func(errors []dto.Error) interface{} {
+			if len(errors) > 0 && errors[0].Code == constants.ConflictError {
+				wrapper.ConflictErrorResponse(errors[0].Message)
+			} else {
+				wrapper.ValidationErrorResponse(errors, "Workspace creation validation failed")
+			}
+			return nil
+		},
+	)
+}
+
+// GetMyInvitations godoc
+// @Summary List my workspace invitations
+// @Description List the pending invitations of the authenticated user to join a workspace, most recent first
+// @Tags workspaces
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} workspaceDto.InvitationDto
+// @Failure 500 {object} dto.ErrorViewModel
+// @Router /workspaces/invitations [get]
+func (h *WorkspaceHandler) GetMyInvitations(c *gin.Context) {
+	wrapper := handlers.WrapContext(c)
+
+	response := h.scopedService(c).GetMyInvitations()
+
+	response.Match(
+		// Exception case
+		func(err error) interface{} {
+			wrapper.SystemErrorResponse(err, "Failed to retrieve invitations")
+			return nil
+		},
+		// Success case
+		func(result []workspaceDto.InvitationDto) interface{} {
+			wrapper.SuccessResponse(result)
+			return nil
+		},
+		// Validation failure case
+		func(errors []dto.Error) interface{} {
+			respondWithFailure(wrapper, errors, "Invitation", "", "Invitation list validation failed")
+			return nil
+		},
+	)
+}
+
+// AcceptInvitation godoc
+// @Summary Accept a workspace invitation
+// @Description Join the workspace of an invitation addressed to the authenticated user, with the role it offers
+// @Tags workspaces
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Invitation ID"
+// @Success 200 {object} workspaceDto.WorkspaceDto
+// @Failure 404 {object} dto.ErrorViewModel
+// @Failure 500 {object} dto.ErrorViewModel
+// @Router /workspaces/invitations/{id}/accept [post]
+func (h *WorkspaceHandler) AcceptInvitation(c *gin.Context) {
+	wrapper := handlers.WrapContext(c)
+	invitationID := c.Param("id")
+
+	response := h.scopedService(c).AcceptInvitation(invitationID)
+
+	response.Match(
+		// Exception case
+		func(err error) interface{} {
+			wrapper.SystemErrorResponse(err, "Failed to accept invitation: %s", invitationID)
+			return nil
+		},
+		// Success case
+		func(result *workspaceDto.WorkspaceDto) interface{} {
+			wrapper.SuccessResponse(result)
+			return nil
+		},
+		// Validation failure case
+		func(errors []dto.Error) interface{} {
+			respondWithFailure(wrapper, errors, "Invitation", invitationID, "Invitation acceptance validation failed")
+			return nil
+		},
+	)
+}
+
+// DeclineInvitation godoc
+// @Summary Decline a workspace invitation
+// @Description Discard an invitation addressed to the authenticated user
+// @Tags workspaces
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Invitation ID"
+// @Success 200 {object} dto.ErrorViewModel
+// @Failure 404 {object} dto.ErrorViewModel
+// @Failure 500 {object} dto.ErrorViewModel
+// @Router /workspaces/invitations/{id} [delete]
+func (h *WorkspaceHandler) DeclineInvitation(c *gin.Context) {
+	wrapper := handlers.WrapContext(c)
+	invitationID := c.Param("id")
+
+	response := h.scopedService(c).DeclineInvitation(invitationID)
+
+	response.Match(
+		// Exception case
+		func(err error) interface{} {
+			wrapper.SystemErrorResponse(err, "Failed to decline invitation: %s", invitationID)
+			return nil
+		},
+		// Success case
+		func(result *workspaceDto.InvitationDto) interface{} {
+			wrapper.NoContentResponse()
+			return nil
+		},
+		// Validation failure case
+		func(errors []dto.Error) interface{} {
+			respondWithFailure(wrapper, errors, "Invitation", invitationID, "Invitation decline validation failed")
+			return nil
+		},
+	)
+}
+
+// respondWithFailure maps service errors to not found, unauthorized or validation responses
+func respondWithFailure(wrapper *handlers.ContextWrapper, errors []dto.Error, resource, identifier, logMessage string) {
+	switch {
+	case len(errors) > 0 && errors[0].Code == constants.NotFoundError:
+		wrapper.NotFoundErrorResponse(resource, identifier)
+	case len(errors) > 0 && errors[0].Code == constants.UnauthorizedError:
+		wrapper.UnauthorizedErrorResponse(errors[0].Message)
+	default:
+		wrapper.ValidationErrorResponse(errors, "%s", logMessage)
+	}
+}
+
+// scopedService binds the service to the request context when supported,
+// so workspaces are resolved for, and attributed to, the current user
+func (h *WorkspaceHandler) scopedService(c *gin.Context) service.WorkspaceServiceInterface {
+	if aware, ok := h.workspaceService.(service.ContextAwareWorkspaceService); ok {
+		return aware.WithContext(c.Request.Context())
+	}
+	return h.workspaceService
+}
@@ -0,0 +1,183 @@
+package handlers
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"thothix-backend/internal/shared/dto"
+	workspaceDto "thothix-backend/internal/workspace/dto"
+)
+
+// MockWorkspaceService is a mock implementation of the WorkspaceService
+type MockWorkspaceService struct {
+	mock.Mock
+}
+
+func (m *MockWorkspaceService) GetMyWorkspaces() *workspaceDto.WorkspaceListResponse {
+	args := m.Called()
+	return args.Get(0).(*workspaceDto.WorkspaceListResponse)
+}
+
+func (m *MockWorkspaceService) GetWorkspace(workspaceID string) *workspaceDto.WorkspaceResponse {
+	args := m.Called(workspaceID)
+	return args.Get(0).(*workspaceDto.WorkspaceResponse)
+}
+
+func (m *MockWorkspaceService) CreateWorkspace(req *workspaceDto.CreateWorkspaceRequest) *workspaceDto.WorkspaceResponse {
+	args := m.Called(req)
+	return args.Get(0).(*workspaceDto.WorkspaceResponse)
+}
+
+func (m *MockWorkspaceService) GetMyInvitations() *workspaceDto.InvitationListResponse {
+	args := m.Called()
+	return args.Get(0).(*workspaceDto.InvitationListResponse)
+}
+
+func (m *MockWorkspaceService) AcceptInvitation(invitationID string) *workspaceDto.WorkspaceResponse {
+	args := m.Called(invitationID)
+	return args.Get(0).(*workspaceDto.WorkspaceResponse)
+}
+
+func (m *MockWorkspaceService) DeclineInvitation(invitationID string) *workspaceDto.InvitationResponse {
+	args := m.Called(invitationID)
+	return args.Get(0).(*workspaceDto.InvitationResponse)
+}
+
+type WorkspaceHandlerTestSuite struct {
+	suite.Suite
+	mockService *MockWorkspaceService
+	router      *gin.Engine
+}
+
+func (suite *WorkspaceHandlerTestSuite) SetupSuite() {
+	gin.SetMode(gin.TestMode)
+}
+
+func (suite *WorkspaceHandlerTestSuite) SetupTest() {
+	suite.mockService = new(MockWorkspaceService)
+	handler := NewWorkspaceHandler(suite.mockService)
+
+	suite.router = gin.New()
+	suite.router.GET("/workspaces", handler.GetMyWorkspaces)
+	suite.router.GET("/workspaces/invitations", handler.GetMyInvitations)
+	suite.router.POST("/workspaces/invitations/:id/accept", handler.AcceptInvitation)
+	suite.router.GET("/workspaces/:id", handler.GetWorkspace)
+	suite.router.POST("/workspaces", handler.CreateWorkspace)
+}
+
+func (suite *WorkspaceHandlerTestSuite) TestGetMyWorkspaces_Success() {
+	// Arrange
+	mockResponse := workspaceDto.NewWorkspaceListResponse(func() dto.Validation[[]workspaceDto.WorkspaceDto] {
+		return dto.Success([]workspaceDto.WorkspaceDto{
+			{ID: "workspace-1", Name: "Acme", Slug: "acme", Role: "admin"},
+			{ID: "workspace-2", Name: "Globex", Slug: "globex", Role: "user"},
+		})
+	})
+
+	suite.mockService.On("GetMyWorkspaces").Return(mockResponse)
+
+	// Act
+	req, _ := http.NewRequest("GET", "/workspaces", nil)
+	w := httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(suite.T(), http.StatusOK, w.Code)
+	assert.Contains(suite.T(), w.Body.String(), "acme")
+	assert.Contains(suite.T(), w.Body.String(), "globex")
+}
+
+func (suite *WorkspaceHandlerTestSuite) TestGetWorkspace_NotFound() {
+	// Arrange
+	mockResponse := workspaceDto.NewWorkspaceResponse(func() dto.Validation[*workspaceDto.WorkspaceDto] {
+		return dto.Invalid[*workspaceDto.WorkspaceDto](dto.NewError("NOT_FOUND", "Workspace not found", nil))
+	})
+
+	suite.mockService.On("GetWorkspace", "workspace-1").Return(mockResponse)
+
+	// Act
+	req, _ := http.NewRequest("GET", "/workspaces/workspace-1", nil)
+	w := httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(suite.T(), http.StatusNotFound, w.Code)
+}
+
+func (suite *WorkspaceHandlerTestSuite) TestCreateWorkspace_Conflict() {
+	// Arrange
+	mockResponse := workspaceDto.NewWorkspaceResponse(func() dto.Validation[*workspaceDto.WorkspaceDto] {
+		return dto.Invalid[*workspaceDto.WorkspaceDto](dto.NewError("CONFLICT", "A workspace with this slug or organization already exists", nil))
+	})
+
+	suite.mockService.On("CreateWorkspace", mock.AnythingOfType("*dto.CreateWorkspaceRequest")).Return(mockResponse)
+
+	// Act
+	req, _ := http.NewRequest("POST", "/workspaces", bytes.NewBufferString(`{"name":"Acme"}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(suite.T(), http.StatusConflict, w.Code)
+}
+
+func (suite *WorkspaceHandlerTestSuite) TestCreateWorkspace_MissingName() {
+	// Act
+	req, _ := http.NewRequest("POST", "/workspaces", bytes.NewBufferString(`{"slug":"acme"}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(suite.T(), http.StatusBadRequest, w.Code)
+	suite.mockService.AssertNotCalled(suite.T(), "CreateWorkspace", mock.Anything)
+}
+
+func (suite *WorkspaceHandlerTestSuite) TestGetMyInvitations_Success() {
+	// Arrange
+	mockResponse := workspaceDto.NewInvitationListResponse(func() dto.Validation[[]workspaceDto.InvitationDto] {
+		return dto.Success([]workspaceDto.InvitationDto{
+			{ID: "invitation-1", WorkspaceID: "workspace-1", WorkspaceName: "Acme", Role: "user"},
+		})
+	})
+
+	suite.mockService.On("GetMyInvitations").Return(mockResponse)
+
+	// Act
+	req, _ := http.NewRequest("GET", "/workspaces/invitations", nil)
+	w := httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(suite.T(), http.StatusOK, w.Code)
+	assert.Contains(suite.T(), w.Body.String(), "invitation-1")
+	suite.mockService.AssertNotCalled(suite.T(), "GetWorkspace", mock.Anything)
+}
+
+func (suite *WorkspaceHandlerTestSuite) TestAcceptInvitation_NotFound() {
+	// Arrange
+	mockResponse := workspaceDto.NewWorkspaceResponse(func() dto.Validation[*workspaceDto.WorkspaceDto] {
+		return dto.Invalid[*workspaceDto.WorkspaceDto](dto.NewError("NOT_FOUND", "Invitation not found", nil))
+	})
+
+	suite.mockService.On("AcceptInvitation", "invitation-1").Return(mockResponse)
+
+	// Act
+	req, _ := http.NewRequest("POST", "/workspaces/invitations/invitation-1/accept", nil)
+	w := httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(suite.T(), http.StatusNotFound, w.Code)
+}
+
+func TestWorkspaceHandlerTestSuite(t *testing.T) {
+	suite.Run(t, new(WorkspaceHandlerTestSuite))
+}
@@ -0,0 +1,52 @@
+package mappers
+
+import (
+	"time"
+
+	"thothix-backend/internal/workspace/domain"
+	workspaceDto "thothix-backend/internal/workspace/dto"
+)
+
+// WorkspaceMapper handles conversion between Workspace models and DTOs
+type WorkspaceMapper struct{}
+
+// NewWorkspaceMapper creates a new WorkspaceMapper instance
+func NewWorkspaceMapper() *WorkspaceMapper {
+	return &WorkspaceMapper{}
+}
+
+// ModelToDto converts a Workspace model to WorkspaceDto, with the caller's role in it
+func (m *WorkspaceMapper) ModelToDto(workspace *domain.Workspace, role string) *workspaceDto.WorkspaceDto {
+	if workspace == nil {
+		return nil
+	}
+
+	result := &workspaceDto.WorkspaceDto{
+		ID:        workspace.ID,
+		Name:      workspace.Name,
+		Slug:      workspace.Slug,
+		Role:      role,
+		CreatedAt: workspace.CreatedAt.Format(time.RFC3339),
+	}
+	if workspace.ClerkOrgID != nil {
+		result.ClerkOrgID = *workspace.ClerkOrgID
+	}
+	return result
+}
+
+// InvitationToDto converts an Invitation model to InvitationDto, with the name of its workspace when known
+func (m *WorkspaceMapper) InvitationToDto(invitation *domain.Invitation, workspaceName string) *workspaceDto.InvitationDto {
+	if invitation == nil {
+		return nil
+	}
+
+	return &workspaceDto.InvitationDto{
+		ID:            invitation.ID,
+		WorkspaceID:   invitation.WorkspaceID,
+		WorkspaceName: workspaceName,
+		UserID:        invitation.UserID,
+		Role:          string(invitation.Role),
+		InvitedBy:     invitation.CreatedBy,
+		CreatedAt:     invitation.CreatedAt.Format(time.RFC3339),
+	}
+}
@@ -0,0 +1,283 @@
+package service
+
+import (
+	"context"
+	"regexp"
+	"strings"
+
+	"gorm.io/gorm"
+
+	"thothix-backend/internal/shared/constants"
+	"thothix-backend/internal/shared/dto"
+	sharedMiddleware "thothix-backend/internal/shared/middleware"
+	sharedModels "thothix-backend/internal/shared/models"
+	"thothix-backend/internal/workspace/domain"
+	workspaceDto "thothix-backend/internal/workspace/dto"
+	"thothix-backend/internal/workspace/mappers"
+)
+
+// slugSeparators matches the runs of characters replaced by a dash when deriving a slug
+var slugSeparators = regexp.MustCompile(`[^a-z0-9]+`)
+
+type WorkspaceService struct {
+	db     *gorm.DB
+	mapper *mappers.WorkspaceMapper
+}
+
+func NewWorkspaceService(db *gorm.DB) *WorkspaceService {
+	return &WorkspaceService{
+		db:     db,
+		mapper: mappers.NewWorkspaceMapper(),
+	}
+}
+
+// WithContext returns a copy of the service whose database session carries ctx
+func (s *WorkspaceService) WithContext(ctx context.Context) *WorkspaceService {
+	return &WorkspaceService{
+		db:     s.db.WithContext(ctx),
+		mapper: s.mapper,
+	}
+}
+
+// membership pairs a workspace with the caller's role in it
+type membership struct {
+	domain.Workspace
+	Role sharedModels.RoleType
+}
+
+// GetMyWorkspaces lists the workspaces the current user is a member of
+func (s *WorkspaceService) GetMyWorkspaces() *workspaceDto.WorkspaceListResponse {
+	return workspaceDto.NewWorkspaceListResponse(func() dto.Validation[[]workspaceDto.WorkspaceDto] {
+		userID, ok := sharedMiddleware.UserIDFromContext(s.db.Statement.Context)
+		if !ok {
+			return dto.Failure[[]workspaceDto.WorkspaceDto](dto.NewError(constants.UnauthorizedError, "User not authenticated", nil))
+		}
+
+		var memberships []membership
+		if err := s.memberships(userID).Order("workspaces.name").Find(&memberships).Error; err != nil {
+			panic(err)
+		}
+
+		result := make([]workspaceDto.WorkspaceDto, 0, len(memberships))
+		for i := range memberships {
+			result = append(result, *s.mapper.ModelToDto(&memberships[i].Workspace, string(memberships[i].Role)))
+		}
+		return dto.Success(result)
+	})
+}
+
+// GetWorkspace retrieves a workspace the current user is a member of
+// Workspaces the user does not belong to are reported as not found
+func (s *WorkspaceService) GetWorkspace(workspaceID string) *workspaceDto.WorkspaceResponse {
+	return workspaceDto.NewWorkspaceResponse(func() dto.Validation[*workspaceDto.WorkspaceDto] {
+		if workspaceID == "" {
+			return dto.Failure[*workspaceDto.WorkspaceDto](dto.NewError(constants.ValidationError, "Workspace ID cannot be empty", nil))
+		}
+
+		userID, ok := sharedMiddleware.UserIDFromContext(s.db.Statement.Context)
+		if !ok {
+			return dto.Failure[*workspaceDto.WorkspaceDto](dto.NewError(constants.UnauthorizedError, "User not authenticated", nil))
+		}
+
+		var found membership
+		if err := s.memberships(userID).Where("workspaces.id::text = ?", workspaceID).First(&found).Error; err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return dto.Invalid[*workspaceDto.WorkspaceDto](dto.NewError(constants.NotFoundError, "Workspace not found", nil))
+			}
+			panic(err)
+		}
+
+		return dto.Success(s.mapper.ModelToDto(&found.Workspace, string(found.Role)))
+	})
+}
+
+// CreateWorkspace creates a workspace and makes the current user its admin
+func (s *WorkspaceService) CreateWorkspace(req *workspaceDto.CreateWorkspaceRequest) *workspaceDto.WorkspaceResponse {
+	return workspaceDto.NewWorkspaceResponse(func() dto.Validation[*workspaceDto.WorkspaceDto] {
+		if req == nil || strings.TrimSpace(req.Name) == "" {
+			return dto.Failure[*workspaceDto.WorkspaceDto](dto.NewError(constants.ValidationError, "Name is required", nil))
+		}
+
+		userID, ok := sharedMiddleware.UserIDFromContext(s.db.Statement.Context)
+		if !ok {
+			return dto.Failure[*workspaceDto.WorkspaceDto](dto.NewError(constants.UnauthorizedError, "User not authenticated", nil))
+		}
+
+		slug := Slugify(req.Slug)
+		if slug == "" {
+			slug = Slugify(req.Name)
+		}
+		if slug == "" {
+			return dto.Failure[*workspaceDto.WorkspaceDto](dto.NewError(constants.ValidationError, "Slug must contain letters or digits", nil))
+		}
+
+		var count int64
+		query := s.db.Model(&domain.Workspace{}).Where("slug = ?", slug)
+		if req.ClerkOrgID != nil && *req.ClerkOrgID != "" {
+			query = query.Or("clerk_org_id = ?", *req.ClerkOrgID)
+		}
+		if err := query.Count(&count).Error; err != nil {
+			panic(err)
+		}
+		if count > 0 {
+			return dto.Invalid[*workspaceDto.WorkspaceDto](dto.NewError(constants.ConflictError, "A workspace with this slug or organization already exists", nil))
+		}
+
+		workspace := domain.Workspace{
+			Name: strings.TrimSpace(req.Name),
+			Slug: slug,
+		}
+		if req.ClerkOrgID != nil && *req.ClerkOrgID != "" {
+			workspace.ClerkOrgID = req.ClerkOrgID
+		}
+
+		err := s.db.Transaction(func(tx *gorm.DB) error {
+			if err := tx.Create(&workspace).Error; err != nil {
+				return err
+			}
+			resourceType := sharedModels.ResourceTypeWorkspace
+			return tx.Create(&sharedModels.UserRole{
+				ResourceID:   &workspace.ID,
+				ResourceType: &resourceType,
+				UserID:       userID,
+				Role:         sharedModels.RoleAdmin,
+			}).Error
+		})
+		if err != nil {
+			panic(err)
+		}
+
+		return dto.Success(s.mapper.ModelToDto(&workspace, string(sharedModels.RoleAdmin)))
+	})
+}
+
+// invitationWithWorkspace pairs an invitation with the name of its workspace
+type invitationWithWorkspace struct {
+	domain.Invitation
+	WorkspaceName string
+}
+
+// GetMyInvitations lists the pending invitations of the current user, most recent first
+func (s *WorkspaceService) GetMyInvitations() *workspaceDto.InvitationListResponse {
+	return workspaceDto.NewInvitationListResponse(func() dto.Validation[[]workspaceDto.InvitationDto] {
+		userID, ok := sharedMiddleware.UserIDFromContext(s.db.Statement.Context)
+		if !ok {
+			return dto.Failure[[]workspaceDto.InvitationDto](dto.NewError(constants.UnauthorizedError, "User not authenticated", nil))
+		}
+
+		var invitations []invitationWithWorkspace
+		if err := s.db.Table("workspace_invitations").
+			Select("workspace_invitations.*, workspaces.name AS workspace_name").
+			Joins("JOIN workspaces ON workspaces.id::text = workspace_invitations.workspace_id").
+			Where("workspace_invitations.user_id = ?", userID).
+			Order("workspace_invitations.created_at DESC").
+			Find(&invitations).Error; err != nil {
+			panic(err)
+		}
+
+		result := make([]workspaceDto.InvitationDto, 0, len(invitations))
+		for i := range invitations {
+			result = append(result, *s.mapper.InvitationToDto(&invitations[i].Invitation, invitations[i].WorkspaceName))
+		}
+		return dto.Success(result)
+	})
+}
+
+// AcceptInvitation makes the current user a member of the workspace they were invited to, with the offered role
+func (s *WorkspaceService) AcceptInvitation(invitationID string) *workspaceDto.WorkspaceResponse {
+	return workspaceDto.NewWorkspaceResponse(func() dto.Validation[*workspaceDto.WorkspaceDto] {
+		invitation, failure := s.findInvitation(invitationID)
+		if failure != nil {
+			return dto.Failure[*workspaceDto.WorkspaceDto](failure...)
+		}
+
+		var workspace domain.Workspace
+		if err := s.db.Where("id = ?", invitation.WorkspaceID).First(&workspace).Error; err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return dto.Invalid[*workspaceDto.WorkspaceDto](dto.NewError(constants.NotFoundError, "Workspace not found", nil))
+			}
+			panic(err)
+		}
+
+		// A membership created in the meantime keeps its role; the invitation is used up either way
+		err := s.db.Transaction(func(tx *gorm.DB) error {
+			var count int64
+			if err := tx.Model(&sharedModels.UserRole{}).
+				Where("user_id = ? AND resource_type = ? AND resource_id = ?", invitation.UserID, sharedModels.ResourceTypeWorkspace, invitation.WorkspaceID).
+				Count(&count).Error; err != nil {
+				return err
+			}
+			if count == 0 {
+				resourceType := sharedModels.ResourceTypeWorkspace
+				if err := tx.Create(&sharedModels.UserRole{
+					ResourceID:   &invitation.WorkspaceID,
+					ResourceType: &resourceType,
+					UserID:       invitation.UserID,
+					Role:         invitation.Role,
+				}).Error; err != nil {
+					return err
+				}
+			}
+			return tx.Delete(invitation).Error
+		})
+		if err != nil {
+			panic(err)
+		}
+
+		role, err := sharedModels.GetWorkspaceRole(s.db, invitation.UserID, invitation.WorkspaceID)
+		if err != nil {
+			panic(err)
+		}
+		return dto.Success(s.mapper.ModelToDto(&workspace, string(role)))
+	})
+}
+
+// DeclineInvitation discards an invitation of the current user
+func (s *WorkspaceService) DeclineInvitation(invitationID string) *workspaceDto.InvitationResponse {
+	return workspaceDto.NewInvitationResponse(func() dto.Validation[*workspaceDto.InvitationDto] {
+		invitation, failure := s.findInvitation(invitationID)
+		if failure != nil {
+			return dto.Failure[*workspaceDto.InvitationDto](failure...)
+		}
+
+		if err := s.db.Delete(invitation).Error; err != nil {
+			panic(err)
+		}
+		return dto.Success(s.mapper.InvitationToDto(invitation, ""))
+	})
+}
+
+// findInvitation loads an invitation addressed to the current user
+// Invitations of other users are reported as not found
+func (s *WorkspaceService) findInvitation(invitationID string) (*domain.Invitation, []dto.Error) {
+	if invitationID == "" {
+		return nil, []dto.Error{dto.NewError(constants.ValidationError, "Invitation ID cannot be empty", nil)}
+	}
+
+	userID, ok := sharedMiddleware.UserIDFromContext(s.db.Statement.Context)
+	if !ok {
+		return nil, []dto.Error{dto.NewError(constants.UnauthorizedError, "User not authenticated", nil)}
+	}
+
+	var invitation domain.Invitation
+	if err := s.db.Where("id = ? AND user_id = ?", invitationID, userID).First(&invitation).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, []dto.Error{dto.NewError(constants.NotFoundError, "Invitation not found", nil)}
+		}
+		panic(err)
+	}
+	return &invitation, nil
+}
+
+// memberships selects the workspaces of a user together with their role in each
+func (s *WorkspaceService) memberships(userID string) *gorm.DB {
+	return s.db.Table("workspaces").
+		Select("workspaces.*, user_roles.role").
+		Joins("JOIN user_roles ON user_roles.resource_id = workspaces.id::text AND user_roles.resource_type = ?", sharedModels.ResourceTypeWorkspace).
+		Where("user_roles.user_id = ?", userID)
+}
+
+// Slugify lowercases s and replaces runs of other characters with a dash
+func Slugify(s string) string {
+	return strings.Trim(slugSeparators.ReplaceAllString(strings.ToLower(s), "-"), "-")
+}
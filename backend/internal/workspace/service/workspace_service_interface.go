@@ -0,0 +1,22 @@
+package service
+
+import (
+	"context"
+
+	workspaceDto "thothix-backend/internal/workspace/dto"
+)
+
+// WorkspaceServiceInterface defines the contract for workspace operations using Response pattern
+type WorkspaceServiceInterface interface {
+	GetMyWorkspaces() *workspaceDto.WorkspaceListResponse
+	GetWorkspace(workspaceID string) *workspaceDto.WorkspaceResponse
+	CreateWorkspace(req *workspaceDto.CreateWorkspaceRequest) *workspaceDto.WorkspaceResponse
+	GetMyInvitations() *workspaceDto.InvitationListResponse
+	AcceptInvitation(invitationID string) *workspaceDto.WorkspaceResponse
+	DeclineInvitation(invitationID string) *workspaceDto.InvitationResponse
+}
+
+// ContextAwareWorkspaceService is implemented by services that can bind their database session to a request context
+type ContextAwareWorkspaceService interface {
+	WithContext(ctx context.Context) *WorkspaceService
+}
@@ -0,0 +1,180 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/gorm"
+	sharedMiddleware "thothix-backend/internal/shared/middleware"
+	sharedModels "thothix-backend/internal/shared/models"
+	sharedTesting "thothix-backend/internal/shared/testing"
+	usersDomain "thothix-backend/internal/users/domain"
+	"thothix-backend/internal/workspace/domain"
+	workspaceDto "thothix-backend/internal/workspace/dto"
+)
+
+type WorkspaceServiceTestSuite struct {
+	suite.Suite
+	container *sharedTesting.PostgresTestContainer
+}
+
+func (suite *WorkspaceServiceTestSuite) SetupSuite() {
+	suite.container = sharedTesting.GetSharedTestContainer(
+		suite.T(),
+		"workspace/service",
+		[]interface{}{&usersDomain.User{}, &sharedModels.UserRole{}, &domain.Workspace{}, &domain.Invitation{}},
+	)
+}
+
+// createUser stores a user with the given system role
+func (suite *WorkspaceServiceTestSuite) createUser(db *gorm.DB, testName string, role sharedModels.RoleType) *usersDomain.User {
+	user := &usersDomain.User{Email: testName + "@example.com", Name: "User " + testName, SystemRole: role}
+	user.ID = uuid.New().String()
+	assert.NoError(suite.T(), db.Create(user).Error)
+	return user
+}
+
+// serviceFor creates a service acting as the given user
+func (suite *WorkspaceServiceTestSuite) serviceFor(db *gorm.DB, userID string) *WorkspaceService {
+	return NewWorkspaceService(db).WithContext(sharedMiddleware.WithUserID(context.Background(), userID))
+}
+
+func (suite *WorkspaceServiceTestSuite) TestCreateWorkspace_CreatorBecomesAdmin() {
+	suite.container.WithTransaction(func(db *gorm.DB) {
+		// Arrange
+		user := suite.createUser(db, "TestCreateWorkspace_CreatorBecomesAdmin", sharedModels.RoleUser)
+		service := suite.serviceFor(db, user.ID)
+
+		// Act
+		response := service.CreateWorkspace(&workspaceDto.CreateWorkspaceRequest{Name: "Acme Corp"})
+
+		// Assert
+		workspace := sharedTesting.AssertSuccessWithValue(suite.T(), response.Response)
+		assert.Equal(suite.T(), "acme-corp", workspace.Slug)
+		assert.Equal(suite.T(), string(sharedModels.RoleAdmin), workspace.Role)
+
+		role, err := sharedModels.GetWorkspaceRole(db, user.ID, workspace.ID)
+		assert.NoError(suite.T(), err)
+		assert.Equal(suite.T(), sharedModels.RoleAdmin, role)
+	})
+}
+
+func (suite *WorkspaceServiceTestSuite) TestCreateWorkspace_DuplicateSlug() {
+	suite.container.WithTransaction(func(db *gorm.DB) {
+		// Arrange
+		user := suite.createUser(db, "TestCreateWorkspace_DuplicateSlug", sharedModels.RoleAdmin)
+		service := suite.serviceFor(db, user.ID)
+		sharedTesting.AssertSuccessWithValue(suite.T(), service.CreateWorkspace(&workspaceDto.CreateWorkspaceRequest{Name: "Duplicate"}).Response)
+
+		// Act
+		response := service.CreateWorkspace(&workspaceDto.CreateWorkspaceRequest{Name: "Other", Slug: "duplicate"})
+
+		// Assert
+		sharedTesting.AssertValidationErrorWithCode(suite.T(), response.Response, "CONFLICT")
+	})
+}
+
+func (suite *WorkspaceServiceTestSuite) TestGetUserRole_IsPerWorkspace() {
+	suite.container.WithTransaction(func(db *gorm.DB) {
+		// Arrange
+		owner := suite.createUser(db, "TestGetUserRole_IsPerWorkspace_owner", sharedModels.RoleAdmin)
+		member := suite.createUser(db, "TestGetUserRole_IsPerWorkspace_member", sharedModels.RoleUser)
+		service := suite.serviceFor(db, owner.ID)
+		first := sharedTesting.AssertSuccessWithValue(suite.T(), service.CreateWorkspace(&workspaceDto.CreateWorkspaceRequest{Name: "First"}).Response)
+		second := sharedTesting.AssertSuccessWithValue(suite.T(), service.CreateWorkspace(&workspaceDto.CreateWorkspaceRequest{Name: "Second"}).Response)
+
+		resourceType := sharedModels.ResourceTypeWorkspace
+		assert.NoError(suite.T(), db.Create(&sharedModels.UserRole{UserID: member.ID, Role: sharedModels.RoleAdmin, ResourceType: &resourceType, ResourceID: &first.ID}).Error)
+		assert.NoError(suite.T(), db.Create(&sharedModels.UserRole{UserID: member.ID, Role: sharedModels.RoleExternal, ResourceType: &resourceType, ResourceID: &second.ID}).Error)
+
+		// Act
+		firstRole, firstErr := sharedModels.GetUserRole(db.WithContext(sharedMiddleware.WithWorkspaceID(context.Background(), first.ID)), member.ID)
+		secondRole, secondErr := sharedModels.GetUserRole(db.WithContext(sharedMiddleware.WithWorkspaceID(context.Background(), second.ID)), member.ID)
+		systemRole, systemErr := sharedModels.GetUserRole(db, member.ID)
+
+		// Assert
+		assert.NoError(suite.T(), firstErr)
+		assert.NoError(suite.T(), secondErr)
+		assert.NoError(suite.T(), systemErr)
+		assert.Equal(suite.T(), sharedModels.RoleAdmin, firstRole)
+		assert.Equal(suite.T(), sharedModels.RoleExternal, secondRole)
+		assert.Equal(suite.T(), sharedModels.RoleUser, systemRole)
+
+		workspaces := sharedTesting.AssertSuccessWithValue(suite.T(), suite.serviceFor(db, member.ID).GetMyWorkspaces().Response)
+		assert.Len(suite.T(), workspaces, 2)
+	})
+}
+
+func (suite *WorkspaceServiceTestSuite) TestGetWorkspace_NotMember() {
+	suite.container.WithTransaction(func(db *gorm.DB) {
+		// Arrange
+		owner := suite.createUser(db, "TestGetWorkspace_NotMember_owner", sharedModels.RoleAdmin)
+		outsider := suite.createUser(db, "TestGetWorkspace_NotMember_outsider", sharedModels.RoleAdmin)
+		workspace := sharedTesting.AssertSuccessWithValue(suite.T(), suite.serviceFor(db, owner.ID).CreateWorkspace(&workspaceDto.CreateWorkspaceRequest{Name: "Private"}).Response)
+
+		// Act
+		response := suite.serviceFor(db, outsider.ID).GetWorkspace(workspace.ID)
+
+		// Assert
+		sharedTesting.AssertValidationErrorWithCode(suite.T(), response.Response, "NOT_FOUND")
+	})
+}
+
+func (suite *WorkspaceServiceTestSuite) TestAcceptInvitation_CreatesMembership() {
+	suite.container.WithTransaction(func(db *gorm.DB) {
+		// Arrange
+		owner := suite.createUser(db, "TestAcceptInvitation_CreatesMembership_owner", sharedModels.RoleAdmin)
+		invitee := suite.createUser(db, "TestAcceptInvitation_CreatesMembership_invitee", sharedModels.RoleUser)
+		workspace := sharedTesting.AssertSuccessWithValue(suite.T(), suite.serviceFor(db, owner.ID).CreateWorkspace(&workspaceDto.CreateWorkspaceRequest{Name: "Invited"}).Response)
+		invitation := &domain.Invitation{WorkspaceID: workspace.ID, UserID: invitee.ID, Role: sharedModels.RoleManager}
+		assert.NoError(suite.T(), db.Create(invitation).Error)
+
+		// Act
+		othersResponse := suite.serviceFor(db, owner.ID).AcceptInvitation(invitation.ID)
+		invitations := sharedTesting.AssertSuccessWithValue(suite.T(), suite.serviceFor(db, invitee.ID).GetMyInvitations().Response)
+		response := suite.serviceFor(db, invitee.ID).AcceptInvitation(invitation.ID)
+
+		// Assert
+		sharedTesting.AssertValidationErrorWithCode(suite.T(), othersResponse.Response, "NOT_FOUND")
+		assert.Len(suite.T(), invitations, 1)
+		assert.Equal(suite.T(), "Invited", invitations[0].WorkspaceName)
+
+		joined := sharedTesting.AssertSuccessWithValue(suite.T(), response.Response)
+		assert.Equal(suite.T(), string(sharedModels.RoleManager), joined.Role)
+
+		var remaining int64
+		db.Model(&domain.Invitation{}).Where("id = ?", invitation.ID).Count(&remaining)
+		assert.Equal(suite.T(), int64(0), remaining)
+	})
+}
+
+func (suite *WorkspaceServiceTestSuite) TestDeclineInvitation_DoesNotJoin() {
+	suite.container.WithTransaction(func(db *gorm.DB) {
+		// Arrange
+		owner := suite.createUser(db, "TestDeclineInvitation_DoesNotJoin_owner", sharedModels.RoleAdmin)
+		invitee := suite.createUser(db, "TestDeclineInvitation_DoesNotJoin_invitee", sharedModels.RoleUser)
+		workspace := sharedTesting.AssertSuccessWithValue(suite.T(), suite.serviceFor(db, owner.ID).CreateWorkspace(&workspaceDto.CreateWorkspaceRequest{Name: "Declined"}).Response)
+		invitation := &domain.Invitation{WorkspaceID: workspace.ID, UserID: invitee.ID, Role: sharedModels.RoleUser}
+		assert.NoError(suite.T(), db.Create(invitation).Error)
+
+		// Act
+		response := suite.serviceFor(db, invitee.ID).DeclineInvitation(invitation.ID)
+
+		// Assert
+		sharedTesting.AssertSuccessWithValue(suite.T(), response.Response)
+		_, err := sharedModels.GetWorkspaceRole(db, invitee.ID, workspace.ID)
+		assert.ErrorIs(suite.T(), err, gorm.ErrRecordNotFound)
+	})
+}
+
+func TestSlugify(t *testing.T) {
+	assert.Equal(t, "acme-corp", Slugify("  Acme Corp! "))
+	assert.Equal(t, "", Slugify("!!!"))
+}
+
+func TestWorkspaceServiceTestSuite(t *testing.T) {
+	suite.Run(t, new(WorkspaceServiceTestSuite))
+}
@@ -1,12 +1,37 @@
 package main
 
 import (
-	"log"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"text/tabwriter"
+	"time"
 
 	_ "thothix-backend/docs" // Importa i documenti Swagger generati
+	"thothix-backend/internal/buildinfo"
 	"thothix-backend/internal/config"
 	"thothix-backend/internal/database"
+	"thothix-backend/internal/encryption"
+	"thothix-backend/internal/health"
+	"thothix-backend/internal/jobs/queue"
+	"thothix-backend/internal/message/search"
+	"thothix-backend/internal/message/unfurl"
+	"thothix-backend/internal/metrics"
+	moderationDomain "thothix-backend/internal/moderation/domain"
+	"thothix-backend/internal/retention/purger"
+	"thothix-backend/internal/scheduling/scheduler"
+	"thothix-backend/internal/server"
+	"thothix-backend/internal/shared/logging"
 	"thothix-backend/internal/shared/router"
+	"thothix-backend/internal/tracing"
+	"thothix-backend/internal/vault"
 )
 
 // @title Thothix API
@@ -30,26 +55,219 @@ import (
 // @description Type "Bearer" followed by a space and JWT token.
 
 func main() {
-	// Carica configurazione
-	cfg := config.Load()
+	printConfig := flag.Bool("print-config", false, "Print the effective configuration with secrets redacted and exit")
+	flag.Parse()
+
+	// Carica configurazione: defaults < CONFIG_FILE < env < Vault
+	cfg, err := config.Load()
+	if *printConfig && cfg != nil {
+		printSettings(cfg.Dump())
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		return
+	}
+	if err != nil {
+		fatal("Invalid configuration", err)
+	}
+
+	// Structured logging: JSON in production, text elsewhere
+	logging.Setup(cfg.Environment, cfg.LogLevel)
+	slog.Debug("Effective configuration", slog.Any("settings", cfg.Dump()))
+
+	// Tracing: OTLP export when a collector is configured, W3C propagation always
+	shutdownTracing, err := tracing.Setup(context.Background(), cfg.TracingEndpoint, cfg.Environment, buildinfo.Version)
+	if err != nil {
+		fatal("Failed to initialize tracing", err)
+	}
+
+	// Encryption at rest of message content and file metadata
+	keyring, err := encryption.New(cfg)
+	if err != nil {
+		fatal("Failed to initialize encryption", err)
+	}
+	if keyring == nil {
+		slog.Warn("ENCRYPTION_KEY is not set, messages are stored as plain text")
+	}
+	encryption.SetDefault(keyring)
 
 	// Inizializza database
 	db, err := database.Initialize(cfg)
 	if err != nil {
-		log.Fatal("Failed to initialize database:", err)
+		fatal("Failed to initialize database", err)
 	}
 
 	// Esegui migrazioni
 	if err := database.Migrate(db); err != nil {
-		log.Fatal("Failed to migrate database:", err)
+		fatal("Failed to migrate database", err)
+	}
+
+	// Keep the Vault token alive: renew it and log in again when it expires
+	stopVault := func(context.Context) error { return nil }
+	if cfg.UseVault {
+		vaultClient, err := vault.Shared()
+		if err != nil {
+			fatal("Failed to initialize vault client", err)
+		}
+		watchCtx, cancel := context.WithCancel(context.Background())
+		go vaultClient.WatchToken(watchCtx)
+		stopVault = func(context.Context) error {
+			cancel()
+			return nil
+		}
+	}
+
+	// Move values under retired keys, and legacy plain text, to the current key
+	stopReencryption := func(context.Context) error { return nil }
+	if keyring != nil {
+		columns := append(search.EncryptedColumns(keyring), scheduler.EncryptedColumns()...)
+		columns = append(columns, unfurl.EncryptedColumns()...)
+		columns = append(columns, moderationDomain.EncryptedColumns()...)
+		reencryptor := encryption.NewReencryptor(db, keyring, columns...)
+		reencryptCtx, cancel := context.WithCancel(context.Background())
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			reencryptor.Run(reencryptCtx, cfg.ReencryptInterval)
+		}()
+		stopReencryption = func(ctx context.Context) error {
+			cancel()
+			select {
+			case <-done:
+				return nil
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+	}
+
+	// Send scheduled messages and reminders; replicas share the work through row locks
+	schedulerHeartbeat := health.NewHeartbeat(3 * cfg.SchedulerInterval)
+	schedulerCtx, cancelScheduler := context.WithCancel(context.Background())
+	schedulerDone := make(chan struct{})
+	go func() {
+		defer close(schedulerDone)
+		scheduler.NewScheduler(db).Run(schedulerCtx, cfg.SchedulerInterval, schedulerHeartbeat)
+	}()
+	stopScheduler := func(ctx context.Context) error {
+		cancelScheduler()
+		select {
+		case <-schedulerDone:
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	// Delete content past its retention policy, except under a legal hold
+	purgeHeartbeat := health.NewHeartbeat(3 * cfg.RetentionInterval)
+	purgeCtx, cancelPurge := context.WithCancel(context.Background())
+	purgeDone := make(chan struct{})
+	go func() {
+		defer close(purgeDone)
+		purger.NewPurger(db).Run(purgeCtx, cfg.RetentionInterval, purgeHeartbeat)
+	}()
+	stopPurge := func(ctx context.Context) error {
+		cancelPurge()
+		select {
+		case <-purgeDone:
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	// Run background jobs; each queue is limited to its concurrency across replicas
+	jobsHeartbeat := health.NewHeartbeat(3*cfg.JobPollInterval + time.Minute)
+	jobsCtx, cancelJobs := context.WithCancel(context.Background())
+	jobsDone := make(chan struct{})
+	go func() {
+		defer close(jobsDone)
+		queue.NewWorker(db, queue.Default, cfg.JobQueues, cfg.JobPollInterval).Run(jobsCtx, jobsHeartbeat)
+	}()
+	stopJobs := func(ctx context.Context) error {
+		cancelJobs()
+		select {
+		case <-jobsDone:
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	// Readiness checks behind /health/ready
+	checker := health.NewChecker()
+	checker.Add("database", database.PingCheck(db))
+	checker.Add("migrations", database.MigrationsCheck(db))
+	checker.Add("scheduler", schedulerHeartbeat.Check)
+	checker.Add("retention", purgeHeartbeat.Check)
+	checker.Add("jobs", jobsHeartbeat.Check)
+	if cfg.UseVault {
+		checker.Add("vault", vault.HealthCheck)
 	}
 
 	// Inizializza router
-	r := router.Setup(db, cfg)
+	r := router.Setup(db, cfg, checker)
+
+	// Metrics on a separate admin port that is not exposed publicly
+	var metricsServer *http.Server
+	if cfg.MetricsAddr != "" {
+		mux := http.NewServeMux()
+		mux.Handle("/metrics", metrics.Handler())
+		metricsServer = &http.Server{Addr: cfg.MetricsAddr, Handler: mux, ReadHeaderTimeout: cfg.HTTPReadHeaderTimeout}
+		go func() {
+			slog.Info("Metrics server starting", slog.String("addr", cfg.MetricsAddr))
+			if err := metricsServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				slog.Error("Metrics server stopped", slog.Any("error", err))
+			}
+		}()
+	}
 
 	// Avvia server
-	log.Printf("Server starting on port %s", cfg.Port)
-	if err := r.Run(":" + cfg.Port); err != nil {
-		log.Fatal("Failed to start server:", err)
+	srv := server.New(cfg, r)
+	listener, err := net.Listen("tcp", srv.Addr)
+	if err != nil {
+		fatal("Failed to start server", err)
+	}
+	slog.Info("Server starting", slog.String("version", buildinfo.Version), slog.String("commit", buildinfo.Commit), slog.String("port", cfg.Port))
+
+	// SIGTERM drains HTTP, then stops workers and closes the database, in order
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	steps := []server.Step{
+		// Background workers stop here, before the database they use is closed
+		{Name: "scheduler", Stop: stopScheduler},
+		{Name: "retention", Stop: stopPurge},
+		{Name: "jobs", Stop: stopJobs},
+		{Name: "reencryption", Stop: stopReencryption},
+		{Name: "database", Stop: func(context.Context) error { return database.Close(db) }},
+		{Name: "vault", Stop: stopVault},
+	}
+	if metricsServer != nil {
+		steps = append(steps, server.Step{Name: "metrics", Stop: metricsServer.Shutdown})
+	}
+	steps = append(steps, server.Step{Name: "tracing", Stop: shutdownTracing})
+
+	if err := server.Run(ctx, srv, listener, cfg.ShutdownTimeout, steps...); err != nil {
+		fatal("Server stopped with errors", err)
+	}
+	slog.Info("Server stopped")
+}
+
+// fatal logs a startup failure and exits
+func fatal(message string, err error) {
+	slog.Error(message, slog.Any("error", err))
+	os.Exit(1)
+}
+
+// printSettings writes the effective configuration as an aligned table
+func printSettings(settings []config.Setting) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "KEY\tVALUE\tSOURCE")
+	for _, setting := range settings {
+		fmt.Fprintf(w, "%s\t%s\t%s\n", setting.Key, setting.Value, setting.Source)
 	}
+	_ = w.Flush()
 }
@@ -319,6 +319,28 @@ path "auth/token/renew-self" {
 # Allow token lookup
 path "auth/token/lookup-self" {
   capabilities = ["read"]
+}
+
+# Short-lived database credentials and their renewal
+path "database/creds/*" {
+  capabilities = ["read"]
+}
+
+path "sys/leases/renew" {
+  capabilities = ["update"]
+}
+
+# Wrapping message data keys with the transit engine
+path "transit/encrypt/thothix-messages" {
+  capabilities = ["update"]
+}
+
+path "transit/decrypt/thothix-messages" {
+  capabilities = ["update"]
+}
+
+path "transit/keys/thothix-messages" {
+  capabilities = ["read"]
 }`;
 
 	const readonlyPolicy = `# Read-only access for monitoring/debugging
@@ -341,6 +363,28 @@ path "auth/token/renew-self" {
 # Allow token lookup
 path "auth/token/lookup-self" {
   capabilities = ["read"]
+}
+
+# Short-lived database credentials and their renewal
+path "database/creds/*" {
+  capabilities = ["read"]
+}
+
+path "sys/leases/renew" {
+  capabilities = ["update"]
+}
+
+# Wrapping message data keys with the transit engine
+path "transit/encrypt/thothix-messages" {
+  capabilities = ["update"]
+}
+
+path "transit/decrypt/thothix-messages" {
+  capabilities = ["update"]
+}
+
+path "transit/keys/thothix-messages" {
+  capabilities = ["read"]
 }`;
 
 		const readonlyPolicyContent = `# Read-only access for monitoring/debugging